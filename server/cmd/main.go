@@ -1,15 +1,27 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"flag"
 	"log"
 	"net/http"
+	"os"
+
+	"github.com/sirupsen/logrus"
 
 	"github.com/julkhong/walletapp/server/internal/api"
 	"github.com/julkhong/walletapp/server/internal/config"
+	"github.com/julkhong/walletapp/server/internal/service"
 )
 
 func main() {
 	cfg := config.LoadConfig()
+
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(cfg, os.Args[1], os.Args[2:]))
+	}
+
 	cfg.InitRedis()
 
 	router := api.SetupRouter(cfg)
@@ -17,3 +29,80 @@ func main() {
 	log.Println("Starting server on :8080")
 	_ = http.ListenAndServe(":8080", router)
 }
+
+// runCommand runs a one-off maintenance command and returns the process exit code.
+func runCommand(cfg *config.Config, name string, args []string) int {
+	logger := logrus.New()
+	walletService := service.NewWalletService(cfg, logger)
+
+	switch name {
+	case "audit-verify":
+		result, err := walletService.Impl.VerifyAuditLog(context.Background())
+		if err != nil {
+			logger.WithError(err).Error("audit verification failed")
+			return 1
+		}
+		_ = json.NewEncoder(os.Stdout).Encode(result)
+		if !result.Valid {
+			return 2
+		}
+		return 0
+	case "ledger-reconcile":
+		flags := flag.NewFlagSet(name, flag.ContinueOnError)
+		apply := flags.Bool("apply", false, "write correcting adjustment entries")
+		operator := flags.String("operator", "", "operator identity recorded on corrections")
+		if err := flags.Parse(args); err != nil {
+			return 1
+		}
+
+		report, err := walletService.Impl.CheckLedgerIntegrity(context.Background(), *apply, *operator)
+		if err != nil {
+			logger.WithError(err).Error("ledger reconciliation failed")
+			return 1
+		}
+		_ = json.NewEncoder(os.Stdout).Encode(report)
+		if len(report.Discrepancies) > 0 && !report.Applied {
+			return 2
+		}
+		return 0
+	case "bank-import":
+		flags := flag.NewFlagSet(name, flag.ContinueOnError)
+		file := flags.String("file", "", "camt.053 or MT940 statement file")
+		format := flags.String("format", "", "camt053 or mt940; detected from the file when empty")
+		operator := flags.String("operator", "", "operator identity recorded on the import")
+		if err := flags.Parse(args); err != nil {
+			return 1
+		}
+		if *file == "" {
+			logger.Error("-file is required")
+			return 1
+		}
+
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			logger.WithError(err).Error("cannot read statement file")
+			return 1
+		}
+		result, err := walletService.Impl.ImportBankStatement(context.Background(), *format, data, *operator)
+		if err != nil {
+			logger.WithError(err).Error("bank statement import failed")
+			return 1
+		}
+		_ = json.NewEncoder(os.Stdout).Encode(result)
+		if len(result.Report.Unmatched) > 0 || len(result.Report.Ambiguous) > 0 {
+			return 2
+		}
+		return 0
+	case "payout-batch":
+		batch, err := walletService.Impl.GeneratePayoutBatch(context.Background())
+		if err != nil {
+			logger.WithError(err).Error("payout batch failed")
+			return 1
+		}
+		_ = json.NewEncoder(os.Stdout).Encode(batch)
+		return 0
+	}
+
+	logger.Errorf("unknown command %q", name)
+	return 1
+}
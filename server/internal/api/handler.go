@@ -1,13 +1,17 @@
 package api
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
 	"github.com/sirupsen/logrus"
 
+	"github.com/julkhong/walletapp/server/internal/common"
 	"github.com/julkhong/walletapp/server/internal/config"
+	"github.com/julkhong/walletapp/server/internal/jobs"
 	"github.com/julkhong/walletapp/server/internal/service"
 )
 
@@ -19,6 +23,9 @@ func SetupRouter(cfg *config.Config) http.Handler {
 	r := chi.NewRouter()
 	// recovers panic
 	r.Use(middleware.Recoverer)
+	r.Use(middleware.RequestID)
+	r.Use(middleware.RealIP)
+	r.Use(withAuditMeta)
 
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.TextFormatter{
@@ -29,11 +36,209 @@ func SetupRouter(cfg *config.Config) http.Handler {
 
 	walletService := service.NewWalletService(cfg, logger)
 
+	r.Post("/users", walletService.CreateUserHandler)
+	r.Post("/users/{id}/wallets", walletService.CreateWalletHandler)
 	r.Post("/wallets/{id}/deposit", walletService.DepositHandler)
 	r.Post("/wallets/{id}/withdraw", walletService.WithdrawHandler)
 	r.Post("/wallets/transfer", walletService.TransferHandler)
+	r.Post("/wallets/transfer/batch", walletService.CreateTransferBatchHandler)
+	r.Get("/wallets/transfer/batch/{id}", walletService.GetTransferBatchHandler)
+	r.Get("/wallets/{id}", walletService.GetWalletHandler)
 	r.Get("/wallets/{id}/balance", walletService.BalanceHandler)
+	r.Post("/wallets/{id}/pockets", walletService.CreatePocketHandler)
+	r.Post("/wallets/{id}/pockets/move", walletService.MovePocketFundsHandler)
+	r.Post("/pockets/{id}/goal", walletService.SetPocketGoalHandler)
+	r.Get("/wallets/{id}/members", walletService.ListMembersHandler)
+	r.Post("/wallets/{id}/members", walletService.InviteMemberHandler)
+	r.Post("/wallets/{id}/members/accept", walletService.AcceptInvitationHandler)
+	r.Post("/wallets/{id}/members/{userId}/remove", walletService.RemoveMemberHandler)
 	r.Get("/wallets/{id}/transactions", walletService.TransactionHistoryHandler)
+	r.Get("/wallets/{id}/transactions/export", walletService.ExportTransactionsHandler)
+	r.Get("/wallets/{id}/statements", walletService.ListStatementsHandler)
+	r.Get("/wallets/{id}/interest", walletService.InterestReportHandler)
+	r.Get("/statements/{id}", walletService.GetStatementHandler)
+	r.Post("/wallets/{id}/scheduled-transfers", walletService.CreateScheduledTransferHandler)
+	r.Get("/wallets/{id}/scheduled-transfers", walletService.ListScheduledTransfersHandler)
+	r.Get("/scheduled-transfers/{id}", walletService.GetScheduledTransferHandler)
+	r.Post("/scheduled-transfers/{id}/pause", walletService.PauseScheduledTransferHandler)
+	r.Post("/scheduled-transfers/{id}/resume", walletService.ResumeScheduledTransferHandler)
+	r.Post("/scheduled-transfers/{id}/cancel", walletService.CancelScheduledTransferHandler)
+	r.Post("/fees/quote", walletService.QuoteFeeHandler)
+	r.Post("/payments/split", walletService.SplitPaymentHandler)
+	r.Post("/escrows", walletService.CreateEscrowHandler)
+	r.Get("/escrows/{id}", walletService.GetEscrowHandler)
+	r.Post("/escrows/{id}/release", walletService.ReleaseEscrowHandler)
+	r.Post("/escrows/{id}/cancel", walletService.CancelEscrowHandler)
+	r.Post("/escrows/{id}/dispute", walletService.DisputeEscrowHandler)
+	r.Post("/payment-requests", walletService.CreatePaymentRequestHandler)
+	r.Get("/wallets/{id}/payment-requests", walletService.ListPaymentRequestsHandler)
+	r.Get("/payment-requests/{id}", walletService.GetPaymentRequestHandler)
+	r.Post("/payment-requests/{id}/accept", walletService.AcceptPaymentRequestHandler)
+	r.Post("/payment-requests/{id}/decline", walletService.DeclinePaymentRequestHandler)
+	r.Post("/payment-requests/{id}/cancel", walletService.CancelPaymentRequestHandler)
+	r.Post("/payment-links", walletService.CreatePaymentLinkHandler)
+	r.Get("/payment-links/{token}", walletService.ResolvePaymentLinkHandler)
+	r.Get("/payment-links/{token}/qr", walletService.PaymentLinkQRHandler)
+
+	r.Get("/approvals", walletService.ListApprovalsHandler)
+	r.Get("/approvals/{id}", walletService.GetApprovalHandler)
+	r.Post("/approvals/{id}/approve", walletService.ApproveHandler)
+	r.Post("/approvals/{id}/reject", walletService.RejectHandler)
+
+	r.Post("/admin/users/{id}/kyc", walletService.KycUpgradeHandler)
+	r.Get("/admin/wallets/{id}/adjustments", walletService.ListAdjustmentsHandler)
+	r.Post("/admin/wallets/{id}/adjustments", walletService.CreateAdjustmentHandler)
+	r.Post("/admin/wallets/{id}/credit-limit", walletService.SetCreditLimitHandler)
+	r.Get("/admin/reviews", walletService.ListTransactionReviewsHandler)
+	r.Post("/admin/reviews/{id}/approve", walletService.ApproveTransactionReviewHandler)
+	r.Post("/admin/reviews/{id}/reject", walletService.RejectTransactionReviewHandler)
+	r.Get("/admin/audit", walletService.ListAuditLogHandler)
+	r.Get("/admin/audit/verify", walletService.VerifyAuditLogHandler)
+	r.Post("/admin/ledger/reconcile", walletService.ReconcileLedgerHandler)
+	r.Post("/admin/bank-statements/import", walletService.ImportBankStatementHandler)
+	r.Get("/admin/bank-statements/{id}", walletService.GetBankImportHandler)
+	r.Get("/admin/payouts", walletService.ListPayoutsHandler)
+	r.Get("/admin/payouts/{id}", walletService.GetPayoutHandler)
+	r.Post("/admin/payouts/{id}/status", walletService.UpdatePayoutStatusHandler)
+	r.Get("/admin/provider-operations/{id}", walletService.GetProviderOperationHandler)
+	r.Post("/admin/escrows/{id}/resolve", walletService.ResolveEscrowHandler)
+
+	r.Post("/providers/{name}/callbacks", walletService.ProviderCallbackHandler)
+
+	// recorded in the background so serving does not wait on the audit log
+	go walletService.RecordStartupConfig(context.Background())
+	startJobs(cfg, walletService, logger)
 
 	return r
 }
+
+// startJobs registers and starts the background jobs of the wallet service.
+func startJobs(cfg *config.Config, walletService *service.WalletService, logger *logrus.Logger) {
+	runner := jobs.NewRunner(logger)
+
+	runner.Register(jobs.Job{
+		Name:     "expire-pending-operations",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			return walletService.ExpirePendingOperations(ctx)
+		},
+	})
+
+	if cfg.BalanceSnapshotInterval > 0 {
+		runner.Register(jobs.Job{
+			Name:     "balance-snapshots",
+			Interval: cfg.BalanceSnapshotInterval,
+			Run: func(ctx context.Context) error {
+				_, err := walletService.Impl.SnapshotBalances(ctx)
+				return err
+			},
+		})
+	}
+
+	if cfg.LedgerReconcileInterval > 0 {
+		runner.Register(jobs.Job{
+			Name:     "ledger-reconciliation",
+			Interval: cfg.LedgerReconcileInterval,
+			Run: func(ctx context.Context) error {
+				report, err := walletService.Impl.CheckLedgerIntegrity(ctx, cfg.LedgerReconcileApply, common.LedgerReconciliationActor)
+				if err != nil {
+					return err
+				}
+				if len(report.Discrepancies) > 0 {
+					logger.Warnf("Ledger reconciliation found %d discrepancies (applied=%v)", len(report.Discrepancies), report.Applied)
+				}
+				return nil
+			},
+		})
+	}
+
+	if cfg.StatementInterval > 0 {
+		runner.Register(jobs.Job{
+			Name:     "monthly-statements",
+			Interval: cfg.StatementInterval,
+			Run: func(ctx context.Context) error {
+				_, err := walletService.Impl.GenerateMonthlyStatements(ctx)
+				return err
+			},
+		})
+	}
+
+	if cfg.PayoutInterval > 0 && cfg.PayoutDebtorIBAN != "" {
+		runner.Register(jobs.Job{
+			Name:     "payout-batches",
+			Interval: cfg.PayoutInterval,
+			Run: func(ctx context.Context) error {
+				_, err := walletService.Impl.GeneratePayoutBatch(ctx)
+				return err
+			},
+		})
+	}
+
+	if cfg.ProviderSyncInterval > 0 {
+		runner.Register(jobs.Job{
+			Name:     "provider-status-sync",
+			Interval: cfg.ProviderSyncInterval,
+			Run: func(ctx context.Context) error {
+				_, err := walletService.Impl.SyncProviderOperations(ctx)
+				return err
+			},
+		})
+	}
+
+	if cfg.InterestInterval > 0 {
+		runner.Register(jobs.Job{
+			Name:     "interest-accrual",
+			Interval: cfg.InterestInterval,
+			Run: func(ctx context.Context) error {
+				_, err := walletService.Impl.AccrueInterest(ctx)
+				return err
+			},
+		})
+	}
+
+	if cfg.ScheduleInterval > 0 {
+		runner.Register(jobs.Job{
+			Name:     "scheduled-transfers",
+			Interval: cfg.ScheduleInterval,
+			Run: func(ctx context.Context) error {
+				_, err := walletService.Impl.RunDueSchedules(ctx)
+				return err
+			},
+		})
+	}
+
+	if cfg.TransferBatchInterval > 0 {
+		runner.Register(jobs.Job{
+			Name:     "transfer-batches",
+			Interval: cfg.TransferBatchInterval,
+			Run: func(ctx context.Context) error {
+				_, err := walletService.Impl.ProcessTransferBatches(ctx)
+				return err
+			},
+		})
+	}
+
+	if cfg.EscrowInterval > 0 {
+		runner.Register(jobs.Job{
+			Name:     "escrow-timeouts",
+			Interval: cfg.EscrowInterval,
+			Run: func(ctx context.Context) error {
+				_, err := walletService.Impl.ExpireEscrows(ctx)
+				return err
+			},
+		})
+	}
+
+	if cfg.PaymentRequestInterval > 0 {
+		runner.Register(jobs.Job{
+			Name:     "payment-request-expiry",
+			Interval: cfg.PaymentRequestInterval,
+			Run: func(ctx context.Context) error {
+				_, err := walletService.Impl.ExpirePaymentRequests(ctx)
+				return err
+			},
+		})
+	}
+
+	runner.Start(context.Background())
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+
+	"github.com/julkhong/walletapp/server/internal/audit"
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+// withAuditMeta captures the caller, client IP and request ID for the audit trail.
+// It must run after middleware.RequestID and middleware.RealIP.
+func withAuditMeta(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+
+		requestID := middleware.GetReqID(r.Context())
+		w.Header().Set(middleware.RequestIDHeader, requestID)
+
+		ctx := audit.WithMeta(r.Context(), audit.Meta{
+			Actor:     common.GetActorID(r),
+			IP:        ip,
+			RequestID: requestID,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
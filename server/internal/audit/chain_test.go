@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildChain(n int) []Link {
+	links := make([]Link, 0, n)
+	prev := ""
+	for i := 1; i <= n; i++ {
+		content := Content{
+			Seq:       int64(i),
+			Actor:     "operator-1",
+			Action:    "wallet.deposit",
+			EntityID:  "wallet-1",
+			Details:   `{"amount":10}`,
+			CreatedAt: time.Date(2026, 3, 3, 10, 0, i, 0, time.UTC),
+		}
+		hash := Hash(prev, content)
+		links = append(links, Link{Content: content, PrevHash: prev, Hash: hash})
+		prev = hash
+	}
+	return links
+}
+
+func TestHashIsStable(t *testing.T) {
+	content := Content{Seq: 1, Action: "wallet.deposit", CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	local := content
+	local.CreatedAt = content.CreatedAt.In(time.FixedZone("UTC+8", 8*3600))
+
+	assert.Len(t, Hash("", content), 64)
+	assert.Equal(t, Hash("", content), Hash("", local), "time zone must not change the hash")
+	assert.NotEqual(t, Hash("", content), Hash("abc", content), "previous hash is part of the hash")
+}
+
+func TestVerifier(t *testing.T) {
+	t.Run("intact chain across batches", func(t *testing.T) {
+		links := buildChain(5)
+		var v Verifier
+		assert.Nil(t, v.Add(links[:2]))
+		assert.Nil(t, v.Add(links[2:]))
+		assert.Equal(t, int64(5), v.Checked())
+	})
+
+	t.Run("edited row", func(t *testing.T) {
+		links := buildChain(5)
+		links[2].Details = `{"amount":1000}`
+		var v Verifier
+		brk := v.Add(links)
+		assert.Equal(t, int64(3), brk.Seq)
+		assert.Equal(t, "row content does not match its hash", brk.Reason)
+		assert.Equal(t, int64(2), v.Checked())
+	})
+
+	t.Run("rehashed row breaks the next link", func(t *testing.T) {
+		links := buildChain(5)
+		links[2].Details = `{"amount":1000}`
+		links[2].Hash = Hash(links[2].PrevHash, links[2].Content)
+		var v Verifier
+		brk := v.Add(links)
+		assert.Equal(t, int64(4), brk.Seq)
+		assert.Equal(t, "previous hash does not match the preceding row", brk.Reason)
+	})
+
+	t.Run("deleted row", func(t *testing.T) {
+		links := buildChain(5)
+		links = append(links[:1], links[2:]...)
+		var v Verifier
+		brk := v.Add(links)
+		assert.Equal(t, int64(3), brk.Seq)
+		assert.Equal(t, "sequence gap after 1", brk.Reason)
+	})
+}
+
+func TestMetaContext(t *testing.T) {
+	assert.Equal(t, Meta{}, FromContext(context.Background()))
+
+	ctx := WithMeta(context.Background(), Meta{Actor: "operator-1", IP: "10.0.0.1", RequestID: "req-1"})
+	assert.Equal(t, "operator-1", FromContext(ctx).Actor)
+}
@@ -0,0 +1,22 @@
+package audit
+
+import "context"
+
+// Meta identifies who made a state-changing call and through which request.
+type Meta struct {
+	Actor     string
+	IP        string
+	RequestID string
+}
+
+type metaKey struct{}
+
+func WithMeta(ctx context.Context, meta Meta) context.Context {
+	return context.WithValue(ctx, metaKey{}, meta)
+}
+
+// FromContext returns the request metadata, or a zero Meta for calls made outside a request.
+func FromContext(ctx context.Context) Meta {
+	meta, _ := ctx.Value(metaKey{}).(Meta)
+	return meta
+}
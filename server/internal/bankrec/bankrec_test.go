@@ -0,0 +1,150 @@
+package bankrec
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	data, err := os.ReadFile("testdata/" + name)
+	assert.NoError(t, err)
+	return data
+}
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseCamt053(t *testing.T) {
+	statement, err := Parse("", readFixture(t, "camt053.xml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "STMT-20260303", statement.ID)
+	assert.Equal(t, "DE89370400440532013000", statement.Account)
+
+	// the pending entry is skipped
+	assert.Len(t, statement.Lines, 3)
+	assert.Equal(t, Line{
+		Reference:     "3f1c2a9e-8d4b-4c1e-9a57-0b6d2e4f8a11",
+		BankReference: "BANK-0001",
+		Amount:        150,
+		Currency:      "USD",
+		BookingDate:   day(2026, 3, 3),
+		ValueDate:     day(2026, 3, 3),
+		Description:   "Wallet top-up",
+	}, statement.Lines[0])
+	assert.Equal(t, -42.5, statement.Lines[1].Amount)
+	assert.Equal(t, "Account fee", statement.Lines[1].Description)
+	assert.Equal(t, "", statement.Lines[2].Reference)
+	assert.Equal(t, time.Date(2026, 3, 3, 15, 4, 5, 0, time.UTC), statement.Lines[2].BookingDate)
+	assert.Equal(t, "Top-up for wallet 10000000-0000-0000-0000-000000000001", statement.Lines[2].Description)
+}
+
+func TestParseMT940(t *testing.T) {
+	statement, err := Parse("", readFixture(t, "mt940.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "STMT-MT-0304", statement.ID)
+	assert.Equal(t, "DE89370400440532013000", statement.Account)
+	assert.Len(t, statement.Lines, 3)
+
+	assert.Equal(t, Line{
+		Reference:     "3f1c2a9e-8d4b",
+		BankReference: "BANK-0001",
+		Amount:        150,
+		Currency:      "USD",
+		BookingDate:   day(2026, 3, 3),
+		ValueDate:     day(2026, 3, 3),
+		Description:   "Wallet top-up 3f1c2a9e-8d4b-4c1e-9a57- 0b6d2e4f8a11",
+	}, statement.Lines[0])
+	assert.Equal(t, -42.5, statement.Lines[1].Amount)
+	assert.Equal(t, "", statement.Lines[1].Reference)
+	// the entry date falls in the year after the value date
+	assert.Equal(t, day(2026, 1, 2), statement.Lines[2].BookingDate)
+	assert.Equal(t, day(2025, 12, 31), statement.Lines[2].ValueDate)
+	assert.Equal(t, 20.0, statement.Lines[2].Amount)
+}
+
+func TestParseErrors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		format string
+		data   string
+	}{
+		"unknown content":  {"", "hello"},
+		"broken xml":       {FormatCamt053, "<Document><BkToCstmrStmt>"},
+		"no statement":     {FormatCamt053, "<Document></Document>"},
+		"bad camt amount":  {FormatCamt053, `<Document><BkToCstmrStmt><Stmt><Ntry><Amt>ten</Amt><CdtDbtInd>CRDT</CdtDbtInd><BookgDt><Dt>2026-03-03</Dt></BookgDt></Ntry></Stmt></BkToCstmrStmt></Document>`},
+		"bad 61 line":      {FormatMT940, ":20:X\n:61:yesterday\n"},
+		"missing 20":       {FormatMT940, ":25:ACC\n"},
+		"text before tags": {FormatMT940, "hello\n:20:X\n"},
+	} {
+		_, err := Parse(tc.format, []byte(tc.data))
+		assert.Error(t, err, name)
+	}
+
+	_, err := Parse("", []byte("hello"))
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	_, err = Parse(FormatMT940, []byte(":20:X\n:61:yesterday\n"))
+	assert.ErrorIs(t, err, ErrMalformed)
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestReconcile(t *testing.T) {
+	walletA, walletB := "10000000-0000-0000-0000-000000000001", "10000000-0000-0000-0000-000000000002"
+	at := func(d int, h int) time.Time { return time.Date(2026, 3, d, h, 0, 0, 0, time.UTC) }
+	deposits := []dao.Transaction{
+		{ID: "3f1c2a9e-8d4b-4c1e-9a57-0b6d2e4f8a11", WalletID: walletA, Type: "deposit", Amount: 150, CreatedAt: at(3, 9)},
+		{ID: "tx-b", WalletID: walletA, Type: "deposit", Amount: 20, CreatedAt: at(3, 10)},
+		{ID: "tx-c", WalletID: walletB, Type: "deposit", Amount: 20, CreatedAt: at(4, 10)},
+		{ID: "tx-d", WalletID: walletB, Type: "deposit", Amount: 75, CreatedAt: at(2, 8)},
+		{ID: "tx-e", WalletID: walletA, Type: "deposit", Amount: 75, CreatedAt: at(3, 8)},
+		{ID: "tx-f", WalletID: walletB, Type: "deposit", Amount: 12, CreatedAt: at(20, 8)},
+	}
+	lines := []Line{
+		{BankReference: "B1", Amount: 150, BookingDate: day(2026, 3, 3), Description: "top-up 3F1C2A9E-8d4b-4c1e-9a57-0b6d2e4f8a11"},
+		{BankReference: "B2", Amount: -42.5, BookingDate: day(2026, 3, 3)},
+		{BankReference: "B3", Amount: 20, BookingDate: day(2026, 3, 3), Description: "wallet " + walletB},
+		{BankReference: "B4", Amount: 75, BookingDate: day(2026, 3, 3)},
+		{BankReference: "B5", Amount: 12, BookingDate: day(2026, 3, 3)},
+		{BankReference: "B6", Amount: 20, BookingDate: day(2026, 3, 3)},
+	}
+
+	report := Reconcile(lines, deposits, DefaultDateTolerance)
+
+	assert.Equal(t, 1, report.SkippedDebits)
+	assert.Len(t, report.Matched, 3)
+	assert.Equal(t, Match{Line: lines[0], TransactionID: deposits[0].ID, WalletID: walletA, Method: MatchByReference}, report.Matched[0])
+	// the wallet named in the description picks tx-c over tx-b
+	assert.Equal(t, "tx-c", report.Matched[1].TransactionID)
+	assert.Equal(t, MatchByAmountDate, report.Matched[1].Method)
+	// the remaining 20 can only be tx-b
+	assert.Equal(t, "tx-b", report.Matched[2].TransactionID)
+
+	assert.Equal(t, []Ambiguous{{Line: lines[3], CandidateIDs: []string{"tx-d", "tx-e"}}}, report.Ambiguous)
+	// tx-f is 17 days away from the booking date
+	assert.Equal(t, []Line{lines[4]}, report.Unmatched)
+	assert.Equal(t, []string{"tx-d", "tx-e", "tx-f"}, report.UnmatchedDeposits)
+}
+
+func TestReconcileWrappedReference(t *testing.T) {
+	statement, err := ParseMT940(readFixture(t, "mt940.txt"))
+	assert.NoError(t, err)
+
+	report := Reconcile(statement.Lines[:1], []dao.Transaction{
+		{ID: "3f1c2a9e-8d4b-4c1e-9a57-0b6d2e4f8a11", Amount: 999, CreatedAt: day(2025, 1, 1)},
+	}, DefaultDateTolerance)
+	assert.Len(t, report.Matched, 1)
+	assert.Equal(t, MatchByReference, report.Matched[0].Method)
+}
+
+func TestWindow(t *testing.T) {
+	from, to := Window([]Line{
+		{BookingDate: day(2026, 3, 5)},
+		{BookingDate: day(2026, 3, 3)},
+	}, 24*time.Hour)
+	assert.Equal(t, day(2026, 3, 2), from)
+	assert.Equal(t, day(2026, 3, 7), to)
+}
@@ -0,0 +1,148 @@
+package bankrec
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// camt053Document maps the parts of an ISO 20022 BankToCustomerStatement we use. Tags are
+// matched by local name, so any camt.053 schema version parses.
+type camt053Document struct {
+	Statements []struct {
+		ID      string `xml:"Id"`
+		Account struct {
+			IBAN  string `xml:"Id>IBAN"`
+			Other string `xml:"Id>Othr>Id"`
+		} `xml:"Acct"`
+		Entries []camt053Entry `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camt053Entry struct {
+	Amount struct {
+		Value    string `xml:",chardata"`
+		Currency string `xml:"Ccy,attr"`
+	} `xml:"Amt"`
+	CreditDebit string `xml:"CdtDbtInd"`
+	// plain text before camt.053.001.08, a Cd element since
+	Status struct {
+		Text string `xml:",chardata"`
+		Code string `xml:"Cd"`
+	} `xml:"Sts"`
+	BookingDate   camt053Date `xml:"BookgDt"`
+	ValueDate     camt053Date `xml:"ValDt"`
+	ServicerRef   string      `xml:"AcctSvcrRef"`
+	AdditionalInf string      `xml:"AddtlNtryInf"`
+	Transactions  []struct {
+		EndToEndID   string   `xml:"Refs>EndToEndId"`
+		Unstructured []string `xml:"RmtInf>Ustrd"`
+		CreditorRef  string   `xml:"RmtInf>Strd>CdtrRefInf>Ref"`
+	} `xml:"NtryDtls>TxDtls"`
+}
+
+type camt053Date struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+func (d camt053Date) parse() (time.Time, error) {
+	switch {
+	case d.Date != "":
+		return time.Parse("2006-01-02", strings.TrimSpace(d.Date))
+	case d.DateTime != "":
+		return time.Parse(time.RFC3339, strings.TrimSpace(d.DateTime))
+	default:
+		return time.Time{}, nil
+	}
+}
+
+// ParseCamt053 reads the booked entries of an ISO 20022 camt.053 statement. Entries that are
+// pending or information only are skipped.
+func ParseCamt053(data []byte) (*Statement, error) {
+	var doc camt053Document
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	if len(doc.Statements) == 0 {
+		return nil, fmt.Errorf("%w: no Stmt element", ErrMalformed)
+	}
+
+	statement := &Statement{ID: doc.Statements[0].ID, Account: doc.Statements[0].Account.IBAN}
+	if statement.Account == "" {
+		statement.Account = doc.Statements[0].Account.Other
+	}
+
+	for _, stmt := range doc.Statements {
+		for i, entry := range stmt.Entries {
+			status := entry.Status.Code
+			if status == "" {
+				status = strings.TrimSpace(entry.Status.Text)
+			}
+			if status != "" && status != "BOOK" {
+				continue
+			}
+
+			line, err := entry.line()
+			if err != nil {
+				return nil, fmt.Errorf("%w: statement %s entry %d: %v", ErrMalformed, stmt.ID, i+1, err)
+			}
+			statement.Lines = append(statement.Lines, line)
+		}
+	}
+	return statement, nil
+}
+
+func (e camt053Entry) line() (Line, error) {
+	amount, err := strconv.ParseFloat(strings.TrimSpace(e.Amount.Value), 64)
+	if err != nil {
+		return Line{}, fmt.Errorf("invalid amount %q", e.Amount.Value)
+	}
+	switch e.CreditDebit {
+	case "CRDT":
+	case "DBIT":
+		amount = -amount
+	default:
+		return Line{}, fmt.Errorf("invalid credit/debit indicator %q", e.CreditDebit)
+	}
+
+	booking, err := e.BookingDate.parse()
+	if err != nil || booking.IsZero() {
+		return Line{}, fmt.Errorf("invalid booking date")
+	}
+	value, err := e.ValueDate.parse()
+	if err != nil {
+		return Line{}, fmt.Errorf("invalid value date")
+	}
+	if value.IsZero() {
+		value = booking
+	}
+
+	line := Line{
+		BankReference: e.ServicerRef,
+		Amount:        amount,
+		Currency:      e.Amount.Currency,
+		BookingDate:   booking,
+		ValueDate:     value,
+	}
+
+	var description []string
+	for _, tx := range e.Transactions {
+		if line.Reference == "" && tx.EndToEndID != "" && tx.EndToEndID != "NOTPROVIDED" {
+			line.Reference = tx.EndToEndID
+		}
+		if line.Reference == "" && tx.CreditorRef != "" {
+			line.Reference = tx.CreditorRef
+		}
+		description = append(description, tx.Unstructured...)
+	}
+	if e.AdditionalInf != "" {
+		description = append(description, e.AdditionalInf)
+	}
+	line.Description = strings.Join(description, " ")
+	return line, nil
+}
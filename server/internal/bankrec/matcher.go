@@ -0,0 +1,179 @@
+package bankrec
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+)
+
+const (
+	MatchByReference  = "reference"
+	MatchByAmountDate = "amount_date"
+)
+
+// DefaultDateTolerance is how far a deposit may be recorded from the bank booking date.
+const DefaultDateTolerance = 3 * 24 * time.Hour
+
+var uuidPattern = regexp.MustCompile(`(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+
+type Match struct {
+	Line          Line   `json:"line"`
+	TransactionID string `json:"transaction_id"`
+	WalletID      string `json:"wallet_id"`
+	Method        string `json:"method"`
+}
+
+type Ambiguous struct {
+	Line         Line     `json:"line"`
+	CandidateIDs []string `json:"candidate_ids"`
+}
+
+// Report is the outcome of matching bank credits against deposits. Debit lines are not deposits
+// and are only counted.
+type Report struct {
+	Matched           []Match     `json:"matched"`
+	Unmatched         []Line      `json:"unmatched"`
+	Ambiguous         []Ambiguous `json:"ambiguous"`
+	UnmatchedDeposits []string    `json:"unmatched_deposits"`
+	SkippedDebits     int         `json:"skipped_debits"`
+}
+
+// Window is the time range deposits must fall in to be candidates for the lines.
+func Window(lines []Line, tolerance time.Duration) (time.Time, time.Time) {
+	var from, to time.Time
+	for i, line := range lines {
+		if i == 0 || line.BookingDate.Before(from) {
+			from = line.BookingDate
+		}
+		if i == 0 || line.BookingDate.After(to) {
+			to = line.BookingDate
+		}
+	}
+	// booking dates are whole days
+	return from.Add(-tolerance), to.Add(24*time.Hour + tolerance)
+}
+
+// Reconcile pairs credit lines with deposits. A line whose references or description name a
+// deposit's transaction ID matches it outright. Otherwise it matches the one unclaimed deposit
+// with the same amount booked within tolerance of the line, narrowed to a wallet when the line
+// names one; several such deposits make the line ambiguous. Each deposit matches at most one
+// line, and lines are matched in statement order so the result is deterministic.
+func Reconcile(lines []Line, deposits []dao.Transaction, tolerance time.Duration) *Report {
+	report := &Report{
+		Matched:           []Match{},
+		Unmatched:         []Line{},
+		Ambiguous:         []Ambiguous{},
+		UnmatchedDeposits: []string{},
+	}
+
+	byID := make(map[string]*dao.Transaction, len(deposits))
+	wallets := map[string]bool{}
+	for i := range deposits {
+		byID[strings.ToLower(deposits[i].ID)] = &deposits[i]
+		wallets[strings.ToLower(deposits[i].WalletID)] = true
+	}
+	claimed := map[string]bool{}
+
+	credits := make([]Line, 0, len(lines))
+	for _, line := range lines {
+		if line.Amount <= 0 {
+			report.SkippedDebits++
+			continue
+		}
+		credits = append(credits, line)
+	}
+
+	// references first, so a referenced deposit is never taken by an amount/date match
+	pending := make([]Line, 0, len(credits))
+	for _, line := range credits {
+		matched := false
+		for _, id := range referencedIDs(line) {
+			if tx, ok := byID[id]; ok && !claimed[id] {
+				claimed[id] = true
+				report.Matched = append(report.Matched, Match{Line: line, TransactionID: tx.ID, WalletID: tx.WalletID, Method: MatchByReference})
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			pending = append(pending, line)
+		}
+	}
+
+	for _, line := range pending {
+		wallet := ""
+		for _, id := range referencedIDs(line) {
+			if wallets[id] {
+				wallet = id
+				break
+			}
+		}
+
+		var candidates []*dao.Transaction
+		for i := range deposits {
+			tx := &deposits[i]
+			if claimed[strings.ToLower(tx.ID)] || (wallet != "" && strings.ToLower(tx.WalletID) != wallet) {
+				continue
+			}
+			if common.RoundToNDecimals(tx.Amount, 2) != common.RoundToNDecimals(line.Amount, 2) {
+				continue
+			}
+			if !withinTolerance(tx.CreatedAt, line.BookingDate, tolerance) {
+				continue
+			}
+			candidates = append(candidates, tx)
+		}
+
+		switch len(candidates) {
+		case 0:
+			report.Unmatched = append(report.Unmatched, line)
+		case 1:
+			claimed[strings.ToLower(candidates[0].ID)] = true
+			report.Matched = append(report.Matched, Match{Line: line, TransactionID: candidates[0].ID, WalletID: candidates[0].WalletID, Method: MatchByAmountDate})
+		default:
+			ids := make([]string, len(candidates))
+			for i, tx := range candidates {
+				ids[i] = tx.ID
+			}
+			sort.Strings(ids)
+			report.Ambiguous = append(report.Ambiguous, Ambiguous{Line: line, CandidateIDs: ids})
+		}
+	}
+
+	for _, tx := range deposits {
+		if !claimed[strings.ToLower(tx.ID)] {
+			report.UnmatchedDeposits = append(report.UnmatchedDeposits, tx.ID)
+		}
+	}
+	sort.Strings(report.UnmatchedDeposits)
+	return report
+}
+
+// referencedIDs returns the UUIDs a line mentions. Descriptions are also searched with
+// whitespace removed, because banks wrap long remittance text at fixed widths.
+func referencedIDs(line Line) []string {
+	text := line.Reference + " " + line.BankReference + " " + line.Description
+	found := uuidPattern.FindAllString(text, -1)
+	found = append(found, uuidPattern.FindAllString(strings.Join(strings.Fields(line.Description), ""), -1)...)
+
+	ids := make([]string, 0, len(found))
+	seen := map[string]bool{}
+	for _, id := range found {
+		id = strings.ToLower(id)
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// withinTolerance compares a deposit time with a booking date, which covers the whole day.
+func withinTolerance(recorded, booked time.Time, tolerance time.Duration) bool {
+	dayStart := time.Date(booked.Year(), booked.Month(), booked.Day(), 0, 0, 0, 0, time.UTC)
+	return !recorded.Before(dayStart.Add(-tolerance)) && recorded.Before(dayStart.Add(24*time.Hour+tolerance))
+}
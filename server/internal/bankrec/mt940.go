@@ -0,0 +1,161 @@
+package bankrec
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mt940Line matches the :61: statement line: value date, optional entry date, debit/credit mark,
+// optional funds code, amount, transaction type, customer reference and optional bank reference.
+var mt940Line = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?(\d+,\d*)([NSF][A-Z0-9]{3})(.*?)(?://(.*))?$`)
+
+// mt940Balance matches :60F:/:60M: balances, which carry the statement currency.
+var mt940Balance = regexp.MustCompile(`^[CD]\d{6}([A-Z]{3})`)
+
+type mt940Field struct {
+	tag     string
+	value   string
+	lineNum int
+}
+
+// ParseMT940 reads the statement lines of a SWIFT MT940 file. Several statements in one file are
+// merged; the ID and account of the first one are reported.
+func ParseMT940(data []byte) (*Statement, error) {
+	fields, err := mt940Fields(data)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &Statement{}
+	currency := ""
+	var last *Line
+	for _, f := range fields {
+		switch f.tag {
+		case "20":
+			if statement.ID == "" {
+				statement.ID = f.value
+			}
+		case "25":
+			if statement.Account == "" {
+				statement.Account = f.value
+			}
+		case "60F", "60M":
+			if m := mt940Balance.FindStringSubmatch(f.value); m != nil {
+				currency = m[1]
+			}
+		case "61":
+			line, err := parseMT940Line(f.value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrMalformed, f.lineNum, err)
+			}
+			line.Currency = currency
+			statement.Lines = append(statement.Lines, line)
+			last = &statement.Lines[len(statement.Lines)-1]
+		case "86":
+			// information to account owner belongs to the statement line before it
+			if last != nil {
+				last.Description = strings.TrimSpace(last.Description + " " + strings.Join(strings.Fields(f.value), " "))
+				last = nil
+			}
+		}
+	}
+
+	if statement.ID == "" {
+		return nil, fmt.Errorf("%w: missing :20: transaction reference", ErrMalformed)
+	}
+	return statement, nil
+}
+
+// mt940Fields splits the text block into tagged fields, joining continuation lines and dropping
+// the SWIFT envelope around block 4.
+func mt940Fields(data []byte) ([]mt940Field, error) {
+	var fields []mt940Field
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		text := strings.TrimRight(scanner.Text(), "\r ")
+		if strings.HasPrefix(text, "{") {
+			if idx := strings.Index(text, "{4:"); idx >= 0 {
+				text = text[idx+3:]
+			} else {
+				continue
+			}
+		}
+		if text == "" || text == "-}" || text == "-" {
+			continue
+		}
+
+		if strings.HasPrefix(text, ":") {
+			end := strings.Index(text[1:], ":")
+			if end < 0 {
+				return nil, fmt.Errorf("%w: line %d: unterminated tag", ErrMalformed, lineNum)
+			}
+			fields = append(fields, mt940Field{tag: text[1 : end+1], value: text[end+2:], lineNum: lineNum})
+			continue
+		}
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("%w: line %d: text before the first tag", ErrMalformed, lineNum)
+		}
+		fields[len(fields)-1].value += "\n" + text
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return fields, nil
+}
+
+func parseMT940Line(value string) (Line, error) {
+	first, supplementary, _ := strings.Cut(value, "\n")
+	m := mt940Line.FindStringSubmatch(first)
+	if m == nil {
+		return Line{}, fmt.Errorf("invalid :61: statement line %q", first)
+	}
+
+	valueDate, err := time.Parse("060102", m[1])
+	if err != nil {
+		return Line{}, fmt.Errorf("invalid value date %q", m[1])
+	}
+	bookingDate := valueDate
+	if m[2] != "" {
+		entry, err := time.Parse("0102", m[2])
+		if err != nil {
+			return Line{}, fmt.Errorf("invalid entry date %q", m[2])
+		}
+		bookingDate = time.Date(valueDate.Year(), entry.Month(), entry.Day(), 0, 0, 0, 0, time.UTC)
+		// the entry date carries no year; it can fall either side of a year boundary
+		switch {
+		case bookingDate.Sub(valueDate) > 180*24*time.Hour:
+			bookingDate = bookingDate.AddDate(-1, 0, 0)
+		case valueDate.Sub(bookingDate) > 180*24*time.Hour:
+			bookingDate = bookingDate.AddDate(1, 0, 0)
+		}
+	}
+
+	amount, err := strconv.ParseFloat(strings.Replace(m[5], ",", ".", 1), 64)
+	if err != nil {
+		return Line{}, fmt.Errorf("invalid amount %q", m[5])
+	}
+	// reversals of credits are debits and vice versa
+	if m[3] == "D" || m[3] == "RC" {
+		amount = -amount
+	}
+
+	reference := strings.TrimSpace(m[7])
+	if reference == "NONREF" {
+		reference = ""
+	}
+	return Line{
+		Reference:     reference,
+		BankReference: strings.TrimSpace(m[8]),
+		Amount:        amount,
+		BookingDate:   bookingDate,
+		ValueDate:     valueDate,
+		Description:   strings.TrimSpace(supplementary),
+	}, nil
+}
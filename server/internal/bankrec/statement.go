@@ -0,0 +1,63 @@
+package bankrec
+
+import (
+	"bytes"
+	"errors"
+	"time"
+)
+
+const (
+	FormatCamt053 = "camt053"
+	FormatMT940   = "mt940"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported bank statement format, expected camt053 or mt940")
+	ErrMalformed         = errors.New("malformed bank statement")
+)
+
+// Statement is a bank account statement reduced to what reconciliation needs.
+type Statement struct {
+	ID      string `json:"id"`
+	Account string `json:"account"`
+	Lines   []Line `json:"lines"`
+}
+
+// Line is one booked entry on a bank statement. Amount is positive for credits and negative for debits.
+type Line struct {
+	Reference     string    `json:"reference"`
+	BankReference string    `json:"bank_reference"`
+	Amount        float64   `json:"amount"`
+	Currency      string    `json:"currency"`
+	BookingDate   time.Time `json:"booking_date"`
+	ValueDate     time.Time `json:"value_date"`
+	Description   string    `json:"description"`
+}
+
+// Parse reads a statement file in the given format. An empty format is detected from the content.
+func Parse(format string, data []byte) (*Statement, error) {
+	if format == "" {
+		format = Detect(data)
+	}
+	switch format {
+	case FormatCamt053:
+		return ParseCamt053(data)
+	case FormatMT940:
+		return ParseMT940(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// Detect guesses the format of a statement file: camt.053 is XML, MT940 is tagged text.
+func Detect(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return FormatCamt053
+	case bytes.HasPrefix(trimmed, []byte(":20:")), bytes.HasPrefix(trimmed, []byte("{1:")):
+		return FormatMT940
+	default:
+		return ""
+	}
+}
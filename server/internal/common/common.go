@@ -34,3 +34,8 @@ func IsValidUUID(s string) bool {
 func GetIdempotencyKey(r *http.Request) string {
 	return r.Header.Get("Idempotency-Key")
 }
+
+// GetActorID returns the identity of the operator or user making the request.
+func GetActorID(r *http.Request) string {
+	return r.Header.Get("X-Actor-ID")
+}
@@ -84,3 +84,13 @@ func TestGetIdempotencyKey(t *testing.T) {
 		t.Errorf("expected idempotency key 'xyz-123'; got '%s'", key)
 	}
 }
+
+func TestGetActorID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("X-Actor-ID", "operator-1")
+
+	actor := GetActorID(req)
+	if actor != "operator-1" {
+		t.Errorf("expected actor 'operator-1'; got '%s'", actor)
+	}
+}
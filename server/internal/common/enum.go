@@ -4,4 +4,238 @@ const (
 	TransactionTypeDeposit  = "deposit"
 	TransactionTypeWithdraw = "withdraw"
 	TransactionTypeTransfer = "transfer"
+	// TransactionTypeAdjustment is an operator correction or goodwill credit, positive or negative.
+	TransactionTypeAdjustment = "adjustment"
+	// TransactionTypePayoutReturn credits a bank payout the beneficiary's bank sent back.
+	TransactionTypePayoutReturn = "payout_return"
+	// TransactionTypeWithdrawReversal credits back a withdrawal an external provider failed to pay out.
+	TransactionTypeWithdrawReversal = "withdraw_reversal"
+	// TransactionTypeFee moves a transfer or withdrawal fee into the system fee wallet. Both rows
+	// point at the charged transaction through their parent transaction ID.
+	TransactionTypeFee = "fee"
+	// TransactionTypeInterest credits a savings wallet with the interest accrued over a month.
+	TransactionTypeInterest = "interest"
+	// TransactionTypeOverdraftInterest debits a wallet with the interest its overdraft accrued over a month.
+	TransactionTypeOverdraftInterest = "overdraft_interest"
+	// TransactionTypePocketMove moves money between a wallet and its pockets, free of fees.
+	TransactionTypePocketMove = "pocket_move"
+	// TransactionTypeEscrowHold moves a buyer's payment into the system escrow wallet.
+	TransactionTypeEscrowHold = "escrow_hold"
+	// TransactionTypeEscrowRelease pays held money out of escrow to the seller. Both rows point at
+	// the hold through their parent transaction ID.
+	TransactionTypeEscrowRelease = "escrow_release"
+	// TransactionTypeEscrowRefund returns held money out of escrow to the buyer. Both rows point at
+	// the hold through their parent transaction ID.
+	TransactionTypeEscrowRefund = "escrow_refund"
+)
+
+const (
+	KycLevelUnverified = "unverified"
+	KycLevelBasic      = "basic"
+	KycLevelFull       = "full"
+)
+
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+	ReviewStatusFailed   = "failed"
+)
+
+const (
+	WalletStatusActive = "active"
+	WalletStatusFrozen = "frozen"
+)
+
+const (
+	WalletTypeStandard = "standard"
+	// WalletTypeSavings earns daily interest, posted monthly.
+	WalletTypeSavings = "savings"
+	// WalletTypePocket ring-fences money under a parent wallet; money only moves between the two.
+	WalletTypePocket = "pocket"
+)
+
+const (
+	// MemberRoleOwner may spend without a cap and manage the wallet and its members.
+	MemberRoleOwner = "owner"
+	// MemberRoleSpender may spend, up to their spending cap when they have one.
+	MemberRoleSpender = "spender"
+	// MemberRoleViewer may only see the wallet.
+	MemberRoleViewer = "viewer"
+)
+
+const (
+	MemberStatusInvited = "invited"
+	MemberStatusActive  = "active"
+)
+
+const (
+	ScreeningStatusClear = "clear"
+	ScreeningStatusMatch = "match"
+)
+
+const (
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusRejected = "rejected"
+	ApprovalStatusExpired  = "expired"
+	ApprovalStatusFailed   = "failed"
+)
+
+const (
+	PayoutStatusPending  = "pending_payout"
+	PayoutStatusSent     = "sent"
+	PayoutStatusSettled  = "settled"
+	PayoutStatusReturned = "returned"
+)
+
+const (
+	ScheduleStatusActive    = "active"
+	ScheduleStatusPaused    = "paused"
+	ScheduleStatusCancelled = "cancelled"
+	ScheduleStatusCompleted = "completed"
+)
+
+const (
+	ScheduleRunPending   = "pending"
+	ScheduleRunSucceeded = "succeeded"
+	ScheduleRunFailed    = "failed"
+	// ScheduleRunReview is an occurrence parked by risk screening for operator review.
+	ScheduleRunReview = "review"
+	// ScheduleRunError is an occurrence that hit an unexpected error and is retried.
+	ScheduleRunError = "error"
+)
+
+const (
+	BatchModeAllOrNothing = "all_or_nothing"
+	BatchModeBestEffort   = "best_effort"
+)
+
+const (
+	BatchStatusPending    = "pending"
+	BatchStatusProcessing = "processing"
+	BatchStatusCompleted  = "completed"
+	// BatchStatusPartial is a best-effort batch where some items failed.
+	BatchStatusPartial = "partially_completed"
+	BatchStatusFailed  = "failed"
+)
+
+const (
+	BatchItemPending   = "pending"
+	BatchItemSucceeded = "succeeded"
+	BatchItemFailed    = "failed"
+	// BatchItemReview is a best-effort item parked by risk screening for operator review.
+	BatchItemReview = "review"
+	// BatchItemSkipped is an item of an all-or-nothing batch that was not paid because another
+	// item or the batch as a whole failed.
+	BatchItemSkipped = "skipped"
+)
+
+const (
+	EscrowStatusHeld     = "held"
+	EscrowStatusReleased = "released"
+	EscrowStatusRefunded = "refunded"
+	// EscrowStatusDisputed holds the money until an operator resolves the dispute; it is neither
+	// released by the buyer nor refunded on timeout.
+	EscrowStatusDisputed = "disputed"
+)
+
+const (
+	PaymentRequestStatusPending   = "pending"
+	PaymentRequestStatusAccepted  = "accepted"
+	PaymentRequestStatusDeclined  = "declined"
+	PaymentRequestStatusCancelled = "cancelled"
+	PaymentRequestStatusExpired   = "expired"
+)
+
+// Event types sent to the notifier.
+const (
+	EventPaymentRequestCreated   = "payment_request.created"
+	EventPaymentRequestAccepted  = "payment_request.accepted"
+	EventPaymentRequestDeclined  = "payment_request.declined"
+	EventPaymentRequestCancelled = "payment_request.cancelled"
+	EventPaymentRequestExpired   = "payment_request.expired"
+	EventPaymentLinkPaid         = "payment_link.paid"
+)
+
+const (
+	ProviderOperationPayout = "payout"
+	ProviderOperationPayin  = "payin"
+)
+
+const (
+	ProviderStatusInitiated = "initiated"
+	ProviderStatusPending   = "pending"
+	ProviderStatusSucceeded = "succeeded"
+	ProviderStatusFailed    = "failed"
+)
+
+const (
+	AdjustmentReasonGoodwill        = "goodwill"
+	AdjustmentReasonErrorCorrection = "error_correction"
+	AdjustmentReasonFeeRefund       = "fee_refund"
+	AdjustmentReasonChargeback      = "chargeback"
+	AdjustmentReasonOther           = "other"
+)
+
+const (
+	AuditActionDeposit           = "wallet.deposit"
+	AuditActionWithdraw          = "wallet.withdraw"
+	AuditActionTransfer          = "wallet.transfer"
+	AuditActionAdjustment        = "wallet.adjustment"
+	AuditActionSplitPayment      = "wallet.split_payment"
+	AuditActionWalletsFrozen     = "user.wallets_frozen"
+	AuditActionUserCreated       = "user.created"
+	AuditActionKycUpgraded       = "user.kyc_upgraded"
+	AuditActionReviewCreated     = "review.created"
+	AuditActionReviewDecided     = "review.decided"
+	AuditActionApprovalSubmitted = "approval.submitted"
+	AuditActionApprovalDecided   = "approval.decided"
+	AuditActionConfigChanged     = "config.changed"
+	AuditActionLedgerCorrected   = "ledger.corrected"
+	AuditActionBankImported      = "bank.statement_imported"
+	AuditActionPayoutBatched     = "payout.batched"
+	AuditActionPayoutStatus      = "payout.status_changed"
+	AuditActionProviderStatus    = "provider.operation_status_changed"
+	AuditActionScheduleCreated   = "schedule.created"
+	AuditActionScheduleStatus    = "schedule.status_changed"
+	AuditActionBatchCreated      = "transfer_batch.created"
+	AuditActionBatchCompleted    = "transfer_batch.completed"
+	AuditActionWalletCreated     = "wallet.created"
+	AuditActionInterestPosted    = "wallet.interest_posted"
+	AuditActionCreditLimitSet    = "wallet.credit_limit_set"
+	AuditActionPocketCreated     = "pocket.created"
+	AuditActionPocketGoalSet     = "pocket.goal_set"
+	AuditActionPocketMove        = "pocket.move"
+	AuditActionMemberInvited     = "member.invited"
+	AuditActionMemberJoined      = "member.joined"
+	AuditActionMemberRemoved     = "member.removed"
+	AuditActionEscrowCreated     = "escrow.created"
+	AuditActionEscrowStatus      = "escrow.status_changed"
+	AuditActionRequestCreated    = "payment_request.created"
+	AuditActionRequestStatus     = "payment_request.status_changed"
+	AuditActionLinkCreated       = "payment_link.created"
+	AuditActionLinkPaid          = "payment_link.paid"
+)
+
+const (
+	AuditEntityWallet      = "wallet"
+	AuditEntityUser        = "user"
+	AuditEntityReview      = "transaction_review"
+	AuditEntityOperation   = "pending_operation"
+	AuditEntityConfig      = "config"
+	AuditEntityBankImport  = "bank_import"
+	AuditEntityPayout      = "payout"
+	AuditEntityPayoutBatch = "payout_batch"
+	AuditEntityProviderOp  = "provider_operation"
+	AuditEntitySchedule    = "schedule"
+	AuditEntityBatch       = "transfer_batch"
+	AuditEntityEscrow      = "escrow"
+	AuditEntityRequest     = "payment_request"
+	AuditEntityLink        = "payment_link"
+)
+
+const (
+	// LedgerReconciliationActor is recorded as the requester of corrections written by the integrity checker.
+	LedgerReconciliationActor = "ledger-reconciliation"
 )
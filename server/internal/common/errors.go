@@ -20,6 +20,41 @@ const (
 	ErrWalletNotFound      = 1001
 	ErrInsufficientBalance = 1002
 	ErrDatabase            = 1003
+	ErrUserNotFound        = 1004
+	ErrUnauthorized        = 1005
+	ErrKycBalanceLimit     = 1006
+	ErrKycTransferLimit    = 1007
+	ErrKycWithdrawDisabled = 1008
+	ErrTransactionDenied   = 1009
+	ErrReviewNotFound      = 1010
+	ErrWalletFrozen        = 1011
+	ErrSanctionsMatch      = 1012
+	ErrApprovalNotFound    = 1013
+	ErrApprovalConflict    = 1014
+	ErrSelfApproval        = 1015
+	ErrApprovalRejected    = 1016
+	ErrInvalidReasonCode   = 1017
+	ErrStatementNotFound   = 1018
+	ErrBankImportNotFound  = 1019
+	ErrPayoutNotFound      = 1020
+	ErrPayoutConflict      = 1021
+	ErrProviderNotFound    = 1022
+	ErrProviderOpNotFound  = 1023
+	ErrProviderFailed      = 1024
+	ErrScheduleNotFound    = 1025
+	ErrScheduleConflict    = 1026
+	ErrBatchNotFound       = 1027
+	ErrPocketMovement      = 1028
+	ErrWalletAccessDenied  = 1029
+	ErrSpendingCapExceeded = 1030
+	ErrMemberNotFound      = 1031
+	ErrMemberConflict      = 1032
+	ErrEscrowNotFound      = 1033
+	ErrEscrowConflict      = 1034
+	ErrRequestNotFound     = 1035
+	ErrRequestConflict     = 1036
+	ErrLinkNotFound        = 1037
+	ErrLinkConflict        = 1038
 	ErrUnknown             = 1099
 )
 
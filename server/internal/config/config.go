@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 	redis "github.com/redis/go-redis/v9"
@@ -19,6 +21,65 @@ type Config struct {
 	RedisHost  string
 	RedisPort  string
 	Redis      *redis.Client
+
+	RiskRulesFile string
+
+	FeeScheduleFile string
+	FeeWalletID     string
+
+	EscrowWalletID string
+	EscrowTimeout  time.Duration
+	EscrowInterval time.Duration
+
+	PaymentRequestExpiry   time.Duration
+	PaymentRequestInterval time.Duration
+
+	PaymentLinkSecret   string
+	PaymentLinkExpiry   time.Duration
+	PaymentLinkGUID     string
+	PaymentLinkCurrency string
+	PaymentLinkCountry  string
+	PaymentLinkCity     string
+
+	NotificationWebhookURL    string
+	NotificationWebhookSecret string
+
+	SavingsInterestRate   float64
+	OverdraftInterestRate float64
+	InterestInterval      time.Duration
+
+	SanctionsListDir        string
+	SanctionsMatchThreshold float64
+
+	ApprovalTransferThreshold float64
+	ApprovalTTL               time.Duration
+
+	LedgerReconcileInterval time.Duration
+	LedgerReconcileApply    bool
+
+	BalanceSnapshotInterval time.Duration
+
+	StatementInterval time.Duration
+
+	PayoutDir        string
+	PayoutInterval   time.Duration
+	PayoutCurrency   string
+	PayoutDebtorName string
+	PayoutDebtorIBAN string
+	PayoutDebtorBIC  string
+
+	ProviderSyncInterval    time.Duration
+	FakeProviderEnabled     bool
+	FakeProviderLatency     time.Duration
+	FakeProviderSettleAfter time.Duration
+	FakeProviderFailureRate float64
+	FakeProviderSecret      string
+
+	ScheduleInterval      time.Duration
+	TransferBatchInterval time.Duration
+
+	// WalletMembershipRequired rejects wallet calls that do not name the member making them.
+	WalletMembershipRequired bool
 }
 
 func LoadConfig() *Config {
@@ -35,6 +96,64 @@ func LoadConfig() *Config {
 		DBName:     getEnv("DB_NAME", "wallet"),
 		RedisHost:  getEnv("REDIS_HOST", "localhost"),
 		RedisPort:  getEnv("REDIS_PORT", "6379"),
+
+		RiskRulesFile: getEnv("RISK_RULES_FILE", "server/rules/risk_rules.json"),
+
+		FeeScheduleFile: getEnv("FEE_SCHEDULE_FILE", "server/rules/fee_schedule.json"),
+		FeeWalletID:     getEnv("FEE_WALLET_ID", "10000000-0000-0000-0000-0000000000fe"),
+
+		EscrowWalletID: getEnv("ESCROW_WALLET_ID", "10000000-0000-0000-0000-0000000000e5"),
+		EscrowTimeout:  getEnvDuration("ESCROW_TIMEOUT", 14*24*time.Hour),
+		EscrowInterval: getEnvDuration("ESCROW_INTERVAL", time.Minute),
+
+		PaymentRequestExpiry:   getEnvDuration("PAYMENT_REQUEST_EXPIRY", 7*24*time.Hour),
+		PaymentRequestInterval: getEnvDuration("PAYMENT_REQUEST_INTERVAL", time.Minute),
+
+		PaymentLinkSecret:   getEnv("PAYMENT_LINK_SECRET", ""),
+		PaymentLinkExpiry:   getEnvDuration("PAYMENT_LINK_EXPIRY", 30*24*time.Hour),
+		PaymentLinkGUID:     getEnv("PAYMENT_LINK_GUID", "com.walletapp"),
+		PaymentLinkCurrency: getEnv("PAYMENT_LINK_CURRENCY", "840"),
+		PaymentLinkCountry:  getEnv("PAYMENT_LINK_COUNTRY", "US"),
+		PaymentLinkCity:     getEnv("PAYMENT_LINK_CITY", "Online"),
+
+		NotificationWebhookURL:    getEnv("NOTIFICATION_WEBHOOK_URL", ""),
+		NotificationWebhookSecret: getEnv("NOTIFICATION_WEBHOOK_SECRET", ""),
+
+		SavingsInterestRate:   getEnvFloat("SAVINGS_INTEREST_RATE", 0.02),
+		OverdraftInterestRate: getEnvFloat("OVERDRAFT_INTEREST_RATE", 0.18),
+		InterestInterval:      getEnvDuration("INTEREST_INTERVAL", time.Hour),
+
+		SanctionsListDir:        getEnv("SANCTIONS_LIST_DIR", "server/sanctions"),
+		SanctionsMatchThreshold: getEnvFloat("SANCTIONS_MATCH_THRESHOLD", 0.85),
+
+		ApprovalTransferThreshold: getEnvFloat("APPROVAL_TRANSFER_THRESHOLD", 5000),
+		ApprovalTTL:               getEnvDuration("APPROVAL_TTL", 24*time.Hour),
+
+		LedgerReconcileInterval: getEnvDuration("LEDGER_RECONCILE_INTERVAL", 24*time.Hour),
+		LedgerReconcileApply:    getEnvBool("LEDGER_RECONCILE_APPLY", false),
+
+		BalanceSnapshotInterval: getEnvDuration("BALANCE_SNAPSHOT_INTERVAL", 24*time.Hour),
+
+		StatementInterval: getEnvDuration("STATEMENT_INTERVAL", time.Hour),
+
+		PayoutDir:        getEnv("PAYOUT_DIR", "server/payouts"),
+		PayoutInterval:   getEnvDuration("PAYOUT_INTERVAL", time.Hour),
+		PayoutCurrency:   getEnv("PAYOUT_CURRENCY", "USD"),
+		PayoutDebtorName: getEnv("PAYOUT_DEBTOR_NAME", "Wallet App"),
+		PayoutDebtorIBAN: getEnv("PAYOUT_DEBTOR_IBAN", ""),
+		PayoutDebtorBIC:  getEnv("PAYOUT_DEBTOR_BIC", ""),
+
+		ProviderSyncInterval:    getEnvDuration("PROVIDER_SYNC_INTERVAL", time.Minute),
+		FakeProviderEnabled:     getEnvBool("FAKE_PROVIDER_ENABLED", false),
+		FakeProviderLatency:     getEnvDuration("FAKE_PROVIDER_LATENCY", 200*time.Millisecond),
+		FakeProviderSettleAfter: getEnvDuration("FAKE_PROVIDER_SETTLE_AFTER", 30*time.Second),
+		FakeProviderFailureRate: getEnvFloat("FAKE_PROVIDER_FAILURE_RATE", 0),
+		FakeProviderSecret:      getEnv("FAKE_PROVIDER_SECRET", ""),
+
+		ScheduleInterval:      getEnvDuration("SCHEDULE_INTERVAL", time.Minute),
+		TransferBatchInterval: getEnvDuration("TRANSFER_BATCH_INTERVAL", 5*time.Second),
+
+		WalletMembershipRequired: getEnvBool("WALLET_MEMBERSHIP_REQUIRED", false),
 	}
 
 	cfg.DBURL = fmt.Sprintf(
@@ -52,6 +171,39 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+func getEnvFloat(key string, fallback float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			return parsed
+		}
+		log.Printf("Invalid value for %s, using default %v", key, fallback)
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err == nil {
+			return parsed
+		}
+		log.Printf("Invalid value for %s, using default %v", key, fallback)
+	}
+	return fallback
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err == nil {
+			return parsed
+		}
+		log.Printf("Invalid value for %s, using default %v", key, fallback)
+	}
+	return fallback
+}
+
 func (c *Config) InitRedis() {
 	rdb := redis.NewClient(&redis.Options{
 		Addr: c.RedisHost + ":" + c.RedisPort,
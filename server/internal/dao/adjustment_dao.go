@@ -0,0 +1,16 @@
+package dao
+
+func (dao *WalletDao) CreateAdjustment(adjustment *Adjustment) error {
+	dao.logger.Infof("Recording %s adjustment of %.4f on wallet %s", adjustment.ReasonCode, adjustment.Amount, adjustment.WalletID)
+	return dao.db.Table("adjustments").Create(adjustment).Error
+}
+
+func (dao *WalletDao) ListAdjustments(walletID string) ([]Adjustment, error) {
+	var adjustments []Adjustment
+	err := dao.db.Table("adjustments").Where("wallet_id = ?", walletID).Order("created_at DESC").Find(&adjustments).Error
+	if err != nil {
+		dao.logger.WithError(err).Error("Failed to list adjustments")
+		return nil, err
+	}
+	return adjustments, nil
+}
@@ -0,0 +1,49 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAdjustment(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	adjustment := &Adjustment{
+		ID:            "adj-1",
+		TransactionID: "tx-1",
+		WalletID:      "wallet-1",
+		Amount:        -12.5,
+		ReasonCode:    "error_correction",
+		Note:          "duplicate deposit",
+		RequestedBy:   "maker",
+		ApprovedBy:    "checker",
+		CreatedAt:     time.Now(),
+	}
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "adjustments"`)).
+		WithArgs("adj-1", "tx-1", "wallet-1", -12.5, "error_correction", "duplicate deposit", "maker", "checker", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectCommit()
+
+	assert.NoError(t, dao.CreateAdjustment(adjustment))
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestListAdjustments(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "adjustments" WHERE wallet_id = $1 ORDER BY created_at DESC`)).
+		WithArgs("wallet-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "wallet_id", "amount", "reason_code"}).
+			AddRow("adj-1", "wallet-1", 20.0, "goodwill"))
+
+	adjustments, err := dao.ListAdjustments("wallet-1")
+	assert.NoError(t, err)
+	assert.Len(t, adjustments, 1)
+	assert.Equal(t, "goodwill", adjustments[0].ReasonCode)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
@@ -0,0 +1,86 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+var (
+	ErrPendingOperationNotFound = errors.New("pending operation not found")
+)
+
+func (dao *WalletDao) CreatePendingOperation(op *PendingOperation) error {
+	dao.logger.Infof("Creating pending %s operation initiated by %q", op.Type, op.InitiatedBy)
+	return dao.db.Table("pending_operations").Create(op).Error
+}
+
+func (dao *WalletDao) GetPendingOperation(operationID string) (*PendingOperation, error) {
+	var op PendingOperation
+	result := dao.db.Table("pending_operations").Where("id = ?", operationID).First(&op)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrPendingOperationNotFound
+		}
+		dao.logger.WithError(result.Error).Error("Failed to fetch pending operation")
+		return nil, result.Error
+	}
+	return &op, nil
+}
+
+func (dao *WalletDao) ListPendingOperations(status string) ([]PendingOperation, error) {
+	query := dao.db.Table("pending_operations")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var ops []PendingOperation
+	if err := query.Order("created_at ASC").Find(&ops).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list pending operations")
+		return nil, err
+	}
+	return ops, nil
+}
+
+// UpdatePendingOperationStatus moves an operation from one status to another. It returns
+// ErrPendingOperationNotFound when no operation with the expected current status exists.
+func (dao *WalletDao) UpdatePendingOperationStatus(operationID, fromStatus, toStatus, decidedBy string, result *string) error {
+	updates := map[string]any{
+		"status":     toStatus,
+		"decided_by": decidedBy,
+		"decided_at": time.Now(),
+	}
+	if result != nil {
+		updates["result"] = *result
+	}
+
+	res := dao.db.Table("pending_operations").
+		Where("id = ? AND status = ?", operationID, fromStatus).
+		Updates(updates)
+	if res.Error != nil {
+		dao.logger.WithError(res.Error).Error("Failed to update pending operation")
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrPendingOperationNotFound
+	}
+	return nil
+}
+
+// ExpirePendingOperations marks every pending operation whose expiry has passed as expired and
+// returns the operations it expired.
+func (dao *WalletDao) ExpirePendingOperations(now time.Time) ([]PendingOperation, error) {
+	var ops []PendingOperation
+	err := dao.db.Table("pending_operations").Model(&ops).Clauses(clause.Returning{}).
+		Where("status = ? AND expires_at <= ?", common.ApprovalStatusPending, now).
+		Update("status", common.ApprovalStatusExpired).Error
+	if err != nil {
+		dao.logger.WithError(err).Error("Failed to expire pending operations")
+		return nil, err
+	}
+	return ops, nil
+}
@@ -0,0 +1,67 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdatePendingOperationStatus(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+
+	t.Run("pending operation decided", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "pending_operations" SET "decided_at"=$1,"decided_by"=$2,"status"=$3 WHERE id = $4 AND status = $5`)).
+			WithArgs(sqlmock.AnyArg(), "checker", "approved", "op-1", "pending").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		err := dao.UpdatePendingOperationStatus("op-1", "pending", "approved", "checker", nil)
+		assert.NoError(t, err)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("result recorded", func(t *testing.T) {
+		result := "insufficient balance"
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "pending_operations" SET "decided_at"=$1,"decided_by"=$2,"result"=$3,"status"=$4 WHERE id = $5 AND status = $6`)).
+			WithArgs(sqlmock.AnyArg(), "checker", result, "failed", "op-1", "approved").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		err := dao.UpdatePendingOperationStatus("op-1", "approved", "failed", "checker", &result)
+		assert.NoError(t, err)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("already decided", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "pending_operations"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectCommit()
+
+		err := dao.UpdatePendingOperationStatus("op-1", "pending", "approved", "checker", nil)
+		assert.ErrorIs(t, err, ErrPendingOperationNotFound)
+	})
+}
+
+func TestExpirePendingOperations(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	now := time.Now()
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectQuery(regexp.QuoteMeta(`UPDATE "pending_operations" SET "status"=$1 WHERE status = $2 AND expires_at <= $3 RETURNING *`)).
+		WithArgs("expired", "pending", now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "idempotency_key"}).
+			AddRow("op-1", "expired", "key-1").AddRow("op-2", "expired", ""))
+	dbMock.ExpectCommit()
+
+	ops, err := dao.ExpirePendingOperations(now)
+	assert.NoError(t, err)
+	assert.Len(t, ops, 2)
+	assert.Equal(t, "key-1", ops[0].IdempotencyKey)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
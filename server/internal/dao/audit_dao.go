@@ -0,0 +1,82 @@
+package dao
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/julkhong/walletapp/server/internal/audit"
+)
+
+var ErrAuditLogNotFound = errors.New("audit log entry not found")
+
+// auditLockKey serialises appends so every row links to the one before it.
+const auditLockKey = 7413001
+
+// AppendAuditLog assigns the next sequence number to entry, chains it to the latest row and inserts it.
+func (dao *WalletDao) AppendAuditLog(entry *AuditLog) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditLockKey).Error; err != nil {
+			return err
+		}
+
+		var last AuditLog
+		if err := tx.Table("audit_log").Order("seq DESC").Limit(1).Find(&last).Error; err != nil {
+			dao.logger.WithError(err).Error("Failed to fetch audit log head")
+			return err
+		}
+
+		entry.Seq = last.Seq + 1
+		entry.PrevHash = last.Hash
+		entry.Hash = audit.Hash(entry.PrevHash, entry.Content())
+
+		if err := tx.Table("audit_log").Create(entry).Error; err != nil {
+			dao.logger.WithError(err).Error("Failed to append audit log")
+			return err
+		}
+		return nil
+	})
+}
+
+// ListAuditLog returns up to limit rows following afterSeq in chain order.
+func (dao *WalletDao) ListAuditLog(afterSeq int64, limit int) ([]AuditLog, error) {
+	var entries []AuditLog
+	err := dao.db.Table("audit_log").Where("seq > ?", afterSeq).Order("seq ASC").Limit(limit).Find(&entries).Error
+	if err != nil {
+		dao.logger.WithError(err).Error("Failed to list audit log")
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetLatestAuditLog returns the most recent row of action on an entity. It returns
+// ErrAuditLogNotFound when there is none.
+func (dao *WalletDao) GetLatestAuditLog(action, entityType, entityID string) (*AuditLog, error) {
+	var entry AuditLog
+	err := dao.db.Table("audit_log").
+		Where("action = ? AND entity_type = ? AND entity_id = ?", action, entityType, entityID).
+		Order("seq DESC").First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAuditLogNotFound
+		}
+		dao.logger.WithError(err).Error("Failed to fetch latest audit log")
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Content returns the hashed part of the row.
+func (a *AuditLog) Content() audit.Content {
+	return audit.Content{
+		Seq:        a.Seq,
+		Actor:      a.Actor,
+		IP:         a.IP,
+		RequestID:  a.RequestID,
+		Action:     a.Action,
+		EntityType: a.EntityType,
+		EntityID:   a.EntityID,
+		Details:    a.Details,
+		CreatedAt:  a.CreatedAt,
+	}
+}
@@ -0,0 +1,73 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/julkhong/walletapp/server/internal/audit"
+)
+
+func TestAppendAuditLog(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	createdAt := time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC)
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1)`)).
+		WithArgs(auditLockKey).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "audit_log" ORDER BY seq DESC LIMIT $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"seq", "hash"}).AddRow(41, "head-hash"))
+	dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "audit_log"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectCommit()
+
+	entry := &AuditLog{Actor: "operator-1", Action: "wallet.deposit", EntityID: "wallet-1", Details: `{}`, CreatedAt: createdAt}
+	err := dao.AppendAuditLog(entry)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), entry.Seq)
+	assert.Equal(t, "head-hash", entry.PrevHash)
+	assert.Equal(t, audit.Hash("head-hash", entry.Content()), entry.Hash)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestListAuditLog(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "audit_log" WHERE seq > $1 ORDER BY seq ASC LIMIT $2`)).
+		WithArgs(10, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow(11).AddRow(12))
+
+	entries, err := dao.ListAuditLog(10, 2)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestGetLatestAuditLog(t *testing.T) {
+	latest := regexp.QuoteMeta(`SELECT * FROM "audit_log" WHERE action = $1 AND entity_type = $2 AND entity_id = $3 ORDER BY seq DESC,"audit_log"."seq" LIMIT $4`)
+
+	t.Run("found", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectQuery(latest).WithArgs("config.changed", "config", "fee_schedule", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"seq", "details"}).AddRow(7, `{"file":"fees.json"}`))
+
+		entry, err := dao.GetLatestAuditLog("config.changed", "config", "fee_schedule")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), entry.Seq)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("never recorded", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectQuery(latest).WillReturnRows(sqlmock.NewRows([]string{"seq"}))
+
+		_, err := dao.GetLatestAuditLog("config.changed", "config", "fee_schedule")
+		assert.ErrorIs(t, err, ErrAuditLogNotFound)
+	})
+}
@@ -0,0 +1,71 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+var (
+	ErrBankImportNotFound = errors.New("bank import not found")
+)
+
+// ListUnmatchedDeposits returns the deposits made in [from, to) that no bank line backs yet.
+func (dao *WalletDao) ListUnmatchedDeposits(from, to time.Time) ([]Transaction, error) {
+	var txs []Transaction
+	err := dao.db.Table("transactions").
+		Where("type = ? AND created_at >= ? AND created_at < ?", common.TransactionTypeDeposit, from, to).
+		Where("NOT EXISTS (SELECT 1 FROM bank_matches WHERE bank_matches.transaction_id = transactions.id)").
+		Order("created_at").Order("id").
+		Find(&txs).Error
+	if err != nil {
+		dao.logger.WithError(err).Error("Failed to list unmatched deposits")
+		return nil, err
+	}
+	return txs, nil
+}
+
+// CreateBankImport stores an import together with its matches in one DB transaction.
+func (dao *WalletDao) CreateBankImport(bankImport *BankImport, matches []BankMatch) error {
+	dao.logger.Infof("Storing bank import %s with %d matches", bankImport.ID, len(matches))
+
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("bank_imports").Create(bankImport).Error; err != nil {
+			dao.logger.WithError(err).Error("Failed to store bank import")
+			return err
+		}
+		if len(matches) == 0 {
+			return nil
+		}
+		if err := tx.Table("bank_matches").Create(&matches).Error; err != nil {
+			dao.logger.WithError(err).Error("Failed to store bank matches")
+			return err
+		}
+		return nil
+	})
+}
+
+func (dao *WalletDao) GetBankImport(importID string) (*BankImport, error) {
+	return dao.findBankImport("id = ?", importID)
+}
+
+// GetBankImportByHash finds an earlier import of the same file.
+func (dao *WalletDao) GetBankImportByHash(fileHash string) (*BankImport, error) {
+	return dao.findBankImport("file_hash = ?", fileHash)
+}
+
+func (dao *WalletDao) findBankImport(query string, arg string) (*BankImport, error) {
+	var bankImport BankImport
+	err := dao.db.Table("bank_imports").Where(query, arg).First(&bankImport).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBankImportNotFound
+		}
+		dao.logger.WithError(err).Error("Failed to fetch bank import")
+		return nil, err
+	}
+	return &bankImport, nil
+}
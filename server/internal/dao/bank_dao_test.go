@@ -0,0 +1,82 @@
+package dao
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListUnmatchedDeposits(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "transactions" WHERE (type = $1 AND created_at >= $2 AND created_at < $3) AND NOT EXISTS (SELECT 1 FROM bank_matches WHERE bank_matches.transaction_id = transactions.id) ORDER BY created_at,id`)).
+		WithArgs("deposit", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "wallet_id", "type", "amount"}).
+			AddRow("tx-1", "wallet-1", "deposit", 150).
+			AddRow("tx-2", "wallet-2", "deposit", 20))
+
+	txs, err := dao.ListUnmatchedDeposits(from, to)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 2)
+	assert.Equal(t, 150.0, txs[0].Amount)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestCreateBankImport(t *testing.T) {
+	bankImport := &BankImport{ID: "import-1", Format: "camt053", FileHash: "abc", ReportJSON: "{}", ImportedBy: "operator-1", CreatedAt: time.Now()}
+	matches := []BankMatch{{TransactionID: "tx-1", ImportID: "import-1", Amount: 150, Method: "reference", CreatedAt: time.Now()}}
+
+	t.Run("import and matches stored together", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "bank_imports"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "bank_matches"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.CreateBankImport(bankImport, matches))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "bank_imports"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.CreateBankImport(bankImport, nil))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("deposit already matched rolls back", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "bank_imports"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "bank_matches"`)).WillReturnError(errors.New("duplicate key"))
+		dbMock.ExpectRollback()
+
+		assert.Error(t, dao.CreateBankImport(bankImport, matches))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestGetBankImport(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "bank_imports" WHERE id = $1`)).WithArgs("import-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "file_hash", "report"}).AddRow("import-1", "abc", "{}"))
+	bankImport, err := dao.GetBankImport("import-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", bankImport.ReportJSON)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "bank_imports" WHERE file_hash = $1`)).WithArgs("def", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = dao.GetBankImportByHash("def")
+	assert.ErrorIs(t, err, ErrBankImportNotFound)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
@@ -0,0 +1,120 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+var ErrEscrowNotFound = errors.New("escrow not found")
+
+// CreateEscrow posts the legs moving the buyer's payment, and its fee, into escrow and records
+// the escrow in one DB transaction. Nothing is written when the buyer would go past its credit
+// limit, in which case ErrInsufficientFunds is returned.
+func (dao *WalletDao) CreateEscrow(escrow *Escrow, legs []TransferLeg) error {
+	dao.logger.Infof("Holding %.4f from wallet %s in escrow %s", escrow.Amount, escrow.BuyerWalletID, escrow.ID)
+
+	err := dao.db.Transaction(func(db *gorm.DB) error {
+		if err := postTransfers(db, legs, escrow.CreatedAt); err != nil {
+			return err
+		}
+		return db.Table("escrows").Create(escrow).Error
+	})
+	if err != nil {
+		if !errors.Is(err, ErrInsufficientFunds) {
+			dao.logger.WithError(err).Error("Failed to create escrow")
+		}
+		return err
+	}
+
+	for _, walletID := range legWallets(legs) {
+		dao.invalidateBalance(walletID)
+	}
+	return nil
+}
+
+func (dao *WalletDao) GetEscrow(escrowID string) (*Escrow, error) {
+	var escrow Escrow
+	result := dao.db.Table("escrows").Where("id = ?", escrowID).First(&escrow)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrEscrowNotFound
+		}
+		dao.logger.WithError(result.Error).Error("Failed to fetch escrow")
+		return nil, result.Error
+	}
+	return &escrow, nil
+}
+
+// ListExpiredEscrows returns held escrows whose timeout is at or before now, oldest first.
+// Disputed escrows never expire.
+func (dao *WalletDao) ListExpiredEscrows(now time.Time, limit int) ([]Escrow, error) {
+	var escrows []Escrow
+	if err := dao.db.Table("escrows").
+		Where("status = ? AND expires_at <= ?", common.EscrowStatusHeld, now).
+		Order("expires_at ASC").Order("id ASC").Limit(limit).Find(&escrows).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list expired escrows")
+		return nil, err
+	}
+	return escrows, nil
+}
+
+// DisputeEscrow moves a held escrow to disputed. It returns ErrEscrowNotFound when no held
+// escrow with the ID exists.
+func (dao *WalletDao) DisputeEscrow(escrowID, reason, disputedBy string, at time.Time) error {
+	result := dao.db.Table("escrows").
+		Where("id = ? AND status = ?", escrowID, common.EscrowStatusHeld).
+		Updates(map[string]any{
+			"status":         common.EscrowStatusDisputed,
+			"dispute_reason": reason,
+			"disputed_by":    disputedBy,
+			"updated_at":     at,
+		})
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to dispute escrow")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrEscrowNotFound
+	}
+	return nil
+}
+
+// SettleEscrow moves an escrow in one of fromStatuses to its new status and posts the leg paying
+// the money out of escrow in one DB transaction. It returns ErrEscrowNotFound, and posts nothing,
+// when no escrow with an expected status exists, so an escrow is settled at most once.
+func (dao *WalletDao) SettleEscrow(escrow *Escrow, fromStatuses []string, legs []TransferLeg) error {
+	dao.logger.Infof("Settling escrow %s as %s", escrow.ID, escrow.Status)
+
+	err := dao.db.Transaction(func(db *gorm.DB) error {
+		result := db.Table("escrows").
+			Where("id = ? AND status IN ?", escrow.ID, fromStatuses).
+			Updates(map[string]any{
+				"status":                    escrow.Status,
+				"settlement_transaction_id": escrow.SettlementTransactionID,
+				"settled_at":                escrow.SettledAt,
+				"updated_at":                escrow.UpdatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrEscrowNotFound
+		}
+		return postTransfers(db, legs, escrow.UpdatedAt)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrEscrowNotFound) {
+			dao.logger.WithError(err).Error("Failed to settle escrow")
+		}
+		return err
+	}
+
+	for _, walletID := range legWallets(legs) {
+		dao.invalidateBalance(walletID)
+	}
+	return nil
+}
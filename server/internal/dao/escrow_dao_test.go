@@ -0,0 +1,125 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateEscrow(t *testing.T) {
+	lockWallets := regexp.QuoteMeta(`SELECT * FROM "wallets" WHERE id IN ($1,$2) ORDER BY id FOR UPDATE`)
+	now := time.Now()
+	legs := []TransferLeg{{FromWalletID: "wallet-1", ToWalletID: "wallet-escrow", Amount: 40, DebitID: "tx-1", CreditID: "tx-2", Type: "escrow_hold"}}
+	escrow := &Escrow{ID: "escrow-1", BuyerWalletID: "wallet-1", SellerWalletID: "wallet-2", Amount: 40, Status: "held",
+		ExpiresAt: now.Add(time.Hour), HoldTransactionID: "tx-1", CreatedAt: now, UpdatedAt: now}
+
+	t.Run("holds the payment", func(t *testing.T) {
+		dao, dbMock, redisMock := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(lockWallets).WithArgs("wallet-1", "wallet-escrow").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 100.0).AddRow("wallet-escrow", 10.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WithArgs(60.0, "wallet-1").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WithArgs(50.0, "wallet-escrow").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 2))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "escrows"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+		redisMock.ExpectDel("wallet_balance:wallet-1").SetVal(1)
+		redisMock.ExpectDel("wallet_balance:wallet-escrow").SetVal(1)
+
+		assert.NoError(t, dao.CreateEscrow(escrow, legs))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("insufficient funds records nothing", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(lockWallets).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 30.0).AddRow("wallet-escrow", 0.0))
+		dbMock.ExpectRollback()
+
+		assert.ErrorIs(t, dao.CreateEscrow(escrow, legs), ErrInsufficientFunds)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestGetEscrow(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "escrows" WHERE id = $1 ORDER BY "escrows"."id" LIMIT $2`)).
+		WithArgs("escrow-1", 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := dao.GetEscrow("escrow-1")
+	assert.ErrorIs(t, err, ErrEscrowNotFound)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestListExpiredEscrows(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	now := time.Now()
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "escrows" WHERE status = $1 AND expires_at <= $2 ORDER BY expires_at ASC,id ASC LIMIT $3`)).
+		WithArgs("held", now, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow("escrow-1", "held"))
+
+	escrows, err := dao.ListExpiredEscrows(now, 50)
+	assert.NoError(t, err)
+	assert.Len(t, escrows, 1)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestDisputeEscrow(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	now := time.Now()
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "escrows" SET "dispute_reason"=$1,"disputed_by"=$2,"status"=$3,"updated_at"=$4 WHERE id = $5 AND status = $6`)).
+		WithArgs("not delivered", "user-1", "disputed", now, "escrow-1", "held").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectCommit()
+
+	assert.ErrorIs(t, dao.DisputeEscrow("escrow-1", "not delivered", "user-1", now), ErrEscrowNotFound)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestSettleEscrow(t *testing.T) {
+	update := regexp.QuoteMeta(`UPDATE "escrows" SET "settled_at"=$1,"settlement_transaction_id"=$2,"status"=$3,"updated_at"=$4 WHERE id = $5 AND status IN ($6,$7)`)
+	now := time.Now()
+	settlementID, holdID := "tx-4", "tx-1"
+	legs := []TransferLeg{{FromWalletID: "wallet-escrow", ToWalletID: "wallet-1", Amount: 40, DebitID: "tx-3", CreditID: "tx-4",
+		Type: "escrow_refund", ParentID: &holdID}}
+	newEscrow := func() *Escrow {
+		return &Escrow{ID: "escrow-1", Status: "refunded", SettlementTransactionID: &settlementID, SettledAt: &now, UpdatedAt: now}
+	}
+
+	t.Run("refunds the buyer", func(t *testing.T) {
+		dao, dbMock, redisMock := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(update).WithArgs(&now, &settlementID, "refunded", now, "escrow-1", "held", "disputed").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallets" WHERE id IN ($1,$2) ORDER BY id FOR UPDATE`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 60.0).AddRow("wallet-escrow", 40.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WithArgs(100.0, "wallet-1").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WithArgs(0.0, "wallet-escrow").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).
+			WithArgs("tx-3", "wallet-escrow", "escrow_refund", -40.0, "wallet-1", 0.0, &holdID, nil, nil, sqlmock.AnyArg(),
+				"tx-4", "wallet-1", "escrow_refund", 40.0, "wallet-escrow", 100.0, &holdID, nil, nil, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		dbMock.ExpectCommit()
+		redisMock.ExpectDel("wallet_balance:wallet-1").SetVal(1)
+		redisMock.ExpectDel("wallet_balance:wallet-escrow").SetVal(1)
+
+		assert.NoError(t, dao.SettleEscrow(newEscrow(), []string{"held", "disputed"}, legs))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("already settled posts nothing", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectRollback()
+
+		assert.ErrorIs(t, dao.SettleEscrow(newEscrow(), []string{"held", "disputed"}, legs), ErrEscrowNotFound)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
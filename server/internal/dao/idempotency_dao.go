@@ -25,3 +25,15 @@ func (dao *WalletDao) SaveIdempotencyKey(record *IdempotencyRecord) error {
 		Create(record).
 		Error
 }
+
+// UpdateIdempotencyKey replaces the stored response of an existing idempotency key.
+func (dao *WalletDao) UpdateIdempotencyKey(record *IdempotencyRecord) error {
+	return dao.db.
+		Table("idempotency_keys").
+		Where("key = ? AND method = ? AND path = ?", record.Key, record.Method, record.Path).
+		Updates(map[string]any{
+			"response":    record.Response,
+			"status_code": record.StatusCode,
+		}).
+		Error
+}
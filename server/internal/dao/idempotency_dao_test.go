@@ -94,3 +94,24 @@ func TestCheckIdempotencyKey(t *testing.T) {
 		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
+
+func TestUpdateIdempotencyKey(t *testing.T) {
+	db, mock := setupMockDB(t)
+	dao := &WalletDao{db: db}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "idempotency_keys" SET "response"=$1,"status_code"=$2 WHERE key = $3 AND method = $4 AND path = $5`)).
+		WithArgs(`{"status":"success"}`, 200, "key-123", "POST", "/wallets/transfer").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := dao.UpdateIdempotencyKey(&IdempotencyRecord{
+		Key:        "key-123",
+		Method:     "POST",
+		Path:       "/wallets/transfer",
+		Response:   `{"status":"success"}`,
+		StatusCode: 200,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
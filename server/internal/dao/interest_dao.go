@@ -0,0 +1,116 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+var ErrInterestPosted = errors.New("interest already posted")
+
+// ListInterestWallets returns every wallet that accrues interest: savings wallets, and wallets
+// with a credit line, which are charged interest on their overdraft.
+func (dao *WalletDao) ListInterestWallets() ([]Wallet, error) {
+	var wallets []Wallet
+	if err := dao.db.Table("wallets").Where("type = ? OR credit_limit > 0", common.WalletTypeSavings).
+		Order("id").Find(&wallets).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list interest wallets")
+		return nil, err
+	}
+	return wallets, nil
+}
+
+// GetLastInterestAccrualDate returns the latest day interest was accrued for a wallet, or nil
+// when there is none yet.
+func (dao *WalletDao) GetLastInterestAccrualDate(walletID string) (*time.Time, error) {
+	var last *time.Time
+	if err := dao.db.Table("interest_accruals").Select("MAX(accrual_date)").
+		Where("wallet_id = ?", walletID).Scan(&last).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to fetch last interest accrual")
+		return nil, err
+	}
+	return last, nil
+}
+
+// CreateInterestAccruals stores daily accruals and returns how many were new. A day accrued
+// before is left as it is.
+func (dao *WalletDao) CreateInterestAccruals(accruals []InterestAccrual) (int64, error) {
+	if len(accruals) == 0 {
+		return 0, nil
+	}
+	result := dao.db.Table("interest_accruals").Clauses(clause.OnConflict{DoNothing: true}).Create(&accruals)
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to store interest accruals")
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// ListInterestAccruals returns a wallet's accruals for the days from from up to and including
+// to, oldest first.
+func (dao *WalletDao) ListInterestAccruals(walletID string, from, to time.Time) ([]InterestAccrual, error) {
+	var accruals []InterestAccrual
+	if err := dao.db.Table("interest_accruals").
+		Where("wallet_id = ? AND accrual_date >= ? AND accrual_date <= ?", walletID, from, to).
+		Order("accrual_date").Find(&accruals).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list interest accruals")
+		return nil, err
+	}
+	return accruals, nil
+}
+
+// ListUnpostedInterestAccruals returns a wallet's accruals before a day that are not posted yet,
+// oldest first.
+func (dao *WalletDao) ListUnpostedInterestAccruals(walletID string, before time.Time) ([]InterestAccrual, error) {
+	var accruals []InterestAccrual
+	if err := dao.db.Table("interest_accruals").
+		Where("wallet_id = ? AND accrual_date < ? AND posted_at IS NULL", walletID, before).
+		Order("accrual_date").Find(&accruals).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list unposted interest accruals")
+		return nil, err
+	}
+	return accruals, nil
+}
+
+// PostInterest credits the interest transaction and marks the wallet's unposted accruals of the
+// days from from up to, but excluding, to as posted, in one DB transaction. A nil credit, for
+// interest that rounds to zero, only marks the accruals. It returns ErrInterestPosted when
+// another run posted them first.
+func (dao *WalletDao) PostInterest(walletID string, from, to, postedAt time.Time, credit *Transaction) error {
+	dao.logger.Infof("Posting interest of wallet %s for %s", walletID, from.Format("2006-01"))
+
+	err := dao.db.Transaction(func(db *gorm.DB) error {
+		update := map[string]any{"posted_at": postedAt}
+		if credit != nil {
+			if err := creditWallet(db, credit); err != nil {
+				return err
+			}
+			update["transaction_id"] = credit.ID
+		}
+
+		result := db.Table("interest_accruals").
+			Where("wallet_id = ? AND accrual_date >= ? AND accrual_date < ? AND posted_at IS NULL", walletID, from, to).
+			Updates(update)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInterestPosted
+		}
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, ErrInterestPosted) {
+			dao.logger.WithError(err).Error("Failed to post interest")
+		}
+		return err
+	}
+	if credit != nil {
+		dao.invalidateBalance(walletID)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateInterestAccruals(t *testing.T) {
+	insert := regexp.QuoteMeta(`INSERT INTO "interest_accruals"`) + `.*` + regexp.QuoteMeta(`ON CONFLICT DO NOTHING`)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	accruals := []InterestAccrual{
+		{WalletID: "wallet-1", AccrualDate: day, Balance: 1000, AnnualRate: 0.02, Amount: "0.0547945205"},
+		{WalletID: "wallet-1", AccrualDate: day.AddDate(0, 0, 1), Balance: 1000, AnnualRate: 0.02, Amount: "0.0547945205"},
+	}
+
+	dao, dbMock, _ := setupTest(t)
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectCommit()
+
+	created, err := dao.CreateInterestAccruals(accruals)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), created, "the day accrued before is skipped")
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+
+	created, err = dao.CreateInterestAccruals(nil)
+	assert.NoError(t, err)
+	assert.Zero(t, created)
+}
+
+func TestPostInterest(t *testing.T) {
+	lockWallet := regexp.QuoteMeta(`SELECT * FROM "wallets" WHERE id = $1 ORDER BY "wallets"."id" LIMIT $2 FOR UPDATE`)
+	markPosted := regexp.QuoteMeta(`UPDATE "interest_accruals" SET "posted_at"=$1,"transaction_id"=$2 WHERE wallet_id = $3 AND accrual_date >= $4 AND accrual_date < $5 AND posted_at IS NULL`)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	now := time.Now()
+
+	t.Run("credits the month", func(t *testing.T) {
+		dao, dbMock, redisMock := setupTest(t)
+		credit := &Transaction{ID: "tx-i", WalletID: "wallet-1", Type: "interest", Amount: 1.6986, CreatedAt: now}
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(lockWallet).WithArgs("wallet-1", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 1000.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets" SET "balance"=$1 WHERE id = $2`)).WithArgs(1001.6986, "wallet-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(markPosted).WithArgs(now, "tx-i", "wallet-1", from, to).
+			WillReturnResult(sqlmock.NewResult(0, 31))
+		dbMock.ExpectCommit()
+		redisMock.ExpectDel("wallet_balance:wallet-1").SetVal(1)
+
+		assert.NoError(t, dao.PostInterest("wallet-1", from, to, now, credit))
+		assert.Equal(t, 1001.6986, *credit.BalanceAfter)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("zero interest only marks the days", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "interest_accruals" SET "posted_at"=$1`)).
+			WillReturnResult(sqlmock.NewResult(0, 31))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.PostInterest("wallet-1", from, to, now, nil))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("already posted rolls back the credit", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		credit := &Transaction{ID: "tx-i", WalletID: "wallet-1", Type: "interest", Amount: 1.6986, CreatedAt: now}
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(lockWallet).WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 1000.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(markPosted).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectRollback()
+
+		assert.ErrorIs(t, dao.PostInterest("wallet-1", from, to, now, credit), ErrInterestPosted)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
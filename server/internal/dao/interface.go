@@ -1,12 +1,118 @@
 package dao
 
+import "time"
+
 //go:generate mockery --name=WalletDaoInterface --output=mocks --outpkg=mocks
 type WalletDaoInterface interface {
 	GetBalance(walletID string) (float64, error)
 	UpdateBalance(input *UpdateBalance) error
 	CreateTransaction(tx *Transaction) error
 	GetWalletByID(walletID string) (*Wallet, error)
-	GetTransactionHistory(walletID, txType, start, end string, limit, offset int) ([]Transaction, error)
+	SetCreditLimit(walletID string, limit float64) error
+	ListPockets(parentWalletID string) ([]Wallet, error)
+	UpdatePocketGoal(pocketID string, amount *float64, date *time.Time) error
+	GetTransactionHistory(filter TransactionFilter) ([]Transaction, error)
+	StreamTransactionHistory(filter TransactionFilter, fn func(*Transaction) error) error
 	SaveIdempotencyKey(record *IdempotencyRecord) error
 	CheckIdempotencyKey(key, method, path string) (*IdempotencyRecord, bool)
+	UpdateIdempotencyKey(record *IdempotencyRecord) error
+	GetUserByID(userID string) (*User, error)
+	GetUserByWalletID(walletID string) (*User, error)
+	UpgradeKycLevel(upgrade *KycUpgrade) error
+	CreateUserWithWallet(user *User, wallet *Wallet) error
+	SaveUserScreening(screening *UserScreening) error
+	FreezeUserWallets(userID string) error
+	CreateTransactionReview(review *TransactionReview) error
+	GetTransactionReview(reviewID string) (*TransactionReview, error)
+	ListTransactionReviews(status string) ([]TransactionReview, error)
+	SetTransactionReviewRequest(reviewID, idempotencyKey, method, path string) error
+	UpdateTransactionReviewStatus(reviewID, fromStatus, toStatus, reviewedBy string) error
+	CreatePendingOperation(op *PendingOperation) error
+	GetPendingOperation(operationID string) (*PendingOperation, error)
+	ListPendingOperations(status string) ([]PendingOperation, error)
+	UpdatePendingOperationStatus(operationID, fromStatus, toStatus, decidedBy string, result *string) error
+	ExpirePendingOperations(now time.Time) ([]PendingOperation, error)
+	CreateAdjustment(adjustment *Adjustment) error
+	ListAdjustments(walletID string) ([]Adjustment, error)
+	AppendAuditLog(entry *AuditLog) error
+	GetLatestAuditLog(action, entityType, entityID string) (*AuditLog, error)
+	ListAuditLog(afterSeq int64, limit int) ([]AuditLog, error)
+	ListLedgerDiscrepancies() ([]LedgerBalance, error)
+	RecordLedgerCorrection(tx *Transaction, adjustment *Adjustment) error
+	CreateBalanceSnapshots(asOf time.Time) (int64, error)
+	GetBalanceAsOf(walletID string, asOf time.Time) (float64, error)
+	CreateStatement(statement *Statement) error
+	GetStatement(statementID string) (*Statement, error)
+	GetStatementForPeriod(walletID string, periodStart time.Time) (*Statement, error)
+	ListStatements(walletID string) ([]Statement, error)
+	ListWalletsWithoutStatement(periodStart, periodEnd time.Time) ([]string, error)
+	ListUnmatchedDeposits(from, to time.Time) ([]Transaction, error)
+	CreateBankImport(bankImport *BankImport, matches []BankMatch) error
+	GetBankImport(importID string) (*BankImport, error)
+	GetBankImportByHash(fileHash string) (*BankImport, error)
+	GetPayout(payoutID string) (*Payout, error)
+	ListPayouts(status string, limit int) ([]Payout, error)
+	MarkPayoutsSent(batch *PayoutBatch, payoutIDs []string) error
+	UpdatePayoutStatus(payoutID, fromStatus, toStatus string) error
+	ReturnPayout(payoutID, fromStatus, reason string, reversal *Transaction) error
+	CreateProviderOperation(op *ProviderOperation) error
+	GetProviderOperation(opID string) (*ProviderOperation, error)
+	ListProviderOperations(kind string, statuses []string, limit int) ([]ProviderOperation, error)
+	UpdateProviderOperation(opID, fromStatus string, update ProviderOperationUpdate) error
+	ReverseProviderOperation(opID, fromStatus, reason string, reversal *Transaction) error
+	CompleteProviderPayin(opID, fromStatus string, deposit *Transaction) error
+	CreateSchedule(schedule *Schedule) error
+	GetSchedule(scheduleID string) (*Schedule, error)
+	ListSchedules(walletID string) ([]Schedule, error)
+	ListDueSchedules(now time.Time, limit int) ([]Schedule, error)
+	UpdateScheduleStatus(scheduleID string, fromStatuses []string, toStatus string, nextRunAt *time.Time) error
+	ClaimScheduleRun(run *ScheduleRun) error
+	FinishScheduleRun(idempotencyKey, status string, runErr *string) error
+	AdvanceSchedule(scheduleID string, occurrence time.Time, advance ScheduleAdvance) error
+	ListScheduleRuns(scheduleID string, limit int) ([]ScheduleRun, error)
+	CreateTransferBatch(batch *TransferBatch, items []TransferBatchItem) error
+	GetTransferBatch(batchID string) (*TransferBatch, error)
+	GetTransferBatchByKey(idempotencyKey string) (*TransferBatch, error)
+	ListTransferBatchItems(batchID string) ([]TransferBatchItem, error)
+	ListPendingTransferBatches(limit int) ([]TransferBatch, error)
+	ClaimTransferBatch(batchID string) error
+	ListStalledTransferBatches(before time.Time, limit int) ([]TransferBatch, error)
+	ReclaimTransferBatch(batchID string, before time.Time) error
+	UpdateTransferBatchItem(itemID, status string, itemErr, transactionID *string) error
+	ParkTransferBatchItem(itemID, reviewID string) error
+	PostTransferBatchItem(itemID string, legs []TransferLeg) error
+	PostReviewedTransfer(reviewID string, legs []TransferLeg) error
+	FailReviewedTransferBatchItem(reviewID, itemErr string) error
+	FinishTransferBatch(batch *TransferBatch) error
+	ExecuteTransferBatch(batch *TransferBatch, itemIDs []string, legs []TransferLeg) error
+	PostTransfers(legs []TransferLeg) error
+	PostWithdrawal(w Withdrawal) error
+	CreateWallet(wallet *Wallet) error
+	ListInterestWallets() ([]Wallet, error)
+	GetLastInterestAccrualDate(walletID string) (*time.Time, error)
+	CreateInterestAccruals(accruals []InterestAccrual) (int64, error)
+	ListInterestAccruals(walletID string, from, to time.Time) ([]InterestAccrual, error)
+	ListUnpostedInterestAccruals(walletID string, before time.Time) ([]InterestAccrual, error)
+	PostInterest(walletID string, from, to, postedAt time.Time, credit *Transaction) error
+	GetWalletMember(walletID, userID string) (*WalletMember, error)
+	ListWalletMembers(walletID string) ([]WalletMember, error)
+	SaveInvitation(member *WalletMember) error
+	AcceptInvitation(walletID, userID string, acceptedAt time.Time) error
+	RemoveWalletMember(walletID, userID string) error
+	SumMemberSpending(walletID, userID string, since time.Time) (float64, error)
+	CreateEscrow(escrow *Escrow, legs []TransferLeg) error
+	GetEscrow(escrowID string) (*Escrow, error)
+	ListExpiredEscrows(now time.Time, limit int) ([]Escrow, error)
+	DisputeEscrow(escrowID, reason, disputedBy string, at time.Time) error
+	SettleEscrow(escrow *Escrow, fromStatuses []string, legs []TransferLeg) error
+	CreatePaymentRequest(req *PaymentRequest) error
+	GetPaymentRequest(requestID string) (*PaymentRequest, error)
+	ListPaymentRequests(walletID string, incoming bool, status string, limit int) ([]PaymentRequest, error)
+	ListExpiredPaymentRequests(now time.Time, limit int) ([]PaymentRequest, error)
+	UpdatePaymentRequest(req *PaymentRequest, fromStatus string) error
+	CreatePaymentLink(link *PaymentLink) error
+	GetPaymentLink(linkID string) (*PaymentLink, error)
+	ClaimPaymentLink(linkID string, at time.Time) error
+	ReleasePaymentLink(linkID string, at time.Time) error
+	SetPaymentLinkTransaction(linkID, transactionID string) error
 }
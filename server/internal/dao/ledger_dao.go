@@ -0,0 +1,73 @@
+package dao
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+var (
+	ErrLedgerChanged = errors.New("wallet ledger changed since it was checked")
+)
+
+// signedAmountSQL is a transaction's effect on its wallet's balance. Withdrawals are stored as
+// positive amounts, every other type already carries its sign.
+const signedAmountSQL = "CASE WHEN transactions.type = 'withdraw' THEN -transactions.amount ELSE transactions.amount END"
+
+// ListLedgerDiscrepancies returns every wallet whose stored balance differs from the sum of its transactions.
+func (dao *WalletDao) ListLedgerDiscrepancies() ([]LedgerBalance, error) {
+	var balances []LedgerBalance
+	err := dao.db.Table("wallets").
+		Select("wallets.id AS wallet_id, wallets.balance AS balance, " +
+			"COALESCE(SUM(" + signedAmountSQL + "), 0) AS ledger_balance, COUNT(transactions.id) AS transaction_count").
+		Joins("LEFT JOIN transactions ON transactions.wallet_id = wallets.id").
+		Group("wallets.id, wallets.balance").
+		Having("wallets.balance <> COALESCE(SUM(" + signedAmountSQL + "), 0)").
+		Order("wallets.id").
+		Scan(&balances).Error
+	if err != nil {
+		dao.logger.WithError(err).Error("Failed to compute ledger balances")
+		return nil, err
+	}
+	return balances, nil
+}
+
+// RecordLedgerCorrection writes a correcting transaction and its adjustment record without touching
+// the stored balance. The wallet is locked and re-checked first, so a discrepancy that changed or
+// disappeared since it was reported returns ErrLedgerChanged instead of being corrected twice.
+func (dao *WalletDao) RecordLedgerCorrection(tx *Transaction, adjustment *Adjustment) error {
+	dao.logger.Warnf("Recording ledger correction of %.4f on wallet %s", tx.Amount, tx.WalletID)
+
+	return dao.db.Transaction(func(db *gorm.DB) error {
+		var wallet Wallet
+		if err := db.Table("wallets").Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", tx.WalletID).First(&wallet).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrWalletNotFound
+			}
+			return err
+		}
+
+		var ledger float64
+		if err := db.Table("transactions").Select("COALESCE(SUM("+signedAmountSQL+"), 0)").
+			Where("wallet_id = ?", tx.WalletID).Scan(&ledger).Error; err != nil {
+			return err
+		}
+		if common.RoundToNDecimals(wallet.Balance-ledger, 4) != common.RoundToNDecimals(tx.Amount, 4) {
+			return ErrLedgerChanged
+		}
+
+		if err := db.Table("transactions").Create(tx).Error; err != nil {
+			dao.logger.WithError(err).Error("Failed to create correcting transaction")
+			return err
+		}
+		if err := db.Table("adjustments").Create(adjustment).Error; err != nil {
+			dao.logger.WithError(err).Error("Failed to record correcting adjustment")
+			return err
+		}
+		return nil
+	})
+}
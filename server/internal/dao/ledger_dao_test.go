@@ -0,0 +1,56 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListLedgerDiscrepancies(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT wallets.id AS wallet_id, wallets.balance AS balance, COALESCE(SUM(CASE WHEN transactions.type = 'withdraw' THEN -transactions.amount ELSE transactions.amount END), 0) AS ledger_balance`)).
+		WillReturnRows(sqlmock.NewRows([]string{"wallet_id", "balance", "ledger_balance", "transaction_count"}).
+			AddRow("wallet-1", 100.0, 90.0, 4))
+
+	balances, err := dao.ListLedgerDiscrepancies()
+	assert.NoError(t, err)
+	assert.Equal(t, []LedgerBalance{{WalletID: "wallet-1", Balance: 100, LedgerBalance: 90, TransactionCount: 4}}, balances)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestRecordLedgerCorrection(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	tx := &Transaction{ID: "tx-1", WalletID: "wallet-1", Type: "adjustment", Amount: 10}
+	adjustment := &Adjustment{ID: "adj-1", TransactionID: "tx-1", WalletID: "wallet-1", Amount: 10}
+
+	t.Run("correction written", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallets" WHERE id = $1 ORDER BY "wallets"."id" LIMIT $2 FOR UPDATE`)).
+			WithArgs("wallet-1", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 100.0))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(`)).
+			WithArgs("wallet-1").
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(90.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "adjustments"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.RecordLedgerCorrection(tx, adjustment))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("ledger changed since check", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallets"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 100.0))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(`)).
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(100.0))
+		dbMock.ExpectRollback()
+
+		assert.ErrorIs(t, dao.RecordLedgerCorrection(tx, adjustment), ErrLedgerChanged)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
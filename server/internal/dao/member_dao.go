@@ -0,0 +1,118 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+var ErrMemberNotFound = errors.New("wallet member not found")
+
+// GetWalletMember returns a user's membership of a wallet, invited or active.
+func (dao *WalletDao) GetWalletMember(walletID, userID string) (*WalletMember, error) {
+	var member WalletMember
+	result := dao.db.Table("wallet_members").Where("wallet_id = ? AND user_id = ?", walletID, userID).First(&member)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrMemberNotFound
+		}
+		dao.logger.WithError(result.Error).Error("Failed to fetch wallet member")
+		return nil, result.Error
+	}
+	return &member, nil
+}
+
+// ListWalletMembers returns the members of a wallet, oldest first.
+func (dao *WalletDao) ListWalletMembers(walletID string) ([]WalletMember, error) {
+	var members []WalletMember
+	if err := dao.db.Table("wallet_members").Where("wallet_id = ?", walletID).
+		Order("created_at, user_id").Find(&members).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list wallet members")
+		return nil, err
+	}
+	return members, nil
+}
+
+// SaveInvitation stores an invitation to a wallet. Inviting a user again while the invitation is
+// open replaces its role and cap; an active member is left as they are.
+func (dao *WalletDao) SaveInvitation(member *WalletMember) error {
+	dao.logger.Infof("Inviting user %s to wallet %s as %s", member.UserID, member.WalletID, member.Role)
+
+	err := dao.db.Table("wallet_members").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "wallet_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "spending_cap", "invited_by", "created_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: "wallet_members", Name: "status"}, Value: common.MemberStatusInvited},
+		}},
+	}).Create(member).Error
+	if err != nil {
+		dao.logger.WithError(err).Error("Failed to store invitation")
+	}
+	return err
+}
+
+// AcceptInvitation makes an invited member active. It returns ErrMemberNotFound when the user
+// has no open invitation to the wallet.
+func (dao *WalletDao) AcceptInvitation(walletID, userID string, acceptedAt time.Time) error {
+	dao.logger.Infof("User %s accepting invitation to wallet %s", userID, walletID)
+
+	result := dao.db.Table("wallet_members").
+		Where("wallet_id = ? AND user_id = ? AND status = ?", walletID, userID, common.MemberStatusInvited).
+		Updates(map[string]any{"status": common.MemberStatusActive, "accepted_at": acceptedAt})
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to accept invitation")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+// RemoveWalletMember removes a member or withdraws their invitation.
+func (dao *WalletDao) RemoveWalletMember(walletID, userID string) error {
+	dao.logger.Infof("Removing user %s from wallet %s", userID, walletID)
+
+	result := dao.db.Table("wallet_members").Where("wallet_id = ? AND user_id = ?", walletID, userID).
+		Delete(&WalletMember{})
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to remove wallet member")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+// SumMemberSpending returns what a member moved out of a wallet by withdrawals, transfers and
+// escrow payments since a point in time. Fees are not counted.
+func (dao *WalletDao) SumMemberSpending(walletID, userID string, since time.Time) (float64, error) {
+	var spent float64
+	err := dao.db.Table("transactions").Select("COALESCE(SUM(-("+signedAmountSQL+")), 0)").
+		Where("wallet_id = ? AND initiated_by = ? AND created_at >= ?", walletID, userID, since).
+		Where("type IN ? AND ("+signedAmountSQL+") < 0",
+			[]string{common.TransactionTypeWithdraw, common.TransactionTypeTransfer, common.TransactionTypeEscrowHold}).
+		Scan(&spent).Error
+	if err != nil {
+		dao.logger.WithError(err).Error("Failed to sum member spending")
+		return 0, err
+	}
+	return spent, nil
+}
+
+// ownerMember is the membership a new wallet's user holds from the start.
+func ownerMember(wallet *Wallet) *WalletMember {
+	return &WalletMember{
+		WalletID:   wallet.ID,
+		UserID:     wallet.UserID,
+		Role:       common.MemberRoleOwner,
+		Status:     common.MemberStatusActive,
+		CreatedAt:  wallet.CreatedAt,
+		AcceptedAt: &wallet.CreatedAt,
+	}
+}
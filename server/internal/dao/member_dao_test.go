@@ -0,0 +1,100 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWalletMember(t *testing.T) {
+	query := regexp.QuoteMeta(`SELECT * FROM "wallet_members" WHERE wallet_id = $1 AND user_id = $2 ORDER BY "wallet_members"."wallet_id" LIMIT $3`)
+
+	t.Run("member", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectQuery(query).WithArgs("wallet-1", "user-2", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"wallet_id", "user_id", "role", "spending_cap", "status"}).
+				AddRow("wallet-1", "user-2", "spender", 200.0, "active"))
+
+		member, err := dao.GetWalletMember("wallet-1", "user-2")
+		assert.NoError(t, err)
+		assert.Equal(t, "spender", member.Role)
+		assert.Equal(t, 200.0, *member.SpendingCap)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"wallet_id"}))
+
+		_, err := dao.GetWalletMember("wallet-1", "user-3")
+		assert.ErrorIs(t, err, ErrMemberNotFound)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestSaveInvitation(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	now := time.Now()
+	member := &WalletMember{WalletID: "wallet-1", UserID: "user-2", Role: "viewer", Status: "invited", InvitedBy: "user-1", CreatedAt: now}
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_members" ("wallet_id","user_id","role","spending_cap","status","invited_by","created_at","accepted_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8) ON CONFLICT ("wallet_id","user_id") DO UPDATE SET "role"="excluded"."role","spending_cap"="excluded"."spending_cap","invited_by"="excluded"."invited_by","created_at"="excluded"."created_at" WHERE "wallet_members"."status" = $9`)).
+		WithArgs("wallet-1", "user-2", "viewer", nil, "invited", "user-1", now, nil, "invited").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectCommit()
+
+	assert.NoError(t, dao.SaveInvitation(member))
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	update := regexp.QuoteMeta(`UPDATE "wallet_members" SET "accepted_at"=$1,"status"=$2 WHERE wallet_id = $3 AND user_id = $4 AND status = $5`)
+	now := time.Now()
+
+	t.Run("accepted", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(update).WithArgs(now, "active", "wallet-1", "user-2", "invited").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.AcceptInvitation("wallet-1", "user-2", now))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("no open invitation", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectCommit()
+
+		assert.ErrorIs(t, dao.AcceptInvitation("wallet-1", "user-2", now), ErrMemberNotFound)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestRemoveWalletMember(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "wallet_members" WHERE wallet_id = $1 AND user_id = $2`)).
+		WithArgs("wallet-1", "user-2").WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectCommit()
+
+	assert.ErrorIs(t, dao.RemoveWalletMember("wallet-1", "user-2"), ErrMemberNotFound)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestSumMemberSpending(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(-(`+signedAmountSQL+`)), 0) FROM "transactions" WHERE (wallet_id = $1 AND initiated_by = $2 AND created_at >= $3) AND (type IN ($4,$5,$6) AND (`+signedAmountSQL+`) < 0)`)).
+		WithArgs("wallet-1", "user-2", since, "withdraw", "transfer", "escrow_hold").
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(75.5))
+
+	spent, err := dao.SumMemberSpending("wallet-1", "user-2", since)
+	assert.NoError(t, err)
+	assert.Equal(t, 75.5, spent)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
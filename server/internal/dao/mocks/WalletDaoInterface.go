@@ -5,6 +5,8 @@ package mocks
 import (
 	dao "github.com/julkhong/walletapp/server/internal/dao"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // WalletDaoInterface is an autogenerated mock type for the WalletDaoInterface type
@@ -12,6 +14,60 @@ type WalletDaoInterface struct {
 	mock.Mock
 }
 
+// AcceptInvitation provides a mock function with given fields: walletID, userID, acceptedAt
+func (_m *WalletDaoInterface) AcceptInvitation(walletID string, userID string, acceptedAt time.Time) error {
+	ret := _m.Called(walletID, userID, acceptedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcceptInvitation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) error); ok {
+		r0 = rf(walletID, userID, acceptedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AdvanceSchedule provides a mock function with given fields: scheduleID, occurrence, advance
+func (_m *WalletDaoInterface) AdvanceSchedule(scheduleID string, occurrence time.Time, advance dao.ScheduleAdvance) error {
+	ret := _m.Called(scheduleID, occurrence, advance)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdvanceSchedule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, dao.ScheduleAdvance) error); ok {
+		r0 = rf(scheduleID, occurrence, advance)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AppendAuditLog provides a mock function with given fields: entry
+func (_m *WalletDaoInterface) AppendAuditLog(entry *dao.AuditLog) error {
+	ret := _m.Called(entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AppendAuditLog")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.AuditLog) error); ok {
+		r0 = rf(entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CheckIdempotencyKey provides a mock function with given fields: key, method, path
 func (_m *WalletDaoInterface) CheckIdempotencyKey(key string, method string, path string) (*dao.IdempotencyRecord, bool) {
 	ret := _m.Called(key, method, path)
@@ -42,17 +98,17 @@ func (_m *WalletDaoInterface) CheckIdempotencyKey(key string, method string, pat
 	return r0, r1
 }
 
-// CreateTransaction provides a mock function with given fields: tx
-func (_m *WalletDaoInterface) CreateTransaction(tx *dao.Transaction) error {
-	ret := _m.Called(tx)
+// ClaimPaymentLink provides a mock function with given fields: linkID, at
+func (_m *WalletDaoInterface) ClaimPaymentLink(linkID string, at time.Time) error {
+	ret := _m.Called(linkID, at)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateTransaction")
+		panic("no return value specified for ClaimPaymentLink")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*dao.Transaction) error); ok {
-		r0 = rf(tx)
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(linkID, at)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -60,57 +116,99 @@ func (_m *WalletDaoInterface) CreateTransaction(tx *dao.Transaction) error {
 	return r0
 }
 
-// GetBalance provides a mock function with given fields: walletID
-func (_m *WalletDaoInterface) GetBalance(walletID string) (float64, error) {
-	ret := _m.Called(walletID)
+// ClaimScheduleRun provides a mock function with given fields: run
+func (_m *WalletDaoInterface) ClaimScheduleRun(run *dao.ScheduleRun) error {
+	ret := _m.Called(run)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetBalance")
+		panic("no return value specified for ClaimScheduleRun")
 	}
 
-	var r0 float64
-	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (float64, error)); ok {
-		return rf(walletID)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.ScheduleRun) error); ok {
+		r0 = rf(run)
+	} else {
+		r0 = ret.Error(0)
 	}
-	if rf, ok := ret.Get(0).(func(string) float64); ok {
-		r0 = rf(walletID)
+
+	return r0
+}
+
+// ClaimTransferBatch provides a mock function with given fields: batchID
+func (_m *WalletDaoInterface) ClaimTransferBatch(batchID string) error {
+	ret := _m.Called(batchID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimTransferBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(batchID)
 	} else {
-		r0 = ret.Get(0).(float64)
+		r0 = ret.Error(0)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(walletID)
+	return r0
+}
+
+// CompleteProviderPayin provides a mock function with given fields: opID, fromStatus, deposit
+func (_m *WalletDaoInterface) CompleteProviderPayin(opID string, fromStatus string, deposit *dao.Transaction) error {
+	ret := _m.Called(opID, fromStatus, deposit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteProviderPayin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, *dao.Transaction) error); ok {
+		r0 = rf(opID, fromStatus, deposit)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// GetTransactionHistory provides a mock function with given fields: walletID, txType, start, end, limit, offset
-func (_m *WalletDaoInterface) GetTransactionHistory(walletID string, txType string, start string, end string, limit int, offset int) ([]dao.Transaction, error) {
-	ret := _m.Called(walletID, txType, start, end, limit, offset)
+// CreateAdjustment provides a mock function with given fields: adjustment
+func (_m *WalletDaoInterface) CreateAdjustment(adjustment *dao.Adjustment) error {
+	ret := _m.Called(adjustment)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTransactionHistory")
+		panic("no return value specified for CreateAdjustment")
 	}
 
-	var r0 []dao.Transaction
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.Adjustment) error); ok {
+		r0 = rf(adjustment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateBalanceSnapshots provides a mock function with given fields: asOf
+func (_m *WalletDaoInterface) CreateBalanceSnapshots(asOf time.Time) (int64, error) {
+	ret := _m.Called(asOf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBalanceSnapshots")
+	}
+
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, string, string, int, int) ([]dao.Transaction, error)); ok {
-		return rf(walletID, txType, start, end, limit, offset)
+	if rf, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return rf(asOf)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, string, string, int, int) []dao.Transaction); ok {
-		r0 = rf(walletID, txType, start, end, limit, offset)
+	if rf, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = rf(asOf)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]dao.Transaction)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, string, string, int, int) error); ok {
-		r1 = rf(walletID, txType, start, end, limit, offset)
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(asOf)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -118,29 +216,63 @@ func (_m *WalletDaoInterface) GetTransactionHistory(walletID string, txType stri
 	return r0, r1
 }
 
-// GetWalletByID provides a mock function with given fields: walletID
-func (_m *WalletDaoInterface) GetWalletByID(walletID string) (*dao.Wallet, error) {
-	ret := _m.Called(walletID)
+// CreateBankImport provides a mock function with given fields: bankImport, matches
+func (_m *WalletDaoInterface) CreateBankImport(bankImport *dao.BankImport, matches []dao.BankMatch) error {
+	ret := _m.Called(bankImport, matches)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetWalletByID")
+		panic("no return value specified for CreateBankImport")
 	}
 
-	var r0 *dao.Wallet
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.BankImport, []dao.BankMatch) error); ok {
+		r0 = rf(bankImport, matches)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateEscrow provides a mock function with given fields: escrow, legs
+func (_m *WalletDaoInterface) CreateEscrow(escrow *dao.Escrow, legs []dao.TransferLeg) error {
+	ret := _m.Called(escrow, legs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateEscrow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.Escrow, []dao.TransferLeg) error); ok {
+		r0 = rf(escrow, legs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateInterestAccruals provides a mock function with given fields: accruals
+func (_m *WalletDaoInterface) CreateInterestAccruals(accruals []dao.InterestAccrual) (int64, error) {
+	ret := _m.Called(accruals)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateInterestAccruals")
+	}
+
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*dao.Wallet, error)); ok {
-		return rf(walletID)
+	if rf, ok := ret.Get(0).(func([]dao.InterestAccrual) (int64, error)); ok {
+		return rf(accruals)
 	}
-	if rf, ok := ret.Get(0).(func(string) *dao.Wallet); ok {
-		r0 = rf(walletID)
+	if rf, ok := ret.Get(0).(func([]dao.InterestAccrual) int64); ok {
+		r0 = rf(accruals)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*dao.Wallet)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(walletID)
+	if rf, ok := ret.Get(1).(func([]dao.InterestAccrual) error); ok {
+		r1 = rf(accruals)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -148,17 +280,17 @@ func (_m *WalletDaoInterface) GetWalletByID(walletID string) (*dao.Wallet, error
 	return r0, r1
 }
 
-// SaveIdempotencyKey provides a mock function with given fields: record
-func (_m *WalletDaoInterface) SaveIdempotencyKey(record *dao.IdempotencyRecord) error {
-	ret := _m.Called(record)
+// CreatePaymentLink provides a mock function with given fields: link
+func (_m *WalletDaoInterface) CreatePaymentLink(link *dao.PaymentLink) error {
+	ret := _m.Called(link)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SaveIdempotencyKey")
+		panic("no return value specified for CreatePaymentLink")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*dao.IdempotencyRecord) error); ok {
-		r0 = rf(record)
+	if rf, ok := ret.Get(0).(func(*dao.PaymentLink) error); ok {
+		r0 = rf(link)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -166,17 +298,2327 @@ func (_m *WalletDaoInterface) SaveIdempotencyKey(record *dao.IdempotencyRecord)
 	return r0
 }
 
-// UpdateBalance provides a mock function with given fields: input
-func (_m *WalletDaoInterface) UpdateBalance(input *dao.UpdateBalance) error {
-	ret := _m.Called(input)
+// CreatePaymentRequest provides a mock function with given fields: req
+func (_m *WalletDaoInterface) CreatePaymentRequest(req *dao.PaymentRequest) error {
+	ret := _m.Called(req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateBalance")
+		panic("no return value specified for CreatePaymentRequest")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*dao.UpdateBalance) error); ok {
-		r0 = rf(input)
+	if rf, ok := ret.Get(0).(func(*dao.PaymentRequest) error); ok {
+		r0 = rf(req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreatePendingOperation provides a mock function with given fields: op
+func (_m *WalletDaoInterface) CreatePendingOperation(op *dao.PendingOperation) error {
+	ret := _m.Called(op)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePendingOperation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.PendingOperation) error); ok {
+		r0 = rf(op)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateProviderOperation provides a mock function with given fields: op
+func (_m *WalletDaoInterface) CreateProviderOperation(op *dao.ProviderOperation) error {
+	ret := _m.Called(op)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateProviderOperation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.ProviderOperation) error); ok {
+		r0 = rf(op)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateSchedule provides a mock function with given fields: schedule
+func (_m *WalletDaoInterface) CreateSchedule(schedule *dao.Schedule) error {
+	ret := _m.Called(schedule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSchedule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.Schedule) error); ok {
+		r0 = rf(schedule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateStatement provides a mock function with given fields: statement
+func (_m *WalletDaoInterface) CreateStatement(statement *dao.Statement) error {
+	ret := _m.Called(statement)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateStatement")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.Statement) error); ok {
+		r0 = rf(statement)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTransaction provides a mock function with given fields: tx
+func (_m *WalletDaoInterface) CreateTransaction(tx *dao.Transaction) error {
+	ret := _m.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTransaction")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.Transaction) error); ok {
+		r0 = rf(tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTransactionReview provides a mock function with given fields: review
+func (_m *WalletDaoInterface) CreateTransactionReview(review *dao.TransactionReview) error {
+	ret := _m.Called(review)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTransactionReview")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.TransactionReview) error); ok {
+		r0 = rf(review)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTransferBatch provides a mock function with given fields: batch, items
+func (_m *WalletDaoInterface) CreateTransferBatch(batch *dao.TransferBatch, items []dao.TransferBatchItem) error {
+	ret := _m.Called(batch, items)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTransferBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.TransferBatch, []dao.TransferBatchItem) error); ok {
+		r0 = rf(batch, items)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateUserWithWallet provides a mock function with given fields: user, wallet
+func (_m *WalletDaoInterface) CreateUserWithWallet(user *dao.User, wallet *dao.Wallet) error {
+	ret := _m.Called(user, wallet)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUserWithWallet")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.User, *dao.Wallet) error); ok {
+		r0 = rf(user, wallet)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateWallet provides a mock function with given fields: wallet
+func (_m *WalletDaoInterface) CreateWallet(wallet *dao.Wallet) error {
+	ret := _m.Called(wallet)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWallet")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.Wallet) error); ok {
+		r0 = rf(wallet)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisputeEscrow provides a mock function with given fields: escrowID, reason, disputedBy, at
+func (_m *WalletDaoInterface) DisputeEscrow(escrowID string, reason string, disputedBy string, at time.Time) error {
+	ret := _m.Called(escrowID, reason, disputedBy, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisputeEscrow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, time.Time) error); ok {
+		r0 = rf(escrowID, reason, disputedBy, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExecuteTransferBatch provides a mock function with given fields: batch, itemIDs, legs
+func (_m *WalletDaoInterface) ExecuteTransferBatch(batch *dao.TransferBatch, itemIDs []string, legs []dao.TransferLeg) error {
+	ret := _m.Called(batch, itemIDs, legs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExecuteTransferBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.TransferBatch, []string, []dao.TransferLeg) error); ok {
+		r0 = rf(batch, itemIDs, legs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpirePendingOperations provides a mock function with given fields: now
+func (_m *WalletDaoInterface) ExpirePendingOperations(now time.Time) ([]dao.PendingOperation, error) {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpirePendingOperations")
+	}
+
+	var r0 []dao.PendingOperation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) ([]dao.PendingOperation, error)); ok {
+		return rf(now)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) []dao.PendingOperation); ok {
+		r0 = rf(now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.PendingOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FailReviewedTransferBatchItem provides a mock function with given fields: reviewID, itemErr
+func (_m *WalletDaoInterface) FailReviewedTransferBatchItem(reviewID string, itemErr string) error {
+	ret := _m.Called(reviewID, itemErr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FailReviewedTransferBatchItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(reviewID, itemErr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FinishScheduleRun provides a mock function with given fields: idempotencyKey, status, runErr
+func (_m *WalletDaoInterface) FinishScheduleRun(idempotencyKey string, status string, runErr *string) error {
+	ret := _m.Called(idempotencyKey, status, runErr)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishScheduleRun")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, *string) error); ok {
+		r0 = rf(idempotencyKey, status, runErr)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FinishTransferBatch provides a mock function with given fields: batch
+func (_m *WalletDaoInterface) FinishTransferBatch(batch *dao.TransferBatch) error {
+	ret := _m.Called(batch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishTransferBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.TransferBatch) error); ok {
+		r0 = rf(batch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FreezeUserWallets provides a mock function with given fields: userID
+func (_m *WalletDaoInterface) FreezeUserWallets(userID string) error {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FreezeUserWallets")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetBalance provides a mock function with given fields: walletID
+func (_m *WalletDaoInterface) GetBalance(walletID string) (float64, error) {
+	ret := _m.Called(walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalance")
+	}
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (float64, error)); ok {
+		return rf(walletID)
+	}
+	if rf, ok := ret.Get(0).(func(string) float64); ok {
+		r0 = rf(walletID)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBalanceAsOf provides a mock function with given fields: walletID, asOf
+func (_m *WalletDaoInterface) GetBalanceAsOf(walletID string, asOf time.Time) (float64, error) {
+	ret := _m.Called(walletID, asOf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalanceAsOf")
+	}
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) (float64, error)); ok {
+		return rf(walletID, asOf)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time) float64); ok {
+		r0 = rf(walletID, asOf)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time) error); ok {
+		r1 = rf(walletID, asOf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBankImport provides a mock function with given fields: importID
+func (_m *WalletDaoInterface) GetBankImport(importID string) (*dao.BankImport, error) {
+	ret := _m.Called(importID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBankImport")
+	}
+
+	var r0 *dao.BankImport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.BankImport, error)); ok {
+		return rf(importID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.BankImport); ok {
+		r0 = rf(importID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.BankImport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(importID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBankImportByHash provides a mock function with given fields: fileHash
+func (_m *WalletDaoInterface) GetBankImportByHash(fileHash string) (*dao.BankImport, error) {
+	ret := _m.Called(fileHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBankImportByHash")
+	}
+
+	var r0 *dao.BankImport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.BankImport, error)); ok {
+		return rf(fileHash)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.BankImport); ok {
+		r0 = rf(fileHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.BankImport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(fileHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEscrow provides a mock function with given fields: escrowID
+func (_m *WalletDaoInterface) GetEscrow(escrowID string) (*dao.Escrow, error) {
+	ret := _m.Called(escrowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEscrow")
+	}
+
+	var r0 *dao.Escrow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.Escrow, error)); ok {
+		return rf(escrowID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.Escrow); ok {
+		r0 = rf(escrowID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Escrow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(escrowID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLastInterestAccrualDate provides a mock function with given fields: walletID
+func (_m *WalletDaoInterface) GetLastInterestAccrualDate(walletID string) (*time.Time, error) {
+	ret := _m.Called(walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastInterestAccrualDate")
+	}
+
+	var r0 *time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*time.Time, error)); ok {
+		return rf(walletID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *time.Time); ok {
+		r0 = rf(walletID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*time.Time)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestAuditLog provides a mock function with given fields: action, entityType, entityID
+func (_m *WalletDaoInterface) GetLatestAuditLog(action string, entityType string, entityID string) (*dao.AuditLog, error) {
+	ret := _m.Called(action, entityType, entityID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestAuditLog")
+	}
+
+	var r0 *dao.AuditLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (*dao.AuditLog, error)); ok {
+		return rf(action, entityType, entityID)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) *dao.AuditLog); ok {
+		r0 = rf(action, entityType, entityID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.AuditLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(action, entityType, entityID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPaymentLink provides a mock function with given fields: linkID
+func (_m *WalletDaoInterface) GetPaymentLink(linkID string) (*dao.PaymentLink, error) {
+	ret := _m.Called(linkID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPaymentLink")
+	}
+
+	var r0 *dao.PaymentLink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.PaymentLink, error)); ok {
+		return rf(linkID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.PaymentLink); ok {
+		r0 = rf(linkID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.PaymentLink)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(linkID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPaymentRequest provides a mock function with given fields: requestID
+func (_m *WalletDaoInterface) GetPaymentRequest(requestID string) (*dao.PaymentRequest, error) {
+	ret := _m.Called(requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPaymentRequest")
+	}
+
+	var r0 *dao.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.PaymentRequest, error)); ok {
+		return rf(requestID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.PaymentRequest); ok {
+		r0 = rf(requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPayout provides a mock function with given fields: payoutID
+func (_m *WalletDaoInterface) GetPayout(payoutID string) (*dao.Payout, error) {
+	ret := _m.Called(payoutID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayout")
+	}
+
+	var r0 *dao.Payout
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.Payout, error)); ok {
+		return rf(payoutID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.Payout); ok {
+		r0 = rf(payoutID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Payout)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(payoutID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPendingOperation provides a mock function with given fields: operationID
+func (_m *WalletDaoInterface) GetPendingOperation(operationID string) (*dao.PendingOperation, error) {
+	ret := _m.Called(operationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingOperation")
+	}
+
+	var r0 *dao.PendingOperation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.PendingOperation, error)); ok {
+		return rf(operationID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.PendingOperation); ok {
+		r0 = rf(operationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.PendingOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(operationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProviderOperation provides a mock function with given fields: opID
+func (_m *WalletDaoInterface) GetProviderOperation(opID string) (*dao.ProviderOperation, error) {
+	ret := _m.Called(opID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProviderOperation")
+	}
+
+	var r0 *dao.ProviderOperation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.ProviderOperation, error)); ok {
+		return rf(opID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.ProviderOperation); ok {
+		r0 = rf(opID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.ProviderOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(opID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSchedule provides a mock function with given fields: scheduleID
+func (_m *WalletDaoInterface) GetSchedule(scheduleID string) (*dao.Schedule, error) {
+	ret := _m.Called(scheduleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSchedule")
+	}
+
+	var r0 *dao.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.Schedule, error)); ok {
+		return rf(scheduleID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.Schedule); ok {
+		r0 = rf(scheduleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(scheduleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatement provides a mock function with given fields: statementID
+func (_m *WalletDaoInterface) GetStatement(statementID string) (*dao.Statement, error) {
+	ret := _m.Called(statementID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatement")
+	}
+
+	var r0 *dao.Statement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.Statement, error)); ok {
+		return rf(statementID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.Statement); ok {
+		r0 = rf(statementID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Statement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(statementID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatementForPeriod provides a mock function with given fields: walletID, periodStart
+func (_m *WalletDaoInterface) GetStatementForPeriod(walletID string, periodStart time.Time) (*dao.Statement, error) {
+	ret := _m.Called(walletID, periodStart)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatementForPeriod")
+	}
+
+	var r0 *dao.Statement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) (*dao.Statement, error)); ok {
+		return rf(walletID, periodStart)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time) *dao.Statement); ok {
+		r0 = rf(walletID, periodStart)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Statement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time) error); ok {
+		r1 = rf(walletID, periodStart)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransactionHistory provides a mock function with given fields: filter
+func (_m *WalletDaoInterface) GetTransactionHistory(filter dao.TransactionFilter) ([]dao.Transaction, error) {
+	ret := _m.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionHistory")
+	}
+
+	var r0 []dao.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(dao.TransactionFilter) ([]dao.Transaction, error)); ok {
+		return rf(filter)
+	}
+	if rf, ok := ret.Get(0).(func(dao.TransactionFilter) []dao.Transaction); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(dao.TransactionFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransactionReview provides a mock function with given fields: reviewID
+func (_m *WalletDaoInterface) GetTransactionReview(reviewID string) (*dao.TransactionReview, error) {
+	ret := _m.Called(reviewID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionReview")
+	}
+
+	var r0 *dao.TransactionReview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.TransactionReview, error)); ok {
+		return rf(reviewID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.TransactionReview); ok {
+		r0 = rf(reviewID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.TransactionReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(reviewID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransferBatch provides a mock function with given fields: batchID
+func (_m *WalletDaoInterface) GetTransferBatch(batchID string) (*dao.TransferBatch, error) {
+	ret := _m.Called(batchID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransferBatch")
+	}
+
+	var r0 *dao.TransferBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.TransferBatch, error)); ok {
+		return rf(batchID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.TransferBatch); ok {
+		r0 = rf(batchID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.TransferBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(batchID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransferBatchByKey provides a mock function with given fields: idempotencyKey
+func (_m *WalletDaoInterface) GetTransferBatchByKey(idempotencyKey string) (*dao.TransferBatch, error) {
+	ret := _m.Called(idempotencyKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransferBatchByKey")
+	}
+
+	var r0 *dao.TransferBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.TransferBatch, error)); ok {
+		return rf(idempotencyKey)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.TransferBatch); ok {
+		r0 = rf(idempotencyKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.TransferBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(idempotencyKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByID provides a mock function with given fields: userID
+func (_m *WalletDaoInterface) GetUserByID(userID string) (*dao.User, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 *dao.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.User, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.User); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByWalletID provides a mock function with given fields: walletID
+func (_m *WalletDaoInterface) GetUserByWalletID(walletID string) (*dao.User, error) {
+	ret := _m.Called(walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByWalletID")
+	}
+
+	var r0 *dao.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.User, error)); ok {
+		return rf(walletID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.User); ok {
+		r0 = rf(walletID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWalletByID provides a mock function with given fields: walletID
+func (_m *WalletDaoInterface) GetWalletByID(walletID string) (*dao.Wallet, error) {
+	ret := _m.Called(walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWalletByID")
+	}
+
+	var r0 *dao.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*dao.Wallet, error)); ok {
+		return rf(walletID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *dao.Wallet); ok {
+		r0 = rf(walletID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWalletMember provides a mock function with given fields: walletID, userID
+func (_m *WalletDaoInterface) GetWalletMember(walletID string, userID string) (*dao.WalletMember, error) {
+	ret := _m.Called(walletID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWalletMember")
+	}
+
+	var r0 *dao.WalletMember
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*dao.WalletMember, error)); ok {
+		return rf(walletID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *dao.WalletMember); ok {
+		r0 = rf(walletID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.WalletMember)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(walletID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAdjustments provides a mock function with given fields: walletID
+func (_m *WalletDaoInterface) ListAdjustments(walletID string) ([]dao.Adjustment, error) {
+	ret := _m.Called(walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAdjustments")
+	}
+
+	var r0 []dao.Adjustment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]dao.Adjustment, error)); ok {
+		return rf(walletID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []dao.Adjustment); ok {
+		r0 = rf(walletID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.Adjustment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAuditLog provides a mock function with given fields: afterSeq, limit
+func (_m *WalletDaoInterface) ListAuditLog(afterSeq int64, limit int) ([]dao.AuditLog, error) {
+	ret := _m.Called(afterSeq, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAuditLog")
+	}
+
+	var r0 []dao.AuditLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int) ([]dao.AuditLog, error)); ok {
+		return rf(afterSeq, limit)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int) []dao.AuditLog); ok {
+		r0 = rf(afterSeq, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.AuditLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int) error); ok {
+		r1 = rf(afterSeq, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDueSchedules provides a mock function with given fields: now, limit
+func (_m *WalletDaoInterface) ListDueSchedules(now time.Time, limit int) ([]dao.Schedule, error) {
+	ret := _m.Called(now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDueSchedules")
+	}
+
+	var r0 []dao.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, int) ([]dao.Schedule, error)); ok {
+		return rf(now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, int) []dao.Schedule); ok {
+		r0 = rf(now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, int) error); ok {
+		r1 = rf(now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListExpiredEscrows provides a mock function with given fields: now, limit
+func (_m *WalletDaoInterface) ListExpiredEscrows(now time.Time, limit int) ([]dao.Escrow, error) {
+	ret := _m.Called(now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListExpiredEscrows")
+	}
+
+	var r0 []dao.Escrow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, int) ([]dao.Escrow, error)); ok {
+		return rf(now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, int) []dao.Escrow); ok {
+		r0 = rf(now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.Escrow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, int) error); ok {
+		r1 = rf(now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListExpiredPaymentRequests provides a mock function with given fields: now, limit
+func (_m *WalletDaoInterface) ListExpiredPaymentRequests(now time.Time, limit int) ([]dao.PaymentRequest, error) {
+	ret := _m.Called(now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListExpiredPaymentRequests")
+	}
+
+	var r0 []dao.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, int) ([]dao.PaymentRequest, error)); ok {
+		return rf(now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, int) []dao.PaymentRequest); ok {
+		r0 = rf(now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, int) error); ok {
+		r1 = rf(now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListInterestAccruals provides a mock function with given fields: walletID, from, to
+func (_m *WalletDaoInterface) ListInterestAccruals(walletID string, from time.Time, to time.Time) ([]dao.InterestAccrual, error) {
+	ret := _m.Called(walletID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListInterestAccruals")
+	}
+
+	var r0 []dao.InterestAccrual
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) ([]dao.InterestAccrual, error)); ok {
+		return rf(walletID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) []dao.InterestAccrual); ok {
+		r0 = rf(walletID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.InterestAccrual)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time) error); ok {
+		r1 = rf(walletID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListInterestWallets provides a mock function with no fields
+func (_m *WalletDaoInterface) ListInterestWallets() ([]dao.Wallet, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListInterestWallets")
+	}
+
+	var r0 []dao.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]dao.Wallet, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []dao.Wallet); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListLedgerDiscrepancies provides a mock function with no fields
+func (_m *WalletDaoInterface) ListLedgerDiscrepancies() ([]dao.LedgerBalance, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLedgerDiscrepancies")
+	}
+
+	var r0 []dao.LedgerBalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]dao.LedgerBalance, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []dao.LedgerBalance); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.LedgerBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPaymentRequests provides a mock function with given fields: walletID, incoming, status, limit
+func (_m *WalletDaoInterface) ListPaymentRequests(walletID string, incoming bool, status string, limit int) ([]dao.PaymentRequest, error) {
+	ret := _m.Called(walletID, incoming, status, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPaymentRequests")
+	}
+
+	var r0 []dao.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, bool, string, int) ([]dao.PaymentRequest, error)); ok {
+		return rf(walletID, incoming, status, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, bool, string, int) []dao.PaymentRequest); ok {
+		r0 = rf(walletID, incoming, status, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, bool, string, int) error); ok {
+		r1 = rf(walletID, incoming, status, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPayouts provides a mock function with given fields: status, limit
+func (_m *WalletDaoInterface) ListPayouts(status string, limit int) ([]dao.Payout, error) {
+	ret := _m.Called(status, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPayouts")
+	}
+
+	var r0 []dao.Payout
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) ([]dao.Payout, error)); ok {
+		return rf(status, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) []dao.Payout); ok {
+		r0 = rf(status, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.Payout)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(status, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPendingOperations provides a mock function with given fields: status
+func (_m *WalletDaoInterface) ListPendingOperations(status string) ([]dao.PendingOperation, error) {
+	ret := _m.Called(status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPendingOperations")
+	}
+
+	var r0 []dao.PendingOperation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]dao.PendingOperation, error)); ok {
+		return rf(status)
+	}
+	if rf, ok := ret.Get(0).(func(string) []dao.PendingOperation); ok {
+		r0 = rf(status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.PendingOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPendingTransferBatches provides a mock function with given fields: limit
+func (_m *WalletDaoInterface) ListPendingTransferBatches(limit int) ([]dao.TransferBatch, error) {
+	ret := _m.Called(limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPendingTransferBatches")
+	}
+
+	var r0 []dao.TransferBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int) ([]dao.TransferBatch, error)); ok {
+		return rf(limit)
+	}
+	if rf, ok := ret.Get(0).(func(int) []dao.TransferBatch); ok {
+		r0 = rf(limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.TransferBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPockets provides a mock function with given fields: parentWalletID
+func (_m *WalletDaoInterface) ListPockets(parentWalletID string) ([]dao.Wallet, error) {
+	ret := _m.Called(parentWalletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPockets")
+	}
+
+	var r0 []dao.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]dao.Wallet, error)); ok {
+		return rf(parentWalletID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []dao.Wallet); ok {
+		r0 = rf(parentWalletID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(parentWalletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListProviderOperations provides a mock function with given fields: kind, statuses, limit
+func (_m *WalletDaoInterface) ListProviderOperations(kind string, statuses []string, limit int) ([]dao.ProviderOperation, error) {
+	ret := _m.Called(kind, statuses, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListProviderOperations")
+	}
+
+	var r0 []dao.ProviderOperation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, []string, int) ([]dao.ProviderOperation, error)); ok {
+		return rf(kind, statuses, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, []string, int) []dao.ProviderOperation); ok {
+		r0 = rf(kind, statuses, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.ProviderOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []string, int) error); ok {
+		r1 = rf(kind, statuses, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListScheduleRuns provides a mock function with given fields: scheduleID, limit
+func (_m *WalletDaoInterface) ListScheduleRuns(scheduleID string, limit int) ([]dao.ScheduleRun, error) {
+	ret := _m.Called(scheduleID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListScheduleRuns")
+	}
+
+	var r0 []dao.ScheduleRun
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) ([]dao.ScheduleRun, error)); ok {
+		return rf(scheduleID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) []dao.ScheduleRun); ok {
+		r0 = rf(scheduleID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.ScheduleRun)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(scheduleID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSchedules provides a mock function with given fields: walletID
+func (_m *WalletDaoInterface) ListSchedules(walletID string) ([]dao.Schedule, error) {
+	ret := _m.Called(walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSchedules")
+	}
+
+	var r0 []dao.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]dao.Schedule, error)); ok {
+		return rf(walletID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []dao.Schedule); ok {
+		r0 = rf(walletID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListStalledTransferBatches provides a mock function with given fields: before, limit
+func (_m *WalletDaoInterface) ListStalledTransferBatches(before time.Time, limit int) ([]dao.TransferBatch, error) {
+	ret := _m.Called(before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListStalledTransferBatches")
+	}
+
+	var r0 []dao.TransferBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, int) ([]dao.TransferBatch, error)); ok {
+		return rf(before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, int) []dao.TransferBatch); ok {
+		r0 = rf(before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.TransferBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, int) error); ok {
+		r1 = rf(before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListStatements provides a mock function with given fields: walletID
+func (_m *WalletDaoInterface) ListStatements(walletID string) ([]dao.Statement, error) {
+	ret := _m.Called(walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListStatements")
+	}
+
+	var r0 []dao.Statement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]dao.Statement, error)); ok {
+		return rf(walletID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []dao.Statement); ok {
+		r0 = rf(walletID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.Statement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTransactionReviews provides a mock function with given fields: status
+func (_m *WalletDaoInterface) ListTransactionReviews(status string) ([]dao.TransactionReview, error) {
+	ret := _m.Called(status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTransactionReviews")
+	}
+
+	var r0 []dao.TransactionReview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]dao.TransactionReview, error)); ok {
+		return rf(status)
+	}
+	if rf, ok := ret.Get(0).(func(string) []dao.TransactionReview); ok {
+		r0 = rf(status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.TransactionReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTransferBatchItems provides a mock function with given fields: batchID
+func (_m *WalletDaoInterface) ListTransferBatchItems(batchID string) ([]dao.TransferBatchItem, error) {
+	ret := _m.Called(batchID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTransferBatchItems")
+	}
+
+	var r0 []dao.TransferBatchItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]dao.TransferBatchItem, error)); ok {
+		return rf(batchID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []dao.TransferBatchItem); ok {
+		r0 = rf(batchID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.TransferBatchItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(batchID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUnmatchedDeposits provides a mock function with given fields: from, to
+func (_m *WalletDaoInterface) ListUnmatchedDeposits(from time.Time, to time.Time) ([]dao.Transaction, error) {
+	ret := _m.Called(from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUnmatchedDeposits")
+	}
+
+	var r0 []dao.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) ([]dao.Transaction, error)); ok {
+		return rf(from, to)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) []dao.Transaction); ok {
+		r0 = rf(from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time) error); ok {
+		r1 = rf(from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUnpostedInterestAccruals provides a mock function with given fields: walletID, before
+func (_m *WalletDaoInterface) ListUnpostedInterestAccruals(walletID string, before time.Time) ([]dao.InterestAccrual, error) {
+	ret := _m.Called(walletID, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUnpostedInterestAccruals")
+	}
+
+	var r0 []dao.InterestAccrual
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) ([]dao.InterestAccrual, error)); ok {
+		return rf(walletID, before)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time) []dao.InterestAccrual); ok {
+		r0 = rf(walletID, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.InterestAccrual)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time) error); ok {
+		r1 = rf(walletID, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWalletMembers provides a mock function with given fields: walletID
+func (_m *WalletDaoInterface) ListWalletMembers(walletID string) ([]dao.WalletMember, error) {
+	ret := _m.Called(walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWalletMembers")
+	}
+
+	var r0 []dao.WalletMember
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]dao.WalletMember, error)); ok {
+		return rf(walletID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []dao.WalletMember); ok {
+		r0 = rf(walletID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.WalletMember)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWalletsWithoutStatement provides a mock function with given fields: periodStart, periodEnd
+func (_m *WalletDaoInterface) ListWalletsWithoutStatement(periodStart time.Time, periodEnd time.Time) ([]string, error) {
+	ret := _m.Called(periodStart, periodEnd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWalletsWithoutStatement")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) ([]string, error)); ok {
+		return rf(periodStart, periodEnd)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time) []string); ok {
+		r0 = rf(periodStart, periodEnd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time) error); ok {
+		r1 = rf(periodStart, periodEnd)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkPayoutsSent provides a mock function with given fields: batch, payoutIDs
+func (_m *WalletDaoInterface) MarkPayoutsSent(batch *dao.PayoutBatch, payoutIDs []string) error {
+	ret := _m.Called(batch, payoutIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkPayoutsSent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.PayoutBatch, []string) error); ok {
+		r0 = rf(batch, payoutIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ParkTransferBatchItem provides a mock function with given fields: itemID, reviewID
+func (_m *WalletDaoInterface) ParkTransferBatchItem(itemID string, reviewID string) error {
+	ret := _m.Called(itemID, reviewID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParkTransferBatchItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(itemID, reviewID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PostInterest provides a mock function with given fields: walletID, from, to, postedAt, credit
+func (_m *WalletDaoInterface) PostInterest(walletID string, from time.Time, to time.Time, postedAt time.Time, credit *dao.Transaction) error {
+	ret := _m.Called(walletID, from, to, postedAt, credit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PostInterest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time, time.Time, *dao.Transaction) error); ok {
+		r0 = rf(walletID, from, to, postedAt, credit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PostReviewedTransfer provides a mock function with given fields: reviewID, legs
+func (_m *WalletDaoInterface) PostReviewedTransfer(reviewID string, legs []dao.TransferLeg) error {
+	ret := _m.Called(reviewID, legs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PostReviewedTransfer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []dao.TransferLeg) error); ok {
+		r0 = rf(reviewID, legs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PostTransferBatchItem provides a mock function with given fields: itemID, legs
+func (_m *WalletDaoInterface) PostTransferBatchItem(itemID string, legs []dao.TransferLeg) error {
+	ret := _m.Called(itemID, legs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PostTransferBatchItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []dao.TransferLeg) error); ok {
+		r0 = rf(itemID, legs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PostTransfers provides a mock function with given fields: legs
+func (_m *WalletDaoInterface) PostTransfers(legs []dao.TransferLeg) error {
+	ret := _m.Called(legs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PostTransfers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]dao.TransferLeg) error); ok {
+		r0 = rf(legs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PostWithdrawal provides a mock function with given fields: w
+func (_m *WalletDaoInterface) PostWithdrawal(w dao.Withdrawal) error {
+	ret := _m.Called(w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PostWithdrawal")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(dao.Withdrawal) error); ok {
+		r0 = rf(w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReclaimTransferBatch provides a mock function with given fields: batchID, before
+func (_m *WalletDaoInterface) ReclaimTransferBatch(batchID string, before time.Time) error {
+	ret := _m.Called(batchID, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReclaimTransferBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(batchID, before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordLedgerCorrection provides a mock function with given fields: tx, adjustment
+func (_m *WalletDaoInterface) RecordLedgerCorrection(tx *dao.Transaction, adjustment *dao.Adjustment) error {
+	ret := _m.Called(tx, adjustment)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordLedgerCorrection")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.Transaction, *dao.Adjustment) error); ok {
+		r0 = rf(tx, adjustment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReleasePaymentLink provides a mock function with given fields: linkID, at
+func (_m *WalletDaoInterface) ReleasePaymentLink(linkID string, at time.Time) error {
+	ret := _m.Called(linkID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleasePaymentLink")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(linkID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveWalletMember provides a mock function with given fields: walletID, userID
+func (_m *WalletDaoInterface) RemoveWalletMember(walletID string, userID string) error {
+	ret := _m.Called(walletID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveWalletMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(walletID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReturnPayout provides a mock function with given fields: payoutID, fromStatus, reason, reversal
+func (_m *WalletDaoInterface) ReturnPayout(payoutID string, fromStatus string, reason string, reversal *dao.Transaction) error {
+	ret := _m.Called(payoutID, fromStatus, reason, reversal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReturnPayout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, *dao.Transaction) error); ok {
+		r0 = rf(payoutID, fromStatus, reason, reversal)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReverseProviderOperation provides a mock function with given fields: opID, fromStatus, reason, reversal
+func (_m *WalletDaoInterface) ReverseProviderOperation(opID string, fromStatus string, reason string, reversal *dao.Transaction) error {
+	ret := _m.Called(opID, fromStatus, reason, reversal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReverseProviderOperation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, *dao.Transaction) error); ok {
+		r0 = rf(opID, fromStatus, reason, reversal)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveIdempotencyKey provides a mock function with given fields: record
+func (_m *WalletDaoInterface) SaveIdempotencyKey(record *dao.IdempotencyRecord) error {
+	ret := _m.Called(record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveIdempotencyKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.IdempotencyRecord) error); ok {
+		r0 = rf(record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveInvitation provides a mock function with given fields: member
+func (_m *WalletDaoInterface) SaveInvitation(member *dao.WalletMember) error {
+	ret := _m.Called(member)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveInvitation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.WalletMember) error); ok {
+		r0 = rf(member)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveUserScreening provides a mock function with given fields: screening
+func (_m *WalletDaoInterface) SaveUserScreening(screening *dao.UserScreening) error {
+	ret := _m.Called(screening)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveUserScreening")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.UserScreening) error); ok {
+		r0 = rf(screening)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetCreditLimit provides a mock function with given fields: walletID, limit
+func (_m *WalletDaoInterface) SetCreditLimit(walletID string, limit float64) error {
+	ret := _m.Called(walletID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetCreditLimit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, float64) error); ok {
+		r0 = rf(walletID, limit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPaymentLinkTransaction provides a mock function with given fields: linkID, transactionID
+func (_m *WalletDaoInterface) SetPaymentLinkTransaction(linkID string, transactionID string) error {
+	ret := _m.Called(linkID, transactionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPaymentLinkTransaction")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(linkID, transactionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTransactionReviewRequest provides a mock function with given fields: reviewID, idempotencyKey, method, path
+func (_m *WalletDaoInterface) SetTransactionReviewRequest(reviewID string, idempotencyKey string, method string, path string) error {
+	ret := _m.Called(reviewID, idempotencyKey, method, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTransactionReviewRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
+		r0 = rf(reviewID, idempotencyKey, method, path)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SettleEscrow provides a mock function with given fields: escrow, fromStatuses, legs
+func (_m *WalletDaoInterface) SettleEscrow(escrow *dao.Escrow, fromStatuses []string, legs []dao.TransferLeg) error {
+	ret := _m.Called(escrow, fromStatuses, legs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SettleEscrow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.Escrow, []string, []dao.TransferLeg) error); ok {
+		r0 = rf(escrow, fromStatuses, legs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StreamTransactionHistory provides a mock function with given fields: filter, fn
+func (_m *WalletDaoInterface) StreamTransactionHistory(filter dao.TransactionFilter, fn func(*dao.Transaction) error) error {
+	ret := _m.Called(filter, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamTransactionHistory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(dao.TransactionFilter, func(*dao.Transaction) error) error); ok {
+		r0 = rf(filter, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SumMemberSpending provides a mock function with given fields: walletID, userID, since
+func (_m *WalletDaoInterface) SumMemberSpending(walletID string, userID string, since time.Time) (float64, error) {
+	ret := _m.Called(walletID, userID, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SumMemberSpending")
+	}
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) (float64, error)); ok {
+		return rf(walletID, userID, since)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, time.Time) float64); ok {
+		r0 = rf(walletID, userID, since)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, time.Time) error); ok {
+		r1 = rf(walletID, userID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateBalance provides a mock function with given fields: input
+func (_m *WalletDaoInterface) UpdateBalance(input *dao.UpdateBalance) error {
+	ret := _m.Called(input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBalance")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.UpdateBalance) error); ok {
+		r0 = rf(input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateIdempotencyKey provides a mock function with given fields: record
+func (_m *WalletDaoInterface) UpdateIdempotencyKey(record *dao.IdempotencyRecord) error {
+	ret := _m.Called(record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateIdempotencyKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.IdempotencyRecord) error); ok {
+		r0 = rf(record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePaymentRequest provides a mock function with given fields: req, fromStatus
+func (_m *WalletDaoInterface) UpdatePaymentRequest(req *dao.PaymentRequest, fromStatus string) error {
+	ret := _m.Called(req, fromStatus)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePaymentRequest")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.PaymentRequest, string) error); ok {
+		r0 = rf(req, fromStatus)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePayoutStatus provides a mock function with given fields: payoutID, fromStatus, toStatus
+func (_m *WalletDaoInterface) UpdatePayoutStatus(payoutID string, fromStatus string, toStatus string) error {
+	ret := _m.Called(payoutID, fromStatus, toStatus)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePayoutStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(payoutID, fromStatus, toStatus)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePendingOperationStatus provides a mock function with given fields: operationID, fromStatus, toStatus, decidedBy, result
+func (_m *WalletDaoInterface) UpdatePendingOperationStatus(operationID string, fromStatus string, toStatus string, decidedBy string, result *string) error {
+	ret := _m.Called(operationID, fromStatus, toStatus, decidedBy, result)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePendingOperationStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string, *string) error); ok {
+		r0 = rf(operationID, fromStatus, toStatus, decidedBy, result)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePocketGoal provides a mock function with given fields: pocketID, amount, date
+func (_m *WalletDaoInterface) UpdatePocketGoal(pocketID string, amount *float64, date *time.Time) error {
+	ret := _m.Called(pocketID, amount, date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePocketGoal")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *float64, *time.Time) error); ok {
+		r0 = rf(pocketID, amount, date)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProviderOperation provides a mock function with given fields: opID, fromStatus, update
+func (_m *WalletDaoInterface) UpdateProviderOperation(opID string, fromStatus string, update dao.ProviderOperationUpdate) error {
+	ret := _m.Called(opID, fromStatus, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProviderOperation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, dao.ProviderOperationUpdate) error); ok {
+		r0 = rf(opID, fromStatus, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateScheduleStatus provides a mock function with given fields: scheduleID, fromStatuses, toStatus, nextRunAt
+func (_m *WalletDaoInterface) UpdateScheduleStatus(scheduleID string, fromStatuses []string, toStatus string, nextRunAt *time.Time) error {
+	ret := _m.Called(scheduleID, fromStatuses, toStatus, nextRunAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateScheduleStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, []string, string, *time.Time) error); ok {
+		r0 = rf(scheduleID, fromStatuses, toStatus, nextRunAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTransactionReviewStatus provides a mock function with given fields: reviewID, fromStatus, toStatus, reviewedBy
+func (_m *WalletDaoInterface) UpdateTransactionReviewStatus(reviewID string, fromStatus string, toStatus string, reviewedBy string) error {
+	ret := _m.Called(reviewID, fromStatus, toStatus, reviewedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTransactionReviewStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
+		r0 = rf(reviewID, fromStatus, toStatus, reviewedBy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTransferBatchItem provides a mock function with given fields: itemID, status, itemErr, transactionID
+func (_m *WalletDaoInterface) UpdateTransferBatchItem(itemID string, status string, itemErr *string, transactionID *string) error {
+	ret := _m.Called(itemID, status, itemErr, transactionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTransferBatchItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, *string, *string) error); ok {
+		r0 = rf(itemID, status, itemErr, transactionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpgradeKycLevel provides a mock function with given fields: upgrade
+func (_m *WalletDaoInterface) UpgradeKycLevel(upgrade *dao.KycUpgrade) error {
+	ret := _m.Called(upgrade)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpgradeKycLevel")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dao.KycUpgrade) error); ok {
+		r0 = rf(upgrade)
 	} else {
 		r0 = ret.Error(0)
 	}
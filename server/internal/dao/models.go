@@ -1,19 +1,46 @@
 package dao
 
-import "time"
+import (
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/fees"
+)
 
 type User struct {
 	ID        string    `json:"id"`
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
+	KycLevel  string    `json:"kyc_level"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type KycUpgrade struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id"`
+	FromLevel    string    `json:"from_level"`
+	ToLevel      string    `json:"to_level"`
+	EvidenceRefs string    `json:"evidence_refs"`
+	ApprovedBy   string    `json:"approved_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
 type Wallet struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Balance   float64   `json:"balance"`
-	CreatedAt time.Time `json:"created_at"`
+	ID      string  `json:"id"`
+	UserID  string  `json:"user_id"`
+	Balance float64 `json:"balance"`
+	Status  string  `json:"status"`
+	Type    string  `json:"type"`
+	// InterestRate is the annual rate of a savings wallet; nil uses the configured default.
+	InterestRate *float64 `json:"interest_rate,omitempty"`
+	// CreditLimit is how far below zero the balance may go.
+	CreditLimit float64 `json:"credit_limit"`
+	// ParentWalletID, Name and the goal are set on pockets only.
+	ParentWalletID *string    `json:"parent_wallet_id,omitempty"`
+	Name           *string    `json:"name,omitempty"`
+	GoalAmount     *float64   `json:"goal_amount,omitempty"`
+	GoalDate       *time.Time `json:"goal_date,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
 }
 
 type UpdateBalance struct {
@@ -22,12 +49,30 @@ type UpdateBalance struct {
 }
 
 type Transaction struct {
-	ID            string    `json:"id"`
-	WalletID      string    `json:"wallet_id"`
-	Type          string    `json:"type"`
-	Amount        float64   `json:"amount"`
-	RelatedUserID *string   `json:"related_user_id"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID            string  `json:"id"`
+	WalletID      string  `json:"wallet_id"`
+	Type          string  `json:"type"`
+	Amount        float64 `json:"amount"`
+	RelatedUserID *string `json:"related_user_id"`
+	// BalanceAfter is the wallet balance right after this transaction. Rows written before
+	// running balances were tracked have none.
+	BalanceAfter *float64 `json:"balance_after"`
+	// ParentTransactionID links a fee to the transaction it was charged for.
+	ParentTransactionID *string `json:"parent_transaction_id,omitempty"`
+	// InitiatedBy is the member who moved the money out of a shared wallet.
+	InitiatedBy *string `json:"initiated_by,omitempty"`
+	// GroupID is shared by every row written for one payment with many legs, such as a split payment.
+	GroupID   *string   `json:"group_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SignedAmount is the transaction's effect on its wallet's balance; withdrawals are stored as
+// positive amounts.
+func (t *Transaction) SignedAmount() float64 {
+	if t.Type == common.TransactionTypeWithdraw {
+		return -t.Amount
+	}
+	return t.Amount
 }
 
 type IdempotencyRecord struct {
@@ -38,3 +83,400 @@ type IdempotencyRecord struct {
 	StatusCode int       `gorm:"column:status_code"`
 	CreatedAt  time.Time `gorm:"column:created_at"`
 }
+
+type UserScreening struct {
+	UserID       string    `json:"user_id" gorm:"primaryKey"`
+	Status       string    `json:"status"`
+	ListName     *string   `json:"list_name"`
+	MatchedName  *string   `json:"matched_name"`
+	MatchedEmail *string   `json:"matched_email"`
+	Score        float64   `json:"score"`
+	ScreenedAt   time.Time `json:"screened_at"`
+}
+
+type TransactionReview struct {
+	ID                   string  `json:"id"`
+	Type                 string  `json:"type"`
+	WalletID             string  `json:"wallet_id"`
+	CounterpartyWalletID *string `json:"counterparty_wallet_id"`
+	Amount               float64 `json:"amount"`
+	RuleHits             string  `json:"rule_hits"`
+	// Beneficiary is the bank account of a parked bank withdrawal, as JSON.
+	Beneficiary *string `json:"beneficiary,omitempty"`
+	// Provider is the payment provider and destination of a parked provider withdrawal, as JSON.
+	Provider       *string    `json:"provider,omitempty"`
+	Status         string     `json:"status"`
+	ReviewedBy     *string    `json:"reviewed_by"`
+	ReviewedAt     *time.Time `json:"reviewed_at"`
+	IdempotencyKey string     `json:"-"`
+	RequestMethod  string     `json:"-"`
+	RequestPath    string     `json:"-"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type PendingOperation struct {
+	ID             string     `json:"id"`
+	Type           string     `json:"type"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status"`
+	InitiatedBy    string     `json:"initiated_by"`
+	DecidedBy      *string    `json:"decided_by"`
+	DecidedAt      *time.Time `json:"decided_at"`
+	Result         *string    `json:"result"`
+	RiskHits       *string    `json:"risk_hits"`
+	IdempotencyKey string     `json:"-"`
+	RequestMethod  string     `json:"-"`
+	RequestPath    string     `json:"-"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	// Fee is what an approved transfer was charged; it is not stored.
+	Fee *fees.Quote `json:"-" gorm:"-"`
+}
+
+type Adjustment struct {
+	ID            string    `json:"id"`
+	TransactionID string    `json:"transaction_id"`
+	WalletID      string    `json:"wallet_id"`
+	Amount        float64   `json:"amount"`
+	ReasonCode    string    `json:"reason_code"`
+	Note          string    `json:"note"`
+	RequestedBy   string    `json:"requested_by"`
+	ApprovedBy    string    `json:"approved_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// AuditLog is one row of the append-only, hash-chained audit trail.
+type AuditLog struct {
+	Seq        int64     `json:"seq" gorm:"primaryKey;autoIncrement:false"`
+	Actor      string    `json:"actor"`
+	IP         string    `json:"ip"`
+	RequestID  string    `json:"request_id"`
+	Action     string    `json:"action"`
+	EntityType string    `json:"entity_type"`
+	EntityID   string    `json:"entity_id"`
+	Details    string    `json:"details"`
+	CreatedAt  time.Time `json:"created_at"`
+	PrevHash   string    `json:"prev_hash"`
+	Hash       string    `json:"hash"`
+}
+
+// LedgerBalance compares a wallet's stored balance with the balance implied by its transactions.
+type LedgerBalance struct {
+	WalletID         string  `json:"wallet_id"`
+	Balance          float64 `json:"balance"`
+	LedgerBalance    float64 `json:"ledger_balance"`
+	TransactionCount int64   `json:"transaction_count"`
+}
+
+// BalanceSnapshot is a wallet's ledger balance at a point in time, used to bound as-of scans.
+type BalanceSnapshot struct {
+	WalletID  string    `json:"wallet_id"`
+	AsOf      time.Time `json:"as_of"`
+	Balance   float64   `json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TransactionCursor is the keyset position of a transaction in history order.
+type TransactionCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+// TransactionFilter selects a page of a wallet's transaction history. Nil and empty fields
+// do not filter. Amount bounds apply to the absolute amount.
+type TransactionFilter struct {
+	WalletID     string
+	Type         string
+	Start        *time.Time
+	End          *time.Time
+	MinAmount    *float64
+	MaxAmount    *float64
+	Counterparty string
+	Ascending    bool
+	After        *TransactionCursor
+	Limit        int
+}
+
+// Statement is a generated account statement. Document holds the rendered statement as JSON so
+// re-downloads return exactly what was generated.
+type Statement struct {
+	ID               string    `json:"id"`
+	WalletID         string    `json:"wallet_id"`
+	PeriodStart      time.Time `json:"period_start"`
+	PeriodEnd        time.Time `json:"period_end"`
+	OpeningBalance   float64   `json:"opening_balance"`
+	ClosingBalance   float64   `json:"closing_balance"`
+	TransactionCount int       `json:"transaction_count"`
+	Document         string    `json:"-"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// BankImport is an imported bank statement file and the outcome of reconciling it. ReportJSON
+// holds the full reconciliation report.
+type BankImport struct {
+	ID             string    `json:"id"`
+	Format         string    `json:"format"`
+	StatementID    string    `json:"statement_id"`
+	Account        string    `json:"account"`
+	FileHash       string    `json:"file_hash"`
+	LineCount      int       `json:"line_count"`
+	MatchedCount   int       `json:"matched_count"`
+	UnmatchedCount int       `json:"unmatched_count"`
+	AmbiguousCount int       `json:"ambiguous_count"`
+	ReportJSON     string    `json:"-" gorm:"column:report"`
+	ImportedBy     string    `json:"imported_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// BankMatch ties a deposit transaction to the bank line that backs it. A deposit is matched at most once.
+type BankMatch struct {
+	TransactionID string    `json:"transaction_id" gorm:"primaryKey"`
+	ImportID      string    `json:"import_id"`
+	BankReference string    `json:"bank_reference"`
+	Amount        float64   `json:"amount"`
+	BookingDate   time.Time `json:"booking_date"`
+	Method        string    `json:"method"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Payout is a withdrawal paid out to a bank account. It waits in pending_payout until the payout
+// job writes it to a pain.001 batch file, and a return credits the amount back to the wallet.
+type Payout struct {
+	ID                    string    `json:"id"`
+	TransactionID         string    `json:"transaction_id"`
+	WalletID              string    `json:"wallet_id"`
+	Amount                float64   `json:"amount"`
+	Currency              string    `json:"currency"`
+	BeneficiaryName       string    `json:"beneficiary_name"`
+	BeneficiaryIBAN       string    `json:"beneficiary_iban"`
+	BeneficiaryBIC        string    `json:"beneficiary_bic"`
+	Status                string    `json:"status"`
+	BatchID               *string   `json:"batch_id"`
+	ReturnReason          *string   `json:"return_reason"`
+	ReversalTransactionID *string   `json:"reversal_transaction_id"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// PayoutBatch is a pain.001 file written by the payout job.
+type PayoutBatch struct {
+	ID          string    `json:"id"`
+	MessageID   string    `json:"message_id"`
+	FileName    string    `json:"file_name"`
+	PayoutCount int       `json:"payout_count"`
+	TotalAmount float64   `json:"total_amount"`
+	Currency    string    `json:"currency"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ProviderOperation tracks money moving through an external payment provider. A payout debits the
+// wallet when initiated and is reversed if the provider fails it; a payin credits the wallet once
+// the provider reports the money received.
+type ProviderOperation struct {
+	ID                    string    `json:"id"`
+	Provider              string    `json:"provider"`
+	Kind                  string    `json:"kind"`
+	WalletID              string    `json:"wallet_id"`
+	Amount                float64   `json:"amount"`
+	Destination           string    `json:"destination,omitempty"`
+	ProviderReference     *string   `json:"provider_reference"`
+	Status                string    `json:"status"`
+	TransactionID         *string   `json:"transaction_id"`
+	ReversalTransactionID *string   `json:"reversal_transaction_id"`
+	FailureReason         *string   `json:"failure_reason"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// ProviderOperationUpdate lists the fields to set when a provider operation changes status.
+// Empty fields are left unchanged.
+type ProviderOperationUpdate struct {
+	Status            string
+	ProviderReference string
+	TransactionID     string
+	FailureReason     string
+}
+
+// Schedule is a recurring transfer. NextRunAt is the next occurrence to execute and is nil once
+// the schedule has no occurrences left.
+type Schedule struct {
+	ID           string     `json:"id"`
+	FromWalletID string     `json:"from_wallet_id"`
+	ToWalletID   string     `json:"to_wallet_id"`
+	Amount       float64    `json:"amount"`
+	Recurrence   string     `json:"recurrence"`
+	StartAt      time.Time  `json:"start_at"`
+	EndAt        *time.Time `json:"end_at"`
+	NextRunAt    *time.Time `json:"next_run_at"`
+	LastRunAt    *time.Time `json:"last_run_at"`
+	FailureCount int        `json:"failure_count"`
+	Status       string     `json:"status"`
+	CreatedBy    string     `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ScheduleRun is one executed occurrence of a schedule.
+type ScheduleRun struct {
+	ID             string    `json:"id"`
+	ScheduleID     string    `json:"schedule_id"`
+	OccurrenceAt   time.Time `json:"occurrence_at"`
+	IdempotencyKey string    `json:"idempotency_key"`
+	Status         string    `json:"status"`
+	Error          *string   `json:"error"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ScheduleAdvance moves a schedule past an executed occurrence.
+type ScheduleAdvance struct {
+	NextRunAt    *time.Time
+	LastRunAt    time.Time
+	FailureCount int
+	Status       string
+}
+
+// TransferBatch pays many wallets from one source wallet. An all_or_nothing batch is posted in
+// one DB transaction; a best_effort batch transfers item by item.
+type TransferBatch struct {
+	ID             string     `json:"id"`
+	FromWalletID   string     `json:"from_wallet_id"`
+	Mode           string     `json:"mode"`
+	Status         string     `json:"status"`
+	IdempotencyKey string     `json:"-"`
+	ItemCount      int        `json:"item_count"`
+	TotalAmount    float64    `json:"total_amount"`
+	SucceededCount int        `json:"succeeded_count"`
+	FailedCount    int        `json:"failed_count"`
+	Error          *string    `json:"error"`
+	CreatedBy      string     `json:"created_by"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	CompletedAt    *time.Time `json:"completed_at"`
+}
+
+// TransferBatchItem is one recipient of a batch. TransactionID is the sender's transfer row and
+// ReviewID the review deciding an item parked by risk screening.
+type TransferBatchItem struct {
+	ID            string    `json:"id"`
+	BatchID       string    `json:"batch_id"`
+	Seq           int       `json:"seq"`
+	ToWalletID    string    `json:"to_wallet_id"`
+	Amount        float64   `json:"amount"`
+	Reference     string    `json:"reference"`
+	Status        string    `json:"status"`
+	Error         *string   `json:"error"`
+	TransactionID *string   `json:"transaction_id"`
+	ReviewID      *string   `json:"review_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// TransferLeg moves Amount between two wallets. DebitID and CreditID are the IDs of the two
+// rows it writes, of Type or transfer when empty. ParentID links both rows to another transaction
+// and GroupID to the other legs of the same payment; InitiatedBy is recorded on the debit.
+type TransferLeg struct {
+	FromWalletID string
+	ToWalletID   string
+	Amount       float64
+	DebitID      string
+	CreditID     string
+	Type         string
+	ParentID     *string
+	InitiatedBy  *string
+	GroupID      *string
+}
+
+// Withdrawal is a debit of money leaving a wallet with the fee charged on it, if any, and the
+// record that sends the money on: a payout to a bank account or an operation of an external
+// payment provider. The debit has no counterparty; its BalanceAfter is set when it is posted.
+type Withdrawal struct {
+	Debit     *Transaction
+	Fee       *TransferLeg
+	Payout    *Payout
+	Operation *ProviderOperation
+}
+
+// InterestAccrual is the interest a savings wallet earned on one day, from its end-of-day balance.
+// Amount is an exact decimal kept at ten places; the month's accruals are added up and posted
+// as one interest transaction.
+type InterestAccrual struct {
+	WalletID      string     `json:"wallet_id"`
+	AccrualDate   time.Time  `json:"accrual_date"`
+	Balance       float64    `json:"balance"`
+	AnnualRate    float64    `json:"annual_rate"`
+	Amount        string     `json:"amount"`
+	TransactionID *string    `json:"transaction_id"`
+	PostedAt      *time.Time `json:"posted_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// WalletMember is a user's share in a wallet. An invited member gets access once they accept.
+// SpendingCap bounds what a spender may move out of the wallet per calendar month; nil is no cap.
+type WalletMember struct {
+	WalletID    string     `json:"wallet_id"`
+	UserID      string     `json:"user_id"`
+	Role        string     `json:"role"`
+	SpendingCap *float64   `json:"spending_cap,omitempty"`
+	Status      string     `json:"status"`
+	InvitedBy   string     `json:"invited_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+}
+
+// Escrow is a buyer's payment held in the system escrow wallet until it is released to the
+// seller or refunded to the buyer. The rows that settle it point at the hold through their
+// parent transaction ID.
+type Escrow struct {
+	ID                      string     `json:"id"`
+	BuyerWalletID           string     `json:"buyer_wallet_id"`
+	SellerWalletID          string     `json:"seller_wallet_id"`
+	Amount                  float64    `json:"amount"`
+	Reference               string     `json:"reference"`
+	Status                  string     `json:"status"`
+	ExpiresAt               time.Time  `json:"expires_at"`
+	HoldTransactionID       string     `json:"hold_transaction_id"`
+	SettlementTransactionID *string    `json:"settlement_transaction_id"`
+	DisputeReason           *string    `json:"dispute_reason,omitempty"`
+	DisputedBy              *string    `json:"disputed_by,omitempty"`
+	CreatedBy               string     `json:"created_by"`
+	CreatedAt               time.Time  `json:"created_at"`
+	UpdatedAt               time.Time  `json:"updated_at"`
+	SettledAt               *time.Time `json:"settled_at"`
+}
+
+// PaymentRequest is a wallet asking another wallet, the payer, to pay it. Once the payer accepts,
+// TransactionID is the payer's transfer row.
+type PaymentRequest struct {
+	ID                string     `json:"id"`
+	RequesterWalletID string     `json:"requester_wallet_id"`
+	PayerWalletID     string     `json:"payer_wallet_id"`
+	Amount            float64    `json:"amount"`
+	Note              string     `json:"note"`
+	Status            string     `json:"status"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	TransactionID     *string    `json:"transaction_id"`
+	CreatedBy         string     `json:"created_by"`
+	RespondedBy       *string    `json:"responded_by,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	RespondedAt       *time.Time `json:"responded_at"`
+}
+
+// PaymentLink lets whoever holds its token pay WalletID. Amount is fixed when set and chosen by
+// the payer otherwise. A single-use link is spent once UseCount reaches one; LastTransactionID
+// is the transfer row of the latest payment.
+type PaymentLink struct {
+	ID                string     `json:"id"`
+	WalletID          string     `json:"wallet_id"`
+	Amount            *float64   `json:"amount"`
+	Reference         string     `json:"reference"`
+	SingleUse         bool       `json:"single_use"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	UseCount          int        `json:"use_count"`
+	LastUsedAt        *time.Time `json:"last_used_at"`
+	LastTransactionID *string    `json:"last_transaction_id"`
+	CreatedBy         string     `json:"created_by"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
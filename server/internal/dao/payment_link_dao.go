@@ -0,0 +1,80 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrPaymentLinkNotFound = errors.New("payment link not found")
+
+func (dao *WalletDao) CreatePaymentLink(link *PaymentLink) error {
+	if err := dao.db.Table("payment_links").Create(link).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to create payment link")
+		return err
+	}
+	return nil
+}
+
+func (dao *WalletDao) GetPaymentLink(linkID string) (*PaymentLink, error) {
+	var link PaymentLink
+	result := dao.db.Table("payment_links").Where("id = ?", linkID).First(&link)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentLinkNotFound
+		}
+		dao.logger.WithError(result.Error).Error("Failed to fetch payment link")
+		return nil, result.Error
+	}
+	return &link, nil
+}
+
+// ClaimPaymentLink counts one use of a link that has not expired at at and, when single use,
+// has not been used yet. It returns ErrPaymentLinkNotFound when no such link exists, so two
+// payers racing for a single-use link cannot both claim it.
+func (dao *WalletDao) ClaimPaymentLink(linkID string, at time.Time) error {
+	result := dao.db.Table("payment_links").
+		Where("id = ? AND expires_at > ? AND (single_use = ? OR use_count = 0)", linkID, at, false).
+		Updates(map[string]any{
+			"use_count":    gorm.Expr("use_count + 1"),
+			"last_used_at": at,
+			"updated_at":   at,
+		})
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to claim payment link")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPaymentLinkNotFound
+	}
+	return nil
+}
+
+// ReleasePaymentLink hands back a use claimed for a payment that did not go through.
+func (dao *WalletDao) ReleasePaymentLink(linkID string, at time.Time) error {
+	result := dao.db.Table("payment_links").
+		Where("id = ? AND use_count > 0", linkID).
+		Updates(map[string]any{
+			"use_count":  gorm.Expr("use_count - 1"),
+			"updated_at": at,
+		})
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to release payment link")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPaymentLinkNotFound
+	}
+	return nil
+}
+
+// SetPaymentLinkTransaction records the transfer row of the latest payment through a link.
+func (dao *WalletDao) SetPaymentLinkTransaction(linkID, transactionID string) error {
+	if err := dao.db.Table("payment_links").Where("id = ?", linkID).
+		Update("last_transaction_id", transactionID).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to record payment link transaction")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPaymentLink(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payment_links" WHERE id = $1 ORDER BY "payment_links"."id" LIMIT $2`)).
+		WithArgs("link-1", 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := dao.GetPaymentLink("link-1")
+	assert.ErrorIs(t, err, ErrPaymentLinkNotFound)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestClaimPaymentLink(t *testing.T) {
+	now := time.Now()
+	claim := regexp.QuoteMeta(`UPDATE "payment_links" SET "last_used_at"=$1,"updated_at"=$2,"use_count"=use_count + 1 WHERE id = $3 AND expires_at > $4 AND (single_use = $5 OR use_count = 0)`)
+
+	t.Run("claimed", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(claim).WithArgs(now, now, "link-1", now, false).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.ClaimPaymentLink("link-1", now))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("used or expired", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(claim).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectCommit()
+
+		assert.ErrorIs(t, dao.ClaimPaymentLink("link-1", now), ErrPaymentLinkNotFound)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestReleasePaymentLink(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	now := time.Now()
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "payment_links" SET "updated_at"=$1,"use_count"=use_count - 1 WHERE id = $2 AND use_count > 0`)).
+		WithArgs(now, "link-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectCommit()
+
+	assert.NoError(t, dao.ReleasePaymentLink("link-1", now))
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
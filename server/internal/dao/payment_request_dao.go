@@ -0,0 +1,92 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+var ErrPaymentRequestNotFound = errors.New("payment request not found")
+
+func (dao *WalletDao) CreatePaymentRequest(req *PaymentRequest) error {
+	if err := dao.db.Table("payment_requests").Create(req).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to create payment request")
+		return err
+	}
+	return nil
+}
+
+func (dao *WalletDao) GetPaymentRequest(requestID string) (*PaymentRequest, error) {
+	var req PaymentRequest
+	result := dao.db.Table("payment_requests").Where("id = ?", requestID).First(&req)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentRequestNotFound
+		}
+		dao.logger.WithError(result.Error).Error("Failed to fetch payment request")
+		return nil, result.Error
+	}
+	return &req, nil
+}
+
+// ListPaymentRequests returns the requests a wallet was asked to pay, or with incoming false the
+// requests it sent, newest first. An empty status lists every status.
+func (dao *WalletDao) ListPaymentRequests(walletID string, incoming bool, status string, limit int) ([]PaymentRequest, error) {
+	query := dao.db.Table("payment_requests")
+	if incoming {
+		query = query.Where("payer_wallet_id = ?", walletID)
+	} else {
+		query = query.Where("requester_wallet_id = ?", walletID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var requests []PaymentRequest
+	if err := query.Order("created_at DESC").Order("id DESC").Find(&requests).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list payment requests")
+		return nil, err
+	}
+	return requests, nil
+}
+
+// ListExpiredPaymentRequests returns pending requests whose expiry is at or before now, oldest first.
+func (dao *WalletDao) ListExpiredPaymentRequests(now time.Time, limit int) ([]PaymentRequest, error) {
+	var requests []PaymentRequest
+	if err := dao.db.Table("payment_requests").
+		Where("status = ? AND expires_at <= ?", common.PaymentRequestStatusPending, now).
+		Order("expires_at ASC").Order("id ASC").Limit(limit).Find(&requests).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list expired payment requests")
+		return nil, err
+	}
+	return requests, nil
+}
+
+// UpdatePaymentRequest stores the status, transaction and response of a request that is still in
+// fromStatus. It returns ErrPaymentRequestNotFound when no such request exists, so two callers
+// racing to move the same request cannot both succeed.
+func (dao *WalletDao) UpdatePaymentRequest(req *PaymentRequest, fromStatus string) error {
+	result := dao.db.Table("payment_requests").
+		Where("id = ? AND status = ?", req.ID, fromStatus).
+		Updates(map[string]any{
+			"status":         req.Status,
+			"transaction_id": req.TransactionID,
+			"responded_by":   req.RespondedBy,
+			"responded_at":   req.RespondedAt,
+			"updated_at":     req.UpdatedAt,
+		})
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to update payment request")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPaymentRequestNotFound
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPaymentRequest(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payment_requests" WHERE id = $1 ORDER BY "payment_requests"."id" LIMIT $2`)).
+		WithArgs("request-1", 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := dao.GetPaymentRequest("request-1")
+	assert.ErrorIs(t, err, ErrPaymentRequestNotFound)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestListPaymentRequests(t *testing.T) {
+	t.Run("incoming in one status", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payment_requests" WHERE payer_wallet_id = $1 AND status = $2 ORDER BY created_at DESC,id DESC LIMIT $3`)).
+			WithArgs("wallet-1", "pending", 100).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow("request-1", "pending"))
+
+		requests, err := dao.ListPaymentRequests("wallet-1", true, "pending", 100)
+		assert.NoError(t, err)
+		assert.Len(t, requests, 1)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("outgoing", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payment_requests" WHERE requester_wallet_id = $1 ORDER BY created_at DESC,id DESC LIMIT $2`)).
+			WithArgs("wallet-1", 100).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		requests, err := dao.ListPaymentRequests("wallet-1", false, "", 100)
+		assert.NoError(t, err)
+		assert.Empty(t, requests)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestListExpiredPaymentRequests(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	now := time.Now()
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payment_requests" WHERE status = $1 AND expires_at <= $2 ORDER BY expires_at ASC,id ASC LIMIT $3`)).
+		WithArgs("pending", now, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow("request-1", "pending"))
+
+	requests, err := dao.ListExpiredPaymentRequests(now, 50)
+	assert.NoError(t, err)
+	assert.Len(t, requests, 1)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestUpdatePaymentRequest(t *testing.T) {
+	now := time.Now()
+	actor := "user-1"
+	req := &PaymentRequest{ID: "request-1", Status: "declined", RespondedBy: &actor, RespondedAt: &now, UpdatedAt: now}
+	update := regexp.QuoteMeta(`UPDATE "payment_requests" SET "responded_at"=$1,"responded_by"=$2,"status"=$3,"transaction_id"=$4,"updated_at"=$5 WHERE id = $6 AND status = $7`)
+
+	t.Run("updated", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(update).WithArgs(&now, &actor, "declined", nil, now, "request-1", "pending").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.UpdatePaymentRequest(req, "pending"))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("no longer in the expected status", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectCommit()
+
+		assert.ErrorIs(t, dao.UpdatePaymentRequest(req, "pending"), ErrPaymentRequestNotFound)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
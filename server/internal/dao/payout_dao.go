@@ -0,0 +1,150 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+var (
+	ErrPayoutNotFound = errors.New("payout not found")
+	ErrPayoutConflict = errors.New("payouts changed while being batched")
+)
+
+func (dao *WalletDao) GetPayout(payoutID string) (*Payout, error) {
+	var payout Payout
+	result := dao.db.Table("payouts").Where("id = ?", payoutID).First(&payout)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrPayoutNotFound
+		}
+		dao.logger.WithError(result.Error).Error("Failed to fetch payout")
+		return nil, result.Error
+	}
+	return &payout, nil
+}
+
+// ListPayouts returns payouts oldest first, optionally of one status. A limit of 0 returns all.
+func (dao *WalletDao) ListPayouts(status string, limit int) ([]Payout, error) {
+	query := dao.db.Table("payouts")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var payouts []Payout
+	if err := query.Order("created_at ASC").Order("id ASC").Find(&payouts).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list payouts")
+		return nil, err
+	}
+	return payouts, nil
+}
+
+// MarkPayoutsSent records a written batch file and moves its payouts from pending_payout to sent.
+// If any of them is no longer pending nothing is stored and ErrPayoutConflict is returned.
+func (dao *WalletDao) MarkPayoutsSent(batch *PayoutBatch, payoutIDs []string) error {
+	dao.logger.Infof("Marking %d payouts sent in batch %s", len(payoutIDs), batch.ID)
+
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("payout_batches").Create(batch).Error; err != nil {
+			dao.logger.WithError(err).Error("Failed to store payout batch")
+			return err
+		}
+		result := tx.Table("payouts").
+			Where("id IN ? AND status = ?", payoutIDs, common.PayoutStatusPending).
+			Updates(map[string]any{
+				"status":     common.PayoutStatusSent,
+				"batch_id":   batch.ID,
+				"updated_at": batch.CreatedAt,
+			})
+		if result.Error != nil {
+			dao.logger.WithError(result.Error).Error("Failed to mark payouts sent")
+			return result.Error
+		}
+		if result.RowsAffected != int64(len(payoutIDs)) {
+			return ErrPayoutConflict
+		}
+		return nil
+	})
+}
+
+// UpdatePayoutStatus moves a payout from one status to another. It returns ErrPayoutNotFound
+// when no payout with the expected current status exists.
+func (dao *WalletDao) UpdatePayoutStatus(payoutID, fromStatus, toStatus string) error {
+	result := dao.db.Table("payouts").
+		Where("id = ? AND status = ?", payoutID, fromStatus).
+		Updates(map[string]any{
+			"status":     toStatus,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to update payout")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPayoutNotFound
+	}
+	return nil
+}
+
+// ReturnPayout marks a payout returned and credits its amount back to the wallet with the
+// reversal transaction, in one DB transaction. The reversal's BalanceAfter is filled in from
+// the locked wallet. It returns ErrPayoutNotFound when the payout is no longer in fromStatus.
+func (dao *WalletDao) ReturnPayout(payoutID, fromStatus, reason string, reversal *Transaction) error {
+	dao.logger.Warnf("Returning payout %s to wallet %s", payoutID, reversal.WalletID)
+
+	err := dao.db.Transaction(func(db *gorm.DB) error {
+		result := db.Table("payouts").
+			Where("id = ? AND status = ?", payoutID, fromStatus).
+			Updates(map[string]any{
+				"status":                  common.PayoutStatusReturned,
+				"return_reason":           reason,
+				"reversal_transaction_id": reversal.ID,
+				"updated_at":              reversal.CreatedAt,
+			})
+		if result.Error != nil {
+			dao.logger.WithError(result.Error).Error("Failed to mark payout returned")
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrPayoutNotFound
+		}
+
+		return creditWallet(db, reversal)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrPayoutNotFound) {
+			dao.logger.WithError(err).Error("Failed to credit returned payout")
+		}
+		return err
+	}
+	dao.invalidateBalance(reversal.WalletID)
+	return nil
+}
+
+// creditWallet locks the wallet, adds the credit's amount to its balance and inserts the credit
+// transaction with its BalanceAfter filled in. It runs inside the caller's DB transaction.
+func creditWallet(db *gorm.DB, credit *Transaction) error {
+	var wallet Wallet
+	if err := db.Table("wallets").Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", credit.WalletID).First(&wallet).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrWalletNotFound
+		}
+		return err
+	}
+	balanceAfter := common.RoundToNDecimals(wallet.Balance+credit.Amount, 4)
+	if err := db.Table("wallets").Where("id = ?", credit.WalletID).
+		Update("balance", balanceAfter).Error; err != nil {
+		return err
+	}
+
+	credit.BalanceAfter = &balanceAfter
+	return db.Table("transactions").Create(credit).Error
+}
@@ -0,0 +1,136 @@
+package dao
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPayout(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	query := regexp.QuoteMeta(`SELECT * FROM "payouts" WHERE id = $1`)
+
+	dbMock.ExpectQuery(query).WithArgs("payout-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "beneficiary_iban"}).AddRow("payout-1", "sent", "GB82WEST12345698765432"))
+	payout, err := dao.GetPayout("payout-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "GB82WEST12345698765432", payout.BeneficiaryIBAN)
+
+	dbMock.ExpectQuery(query).WithArgs("missing", 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = dao.GetPayout("missing")
+	assert.ErrorIs(t, err, ErrPayoutNotFound)
+}
+
+func TestListPayouts(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "payouts" WHERE status = $1 ORDER BY created_at ASC,id ASC LIMIT $2`)).
+		WithArgs("pending_payout", 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("payout-1").AddRow("payout-2"))
+
+	payouts, err := dao.ListPayouts("pending_payout", 100)
+	assert.NoError(t, err)
+	assert.Len(t, payouts, 2)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestMarkPayoutsSent(t *testing.T) {
+	batch := &PayoutBatch{ID: "batch-1", MessageID: "batch1", FileName: "pain001.xml", PayoutCount: 2, CreatedAt: time.Now()}
+	update := regexp.QuoteMeta(`UPDATE "payouts" SET "batch_id"=$1,"status"=$2,"updated_at"=$3 WHERE id IN ($4,$5) AND status = $6`)
+
+	t.Run("all pending", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "payout_batches"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(update).WithArgs("batch-1", "sent", batch.CreatedAt, "payout-1", "payout-2", "pending_payout").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.MarkPayoutsSent(batch, []string{"payout-1", "payout-2"}))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("one no longer pending", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "payout_batches"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectRollback()
+
+		assert.ErrorIs(t, dao.MarkPayoutsSent(batch, []string{"payout-1", "payout-2"}), ErrPayoutConflict)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdatePayoutStatus(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	update := regexp.QuoteMeta(`UPDATE "payouts" SET "status"=$1,"updated_at"=$2 WHERE id = $3 AND status = $4`)
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(update).WithArgs("settled", sqlmock.AnyArg(), "payout-1", "sent").WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectCommit()
+	assert.NoError(t, dao.UpdatePayoutStatus("payout-1", "sent", "settled"))
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectCommit()
+	assert.ErrorIs(t, dao.UpdatePayoutStatus("payout-1", "sent", "settled"), ErrPayoutNotFound)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestReturnPayout(t *testing.T) {
+	markReturned := regexp.QuoteMeta(`UPDATE "payouts" SET "return_reason"=$1,"reversal_transaction_id"=$2,"status"=$3,"updated_at"=$4 WHERE id = $5 AND status = $6`)
+	lockWallet := regexp.QuoteMeta(`SELECT * FROM "wallets" WHERE id = $1 ORDER BY "wallets"."id" LIMIT $2 FOR UPDATE`)
+
+	t.Run("credits the wallet", func(t *testing.T) {
+		dao, dbMock, redisMock := setupTest(t)
+		reversal := &Transaction{ID: "tx-r", WalletID: "wallet-1", Type: "payout_return", Amount: 25, CreatedAt: time.Now()}
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(markReturned).WithArgs("AC04", "tx-r", "returned", reversal.CreatedAt, "payout-1", "sent").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(lockWallet).WithArgs("wallet-1", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 10.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets" SET "balance"=$1 WHERE id = $2`)).WithArgs(35.0, "wallet-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+		redisMock.ExpectDel("wallet_balance:wallet-1").SetVal(1)
+
+		assert.NoError(t, dao.ReturnPayout("payout-1", "sent", "AC04", reversal))
+		assert.Equal(t, 35.0, *reversal.BalanceAfter)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("already returned", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		reversal := &Transaction{ID: "tx-r", WalletID: "wallet-1", Amount: 25, CreatedAt: time.Now()}
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(markReturned).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectRollback()
+
+		assert.ErrorIs(t, dao.ReturnPayout("payout-1", "sent", "", reversal), ErrPayoutNotFound)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("insert failure rolls back", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		reversal := &Transaction{ID: "tx-r", WalletID: "wallet-1", Amount: 25, CreatedAt: time.Now()}
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(markReturned).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(lockWallet).WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 10.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnError(errors.New("insert failed"))
+		dbMock.ExpectRollback()
+
+		assert.Error(t, dao.ReturnPayout("payout-1", "sent", "", reversal))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,34 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+// ListPockets returns the pockets of a wallet, oldest first.
+func (dao *WalletDao) ListPockets(parentWalletID string) ([]Wallet, error) {
+	var pockets []Wallet
+	if err := dao.db.Table("wallets").Where("parent_wallet_id = ?", parentWalletID).
+		Order("created_at, id").Find(&pockets).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list pockets")
+		return nil, err
+	}
+	return pockets, nil
+}
+
+// UpdatePocketGoal sets or, with nil values, clears the goal of a pocket.
+func (dao *WalletDao) UpdatePocketGoal(pocketID string, amount *float64, date *time.Time) error {
+	dao.logger.Infof("Updating goal of pocket %s", pocketID)
+
+	result := dao.db.Table("wallets").Where("id = ? AND type = ?", pocketID, common.WalletTypePocket).
+		Updates(map[string]any{"goal_amount": amount, "goal_date": date})
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to update pocket goal")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWalletNotFound
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListPockets(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallets" WHERE parent_wallet_id = $1 ORDER BY created_at, id`)).
+		WithArgs("wallet-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "parent_wallet_id", "name", "balance"}).
+			AddRow("pocket-1", "wallet-1", "rent", 250.0))
+
+	pockets, err := dao.ListPockets("wallet-1")
+	assert.NoError(t, err)
+	assert.Len(t, pockets, 1)
+	assert.Equal(t, "rent", *pockets[0].Name)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestUpdatePocketGoal(t *testing.T) {
+	update := regexp.QuoteMeta(`UPDATE "wallets" SET "goal_amount"=$1,"goal_date"=$2 WHERE id = $3 AND type = $4`)
+	amount, date := 1200.0, time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("sets the goal", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(update).WithArgs(&amount, &date, "pocket-1", "pocket").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.UpdatePocketGoal("pocket-1", &amount, &date))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("not a pocket", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectCommit()
+
+		assert.ErrorIs(t, dao.UpdatePocketGoal("wallet-1", nil, nil), ErrWalletNotFound)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
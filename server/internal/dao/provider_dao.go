@@ -0,0 +1,137 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+var (
+	ErrProviderOperationNotFound = errors.New("provider operation not found")
+	ErrProviderOperationExists   = errors.New("provider operation already recorded")
+)
+
+// CreateProviderOperation stores a new provider operation. It returns ErrProviderOperationExists
+// when the provider already reported an operation with the same provider reference.
+func (dao *WalletDao) CreateProviderOperation(op *ProviderOperation) error {
+	dao.logger.Infof("Creating %s %s operation %s of %.4f for wallet %s", op.Provider, op.Kind, op.ID, op.Amount, op.WalletID)
+
+	result := dao.db.Table("provider_operations").Clauses(clause.OnConflict{DoNothing: true}).Create(op)
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to create provider operation")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrProviderOperationExists
+	}
+	return nil
+}
+
+func (dao *WalletDao) GetProviderOperation(opID string) (*ProviderOperation, error) {
+	var op ProviderOperation
+	result := dao.db.Table("provider_operations").Where("id = ?", opID).First(&op)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrProviderOperationNotFound
+		}
+		dao.logger.WithError(result.Error).Error("Failed to fetch provider operation")
+		return nil, result.Error
+	}
+	return &op, nil
+}
+
+// ListProviderOperations returns operations of one kind in any of the given statuses, oldest
+// first. A limit of 0 returns all.
+func (dao *WalletDao) ListProviderOperations(kind string, statuses []string, limit int) ([]ProviderOperation, error) {
+	query := dao.db.Table("provider_operations").Where("kind = ? AND status IN ?", kind, statuses)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var ops []ProviderOperation
+	if err := query.Order("created_at ASC").Order("id ASC").Find(&ops).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list provider operations")
+		return nil, err
+	}
+	return ops, nil
+}
+
+// UpdateProviderOperation moves an operation from one status to another. It returns
+// ErrProviderOperationNotFound when no operation with the expected current status exists.
+func (dao *WalletDao) UpdateProviderOperation(opID, fromStatus string, update ProviderOperationUpdate) error {
+	fields := map[string]any{
+		"status":     update.Status,
+		"updated_at": time.Now(),
+	}
+	if update.ProviderReference != "" {
+		fields["provider_reference"] = update.ProviderReference
+	}
+	if update.TransactionID != "" {
+		fields["transaction_id"] = update.TransactionID
+	}
+	if update.FailureReason != "" {
+		fields["failure_reason"] = update.FailureReason
+	}
+
+	result := dao.db.Table("provider_operations").Where("id = ? AND status = ?", opID, fromStatus).Updates(fields)
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to update provider operation")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrProviderOperationNotFound
+	}
+	return nil
+}
+
+// ReverseProviderOperation marks a payout failed and credits its amount back to the wallet with
+// the reversal transaction, in one DB transaction. It returns ErrProviderOperationNotFound when
+// the operation is no longer in fromStatus.
+func (dao *WalletDao) ReverseProviderOperation(opID, fromStatus, reason string, reversal *Transaction) error {
+	dao.logger.Warnf("Reversing provider operation %s to wallet %s", opID, reversal.WalletID)
+
+	return dao.settleProviderOperation(opID, fromStatus, map[string]any{
+		"status":                  common.ProviderStatusFailed,
+		"failure_reason":          reason,
+		"reversal_transaction_id": reversal.ID,
+		"updated_at":              reversal.CreatedAt,
+	}, reversal)
+}
+
+// CompleteProviderPayin marks a payin succeeded and credits its amount to the wallet with the
+// deposit transaction, in one DB transaction. It returns ErrProviderOperationNotFound when the
+// operation is no longer in fromStatus.
+func (dao *WalletDao) CompleteProviderPayin(opID, fromStatus string, deposit *Transaction) error {
+	dao.logger.Infof("Crediting provider payin %s to wallet %s", opID, deposit.WalletID)
+
+	return dao.settleProviderOperation(opID, fromStatus, map[string]any{
+		"status":         common.ProviderStatusSucceeded,
+		"transaction_id": deposit.ID,
+		"updated_at":     deposit.CreatedAt,
+	}, deposit)
+}
+
+func (dao *WalletDao) settleProviderOperation(opID, fromStatus string, fields map[string]any, credit *Transaction) error {
+	err := dao.db.Transaction(func(db *gorm.DB) error {
+		result := db.Table("provider_operations").Where("id = ? AND status = ?", opID, fromStatus).Updates(fields)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrProviderOperationNotFound
+		}
+		return creditWallet(db, credit)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrProviderOperationNotFound) {
+			dao.logger.WithError(err).Errorf("Failed to settle provider operation %s", opID)
+		}
+		return err
+	}
+	dao.invalidateBalance(credit.WalletID)
+	return nil
+}
@@ -0,0 +1,123 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateProviderOperation(t *testing.T) {
+	insert := regexp.QuoteMeta(`INSERT INTO "provider_operations"`)
+	reference := "pi_1"
+	op := &ProviderOperation{ID: "op-1", Provider: "fake", Kind: "payin", WalletID: "wallet-1", Amount: 10, ProviderReference: &reference}
+
+	t.Run("new", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(insert + ".*ON CONFLICT DO NOTHING").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.CreateProviderOperation(op))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("repeated provider reference", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectCommit()
+
+		assert.ErrorIs(t, dao.CreateProviderOperation(op), ErrProviderOperationExists)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestListProviderOperations(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "provider_operations" WHERE kind = $1 AND status IN ($2,$3) ORDER BY created_at ASC,id ASC LIMIT $4`)).
+		WithArgs("payout", "initiated", "pending", 500).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow("op-1", "pending"))
+
+	ops, err := dao.ListProviderOperations("payout", []string{"initiated", "pending"}, 500)
+	assert.NoError(t, err)
+	assert.Len(t, ops, 1)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestUpdateProviderOperation(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	update := regexp.QuoteMeta(`UPDATE "provider_operations" SET "provider_reference"=$1,"status"=$2,"updated_at"=$3 WHERE id = $4 AND status = $5`)
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(update).WithArgs("po_1", "pending", sqlmock.AnyArg(), "op-1", "initiated").WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectCommit()
+	assert.NoError(t, dao.UpdateProviderOperation("op-1", "initiated", ProviderOperationUpdate{Status: "pending", ProviderReference: "po_1"}))
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectCommit()
+	err := dao.UpdateProviderOperation("op-1", "initiated", ProviderOperationUpdate{Status: "pending", ProviderReference: "po_1"})
+	assert.ErrorIs(t, err, ErrProviderOperationNotFound)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestReverseProviderOperation(t *testing.T) {
+	markFailed := regexp.QuoteMeta(`UPDATE "provider_operations" SET "failure_reason"=$1,"reversal_transaction_id"=$2,"status"=$3,"updated_at"=$4 WHERE id = $5 AND status = $6`)
+	lockWallet := regexp.QuoteMeta(`SELECT * FROM "wallets" WHERE id = $1 ORDER BY "wallets"."id" LIMIT $2 FOR UPDATE`)
+
+	t.Run("credits the wallet", func(t *testing.T) {
+		dao, dbMock, redisMock := setupTest(t)
+		reversal := &Transaction{ID: "tx-r", WalletID: "wallet-1", Type: "withdraw_reversal", Amount: 40, CreatedAt: time.Now()}
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(markFailed).WithArgs("card expired", "tx-r", "failed", reversal.CreatedAt, "op-1", "pending").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(lockWallet).WithArgs("wallet-1", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 60.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets" SET "balance"=$1 WHERE id = $2`)).WithArgs(100.0, "wallet-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+		redisMock.ExpectDel("wallet_balance:wallet-1").SetVal(1)
+
+		assert.NoError(t, dao.ReverseProviderOperation("op-1", "pending", "card expired", reversal))
+		assert.Equal(t, 100.0, *reversal.BalanceAfter)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("already settled", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		reversal := &Transaction{ID: "tx-r", WalletID: "wallet-1", Amount: 40, CreatedAt: time.Now()}
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(markFailed).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectRollback()
+
+		assert.ErrorIs(t, dao.ReverseProviderOperation("op-1", "pending", "", reversal), ErrProviderOperationNotFound)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestCompleteProviderPayin(t *testing.T) {
+	dao, dbMock, redisMock := setupTest(t)
+	deposit := &Transaction{ID: "tx-d", WalletID: "wallet-1", Type: "deposit", Amount: 12.5, CreatedAt: time.Now()}
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "provider_operations" SET "status"=$1,"transaction_id"=$2,"updated_at"=$3 WHERE id = $4 AND status = $5`)).
+		WithArgs("succeeded", "tx-d", deposit.CreatedAt, "op-1", "initiated").WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallets"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 100.0))
+	dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WithArgs(112.5, "wallet-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectCommit()
+	redisMock.ExpectDel("wallet_balance:wallet-1").SetVal(1)
+
+	assert.NoError(t, dao.CompleteProviderPayin("op-1", "initiated", deposit))
+	assert.Equal(t, 112.5, *deposit.BalanceAfter)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
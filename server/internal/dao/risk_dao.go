@@ -0,0 +1,109 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+var (
+	ErrReviewNotFound = errors.New("transaction review not found")
+)
+
+// CountOutgoingTransactions counts withdrawals and outgoing transfers of a wallet since the given time.
+func (dao *WalletDao) CountOutgoingTransactions(walletID string, since time.Time) (int64, error) {
+	var count int64
+	err := dao.db.Table("transactions").
+		Where("wallet_id = ? AND created_at >= ?", walletID, since).
+		Where("type = ? OR (type = ? AND amount < 0)", common.TransactionTypeWithdraw, common.TransactionTypeTransfer).
+		Count(&count).Error
+	if err != nil {
+		dao.logger.WithError(err).Error("Failed to count outgoing transactions")
+		return 0, err
+	}
+	return count, nil
+}
+
+// HasTransferBetween reports whether fromWalletID sent a transfer to toWalletID since the given time.
+func (dao *WalletDao) HasTransferBetween(fromWalletID, toWalletID string, since time.Time) (bool, error) {
+	var count int64
+	err := dao.db.Table("transactions").
+		Where("wallet_id = ? AND related_user_id = ? AND type = ? AND amount < 0 AND created_at >= ?",
+			fromWalletID, toWalletID, common.TransactionTypeTransfer, since).
+		Count(&count).Error
+	if err != nil {
+		dao.logger.WithError(err).Error("Failed to look up transfers between wallets")
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (dao *WalletDao) CreateTransactionReview(review *TransactionReview) error {
+	dao.logger.Infof("Parking %s of wallet %s for review", review.Type, review.WalletID)
+	return dao.db.Table("transaction_reviews").Create(review).Error
+}
+
+func (dao *WalletDao) GetTransactionReview(reviewID string) (*TransactionReview, error) {
+	var review TransactionReview
+	result := dao.db.Table("transaction_reviews").Where("id = ?", reviewID).First(&review)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		dao.logger.WithError(result.Error).Error("Failed to fetch transaction review")
+		return nil, result.Error
+	}
+	return &review, nil
+}
+
+func (dao *WalletDao) ListTransactionReviews(status string) ([]TransactionReview, error) {
+	query := dao.db.Table("transaction_reviews")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var reviews []TransactionReview
+	if err := query.Order("created_at ASC").Find(&reviews).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list transaction reviews")
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// SetTransactionReviewRequest records the idempotency key of the request a review parked.
+func (dao *WalletDao) SetTransactionReviewRequest(reviewID, idempotencyKey, method, path string) error {
+	err := dao.db.Table("transaction_reviews").
+		Where("id = ?", reviewID).
+		Updates(map[string]any{
+			"idempotency_key": idempotencyKey,
+			"request_method":  method,
+			"request_path":    path,
+		}).Error
+	if err != nil {
+		dao.logger.WithError(err).Error("Failed to record the request of transaction review")
+	}
+	return err
+}
+
+// UpdateTransactionReviewStatus moves a review from one status to another. It returns
+// ErrReviewNotFound when no review with the expected current status exists.
+func (dao *WalletDao) UpdateTransactionReviewStatus(reviewID, fromStatus, toStatus, reviewedBy string) error {
+	result := dao.db.Table("transaction_reviews").
+		Where("id = ? AND status = ?", reviewID, fromStatus).
+		Updates(map[string]any{
+			"status":      toStatus,
+			"reviewed_by": reviewedBy,
+			"reviewed_at": time.Now(),
+		})
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to update transaction review")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReviewNotFound
+	}
+	return nil
+}
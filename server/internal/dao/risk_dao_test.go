@@ -0,0 +1,79 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountOutgoingTransactions(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	since := time.Now().Add(-time.Minute)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "transactions" WHERE (wallet_id = $1 AND created_at >= $2) AND (type = $3 OR (type = $4 AND amount < 0))`)).
+		WithArgs("wallet-1", since, "withdraw", "transfer").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	count, err := dao.CountOutgoingTransactions("wallet-1", since)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestHasTransferBetween(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	since := time.Now().Add(-time.Hour)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "transactions"`)).
+		WithArgs("wallet-b", "wallet-a", "transfer", since).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	found, err := dao.HasTransferBetween("wallet-b", "wallet-a", since)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestUpdateTransactionReviewStatus(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+
+	t.Run("pending review resolved", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "transaction_reviews" SET "reviewed_at"=$1,"reviewed_by"=$2,"status"=$3 WHERE id = $4 AND status = $5`)).
+			WithArgs(sqlmock.AnyArg(), "operator-1", "approved", "review-1", "pending").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		err := dao.UpdateTransactionReviewStatus("review-1", "pending", "approved", "operator-1")
+		assert.NoError(t, err)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("review no longer pending", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "transaction_reviews"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectCommit()
+
+		err := dao.UpdateTransactionReviewStatus("review-1", "pending", "approved", "operator-1")
+		assert.ErrorIs(t, err, ErrReviewNotFound)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestSetTransactionReviewRequest(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "transaction_reviews" SET "idempotency_key"=$1,"request_method"=$2,"request_path"=$3 WHERE id = $4`)).
+		WithArgs("key-1", "POST", "/wallets/transfer", "review-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectCommit()
+
+	err := dao.SetTransactionReviewRequest("review-1", "key-1", "POST", "/wallets/transfer")
+	assert.NoError(t, err)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
@@ -0,0 +1,155 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+var (
+	ErrScheduleNotFound  = errors.New("schedule not found")
+	ErrScheduleRunExists = errors.New("schedule occurrence already claimed")
+)
+
+func (dao *WalletDao) CreateSchedule(schedule *Schedule) error {
+	dao.logger.Infof("Creating schedule %s of %.4f from wallet %s to %s", schedule.ID, schedule.Amount, schedule.FromWalletID, schedule.ToWalletID)
+	return dao.db.Table("schedules").Create(schedule).Error
+}
+
+func (dao *WalletDao) GetSchedule(scheduleID string) (*Schedule, error) {
+	var schedule Schedule
+	result := dao.db.Table("schedules").Where("id = ?", scheduleID).First(&schedule)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrScheduleNotFound
+		}
+		dao.logger.WithError(result.Error).Error("Failed to fetch schedule")
+		return nil, result.Error
+	}
+	return &schedule, nil
+}
+
+// ListSchedules returns the schedules paying out of a wallet, newest first.
+func (dao *WalletDao) ListSchedules(walletID string) ([]Schedule, error) {
+	var schedules []Schedule
+	if err := dao.db.Table("schedules").Where("from_wallet_id = ?", walletID).
+		Order("created_at DESC").Order("id DESC").Find(&schedules).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list schedules")
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// ListDueSchedules returns active schedules whose next occurrence is at or before now, most
+// overdue first.
+func (dao *WalletDao) ListDueSchedules(now time.Time, limit int) ([]Schedule, error) {
+	var schedules []Schedule
+	if err := dao.db.Table("schedules").
+		Where("status = ? AND next_run_at <= ?", common.ScheduleStatusActive, now).
+		Order("next_run_at ASC").Order("id ASC").Limit(limit).Find(&schedules).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list due schedules")
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// UpdateScheduleStatus moves a schedule in one of fromStatuses to toStatus with a new next
+// occurrence, clearing its failure count. It returns ErrScheduleNotFound when no schedule with
+// an expected status exists.
+func (dao *WalletDao) UpdateScheduleStatus(scheduleID string, fromStatuses []string, toStatus string, nextRunAt *time.Time) error {
+	result := dao.db.Table("schedules").
+		Where("id = ? AND status IN ?", scheduleID, fromStatuses).
+		Updates(map[string]any{
+			"status":        toStatus,
+			"next_run_at":   nextRunAt,
+			"failure_count": 0,
+			"updated_at":    time.Now(),
+		})
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to update schedule status")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+// ClaimScheduleRun records that an occurrence is about to be executed. An occurrence whose
+// earlier attempt ended in error is claimed again; any other existing run returns
+// ErrScheduleRunExists, so each occurrence moves money at most once.
+func (dao *WalletDao) ClaimScheduleRun(run *ScheduleRun) error {
+	result := dao.db.Table("schedule_runs").
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "idempotency_key"}}, DoNothing: true}).
+		Create(run)
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to claim schedule run")
+		return result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil
+	}
+
+	result = dao.db.Table("schedule_runs").
+		Where("idempotency_key = ? AND status = ?", run.IdempotencyKey, common.ScheduleRunError).
+		Updates(map[string]any{
+			"status":     common.ScheduleRunPending,
+			"updated_at": run.CreatedAt,
+		})
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to reclaim schedule run")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrScheduleRunExists
+	}
+	return nil
+}
+
+// FinishScheduleRun records the outcome of a claimed occurrence.
+func (dao *WalletDao) FinishScheduleRun(idempotencyKey, status string, runErr *string) error {
+	return dao.db.Table("schedule_runs").
+		Where("idempotency_key = ?", idempotencyKey).
+		Updates(map[string]any{
+			"status":     status,
+			"error":      runErr,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// AdvanceSchedule moves an active schedule past the occurrence it was due for. It returns
+// ErrScheduleNotFound when the schedule was paused, cancelled or advanced in the meantime.
+func (dao *WalletDao) AdvanceSchedule(scheduleID string, occurrence time.Time, advance ScheduleAdvance) error {
+	result := dao.db.Table("schedules").
+		Where("id = ? AND status = ? AND next_run_at = ?", scheduleID, common.ScheduleStatusActive, occurrence).
+		Updates(map[string]any{
+			"status":        advance.Status,
+			"next_run_at":   advance.NextRunAt,
+			"last_run_at":   advance.LastRunAt,
+			"failure_count": advance.FailureCount,
+			"updated_at":    time.Now(),
+		})
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to advance schedule")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+// ListScheduleRuns returns a schedule's most recent runs, newest first.
+func (dao *WalletDao) ListScheduleRuns(scheduleID string, limit int) ([]ScheduleRun, error) {
+	var runs []ScheduleRun
+	if err := dao.db.Table("schedule_runs").Where("schedule_id = ?", scheduleID).
+		Order("occurrence_at DESC").Limit(limit).Find(&runs).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list schedule runs")
+		return nil, err
+	}
+	return runs, nil
+}
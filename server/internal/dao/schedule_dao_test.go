@@ -0,0 +1,106 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListDueSchedules(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	now := time.Now()
+
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "schedules" WHERE status = $1 AND next_run_at <= $2 ORDER BY next_run_at ASC,id ASC LIMIT $3`)).
+		WithArgs("active", now, 200).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow("sched-1", "active"))
+
+	schedules, err := dao.ListDueSchedules(now, 200)
+	assert.NoError(t, err)
+	assert.Len(t, schedules, 1)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestUpdateScheduleStatus(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	update := regexp.QuoteMeta(`UPDATE "schedules" SET "failure_count"=$1,"next_run_at"=$2,"status"=$3,"updated_at"=$4 WHERE id = $5 AND status IN ($6,$7)`)
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(update).WithArgs(0, nil, "cancelled", sqlmock.AnyArg(), "sched-1", "active", "paused").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectCommit()
+	assert.NoError(t, dao.UpdateScheduleStatus("sched-1", []string{"active", "paused"}, "cancelled", nil))
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectCommit()
+	err := dao.UpdateScheduleStatus("sched-1", []string{"active", "paused"}, "cancelled", nil)
+	assert.ErrorIs(t, err, ErrScheduleNotFound)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestClaimScheduleRun(t *testing.T) {
+	insert := regexp.QuoteMeta(`INSERT INTO "schedule_runs"`) + `.*` + regexp.QuoteMeta(`ON CONFLICT ("idempotency_key") DO NOTHING`)
+	reclaim := regexp.QuoteMeta(`UPDATE "schedule_runs" SET "status"=$1,"updated_at"=$2 WHERE idempotency_key = $3 AND status = $4`)
+	run := &ScheduleRun{ID: "run-1", ScheduleID: "sched-1", IdempotencyKey: "schedule:sched-1:1767225600", Status: "pending"}
+
+	t.Run("new occurrence", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.ClaimScheduleRun(run))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("retries an errored occurrence", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectCommit()
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(reclaim).WithArgs("pending", sqlmock.AnyArg(), run.IdempotencyKey, "error").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.ClaimScheduleRun(run))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("already executed", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectCommit()
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(reclaim).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectCommit()
+
+		assert.ErrorIs(t, dao.ClaimScheduleRun(run), ErrScheduleRunExists)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestAdvanceSchedule(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	occurrence := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	next := occurrence.AddDate(0, 1, 0)
+	update := regexp.QuoteMeta(`UPDATE "schedules" SET "failure_count"=$1,"last_run_at"=$2,"next_run_at"=$3,"status"=$4,"updated_at"=$5 WHERE id = $6 AND status = $7 AND next_run_at = $8`)
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(update).WithArgs(0, occurrence, &next, "active", sqlmock.AnyArg(), "sched-1", "active", occurrence).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectCommit()
+	assert.NoError(t, dao.AdvanceSchedule("sched-1", occurrence, ScheduleAdvance{NextRunAt: &next, LastRunAt: occurrence, Status: "active"}))
+
+	// paused while the occurrence ran
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectCommit()
+	err := dao.AdvanceSchedule("sched-1", occurrence, ScheduleAdvance{NextRunAt: &next, LastRunAt: occurrence, Status: "active"})
+	assert.ErrorIs(t, err, ErrScheduleNotFound)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
@@ -0,0 +1,64 @@
+package dao
+
+import (
+	"time"
+)
+
+// CreateBalanceSnapshots records every wallet's ledger balance as of asOf, starting from the
+// wallet's latest earlier snapshot so each run only scans the transactions since the previous one.
+func (dao *WalletDao) CreateBalanceSnapshots(asOf time.Time) (int64, error) {
+	dao.logger.Infof("Creating balance snapshots as of %s", asOf.Format(time.RFC3339))
+
+	result := dao.db.Exec(`
+INSERT INTO balance_snapshots (wallet_id, as_of, balance, created_at)
+SELECT wallets.id, @as_of,
+       COALESCE(prev.balance, 0) + COALESCE((
+           SELECT SUM(`+signedAmountSQL+`)
+           FROM transactions
+           WHERE transactions.wallet_id = wallets.id
+             AND transactions.created_at > COALESCE(prev.as_of, '-infinity'::timestamp)
+             AND transactions.created_at <= @as_of
+       ), 0),
+       @now
+FROM wallets
+LEFT JOIN LATERAL (
+    SELECT as_of, balance FROM balance_snapshots
+    WHERE balance_snapshots.wallet_id = wallets.id AND balance_snapshots.as_of <= @as_of
+    ORDER BY as_of DESC LIMIT 1
+) prev ON true
+ON CONFLICT (wallet_id, as_of) DO NOTHING`,
+		map[string]any{"as_of": asOf, "now": time.Now()})
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to create balance snapshots")
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// GetBalanceAsOf returns a wallet's ledger balance at asOf: the latest snapshot taken at or
+// before asOf plus the transactions after it.
+func (dao *WalletDao) GetBalanceAsOf(walletID string, asOf time.Time) (float64, error) {
+	var snapshot BalanceSnapshot
+	err := dao.db.Table("balance_snapshots").
+		Where("wallet_id = ? AND as_of <= ?", walletID, asOf).
+		Order("as_of DESC").Limit(1).
+		Find(&snapshot).Error
+	if err != nil {
+		dao.logger.WithError(err).Error("Failed to fetch balance snapshot")
+		return 0, err
+	}
+
+	query := dao.db.Table("transactions").
+		Select("COALESCE(SUM("+signedAmountSQL+"), 0)").
+		Where("wallet_id = ? AND created_at <= ?", walletID, asOf)
+	if snapshot.WalletID != "" {
+		query = query.Where("created_at > ?", snapshot.AsOf)
+	}
+
+	var delta float64
+	if err := query.Scan(&delta).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to sum transactions for balance as of")
+		return 0, err
+	}
+	return snapshot.Balance + delta, nil
+}
@@ -0,0 +1,59 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateBalanceSnapshots(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	asOf := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO balance_snapshots (wallet_id, as_of, balance, created_at)`)).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+
+	count, err := dao.CreateBalanceSnapshots(asOf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestGetBalanceAsOf(t *testing.T) {
+	asOf := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	t.Run("from latest snapshot", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		snapshotAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "balance_snapshots" WHERE wallet_id = $1 AND as_of <= $2 ORDER BY as_of DESC LIMIT $3`)).
+			WithArgs("wallet-1", asOf, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"wallet_id", "as_of", "balance"}).AddRow("wallet-1", snapshotAt, 100.0))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(`)).
+			WithArgs("wallet-1", asOf, snapshotAt).
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(-20.0))
+
+		balance, err := dao.GetBalanceAsOf("wallet-1", asOf)
+		assert.NoError(t, err)
+		assert.Equal(t, 80.0, balance)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("no snapshot yet", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "balance_snapshots"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"wallet_id", "as_of", "balance"}))
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT COALESCE(SUM(`)).
+			WithArgs("wallet-1", asOf).
+			WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(35.5))
+
+		balance, err := dao.GetBalanceAsOf("wallet-1", asOf)
+		assert.NoError(t, err)
+		assert.Equal(t, 35.5, balance)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
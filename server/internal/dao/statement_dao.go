@@ -0,0 +1,86 @@
+package dao
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrStatementNotFound = errors.New("statement not found")
+	ErrStatementExists   = errors.New("statement already exists for period")
+)
+
+// CreateStatement stores a generated statement. Only one statement exists per wallet and period.
+func (dao *WalletDao) CreateStatement(statement *Statement) error {
+	dao.logger.Infof("Storing statement %s for wallet %s", statement.ID, statement.WalletID)
+
+	result := dao.db.Table("statements").Clauses(clause.OnConflict{DoNothing: true}).Create(statement)
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to store statement")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStatementExists
+	}
+	return nil
+}
+
+func (dao *WalletDao) GetStatement(statementID string) (*Statement, error) {
+	var statement Statement
+	err := dao.db.Table("statements").Where("id = ?", statementID).First(&statement).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStatementNotFound
+		}
+		dao.logger.WithError(err).Error("Failed to fetch statement")
+		return nil, err
+	}
+	return &statement, nil
+}
+
+func (dao *WalletDao) GetStatementForPeriod(walletID string, periodStart time.Time) (*Statement, error) {
+	var statement Statement
+	err := dao.db.Table("statements").Where("wallet_id = ? AND period_start = ?", walletID, periodStart).First(&statement).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStatementNotFound
+		}
+		dao.logger.WithError(err).Error("Failed to fetch statement for period")
+		return nil, err
+	}
+	return &statement, nil
+}
+
+// ListStatements returns the wallet's statements, newest period first, without their documents.
+func (dao *WalletDao) ListStatements(walletID string) ([]Statement, error) {
+	var statements []Statement
+	err := dao.db.Table("statements").
+		Omit("document").
+		Where("wallet_id = ?", walletID).
+		Order("period_start DESC").
+		Find(&statements).Error
+	if err != nil {
+		dao.logger.WithError(err).Error("Failed to list statements")
+		return nil, err
+	}
+	return statements, nil
+}
+
+// ListWalletsWithoutStatement returns the wallets that existed before periodEnd and have no
+// statement for the period starting at periodStart.
+func (dao *WalletDao) ListWalletsWithoutStatement(periodStart, periodEnd time.Time) ([]string, error) {
+	var walletIDs []string
+	err := dao.db.Table("wallets").
+		Where("wallets.created_at < ?", periodEnd).
+		Where("NOT EXISTS (SELECT 1 FROM statements WHERE statements.wallet_id = wallets.id AND statements.period_start = ?)", periodStart).
+		Order("wallets.id").
+		Pluck("wallets.id", &walletIDs).Error
+	if err != nil {
+		dao.logger.WithError(err).Error("Failed to list wallets without statement")
+		return nil, err
+	}
+	return walletIDs, nil
+}
@@ -0,0 +1,87 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateStatement(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	statement := &Statement{
+		ID:             "stmt-1",
+		WalletID:       "wallet-1",
+		PeriodStart:    start,
+		PeriodEnd:      start.AddDate(0, 1, 0),
+		OpeningBalance: 10,
+		ClosingBalance: 25,
+		Document:       `{"id":"stmt-1"}`,
+		CreatedAt:      time.Now(),
+	}
+	insert := regexp.QuoteMeta(`INSERT INTO "statements"`) + `.*` + regexp.QuoteMeta(`ON CONFLICT DO NOTHING`)
+
+	t.Run("stored", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.CreateStatement(statement))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("period already has a statement", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectCommit()
+
+		assert.ErrorIs(t, dao.CreateStatement(statement), ErrStatementExists)
+	})
+}
+
+func TestGetStatement(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	query := regexp.QuoteMeta(`SELECT * FROM "statements" WHERE id = $1 ORDER BY "statements"."id" LIMIT $2`)
+
+	dbMock.ExpectQuery(query).WithArgs("stmt-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "wallet_id", "document"}).AddRow("stmt-1", "wallet-1", "{}"))
+	statement, err := dao.GetStatement("stmt-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", statement.Document)
+
+	dbMock.ExpectQuery(query).WithArgs("missing", 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = dao.GetStatement("missing")
+	assert.ErrorIs(t, err, ErrStatementNotFound)
+}
+
+func TestListStatements(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT "statements"."id","statements"."wallet_id","statements"."period_start","statements"."period_end","statements"."opening_balance","statements"."closing_balance","statements"."transaction_count","statements"."created_at" FROM "statements" WHERE wallet_id = $1 ORDER BY period_start DESC`)).
+		WithArgs("wallet-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "wallet_id"}).AddRow("stmt-2", "wallet-1").AddRow("stmt-1", "wallet-1"))
+
+	statements, err := dao.ListStatements("wallet-1")
+	assert.NoError(t, err)
+	assert.Len(t, statements, 2)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestListWalletsWithoutStatement(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT "wallets"."id" FROM "wallets" WHERE wallets.created_at < $1 AND (NOT EXISTS (SELECT 1 FROM statements WHERE statements.wallet_id = wallets.id AND statements.period_start = $2)) ORDER BY wallets.id`)).
+		WithArgs(end, start).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("wallet-1").AddRow("wallet-2"))
+
+	walletIDs, err := dao.ListWalletsWithoutStatement(start, end)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"wallet-1", "wallet-2"}, walletIDs)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
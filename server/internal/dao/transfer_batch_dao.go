@@ -0,0 +1,432 @@
+package dao
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+var (
+	ErrTransferBatchNotFound = errors.New("transfer batch not found")
+	ErrTransferBatchExists   = errors.New("transfer batch already exists for this idempotency key")
+	ErrInsufficientFunds     = errors.New("insufficient funds")
+	ErrBatchItemNotPending   = errors.New("transfer batch item is no longer pending")
+)
+
+// CreateTransferBatch stores a batch with its items in one DB transaction. It returns
+// ErrTransferBatchExists when a batch with the same idempotency key exists.
+func (dao *WalletDao) CreateTransferBatch(batch *TransferBatch, items []TransferBatchItem) error {
+	dao.logger.Infof("Creating transfer batch %s with %d items from wallet %s", batch.ID, len(items), batch.FromWalletID)
+
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("transfer_batches").
+			Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "idempotency_key"}}, DoNothing: true}).
+			Create(batch)
+		if result.Error != nil {
+			dao.logger.WithError(result.Error).Error("Failed to create transfer batch")
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTransferBatchExists
+		}
+		if err := tx.Table("transfer_batch_items").Create(&items).Error; err != nil {
+			dao.logger.WithError(err).Error("Failed to create transfer batch items")
+			return err
+		}
+		return nil
+	})
+}
+
+func (dao *WalletDao) GetTransferBatch(batchID string) (*TransferBatch, error) {
+	return dao.findTransferBatch("id = ?", batchID)
+}
+
+func (dao *WalletDao) GetTransferBatchByKey(idempotencyKey string) (*TransferBatch, error) {
+	return dao.findTransferBatch("idempotency_key = ?", idempotencyKey)
+}
+
+func (dao *WalletDao) findTransferBatch(query string, arg string) (*TransferBatch, error) {
+	var batch TransferBatch
+	err := dao.db.Table("transfer_batches").Where(query, arg).First(&batch).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTransferBatchNotFound
+		}
+		dao.logger.WithError(err).Error("Failed to fetch transfer batch")
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// ListTransferBatchItems returns a batch's items in upload order.
+func (dao *WalletDao) ListTransferBatchItems(batchID string) ([]TransferBatchItem, error) {
+	var items []TransferBatchItem
+	if err := dao.db.Table("transfer_batch_items").Where("batch_id = ?", batchID).
+		Order("seq ASC").Find(&items).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list transfer batch items")
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListPendingTransferBatches returns batches waiting to be executed, oldest first.
+func (dao *WalletDao) ListPendingTransferBatches(limit int) ([]TransferBatch, error) {
+	var batches []TransferBatch
+	if err := dao.db.Table("transfer_batches").Where("status = ?", common.BatchStatusPending).
+		Order("created_at ASC").Order("id ASC").Limit(limit).Find(&batches).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list pending transfer batches")
+		return nil, err
+	}
+	return batches, nil
+}
+
+// ClaimTransferBatch moves a pending batch to processing. It returns ErrTransferBatchNotFound
+// when the batch is no longer pending.
+func (dao *WalletDao) ClaimTransferBatch(batchID string) error {
+	result := dao.db.Table("transfer_batches").
+		Where("id = ? AND status = ?", batchID, common.BatchStatusPending).
+		Updates(map[string]any{
+			"status":     common.BatchStatusProcessing,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to claim transfer batch")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTransferBatchNotFound
+	}
+	return nil
+}
+
+// ListStalledTransferBatches returns batches left processing since before by a run that was
+// interrupted, oldest first.
+func (dao *WalletDao) ListStalledTransferBatches(before time.Time, limit int) ([]TransferBatch, error) {
+	var batches []TransferBatch
+	if err := dao.db.Table("transfer_batches").
+		Where("status = ? AND updated_at < ?", common.BatchStatusProcessing, before).
+		Order("updated_at ASC").Order("id ASC").Limit(limit).Find(&batches).Error; err != nil {
+		dao.logger.WithError(err).Error("Failed to list stalled transfer batches")
+		return nil, err
+	}
+	return batches, nil
+}
+
+// ReclaimTransferBatch takes over a batch left processing since before, so that one run resumes
+// it. It returns ErrTransferBatchNotFound when the batch finished or was taken over meanwhile.
+func (dao *WalletDao) ReclaimTransferBatch(batchID string, before time.Time) error {
+	result := dao.db.Table("transfer_batches").
+		Where("id = ? AND status = ? AND updated_at < ?", batchID, common.BatchStatusProcessing, before).
+		Update("updated_at", time.Now())
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to reclaim transfer batch")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTransferBatchNotFound
+	}
+	return nil
+}
+
+// UpdateTransferBatchItem records the outcome of one item.
+func (dao *WalletDao) UpdateTransferBatchItem(itemID, status string, itemErr, transactionID *string) error {
+	return dao.db.Table("transfer_batch_items").
+		Where("id = ?", itemID).
+		Updates(map[string]any{
+			"status":         status,
+			"error":          itemErr,
+			"transaction_id": transactionID,
+			"updated_at":     time.Now(),
+		}).Error
+}
+
+// ParkTransferBatchItem marks an item parked by risk screening, with the review that decides it.
+func (dao *WalletDao) ParkTransferBatchItem(itemID, reviewID string) error {
+	return dao.db.Table("transfer_batch_items").
+		Where("id = ?", itemID).
+		Updates(map[string]any{
+			"status":     common.BatchItemReview,
+			"review_id":  reviewID,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// PostTransferBatchItem posts the legs paying one pending item and marks the item succeeded, with
+// legs[0] as its transaction, in one DB transaction. Nothing is posted when the item is no longer
+// pending, in which case ErrBatchItemNotPending is returned.
+func (dao *WalletDao) PostTransferBatchItem(itemID string, legs []TransferLeg) error {
+	return dao.postSettlingItem(legs, false, "id = ? AND status = ?", itemID, common.BatchItemPending)
+}
+
+// PostReviewedTransfer posts the legs of a transfer released by review reviewID and, when the
+// review parked a batch item, marks that item succeeded and counts it on its batch, in one DB
+// transaction.
+func (dao *WalletDao) PostReviewedTransfer(reviewID string, legs []TransferLeg) error {
+	return dao.postSettlingItem(legs, true, "review_id = ? AND status = ?", reviewID, common.BatchItemReview)
+}
+
+// FailReviewedTransferBatchItem marks the batch item parked by review reviewID failed and counts
+// it on its batch. A review that did not park a batch item is left alone.
+func (dao *WalletDao) FailReviewedTransferBatchItem(reviewID, itemErr string) error {
+	now := time.Now()
+	return dao.db.Transaction(func(db *gorm.DB) error {
+		item, err := settleBatchItem(db, common.BatchItemFailed, &itemErr, nil, now,
+			"review_id = ? AND status = ?", reviewID, common.BatchItemReview)
+		if errors.Is(err, ErrBatchItemNotPending) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return countSettledItem(db, item.BatchID, now, map[string]any{
+			"failed_count": gorm.Expr("failed_count + 1"),
+			"status": gorm.Expr("CASE WHEN failed_count + 1 >= item_count THEN ? ELSE ? END",
+				common.BatchStatusFailed, common.BatchStatusPartial),
+		})
+	})
+}
+
+// postSettlingItem posts legs and marks the item matching query succeeded in one DB transaction.
+// When optional, legs are posted even if no item matches.
+func (dao *WalletDao) postSettlingItem(legs []TransferLeg, optional bool, query string, args ...any) error {
+	now := time.Now()
+	err := dao.db.Transaction(func(db *gorm.DB) error {
+		if err := postTransfers(db, legs, now); err != nil {
+			return err
+		}
+		item, err := settleBatchItem(db, common.BatchItemSucceeded, nil, &legs[0].DebitID, now, query, args...)
+		if err != nil {
+			if optional && errors.Is(err, ErrBatchItemNotPending) {
+				return nil
+			}
+			return err
+		}
+		if item.ReviewID == nil {
+			return nil
+		}
+		return countSettledItem(db, item.BatchID, now, map[string]any{
+			"succeeded_count": gorm.Expr("succeeded_count + 1"),
+		})
+	})
+	if err != nil {
+		if !errors.Is(err, ErrInsufficientFunds) && !errors.Is(err, ErrBatchItemNotPending) {
+			dao.logger.WithError(err).Error("Failed to post transfer batch item")
+		}
+		return err
+	}
+
+	for _, walletID := range legWallets(legs) {
+		dao.invalidateBalance(walletID)
+	}
+	return nil
+}
+
+// countSettledItem applies updates to the counts of a finished batch whose parked item was
+// settled by a review. The counts of a batch still processing are written when it finishes.
+func countSettledItem(db *gorm.DB, batchID string, now time.Time, updates map[string]any) error {
+	updates["updated_at"] = now
+	return db.Table("transfer_batches").
+		Where("id = ? AND status <> ?", batchID, common.BatchStatusProcessing).
+		Updates(updates).Error
+}
+
+// settleBatchItem locks the item matching query and records its outcome. It returns
+// ErrBatchItemNotPending when no item matches.
+func settleBatchItem(db *gorm.DB, status string, itemErr, transactionID *string, now time.Time, query string, args ...any) (*TransferBatchItem, error) {
+	var item TransferBatchItem
+	err := db.Table("transfer_batch_items").Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(query, args...).First(&item).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBatchItemNotPending
+		}
+		return nil, err
+	}
+	if err := db.Table("transfer_batch_items").Where("id = ?", item.ID).
+		Updates(map[string]any{
+			"status":         status,
+			"error":          itemErr,
+			"transaction_id": transactionID,
+			"updated_at":     now,
+		}).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// FinishTransferBatch records the final status and counts of a processing batch. Items still
+// pending are marked skipped.
+func (dao *WalletDao) FinishTransferBatch(batch *TransferBatch) error {
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("transfer_batch_items").
+			Where("batch_id = ? AND status = ?", batch.ID, common.BatchItemPending).
+			Updates(map[string]any{
+				"status":     common.BatchItemSkipped,
+				"updated_at": batch.UpdatedAt,
+			}).Error; err != nil {
+			return err
+		}
+		return dao.finishTransferBatch(tx, batch)
+	})
+}
+
+func (dao *WalletDao) finishTransferBatch(db *gorm.DB, batch *TransferBatch) error {
+	result := db.Table("transfer_batches").
+		Where("id = ? AND status = ?", batch.ID, common.BatchStatusProcessing).
+		Updates(map[string]any{
+			"status":          batch.Status,
+			"succeeded_count": batch.SucceededCount,
+			"failed_count":    batch.FailedCount,
+			"error":           batch.Error,
+			"updated_at":      batch.UpdatedAt,
+			"completed_at":    batch.CompletedAt,
+		})
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to finish transfer batch")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTransferBatchNotFound
+	}
+	return nil
+}
+
+// ExecuteTransferBatch posts all legs of a batch and marks its items succeeded in one DB
+// transaction. itemIDs[i] is the item paid by legs[i]; legs past the items, such as fees, are
+// posted with them. Nothing is posted when a sender would go past its credit limit, in which case
+// ErrInsufficientFunds is returned.
+func (dao *WalletDao) ExecuteTransferBatch(batch *TransferBatch, itemIDs []string, legs []TransferLeg) error {
+	dao.logger.Infof("Posting %d transfers of batch %s", len(legs), batch.ID)
+
+	err := dao.db.Transaction(func(db *gorm.DB) error {
+		if err := postTransfers(db, legs, batch.UpdatedAt); err != nil {
+			return err
+		}
+		for i, itemID := range itemIDs {
+			if err := db.Table("transfer_batch_items").Where("id = ?", itemID).
+				Updates(map[string]any{
+					"status":         common.BatchItemSucceeded,
+					"transaction_id": legs[i].DebitID,
+					"updated_at":     batch.UpdatedAt,
+				}).Error; err != nil {
+				return err
+			}
+		}
+		return dao.finishTransferBatch(db, batch)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrInsufficientFunds) {
+			dao.logger.WithError(err).Error("Failed to post transfer batch")
+		}
+		return err
+	}
+
+	for _, walletID := range legWallets(legs) {
+		dao.invalidateBalance(walletID)
+	}
+	return nil
+}
+
+// PostTransfers posts legs in one DB transaction: either every leg is applied or none is.
+func (dao *WalletDao) PostTransfers(legs []TransferLeg) error {
+	err := dao.db.Transaction(func(db *gorm.DB) error {
+		return postTransfers(db, legs, time.Now())
+	})
+	if err != nil {
+		if !errors.Is(err, ErrInsufficientFunds) {
+			dao.logger.WithError(err).Error("Failed to post transfers")
+		}
+		return err
+	}
+
+	for _, walletID := range legWallets(legs) {
+		dao.invalidateBalance(walletID)
+	}
+	return nil
+}
+
+// postTransfers applies legs to locked wallet balances and writes a row on each side.
+// Wallets are locked in ID order so concurrent postings cannot deadlock. A sender may go below
+// zero down to its credit limit.
+func postTransfers(db *gorm.DB, legs []TransferLeg, createdAt time.Time) error {
+	walletIDs := legWallets(legs)
+	wallets, err := lockWallets(db, walletIDs)
+	if err != nil {
+		return err
+	}
+
+	balances := make(map[string]float64, len(wallets))
+	creditLimits := make(map[string]float64, len(wallets))
+	for _, wallet := range wallets {
+		balances[wallet.ID] = wallet.Balance
+		creditLimits[wallet.ID] = wallet.CreditLimit
+	}
+
+	transactions := make([]Transaction, 0, 2*len(legs))
+	for _, leg := range legs {
+		fromAfter := common.RoundToNDecimals(balances[leg.FromWalletID]-leg.Amount, 4)
+		if fromAfter < -creditLimits[leg.FromWalletID] {
+			return ErrInsufficientFunds
+		}
+		toAfter := common.RoundToNDecimals(balances[leg.ToWalletID]+leg.Amount, 4)
+		balances[leg.FromWalletID] = fromAfter
+		balances[leg.ToWalletID] = toAfter
+
+		from, to := leg.FromWalletID, leg.ToWalletID
+		txType := leg.Type
+		if txType == "" {
+			txType = common.TransactionTypeTransfer
+		}
+		transactions = append(transactions,
+			Transaction{ID: leg.DebitID, WalletID: from, Type: txType, Amount: -leg.Amount,
+				RelatedUserID: &to, BalanceAfter: &fromAfter, ParentTransactionID: leg.ParentID, InitiatedBy: leg.InitiatedBy,
+				GroupID: leg.GroupID, CreatedAt: createdAt},
+			Transaction{ID: leg.CreditID, WalletID: to, Type: txType, Amount: leg.Amount,
+				RelatedUserID: &from, BalanceAfter: &toAfter, ParentTransactionID: leg.ParentID, GroupID: leg.GroupID,
+				CreatedAt: createdAt},
+		)
+	}
+
+	for _, walletID := range walletIDs {
+		if err := db.Table("wallets").Where("id = ?", walletID).
+			Update("balance", balances[walletID]).Error; err != nil {
+			return err
+		}
+	}
+	return db.Table("transactions").Create(&transactions).Error
+}
+
+// lockWallets locks walletIDs for update in ID order. It returns ErrWalletNotFound when any of
+// them does not exist.
+func lockWallets(db *gorm.DB, walletIDs []string) ([]Wallet, error) {
+	var wallets []Wallet
+	if err := db.Table("wallets").Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", walletIDs).Order("id").Find(&wallets).Error; err != nil {
+		return nil, err
+	}
+	if len(wallets) != len(walletIDs) {
+		return nil, ErrWalletNotFound
+	}
+	return wallets, nil
+}
+
+// legWallets returns the distinct wallets of legs in ID order.
+func legWallets(legs []TransferLeg) []string {
+	seen := map[string]bool{}
+	var ids []string
+	for _, leg := range legs {
+		for _, id := range []string{leg.FromWalletID, leg.ToWalletID} {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,245 @@
+package dao
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTransferBatch(t *testing.T) {
+	insertBatch := regexp.QuoteMeta(`INSERT INTO "transfer_batches"`) + `.*` + regexp.QuoteMeta(`ON CONFLICT ("idempotency_key") DO NOTHING`)
+	batch := &TransferBatch{ID: "batch-1", FromWalletID: "wallet-1", IdempotencyKey: "key-1", ItemCount: 1}
+	items := []TransferBatchItem{{ID: "item-1", BatchID: "batch-1", Seq: 1, ToWalletID: "wallet-2", Amount: 10}}
+
+	t.Run("new", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(insertBatch).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transfer_batch_items"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.CreateTransferBatch(batch, items))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("repeated idempotency key", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(insertBatch).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectRollback()
+
+		assert.ErrorIs(t, dao.CreateTransferBatch(batch, items), ErrTransferBatchExists)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestExecuteTransferBatch(t *testing.T) {
+	lockWallets := regexp.QuoteMeta(`SELECT * FROM "wallets" WHERE id IN ($1,$2,$3) ORDER BY id FOR UPDATE`)
+	now := time.Now()
+	legs := []TransferLeg{
+		{FromWalletID: "wallet-1", ToWalletID: "wallet-2", Amount: 30, DebitID: "tx-1", CreditID: "tx-2"},
+		{FromWalletID: "wallet-1", ToWalletID: "wallet-3", Amount: 50, DebitID: "tx-3", CreditID: "tx-4"},
+	}
+	newBatch := func() *TransferBatch {
+		return &TransferBatch{ID: "batch-1", Status: "completed", SucceededCount: 2, UpdatedAt: now, CompletedAt: &now}
+	}
+
+	t.Run("posts every leg", func(t *testing.T) {
+		dao, dbMock, redisMock := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(lockWallets).WithArgs("wallet-1", "wallet-2", "wallet-3").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).
+				AddRow("wallet-1", 100.0).AddRow("wallet-2", 5.0).AddRow("wallet-3", 0.0))
+		for _, update := range []struct {
+			walletID string
+			balance  float64
+		}{{"wallet-1", 20}, {"wallet-2", 35}, {"wallet-3", 50}} {
+			dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets" SET "balance"=$1 WHERE id = $2`)).
+				WithArgs(update.balance, update.walletID).WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 4))
+		for _, item := range []struct{ itemID, txID string }{{"item-1", "tx-1"}, {"item-2", "tx-3"}} {
+			dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "transfer_batch_items" SET "status"=$1,"transaction_id"=$2,"updated_at"=$3 WHERE id = $4`)).
+				WithArgs("succeeded", item.txID, now, item.itemID).WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "transfer_batches" SET "completed_at"=$1,"error"=$2,"failed_count"=$3,"status"=$4,"succeeded_count"=$5,"updated_at"=$6 WHERE id = $7 AND status = $8`)).
+			WithArgs(&now, nil, 0, "completed", 2, now, "batch-1", "processing").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+		for _, walletID := range []string{"wallet-1", "wallet-2", "wallet-3"} {
+			redisMock.ExpectDel("wallet_balance:" + walletID).SetVal(1)
+		}
+
+		assert.NoError(t, dao.ExecuteTransferBatch(newBatch(), []string{"item-1", "item-2"}, legs))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("insufficient funds posts nothing", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(lockWallets).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).
+				AddRow("wallet-1", 60.0).AddRow("wallet-2", 0.0).AddRow("wallet-3", 0.0))
+		dbMock.ExpectRollback()
+
+		err := dao.ExecuteTransferBatch(newBatch(), []string{"item-1", "item-2"}, legs)
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown wallet", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(lockWallets).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 100.0).AddRow("wallet-2", 0.0))
+		dbMock.ExpectRollback()
+
+		err := dao.ExecuteTransferBatch(newBatch(), []string{"item-1", "item-2"}, legs)
+		assert.ErrorIs(t, err, ErrWalletNotFound)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestFinishTransferBatch(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	now := time.Now()
+	reason := "insufficient balance"
+	batch := &TransferBatch{ID: "batch-1", Status: "failed", Error: &reason, UpdatedAt: now, CompletedAt: &now}
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "transfer_batch_items" SET "status"=$1,"updated_at"=$2 WHERE batch_id = $3 AND status = $4`)).
+		WithArgs("skipped", now, "batch-1", "pending").WillReturnResult(sqlmock.NewResult(0, 3))
+	dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "transfer_batches"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectCommit()
+
+	assert.NoError(t, dao.FinishTransferBatch(batch))
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestPostTransfers(t *testing.T) {
+	dao, dbMock, redisMock := setupTest(t)
+	parentID, initiator, groupID := "tx-0", "user-1", "group-1"
+	leg := TransferLeg{FromWalletID: "wallet-1", ToWalletID: "wallet-fees", Amount: 2, DebitID: "tx-1", CreditID: "tx-2",
+		Type: "fee", ParentID: &parentID, InitiatedBy: &initiator, GroupID: &groupID}
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallets" WHERE id IN ($1,$2) ORDER BY id FOR UPDATE`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 10.0).AddRow("wallet-fees", 0.0))
+	dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WithArgs(8.0, "wallet-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WithArgs(2.0, "wallet-fees").WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).
+		WithArgs("tx-1", "wallet-1", "fee", -2.0, "wallet-fees", 8.0, &parentID, &initiator, &groupID, sqlmock.AnyArg(),
+			"tx-2", "wallet-fees", "fee", 2.0, "wallet-1", 2.0, &parentID, nil, &groupID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	dbMock.ExpectCommit()
+	redisMock.ExpectDel("wallet_balance:wallet-1").SetVal(1)
+	redisMock.ExpectDel("wallet_balance:wallet-fees").SetVal(1)
+
+	assert.NoError(t, dao.PostTransfers([]TransferLeg{leg}))
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+	assert.NoError(t, redisMock.ExpectationsWereMet())
+}
+
+func TestPostTransfersCreditLimit(t *testing.T) {
+	lockWallets := regexp.QuoteMeta(`SELECT * FROM "wallets" WHERE id IN ($1,$2) ORDER BY id FOR UPDATE`)
+	leg := TransferLeg{FromWalletID: "wallet-1", ToWalletID: "wallet-2", Amount: 60, DebitID: "tx-1", CreditID: "tx-2"}
+
+	t.Run("sender goes below zero within its limit", func(t *testing.T) {
+		dao, dbMock, redisMock := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(lockWallets).WillReturnRows(sqlmock.NewRows([]string{"id", "balance", "credit_limit"}).
+			AddRow("wallet-1", 10.0, 50.0).AddRow("wallet-2", 0.0, 0.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WithArgs(-50.0, "wallet-1").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WithArgs(60.0, "wallet-2").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 2))
+		dbMock.ExpectCommit()
+		redisMock.ExpectDel("wallet_balance:wallet-1").SetVal(1)
+		redisMock.ExpectDel("wallet_balance:wallet-2").SetVal(1)
+
+		assert.NoError(t, dao.PostTransfers([]TransferLeg{leg}))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("past the limit", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(lockWallets).WillReturnRows(sqlmock.NewRows([]string{"id", "balance", "credit_limit"}).
+			AddRow("wallet-1", 10.0, 49.0).AddRow("wallet-2", 0.0, 0.0))
+		dbMock.ExpectRollback()
+
+		assert.ErrorIs(t, dao.PostTransfers([]TransferLeg{leg}), ErrInsufficientFunds)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestPostTransferBatchItem(t *testing.T) {
+	lockWallets := regexp.QuoteMeta(`SELECT * FROM "wallets" WHERE id IN ($1,$2) ORDER BY id FOR UPDATE`)
+	selectItem := regexp.QuoteMeta(`SELECT * FROM "transfer_batch_items" WHERE id = $1 AND status = $2`)
+	leg := TransferLeg{FromWalletID: "wallet-1", ToWalletID: "wallet-2", Amount: 30, DebitID: "tx-1", CreditID: "tx-2"}
+	expectPosting := func(dbMock sqlmock.Sqlmock) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(lockWallets).WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).
+			AddRow("wallet-1", 100.0).AddRow("wallet-2", 0.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WithArgs(70.0, "wallet-1").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WithArgs(30.0, "wallet-2").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 2))
+	}
+
+	t.Run("posts the transfer with the item outcome", func(t *testing.T) {
+		dao, dbMock, redisMock := setupTest(t)
+		expectPosting(dbMock)
+		dbMock.ExpectQuery(selectItem).WithArgs("item-1", "pending", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "batch_id", "status"}).AddRow("item-1", "batch-1", "pending"))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "transfer_batch_items" SET "error"=$1,"status"=$2,"transaction_id"=$3,"updated_at"=$4 WHERE id = $5`)).
+			WithArgs(nil, "succeeded", "tx-1", sqlmock.AnyArg(), "item-1").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+		redisMock.ExpectDel("wallet_balance:wallet-1").SetVal(1)
+		redisMock.ExpectDel("wallet_balance:wallet-2").SetVal(1)
+
+		assert.NoError(t, dao.PostTransferBatchItem("item-1", []TransferLeg{leg}))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("item no longer pending posts nothing", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		expectPosting(dbMock)
+		dbMock.ExpectQuery(selectItem).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		dbMock.ExpectRollback()
+
+		assert.ErrorIs(t, dao.PostTransferBatchItem("item-1", []TransferLeg{leg}), ErrBatchItemNotPending)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestFailReviewedTransferBatchItem(t *testing.T) {
+	selectItem := regexp.QuoteMeta(`SELECT * FROM "transfer_batch_items" WHERE review_id = $1 AND status = $2`)
+
+	t.Run("fails the item and counts it on its batch", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(selectItem).WithArgs("review-1", "review", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "batch_id", "status", "review_id"}).AddRow("item-1", "batch-1", "review", "review-1"))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "transfer_batch_items"`)).
+			WithArgs("rejected", "failed", nil, sqlmock.AnyArg(), "item-1").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "transfer_batches" SET "failed_count"=failed_count + 1,"status"=CASE WHEN failed_count + 1 >= item_count THEN $1 ELSE $2 END,"updated_at"=$3 WHERE id = $4 AND status <> $5`)).
+			WithArgs("failed", "partially_completed", sqlmock.AnyArg(), "batch-1", "processing").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.FailReviewedTransferBatchItem("review-1", "rejected"))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("review without a batch item", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(selectItem).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		dbMock.ExpectCommit()
+
+		assert.NoError(t, dao.FailReviewedTransferBatchItem("review-1", "rejected"))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,111 @@
+package dao
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+)
+
+func (dao *WalletDao) GetUserByID(userID string) (*User, error) {
+	dao.logger.Infof("Fetching user by ID: %s", userID)
+
+	var user User
+	result := dao.db.Table("users").Where("id = ?", userID).First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			dao.logger.Warnf("User not found: %s", userID)
+			return nil, ErrUserNotFound
+		}
+		dao.logger.WithError(result.Error).Error("Failed to fetch user")
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
+
+func (dao *WalletDao) GetUserByWalletID(walletID string) (*User, error) {
+	dao.logger.Infof("Fetching owner of wallet: %s", walletID)
+
+	var user User
+	result := dao.db.Table("users").
+		Select("users.*").
+		Joins("JOIN wallets ON wallets.user_id = users.id").
+		Where("wallets.id = ?", walletID).
+		First(&user)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			dao.logger.Warnf("Owner not found for wallet: %s", walletID)
+			return nil, ErrWalletNotFound
+		}
+		dao.logger.WithError(result.Error).Error("Failed to fetch wallet owner")
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
+
+// UpgradeKycLevel sets the user's new KYC level and records the upgrade with its evidence in one DB transaction.
+func (dao *WalletDao) UpgradeKycLevel(upgrade *KycUpgrade) error {
+	dao.logger.Infof("Upgrading KYC level for user %s: %s -> %s", upgrade.UserID, upgrade.FromLevel, upgrade.ToLevel)
+
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("users").Where("id = ?", upgrade.UserID).Update("kyc_level", upgrade.ToLevel)
+		if result.Error != nil {
+			dao.logger.WithError(result.Error).Error("Failed to update KYC level")
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			dao.logger.Warnf("No user found for KYC upgrade: %s", upgrade.UserID)
+			return ErrUserNotFound
+		}
+
+		if err := tx.Table("kyc_upgrades").Create(upgrade).Error; err != nil {
+			dao.logger.WithError(err).Error("Failed to record KYC upgrade")
+			return err
+		}
+		return nil
+	})
+}
+
+// CreateUserWithWallet onboards a user together with their first wallet, which they own, in one
+// DB transaction.
+func (dao *WalletDao) CreateUserWithWallet(user *User, wallet *Wallet) error {
+	dao.logger.Infof("Creating user %s with wallet %s", user.ID, wallet.ID)
+
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("users").Create(user).Error; err != nil {
+			dao.logger.WithError(err).Error("Failed to create user")
+			return err
+		}
+		if err := tx.Table("wallets").Create(wallet).Error; err != nil {
+			dao.logger.WithError(err).Error("Failed to create wallet")
+			return err
+		}
+		if err := tx.Table("wallet_members").Create(ownerMember(wallet)).Error; err != nil {
+			dao.logger.WithError(err).Error("Failed to add wallet owner")
+			return err
+		}
+		return nil
+	})
+}
+
+// SaveUserScreening stores the latest sanctions screening result of a user.
+func (dao *WalletDao) SaveUserScreening(screening *UserScreening) error {
+	return dao.db.Table("user_screenings").Save(screening).Error
+}
+
+func (dao *WalletDao) FreezeUserWallets(userID string) error {
+	dao.logger.Warnf("Freezing all wallets of user %s", userID)
+
+	err := dao.db.Table("wallets").Where("user_id = ?", userID).Update("status", common.WalletStatusFrozen).Error
+	if err != nil {
+		dao.logger.WithError(err).Error("Failed to freeze user wallets")
+	}
+	return err
+}
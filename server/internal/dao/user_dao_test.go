@@ -0,0 +1,141 @@
+package dao
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestGetUserByWalletID(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	walletID := "wallet-123"
+	query := `SELECT users\.\* FROM "users" JOIN wallets ON wallets\.user_id = users\.id WHERE wallets\.id = \$1 ORDER BY "users"\."id" LIMIT \$2`
+
+	t.Run("owner exists", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "name", "email", "kyc_level", "created_at"}).
+			AddRow("user-123", "Alice", "alice@example.com", "basic", time.Now())
+		dbMock.ExpectQuery(query).WithArgs(walletID, 1).WillReturnRows(rows)
+
+		user, err := dao.GetUserByWalletID(walletID)
+		assert.NoError(t, err)
+		assert.Equal(t, "basic", user.KycLevel)
+	})
+
+	t.Run("wallet not found", func(t *testing.T) {
+		dbMock.ExpectQuery(query).WithArgs(walletID, 1).WillReturnError(gorm.ErrRecordNotFound)
+
+		user, err := dao.GetUserByWalletID(walletID)
+		assert.ErrorIs(t, err, ErrWalletNotFound)
+		assert.Nil(t, user)
+	})
+}
+
+func TestUpgradeKycLevel(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	upgrade := &KycUpgrade{
+		ID:           "upgrade-1",
+		UserID:       "user-123",
+		FromLevel:    "unverified",
+		ToLevel:      "basic",
+		EvidenceRefs: `["passport-123"]`,
+		ApprovedBy:   "operator-1",
+		CreatedAt:    time.Now(),
+	}
+
+	t.Run("successful upgrade", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(`UPDATE "users" SET "kyc_level"=\$1 WHERE id = \$2`).
+			WithArgs("basic", "user-123").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "kyc_upgrades"`)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectCommit()
+
+		err := dao.UpgradeKycLevel(upgrade)
+		assert.NoError(t, err)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(`UPDATE "users" SET "kyc_level"=\$1 WHERE id = \$2`).
+			WithArgs("basic", "user-123").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectRollback()
+
+		err := dao.UpgradeKycLevel(upgrade)
+		assert.ErrorIs(t, err, ErrUserNotFound)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("insert failure rolls back", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(`UPDATE "users" SET "kyc_level"=\$1 WHERE id = \$2`).
+			WithArgs("basic", "user-123").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "kyc_upgrades"`)).
+			WillReturnError(errors.New("insert failed"))
+		dbMock.ExpectRollback()
+
+		err := dao.UpgradeKycLevel(upgrade)
+		assert.Error(t, err)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateUserWithWallet(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	now := time.Now()
+	user := &User{ID: "user-1", Name: "Alice", Email: "alice@example.com", KycLevel: "unverified", CreatedAt: now}
+	wallet := &Wallet{ID: "wallet-1", UserID: "user-1", Balance: 0, Status: "active", Type: "standard", CreatedAt: now}
+
+	t.Run("user and wallet created together", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "users"`)).
+			WithArgs("user-1", "Alice", "alice@example.com", "unverified", now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallets"`)).
+			WithArgs("wallet-1", "user-1", 0.0, "active", "standard", nil, 0.0, nil, nil, nil, nil, now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallet_members"`)).
+			WithArgs("wallet-1", "user-1", "owner", nil, "active", "", now, now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectCommit()
+
+		err := dao.CreateUserWithWallet(user, wallet)
+		assert.NoError(t, err)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("wallet failure rolls back user", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "users"`)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "wallets"`)).
+			WillReturnError(errors.New("insert failed"))
+		dbMock.ExpectRollback()
+
+		err := dao.CreateUserWithWallet(user, wallet)
+		assert.Error(t, err)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestFreezeUserWallets(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(`UPDATE "wallets" SET "status"=\$1 WHERE user_id = \$2`).
+		WithArgs("frozen", "user-1").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	dbMock.ExpectCommit()
+
+	err := dao.FreezeUserWallets("user-1")
+	assert.NoError(t, err)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
@@ -9,7 +9,9 @@ import (
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"github.com/julkhong/walletapp/server/internal/common"
 	"github.com/julkhong/walletapp/server/internal/config"
 	"github.com/sirupsen/logrus"
 )
@@ -54,9 +56,34 @@ func (dao *WalletDao) GetWalletByID(walletID string) (*Wallet, error) {
 	return &wallet, nil
 }
 
+// CreateWallet stores a wallet and makes its user the owner in one DB transaction. Pockets have
+// no members of their own; they share those of their parent.
 func (dao *WalletDao) CreateWallet(wallet *Wallet) error {
 	dao.logger.Infof("Creating wallet for user ID: %s", wallet.UserID)
-	return dao.db.Table("wallets").Create(wallet).Error
+	return dao.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("wallets").Create(wallet).Error; err != nil {
+			return err
+		}
+		if wallet.Type == common.WalletTypePocket {
+			return nil
+		}
+		return tx.Table("wallet_members").Create(ownerMember(wallet)).Error
+	})
+}
+
+// SetCreditLimit sets how far below zero a wallet's balance may go.
+func (dao *WalletDao) SetCreditLimit(walletID string, limit float64) error {
+	dao.logger.Infof("Setting credit limit of wallet %s to %.4f", walletID, limit)
+
+	result := dao.db.Table("wallets").Where("id = ?", walletID).Update("credit_limit", limit)
+	if result.Error != nil {
+		dao.logger.WithError(result.Error).Error("Failed to set credit limit")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWalletNotFound
+	}
+	return nil
 }
 
 func (dao *WalletDao) UpdateBalance(input *UpdateBalance) error {
@@ -90,6 +117,15 @@ func (dao *WalletDao) UpdateBalance(input *UpdateBalance) error {
 	return nil
 }
 
+// invalidateBalance drops the cached balance of a wallet whose balance was changed in a DB
+// transaction rather than through UpdateBalance.
+func (dao *WalletDao) invalidateBalance(walletID string) {
+	key := fmt.Sprintf("wallet_balance:%s", walletID)
+	if err := dao.cfg.Redis.Del(context.Background(), key).Err(); err != nil {
+		dao.logger.WithError(err).Warnf("Failed to delete Redis cache for wallet %s", walletID)
+	}
+}
+
 func (dao *WalletDao) GetBalance(walletID string) (float64, error) {
 	dao.logger.Infof("Getting balance for wallet ID: %s", walletID)
 
@@ -124,22 +160,28 @@ func (dao *WalletDao) GetBalance(walletID string) (float64, error) {
 	return wallet.Balance, nil
 }
 
-func (dao *WalletDao) GetTransactionHistory(walletID string, txType string, start, end string, limit, offset int) ([]Transaction, error) {
-	query := dao.db.Table("transactions").Where("wallet_id = ?", walletID)
+// GetTransactionHistory returns one page of history in (created_at, id) order, newest first
+// unless filter.Ascending is set. filter.After continues from the last row of the previous page.
+func (dao *WalletDao) GetTransactionHistory(filter TransactionFilter) ([]Transaction, error) {
+	query := dao.filterTransactions(filter)
 
-	if txType != "" {
-		query = query.Where("type = ?", txType)
+	direction := "DESC"
+	if filter.Ascending {
+		direction = "ASC"
 	}
-	if start != "" && end != "" {
-		query = query.Where("created_at BETWEEN ? AND ?", start, end)
+	if filter.After != nil {
+		if filter.Ascending {
+			query = query.Where("(created_at, id) > (?, ?)", filter.After.CreatedAt, filter.After.ID)
+		} else {
+			query = query.Where("(created_at, id) < (?, ?)", filter.After.CreatedAt, filter.After.ID)
+		}
 	}
-
-	if limit > 0 {
-		query = query.Limit(limit).Offset(offset)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
 	}
 
 	var txs []Transaction
-	err := query.Order("created_at DESC").Find(&txs).Error
+	err := query.Order("created_at " + direction).Order("id " + direction).Find(&txs).Error
 	if err != nil {
 		dao.logger.WithError(err).Error("Failed to fetch transaction history")
 		return nil, err
@@ -147,7 +189,131 @@ func (dao *WalletDao) GetTransactionHistory(walletID string, txType string, star
 	return txs, nil
 }
 
+// StreamTransactionHistory walks every transaction matching the filter, ignoring its paging
+// fields, and passes each row to fn as it is read from the DB cursor. An error from fn stops the walk.
+func (dao *WalletDao) StreamTransactionHistory(filter TransactionFilter, fn func(*Transaction) error) error {
+	direction := "DESC"
+	if filter.Ascending {
+		direction = "ASC"
+	}
+
+	query := dao.filterTransactions(filter).Order("created_at " + direction).Order("id " + direction)
+	rows, err := query.Rows()
+	if err != nil {
+		dao.logger.WithError(err).Error("Failed to open transaction cursor")
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tx Transaction
+		if err := query.ScanRows(rows, &tx); err != nil {
+			dao.logger.WithError(err).Error("Failed to scan transaction")
+			return err
+		}
+		if err := fn(&tx); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// filterTransactions applies the non-paging parts of a filter.
+func (dao *WalletDao) filterTransactions(filter TransactionFilter) *gorm.DB {
+	query := dao.db.Table("transactions").Where("wallet_id = ?", filter.WalletID)
+
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Start != nil {
+		query = query.Where("created_at >= ?", *filter.Start)
+	}
+	if filter.End != nil {
+		query = query.Where("created_at <= ?", *filter.End)
+	}
+	if filter.MinAmount != nil {
+		query = query.Where("ABS(amount) >= ?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		query = query.Where("ABS(amount) <= ?", *filter.MaxAmount)
+	}
+	if filter.Counterparty != "" {
+		query = query.Where("related_user_id = ?", filter.Counterparty)
+	}
+	return query
+}
+
 func (dao *WalletDao) CreateTransaction(tx *Transaction) error {
 	dao.logger.Infof("Creating transaction for wallet %s, type: %s", tx.WalletID, tx.Type)
 	return dao.db.Table("transactions").Create(tx).Error
 }
+
+// PostWithdrawal debits a withdrawal and its fee and records its payout or provider operation in
+// one DB transaction. Nothing is posted when the wallet would go past its credit limit, in which
+// case ErrInsufficientFunds is returned, or when the provider operation already exists, in which
+// case ErrProviderOperationExists is returned.
+func (dao *WalletDao) PostWithdrawal(w Withdrawal) error {
+	debit := w.Debit
+	dao.logger.Infof("Posting withdrawal %s of %.4f from wallet %s", debit.ID, debit.Amount, debit.WalletID)
+
+	walletIDs := []string{debit.WalletID}
+	if w.Fee != nil {
+		walletIDs = legWallets([]TransferLeg{*w.Fee})
+	}
+	err := dao.db.Transaction(func(db *gorm.DB) error {
+		wallets, err := lockWallets(db, walletIDs)
+		if err != nil {
+			return err
+		}
+		var wallet Wallet
+		for _, locked := range wallets {
+			if locked.ID == debit.WalletID {
+				wallet = locked
+			}
+		}
+
+		balanceAfter := common.RoundToNDecimals(wallet.Balance-debit.Amount, 4)
+		if balanceAfter < -wallet.CreditLimit {
+			return ErrInsufficientFunds
+		}
+		if err := db.Table("wallets").Where("id = ?", debit.WalletID).
+			Update("balance", balanceAfter).Error; err != nil {
+			return err
+		}
+		debit.BalanceAfter = &balanceAfter
+		if err := db.Table("transactions").Create(debit).Error; err != nil {
+			return err
+		}
+		if w.Payout != nil {
+			if err := db.Table("payouts").Create(w.Payout).Error; err != nil {
+				return err
+			}
+		}
+		if w.Operation != nil {
+			result := db.Table("provider_operations").Clauses(clause.OnConflict{DoNothing: true}).Create(w.Operation)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrProviderOperationExists
+			}
+		}
+
+		if w.Fee == nil {
+			return nil
+		}
+		return postTransfers(db, []TransferLeg{*w.Fee}, debit.CreatedAt)
+	})
+	if err != nil {
+		debit.BalanceAfter = nil
+		if !errors.Is(err, ErrInsufficientFunds) {
+			dao.logger.WithError(err).Error("Failed to post withdrawal")
+		}
+		return err
+	}
+
+	for _, walletID := range walletIDs {
+		dao.invalidateBalance(walletID)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package dao
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"testing"
 	"time"
 
@@ -128,6 +129,22 @@ func TestUpdateBalance(t *testing.T) {
 	})
 }
 
+func TestSetCreditLimit(t *testing.T) {
+	dao, dbMock, _ := setupTest(t)
+	update := regexp.QuoteMeta(`UPDATE "wallets" SET "credit_limit"=$1 WHERE id = $2`)
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(update).WithArgs(250.0, "wallet-123").WillReturnResult(sqlmock.NewResult(0, 1))
+	dbMock.ExpectCommit()
+	assert.NoError(t, dao.SetCreditLimit("wallet-123", 250))
+
+	dbMock.ExpectBegin()
+	dbMock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 0))
+	dbMock.ExpectCommit()
+	assert.ErrorIs(t, dao.SetCreditLimit("missing", 250), ErrWalletNotFound)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
 func TestGetBalance(t *testing.T) {
 	dao, dbMock, redisMock := setupTest(t)
 	walletID := "wallet-456"
@@ -163,3 +180,205 @@ func TestGetBalance(t *testing.T) {
 		assert.Equal(t, 0.0, balance)
 	})
 }
+
+func TestGetTransactionHistory(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	after := &TransactionCursor{CreatedAt: start.Add(time.Hour), ID: "tx-9"}
+	minAmount := 10.0
+
+	t.Run("newest first after cursor", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "transactions" WHERE wallet_id = $1 AND created_at >= $2 AND ABS(amount) >= $3 AND related_user_id = $4 AND (created_at, id) < ($5, $6) ORDER BY created_at DESC,id DESC LIMIT $7`)).
+			WithArgs("wallet-1", start, minAmount, "wallet-2", after.CreatedAt, "tx-9", 21).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "wallet_id"}).AddRow("tx-8", "wallet-1"))
+
+		txs, err := dao.GetTransactionHistory(TransactionFilter{
+			WalletID:     "wallet-1",
+			Start:        &start,
+			MinAmount:    &minAmount,
+			Counterparty: "wallet-2",
+			After:        after,
+			Limit:        21,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, txs, 1)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("oldest first", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "transactions" WHERE wallet_id = $1 AND type = $2 AND created_at <= $3 AND (created_at, id) > ($4, $5) ORDER BY created_at ASC,id ASC LIMIT $6`)).
+			WithArgs("wallet-1", "deposit", start, after.CreatedAt, "tx-9", 5).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := dao.GetTransactionHistory(TransactionFilter{
+			WalletID:  "wallet-1",
+			Type:      "deposit",
+			End:       &start,
+			Ascending: true,
+			After:     after,
+			Limit:     5,
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestStreamTransactionHistory(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta(`SELECT * FROM "transactions" WHERE wallet_id = $1 AND created_at >= $2 ORDER BY created_at ASC,id ASC`)
+
+	t.Run("rows passed in order without paging", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectQuery(query).
+			WithArgs("wallet-1", start).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "wallet_id", "amount"}).
+				AddRow("tx-1", "wallet-1", 10.0).
+				AddRow("tx-2", "wallet-1", 20.0))
+
+		var ids []string
+		err := dao.StreamTransactionHistory(TransactionFilter{
+			WalletID:  "wallet-1",
+			Start:     &start,
+			Ascending: true,
+			Limit:     5,
+		}, func(tx *Transaction) error {
+			ids = append(ids, tx.ID)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"tx-1", "tx-2"}, ids)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("callback error stops the walk", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		dbMock.ExpectQuery(query).
+			WithArgs("wallet-1", start).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("tx-1").AddRow("tx-2"))
+
+		calls := 0
+		err := dao.StreamTransactionHistory(TransactionFilter{WalletID: "wallet-1", Start: &start, Ascending: true},
+			func(tx *Transaction) error {
+				calls++
+				return errors.New("client gone")
+			})
+		assert.EqualError(t, err, "client gone")
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestPostWithdrawal(t *testing.T) {
+	lockWallets := regexp.QuoteMeta(`SELECT * FROM "wallets" WHERE id IN ($1,$2) ORDER BY id FOR UPDATE`)
+
+	t.Run("debit and fee are posted together", func(t *testing.T) {
+		dao, dbMock, redisMock := setupTest(t)
+		debit := &Transaction{ID: "tx-1", WalletID: "wallet-1", Type: "withdraw", Amount: 20, CreatedAt: time.Now()}
+		parentID := "tx-1"
+		fee := &TransferLeg{FromWalletID: "wallet-1", ToWalletID: "wallet-fees", Amount: 2, DebitID: "tx-2", CreditID: "tx-3",
+			Type: "fee", ParentID: &parentID}
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(lockWallets).WithArgs("wallet-1", "wallet-fees").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 30.0).AddRow("wallet-fees", 0.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets" SET "balance"=$1 WHERE id = $2`)).WithArgs(10.0, "wallet-1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(lockWallets).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 10.0).AddRow("wallet-fees", 0.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WithArgs(8.0, "wallet-1").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WithArgs(2.0, "wallet-fees").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 2))
+		dbMock.ExpectCommit()
+		redisMock.ExpectDel("wallet_balance:wallet-1").SetVal(1)
+		redisMock.ExpectDel("wallet_balance:wallet-fees").SetVal(1)
+
+		assert.NoError(t, dao.PostWithdrawal(Withdrawal{Debit: debit, Fee: fee}))
+		assert.Equal(t, 10.0, *debit.BalanceAfter)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+		assert.NoError(t, redisMock.ExpectationsWereMet())
+	})
+
+	t.Run("past the credit limit", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		debit := &Transaction{ID: "tx-1", WalletID: "wallet-1", Type: "withdraw", Amount: 60, CreatedAt: time.Now()}
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallets" WHERE id IN ($1) ORDER BY id FOR UPDATE`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance", "credit_limit"}).AddRow("wallet-1", 10.0, 49.0))
+		dbMock.ExpectRollback()
+
+		assert.ErrorIs(t, dao.PostWithdrawal(Withdrawal{Debit: debit}), ErrInsufficientFunds)
+		assert.Nil(t, debit.BalanceAfter)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("payout is recorded with the debit", func(t *testing.T) {
+		dao, dbMock, redisMock := setupTest(t)
+		debit := &Transaction{ID: "tx-1", WalletID: "wallet-1", Type: "withdraw", Amount: 20, CreatedAt: time.Now()}
+		payout := &Payout{ID: "payout-1", TransactionID: "tx-1", WalletID: "wallet-1", Amount: 20, Status: "pending"}
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallets" WHERE id IN ($1) ORDER BY id FOR UPDATE`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 30.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WithArgs(10.0, "wallet-1").WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "payouts"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectCommit()
+		redisMock.ExpectDel("wallet_balance:wallet-1").SetVal(1)
+
+		assert.NoError(t, dao.PostWithdrawal(Withdrawal{Debit: debit, Payout: payout}))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("failed payout insert rolls back the debit", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		debit := &Transaction{ID: "tx-1", WalletID: "wallet-1", Type: "withdraw", Amount: 20, CreatedAt: time.Now()}
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallets"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 30.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "payouts"`)).WillReturnError(errors.New("insert failed"))
+		dbMock.ExpectRollback()
+
+		assert.Error(t, dao.PostWithdrawal(Withdrawal{Debit: debit, Payout: &Payout{ID: "payout-1"}}))
+		assert.Nil(t, debit.BalanceAfter)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("provider operation already recorded", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		debit := &Transaction{ID: "tx-1", WalletID: "wallet-1", Type: "withdraw", Amount: 20, CreatedAt: time.Now()}
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "wallets"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 30.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "provider_operations"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+		dbMock.ExpectRollback()
+
+		err := dao.PostWithdrawal(Withdrawal{Debit: debit, Operation: &ProviderOperation{ID: "op-1", TransactionID: &debit.ID}})
+		assert.ErrorIs(t, err, ErrProviderOperationExists)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("failed fee posting rolls back the debit", func(t *testing.T) {
+		dao, dbMock, _ := setupTest(t)
+		debit := &Transaction{ID: "tx-1", WalletID: "wallet-1", Type: "withdraw", Amount: 20, CreatedAt: time.Now()}
+		fee := &TransferLeg{FromWalletID: "wallet-1", ToWalletID: "wallet-fees", Amount: 2, DebitID: "tx-2", CreditID: "tx-3", Type: "fee"}
+
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(lockWallets).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "balance"}).AddRow("wallet-1", 30.0).AddRow("wallet-fees", 0.0))
+		dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE "wallets"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "transactions"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(lockWallets).WillReturnError(errors.New("lock timeout"))
+		dbMock.ExpectRollback()
+
+		assert.Error(t, dao.PostWithdrawal(Withdrawal{Debit: debit, Fee: fee}))
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
+}
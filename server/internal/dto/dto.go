@@ -1,5 +1,7 @@
 package api
 
+import "time"
+
 // TransferDTO represents a transfer request from one user to another
 type TransferDTO struct {
 	FromWalletID string  `json:"from_wallet_id"`
@@ -13,23 +15,207 @@ type DepositRequest struct {
 
 type WithdrawRequest struct {
 	Amount float64 `json:"amount" binding:"required,gt=0"`
+	// Beneficiary pays the withdrawal out to a bank account.
+	Beneficiary *BeneficiaryRequest `json:"beneficiary,omitempty"`
+	// Provider pays the withdrawal out through an external payment provider.
+	Provider *ProviderRequest `json:"provider,omitempty"`
+}
+
+type ProviderRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Destination string `json:"destination"`
+}
+
+type ProviderWithdrawResponse struct {
+	Message     string `json:"message"`
+	OperationID string `json:"operation_id"`
+	Status      string `json:"status"`
+}
+
+type ProviderCallbackResponse struct {
+	Processed int `json:"processed"`
+}
+
+type BeneficiaryRequest struct {
+	Name string `json:"name" binding:"required"`
+	IBAN string `json:"iban" binding:"required"`
+	BIC  string `json:"bic"`
+}
+
+type PayoutResponse struct {
+	Message  string `json:"message"`
+	PayoutID string `json:"payout_id"`
+	Status   string `json:"status"`
+}
+
+type PayoutStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason"`
 }
 
 type TransferRequest struct {
 	FromWalletID string  `json:"from_wallet_id" binding:"required"`
 	ToWalletID   string  `json:"to_wallet_id" binding:"required"`
 	Amount       float64 `json:"amount" binding:"required,gt=0"`
+	// LinkToken pays a payment link. The link names the payee, so ToWalletID may be left out,
+	// and Amount too when the link fixes it.
+	LinkToken string `json:"link_token,omitempty"`
+}
+
+// TransferBatchRequest is the JSON form of a batch transfer. CSV uploads carry from_wallet_id
+// and mode as query parameters and one item per row.
+type TransferBatchRequest struct {
+	FromWalletID string              `json:"from_wallet_id" binding:"required"`
+	Mode         string              `json:"mode" binding:"required"`
+	Items        []TransferBatchItem `json:"items" binding:"required"`
+}
+
+type TransferBatchItem struct {
+	ToWalletID string  `json:"to_wallet_id" binding:"required"`
+	Amount     float64 `json:"amount" binding:"required,gt=0"`
+	Reference  string  `json:"reference"`
 }
 
 type TransferResponse struct {
 	Message  string  `json:"message"`
 	WalletID string  `json:"wallet_id"`
 	Balance  float64 `json:"balance"`
+	// Fee is charged on top of the transferred amount; it is omitted when fees are not enabled.
+	Fee *FeeBreakdown `json:"fee,omitempty"`
+	// PaymentLinkID is the link the transfer paid, if any.
+	PaymentLinkID string `json:"payment_link_id,omitempty"`
 }
 
+// FeeBreakdown is the fee of a transfer or withdrawal, one component per fee rule. Total is what
+// leaves the paying wallet.
+type FeeBreakdown struct {
+	Amount     float64        `json:"amount"`
+	Fee        float64        `json:"fee"`
+	Total      float64        `json:"total"`
+	Components []FeeComponent `json:"components"`
+}
+
+type FeeComponent struct {
+	Rule   string  `json:"rule"`
+	Type   string  `json:"type"`
+	Amount float64 `json:"amount"`
+	Capped bool    `json:"capped,omitempty"`
+}
+
+// FeeQuoteRequest previews the fee of a transfer or withdrawal out of a wallet.
+type FeeQuoteRequest struct {
+	WalletID        string  `json:"wallet_id"`
+	TransactionType string  `json:"transaction_type"`
+	Amount          float64 `json:"amount"`
+}
+
+type FeeQuoteResponse struct {
+	WalletID        string `json:"wallet_id"`
+	TransactionType string `json:"transaction_type"`
+	KycLevel        string `json:"kyc_level"`
+	FeeBreakdown
+}
+
+// ScheduledTransferRequest creates a recurring transfer out of the wallet in the path.
+// Recurrence is a five-field cron expression or an RRULE, evaluated in UTC.
+type ScheduledTransferRequest struct {
+	ToWalletID string     `json:"to_wallet_id" binding:"required"`
+	Amount     float64    `json:"amount" binding:"required,gt=0"`
+	Recurrence string     `json:"recurrence" binding:"required"`
+	StartAt    *time.Time `json:"start_at"`
+	EndAt      *time.Time `json:"end_at"`
+}
+
+// BalanceResponse carries the wallet's credit line with the current balance only; a past
+// balance is reported without it.
 type BalanceResponse struct {
-	WalletID string  `json:"wallet_id"`
-	Balance  float64 `json:"balance"`
+	WalletID        string     `json:"wallet_id"`
+	Balance         float64    `json:"balance"`
+	CreditLimit     *float64   `json:"credit_limit,omitempty"`
+	AvailableCredit *float64   `json:"available_credit,omitempty"`
+	AsOf            *time.Time `json:"as_of,omitempty"`
+}
+
+// CreatePocketRequest opens a pocket. GoalDate is YYYY-MM-DD and needs a GoalAmount.
+type CreatePocketRequest struct {
+	Name       string   `json:"name"`
+	GoalAmount *float64 `json:"goal_amount,omitempty"`
+	GoalDate   *string  `json:"goal_date,omitempty"`
+}
+
+// PocketGoalRequest sets a pocket's goal; a null goal_amount clears it.
+type PocketGoalRequest struct {
+	GoalAmount *float64 `json:"goal_amount"`
+	GoalDate   *string  `json:"goal_date,omitempty"`
+}
+
+type PocketMoveRequest struct {
+	FromWalletID string  `json:"from_wallet_id"`
+	ToWalletID   string  `json:"to_wallet_id"`
+	Amount       float64 `json:"amount"`
+}
+
+// InviteMemberRequest invites a user to a wallet as owner, spender or viewer. SpendingCap is a
+// spender's monthly limit; without one they may spend freely.
+type InviteMemberRequest struct {
+	UserID      string   `json:"user_id"`
+	Role        string   `json:"role"`
+	SpendingCap *float64 `json:"spending_cap,omitempty"`
+}
+
+// SplitPaymentRequest pays many wallets out of one. Each share has either an amount or a percent
+// of Amount; with fixed amounts Amount may be left out.
+type SplitPaymentRequest struct {
+	FromWalletID string       `json:"from_wallet_id"`
+	Amount       float64      `json:"amount"`
+	Splits       []SplitShare `json:"splits"`
+}
+
+type SplitShare struct {
+	ToWalletID string   `json:"to_wallet_id"`
+	Amount     *float64 `json:"amount,omitempty"`
+	Percent    *float64 `json:"percent,omitempty"`
+}
+
+// EscrowRequest holds a buyer's payment for a seller until delivery is confirmed. Without
+// ExpiresAt the escrow is refunded after the configured timeout.
+type EscrowRequest struct {
+	BuyerWalletID  string     `json:"buyer_wallet_id"`
+	SellerWalletID string     `json:"seller_wallet_id"`
+	Amount         float64    `json:"amount"`
+	Reference      string     `json:"reference"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+}
+
+type EscrowDisputeRequest struct {
+	Reason string `json:"reason"`
+}
+
+// EscrowResolveRequest settles a disputed escrow: release pays the seller, refund the buyer.
+type EscrowResolveRequest struct {
+	Outcome string `json:"outcome"`
+}
+
+// PaymentRequestRequest asks the payer wallet to pay the requester wallet.
+type PaymentRequestRequest struct {
+	RequesterWalletID string     `json:"requester_wallet_id"`
+	PayerWalletID     string     `json:"payer_wallet_id"`
+	Amount            float64    `json:"amount"`
+	Note              string     `json:"note"`
+	ExpiresAt         *time.Time `json:"expires_at"`
+}
+
+// PaymentLinkRequest creates a link paying WalletID. Without an amount the payer chooses it.
+type PaymentLinkRequest struct {
+	WalletID  string     `json:"wallet_id"`
+	Amount    *float64   `json:"amount"`
+	Reference string     `json:"reference"`
+	SingleUse bool       `json:"single_use"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type CreditLimitRequest struct {
+	CreditLimit float64 `json:"credit_limit"`
 }
 
 type SuccessResponse struct {
@@ -41,6 +227,58 @@ type TransactionHistoryResponse struct {
 	Transactions []string `json:"transactions"`
 }
 
+type KycUpgradeRequest struct {
+	Level        string   `json:"level" binding:"required"`
+	EvidenceRefs []string `json:"evidence_refs" binding:"required"`
+}
+
+type KycUpgradeResponse struct {
+	UserID   string `json:"user_id"`
+	KycLevel string `json:"kyc_level"`
+}
+
+type PendingReviewResponse struct {
+	Message  string `json:"message"`
+	ReviewID string `json:"review_id"`
+}
+
+type PendingApprovalResponse struct {
+	Message    string `json:"message"`
+	ApprovalID string `json:"approval_id"`
+}
+
+type AdjustmentRequest struct {
+	Amount     float64 `json:"amount" binding:"required"`
+	ReasonCode string  `json:"reason_code" binding:"required"`
+	Note       string  `json:"note" binding:"required"`
+}
+
+type AdjustmentResponse struct {
+	Message  string  `json:"message"`
+	WalletID string  `json:"wallet_id"`
+	Balance  float64 `json:"balance"`
+}
+
+type CreateUserRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email" binding:"required"`
+}
+
+type CreateUserResponse struct {
+	UserID          string `json:"user_id"`
+	WalletID        string `json:"wallet_id"`
+	WalletStatus    string `json:"wallet_status"`
+	KycLevel        string `json:"kyc_level"`
+	ScreeningStatus string `json:"screening_status,omitempty"`
+}
+
+// CreateWalletRequest opens another wallet for a user. InterestRate is the annual rate of a
+// savings wallet (0.02 for 2%); without it the configured default applies.
+type CreateWalletRequest struct {
+	Type         string   `json:"type"`
+	InterestRate *float64 `json:"interest_rate,omitempty"`
+}
+
 type GenericResponse[T any] struct {
 	Status string `json:"status"`
 	Data   T      `json:"data"`
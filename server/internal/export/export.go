@@ -0,0 +1,143 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatJSONL = "jsonl"
+	FormatOFX   = "ofx"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format, expected csv, jsonl or ofx")
+
+var contentTypes = map[string]string{
+	FormatCSV:   "text/csv; charset=utf-8",
+	FormatJSONL: "application/x-ndjson",
+	FormatOFX:   "application/x-ofx",
+}
+
+// Header describes the account and period an export covers.
+type Header struct {
+	WalletID    string
+	Start       time.Time
+	End         time.Time
+	GeneratedAt time.Time
+}
+
+// Writer encodes transactions one at a time, so an export never holds the full history in memory.
+type Writer interface {
+	Write(tx *dao.Transaction) error
+	// Close writes any trailer and flushes buffered output.
+	Close() error
+}
+
+// NewWriter returns a Writer for format on top of out.
+func NewWriter(format string, out io.Writer, header Header) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(out)
+	case FormatJSONL:
+		return &jsonlWriter{enc: json.NewEncoder(out)}, nil
+	case FormatOFX:
+		return newOFXWriter(out, header), nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// ContentType is the MIME type of an export format.
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// IsSupported reports whether format can be exported.
+func IsSupported(format string) bool {
+	_, ok := contentTypes[format]
+	return ok
+}
+
+// record is the flat row shared by the CSV and JSON Lines exports. Amount is signed, so
+// withdrawals and outgoing transfers are negative.
+type record struct {
+	ID           string    `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	Type         string    `json:"type"`
+	Amount       float64   `json:"amount"`
+	BalanceAfter *float64  `json:"balance_after"`
+	Counterparty *string   `json:"counterparty_wallet_id"`
+}
+
+func newRecord(tx *dao.Transaction) record {
+	return record{
+		ID:           tx.ID,
+		CreatedAt:    tx.CreatedAt.UTC(),
+		Type:         tx.Type,
+		Amount:       common.RoundToNDecimals(tx.SignedAmount(), 4),
+		BalanceAfter: tx.BalanceAfter,
+		Counterparty: tx.RelatedUserID,
+	}
+}
+
+var csvColumns = []string{"id", "created_at", "type", "amount", "balance_after", "counterparty_wallet_id"}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(out io.Writer) (*csvWriter, error) {
+	w := csv.NewWriter(out)
+	if err := w.Write(csvColumns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: w}, nil
+}
+
+func (c *csvWriter) Write(tx *dao.Transaction) error {
+	rec := newRecord(tx)
+	balance, counterparty := "", ""
+	if rec.BalanceAfter != nil {
+		balance = formatAmount(*rec.BalanceAfter)
+	}
+	if rec.Counterparty != nil {
+		counterparty = *rec.Counterparty
+	}
+	return c.w.Write([]string{
+		rec.ID,
+		rec.CreatedAt.Format(time.RFC3339),
+		rec.Type,
+		formatAmount(rec.Amount),
+		balance,
+		counterparty,
+	})
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func (j *jsonlWriter) Write(tx *dao.Transaction) error {
+	return j.enc.Encode(newRecord(tx))
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 4, 64)
+}
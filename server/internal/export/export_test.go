@@ -0,0 +1,88 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+)
+
+func sampleTransactions() []dao.Transaction {
+	counterparty := "wallet-2"
+	first, second := 100.0, 75.5
+	return []dao.Transaction{
+		{ID: "tx-1", WalletID: "wallet-1", Type: "deposit", Amount: 100, BalanceAfter: &first,
+			CreatedAt: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)},
+		{ID: "tx-2", WalletID: "wallet-1", Type: "transfer", Amount: -24.5, RelatedUserID: &counterparty, BalanceAfter: &second,
+			CreatedAt: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)},
+		{ID: "tx-3", WalletID: "wallet-1", Type: "withdraw", Amount: 5,
+			CreatedAt: time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)},
+	}
+}
+
+func render(t *testing.T, format string) string {
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf, Header{
+		WalletID:    "wallet-1",
+		Start:       time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		End:         time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		GeneratedAt: time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	for _, tx := range sampleTransactions() {
+		assert.NoError(t, w.Write(&tx))
+	}
+	assert.NoError(t, w.Close())
+	return buf.String()
+}
+
+func TestCSV(t *testing.T) {
+	assert.Equal(t, strings.Join([]string{
+		"id,created_at,type,amount,balance_after,counterparty_wallet_id",
+		"tx-1,2026-03-01T09:00:00Z,deposit,100.0000,100.0000,",
+		"tx-2,2026-03-02T09:00:00Z,transfer,-24.5000,75.5000,wallet-2",
+		"tx-3,2026-03-03T09:00:00Z,withdraw,-5.0000,,",
+	}, "\n")+"\n", render(t, FormatCSV))
+}
+
+func TestJSONL(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(render(t, FormatJSONL)), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, `{"id":"tx-1","created_at":"2026-03-01T09:00:00Z","type":"deposit","amount":100,"balance_after":100,"counterparty_wallet_id":null}`, lines[0])
+	assert.Contains(t, lines[2], `"amount":-5`)
+}
+
+func TestOFX(t *testing.T) {
+	out := render(t, FormatOFX)
+
+	assert.True(t, strings.HasPrefix(out, `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, out, "<ACCTID>wallet-1</ACCTID>")
+	assert.Contains(t, out, "<DTSTART>20260301000000[0:GMT]</DTSTART><DTEND>20260401000000[0:GMT]</DTEND>")
+	assert.Contains(t, out, "<TRNTYPE>CREDIT</TRNTYPE><DTPOSTED>20260301090000[0:GMT]</DTPOSTED><TRNAMT>100.0000</TRNAMT><FITID>tx-1</FITID>")
+	assert.Contains(t, out, "<TRNTYPE>XFER</TRNTYPE>")
+	assert.Contains(t, out, "<NAME>transfer wallet-2</NAME>")
+	assert.Contains(t, out, "<TRNTYPE>DEBIT</TRNTYPE><DTPOSTED>20260303090000[0:GMT]</DTPOSTED><TRNAMT>-5.0000</TRNAMT>")
+	// tx-3 has no running balance, so the ledger balance is the latest one known
+	assert.Contains(t, out, "<LEDGERBAL><BALAMT>75.5000</BALAMT><DTASOF>20260302090000[0:GMT]</DTASOF></LEDGERBAL>")
+	assert.True(t, strings.HasSuffix(out, "</OFX>\n"))
+}
+
+func TestOFXEscapesText(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatOFX, &buf, Header{WalletID: "a&b"})
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.Contains(t, buf.String(), "<ACCTID>a&amp;b</ACCTID>")
+	assert.NotContains(t, buf.String(), "<LEDGERBAL>")
+}
+
+func TestUnsupportedFormat(t *testing.T) {
+	_, err := NewWriter("xlsx", &bytes.Buffer{}, Header{})
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	assert.False(t, IsSupported("xlsx"))
+	assert.Equal(t, "application/x-ofx", ContentType(FormatOFX))
+}
@@ -0,0 +1,104 @@
+package export
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+)
+
+const (
+	// wallets carry no currency of their own, every balance is held in the platform currency
+	ofxCurrency = "USD"
+	ofxBankID   = "WALLETAPP"
+	ofxDate     = "20060102150405"
+)
+
+// ofxWriter writes an OFX 2.2 bank statement response. The balance reported in LEDGERBAL is the
+// running balance of the latest exported transaction, so it is omitted when no row carries one.
+type ofxWriter struct {
+	w *bufio.Writer
+
+	latest        time.Time
+	latestBalance *float64
+}
+
+func newOFXWriter(out io.Writer, header Header) *ofxWriter {
+	o := &ofxWriter{w: bufio.NewWriter(out)}
+	fmt.Fprint(o.w, xml.Header)
+	fmt.Fprint(o.w, `<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>`+"\n")
+	fmt.Fprint(o.w, "<OFX>\n")
+	fmt.Fprint(o.w, "<SIGNONMSGSRSV1><SONRS>")
+	fmt.Fprint(o.w, "<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>")
+	fmt.Fprintf(o.w, "<DTSERVER>%s</DTSERVER><LANGUAGE>ENG</LANGUAGE>", ofxTime(header.GeneratedAt))
+	fmt.Fprint(o.w, "</SONRS></SIGNONMSGSRSV1>\n")
+	fmt.Fprint(o.w, "<BANKMSGSRSV1><STMTTRNRS><TRNUID>0</TRNUID>")
+	fmt.Fprint(o.w, "<STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>\n")
+	fmt.Fprintf(o.w, "<STMTRS><CURDEF>%s</CURDEF>", ofxCurrency)
+	fmt.Fprintf(o.w, "<BANKACCTFROM><BANKID>%s</BANKID><ACCTID>%s</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>\n",
+		ofxBankID, escape(header.WalletID))
+	fmt.Fprintf(o.w, "<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>\n", ofxTime(header.Start), ofxTime(header.End))
+	return o
+}
+
+func (o *ofxWriter) Write(tx *dao.Transaction) error {
+	amount := tx.SignedAmount()
+	name := tx.Type
+	if tx.RelatedUserID != nil {
+		name = tx.Type + " " + *tx.RelatedUserID
+	}
+
+	_, err := fmt.Fprintf(o.w, "<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>%s</FITID><NAME>%s</NAME></STMTTRN>\n",
+		ofxTransactionType(tx.Type, amount), ofxTime(tx.CreatedAt), formatAmount(common.RoundToNDecimals(amount, 4)),
+		escape(tx.ID), escape(truncate(name, 32)))
+
+	if tx.BalanceAfter != nil && !tx.CreatedAt.Before(o.latest) {
+		o.latest = tx.CreatedAt
+		o.latestBalance = tx.BalanceAfter
+	}
+	return err
+}
+
+func (o *ofxWriter) Close() error {
+	fmt.Fprint(o.w, "</BANKTRANLIST>\n")
+	if o.latestBalance != nil {
+		fmt.Fprintf(o.w, "<LEDGERBAL><BALAMT>%s</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL>\n",
+			formatAmount(*o.latestBalance), ofxTime(o.latest))
+	}
+	fmt.Fprint(o.w, "</STMTRS></STMTTRNRS></BANKMSGSRSV1>\n</OFX>\n")
+	return o.w.Flush()
+}
+
+func ofxTransactionType(txType string, amount float64) string {
+	switch {
+	case txType == common.TransactionTypeTransfer:
+		return "XFER"
+	case amount < 0:
+		return "DEBIT"
+	default:
+		return "CREDIT"
+	}
+}
+
+func ofxTime(t time.Time) string {
+	return t.UTC().Format(ofxDate) + "[0:GMT]"
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// truncate cuts s to the OFX field length limit.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
@@ -0,0 +1,116 @@
+package fees
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+const (
+	RuleTypeFlat       = "flat"
+	RuleTypePercentage = "percentage"
+	RuleTypeTiered     = "tiered"
+)
+
+// Overdraft is the transaction type of rules charged on the part of a transfer or withdrawal that
+// takes the wallet below zero.
+const Overdraft = "overdraft"
+
+// Tier is one amount band of a tiered rule. It applies to amounts up to and including UpTo;
+// the last tier also covers every larger amount and may leave UpTo at zero.
+type Tier struct {
+	UpTo    float64 `json:"up_to"`
+	Flat    float64 `json:"flat"`
+	Percent float64 `json:"percent"`
+}
+
+// Rule is a single fee as defined in the fee schedule file. A rule without a KYC level applies
+// to every tier that has no rule of its own for the transaction type. Min and Max bound the fee
+// the rule charges; zero leaves that side open.
+type Rule struct {
+	Name            string  `json:"name"`
+	TransactionType string  `json:"transaction_type"`
+	KycLevel        string  `json:"kyc_level,omitempty"`
+	Type            string  `json:"type"`
+	Flat            float64 `json:"flat,omitempty"`
+	Percent         float64 `json:"percent,omitempty"`
+	Tiers           []Tier  `json:"tiers,omitempty"`
+	Min             float64 `json:"min,omitempty"`
+	Max             float64 `json:"max,omitempty"`
+}
+
+type ruleFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// LoadRules reads and validates a JSON fee schedule file.
+func LoadRules(path string) ([]Rule, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read fee schedule: %w", err)
+	}
+	return ParseRules(raw)
+}
+
+func ParseRules(raw []byte) ([]Rule, error) {
+	var file ruleFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("parse fee schedule: %w", err)
+	}
+
+	for i := range file.Rules {
+		if err := file.Rules[i].validate(); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", file.Rules[i].Name, err)
+		}
+	}
+	return file.Rules, nil
+}
+
+func (r *Rule) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("missing name")
+	}
+	if r.TransactionType == "" {
+		return fmt.Errorf("missing transaction_type")
+	}
+	if r.Flat < 0 || r.Percent < 0 || r.Min < 0 || r.Max < 0 {
+		return fmt.Errorf("amounts must not be negative")
+	}
+	if r.Max > 0 && r.Min > r.Max {
+		return fmt.Errorf("min is above max")
+	}
+
+	switch r.Type {
+	case RuleTypeFlat:
+		if r.Flat <= 0 {
+			return fmt.Errorf("flat must be positive")
+		}
+	case RuleTypePercentage:
+		if r.Percent <= 0 {
+			return fmt.Errorf("percent must be positive")
+		}
+	case RuleTypeTiered:
+		if len(r.Tiers) == 0 {
+			return fmt.Errorf("missing tiers")
+		}
+		sort.SliceStable(r.Tiers, func(i, j int) bool {
+			// the open-ended tier sorts last
+			if r.Tiers[i].UpTo == 0 || r.Tiers[j].UpTo == 0 {
+				return r.Tiers[j].UpTo == 0 && r.Tiers[i].UpTo != 0
+			}
+			return r.Tiers[i].UpTo < r.Tiers[j].UpTo
+		})
+		for i, tier := range r.Tiers {
+			if tier.Flat < 0 || tier.Percent < 0 || tier.UpTo < 0 {
+				return fmt.Errorf("tier %d: amounts must not be negative", i+1)
+			}
+			if tier.UpTo == 0 && i != len(r.Tiers)-1 {
+				return fmt.Errorf("only one tier may be open-ended")
+			}
+		}
+	default:
+		return fmt.Errorf("unknown rule type %q", r.Type)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package fees
+
+import (
+	"github.com/julkhong/walletapp/server/internal/common"
+)
+
+// Component is the part of a fee charged by one rule.
+type Component struct {
+	Rule   string  `json:"rule"`
+	Type   string  `json:"type"`
+	Amount float64 `json:"amount"`
+	// Capped is set when the rule's min or max changed the computed amount.
+	Capped bool `json:"capped,omitempty"`
+}
+
+// Quote is the fee for moving Amount. Total is what leaves the paying wallet.
+type Quote struct {
+	TransactionType string      `json:"transaction_type"`
+	KycLevel        string      `json:"kyc_level"`
+	Amount          float64     `json:"amount"`
+	Fee             float64     `json:"fee"`
+	Total           float64     `json:"total"`
+	Components      []Component `json:"components"`
+}
+
+// Schedule computes fees from a set of rules.
+type Schedule struct {
+	rules []Rule
+}
+
+func NewSchedule(rules []Rule) *Schedule {
+	return &Schedule{rules: rules}
+}
+
+// Quote returns the fee for a transaction of txType and amount paid by a wallet whose owner has
+// kycLevel. Rules for the KYC level replace the rules without one; all remaining rules for the
+// transaction type are added up.
+func (s *Schedule) Quote(txType, kycLevel string, amount float64) Quote {
+	amount = common.RoundToNDecimals(amount, 4)
+	quote := Quote{
+		TransactionType: txType,
+		KycLevel:        kycLevel,
+		Amount:          amount,
+		Total:           amount,
+		Components:      []Component{},
+	}
+
+	for _, rule := range s.rulesFor(txType, kycLevel) {
+		component := rule.charge(amount)
+		if component.Amount <= 0 {
+			continue
+		}
+		quote.Components = append(quote.Components, component)
+		quote.Fee = common.RoundToNDecimals(quote.Fee+component.Amount, 4)
+	}
+	quote.Total = common.RoundToNDecimals(amount+quote.Fee, 4)
+	return quote
+}
+
+func (s *Schedule) rulesFor(txType, kycLevel string) []Rule {
+	var general, tiered []Rule
+	for _, rule := range s.rules {
+		switch {
+		case rule.TransactionType != txType:
+		case rule.KycLevel == "":
+			general = append(general, rule)
+		case rule.KycLevel == kycLevel:
+			tiered = append(tiered, rule)
+		}
+	}
+	if len(tiered) > 0 {
+		return tiered
+	}
+	return general
+}
+
+func (r *Rule) charge(amount float64) Component {
+	var fee float64
+	switch r.Type {
+	case RuleTypeFlat:
+		fee = r.Flat
+	case RuleTypePercentage:
+		fee = amount * r.Percent / 100
+	case RuleTypeTiered:
+		tier := r.Tiers[len(r.Tiers)-1]
+		for _, t := range r.Tiers {
+			if t.UpTo == 0 || amount <= t.UpTo {
+				tier = t
+				break
+			}
+		}
+		fee = tier.Flat + amount*tier.Percent/100
+	}
+	fee = common.RoundToNDecimals(fee, 4)
+
+	component := Component{Rule: r.Name, Type: r.Type, Amount: fee}
+	if r.Min > 0 && fee < r.Min {
+		component.Amount, component.Capped = r.Min, true
+	}
+	if r.Max > 0 && fee > r.Max {
+		component.Amount, component.Capped = r.Max, true
+	}
+	return component
+}
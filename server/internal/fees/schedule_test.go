@@ -0,0 +1,88 @@
+package fees
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuote(t *testing.T) {
+	schedule := NewSchedule([]Rule{
+		{Name: "transfer_percentage", TransactionType: "transfer", Type: RuleTypePercentage, Percent: 0.5, Min: 0.1, Max: 5},
+		{Name: "transfer_full", TransactionType: "transfer", KycLevel: "full", Type: RuleTypeTiered, Tiers: []Tier{
+			{UpTo: 1000},
+			{UpTo: 10000, Percent: 0.2},
+			{Flat: 2, Percent: 0.1},
+		}, Max: 20},
+		{Name: "withdraw_flat", TransactionType: "withdraw", Type: RuleTypeFlat, Flat: 1},
+		{Name: "withdraw_percentage", TransactionType: "withdraw", Type: RuleTypePercentage, Percent: 0.25, Max: 10},
+	})
+
+	t.Run("percentage", func(t *testing.T) {
+		quote := schedule.Quote("transfer", "basic", 200)
+		assert.Equal(t, 1.0, quote.Fee)
+		assert.Equal(t, 201.0, quote.Total)
+		assert.Equal(t, []Component{{Rule: "transfer_percentage", Type: RuleTypePercentage, Amount: 1}}, quote.Components)
+	})
+
+	t.Run("capped", func(t *testing.T) {
+		quote := schedule.Quote("transfer", "basic", 5000)
+		assert.Equal(t, 5.0, quote.Fee)
+		assert.True(t, quote.Components[0].Capped)
+
+		quote = schedule.Quote("transfer", "basic", 4)
+		assert.Equal(t, 0.1, quote.Fee)
+		assert.True(t, quote.Components[0].Capped)
+	})
+
+	t.Run("tiered rule of the kyc level replaces the general rule", func(t *testing.T) {
+		quote := schedule.Quote("transfer", "full", 500)
+		assert.Equal(t, 0.0, quote.Fee)
+		assert.Empty(t, quote.Components)
+
+		quote = schedule.Quote("transfer", "full", 5000)
+		assert.Equal(t, 10.0, quote.Fee)
+
+		quote = schedule.Quote("transfer", "full", 15000)
+		assert.Equal(t, 17.0, quote.Fee)
+
+		quote = schedule.Quote("transfer", "full", 50000)
+		assert.Equal(t, 20.0, quote.Fee)
+	})
+
+	t.Run("components add up", func(t *testing.T) {
+		quote := schedule.Quote("withdraw", "basic", 100)
+		assert.Equal(t, 1.25, quote.Fee)
+		assert.Equal(t, 101.25, quote.Total)
+		assert.Len(t, quote.Components, 2)
+	})
+
+	t.Run("no rules", func(t *testing.T) {
+		quote := schedule.Quote("deposit", "basic", 100)
+		assert.Equal(t, 0.0, quote.Fee)
+		assert.Equal(t, 100.0, quote.Total)
+	})
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]byte(`{"rules": [{"name": "tiers", "transaction_type": "transfer", "type": "tiered",
+		"tiers": [{"percent": 1}, {"up_to": 100, "flat": 1}]}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, rules[0].Tiers[0].UpTo, "the open-ended tier sorts last")
+
+	for name, raw := range map[string]string{
+		"unknown type":       `{"rules": [{"name": "x", "transaction_type": "transfer", "type": "bonus"}]}`,
+		"missing flat":       `{"rules": [{"name": "x", "transaction_type": "transfer", "type": "flat"}]}`,
+		"min above max":      `{"rules": [{"name": "x", "transaction_type": "transfer", "type": "percentage", "percent": 1, "min": 5, "max": 1}]}`,
+		"two open tiers":     `{"rules": [{"name": "x", "transaction_type": "transfer", "type": "tiered", "tiers": [{"flat": 1}, {"flat": 2}]}]}`,
+		"missing tx type":    `{"rules": [{"name": "x", "type": "flat", "flat": 1}]}`,
+		"negative percent":   `{"rules": [{"name": "x", "transaction_type": "transfer", "type": "percentage", "percent": -1}]}`,
+		"malformed document": `{"rules": [`,
+	} {
+		_, err := ParseRules([]byte(raw))
+		assert.Error(t, err, name)
+	}
+
+	_, err = LoadRules("../../rules/fee_schedule.json")
+	assert.NoError(t, err)
+}
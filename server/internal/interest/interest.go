@@ -0,0 +1,86 @@
+package interest
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DaysPerYear is the actual/365 day count: every day earns 1/365 of the annual rate, leap
+	// years included.
+	DaysPerYear = 365
+	// AccrualPlaces is the precision daily accruals are kept at.
+	AccrualPlaces = 10
+	// PostingPlaces is the precision of posted interest, the ledger's four decimals.
+	PostingPlaces = 4
+)
+
+// Daily returns the interest an end-of-day balance earns in one day at annualRate (0.02 for 2%).
+// It is computed exactly and rounded half away from zero to AccrualPlaces. Balances at or below
+// zero earn nothing.
+func Daily(balance, annualRate float64) string {
+	if balance <= 0 || annualRate <= 0 {
+		return format(new(big.Rat), AccrualPlaces)
+	}
+	amount := new(big.Rat).Mul(exact(balance), exact(annualRate))
+	amount.Quo(amount, big.NewRat(DaysPerYear, 1))
+	return format(amount, AccrualPlaces)
+}
+
+// Overdraft returns the interest a negative end-of-day balance is charged in one day at
+// annualRate, as a negative amount rounded like Daily. Balances at or above zero are charged
+// nothing.
+func Overdraft(balance, annualRate float64) string {
+	if balance >= 0 || annualRate <= 0 {
+		return format(new(big.Rat), AccrualPlaces)
+	}
+	amount := new(big.Rat).Mul(exact(balance), exact(annualRate))
+	amount.Quo(amount, big.NewRat(DaysPerYear, 1))
+	return format(amount, AccrualPlaces)
+}
+
+// Sum adds decimal amounts exactly and rounds the total half away from zero to places.
+func Sum(amounts []string, places int) (string, error) {
+	total := new(big.Rat)
+	for _, amount := range amounts {
+		value, ok := new(big.Rat).SetString(amount)
+		if !ok {
+			return "", fmt.Errorf("invalid decimal %q", amount)
+		}
+		total.Add(total, value)
+	}
+	return format(total, places), nil
+}
+
+// exact reads a float as the decimal it prints as, so 0.1 is one tenth and not its binary
+// approximation.
+func exact(value float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(value, 'f', -1, 64))
+	return r
+}
+
+func format(value *big.Rat, places int) string {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil)
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(scale))
+
+	num := new(big.Int).Abs(scaled.Num())
+	quotient, remainder := new(big.Int).QuoRem(num, scaled.Denom(), new(big.Int))
+	if remainder.Lsh(remainder, 1).Cmp(scaled.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+
+	digits := quotient.String()
+	if len(digits) <= places {
+		digits = strings.Repeat("0", places-len(digits)+1) + digits
+	}
+	out := digits[:len(digits)-places]
+	if places > 0 {
+		out += "." + digits[len(digits)-places:]
+	}
+	if value.Sign() < 0 && quotient.Sign() != 0 {
+		out = "-" + out
+	}
+	return out
+}
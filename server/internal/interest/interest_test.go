@@ -0,0 +1,64 @@
+package interest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDaily(t *testing.T) {
+	tests := []struct {
+		balance  float64
+		rate     float64
+		expected string
+	}{
+		{1000, 0.02, "0.0547945205"},
+		{365, 0.1, "0.1000000000"},
+		{1234.5678, 0.035, "0.1183832137"},
+		{0.1, 0.01, "0.0000027397"},
+		{0, 0.02, "0.0000000000"},
+		{-50, 0.02, "0.0000000000"},
+		{1000, 0, "0.0000000000"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, Daily(tt.balance, tt.rate), "balance %v at %v", tt.balance, tt.rate)
+	}
+}
+
+func TestOverdraft(t *testing.T) {
+	assert.Equal(t, "-0.4931506849", Overdraft(-1000, 0.18))
+	assert.Equal(t, "-0.0000049315", Overdraft(-0.01, 0.18))
+	assert.Equal(t, "0.0000000000", Overdraft(0, 0.18))
+	assert.Equal(t, "0.0000000000", Overdraft(1000, 0.18))
+	assert.Equal(t, "0.0000000000", Overdraft(-1000, 0))
+}
+
+func TestSum(t *testing.T) {
+	// thirty-one days of 1000 at 2%, added up without float error
+	days := make([]string, 31)
+	for i := range days {
+		days[i] = Daily(1000, 0.02)
+	}
+	total, err := Sum(days, AccrualPlaces)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.6986301355", total)
+
+	total, err = Sum(days, PostingPlaces)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.6986", total)
+
+	total, err = Sum([]string{"0.00005"}, PostingPlaces)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0001", total, "halves round away from zero")
+
+	total, err = Sum([]string{"-0.00005", "0.00001"}, PostingPlaces)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0000", total)
+
+	total, err = Sum(nil, PostingPlaces)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0000", total)
+
+	_, err = Sum([]string{"1.5", "abc"}, PostingPlaces)
+	assert.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Job is a background task run on a fixed interval.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Runner struct {
+	jobs   []Job
+	logger *logrus.Entry
+}
+
+func NewRunner(baseLogger *logrus.Logger) *Runner {
+	logger := baseLogger.WithField("tag", "JOB-RUNNER")
+	return &Runner{logger: logger}
+}
+
+func (r *Runner) Register(job Job) {
+	r.jobs = append(r.jobs, job)
+}
+
+// Start runs every registered job in its own goroutine until ctx is cancelled.
+func (r *Runner) Start(ctx context.Context) {
+	for _, job := range r.jobs {
+		go r.loop(ctx, job)
+	}
+}
+
+func (r *Runner) loop(ctx context.Context, job Job) {
+	r.logger.Infof("Starting job %s every %s", job.Name, job.Interval)
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Infof("Stopping job %s", job.Name)
+			return
+		case <-ticker.C:
+			r.RunOnce(ctx, job)
+		}
+	}
+}
+
+// RunOnce runs a job a single time, recovering and logging any panic or error.
+func (r *Runner) RunOnce(ctx context.Context, job Job) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.logger.Errorf("Job %s panicked: %v", job.Name, rec)
+		}
+	}()
+
+	if err := job.Run(ctx); err != nil {
+		r.logger.WithError(err).Errorf("Job %s failed", job.Name)
+	}
+}
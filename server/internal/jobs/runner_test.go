@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunnerRunsJobsUntilCancelled(t *testing.T) {
+	runner := NewRunner(logrus.New())
+	var runs atomic.Int64
+	runner.Register(Job{
+		Name:     "counter",
+		Interval: 5 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runner.Start(ctx)
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	cancel()
+}
+
+func TestRunOnceRecoversFromFailures(t *testing.T) {
+	runner := NewRunner(logrus.New())
+
+	assert.NotPanics(t, func() {
+		runner.RunOnce(context.TODO(), Job{Name: "panics", Run: func(ctx context.Context) error { panic("boom") }})
+		runner.RunOnce(context.TODO(), Job{Name: "fails", Run: func(ctx context.Context) error { return errors.New("failed") }})
+	})
+}
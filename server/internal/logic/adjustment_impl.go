@@ -0,0 +1,127 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+)
+
+var (
+	ErrInvalidReasonCode       = errors.New("invalid adjustment reason code")
+	ErrInvalidAdjustmentAmount = errors.New("adjustment amount must be non-zero")
+	ErrAdjustmentNoteRequired  = errors.New("adjustment note is required")
+)
+
+var adjustmentReasonCodes = map[string]bool{
+	common.AdjustmentReasonGoodwill:        true,
+	common.AdjustmentReasonErrorCorrection: true,
+	common.AdjustmentReasonFeeRefund:       true,
+	common.AdjustmentReasonChargeback:      true,
+	common.AdjustmentReasonOther:           true,
+}
+
+// AdjustmentPayload is a manual balance correction. Positive amounts credit the wallet,
+// negative amounts debit it.
+type AdjustmentPayload struct {
+	WalletID    string  `json:"wallet_id"`
+	Amount      float64 `json:"amount"`
+	ReasonCode  string  `json:"reason_code"`
+	Note        string  `json:"note"`
+	RequestedBy string  `json:"requested_by"`
+}
+
+// SubmitAdjustment validates an operator adjustment and parks it for a second operator's approval.
+func (l *WalletImpl) SubmitAdjustment(ctx context.Context, payload AdjustmentPayload, idempotencyKey, method, path string) (*dao.PendingOperation, error) {
+	payload.Amount = common.RoundToNDecimals(payload.Amount, 4)
+	l.logger.Infof("Operator %s requested %s adjustment of %.4f on wallet %s", payload.RequestedBy, payload.ReasonCode, payload.Amount, payload.WalletID)
+
+	if payload.RequestedBy == "" {
+		return nil, ErrApproverRequired
+	}
+	if !adjustmentReasonCodes[payload.ReasonCode] {
+		return nil, ErrInvalidReasonCode
+	}
+	if payload.Amount == 0 {
+		return nil, ErrInvalidAdjustmentAmount
+	}
+	if payload.Note == "" {
+		return nil, ErrAdjustmentNoteRequired
+	}
+
+	if _, err := l.dao.GetWalletByID(payload.WalletID); err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, fmt.Errorf("adjustment failed: %w", err)
+	}
+
+	return l.SubmitForApproval(ctx, ApprovalRequest{
+		Type:           common.TransactionTypeAdjustment,
+		Payload:        payload,
+		InitiatedBy:    payload.RequestedBy,
+		IdempotencyKey: idempotencyKey,
+		Method:         method,
+		Path:           path,
+	})
+}
+
+func (l *WalletImpl) ListAdjustments(ctx context.Context, walletID string) ([]dao.Adjustment, error) {
+	return l.dao.ListAdjustments(walletID)
+}
+
+// applyAdjustment posts an approved adjustment through the deposit ledger path. KYC limits
+// and screening do not apply to operator corrections, but a debit may not overdraw the wallet.
+func (l *WalletImpl) applyAdjustment(ctx context.Context, payload AdjustmentPayload, approvedBy string) error {
+	current, err := l.dao.GetBalance(payload.WalletID)
+	if err != nil {
+		l.logger.WithError(err).Errorf("Failed to fetch wallet balance")
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return ErrWalletNotFound
+		}
+		return fmt.Errorf("adjustment failed: %w", err)
+	}
+
+	if current+payload.Amount < 0 {
+		l.logger.Warnf("Adjustment would overdraw wallet %s: current=%.4f, amount=%.4f", payload.WalletID, current, payload.Amount)
+		return ErrInsufficientBalance
+	}
+
+	txID, err := l.postLedgerEntry(payload.WalletID, current, payload.Amount, common.TransactionTypeAdjustment)
+	if err != nil {
+		l.logger.WithError(err).Errorf("Failed to update balance")
+		return fmt.Errorf("adjustment failed: %w", err)
+	}
+
+	err = l.dao.CreateAdjustment(&dao.Adjustment{
+		ID:            uuid.NewString(),
+		TransactionID: txID,
+		WalletID:      payload.WalletID,
+		Amount:        payload.Amount,
+		ReasonCode:    payload.ReasonCode,
+		Note:          payload.Note,
+		RequestedBy:   payload.RequestedBy,
+		ApprovedBy:    approvedBy,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		// the balance already moved, so surface this loudly but do not report failure
+		l.logger.WithError(err).Errorf("Failed to record adjustment details for transaction %s", txID)
+	}
+
+	l.recordAudit(ctx, common.AuditActionAdjustment, common.AuditEntityWallet, payload.WalletID, map[string]any{
+		"transaction_id": txID,
+		"amount":         payload.Amount,
+		"balance":        common.RoundToNDecimals(current+payload.Amount, 4),
+		"reason_code":    payload.ReasonCode,
+		"note":           payload.Note,
+		"requested_by":   payload.RequestedBy,
+		"approved_by":    approvedBy,
+	})
+	return nil
+}
@@ -0,0 +1,127 @@
+package logic_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func pendingAdjustmentOp(amount string) *dao.PendingOperation {
+	return &dao.PendingOperation{
+		ID:          "op-adj",
+		Type:        common.TransactionTypeAdjustment,
+		Payload:     `{"wallet_id":"wallet-1","amount":` + amount + `,"reason_code":"goodwill","note":"outage credit","requested_by":"maker"}`,
+		Status:      common.ApprovalStatusPending,
+		InitiatedBy: "maker",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+}
+
+func TestSubmitAdjustment(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	impl.SetApprovalPolicy(logic.ApprovalPolicy{TTL: time.Hour})
+	ctx := context.TODO()
+	valid := logic.AdjustmentPayload{WalletID: "wallet-1", Amount: 20, ReasonCode: common.AdjustmentReasonGoodwill, Note: "outage credit", RequestedBy: "maker"}
+
+	t.Run("adjustment parked for approval", func(t *testing.T) {
+		mockDao.On("GetWalletByID", "wallet-1").Return(&dao.Wallet{ID: "wallet-1"}, nil).Once()
+		mockDao.On("CreatePendingOperation", mock.MatchedBy(func(op *dao.PendingOperation) bool {
+			return op.Type == common.TransactionTypeAdjustment && op.InitiatedBy == "maker" && op.IdempotencyKey == "key-1"
+		})).Return(nil).Once()
+
+		op, err := impl.SubmitAdjustment(ctx, valid, "key-1", "POST", "/admin/wallets/wallet-1/adjustments")
+		assert.NoError(t, err)
+		assert.Equal(t, common.ApprovalStatusPending, op.Status)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("invalid reason code", func(t *testing.T) {
+		payload := valid
+		payload.ReasonCode = "because"
+		_, err := impl.SubmitAdjustment(ctx, payload, "key-2", "POST", "/")
+		assert.Equal(t, logic.ErrInvalidReasonCode, err)
+	})
+
+	t.Run("zero amount", func(t *testing.T) {
+		payload := valid
+		payload.Amount = 0.00001
+		_, err := impl.SubmitAdjustment(ctx, payload, "key-3", "POST", "/")
+		assert.Equal(t, logic.ErrInvalidAdjustmentAmount, err)
+	})
+
+	t.Run("note required", func(t *testing.T) {
+		payload := valid
+		payload.Note = ""
+		_, err := impl.SubmitAdjustment(ctx, payload, "key-4", "POST", "/")
+		assert.Equal(t, logic.ErrAdjustmentNoteRequired, err)
+	})
+
+	t.Run("wallet not found", func(t *testing.T) {
+		mockDao.On("GetWalletByID", "wallet-missing").Return(nil, dao.ErrWalletNotFound).Once()
+		payload := valid
+		payload.WalletID = "wallet-missing"
+		_, err := impl.SubmitAdjustment(ctx, payload, "key-5", "POST", "/")
+		assert.Equal(t, logic.ErrWalletNotFound, err)
+	})
+}
+
+func TestApproveAdjustment(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	ctx := context.TODO()
+
+	t.Run("approved adjustment posts to the ledger", func(t *testing.T) {
+		mockDao.On("GetPendingOperation", "op-adj").Return(pendingAdjustmentOp("20"), nil).Once()
+		mockDao.On("UpdatePendingOperationStatus", "op-adj", common.ApprovalStatusPending, common.ApprovalStatusApproved, "checker", (*string)(nil)).
+			Return(nil).Once()
+		mockDao.On("GetBalance", "wallet-1").Return(100.0, nil).Once()
+		mockDao.On("UpdateBalance", &dao.UpdateBalance{WalletID: "wallet-1", Amount: 120}).Return(nil).Once()
+		mockDao.On("CreateTransaction", mock.MatchedBy(func(tx *dao.Transaction) bool {
+			return tx.Type == common.TransactionTypeAdjustment && tx.Amount == 20
+		})).Return(nil).Once()
+		mockDao.On("CreateAdjustment", mock.MatchedBy(func(a *dao.Adjustment) bool {
+			return a.TransactionID != "" && a.RequestedBy == "maker" && a.ApprovedBy == "checker" && a.ReasonCode == "goodwill"
+		})).Return(nil).Once()
+
+		op, err := impl.ApprovePendingOperation(ctx, "op-adj", "checker")
+		assert.NoError(t, err)
+		assert.Equal(t, common.ApprovalStatusApproved, op.Status)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("debit may not overdraw the wallet", func(t *testing.T) {
+		mockDao.On("GetPendingOperation", "op-adj").Return(pendingAdjustmentOp("-150"), nil).Once()
+		mockDao.On("UpdatePendingOperationStatus", "op-adj", common.ApprovalStatusPending, common.ApprovalStatusApproved, "checker", (*string)(nil)).
+			Return(nil).Once()
+		mockDao.On("GetBalance", "wallet-1").Return(100.0, nil).Once()
+		mockDao.On("UpdatePendingOperationStatus", "op-adj", common.ApprovalStatusApproved, common.ApprovalStatusFailed, "checker", mock.Anything).
+			Return(nil).Once()
+
+		op, err := impl.ApprovePendingOperation(ctx, "op-adj", "checker")
+		assert.Equal(t, logic.ErrInsufficientBalance, err)
+		assert.Equal(t, common.ApprovalStatusFailed, op.Status)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("balance update failure fails the operation", func(t *testing.T) {
+		mockDao.On("GetPendingOperation", "op-adj").Return(pendingAdjustmentOp("-10"), nil).Once()
+		mockDao.On("UpdatePendingOperationStatus", "op-adj", common.ApprovalStatusPending, common.ApprovalStatusApproved, "checker", (*string)(nil)).
+			Return(nil).Once()
+		mockDao.On("GetBalance", "wallet-1").Return(100.0, nil).Once()
+		mockDao.On("UpdateBalance", &dao.UpdateBalance{WalletID: "wallet-1", Amount: 90}).Return(errors.New("db down")).Once()
+		mockDao.On("CreateTransaction", mock.Anything).Return(nil).Once()
+		mockDao.On("UpdatePendingOperationStatus", "op-adj", common.ApprovalStatusApproved, common.ApprovalStatusFailed, "checker", mock.Anything).
+			Return(nil).Once()
+
+		op, err := impl.ApprovePendingOperation(ctx, "op-adj", "checker")
+		assert.Error(t, err)
+		assert.Equal(t, common.ApprovalStatusFailed, op.Status)
+		mockDao.AssertNumberOfCalls(t, "CreateAdjustment", 1)
+	})
+}
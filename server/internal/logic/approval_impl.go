@@ -0,0 +1,277 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/risk"
+)
+
+var (
+	ErrApprovalNotFound   = errors.New("pending operation not found")
+	ErrApprovalNotPending = errors.New("operation is no longer pending")
+	ErrApprovalExpired    = errors.New("pending operation has expired")
+	ErrApproverRequired   = errors.New("approver identity is required")
+	ErrInitiatorRequired  = errors.New("initiator identity is required")
+	ErrSelfApproval       = errors.New("operation must be decided by a different principal than its initiator")
+)
+
+// ApprovalPolicy decides which operations need a second person's approval.
+type ApprovalPolicy struct {
+	// TransferThreshold is the amount above which transfers need approval. Zero disables it.
+	TransferThreshold float64
+	// TTL is how long an operation waits for a decision before it expires.
+	TTL time.Duration
+}
+
+// ApprovalRequest describes an operation submitted for maker-checker approval, together
+// with the request it originated from so the outcome can be stored for its idempotency key.
+type ApprovalRequest struct {
+	Type           string
+	Payload        any
+	InitiatedBy    string
+	IdempotencyKey string
+	Method         string
+	Path           string
+}
+
+type TransferPayload struct {
+	FromWalletID string  `json:"from_wallet_id"`
+	ToWalletID   string  `json:"to_wallet_id"`
+	Amount       float64 `json:"amount"`
+}
+
+func (l *WalletImpl) SetApprovalPolicy(policy ApprovalPolicy) {
+	l.approvals = policy
+}
+
+// RequiresApproval reports whether a transfer of the given amount must go through maker-checker.
+func (l *WalletImpl) RequiresApproval(amount float64) bool {
+	return l.approvals.TransferThreshold > 0 && amount > l.approvals.TransferThreshold
+}
+
+// SubmitForApproval parks an operation until a principal other than its initiator decides it.
+// The initiator is required, as without one anybody could approve their own operation.
+// Transfers are screened first: a denied transfer is not submitted, and the rules a transfer
+// hit for review are stored on the operation for the checker.
+func (l *WalletImpl) SubmitForApproval(ctx context.Context, req ApprovalRequest) (*dao.PendingOperation, error) {
+	if req.InitiatedBy == "" {
+		return nil, ErrInitiatorRequired
+	}
+	payload, err := json.Marshal(req.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("submit for approval failed: %w", err)
+	}
+
+	var riskHits *string
+	if req.Type == common.TransactionTypeTransfer {
+		var transfer TransferPayload
+		if err := json.Unmarshal(payload, &transfer); err != nil {
+			return nil, fmt.Errorf("invalid transfer payload: %w", err)
+		}
+		hits, err := l.screenPendingTransfer(ctx, transfer)
+		if err != nil {
+			return nil, err
+		}
+		if len(hits) > 0 {
+			raw, _ := json.Marshal(hits)
+			details := string(raw)
+			riskHits = &details
+		}
+	}
+
+	now := time.Now()
+	op := &dao.PendingOperation{
+		ID:             uuid.NewString(),
+		Type:           req.Type,
+		Payload:        string(payload),
+		Status:         common.ApprovalStatusPending,
+		InitiatedBy:    req.InitiatedBy,
+		RiskHits:       riskHits,
+		IdempotencyKey: req.IdempotencyKey,
+		RequestMethod:  req.Method,
+		RequestPath:    req.Path,
+		ExpiresAt:      now.Add(l.approvals.TTL),
+		CreatedAt:      now,
+	}
+	if err := l.dao.CreatePendingOperation(op); err != nil {
+		l.logger.WithError(err).Error("Failed to create pending operation")
+		return nil, fmt.Errorf("submit for approval failed: %w", err)
+	}
+	l.recordAudit(ctx, common.AuditActionApprovalSubmitted, common.AuditEntityOperation, op.ID, map[string]any{
+		"type":         op.Type,
+		"payload":      json.RawMessage(payload),
+		"initiated_by": op.InitiatedBy,
+	})
+	return op, nil
+}
+
+func (l *WalletImpl) GetPendingOperation(ctx context.Context, operationID string) (*dao.PendingOperation, error) {
+	op, err := l.dao.GetPendingOperation(operationID)
+	if errors.Is(err, dao.ErrPendingOperationNotFound) {
+		return nil, ErrApprovalNotFound
+	}
+	return op, err
+}
+
+func (l *WalletImpl) ListPendingOperations(ctx context.Context, status string) ([]dao.PendingOperation, error) {
+	return l.dao.ListPendingOperations(status)
+}
+
+// ApprovePendingOperation records the approval and executes the operation. A non-nil
+// operation returned with an error means the approval was recorded but execution failed.
+func (l *WalletImpl) ApprovePendingOperation(ctx context.Context, operationID, approver string) (*dao.PendingOperation, error) {
+	op, err := l.claimPendingOperation(ctx, operationID, approver, common.ApprovalStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	if execErr := l.executePendingOperation(ctx, op); execErr != nil {
+		l.logger.WithError(execErr).Errorf("Approved operation %s failed to execute", op.ID)
+		msg := execErr.Error()
+		if err := l.dao.UpdatePendingOperationStatus(op.ID, common.ApprovalStatusApproved, common.ApprovalStatusFailed, approver, &msg); err != nil {
+			l.logger.WithError(err).Errorf("Failed to mark operation %s as failed", op.ID)
+		}
+		l.recordAudit(ctx, common.AuditActionApprovalDecided, common.AuditEntityOperation, op.ID, map[string]any{
+			"status": common.ApprovalStatusFailed,
+			"result": msg,
+		})
+		op.Status = common.ApprovalStatusFailed
+		op.Result = &msg
+		return op, execErr
+	}
+
+	op.Status = common.ApprovalStatusApproved
+	return op, nil
+}
+
+func (l *WalletImpl) RejectPendingOperation(ctx context.Context, operationID, approver string) (*dao.PendingOperation, error) {
+	op, err := l.claimPendingOperation(ctx, operationID, approver, common.ApprovalStatusRejected)
+	if err != nil {
+		return nil, err
+	}
+	op.Status = common.ApprovalStatusRejected
+	return op, nil
+}
+
+// ExpirePendingOperations marks overdue operations as expired and returns the operations it expired.
+func (l *WalletImpl) ExpirePendingOperations(ctx context.Context) ([]dao.PendingOperation, error) {
+	ops, err := l.dao.ExpirePendingOperations(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(ops) > 0 {
+		l.logger.Infof("Expired %d pending operations", len(ops))
+		l.recordAudit(ctx, common.AuditActionApprovalDecided, common.AuditEntityOperation, "", map[string]any{
+			"status": common.ApprovalStatusExpired,
+			"count":  len(ops),
+		})
+	}
+	return ops, nil
+}
+
+// claimPendingOperation validates the decider and atomically moves the operation out of pending.
+func (l *WalletImpl) claimPendingOperation(ctx context.Context, operationID, decider, toStatus string) (*dao.PendingOperation, error) {
+	if decider == "" {
+		return nil, ErrApproverRequired
+	}
+
+	op, err := l.dao.GetPendingOperation(operationID)
+	if err != nil {
+		if errors.Is(err, dao.ErrPendingOperationNotFound) {
+			return nil, ErrApprovalNotFound
+		}
+		return nil, err
+	}
+
+	if op.Status != common.ApprovalStatusPending {
+		return nil, ErrApprovalNotPending
+	}
+	if op.InitiatedBy == "" {
+		l.logger.Warnf("Rejected decision of operation %s which has no initiator", op.ID)
+		return nil, ErrInitiatorRequired
+	}
+	if decider == op.InitiatedBy {
+		l.logger.Warnf("Rejected self-decision of operation %s by %s", op.ID, decider)
+		return nil, ErrSelfApproval
+	}
+	if time.Now().After(op.ExpiresAt) {
+		_ = l.dao.UpdatePendingOperationStatus(op.ID, common.ApprovalStatusPending, common.ApprovalStatusExpired, decider, nil)
+		return nil, ErrApprovalExpired
+	}
+
+	if err := l.dao.UpdatePendingOperationStatus(op.ID, common.ApprovalStatusPending, toStatus, decider, nil); err != nil {
+		if errors.Is(err, dao.ErrPendingOperationNotFound) {
+			return nil, ErrApprovalNotPending
+		}
+		return nil, err
+	}
+	l.recordAudit(ctx, common.AuditActionApprovalDecided, common.AuditEntityOperation, op.ID, map[string]any{
+		"status":     toStatus,
+		"decided_by": decider,
+	})
+
+	op.DecidedBy = &decider
+	return op, nil
+}
+
+func (l *WalletImpl) executePendingOperation(ctx context.Context, op *dao.PendingOperation) error {
+	switch op.Type {
+	case common.TransactionTypeTransfer:
+		var payload TransferPayload
+		if err := json.Unmarshal([]byte(op.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid transfer payload: %w", err)
+		}
+		// rules may have changed while the operation waited, so a deny still fails it; the
+		// checker's approval stands in for a review
+		if _, err := l.screenPendingTransfer(ctx, payload); err != nil {
+			return err
+		}
+		_, fee, err := l.transfer(withInitiator(ctx, op.InitiatedBy), payload.FromWalletID, payload.ToWalletID, payload.Amount, false)
+		op.Fee = fee
+		return err
+	case common.TransactionTypeAdjustment:
+		var payload AdjustmentPayload
+		if err := json.Unmarshal([]byte(op.Payload), &payload); err != nil {
+			return fmt.Errorf("invalid adjustment payload: %w", err)
+		}
+		return l.applyAdjustment(ctx, payload, *op.DecidedBy)
+	}
+	return fmt.Errorf("unsupported operation type %q", op.Type)
+}
+
+// screenPendingTransfer screens a transfer that goes through maker-checker. A deny is returned
+// as ErrTransactionDenied; a review is not parked, as the checker decides it, and the rules it
+// hit are returned instead.
+func (l *WalletImpl) screenPendingTransfer(ctx context.Context, payload TransferPayload) ([]string, error) {
+	if l.screener == nil {
+		return nil, nil
+	}
+
+	result, err := l.screener.Screen(ctx, risk.Event{
+		Type:                 common.TransactionTypeTransfer,
+		WalletID:             payload.FromWalletID,
+		CounterpartyWalletID: payload.ToWalletID,
+		Amount:               payload.Amount,
+	})
+	if err != nil {
+		l.logger.WithError(err).Error("Risk screening failed")
+		return nil, fmt.Errorf("transfer screening failed: %w", err)
+	}
+
+	switch result.Action {
+	case risk.ActionDeny:
+		l.logger.Warnf("transfer from wallet %s denied by rules %v", payload.FromWalletID, result.Hits)
+		return nil, ErrTransactionDenied
+	case risk.ActionReview:
+		return result.Hits, nil
+	}
+	return nil, nil
+}
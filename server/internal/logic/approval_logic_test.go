@@ -0,0 +1,228 @@
+package logic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/risk"
+	riskMocks "github.com/julkhong/walletapp/server/internal/risk/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func pendingTransferOp() *dao.PendingOperation {
+	return &dao.PendingOperation{
+		ID:          "op-1",
+		Type:        common.TransactionTypeTransfer,
+		Payload:     `{"from_wallet_id":"wallet-from","to_wallet_id":"wallet-to","amount":25}`,
+		Status:      common.ApprovalStatusPending,
+		InitiatedBy: "maker",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+}
+
+func TestRequiresApproval(t *testing.T) {
+	impl, _ := setupLogicTest()
+	assert.False(t, impl.RequiresApproval(1_000_000), "disabled without a policy")
+
+	impl.SetApprovalPolicy(logic.ApprovalPolicy{TransferThreshold: 1000, TTL: time.Hour})
+	assert.False(t, impl.RequiresApproval(1000))
+	assert.True(t, impl.RequiresApproval(1000.01))
+}
+
+func TestSubmitForApproval(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	impl.SetApprovalPolicy(logic.ApprovalPolicy{TransferThreshold: 1000, TTL: time.Hour})
+
+	mockDao.On("CreatePendingOperation", mock.MatchedBy(func(op *dao.PendingOperation) bool {
+		return op.Status == common.ApprovalStatusPending && op.InitiatedBy == "maker" &&
+			op.Payload == `{"from_wallet_id":"a","to_wallet_id":"b","amount":2000}` &&
+			op.IdempotencyKey == "key-1" && op.ExpiresAt.After(time.Now().Add(59*time.Minute))
+	})).Return(nil).Once()
+
+	op, err := impl.SubmitForApproval(context.TODO(), logic.ApprovalRequest{
+		Type:           common.TransactionTypeTransfer,
+		Payload:        logic.TransferPayload{FromWalletID: "a", ToWalletID: "b", Amount: 2000},
+		InitiatedBy:    "maker",
+		IdempotencyKey: "key-1",
+		Method:         "POST",
+		Path:           "/wallets/transfer",
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, op.ID)
+	mockDao.AssertExpectations(t)
+
+	t.Run("initiator is required", func(t *testing.T) {
+		_, err := impl.SubmitForApproval(context.TODO(), logic.ApprovalRequest{
+			Type:    common.TransactionTypeTransfer,
+			Payload: logic.TransferPayload{FromWalletID: "a", ToWalletID: "b", Amount: 2000},
+		})
+		assert.ErrorIs(t, err, logic.ErrInitiatorRequired)
+		mockDao.AssertNumberOfCalls(t, "CreatePendingOperation", 1)
+	})
+
+	t.Run("review hits are recorded for the checker", func(t *testing.T) {
+		screener := new(riskMocks.Screener)
+		impl.SetScreener(screener)
+		defer impl.SetScreener(nil)
+		screener.On("Screen", mock.Anything, mock.MatchedBy(func(ev risk.Event) bool {
+			return ev.WalletID == "a" && ev.CounterpartyWalletID == "b" && ev.Amount == 20000
+		})).Return(risk.Result{Action: risk.ActionReview, Hits: []string{"large_amount_review"}}, nil).Once()
+		mockDao.On("CreatePendingOperation", mock.MatchedBy(func(op *dao.PendingOperation) bool {
+			return op.RiskHits != nil && *op.RiskHits == `["large_amount_review"]`
+		})).Return(nil).Once()
+
+		_, err := impl.SubmitForApproval(context.TODO(), logic.ApprovalRequest{
+			Type:        common.TransactionTypeTransfer,
+			Payload:     logic.TransferPayload{FromWalletID: "a", ToWalletID: "b", Amount: 20000},
+			InitiatedBy: "maker",
+		})
+		assert.NoError(t, err)
+		mockDao.AssertExpectations(t)
+		screener.AssertExpectations(t)
+	})
+
+	t.Run("denied transfer is not submitted", func(t *testing.T) {
+		screener := new(riskMocks.Screener)
+		impl.SetScreener(screener)
+		defer impl.SetScreener(nil)
+		screener.On("Screen", mock.Anything, mock.Anything).
+			Return(risk.Result{Action: risk.ActionDeny, Hits: []string{"very_large_amount_deny"}}, nil).Once()
+
+		_, err := impl.SubmitForApproval(context.TODO(), logic.ApprovalRequest{
+			Type:        common.TransactionTypeTransfer,
+			Payload:     logic.TransferPayload{FromWalletID: "a", ToWalletID: "b", Amount: 150000},
+			InitiatedBy: "maker",
+		})
+		assert.ErrorIs(t, err, logic.ErrTransactionDenied)
+		mockDao.AssertNumberOfCalls(t, "CreatePendingOperation", 2)
+	})
+}
+
+func TestApprovePendingOperation(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	ctx := context.TODO()
+	expectActiveWallets(mockDao, "wallet-from", "wallet-to")
+
+	t.Run("approved transfer is executed", func(t *testing.T) {
+		// the approval stands in for a review, so a transfer the rules would park is not parked again
+		screener := new(riskMocks.Screener)
+		impl.SetScreener(screener)
+		defer impl.SetScreener(nil)
+		screener.On("Screen", mock.Anything, mock.Anything).
+			Return(risk.Result{Action: risk.ActionReview, Hits: []string{"large_amount_review"}}, nil).Once()
+		mockDao.On("GetPendingOperation", "op-1").Return(pendingTransferOp(), nil).Once()
+		mockDao.On("UpdatePendingOperationStatus", "op-1", common.ApprovalStatusPending, common.ApprovalStatusApproved, "checker", (*string)(nil)).
+			Return(nil).Once()
+		mockDao.On("GetBalance", "wallet-from").Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-from").Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", "wallet-to").Return(0.0, nil).Once()
+		mockDao.On("PostTransfers", mock.Anything).Return(nil).Once()
+
+		op, err := impl.ApprovePendingOperation(ctx, "op-1", "checker")
+		assert.NoError(t, err)
+		assert.Equal(t, common.ApprovalStatusApproved, op.Status)
+		mockDao.AssertExpectations(t)
+		screener.AssertExpectations(t)
+	})
+
+	t.Run("approved transfer denied by the rules fails", func(t *testing.T) {
+		screener := new(riskMocks.Screener)
+		impl.SetScreener(screener)
+		defer impl.SetScreener(nil)
+		screener.On("Screen", mock.Anything, mock.MatchedBy(func(ev risk.Event) bool {
+			return ev.Amount == 150000
+		})).Return(risk.Result{Action: risk.ActionDeny, Hits: []string{"very_large_amount_deny"}}, nil).Once()
+
+		large := pendingTransferOp()
+		large.Payload = `{"from_wallet_id":"wallet-from","to_wallet_id":"wallet-to","amount":150000}`
+		mockDao.On("GetPendingOperation", "op-1").Return(large, nil).Once()
+		mockDao.On("UpdatePendingOperationStatus", "op-1", common.ApprovalStatusPending, common.ApprovalStatusApproved, "checker", (*string)(nil)).
+			Return(nil).Once()
+		mockDao.On("UpdatePendingOperationStatus", "op-1", common.ApprovalStatusApproved, common.ApprovalStatusFailed, "checker", mock.Anything).
+			Return(nil).Once()
+
+		op, err := impl.ApprovePendingOperation(ctx, "op-1", "checker")
+		assert.ErrorIs(t, err, logic.ErrTransactionDenied)
+		assert.Equal(t, common.ApprovalStatusFailed, op.Status)
+		mockDao.AssertExpectations(t)
+		mockDao.AssertNumberOfCalls(t, "PostTransfers", 1) // only the transfer approved above
+		screener.AssertExpectations(t)
+	})
+
+	t.Run("initiator cannot approve", func(t *testing.T) {
+		mockDao.On("GetPendingOperation", "op-1").Return(pendingTransferOp(), nil).Once()
+
+		op, err := impl.ApprovePendingOperation(ctx, "op-1", "maker")
+		assert.ErrorIs(t, err, logic.ErrSelfApproval)
+		assert.Nil(t, op)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("operation without initiator cannot be decided", func(t *testing.T) {
+		anonymous := pendingTransferOp()
+		anonymous.InitiatedBy = ""
+		mockDao.On("GetPendingOperation", "op-1").Return(anonymous, nil).Once()
+
+		op, err := impl.ApprovePendingOperation(ctx, "op-1", "checker")
+		assert.ErrorIs(t, err, logic.ErrInitiatorRequired)
+		assert.Nil(t, op)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("expired operation", func(t *testing.T) {
+		expired := pendingTransferOp()
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		mockDao.On("GetPendingOperation", "op-1").Return(expired, nil).Once()
+		mockDao.On("UpdatePendingOperationStatus", "op-1", common.ApprovalStatusPending, common.ApprovalStatusExpired, "checker", (*string)(nil)).
+			Return(nil).Once()
+
+		_, err := impl.ApprovePendingOperation(ctx, "op-1", "checker")
+		assert.ErrorIs(t, err, logic.ErrApprovalExpired)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("execution failure is recorded", func(t *testing.T) {
+		mockDao.On("GetPendingOperation", "op-1").Return(pendingTransferOp(), nil).Once()
+		mockDao.On("UpdatePendingOperationStatus", "op-1", common.ApprovalStatusPending, common.ApprovalStatusApproved, "checker", (*string)(nil)).
+			Return(nil).Once()
+		mockDao.On("GetBalance", "wallet-from").Return(10.0, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-from").Return(fullKycUser, nil).Once()
+		mockDao.On("UpdatePendingOperationStatus", "op-1", common.ApprovalStatusApproved, common.ApprovalStatusFailed, "checker", mock.Anything).
+			Return(nil).Once()
+
+		op, err := impl.ApprovePendingOperation(ctx, "op-1", "checker")
+		assert.ErrorIs(t, err, logic.ErrInsufficientBalance)
+		assert.Equal(t, common.ApprovalStatusFailed, op.Status)
+		mockDao.AssertExpectations(t)
+	})
+}
+
+func TestRejectPendingOperation(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+
+	t.Run("already decided", func(t *testing.T) {
+		decided := pendingTransferOp()
+		decided.Status = common.ApprovalStatusApproved
+		mockDao.On("GetPendingOperation", "op-1").Return(decided, nil).Once()
+
+		_, err := impl.RejectPendingOperation(context.TODO(), "op-1", "checker")
+		assert.ErrorIs(t, err, logic.ErrApprovalNotPending)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		mockDao.On("GetPendingOperation", "op-1").Return(pendingTransferOp(), nil).Once()
+		mockDao.On("UpdatePendingOperationStatus", "op-1", common.ApprovalStatusPending, common.ApprovalStatusRejected, "checker", (*string)(nil)).
+			Return(nil).Once()
+
+		op, err := impl.RejectPendingOperation(context.TODO(), "op-1", "checker")
+		assert.NoError(t, err)
+		assert.Equal(t, common.ApprovalStatusRejected, op.Status)
+		mockDao.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,102 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/julkhong/walletapp/server/internal/audit"
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+)
+
+// auditVerifyBatch is how many rows are loaded at a time while walking the chain.
+const auditVerifyBatch = 500
+
+// AuditVerification is the outcome of walking the audit chain.
+type AuditVerification struct {
+	Valid   bool         `json:"valid"`
+	Checked int64        `json:"checked"`
+	Break   *audit.Break `json:"break,omitempty"`
+}
+
+// RecordConfigChange records a configuration value taking effect. Nothing is recorded when value
+// is the one last recorded under key, so restarting with the same configuration adds no rows.
+func (l *WalletImpl) RecordConfigChange(ctx context.Context, key string, value any) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		l.logger.WithError(err).Errorf("Failed to encode %s config", key)
+		return
+	}
+
+	last, err := l.dao.GetLatestAuditLog(common.AuditActionConfigChanged, common.AuditEntityConfig, key)
+	switch {
+	case err == nil && last.Details == string(payload):
+		return
+	case err != nil && !errors.Is(err, dao.ErrAuditLogNotFound):
+		// a repeated row is better than a change missing from the trail
+		l.logger.WithError(err).Warnf("Failed to fetch the last recorded %s config", key)
+	}
+	l.recordAudit(ctx, common.AuditActionConfigChanged, common.AuditEntityConfig, key, value)
+}
+
+func (l *WalletImpl) ListAuditLog(ctx context.Context, afterSeq int64, limit int) ([]dao.AuditLog, error) {
+	return l.dao.ListAuditLog(afterSeq, limit)
+}
+
+// VerifyAuditLog walks the whole chain and reports the first broken link.
+func (l *WalletImpl) VerifyAuditLog(ctx context.Context) (*AuditVerification, error) {
+	var (
+		verifier audit.Verifier
+		afterSeq int64
+	)
+	for {
+		entries, err := l.dao.ListAuditLog(afterSeq, auditVerifyBatch)
+		if err != nil {
+			return nil, fmt.Errorf("audit verification failed: %w", err)
+		}
+		if len(entries) == 0 {
+			return &AuditVerification{Valid: true, Checked: verifier.Checked()}, nil
+		}
+
+		links := make([]audit.Link, len(entries))
+		for i := range entries {
+			links[i] = audit.Link{Content: entries[i].Content(), PrevHash: entries[i].PrevHash, Hash: entries[i].Hash}
+		}
+		if brk := verifier.Add(links); brk != nil {
+			l.logger.Errorf("Audit chain broken at seq %d: %s", brk.Seq, brk.Reason)
+			return &AuditVerification{Valid: false, Checked: verifier.Checked(), Break: brk}, nil
+		}
+		afterSeq = entries[len(entries)-1].Seq
+	}
+}
+
+// recordAudit appends a state change to the audit trail. The change has already happened,
+// so a failed append is logged rather than returned.
+func (l *WalletImpl) recordAudit(ctx context.Context, action, entityType, entityID string, details any) {
+	payload, err := json.Marshal(details)
+	if err != nil {
+		l.logger.WithError(err).Errorf("Failed to encode audit details for %s", action)
+		return
+	}
+
+	meta := audit.FromContext(ctx)
+	if meta.Actor == "" {
+		meta.Actor = "system"
+	}
+
+	err = l.dao.AppendAuditLog(&dao.AuditLog{
+		Actor:      meta.Actor,
+		IP:         meta.IP,
+		RequestID:  meta.RequestID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Details:    string(payload),
+		CreatedAt:  audit.Timestamp(),
+	})
+	if err != nil {
+		l.logger.WithError(err).Errorf("Failed to append %s to audit log", action)
+	}
+}
@@ -0,0 +1,115 @@
+package logic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/audit"
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func auditChain(n int) []dao.AuditLog {
+	entries := make([]dao.AuditLog, 0, n)
+	prev := ""
+	for i := 1; i <= n; i++ {
+		entry := dao.AuditLog{
+			Seq:       int64(i),
+			Actor:     "operator-1",
+			Action:    common.AuditActionDeposit,
+			EntityID:  "wallet-1",
+			Details:   `{"amount":10}`,
+			CreatedAt: time.Date(2026, 3, 3, 10, 0, i, 0, time.UTC),
+			PrevHash:  prev,
+		}
+		entry.Hash = audit.Hash(prev, entry.Content())
+		prev = entry.Hash
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestDepositIsAudited(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	expectActiveWallets(mockDao, "wallet-1")
+	ctx := audit.WithMeta(context.TODO(), audit.Meta{Actor: "user-1", IP: "10.0.0.7", RequestID: "req-1"})
+
+	mockDao.On("GetBalance", "wallet-1").Return(90.0, nil).Once()
+	mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil).Once()
+	mockDao.On("UpdateBalance", mock.Anything).Return(nil).Once()
+	mockDao.On("CreateTransaction", mock.Anything).Return(nil).Once()
+
+	assert.NoError(t, impl.Deposit(ctx, "wallet-1", 10))
+	mockDao.AssertCalled(t, "AppendAuditLog", mock.MatchedBy(func(entry *dao.AuditLog) bool {
+		return entry.Action == common.AuditActionDeposit && entry.EntityID == "wallet-1" &&
+			entry.Actor == "user-1" && entry.IP == "10.0.0.7" && entry.RequestID == "req-1" &&
+			entry.Details == `{"amount":10,"balance":100}`
+	}))
+}
+
+func TestRecordConfigChange(t *testing.T) {
+	ctx := context.TODO()
+	policy := map[string]any{"transfer_threshold": 5000}
+	lookup := []any{common.AuditActionConfigChanged, common.AuditEntityConfig, "approval_policy"}
+
+	t.Run("first value is recorded as system", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetLatestAuditLog", lookup...).Return(nil, dao.ErrAuditLogNotFound).Once()
+
+		impl.RecordConfigChange(ctx, "approval_policy", policy)
+		mockDao.AssertCalled(t, "AppendAuditLog", mock.MatchedBy(func(entry *dao.AuditLog) bool {
+			return entry.Action == common.AuditActionConfigChanged && entry.Actor == "system" && entry.EntityID == "approval_policy"
+		}))
+	})
+
+	t.Run("unchanged value is not recorded again", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetLatestAuditLog", lookup...).Return(&dao.AuditLog{Details: `{"transfer_threshold":5000}`}, nil).Once()
+
+		impl.RecordConfigChange(ctx, "approval_policy", policy)
+		mockDao.AssertNotCalled(t, "AppendAuditLog", mock.Anything)
+	})
+
+	t.Run("changed value is recorded", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetLatestAuditLog", lookup...).Return(&dao.AuditLog{Details: `{"transfer_threshold":1000}`}, nil).Once()
+
+		impl.RecordConfigChange(ctx, "approval_policy", policy)
+		mockDao.AssertCalled(t, "AppendAuditLog", mock.MatchedBy(func(entry *dao.AuditLog) bool {
+			return entry.Details == `{"transfer_threshold":5000}`
+		}))
+	})
+}
+
+func TestVerifyAuditLog(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("intact chain", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		chain := auditChain(3)
+		mockDao.On("ListAuditLog", int64(0), 500).Return(chain, nil).Once()
+		mockDao.On("ListAuditLog", int64(3), 500).Return([]dao.AuditLog{}, nil).Once()
+
+		result, err := impl.VerifyAuditLog(ctx)
+		assert.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, int64(3), result.Checked)
+		assert.Nil(t, result.Break)
+	})
+
+	t.Run("edited row reported", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		chain := auditChain(3)
+		chain[1].Details = `{"amount":10000}`
+		mockDao.On("ListAuditLog", int64(0), 500).Return(chain, nil).Once()
+
+		result, err := impl.VerifyAuditLog(ctx)
+		assert.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, int64(1), result.Checked)
+		assert.Equal(t, int64(2), result.Break.Seq)
+	})
+}
@@ -0,0 +1,145 @@
+package logic
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/bankrec"
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+)
+
+var (
+	ErrBankImportNotFound = errors.New("bank import not found")
+)
+
+// BankImportResult is a stored bank statement import with its reconciliation report. Duplicate is
+// set when the same file was imported before; its original report is returned unchanged.
+type BankImportResult struct {
+	dao.BankImport
+	Duplicate bool            `json:"duplicate"`
+	Report    *bankrec.Report `json:"report"`
+}
+
+// ImportBankStatement parses a camt.053 or MT940 file and matches its credit lines against
+// deposits that no earlier import has matched. Matches are stored so each deposit is backed by
+// one bank line only. An empty format is detected from the file.
+func (l *WalletImpl) ImportBankStatement(ctx context.Context, format string, data []byte, importedBy string) (*BankImportResult, error) {
+	if importedBy == "" {
+		return nil, ErrApproverRequired
+	}
+
+	sum := sha256.Sum256(data)
+	fileHash := hex.EncodeToString(sum[:])
+	existing, err := l.dao.GetBankImportByHash(fileHash)
+	if err == nil {
+		l.logger.Infof("Bank statement already imported as %s", existing.ID)
+		result, err := bankImportResult(existing)
+		if err != nil {
+			return nil, err
+		}
+		result.Duplicate = true
+		return result, nil
+	}
+	if !errors.Is(err, dao.ErrBankImportNotFound) {
+		return nil, fmt.Errorf("bank import failed: %w", err)
+	}
+
+	if format == "" {
+		format = bankrec.Detect(data)
+	}
+	statement, err := bankrec.Parse(format, data)
+	if err != nil {
+		l.logger.WithError(err).Warn("Rejected bank statement")
+		return nil, err
+	}
+
+	var deposits []dao.Transaction
+	if len(statement.Lines) > 0 {
+		from, to := bankrec.Window(statement.Lines, bankrec.DefaultDateTolerance)
+		deposits, err = l.dao.ListUnmatchedDeposits(from, to)
+		if err != nil {
+			return nil, fmt.Errorf("bank import failed: %w", err)
+		}
+	}
+	report := bankrec.Reconcile(statement.Lines, deposits, bankrec.DefaultDateTolerance)
+
+	raw, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("bank import failed: %w", err)
+	}
+	now := time.Now()
+	bankImport := dao.BankImport{
+		ID:             uuid.NewString(),
+		Format:         format,
+		StatementID:    statement.ID,
+		Account:        statement.Account,
+		FileHash:       fileHash,
+		LineCount:      len(statement.Lines),
+		MatchedCount:   len(report.Matched),
+		UnmatchedCount: len(report.Unmatched),
+		AmbiguousCount: len(report.Ambiguous),
+		ReportJSON:     string(raw),
+		ImportedBy:     importedBy,
+		CreatedAt:      now,
+	}
+	matches := make([]dao.BankMatch, 0, len(report.Matched))
+	for _, m := range report.Matched {
+		reference := m.Line.BankReference
+		if reference == "" {
+			reference = m.Line.Reference
+		}
+		matches = append(matches, dao.BankMatch{
+			TransactionID: m.TransactionID,
+			ImportID:      bankImport.ID,
+			BankReference: reference,
+			Amount:        m.Line.Amount,
+			BookingDate:   m.Line.BookingDate,
+			Method:        m.Method,
+			CreatedAt:     now,
+		})
+	}
+
+	if err := l.dao.CreateBankImport(&bankImport, matches); err != nil {
+		l.logger.WithError(err).Error("Failed to store bank import")
+		return nil, fmt.Errorf("bank import failed: %w", err)
+	}
+
+	l.logger.Infof("Imported bank statement %s: %d matched, %d unmatched, %d ambiguous",
+		statement.ID, len(report.Matched), len(report.Unmatched), len(report.Ambiguous))
+	l.recordAudit(ctx, common.AuditActionBankImported, common.AuditEntityBankImport, bankImport.ID, map[string]any{
+		"format":       format,
+		"statement_id": statement.ID,
+		"file_hash":    fileHash,
+		"matched":      len(report.Matched),
+		"unmatched":    len(report.Unmatched),
+		"ambiguous":    len(report.Ambiguous),
+	})
+	return &BankImportResult{BankImport: bankImport, Report: report}, nil
+}
+
+func (l *WalletImpl) GetBankImport(ctx context.Context, importID string) (*BankImportResult, error) {
+	bankImport, err := l.dao.GetBankImport(importID)
+	if err != nil {
+		if errors.Is(err, dao.ErrBankImportNotFound) {
+			return nil, ErrBankImportNotFound
+		}
+		return nil, err
+	}
+	return bankImportResult(bankImport)
+}
+
+func bankImportResult(bankImport *dao.BankImport) (*BankImportResult, error) {
+	var report bankrec.Report
+	if err := json.Unmarshal([]byte(bankImport.ReportJSON), &report); err != nil {
+		return nil, fmt.Errorf("bank import %s has a corrupt report: %w", bankImport.ID, err)
+	}
+	return &BankImportResult{BankImport: *bankImport, Report: &report}, nil
+}
@@ -0,0 +1,122 @@
+package logic_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/bankrec"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const mt940Statement = ":20:STMT-1\r\n" +
+	":25:DE89370400440532013000\r\n" +
+	":60F:C260302USD100,00\r\n" +
+	":61:2603030303C150,00NTRF3f1c2a9e-8d4b-4c1e-9a57-0b6d2e4f8a11//BANK-1\r\n" +
+	":61:2603030303C20,00NTRFNONREF//BANK-2\r\n" +
+	":61:2603030303C99,00NTRFNONREF//BANK-3\r\n" +
+	":62F:C260303USD369,00\r\n"
+
+func TestImportBankStatement(t *testing.T) {
+	ctx := context.TODO()
+	sum := sha256.Sum256([]byte(mt940Statement))
+	fileHash := hex.EncodeToString(sum[:])
+	deposits := []dao.Transaction{
+		{ID: "3f1c2a9e-8d4b-4c1e-9a57-0b6d2e4f8a11", WalletID: "wallet-1", Type: "deposit", Amount: 150, CreatedAt: time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)},
+		{ID: "tx-2", WalletID: "wallet-2", Type: "deposit", Amount: 20, CreatedAt: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)},
+	}
+
+	t.Run("matches and stores the import", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetBankImportByHash", fileHash).Return(nil, dao.ErrBankImportNotFound).Once()
+		mockDao.On("ListUnmatchedDeposits", mock.Anything, mock.Anything).Return(deposits, nil).Once()
+
+		var stored *dao.BankImport
+		var storedMatches []dao.BankMatch
+		mockDao.On("CreateBankImport", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(0).(*dao.BankImport)
+			storedMatches = args.Get(1).([]dao.BankMatch)
+		}).Return(nil).Once()
+
+		result, err := impl.ImportBankStatement(ctx, "", []byte(mt940Statement), "operator-1")
+		assert.NoError(t, err)
+		assert.False(t, result.Duplicate)
+		assert.Equal(t, bankrec.FormatMT940, result.Format)
+		assert.Equal(t, 3, result.LineCount)
+		assert.Equal(t, 2, result.MatchedCount)
+		assert.Equal(t, 1, result.UnmatchedCount)
+		assert.Equal(t, "operator-1", stored.ImportedBy)
+		assert.Equal(t, fileHash, stored.FileHash)
+		assert.Contains(t, stored.ReportJSON, `"unmatched":[{`)
+
+		assert.Len(t, storedMatches, 2)
+		assert.Equal(t, deposits[0].ID, storedMatches[0].TransactionID)
+		assert.Equal(t, bankrec.MatchByReference, storedMatches[0].Method)
+		assert.Equal(t, "tx-2", storedMatches[1].TransactionID)
+		assert.Equal(t, "BANK-2", storedMatches[1].BankReference)
+		assert.Equal(t, stored.ID, storedMatches[1].ImportID)
+	})
+
+	t.Run("same file returns the earlier report", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetBankImportByHash", fileHash).Return(&dao.BankImport{
+			ID:           "import-1",
+			MatchedCount: 2,
+			ReportJSON:   `{"matched":[],"unmatched":[],"ambiguous":[],"unmatched_deposits":[],"skipped_debits":0}`,
+		}, nil).Once()
+
+		result, err := impl.ImportBankStatement(ctx, "", []byte(mt940Statement), "operator-1")
+		assert.NoError(t, err)
+		assert.True(t, result.Duplicate)
+		assert.Equal(t, "import-1", result.ID)
+		mockDao.AssertNotCalled(t, "CreateBankImport", mock.Anything, mock.Anything)
+	})
+
+	t.Run("malformed file", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetBankImportByHash", mock.Anything).Return(nil, dao.ErrBankImportNotFound).Once()
+
+		_, err := impl.ImportBankStatement(ctx, bankrec.FormatMT940, []byte(":20:X\n:61:yesterday\n"), "operator-1")
+		assert.ErrorIs(t, err, bankrec.ErrMalformed)
+	})
+
+	t.Run("operator required", func(t *testing.T) {
+		impl, _ := setupLogicTest()
+		_, err := impl.ImportBankStatement(ctx, "", []byte(mt940Statement), "")
+		assert.Equal(t, logic.ErrApproverRequired, err)
+	})
+
+	t.Run("store failure", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetBankImportByHash", fileHash).Return(nil, dao.ErrBankImportNotFound).Once()
+		mockDao.On("ListUnmatchedDeposits", mock.Anything, mock.Anything).Return([]dao.Transaction{}, nil).Once()
+		mockDao.On("CreateBankImport", mock.Anything, mock.Anything).Return(errors.New("db down")).Once()
+
+		_, err := impl.ImportBankStatement(ctx, "", []byte(mt940Statement), "operator-1")
+		assert.Error(t, err)
+	})
+}
+
+func TestGetBankImport(t *testing.T) {
+	ctx := context.TODO()
+	impl, mockDao := setupLogicTest()
+
+	mockDao.On("GetBankImport", "import-1").Return(&dao.BankImport{
+		ID:         "import-1",
+		ReportJSON: `{"matched":[],"unmatched":[],"ambiguous":[],"unmatched_deposits":["tx-9"],"skipped_debits":1}`,
+	}, nil).Once()
+	result, err := impl.GetBankImport(ctx, "import-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx-9"}, result.Report.UnmatchedDeposits)
+	assert.Equal(t, 1, result.Report.SkippedDebits)
+
+	mockDao.On("GetBankImport", "missing").Return(nil, dao.ErrBankImportNotFound).Once()
+	_, err = impl.GetBankImport(ctx, "missing")
+	assert.Equal(t, logic.ErrBankImportNotFound, err)
+}
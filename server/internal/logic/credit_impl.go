@@ -0,0 +1,96 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/fees"
+)
+
+var ErrInvalidCreditLimit = errors.New("credit limit must be zero or more and is for standard wallets only")
+
+// SetCreditLimit lets a standard wallet's balance go below zero down to -limit. A limit of zero
+// closes the credit line; a wallet already overdrawn past a lowered limit can only pay back.
+func (l *WalletImpl) SetCreditLimit(ctx context.Context, walletID string, limit float64, actorID string) (*dao.Wallet, error) {
+	limit = common.RoundToNDecimals(limit, 4)
+	if limit < 0 {
+		return nil, ErrInvalidCreditLimit
+	}
+
+	wallet, err := l.dao.GetWalletByID(walletID)
+	if err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, fmt.Errorf("set credit limit failed: %w", err)
+	}
+	if wallet.Type == common.WalletTypeSavings || wallet.Type == common.WalletTypePocket {
+		return nil, ErrInvalidCreditLimit
+	}
+
+	if err := l.dao.SetCreditLimit(walletID, limit); err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, fmt.Errorf("set credit limit failed: %w", err)
+	}
+
+	l.recordAudit(ctx, common.AuditActionCreditLimitSet, common.AuditEntityWallet, walletID, map[string]any{
+		"from_limit": wallet.CreditLimit,
+		"to_limit":   limit,
+		"set_by":     actorID,
+	})
+	wallet.CreditLimit = limit
+	return wallet, nil
+}
+
+// GetCreditLimit returns how far below zero a wallet's balance may go.
+func (l *WalletImpl) GetCreditLimit(ctx context.Context, walletID string) (float64, error) {
+	wallet, err := l.dao.GetWalletByID(walletID)
+	if err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return 0, ErrWalletNotFound
+		}
+		return 0, err
+	}
+	return wallet.CreditLimit, nil
+}
+
+// AvailableCredit is the part of a credit line a wallet with balance has not drawn yet.
+func AvailableCredit(balance, creditLimit float64) float64 {
+	return common.RoundToNDecimals(math.Max(0, creditLimit+math.Min(balance, 0)), 4)
+}
+
+// addOverdraftFee adds the overdraft fee to the quote of a movement out of a wallet with balance
+// when the movement and its fee take the wallet below zero. The overdraft rules of the schedule
+// are charged on the overdrawn part only.
+func (l *WalletImpl) addOverdraftFee(quote *fees.Quote, kycLevel, walletID string, balance float64) {
+	if !l.feesEnabled() || walletID == l.fees.WalletID {
+		return
+	}
+	overdrawn := common.RoundToNDecimals(quote.Total-math.Max(balance, 0), 4)
+	if overdrawn <= 0 {
+		return
+	}
+	overdraft := l.fees.Schedule.Quote(fees.Overdraft, kycLevel, overdrawn)
+	if overdraft.Fee <= 0 {
+		return
+	}
+	quote.Fee = common.RoundToNDecimals(quote.Fee+overdraft.Fee, 4)
+	quote.Total = common.RoundToNDecimals(quote.Total+overdraft.Fee, 4)
+	quote.Components = append(quote.Components, overdraft.Components...)
+}
+
+// overdraftFeeLeg charges the overdraft fee once for a payment made of several legs, on what
+// amount and the fees already charged for it take the wallet below zero. The fee leg points at
+// parentID. It returns the leg, or nil when nothing is overdrawn, and the fee.
+func (l *WalletImpl) overdraftFeeLeg(kycLevel, walletID, parentID string, balance, amount, fee float64) (*dao.TransferLeg, float64) {
+	quote := fees.Quote{Amount: amount, Fee: fee, Total: common.RoundToNDecimals(amount+fee, 4)}
+	l.addOverdraftFee(&quote, kycLevel, walletID, balance)
+	overdraft := common.RoundToNDecimals(quote.Fee-fee, 4)
+	return l.feeLeg(walletID, parentID, fees.Quote{Fee: overdraft}), overdraft
+}
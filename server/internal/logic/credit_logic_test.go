@@ -0,0 +1,178 @@
+package logic_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/dao/mocks"
+	"github.com/julkhong/walletapp/server/internal/fees"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func expectCreditWallet(mockDao *mocks.WalletDaoInterface, walletID string, creditLimit float64) {
+	mockDao.On("GetWalletByID", walletID).
+		Return(&dao.Wallet{ID: walletID, Status: common.WalletStatusActive, Type: common.WalletTypeStandard, CreditLimit: creditLimit}, nil).Maybe()
+}
+
+func TestWithdrawOnCredit(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("balance may go down to the credit limit", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectCreditWallet(mockDao, "wallet-1", 100)
+		mockDao.On("GetBalance", "wallet-1").Return(20.0, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil).Once()
+		mockDao.On("PostWithdrawal", mock.MatchedBy(func(w dao.Withdrawal) bool {
+			return w.Debit.WalletID == "wallet-1" && w.Debit.Amount == 100 && w.Fee == nil
+		})).Return(nil).Once()
+
+		assert.NoError(t, impl.Withdraw(ctx, "wallet-1", 100))
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("past the credit limit", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectCreditWallet(mockDao, "wallet-1", 100)
+		mockDao.On("GetBalance", "wallet-1").Return(-50.0, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil).Once()
+
+		assert.ErrorIs(t, impl.Withdraw(ctx, "wallet-1", 50.01), logic.ErrInsufficientBalance)
+		mockDao.AssertNotCalled(t, "PostWithdrawal", mock.Anything)
+	})
+}
+
+func TestTransferOverdraftFee(t *testing.T) {
+	ctx := context.TODO()
+	setup := func() (*logic.WalletImpl, *mocks.WalletDaoInterface) {
+		impl, mockDao := setupLogicTest()
+		impl.SetFeeConfig(logic.FeeConfig{
+			Schedule: fees.NewSchedule([]fees.Rule{
+				{Name: "transfer_flat", TransactionType: "transfer", Type: fees.RuleTypeFlat, Flat: 1},
+				{Name: "overdraft_flat", TransactionType: fees.Overdraft, Type: fees.RuleTypeFlat, Flat: 5},
+			}),
+			WalletID: feeWallet,
+		})
+		expectCreditWallet(mockDao, "wallet-from", 100)
+		expectActiveWallets(mockDao, "wallet-to")
+		mockDao.On("GetUserByWalletID", "wallet-from").Return(fullKycUser, nil).Once()
+		return impl, mockDao
+	}
+
+	t.Run("charged when the transfer overdraws", func(t *testing.T) {
+		impl, mockDao := setup()
+		mockDao.On("GetBalance", "wallet-from").Return(30.0, nil).Once()
+		mockDao.On("GetBalance", "wallet-to").Return(0.0, nil).Once()
+		mockDao.On("PostTransfers", transferWithFee("wallet-from", 50, 6)).Return(nil).Once()
+
+		fee, err := impl.Transfer(ctx, "wallet-from", "wallet-to", 50)
+		assert.NoError(t, err)
+		assert.Equal(t, 6.0, fee.Fee)
+		assert.Equal(t, 56.0, fee.Total)
+		assert.Equal(t, "overdraft_flat", fee.Components[1].Rule)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("not charged within the balance", func(t *testing.T) {
+		impl, mockDao := setup()
+		mockDao.On("GetBalance", "wallet-from").Return(51.0, nil).Once()
+		mockDao.On("GetBalance", "wallet-to").Return(0.0, nil).Once()
+		mockDao.On("PostTransfers", transferWithFee("wallet-from", 50, 1)).Return(nil).Once()
+
+		fee, err := impl.Transfer(ctx, "wallet-from", "wallet-to", 50)
+		assert.NoError(t, err)
+		assert.Equal(t, 1.0, fee.Fee)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("limit must cover the overdraft fee", func(t *testing.T) {
+		impl, mockDao := setup()
+		mockDao.On("GetBalance", "wallet-from").Return(-50.0, nil).Once()
+
+		_, err := impl.Transfer(ctx, "wallet-from", "wallet-to", 45)
+		assert.ErrorIs(t, err, logic.ErrInsufficientBalance)
+		mockDao.AssertNotCalled(t, "PostTransfers", mock.Anything)
+	})
+
+	t.Run("charged once on an overdrawing split payment", func(t *testing.T) {
+		impl, mockDao := setup()
+		expectActiveWallets(mockDao, "wallet-c")
+		mockDao.On("GetBalance", "wallet-from").Return(30.0, nil).Once()
+		mockDao.On("PostTransfers", mock.MatchedBy(func(legs []dao.TransferLeg) bool {
+			return len(legs) == 5 && legs[2].Amount == 1 && legs[3].Amount == 1 &&
+				legs[4].ToWalletID == feeWallet && legs[4].Amount == 5 && *legs[4].ParentID == legs[0].DebitID &&
+				legs[4].GroupID != nil && *legs[4].GroupID == *legs[0].GroupID
+		})).Return(nil).Once()
+
+		result, err := impl.SplitPayment(ctx, logic.SplitPaymentRequest{
+			FromWalletID: "wallet-from",
+			Shares:       []logic.SplitShare{amountShare("wallet-to", 30), amountShare("wallet-c", 20)},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 7.0, result.Fee)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("charged once on an overdrawing all or nothing batch", func(t *testing.T) {
+		impl, mockDao := setup()
+		expectActiveWallets(mockDao, "wallet-c")
+		batch := dao.TransferBatch{ID: "batch-1", FromWalletID: "wallet-from", Mode: common.BatchModeAllOrNothing,
+			ItemCount: 2, TotalAmount: 50, Status: common.BatchStatusPending}
+		mockDao.On("ListStalledTransferBatches", mock.Anything, 20).Return([]dao.TransferBatch{}, nil).Once()
+		mockDao.On("ListPendingTransferBatches", 20).Return([]dao.TransferBatch{batch}, nil).Once()
+		mockDao.On("ClaimTransferBatch", "batch-1").Return(nil).Once()
+		mockDao.On("ListTransferBatchItems", "batch-1").Return([]dao.TransferBatchItem{
+			{ID: "item-1", Seq: 1, ToWalletID: "wallet-to", Amount: 30},
+			{ID: "item-2", Seq: 2, ToWalletID: "wallet-c", Amount: 20},
+		}, nil).Once()
+		mockDao.On("GetBalance", "wallet-from").Return(30.0, nil).Once()
+		mockDao.On("ExecuteTransferBatch", mock.Anything, []string{"item-1", "item-2"}, mock.MatchedBy(func(legs []dao.TransferLeg) bool {
+			return len(legs) == 5 && legs[2].Amount == 1 && legs[3].Amount == 1 &&
+				legs[4].ToWalletID == feeWallet && legs[4].Amount == 5 && *legs[4].ParentID == legs[0].DebitID
+		})).Return(nil).Once()
+
+		_, err := impl.ProcessTransferBatches(ctx)
+		assert.NoError(t, err)
+		mockDao.AssertExpectations(t)
+	})
+}
+
+func TestSetCreditLimit(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("standard wallet", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectCreditWallet(mockDao, "wallet-1", 0)
+		mockDao.On("SetCreditLimit", "wallet-1", 250.0).Return(nil).Once()
+
+		wallet, err := impl.SetCreditLimit(ctx, "wallet-1", 250, "operator-1")
+		assert.NoError(t, err)
+		assert.Equal(t, 250.0, wallet.CreditLimit)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("savings wallet", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetWalletByID", "wallet-s").Return(&dao.Wallet{ID: "wallet-s", Type: common.WalletTypeSavings}, nil).Once()
+
+		_, err := impl.SetCreditLimit(ctx, "wallet-s", 250, "operator-1")
+		assert.ErrorIs(t, err, logic.ErrInvalidCreditLimit)
+		mockDao.AssertNotCalled(t, "SetCreditLimit", mock.Anything, mock.Anything)
+	})
+
+	t.Run("negative limit", func(t *testing.T) {
+		impl, _ := setupLogicTest()
+		_, err := impl.SetCreditLimit(ctx, "wallet-1", -1, "operator-1")
+		assert.ErrorIs(t, err, logic.ErrInvalidCreditLimit)
+	})
+}
+
+func TestAvailableCredit(t *testing.T) {
+	assert.Equal(t, 500.0, logic.AvailableCredit(150, 500))
+	assert.Equal(t, 380.0, logic.AvailableCredit(-120, 500))
+	assert.Equal(t, 0.0, logic.AvailableCredit(-600, 500))
+	assert.Equal(t, 0.0, logic.AvailableCredit(150, 0))
+}
@@ -0,0 +1,331 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+)
+
+var (
+	ErrEscrowDisabled         = errors.New("escrow is not configured")
+	ErrEscrowNotFound         = errors.New("escrow not found")
+	ErrEscrowStatusConflict   = errors.New("escrow cannot change to that status")
+	ErrInvalidEscrow          = errors.New("invalid escrow")
+	ErrInvalidEscrowOutcome   = errors.New("outcome must be release or refund")
+	ErrEscrowRequiresApproval = errors.New("amount requires approval and cannot be held in escrow")
+	ErrEscrowNeedsReview      = errors.New("escrow payment was flagged for review")
+)
+
+const (
+	// maxEscrowExpiryBatch bounds the expired escrows one timeout pass refunds.
+	maxEscrowExpiryBatch = 200
+	// escrowTimeoutActor is recorded as the actor of refunds made on timeout.
+	escrowTimeoutActor = "escrow-timeout"
+)
+
+const (
+	EscrowOutcomeRelease = "release"
+	EscrowOutcomeRefund  = "refund"
+)
+
+// EscrowConfig is the system wallet holding the money of open escrows and how long an escrow is
+// held before it is refunded when the request names no expiry.
+type EscrowConfig struct {
+	WalletID string
+	Timeout  time.Duration
+}
+
+// SetEscrowConfig enables escrow. Without an escrow wallet no escrow can be opened.
+func (l *WalletImpl) SetEscrowConfig(cfg EscrowConfig) {
+	l.escrow = cfg
+}
+
+// EscrowRequest moves Amount from the buyer into escrow for the seller. ExpiresAt defaults to
+// the configured timeout from now.
+type EscrowRequest struct {
+	BuyerWalletID  string
+	SellerWalletID string
+	Amount         float64
+	Reference      string
+	ExpiresAt      *time.Time
+	CreatedBy      string
+}
+
+// CreateEscrow holds a buyer's payment in the escrow wallet. The buyer is charged the transfer
+// fee and is subject to the same KYC, sanctions and risk checks as a transfer to the seller;
+// a payment risk screening would send to review is rejected rather than parked, since a
+// review cannot open the escrow afterwards.
+func (l *WalletImpl) CreateEscrow(ctx context.Context, req EscrowRequest) (*dao.Escrow, error) {
+	if l.escrow.WalletID == "" {
+		return nil, ErrEscrowDisabled
+	}
+	amount := common.RoundToNDecimals(req.Amount, 4)
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidEscrow)
+	}
+	if req.BuyerWalletID == req.SellerWalletID {
+		return nil, fmt.Errorf("%w: buyer and seller wallets must differ", ErrInvalidEscrow)
+	}
+	if req.BuyerWalletID == l.escrow.WalletID || req.SellerWalletID == l.escrow.WalletID {
+		return nil, fmt.Errorf("%w: the escrow wallet cannot be a party", ErrInvalidEscrow)
+	}
+	now := time.Now()
+	expiresAt := now.Add(l.escrow.Timeout)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			return nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidEscrow)
+		}
+		expiresAt = *req.ExpiresAt
+	}
+	if l.RequiresApproval(amount) {
+		return nil, ErrEscrowRequiresApproval
+	}
+	l.logger.Infof("Opening escrow of %.4f from wallet %s for %s", amount, req.BuyerWalletID, req.SellerWalletID)
+
+	buyer, err := l.activeWallet(req.BuyerWalletID)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.ensureWalletActive(req.SellerWalletID); err != nil {
+		return nil, err
+	}
+	kycLevel, rule, err := l.kycForWallet(req.BuyerWalletID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.MaxTransferAmount > 0 && amount > rule.MaxTransferAmount {
+		l.logger.Warnf("KYC transfer limit exceeded: requested=%.4f, max=%.4f", amount, rule.MaxTransferAmount)
+		return nil, ErrKycTransferLimitExceeded
+	}
+	if err := l.ScreenTransferParties(ctx, req.BuyerWalletID, req.SellerWalletID); err != nil {
+		return nil, err
+	}
+	if err := l.screenWithoutReview(ctx, req.BuyerWalletID, req.SellerWalletID, amount, ErrEscrowNeedsReview); err != nil {
+		return nil, err
+	}
+
+	balance, err := l.dao.GetBalance(req.BuyerWalletID)
+	if err != nil {
+		return nil, fmt.Errorf("escrow failed: %w", err)
+	}
+	fee := l.quoteFee(common.TransactionTypeTransfer, kycLevel, req.BuyerWalletID, amount)
+	l.addOverdraftFee(&fee, kycLevel, req.BuyerWalletID, balance)
+	if balance+buyer.CreditLimit < fee.Total {
+		l.logger.Warnf("Insufficient funds for escrow: current=%.4f, credit_limit=%.4f, requested=%.4f, fee=%.4f",
+			balance, buyer.CreditLimit, amount, fee.Fee)
+		return nil, ErrInsufficientBalance
+	}
+
+	hold := dao.TransferLeg{
+		FromWalletID: req.BuyerWalletID,
+		ToWalletID:   l.escrow.WalletID,
+		Amount:       amount,
+		DebitID:      uuid.NewString(),
+		CreditID:     uuid.NewString(),
+		Type:         common.TransactionTypeEscrowHold,
+		InitiatedBy:  initiator(ctx),
+	}
+	legs := []dao.TransferLeg{hold}
+	if leg := l.feeLeg(req.BuyerWalletID, hold.DebitID, fee); leg != nil {
+		legs = append(legs, *leg)
+	}
+
+	escrow := &dao.Escrow{
+		ID:                uuid.NewString(),
+		BuyerWalletID:     req.BuyerWalletID,
+		SellerWalletID:    req.SellerWalletID,
+		Amount:            amount,
+		Reference:         req.Reference,
+		Status:            common.EscrowStatusHeld,
+		ExpiresAt:         expiresAt.UTC(),
+		HoldTransactionID: hold.DebitID,
+		CreatedBy:         req.CreatedBy,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := l.dao.CreateEscrow(escrow, legs); err != nil {
+		if errors.Is(err, dao.ErrInsufficientFunds) {
+			return nil, ErrInsufficientBalance
+		}
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, fmt.Errorf("escrow failed: %w", err)
+	}
+
+	l.recordAudit(ctx, common.AuditActionEscrowCreated, common.AuditEntityEscrow, escrow.ID, map[string]any{
+		"buyer_wallet_id":  escrow.BuyerWalletID,
+		"seller_wallet_id": escrow.SellerWalletID,
+		"amount":           amount,
+		"fee":              fee.Fee,
+		"expires_at":       escrow.ExpiresAt,
+	})
+	return escrow, nil
+}
+
+func (l *WalletImpl) GetEscrow(ctx context.Context, escrowID string) (*dao.Escrow, error) {
+	escrow, err := l.dao.GetEscrow(escrowID)
+	if err != nil {
+		if errors.Is(err, dao.ErrEscrowNotFound) {
+			return nil, ErrEscrowNotFound
+		}
+		return nil, err
+	}
+	return escrow, nil
+}
+
+// ReleaseEscrow pays a held escrow out to the seller once the buyer confirms delivery.
+func (l *WalletImpl) ReleaseEscrow(ctx context.Context, escrowID, actor string) (*dao.Escrow, error) {
+	escrow, err := l.GetEscrow(ctx, escrowID)
+	if err != nil {
+		return nil, err
+	}
+	return l.settleEscrow(ctx, escrow, []string{common.EscrowStatusHeld}, common.EscrowStatusReleased, actor)
+}
+
+// RefundEscrow returns a held or disputed escrow to the buyer, when the seller cancels the sale.
+func (l *WalletImpl) RefundEscrow(ctx context.Context, escrowID, actor string) (*dao.Escrow, error) {
+	escrow, err := l.GetEscrow(ctx, escrowID)
+	if err != nil {
+		return nil, err
+	}
+	from := []string{common.EscrowStatusHeld, common.EscrowStatusDisputed}
+	return l.settleEscrow(ctx, escrow, from, common.EscrowStatusRefunded, actor)
+}
+
+// DisputeEscrow blocks the release and the timeout of a held escrow until an operator resolves it.
+func (l *WalletImpl) DisputeEscrow(ctx context.Context, escrowID, reason, actor string) (*dao.Escrow, error) {
+	escrow, err := l.GetEscrow(ctx, escrowID)
+	if err != nil {
+		return nil, err
+	}
+	if escrow.Status != common.EscrowStatusHeld {
+		return nil, ErrEscrowStatusConflict
+	}
+
+	now := time.Now()
+	if err := l.dao.DisputeEscrow(escrow.ID, reason, actor, now); err != nil {
+		if errors.Is(err, dao.ErrEscrowNotFound) {
+			// settled or disputed by someone else between the read and the update
+			return nil, ErrEscrowStatusConflict
+		}
+		return nil, err
+	}
+
+	l.recordAudit(ctx, common.AuditActionEscrowStatus, common.AuditEntityEscrow, escrow.ID, map[string]any{
+		"from":   escrow.Status,
+		"to":     common.EscrowStatusDisputed,
+		"reason": reason,
+		"actor":  actor,
+	})
+	escrow.Status = common.EscrowStatusDisputed
+	escrow.DisputeReason = &reason
+	escrow.DisputedBy = &actor
+	escrow.UpdatedAt = now
+	return escrow, nil
+}
+
+// ResolveEscrow settles a disputed escrow as an operator decided: released to the seller or
+// refunded to the buyer.
+func (l *WalletImpl) ResolveEscrow(ctx context.Context, escrowID, outcome, operator string) (*dao.Escrow, error) {
+	var to string
+	switch outcome {
+	case EscrowOutcomeRelease:
+		to = common.EscrowStatusReleased
+	case EscrowOutcomeRefund:
+		to = common.EscrowStatusRefunded
+	default:
+		return nil, ErrInvalidEscrowOutcome
+	}
+
+	escrow, err := l.GetEscrow(ctx, escrowID)
+	if err != nil {
+		return nil, err
+	}
+	return l.settleEscrow(ctx, escrow, []string{common.EscrowStatusDisputed}, to, operator)
+}
+
+// ExpireEscrows refunds held escrows past their expiry to their buyers. Disputed escrows wait for
+// an operator. An escrow that fails to refund is left for the next pass. It returns the number
+// of escrows refunded.
+func (l *WalletImpl) ExpireEscrows(ctx context.Context) (int, error) {
+	escrows, err := l.dao.ListExpiredEscrows(time.Now(), maxEscrowExpiryBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	refunded := 0
+	for i := range escrows {
+		escrow := &escrows[i]
+		if _, err := l.settleEscrow(ctx, escrow, []string{common.EscrowStatusHeld}, common.EscrowStatusRefunded, escrowTimeoutActor); err != nil {
+			if !errors.Is(err, ErrEscrowStatusConflict) {
+				l.logger.WithError(err).Errorf("Failed to refund expired escrow %s", escrow.ID)
+			}
+			continue
+		}
+		refunded++
+	}
+	if refunded > 0 {
+		l.logger.Infof("Refunded %d expired escrows", refunded)
+	}
+	return refunded, nil
+}
+
+// settleEscrow pays an escrow in one of from out of the escrow wallet: to the seller when it is
+// released, back to the buyer when it is refunded. Both rows point at the hold. Refunds go back
+// whatever the buyer wallet's status, as the money is theirs; releases need an active seller.
+func (l *WalletImpl) settleEscrow(ctx context.Context, escrow *dao.Escrow, from []string, to, actor string) (*dao.Escrow, error) {
+	if !slices.Contains(from, escrow.Status) {
+		return nil, ErrEscrowStatusConflict
+	}
+
+	payee, txType := escrow.BuyerWalletID, common.TransactionTypeEscrowRefund
+	if to == common.EscrowStatusReleased {
+		payee, txType = escrow.SellerWalletID, common.TransactionTypeEscrowRelease
+		if err := l.ensureWalletActive(payee); err != nil {
+			return nil, err
+		}
+	}
+
+	leg := dao.TransferLeg{
+		FromWalletID: l.escrow.WalletID,
+		ToWalletID:   payee,
+		Amount:       escrow.Amount,
+		DebitID:      uuid.NewString(),
+		CreditID:     uuid.NewString(),
+		Type:         txType,
+		ParentID:     &escrow.HoldTransactionID,
+		InitiatedBy:  initiator(ctx),
+	}
+	now := time.Now()
+	previous := escrow.Status
+	escrow.Status = to
+	escrow.SettlementTransactionID = &leg.CreditID
+	escrow.SettledAt = &now
+	escrow.UpdatedAt = now
+	if err := l.dao.SettleEscrow(escrow, from, []dao.TransferLeg{leg}); err != nil {
+		escrow.Status, escrow.SettlementTransactionID, escrow.SettledAt = previous, nil, nil
+		if errors.Is(err, dao.ErrEscrowNotFound) {
+			// settled or disputed by someone else between the read and the update
+			return nil, ErrEscrowStatusConflict
+		}
+		return nil, fmt.Errorf("escrow settlement failed: %w", err)
+	}
+
+	l.recordAudit(ctx, common.AuditActionEscrowStatus, common.AuditEntityEscrow, escrow.ID, map[string]any{
+		"from":           previous,
+		"to":             to,
+		"amount":         escrow.Amount,
+		"payee":          payee,
+		"transaction_id": leg.CreditID,
+		"actor":          actor,
+	})
+	return escrow, nil
+}
@@ -0,0 +1,179 @@
+package logic_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const escrowWallet = "wallet-escrow"
+
+func setupEscrow(impl *logic.WalletImpl) {
+	impl.SetEscrowConfig(logic.EscrowConfig{WalletID: escrowWallet, Timeout: 72 * time.Hour})
+}
+
+func heldEscrow(status string) *dao.Escrow {
+	return &dao.Escrow{ID: "escrow-1", BuyerWalletID: "wallet-buyer", SellerWalletID: "wallet-seller", Amount: 40,
+		Status: status, ExpiresAt: time.Now().Add(time.Hour), HoldTransactionID: "tx-hold"}
+}
+
+// settlementLeg matches the single leg paying an escrow of 40 out to walletID.
+func settlementLeg(walletID, txType string) any {
+	return mock.MatchedBy(func(legs []dao.TransferLeg) bool {
+		return len(legs) == 1 && legs[0].FromWalletID == escrowWallet && legs[0].ToWalletID == walletID &&
+			legs[0].Amount == 40 && legs[0].Type == txType && *legs[0].ParentID == "tx-hold"
+	})
+}
+
+func TestCreateEscrow(t *testing.T) {
+	ctx := context.TODO()
+	req := logic.EscrowRequest{BuyerWalletID: "wallet-buyer", SellerWalletID: "wallet-seller", Amount: 50, Reference: "order-7"}
+
+	t.Run("holds the payment with its fee", func(t *testing.T) {
+		impl, mockDao := setupFeeTest()
+		setupEscrow(impl)
+		expectActiveWallets(mockDao, "wallet-buyer", "wallet-seller")
+		mockDao.On("GetUserByWalletID", "wallet-buyer").Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", "wallet-buyer").Return(100.0, nil).Once()
+		mockDao.On("CreateEscrow", mock.MatchedBy(func(e *dao.Escrow) bool {
+			return e.Status == common.EscrowStatusHeld && e.Amount == 50 && e.Reference == "order-7" &&
+				e.ExpiresAt.Sub(e.CreatedAt) == 72*time.Hour
+		}), mock.MatchedBy(func(legs []dao.TransferLeg) bool {
+			return len(legs) == 2 &&
+				legs[0].ToWalletID == escrowWallet && legs[0].Amount == 50 && legs[0].Type == common.TransactionTypeEscrowHold &&
+				legs[1].ToWalletID == feeWallet && legs[1].Amount == 0.5 && *legs[1].ParentID == legs[0].DebitID
+		})).Run(func(args mock.Arguments) {
+			escrow, legs := args.Get(0).(*dao.Escrow), args.Get(1).([]dao.TransferLeg)
+			assert.Equal(t, legs[0].DebitID, escrow.HoldTransactionID)
+		}).Return(nil).Once()
+
+		escrow, err := impl.CreateEscrow(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, "wallet-seller", escrow.SellerWalletID)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		impl, mockDao := setupFeeTest()
+		setupEscrow(impl)
+		expectActiveWallets(mockDao, "wallet-buyer", "wallet-seller")
+		mockDao.On("GetUserByWalletID", "wallet-buyer").Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", "wallet-buyer").Return(50.0, nil).Once()
+
+		_, err := impl.CreateEscrow(ctx, req)
+		assert.ErrorIs(t, err, logic.ErrInsufficientBalance)
+		mockDao.AssertNotCalled(t, "CreateEscrow", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		impl, _ := setupLogicTest()
+		_, err := impl.CreateEscrow(ctx, req)
+		assert.ErrorIs(t, err, logic.ErrEscrowDisabled)
+
+		setupEscrow(impl)
+		same := req
+		same.SellerWalletID = same.BuyerWalletID
+		_, err = impl.CreateEscrow(ctx, same)
+		assert.ErrorIs(t, err, logic.ErrInvalidEscrow)
+
+		past := time.Now().Add(-time.Minute)
+		expired := req
+		expired.ExpiresAt = &past
+		_, err = impl.CreateEscrow(ctx, expired)
+		assert.ErrorIs(t, err, logic.ErrInvalidEscrow)
+
+		impl.SetApprovalPolicy(logic.ApprovalPolicy{TransferThreshold: 10})
+		_, err = impl.CreateEscrow(ctx, req)
+		assert.ErrorIs(t, err, logic.ErrEscrowRequiresApproval)
+	})
+}
+
+func TestReleaseEscrow(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("pays the seller", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		setupEscrow(impl)
+		expectActiveWallets(mockDao, "wallet-seller")
+		mockDao.On("GetEscrow", "escrow-1").Return(heldEscrow(common.EscrowStatusHeld), nil).Once()
+		mockDao.On("SettleEscrow", mock.MatchedBy(func(e *dao.Escrow) bool {
+			return e.Status == common.EscrowStatusReleased && e.SettlementTransactionID != nil && e.SettledAt != nil
+		}), []string{common.EscrowStatusHeld}, settlementLeg("wallet-seller", common.TransactionTypeEscrowRelease)).
+			Return(nil).Once()
+
+		escrow, err := impl.ReleaseEscrow(ctx, "escrow-1", "user-buyer")
+		assert.NoError(t, err)
+		assert.Equal(t, common.EscrowStatusReleased, escrow.Status)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("dispute blocks the release", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		setupEscrow(impl)
+		mockDao.On("GetEscrow", "escrow-1").Return(heldEscrow(common.EscrowStatusDisputed), nil).Once()
+
+		_, err := impl.ReleaseEscrow(ctx, "escrow-1", "user-buyer")
+		assert.ErrorIs(t, err, logic.ErrEscrowStatusConflict)
+		mockDao.AssertNotCalled(t, "SettleEscrow", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("settled concurrently", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		setupEscrow(impl)
+		expectActiveWallets(mockDao, "wallet-seller")
+		mockDao.On("GetEscrow", "escrow-1").Return(heldEscrow(common.EscrowStatusHeld), nil).Once()
+		mockDao.On("SettleEscrow", mock.Anything, mock.Anything, mock.Anything).Return(dao.ErrEscrowNotFound).Once()
+
+		_, err := impl.ReleaseEscrow(ctx, "escrow-1", "user-buyer")
+		assert.ErrorIs(t, err, logic.ErrEscrowStatusConflict)
+	})
+}
+
+func TestDisputeAndResolveEscrow(t *testing.T) {
+	ctx := context.TODO()
+	impl, mockDao := setupLogicTest()
+	setupEscrow(impl)
+
+	mockDao.On("GetEscrow", "escrow-1").Return(heldEscrow(common.EscrowStatusHeld), nil).Once()
+	mockDao.On("DisputeEscrow", "escrow-1", "item not received", "user-buyer", mock.Anything).Return(nil).Once()
+	escrow, err := impl.DisputeEscrow(ctx, "escrow-1", "item not received", "user-buyer")
+	assert.NoError(t, err)
+	assert.Equal(t, common.EscrowStatusDisputed, escrow.Status)
+
+	_, err = impl.ResolveEscrow(ctx, "escrow-1", "split", "ops-1")
+	assert.ErrorIs(t, err, logic.ErrInvalidEscrowOutcome)
+
+	mockDao.On("GetEscrow", "escrow-1").Return(heldEscrow(common.EscrowStatusDisputed), nil).Once()
+	mockDao.On("SettleEscrow", mock.Anything, []string{common.EscrowStatusDisputed},
+		settlementLeg("wallet-buyer", common.TransactionTypeEscrowRefund)).Return(nil).Once()
+	escrow, err = impl.ResolveEscrow(ctx, "escrow-1", logic.EscrowOutcomeRefund, "ops-1")
+	assert.NoError(t, err)
+	assert.Equal(t, common.EscrowStatusRefunded, escrow.Status)
+	mockDao.AssertExpectations(t)
+}
+
+func TestExpireEscrows(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	setupEscrow(impl)
+	first, second := *heldEscrow(common.EscrowStatusHeld), *heldEscrow(common.EscrowStatusHeld)
+	second.ID = "escrow-2"
+	mockDao.On("ListExpiredEscrows", mock.Anything, mock.Anything).Return([]dao.Escrow{first, second}, nil).Once()
+	mockDao.On("SettleEscrow", mock.MatchedBy(func(e *dao.Escrow) bool { return e.ID == "escrow-1" }),
+		[]string{common.EscrowStatusHeld}, settlementLeg("wallet-buyer", common.TransactionTypeEscrowRefund)).
+		Return(errors.New("connection reset")).Once()
+	mockDao.On("SettleEscrow", mock.MatchedBy(func(e *dao.Escrow) bool { return e.ID == "escrow-2" }),
+		[]string{common.EscrowStatusHeld}, settlementLeg("wallet-buyer", common.TransactionTypeEscrowRefund)).
+		Return(nil).Once()
+
+	refunded, err := impl.ExpireEscrows(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, refunded)
+	mockDao.AssertExpectations(t)
+}
@@ -0,0 +1,63 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/export"
+)
+
+// ExportTransactions streams every transaction matching the filter into out, encoded as format.
+// Paging fields of the filter are ignored. Nothing is written to out when the request is rejected,
+// so callers can still report those errors as a regular response.
+func (l *WalletImpl) ExportTransactions(ctx context.Context, filter dao.TransactionFilter, format string, out io.Writer) error {
+	if !export.IsSupported(format) {
+		return export.ErrUnsupportedFormat
+	}
+	filter.Limit, filter.After = 0, nil
+	if err := validateTransactionFilter(&filter); err != nil {
+		return err
+	}
+
+	wallet, err := l.dao.GetWalletByID(filter.WalletID)
+	if err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return ErrWalletNotFound
+		}
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	now := time.Now()
+	header := export.Header{WalletID: wallet.ID, Start: wallet.CreatedAt, End: now, GeneratedAt: now}
+	if filter.Start != nil {
+		header.Start = *filter.Start
+	}
+	if filter.End != nil {
+		header.End = *filter.End
+	}
+
+	w, err := export.NewWriter(format, out, header)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	err = l.dao.StreamTransactionHistory(filter, func(tx *dao.Transaction) error {
+		count++
+		return w.Write(tx)
+	})
+	if err != nil {
+		l.logger.WithError(err).Errorf("Export of wallet %s failed after %d transactions", wallet.ID, count)
+		return fmt.Errorf("export failed: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+
+	l.logger.Infof("Exported %d transactions of wallet %s as %s", count, wallet.ID, format)
+	return nil
+}
@@ -0,0 +1,77 @@
+package logic_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/export"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExportTransactions(t *testing.T) {
+	ctx := context.TODO()
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("streams every matching row", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetWalletByID", "wallet-1").Return(&dao.Wallet{ID: "wallet-1", CreatedAt: created}, nil).Once()
+		mockDao.On("StreamTransactionHistory", mock.MatchedBy(func(f dao.TransactionFilter) bool {
+			return f.WalletID == "wallet-1" && f.Type == "deposit" && f.Limit == 0 && f.After == nil
+		}), mock.Anything).Run(func(args mock.Arguments) {
+			fn := args.Get(1).(func(*dao.Transaction) error)
+			_ = fn(&dao.Transaction{ID: "tx-1", Type: "deposit", Amount: 10, CreatedAt: created})
+			_ = fn(&dao.Transaction{ID: "tx-2", Type: "deposit", Amount: 2.5, CreatedAt: created})
+		}).Return(nil).Once()
+
+		var out bytes.Buffer
+		err := impl.ExportTransactions(ctx, dao.TransactionFilter{
+			WalletID: "wallet-1",
+			Type:     "deposit",
+			Limit:    500,
+			After:    &dao.TransactionCursor{ID: "tx-0"},
+		}, export.FormatCSV, &out)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, strings.Count(out.String(), "\n"))
+		assert.Contains(t, out.String(), "tx-2,2026-01-01T00:00:00Z,deposit,2.5000,,")
+	})
+
+	t.Run("rejected before anything is written", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetWalletByID", "missing").Return(nil, dao.ErrWalletNotFound).Once()
+		start, end := created.Add(time.Hour), created
+
+		for _, tc := range []struct {
+			name   string
+			filter dao.TransactionFilter
+			format string
+			want   error
+		}{
+			{"unknown format", dao.TransactionFilter{WalletID: "wallet-1"}, "xlsx", export.ErrUnsupportedFormat},
+			{"bad type", dao.TransactionFilter{WalletID: "wallet-1", Type: "gift"}, export.FormatCSV, logic.ErrInvalidTxType},
+			{"bad range", dao.TransactionFilter{WalletID: "wallet-1", Start: &start, End: &end}, export.FormatOFX, logic.ErrInvalidTimeRange},
+			{"unknown wallet", dao.TransactionFilter{WalletID: "missing"}, export.FormatJSONL, logic.ErrWalletNotFound},
+		} {
+			var out bytes.Buffer
+			err := impl.ExportTransactions(ctx, tc.filter, tc.format, &out)
+			assert.ErrorIs(t, err, tc.want, tc.name)
+			assert.Zero(t, out.Len(), tc.name)
+		}
+		mockDao.AssertNotCalled(t, "StreamTransactionHistory", mock.Anything, mock.Anything)
+	})
+
+	t.Run("cursor failure", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetWalletByID", "wallet-1").Return(&dao.Wallet{ID: "wallet-1"}, nil).Once()
+		mockDao.On("StreamTransactionHistory", mock.Anything, mock.Anything).Return(errors.New("connection reset")).Once()
+
+		err := impl.ExportTransactions(ctx, dao.TransactionFilter{WalletID: "wallet-1"}, export.FormatJSONL, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "connection reset")
+	})
+}
@@ -0,0 +1,80 @@
+package logic
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/fees"
+)
+
+var ErrInvalidFeeQuote = errors.New("fees are quoted for transfer and withdraw only")
+
+// FeeConfig is the fee schedule charged on transfers and withdrawals and the system wallet that
+// collects the fees.
+type FeeConfig struct {
+	Schedule *fees.Schedule
+	WalletID string
+}
+
+// SetFeeConfig enables fees. Without a schedule and fee wallet nothing is charged.
+func (l *WalletImpl) SetFeeConfig(cfg FeeConfig) {
+	l.fees = cfg
+}
+
+// QuoteFee previews the fee a transfer or withdrawal of amount out of walletID would be charged,
+// including the overdraft fee when it would take the wallet below zero.
+func (l *WalletImpl) QuoteFee(ctx context.Context, txType, walletID string, amount float64) (*fees.Quote, error) {
+	if txType != common.TransactionTypeTransfer && txType != common.TransactionTypeWithdraw {
+		return nil, ErrInvalidFeeQuote
+	}
+	if err := l.ensureWalletActive(walletID); err != nil {
+		return nil, err
+	}
+	kycLevel, _, err := l.kycForWallet(walletID)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := l.dao.GetBalance(walletID)
+	if err != nil {
+		return nil, err
+	}
+	quote := l.quoteFee(txType, kycLevel, walletID, amount)
+	l.addOverdraftFee(&quote, kycLevel, walletID, balance)
+	return &quote, nil
+}
+
+// quoteFee computes the fee of a movement out of walletID. The fee wallet itself pays no fees.
+func (l *WalletImpl) quoteFee(txType, kycLevel, walletID string, amount float64) fees.Quote {
+	if !l.feesEnabled() || walletID == l.fees.WalletID {
+		amount = common.RoundToNDecimals(amount, 4)
+		return fees.Quote{TransactionType: txType, KycLevel: kycLevel, Amount: amount, Total: amount, Components: []fees.Component{}}
+	}
+	return l.fees.Schedule.Quote(txType, kycLevel, amount)
+}
+
+// feeLeg moves the quoted fee from the paying wallet into the fee wallet as fee rows pointing at
+// the parent transaction. It is posted with the parent, so a movement is never left without its
+// fee. It returns nil when there is no fee.
+func (l *WalletImpl) feeLeg(walletID, parentID string, quote fees.Quote) *dao.TransferLeg {
+	if quote.Fee <= 0 {
+		return nil
+	}
+	return &dao.TransferLeg{
+		FromWalletID: walletID,
+		ToWalletID:   l.fees.WalletID,
+		Amount:       quote.Fee,
+		DebitID:      uuid.NewString(),
+		CreditID:     uuid.NewString(),
+		Type:         common.TransactionTypeFee,
+		ParentID:     &parentID,
+	}
+}
+
+// feesEnabled reports whether movements are charged fees.
+func (l *WalletImpl) feesEnabled() bool {
+	return l.fees.Schedule != nil && l.fees.WalletID != ""
+}
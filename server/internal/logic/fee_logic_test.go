@@ -0,0 +1,186 @@
+package logic_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/dao/mocks"
+	"github.com/julkhong/walletapp/server/internal/fees"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const feeWallet = "wallet-fees"
+
+func setupFeeTest() (*logic.WalletImpl, *mocks.WalletDaoInterface) {
+	impl, mockDao := setupLogicTest()
+	impl.SetFeeConfig(logic.FeeConfig{
+		Schedule: fees.NewSchedule([]fees.Rule{
+			{Name: "transfer_percentage", TransactionType: "transfer", Type: fees.RuleTypePercentage, Percent: 1, Max: 5},
+			{Name: "withdraw_flat", TransactionType: "withdraw", Type: fees.RuleTypeFlat, Flat: 2},
+		}),
+		WalletID: feeWallet,
+	})
+	return impl, mockDao
+}
+
+// isFeeLeg reports whether leg moves amount out of walletID into the fee wallet on parentID.
+func isFeeLeg(leg dao.TransferLeg, walletID string, amount float64, parentID string) bool {
+	return leg.FromWalletID == walletID && leg.ToWalletID == feeWallet && leg.Amount == amount &&
+		leg.Type == common.TransactionTypeFee && leg.ParentID != nil && *leg.ParentID == parentID
+}
+
+// transferWithFee matches a transfer of amount out of walletID posted with its fee.
+func transferWithFee(walletID string, amount, fee float64) any {
+	return mock.MatchedBy(func(legs []dao.TransferLeg) bool {
+		return len(legs) == 2 && legs[0].FromWalletID == walletID && legs[0].Amount == amount &&
+			isFeeLeg(legs[1], walletID, fee, legs[0].DebitID)
+	})
+}
+
+// withdrawalWithFee matches a withdrawal of amount out of walletID posted with its fee.
+func withdrawalWithFee(walletID string, amount, fee float64) any {
+	return mock.MatchedBy(func(w dao.Withdrawal) bool {
+		return w.Debit.WalletID == walletID && w.Debit.Amount == amount && w.Fee != nil &&
+			isFeeLeg(*w.Fee, walletID, fee, w.Debit.ID)
+	})
+}
+
+func TestTransferWithFee(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("fee is charged on top and linked to the transfer", func(t *testing.T) {
+		impl, mockDao := setupFeeTest()
+		expectActiveWallets(mockDao, "wallet-from", "wallet-to")
+		mockDao.On("GetBalance", "wallet-from").Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-from").Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", "wallet-to").Return(0.0, nil).Once()
+		mockDao.On("PostTransfers", transferWithFee("wallet-from", 50, 0.5)).Return(nil).Once()
+
+		fee, err := impl.Transfer(ctx, "wallet-from", "wallet-to", 50)
+		assert.NoError(t, err)
+		assert.Equal(t, 0.5, fee.Fee)
+		assert.Equal(t, 50.5, fee.Total)
+		assert.Equal(t, "transfer_percentage", fee.Components[0].Rule)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("balance must cover the fee", func(t *testing.T) {
+		impl, mockDao := setupFeeTest()
+		expectActiveWallets(mockDao, "wallet-from")
+		mockDao.On("GetBalance", "wallet-from").Return(50.2, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-from").Return(fullKycUser, nil).Once()
+
+		_, err := impl.Transfer(ctx, "wallet-from", "wallet-to", 50)
+		assert.ErrorIs(t, err, logic.ErrInsufficientBalance)
+		mockDao.AssertNotCalled(t, "PostTransfers", mock.Anything)
+	})
+
+	t.Run("failed posting fails the transfer", func(t *testing.T) {
+		impl, mockDao := setupFeeTest()
+		expectActiveWallets(mockDao, "wallet-from", "wallet-to")
+		mockDao.On("GetBalance", "wallet-from").Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-from").Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", "wallet-to").Return(0.0, nil).Once()
+		mockDao.On("PostTransfers", mock.Anything).Return(dao.ErrInsufficientFunds).Once()
+
+		fee, err := impl.Transfer(ctx, "wallet-from", "wallet-to", 50)
+		assert.ErrorIs(t, err, logic.ErrInsufficientBalance)
+		assert.Nil(t, fee)
+	})
+}
+
+func TestWithdrawWithFee(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("flat fee", func(t *testing.T) {
+		impl, mockDao := setupFeeTest()
+		expectActiveWallets(mockDao, "wallet-1")
+		mockDao.On("GetBalance", "wallet-1").Return(30.0, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil).Once()
+		mockDao.On("PostWithdrawal", withdrawalWithFee("wallet-1", 20, 2)).Return(nil).Once()
+
+		assert.NoError(t, impl.Withdraw(ctx, "wallet-1", 20))
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("balance must cover the fee", func(t *testing.T) {
+		impl, mockDao := setupFeeTest()
+		expectActiveWallets(mockDao, "wallet-1")
+		mockDao.On("GetBalance", "wallet-1").Return(21.0, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil).Once()
+
+		assert.ErrorIs(t, impl.Withdraw(ctx, "wallet-1", 20), logic.ErrInsufficientBalance)
+	})
+
+	t.Run("failed posting fails the withdrawal", func(t *testing.T) {
+		impl, mockDao := setupFeeTest()
+		expectActiveWallets(mockDao, "wallet-1")
+		mockDao.On("GetBalance", "wallet-1").Return(30.0, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil).Once()
+		mockDao.On("PostWithdrawal", mock.Anything).Return(errors.New("db down")).Once()
+
+		assert.Error(t, impl.Withdraw(ctx, "wallet-1", 20))
+		mockDao.AssertNotCalled(t, "AppendAuditLog", mock.Anything)
+	})
+}
+
+func TestQuoteFee(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("capped percentage", func(t *testing.T) {
+		impl, mockDao := setupFeeTest()
+		expectActiveWallets(mockDao, "wallet-1")
+		mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", "wallet-1").Return(2000.0, nil).Once()
+
+		quote, err := impl.QuoteFee(ctx, common.TransactionTypeTransfer, "wallet-1", 1000)
+		assert.NoError(t, err)
+		assert.Equal(t, 5.0, quote.Fee)
+		assert.True(t, quote.Components[0].Capped)
+		assert.Equal(t, common.KycLevelFull, quote.KycLevel)
+	})
+
+	t.Run("the fee wallet pays no fees", func(t *testing.T) {
+		impl, mockDao := setupFeeTest()
+		expectActiveWallets(mockDao, feeWallet)
+		mockDao.On("GetUserByWalletID", feeWallet).Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", feeWallet).Return(0.0, nil).Once()
+
+		quote, err := impl.QuoteFee(ctx, common.TransactionTypeWithdraw, feeWallet, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, 0.0, quote.Fee)
+		assert.Equal(t, 100.0, quote.Total)
+	})
+
+	t.Run("deposits have no fees", func(t *testing.T) {
+		impl, _ := setupFeeTest()
+		_, err := impl.QuoteFee(ctx, common.TransactionTypeDeposit, "wallet-1", 100)
+		assert.ErrorIs(t, err, logic.ErrInvalidFeeQuote)
+	})
+}
+
+func TestAllOrNothingBatchWithFees(t *testing.T) {
+	impl, mockDao := setupFeeTest()
+	batch := dao.TransferBatch{ID: "batch-1", FromWalletID: "wallet-1", Mode: common.BatchModeAllOrNothing, ItemCount: 2, Status: common.BatchStatusPending}
+	mockDao.On("ListStalledTransferBatches", mock.Anything, 20).Return([]dao.TransferBatch{}, nil).Once()
+	mockDao.On("ListPendingTransferBatches", 20).Return([]dao.TransferBatch{batch}, nil).Once()
+	mockDao.On("ClaimTransferBatch", "batch-1").Return(nil).Once()
+	mockDao.On("ListTransferBatchItems", "batch-1").Return(batchItems(), nil).Once()
+	mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil).Once()
+	expectActiveWallets(mockDao, "wallet-1", "wallet-2", "wallet-3")
+	mockDao.On("GetBalance", "wallet-1").Return(100.0, nil).Once()
+	mockDao.On("ExecuteTransferBatch", mock.Anything, []string{"item-1", "item-2"}, mock.MatchedBy(func(legs []dao.TransferLeg) bool {
+		return len(legs) == 4 &&
+			legs[2].Type == common.TransactionTypeFee && legs[2].Amount == 0.3 && *legs[2].ParentID == legs[0].DebitID &&
+			legs[3].Amount == 0.5 && *legs[3].ParentID == legs[1].DebitID && legs[3].ToWalletID == feeWallet
+	})).Return(nil).Once()
+
+	_, err := impl.ProcessTransferBatches(context.TODO())
+	assert.NoError(t, err)
+	mockDao.AssertExpectations(t)
+}
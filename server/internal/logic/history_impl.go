@@ -0,0 +1,129 @@
+package logic
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+)
+
+const (
+	DefaultHistoryPageSize = 20
+	MaxHistoryPageSize     = 100
+)
+
+var (
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrInvalidPageSize    = errors.New("limit must be between 1 and 100")
+	ErrInvalidTimeRange   = errors.New("start must not be after end")
+	ErrInvalidAmountRange = errors.New("amount bounds must be non-negative and min must not exceed max")
+	ErrInvalidTxType      = errors.New("invalid transaction type")
+)
+
+var transactionTypes = map[string]bool{
+	common.TransactionTypeDeposit:           true,
+	common.TransactionTypeWithdraw:          true,
+	common.TransactionTypeTransfer:          true,
+	common.TransactionTypeAdjustment:        true,
+	common.TransactionTypePayoutReturn:      true,
+	common.TransactionTypeWithdrawReversal:  true,
+	common.TransactionTypeFee:               true,
+	common.TransactionTypeInterest:          true,
+	common.TransactionTypeOverdraftInterest: true,
+	common.TransactionTypePocketMove:        true,
+	common.TransactionTypeEscrowHold:        true,
+	common.TransactionTypeEscrowRelease:     true,
+	common.TransactionTypeEscrowRefund:      true,
+}
+
+// TransactionPage is one page of history. NextCursor is nil on the last page.
+type TransactionPage struct {
+	WalletID     string            `json:"wallet_id"`
+	Transactions []dao.Transaction `json:"transactions"`
+	NextCursor   *string           `json:"next_cursor"`
+}
+
+// GetTransactionHistory returns a page of the wallet's history. The cursor is the next_cursor
+// of the previous page; an empty cursor starts from the beginning.
+func (l *WalletImpl) GetTransactionHistory(ctx context.Context, filter dao.TransactionFilter, cursor string) (*TransactionPage, error) {
+	if err := validateHistoryFilter(&filter); err != nil {
+		return nil, err
+	}
+	if cursor != "" {
+		after, err := DecodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+
+	// one extra row tells whether another page follows
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+	txs, err := l.dao.GetTransactionHistory(filter)
+	if err != nil {
+		l.logger.WithError(err).Errorf("Failed to fetch history of wallet %s", filter.WalletID)
+		return nil, err
+	}
+
+	page := &TransactionPage{WalletID: filter.WalletID, Transactions: txs}
+	if len(txs) > pageSize {
+		page.Transactions = txs[:pageSize]
+		last := page.Transactions[pageSize-1]
+		next := EncodeCursor(dao.TransactionCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+		page.NextCursor = &next
+	}
+	if page.Transactions == nil {
+		page.Transactions = []dao.Transaction{}
+	}
+	return page, nil
+}
+
+// validateHistoryFilter checks the filter and applies the default page size.
+func validateHistoryFilter(filter *dao.TransactionFilter) error {
+	if filter.Limit == 0 {
+		filter.Limit = DefaultHistoryPageSize
+	}
+	if filter.Limit < 0 || filter.Limit > MaxHistoryPageSize {
+		return ErrInvalidPageSize
+	}
+	return validateTransactionFilter(filter)
+}
+
+// validateTransactionFilter checks the non-paging parts of a filter.
+func validateTransactionFilter(filter *dao.TransactionFilter) error {
+	if filter.Type != "" && !transactionTypes[filter.Type] {
+		return ErrInvalidTxType
+	}
+	if filter.Start != nil && filter.End != nil && filter.Start.After(*filter.End) {
+		return ErrInvalidTimeRange
+	}
+	if (filter.MinAmount != nil && *filter.MinAmount < 0) || (filter.MaxAmount != nil && *filter.MaxAmount < 0) {
+		return ErrInvalidAmountRange
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return ErrInvalidAmountRange
+	}
+	return nil
+}
+
+// EncodeCursor turns a keyset position into an opaque token.
+func EncodeCursor(cursor dao.TransactionCursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(token string) (*dao.TransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor dao.TransactionCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == "" || cursor.CreatedAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
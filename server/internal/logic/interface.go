@@ -2,15 +2,101 @@ package logic
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"time"
 
 	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/fees"
+	"github.com/julkhong/walletapp/server/internal/payout"
+	"github.com/julkhong/walletapp/server/internal/statement"
 )
 
 //go:generate mockery --name=WalletImplInterface --output=./mocks --outpkg=mocks
 type WalletImplInterface interface {
 	Deposit(ctx context.Context, walletID string, amount float64) error
 	Withdraw(ctx context.Context, walletID string, amount float64) error
-	Transfer(ctx context.Context, fromWalletID, toWalletID string, amount float64) error
+	WithdrawToBank(ctx context.Context, walletID string, amount float64, beneficiary payout.Account) (*dao.Payout, error)
+	Transfer(ctx context.Context, fromWalletID, toWalletID string, amount float64) (*fees.Quote, error)
+	SplitPayment(ctx context.Context, req SplitPaymentRequest) (*SplitPaymentResult, error)
 	GetBalance(ctx context.Context, walletID string) (float64, error)
-	GetTransactionHistory(ctx context.Context, walletID, txType, start, end string, limit, offset int) ([]dao.Transaction, error)
+	GetBalanceAsOf(ctx context.Context, walletID string, asOf time.Time) (float64, error)
+	SnapshotBalances(ctx context.Context) (int64, error)
+	GetTransactionHistory(ctx context.Context, filter dao.TransactionFilter, cursor string) (*TransactionPage, error)
+	ExportTransactions(ctx context.Context, filter dao.TransactionFilter, format string, out io.Writer) error
+	GenerateMonthlyStatements(ctx context.Context) (int, error)
+	ListStatements(ctx context.Context, walletID string) ([]dao.Statement, error)
+	GetStatement(ctx context.Context, statementID string) (*statement.Statement, error)
+	ImportBankStatement(ctx context.Context, format string, data []byte, importedBy string) (*BankImportResult, error)
+	GetBankImport(ctx context.Context, importID string) (*BankImportResult, error)
+	ListPayouts(ctx context.Context, status string) ([]dao.Payout, error)
+	GetPayout(ctx context.Context, payoutID string) (*dao.Payout, error)
+	UpdatePayoutStatus(ctx context.Context, payoutID, status, reason, operator string) (*dao.Payout, error)
+	GeneratePayoutBatch(ctx context.Context) (*dao.PayoutBatch, error)
+	WithdrawViaProvider(ctx context.Context, walletID string, amount float64, target ProviderTarget) (*dao.ProviderOperation, error)
+	GetProviderOperation(ctx context.Context, opID string) (*dao.ProviderOperation, error)
+	HandleProviderCallback(ctx context.Context, name string, header http.Header, body []byte) (int, error)
+	SyncProviderOperations(ctx context.Context) (int, error)
+	CreateScheduledTransfer(ctx context.Context, req ScheduleRequest) (*dao.Schedule, error)
+	ListScheduledTransfers(ctx context.Context, walletID string) ([]dao.Schedule, error)
+	GetScheduledTransfer(ctx context.Context, scheduleID string) (*ScheduleDetail, error)
+	PauseScheduledTransfer(ctx context.Context, scheduleID, actor string) (*dao.Schedule, error)
+	ResumeScheduledTransfer(ctx context.Context, scheduleID, actor string) (*dao.Schedule, error)
+	CancelScheduledTransfer(ctx context.Context, scheduleID, actor string) (*dao.Schedule, error)
+	RunDueSchedules(ctx context.Context) (int, error)
+	CreateTransferBatch(ctx context.Context, req TransferBatchRequest) (*TransferBatchResult, error)
+	GetTransferBatch(ctx context.Context, batchID string) (*TransferBatchResult, error)
+	ProcessTransferBatches(ctx context.Context) (int, error)
+	QuoteFee(ctx context.Context, txType, walletID string, amount float64) (*fees.Quote, error)
+	CreateWallet(ctx context.Context, userID, walletType string, interestRate *float64) (*dao.Wallet, error)
+	AccrueInterest(ctx context.Context) (int, error)
+	GetInterestReport(ctx context.Context, walletID string, from, to time.Time) (*InterestReport, error)
+	SetCreditLimit(ctx context.Context, walletID string, limit float64, actorID string) (*dao.Wallet, error)
+	GetCreditLimit(ctx context.Context, walletID string) (float64, error)
+	CreatePocket(ctx context.Context, parentWalletID, name string, goalAmount *float64, goalDate *time.Time) (*dao.Wallet, error)
+	SetPocketGoal(ctx context.Context, pocketID string, goalAmount *float64, goalDate *time.Time) (*Pocket, error)
+	MovePocketFunds(ctx context.Context, walletID, fromWalletID, toWalletID string, amount float64) (*WalletView, error)
+	GetWalletView(ctx context.Context, walletID string) (*WalletView, error)
+	AuthorizeWallet(ctx context.Context, walletID, userID string, access WalletAccess, amount float64) error
+	InviteMember(ctx context.Context, walletID string, invite MemberInvite) (*dao.WalletMember, error)
+	AcceptInvitation(ctx context.Context, walletID, userID string) (*dao.WalletMember, error)
+	ListMembers(ctx context.Context, walletID string) ([]MemberView, error)
+	RemoveMember(ctx context.Context, walletID, userID, actorID string) error
+	CreateEscrow(ctx context.Context, req EscrowRequest) (*dao.Escrow, error)
+	GetEscrow(ctx context.Context, escrowID string) (*dao.Escrow, error)
+	ReleaseEscrow(ctx context.Context, escrowID, actor string) (*dao.Escrow, error)
+	RefundEscrow(ctx context.Context, escrowID, actor string) (*dao.Escrow, error)
+	DisputeEscrow(ctx context.Context, escrowID, reason, actor string) (*dao.Escrow, error)
+	ResolveEscrow(ctx context.Context, escrowID, outcome, operator string) (*dao.Escrow, error)
+	ExpireEscrows(ctx context.Context) (int, error)
+	CreatePaymentRequest(ctx context.Context, in PaymentRequestInput) (*dao.PaymentRequest, error)
+	GetPaymentRequest(ctx context.Context, requestID string) (*dao.PaymentRequest, error)
+	ListPaymentRequests(ctx context.Context, walletID string, incoming bool, status string) ([]dao.PaymentRequest, error)
+	AcceptPaymentRequest(ctx context.Context, requestID, actor string) (*dao.PaymentRequest, error)
+	DeclinePaymentRequest(ctx context.Context, requestID, actor string) (*dao.PaymentRequest, error)
+	CancelPaymentRequest(ctx context.Context, requestID, actor string) (*dao.PaymentRequest, error)
+	ExpirePaymentRequests(ctx context.Context) (int, error)
+	CreatePaymentLink(ctx context.Context, in PaymentLinkInput) (*PaymentLinkView, error)
+	ResolvePaymentLink(ctx context.Context, token string) (*PaymentLinkView, error)
+	PaymentLinkQR(ctx context.Context, token string, scale int) ([]byte, error)
+	PayPaymentLink(ctx context.Context, token, fromWalletID string, amount float64) (*PaymentLinkPayment, error)
+	UpgradeKycLevel(ctx context.Context, userID, level string, evidenceRefs []string, approvedBy string) (*dao.User, error)
+	CreateUser(ctx context.Context, name, email string) (*dao.User, *dao.Wallet, *dao.UserScreening, error)
+	ScreenTransferParties(ctx context.Context, fromWalletID, toWalletID string) error
+	RequiresApproval(amount float64) bool
+	SubmitForApproval(ctx context.Context, req ApprovalRequest) (*dao.PendingOperation, error)
+	GetPendingOperation(ctx context.Context, operationID string) (*dao.PendingOperation, error)
+	ListPendingOperations(ctx context.Context, status string) ([]dao.PendingOperation, error)
+	ApprovePendingOperation(ctx context.Context, operationID, approver string) (*dao.PendingOperation, error)
+	RejectPendingOperation(ctx context.Context, operationID, approver string) (*dao.PendingOperation, error)
+	ExpirePendingOperations(ctx context.Context) ([]dao.PendingOperation, error)
+	ListTransactionReviews(ctx context.Context, status string) ([]dao.TransactionReview, error)
+	ApproveTransactionReview(ctx context.Context, reviewID, reviewer string) (*dao.TransactionReview, error)
+	RejectTransactionReview(ctx context.Context, reviewID, reviewer string) (*dao.TransactionReview, error)
+	SubmitAdjustment(ctx context.Context, payload AdjustmentPayload, idempotencyKey, method, path string) (*dao.PendingOperation, error)
+	ListAdjustments(ctx context.Context, walletID string) ([]dao.Adjustment, error)
+	RecordConfigChange(ctx context.Context, key string, value any)
+	ListAuditLog(ctx context.Context, afterSeq int64, limit int) ([]dao.AuditLog, error)
+	VerifyAuditLog(ctx context.Context) (*AuditVerification, error)
+	CheckLedgerIntegrity(ctx context.Context, apply bool, operator string) (*LedgerReport, error)
 }
@@ -0,0 +1,126 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+)
+
+var (
+	ErrUserNotFound             = errors.New("user not found")
+	ErrInvalidKycLevel          = errors.New("invalid kyc level")
+	ErrKycDowngradeNotAllowed   = errors.New("kyc level can only be upgraded")
+	ErrKycEvidenceRequired      = errors.New("kyc upgrade requires evidence references")
+	ErrKycBalanceLimitExceeded  = errors.New("deposit exceeds max wallet balance for kyc tier")
+	ErrKycTransferLimitExceeded = errors.New("transfer exceeds max amount for kyc tier")
+	ErrKycWithdrawNotAllowed    = errors.New("withdrawals are not allowed for kyc tier")
+)
+
+// KycTierRule holds the limits enforced for a KYC level. A zero limit means unlimited.
+type KycTierRule struct {
+	MaxBalance        float64
+	MaxTransferAmount float64
+	WithdrawAllowed   bool
+}
+
+var KycTierRules = map[string]KycTierRule{
+	common.KycLevelUnverified: {MaxBalance: 1000, MaxTransferAmount: 250, WithdrawAllowed: false},
+	common.KycLevelBasic:      {MaxBalance: 50000, MaxTransferAmount: 5000, WithdrawAllowed: true},
+	common.KycLevelFull:       {MaxBalance: 0, MaxTransferAmount: 0, WithdrawAllowed: true},
+}
+
+var kycLevelRank = map[string]int{
+	common.KycLevelUnverified: 0,
+	common.KycLevelBasic:      1,
+	common.KycLevelFull:       2,
+}
+
+func (l *WalletImpl) UpgradeKycLevel(ctx context.Context, userID, level string, evidenceRefs []string, approvedBy string) (*dao.User, error) {
+	l.logger.Infof("Upgrading KYC level of user %s to %s", userID, level)
+
+	newRank, ok := kycLevelRank[level]
+	if !ok {
+		return nil, ErrInvalidKycLevel
+	}
+	if len(evidenceRefs) == 0 {
+		return nil, ErrKycEvidenceRequired
+	}
+
+	user, err := l.dao.GetUserByID(userID)
+	if err != nil {
+		l.logger.WithError(err).Errorf("Failed to fetch user %s", userID)
+		if errors.Is(err, dao.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("kyc upgrade failed: %w", err)
+	}
+
+	if newRank <= kycLevelRank[user.KycLevel] {
+		l.logger.Warnf("Rejected KYC change for user %s: %s -> %s", userID, user.KycLevel, level)
+		return nil, ErrKycDowngradeNotAllowed
+	}
+
+	refs, err := json.Marshal(evidenceRefs)
+	if err != nil {
+		return nil, fmt.Errorf("kyc upgrade failed: %w", err)
+	}
+
+	err = l.dao.UpgradeKycLevel(&dao.KycUpgrade{
+		ID:           uuid.NewString(),
+		UserID:       userID,
+		FromLevel:    user.KycLevel,
+		ToLevel:      level,
+		EvidenceRefs: string(refs),
+		ApprovedBy:   approvedBy,
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		l.logger.WithError(err).Errorf("Failed to upgrade KYC level")
+		if errors.Is(err, dao.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("kyc upgrade failed: %w", err)
+	}
+
+	l.recordAudit(ctx, common.AuditActionKycUpgraded, common.AuditEntityUser, userID, map[string]any{
+		"from_level":    user.KycLevel,
+		"to_level":      level,
+		"evidence_refs": evidenceRefs,
+		"approved_by":   approvedBy,
+	})
+
+	user.KycLevel = level
+	return user, nil
+}
+
+// kycRuleForWallet resolves the tier rule of the wallet owner.
+func (l *WalletImpl) kycRuleForWallet(walletID string) (KycTierRule, error) {
+	_, rule, err := l.kycForWallet(walletID)
+	return rule, err
+}
+
+// kycForWallet resolves the KYC level of the wallet owner and the rule of that tier.
+func (l *WalletImpl) kycForWallet(walletID string) (string, KycTierRule, error) {
+	user, err := l.dao.GetUserByWalletID(walletID)
+	if err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return "", KycTierRule{}, ErrWalletNotFound
+		}
+		return "", KycTierRule{}, err
+	}
+
+	rule, ok := KycTierRules[user.KycLevel]
+	if !ok {
+		// unknown levels get the most restrictive tier
+		l.logger.Warnf("Unknown KYC level %q for user %s, applying unverified rules", user.KycLevel, user.ID)
+		return user.KycLevel, KycTierRules[common.KycLevelUnverified], nil
+	}
+	return user.KycLevel, rule, nil
+}
@@ -0,0 +1,60 @@
+package logic_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpgradeKycLevel(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	ctx := context.TODO()
+	userID := "user-1"
+	evidence := []string{"passport-123"}
+
+	t.Run("successful upgrade", func(t *testing.T) {
+		mockDao.On("GetUserByID", userID).
+			Return(&dao.User{ID: userID, KycLevel: common.KycLevelUnverified}, nil).Once()
+		mockDao.On("UpgradeKycLevel", mock.MatchedBy(func(u *dao.KycUpgrade) bool {
+			return u.UserID == userID && u.FromLevel == common.KycLevelUnverified &&
+				u.ToLevel == common.KycLevelBasic && u.EvidenceRefs == `["passport-123"]` && u.ApprovedBy == "operator-1"
+		})).Return(nil).Once()
+
+		user, err := impl.UpgradeKycLevel(ctx, userID, common.KycLevelBasic, evidence, "operator-1")
+		assert.NoError(t, err)
+		assert.Equal(t, common.KycLevelBasic, user.KycLevel)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		_, err := impl.UpgradeKycLevel(ctx, userID, "gold", evidence, "operator-1")
+		assert.ErrorIs(t, err, logic.ErrInvalidKycLevel)
+	})
+
+	t.Run("missing evidence", func(t *testing.T) {
+		_, err := impl.UpgradeKycLevel(ctx, userID, common.KycLevelFull, nil, "operator-1")
+		assert.ErrorIs(t, err, logic.ErrKycEvidenceRequired)
+	})
+
+	t.Run("downgrade rejected", func(t *testing.T) {
+		mockDao.On("GetUserByID", userID).
+			Return(&dao.User{ID: userID, KycLevel: common.KycLevelFull}, nil).Once()
+
+		_, err := impl.UpgradeKycLevel(ctx, userID, common.KycLevelBasic, evidence, "operator-1")
+		assert.ErrorIs(t, err, logic.ErrKycDowngradeNotAllowed)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mockDao.On("GetUserByID", userID).Return(nil, dao.ErrUserNotFound).Once()
+
+		_, err := impl.UpgradeKycLevel(ctx, userID, common.KycLevelBasic, evidence, "operator-1")
+		assert.ErrorIs(t, err, logic.ErrUserNotFound)
+		mockDao.AssertExpectations(t)
+	})
+}
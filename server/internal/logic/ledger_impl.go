@@ -0,0 +1,118 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+)
+
+// LedgerDiscrepancy is a wallet whose stored balance disagrees with its transaction history.
+// Difference is what the ledger is missing: balance minus ledger balance.
+type LedgerDiscrepancy struct {
+	WalletID         string  `json:"wallet_id"`
+	Balance          float64 `json:"balance"`
+	LedgerBalance    float64 `json:"ledger_balance"`
+	Difference       float64 `json:"difference"`
+	TransactionCount int64   `json:"transaction_count"`
+	// CorrectionID is the correcting transaction written in apply mode.
+	CorrectionID *string `json:"correction_id,omitempty"`
+	// CorrectionError explains why a discrepancy was left uncorrected in apply mode.
+	CorrectionError *string `json:"correction_error,omitempty"`
+}
+
+type LedgerReport struct {
+	CheckedAt     time.Time           `json:"checked_at"`
+	Applied       bool                `json:"applied"`
+	Discrepancies []LedgerDiscrepancy `json:"discrepancies"`
+}
+
+// CheckLedgerIntegrity recomputes every wallet's balance from its transactions and reports the
+// wallets that disagree. With apply set, each discrepancy gets an error_correction adjustment entry
+// that brings the ledger in line with the stored balance; the balance itself is not changed,
+// since a missing transaction row is the known way for the two to drift apart.
+func (l *WalletImpl) CheckLedgerIntegrity(ctx context.Context, apply bool, operator string) (*LedgerReport, error) {
+	if apply && operator == "" {
+		return nil, ErrApproverRequired
+	}
+
+	balances, err := l.dao.ListLedgerDiscrepancies()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LedgerReport{
+		CheckedAt:     time.Now(),
+		Applied:       apply,
+		Discrepancies: make([]LedgerDiscrepancy, 0, len(balances)),
+	}
+	for _, b := range balances {
+		d := LedgerDiscrepancy{
+			WalletID:         b.WalletID,
+			Balance:          common.RoundToNDecimals(b.Balance, 4),
+			LedgerBalance:    common.RoundToNDecimals(b.LedgerBalance, 4),
+			Difference:       common.RoundToNDecimals(b.Balance-b.LedgerBalance, 4),
+			TransactionCount: b.TransactionCount,
+		}
+		if d.Difference == 0 {
+			// differences below the stored precision
+			continue
+		}
+		l.logger.Warnf("Ledger discrepancy on wallet %s: balance=%.4f, ledger=%.4f", d.WalletID, d.Balance, d.LedgerBalance)
+
+		if apply {
+			l.correctLedger(ctx, &d, operator)
+		}
+		report.Discrepancies = append(report.Discrepancies, d)
+	}
+
+	return report, nil
+}
+
+func (l *WalletImpl) correctLedger(ctx context.Context, d *LedgerDiscrepancy, operator string) {
+	now := time.Now()
+	txID := uuid.NewString()
+	var related *string
+
+	err := l.dao.RecordLedgerCorrection(&dao.Transaction{
+		ID:            txID,
+		WalletID:      d.WalletID,
+		Type:          common.TransactionTypeAdjustment,
+		Amount:        d.Difference,
+		RelatedUserID: related,
+		BalanceAfter:  &d.Balance,
+		CreatedAt:     now,
+	}, &dao.Adjustment{
+		ID:            uuid.NewString(),
+		TransactionID: txID,
+		WalletID:      d.WalletID,
+		Amount:        d.Difference,
+		ReasonCode:    common.AdjustmentReasonErrorCorrection,
+		Note:          "ledger reconciliation: transactions did not sum to the stored balance",
+		RequestedBy:   common.LedgerReconciliationActor,
+		ApprovedBy:    operator,
+		CreatedAt:     now,
+	})
+	if err != nil {
+		msg := err.Error()
+		if errors.Is(err, dao.ErrLedgerChanged) {
+			msg = "wallet changed during reconciliation, re-run to correct"
+		}
+		l.logger.WithError(err).Errorf("Failed to correct ledger of wallet %s", d.WalletID)
+		d.CorrectionError = &msg
+		return
+	}
+
+	d.CorrectionID = &txID
+	l.recordAudit(ctx, common.AuditActionLedgerCorrected, common.AuditEntityWallet, d.WalletID, map[string]any{
+		"transaction_id": txID,
+		"balance":        d.Balance,
+		"ledger_balance": d.LedgerBalance,
+		"difference":     d.Difference,
+		"approved_by":    operator,
+	})
+}
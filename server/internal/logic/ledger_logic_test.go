@@ -0,0 +1,69 @@
+package logic_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckLedgerIntegrity(t *testing.T) {
+	ctx := context.TODO()
+	discrepancies := []dao.LedgerBalance{
+		{WalletID: "wallet-1", Balance: 100, LedgerBalance: 75.5, TransactionCount: 3},
+		{WalletID: "wallet-2", Balance: 10, LedgerBalance: 10.00001, TransactionCount: 1},
+	}
+
+	t.Run("dry run only reports", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("ListLedgerDiscrepancies").Return(discrepancies, nil).Once()
+
+		report, err := impl.CheckLedgerIntegrity(ctx, false, "")
+		assert.NoError(t, err)
+		assert.False(t, report.Applied)
+		assert.Len(t, report.Discrepancies, 1, "sub-precision differences are ignored")
+		assert.Equal(t, 24.5, report.Discrepancies[0].Difference)
+		assert.Nil(t, report.Discrepancies[0].CorrectionID)
+		mockDao.AssertNotCalled(t, "RecordLedgerCorrection", mock.Anything, mock.Anything)
+	})
+
+	t.Run("apply writes correcting adjustments", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("ListLedgerDiscrepancies").Return(discrepancies, nil).Once()
+		mockDao.On("RecordLedgerCorrection",
+			mock.MatchedBy(func(tx *dao.Transaction) bool {
+				return tx.WalletID == "wallet-1" && tx.Type == common.TransactionTypeAdjustment && tx.Amount == 24.5
+			}),
+			mock.MatchedBy(func(a *dao.Adjustment) bool {
+				return a.ReasonCode == common.AdjustmentReasonErrorCorrection && a.ApprovedBy == "operator-1" &&
+					a.RequestedBy == common.LedgerReconciliationActor
+			}),
+		).Return(nil).Once()
+
+		report, err := impl.CheckLedgerIntegrity(ctx, true, "operator-1")
+		assert.NoError(t, err)
+		assert.NotNil(t, report.Discrepancies[0].CorrectionID)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("changed wallet is left for the next run", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("ListLedgerDiscrepancies").Return(discrepancies[:1], nil).Once()
+		mockDao.On("RecordLedgerCorrection", mock.Anything, mock.Anything).Return(dao.ErrLedgerChanged).Once()
+
+		report, err := impl.CheckLedgerIntegrity(ctx, true, "operator-1")
+		assert.NoError(t, err)
+		assert.Nil(t, report.Discrepancies[0].CorrectionID)
+		assert.NotNil(t, report.Discrepancies[0].CorrectionError)
+	})
+
+	t.Run("apply requires an operator", func(t *testing.T) {
+		impl, _ := setupLogicTest()
+		_, err := impl.CheckLedgerIntegrity(ctx, true, "")
+		assert.Equal(t, logic.ErrApproverRequired, err)
+	})
+}
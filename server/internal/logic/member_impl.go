@@ -0,0 +1,312 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/audit"
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/statement"
+)
+
+var (
+	ErrWalletAccessDenied  = errors.New("caller is not a member of the wallet with the required role")
+	ErrSpendingCapExceeded = errors.New("amount exceeds the member's remaining monthly spending cap")
+	ErrInvalidMemberRole   = errors.New("role must be owner, spender or viewer")
+	ErrInvalidSpendingCap  = errors.New("spending cap must not be negative and is for spenders only")
+	ErrAlreadyMember       = errors.New("user is already a member of the wallet")
+	ErrInvitationNotFound  = errors.New("no open invitation to the wallet")
+	ErrMemberNotFound      = errors.New("user is not a member of the wallet")
+	ErrLastOwner           = errors.New("the last owner cannot leave the wallet")
+	ErrPocketMembers       = errors.New("pockets share the members of their parent wallet")
+)
+
+// WalletAccess is what a caller wants to do with a wallet.
+type WalletAccess int
+
+const (
+	// AccessView reads the wallet: balances, history, statements and members. Every role has it.
+	AccessView WalletAccess = iota
+	// AccessSpend moves money in or out of the wallet. Owners and spenders have it.
+	AccessSpend
+	// AccessManage changes the wallet itself: its pockets and members. Only owners have it.
+	AccessManage
+)
+
+// MemberInvite invites a user to a wallet. SpendingCap is for spenders only.
+type MemberInvite struct {
+	UserID      string
+	Role        string
+	SpendingCap *float64
+	InvitedBy   string
+}
+
+// MemberView is a member with what they spent from the wallet this month, and for a spender
+// with a cap, what is left of it.
+type MemberView struct {
+	dao.WalletMember
+	SpentThisMonth float64  `json:"spent_this_month"`
+	RemainingCap   *float64 `json:"remaining_cap,omitempty"`
+}
+
+// AuthorizeWallet checks that a user is an active member of a wallet whose role grants access.
+// For spending, amount must also fit in what is left of a spender's monthly cap. Pockets are
+// shared with the members of their parent wallet.
+func (l *WalletImpl) AuthorizeWallet(ctx context.Context, walletID, userID string, access WalletAccess, amount float64) error {
+	walletID, err := l.membershipWallet(walletID)
+	if err != nil {
+		return err
+	}
+
+	member, err := l.dao.GetWalletMember(walletID, userID)
+	if err != nil {
+		if errors.Is(err, dao.ErrMemberNotFound) {
+			return ErrWalletAccessDenied
+		}
+		return fmt.Errorf("authorize failed: %w", err)
+	}
+	if member.Status != common.MemberStatusActive {
+		return ErrWalletAccessDenied
+	}
+
+	switch member.Role {
+	case common.MemberRoleOwner:
+		return nil
+	case common.MemberRoleSpender:
+		if access == AccessManage {
+			return ErrWalletAccessDenied
+		}
+		if access != AccessSpend || member.SpendingCap == nil || amount <= 0 {
+			return nil
+		}
+		spent, err := l.memberSpending(walletID, userID, time.Now())
+		if err != nil {
+			return fmt.Errorf("authorize failed: %w", err)
+		}
+		if spent+common.RoundToNDecimals(amount, 4) > *member.SpendingCap {
+			l.logger.Warnf("Spending cap of user %s on wallet %s exceeded: spent=%.4f, requested=%.4f, cap=%.4f",
+				userID, walletID, spent, amount, *member.SpendingCap)
+			return ErrSpendingCapExceeded
+		}
+		return nil
+	default:
+		if access != AccessView {
+			return ErrWalletAccessDenied
+		}
+		return nil
+	}
+}
+
+// InviteMember invites a user to share a wallet. The invitation grants nothing until the user
+// accepts it; inviting them again before that replaces the role and cap.
+func (l *WalletImpl) InviteMember(ctx context.Context, walletID string, invite MemberInvite) (*dao.WalletMember, error) {
+	switch invite.Role {
+	case common.MemberRoleOwner, common.MemberRoleSpender, common.MemberRoleViewer:
+	default:
+		return nil, ErrInvalidMemberRole
+	}
+	if invite.SpendingCap != nil {
+		if invite.Role != common.MemberRoleSpender || *invite.SpendingCap < 0 {
+			return nil, ErrInvalidSpendingCap
+		}
+		spendingCap := common.RoundToNDecimals(*invite.SpendingCap, 4)
+		invite.SpendingCap = &spendingCap
+	}
+
+	wallet, err := l.dao.GetWalletByID(walletID)
+	if err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, fmt.Errorf("invite failed: %w", err)
+	}
+	if wallet.Type == common.WalletTypePocket {
+		return nil, ErrPocketMembers
+	}
+	if _, err := l.dao.GetUserByID(invite.UserID); err != nil {
+		if errors.Is(err, dao.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("invite failed: %w", err)
+	}
+
+	existing, err := l.dao.GetWalletMember(walletID, invite.UserID)
+	if err != nil && !errors.Is(err, dao.ErrMemberNotFound) {
+		return nil, fmt.Errorf("invite failed: %w", err)
+	}
+	if existing != nil && existing.Status == common.MemberStatusActive {
+		return nil, ErrAlreadyMember
+	}
+
+	member := &dao.WalletMember{
+		WalletID:    walletID,
+		UserID:      invite.UserID,
+		Role:        invite.Role,
+		SpendingCap: invite.SpendingCap,
+		Status:      common.MemberStatusInvited,
+		InvitedBy:   invite.InvitedBy,
+		CreatedAt:   time.Now(),
+	}
+	if err := l.dao.SaveInvitation(member); err != nil {
+		return nil, fmt.Errorf("invite failed: %w", err)
+	}
+
+	l.recordAudit(ctx, common.AuditActionMemberInvited, common.AuditEntityWallet, walletID, map[string]any{
+		"user_id":      member.UserID,
+		"role":         member.Role,
+		"spending_cap": member.SpendingCap,
+	})
+	return member, nil
+}
+
+// AcceptInvitation makes the user an active member of the wallet they were invited to.
+func (l *WalletImpl) AcceptInvitation(ctx context.Context, walletID, userID string) (*dao.WalletMember, error) {
+	if err := l.dao.AcceptInvitation(walletID, userID, time.Now()); err != nil {
+		if errors.Is(err, dao.ErrMemberNotFound) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, fmt.Errorf("accept invitation failed: %w", err)
+	}
+
+	member, err := l.dao.GetWalletMember(walletID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("accept invitation failed: %w", err)
+	}
+	l.recordAudit(ctx, common.AuditActionMemberJoined, common.AuditEntityWallet, walletID, map[string]any{
+		"user_id": userID,
+		"role":    member.Role,
+	})
+	return member, nil
+}
+
+// ListMembers returns the members and open invitations of a wallet, or of a pocket's parent.
+func (l *WalletImpl) ListMembers(ctx context.Context, walletID string) ([]MemberView, error) {
+	walletID, err := l.membershipWallet(walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := l.dao.ListWalletMembers(walletID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	views := make([]MemberView, len(members))
+	for i, member := range members {
+		views[i] = MemberView{WalletMember: member}
+		if member.Status != common.MemberStatusActive || member.Role == common.MemberRoleViewer {
+			continue
+		}
+		spent, err := l.memberSpending(walletID, member.UserID, now)
+		if err != nil {
+			return nil, err
+		}
+		views[i].SpentThisMonth = spent
+		if member.SpendingCap != nil {
+			remaining := common.RoundToNDecimals(max(*member.SpendingCap-spent, 0), 4)
+			views[i].RemainingCap = &remaining
+		}
+	}
+	return views, nil
+}
+
+// RemoveMember takes a user off a wallet, or withdraws their invitation. A wallet always keeps
+// at least one active owner.
+func (l *WalletImpl) RemoveMember(ctx context.Context, walletID, userID, actorID string) error {
+	member, err := l.dao.GetWalletMember(walletID, userID)
+	if err != nil {
+		if errors.Is(err, dao.ErrMemberNotFound) {
+			return ErrMemberNotFound
+		}
+		return fmt.Errorf("remove member failed: %w", err)
+	}
+
+	if member.Role == common.MemberRoleOwner && member.Status == common.MemberStatusActive {
+		members, err := l.dao.ListWalletMembers(walletID)
+		if err != nil {
+			return fmt.Errorf("remove member failed: %w", err)
+		}
+		owners := 0
+		for _, other := range members {
+			if other.Role == common.MemberRoleOwner && other.Status == common.MemberStatusActive {
+				owners++
+			}
+		}
+		if owners <= 1 {
+			return ErrLastOwner
+		}
+	}
+
+	if err := l.dao.RemoveWalletMember(walletID, userID); err != nil {
+		if errors.Is(err, dao.ErrMemberNotFound) {
+			return ErrMemberNotFound
+		}
+		return fmt.Errorf("remove member failed: %w", err)
+	}
+	l.recordAudit(ctx, common.AuditActionMemberRemoved, common.AuditEntityWallet, walletID, map[string]any{
+		"user_id":    userID,
+		"role":       member.Role,
+		"status":     member.Status,
+		"removed_by": actorID,
+	})
+	return nil
+}
+
+// membershipWallet returns the wallet whose members decide access to walletID: the wallet
+// itself, or the parent of a pocket.
+func (l *WalletImpl) membershipWallet(walletID string) (string, error) {
+	wallet, err := l.dao.GetWalletByID(walletID)
+	if err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return "", ErrWalletNotFound
+		}
+		return "", err
+	}
+	if wallet.ParentWalletID != nil {
+		return *wallet.ParentWalletID, nil
+	}
+	return wallet.ID, nil
+}
+
+// memberSpending returns what a member moved out of a wallet in the calendar month of now.
+func (l *WalletImpl) memberSpending(walletID, userID string, now time.Time) (float64, error) {
+	start, _ := statement.MonthPeriod(now)
+	spent, err := l.dao.SumMemberSpending(walletID, userID, start)
+	if err != nil {
+		return 0, err
+	}
+	return common.RoundToNDecimals(spent, 4), nil
+}
+
+// authorizeCreator checks again, when deferred work pays out, that whoever set it up may still
+// spend amount from the wallet: they may have been removed or used up their cap since.
+// Work created without an actor is not attributed to anybody and is not checked.
+func (l *WalletImpl) authorizeCreator(ctx context.Context, walletID, createdBy string, amount float64) error {
+	if createdBy == "" {
+		return nil
+	}
+	return l.AuthorizeWallet(ctx, walletID, createdBy, AccessSpend, amount)
+}
+
+type initiatorKey struct{}
+
+// withInitiator attributes the money movements made with ctx to userID instead of the request's
+// actor, for work that runs on someone else's behalf. An empty userID attributes them to nobody.
+func withInitiator(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, initiatorKey{}, userID)
+}
+
+// initiator returns who the money movements made with ctx are attributed to, or nil for none.
+func initiator(ctx context.Context) *string {
+	userID, ok := ctx.Value(initiatorKey{}).(string)
+	if !ok {
+		userID = audit.FromContext(ctx).Actor
+	}
+	if userID == "" {
+		return nil
+	}
+	return &userID
+}
@@ -0,0 +1,222 @@
+package logic_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/audit"
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func sharedWallet() *dao.Wallet {
+	return &dao.Wallet{ID: "wallet-1", UserID: "user-1", Status: common.WalletStatusActive, Type: common.WalletTypeStandard}
+}
+
+func member(userID, role, status string, spendingCap *float64) *dao.WalletMember {
+	return &dao.WalletMember{WalletID: "wallet-1", UserID: userID, Role: role, Status: status, SpendingCap: spendingCap}
+}
+
+func TestAuthorizeWallet(t *testing.T) {
+	ctx := context.TODO()
+	spendingCap := 100.0
+
+	t.Run("roles", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetWalletByID", "wallet-1").Return(sharedWallet(), nil)
+		mockDao.On("GetWalletMember", "wallet-1", "owner").Return(member("owner", common.MemberRoleOwner, common.MemberStatusActive, nil), nil)
+		mockDao.On("GetWalletMember", "wallet-1", "spender").Return(member("spender", common.MemberRoleSpender, common.MemberStatusActive, nil), nil)
+		mockDao.On("GetWalletMember", "wallet-1", "viewer").Return(member("viewer", common.MemberRoleViewer, common.MemberStatusActive, nil), nil)
+		mockDao.On("GetWalletMember", "wallet-1", "invitee").Return(member("invitee", common.MemberRoleOwner, common.MemberStatusInvited, nil), nil)
+		mockDao.On("GetWalletMember", "wallet-1", "stranger").Return(nil, dao.ErrMemberNotFound)
+
+		tests := []struct {
+			userID string
+			access logic.WalletAccess
+			err    error
+		}{
+			{"owner", logic.AccessManage, nil},
+			{"spender", logic.AccessSpend, nil},
+			{"spender", logic.AccessManage, logic.ErrWalletAccessDenied},
+			{"viewer", logic.AccessView, nil},
+			{"viewer", logic.AccessSpend, logic.ErrWalletAccessDenied},
+			{"invitee", logic.AccessView, logic.ErrWalletAccessDenied},
+			{"stranger", logic.AccessView, logic.ErrWalletAccessDenied},
+		}
+		for _, tt := range tests {
+			err := impl.AuthorizeWallet(ctx, "wallet-1", tt.userID, tt.access, 10)
+			assert.Equal(t, tt.err, err, "%s with access %d", tt.userID, tt.access)
+		}
+		mockDao.AssertNotCalled(t, "SumMemberSpending", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("spending cap", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetWalletByID", "wallet-1").Return(sharedWallet(), nil)
+		mockDao.On("GetWalletMember", "wallet-1", "spender").Return(member("spender", common.MemberRoleSpender, common.MemberStatusActive, &spendingCap), nil)
+		mockDao.On("SumMemberSpending", "wallet-1", "spender", mock.Anything).Return(80.0, nil)
+
+		assert.NoError(t, impl.AuthorizeWallet(ctx, "wallet-1", "spender", logic.AccessSpend, 20))
+		assert.ErrorIs(t, impl.AuthorizeWallet(ctx, "wallet-1", "spender", logic.AccessSpend, 20.01), logic.ErrSpendingCapExceeded)
+		assert.NoError(t, impl.AuthorizeWallet(ctx, "wallet-1", "spender", logic.AccessView, 500))
+	})
+
+	t.Run("pockets use the parent's members", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		pocket := pocketWallet("pocket-1", 0, nil)
+		mockDao.On("GetWalletByID", "pocket-1").Return(&pocket, nil).Once()
+		mockDao.On("GetWalletMember", "wallet-p", "owner").Return(member("owner", common.MemberRoleOwner, common.MemberStatusActive, nil), nil).Once()
+
+		assert.NoError(t, impl.AuthorizeWallet(ctx, "pocket-1", "owner", logic.AccessManage, 0))
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("unknown wallet", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetWalletByID", "wallet-x").Return(nil, dao.ErrWalletNotFound).Once()
+
+		assert.ErrorIs(t, impl.AuthorizeWallet(ctx, "wallet-x", "owner", logic.AccessView, 0), logic.ErrWalletNotFound)
+	})
+}
+
+func TestInviteMember(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("spender with a cap", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		spendingCap := 250.0
+		mockDao.On("GetWalletByID", "wallet-1").Return(sharedWallet(), nil).Once()
+		mockDao.On("GetUserByID", "user-2").Return(&dao.User{ID: "user-2"}, nil).Once()
+		mockDao.On("GetWalletMember", "wallet-1", "user-2").Return(nil, dao.ErrMemberNotFound).Once()
+		mockDao.On("SaveInvitation", mock.MatchedBy(func(m *dao.WalletMember) bool {
+			return m.Role == common.MemberRoleSpender && *m.SpendingCap == 250 && m.Status == common.MemberStatusInvited &&
+				m.InvitedBy == "user-1"
+		})).Return(nil).Once()
+
+		invited, err := impl.InviteMember(ctx, "wallet-1", logic.MemberInvite{
+			UserID: "user-2", Role: common.MemberRoleSpender, SpendingCap: &spendingCap, InvitedBy: "user-1",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, common.MemberStatusInvited, invited.Status)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("invalid role or cap", func(t *testing.T) {
+		impl, _ := setupLogicTest()
+		spendingCap := 10.0
+
+		_, err := impl.InviteMember(ctx, "wallet-1", logic.MemberInvite{UserID: "user-2", Role: "admin"})
+		assert.ErrorIs(t, err, logic.ErrInvalidMemberRole)
+		_, err = impl.InviteMember(ctx, "wallet-1", logic.MemberInvite{UserID: "user-2", Role: common.MemberRoleViewer, SpendingCap: &spendingCap})
+		assert.ErrorIs(t, err, logic.ErrInvalidSpendingCap)
+	})
+
+	t.Run("already a member", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetWalletByID", "wallet-1").Return(sharedWallet(), nil).Once()
+		mockDao.On("GetUserByID", "user-2").Return(&dao.User{ID: "user-2"}, nil).Once()
+		mockDao.On("GetWalletMember", "wallet-1", "user-2").
+			Return(member("user-2", common.MemberRoleViewer, common.MemberStatusActive, nil), nil).Once()
+
+		_, err := impl.InviteMember(ctx, "wallet-1", logic.MemberInvite{UserID: "user-2", Role: common.MemberRoleOwner})
+		assert.ErrorIs(t, err, logic.ErrAlreadyMember)
+		mockDao.AssertNotCalled(t, "SaveInvitation", mock.Anything)
+	})
+
+	t.Run("pockets have no members", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		pocket := pocketWallet("pocket-1", 0, nil)
+		mockDao.On("GetWalletByID", "pocket-1").Return(&pocket, nil).Once()
+
+		_, err := impl.InviteMember(ctx, "pocket-1", logic.MemberInvite{UserID: "user-2", Role: common.MemberRoleViewer})
+		assert.ErrorIs(t, err, logic.ErrPocketMembers)
+	})
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("accepted", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("AcceptInvitation", "wallet-1", "user-2", mock.Anything).Return(nil).Once()
+		mockDao.On("GetWalletMember", "wallet-1", "user-2").
+			Return(member("user-2", common.MemberRoleViewer, common.MemberStatusActive, nil), nil).Once()
+
+		joined, err := impl.AcceptInvitation(ctx, "wallet-1", "user-2")
+		assert.NoError(t, err)
+		assert.Equal(t, common.MemberStatusActive, joined.Status)
+	})
+
+	t.Run("no invitation", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("AcceptInvitation", "wallet-1", "user-2", mock.Anything).Return(dao.ErrMemberNotFound).Once()
+
+		_, err := impl.AcceptInvitation(ctx, "wallet-1", "user-2")
+		assert.ErrorIs(t, err, logic.ErrInvitationNotFound)
+	})
+}
+
+func TestListMembers(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	spendingCap := 100.0
+	mockDao.On("GetWalletByID", "wallet-1").Return(sharedWallet(), nil).Once()
+	mockDao.On("ListWalletMembers", "wallet-1").Return([]dao.WalletMember{
+		*member("user-1", common.MemberRoleOwner, common.MemberStatusActive, nil),
+		*member("user-2", common.MemberRoleSpender, common.MemberStatusActive, &spendingCap),
+		*member("user-3", common.MemberRoleViewer, common.MemberStatusInvited, nil),
+	}, nil).Once()
+	mockDao.On("SumMemberSpending", "wallet-1", "user-1", mock.Anything).Return(500.0, nil).Once()
+	mockDao.On("SumMemberSpending", "wallet-1", "user-2", mock.Anything).Return(130.0, nil).Once()
+
+	members, err := impl.ListMembers(context.TODO(), "wallet-1")
+	assert.NoError(t, err)
+	assert.Len(t, members, 3)
+	assert.Equal(t, 500.0, members[0].SpentThisMonth)
+	assert.Nil(t, members[0].RemainingCap)
+	assert.Equal(t, 0.0, *members[1].RemainingCap)
+	assert.Equal(t, 0.0, members[2].SpentThisMonth)
+	mockDao.AssertExpectations(t)
+}
+
+func TestRemoveMember(t *testing.T) {
+	ctx := context.TODO()
+	owner := member("user-1", common.MemberRoleOwner, common.MemberStatusActive, nil)
+
+	t.Run("last owner stays", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetWalletMember", "wallet-1", "user-1").Return(owner, nil).Once()
+		mockDao.On("ListWalletMembers", "wallet-1").Return([]dao.WalletMember{
+			*owner, *member("user-2", common.MemberRoleOwner, common.MemberStatusInvited, nil),
+		}, nil).Once()
+
+		assert.ErrorIs(t, impl.RemoveMember(ctx, "wallet-1", "user-1", "user-1"), logic.ErrLastOwner)
+		mockDao.AssertNotCalled(t, "RemoveWalletMember", mock.Anything, mock.Anything)
+	})
+
+	t.Run("spender removed", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetWalletMember", "wallet-1", "user-2").
+			Return(member("user-2", common.MemberRoleSpender, common.MemberStatusActive, nil), nil).Once()
+		mockDao.On("RemoveWalletMember", "wallet-1", "user-2").Return(nil).Once()
+
+		assert.NoError(t, impl.RemoveMember(ctx, "wallet-1", "user-2", "user-1"))
+		mockDao.AssertExpectations(t)
+	})
+}
+
+func TestWithdrawRecordsInitiator(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	ctx := audit.WithMeta(context.TODO(), audit.Meta{Actor: "user-2"})
+	mockDao.On("GetBalance", "wallet-1").Return(100.0, nil).Once()
+	expectActiveWallets(mockDao, "wallet-1")
+	mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil).Once()
+	mockDao.On("PostWithdrawal", mock.MatchedBy(func(w dao.Withdrawal) bool {
+		return w.Debit.InitiatedBy != nil && *w.Debit.InitiatedBy == "user-2"
+	})).Return(nil).Once()
+
+	assert.NoError(t, impl.Withdraw(ctx, "wallet-1", 40))
+	mockDao.AssertExpectations(t)
+}
@@ -6,26 +6,2052 @@ import (
 	context "context"
 
 	dao "github.com/julkhong/walletapp/server/internal/dao"
+	fees "github.com/julkhong/walletapp/server/internal/fees"
+
+	http "net/http"
+
+	io "io"
+
+	logic "github.com/julkhong/walletapp/server/internal/logic"
 
 	mock "github.com/stretchr/testify/mock"
+
+	payout "github.com/julkhong/walletapp/server/internal/payout"
+
+	statement "github.com/julkhong/walletapp/server/internal/statement"
+
+	time "time"
 )
 
-// WalletImplInterface is an autogenerated mock type for the WalletImplInterface type
-type WalletImplInterface struct {
-	mock.Mock
+// WalletImplInterface is an autogenerated mock type for the WalletImplInterface type
+type WalletImplInterface struct {
+	mock.Mock
+}
+
+// AcceptInvitation provides a mock function with given fields: ctx, walletID, userID
+func (_m *WalletImplInterface) AcceptInvitation(ctx context.Context, walletID string, userID string) (*dao.WalletMember, error) {
+	ret := _m.Called(ctx, walletID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcceptInvitation")
+	}
+
+	var r0 *dao.WalletMember
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*dao.WalletMember, error)); ok {
+		return rf(ctx, walletID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dao.WalletMember); ok {
+		r0 = rf(ctx, walletID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.WalletMember)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, walletID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AcceptPaymentRequest provides a mock function with given fields: ctx, requestID, actor
+func (_m *WalletImplInterface) AcceptPaymentRequest(ctx context.Context, requestID string, actor string) (*dao.PaymentRequest, error) {
+	ret := _m.Called(ctx, requestID, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcceptPaymentRequest")
+	}
+
+	var r0 *dao.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*dao.PaymentRequest, error)); ok {
+		return rf(ctx, requestID, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dao.PaymentRequest); ok {
+		r0 = rf(ctx, requestID, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, requestID, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AccrueInterest provides a mock function with given fields: ctx
+func (_m *WalletImplInterface) AccrueInterest(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AccrueInterest")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ApprovePendingOperation provides a mock function with given fields: ctx, operationID, approver
+func (_m *WalletImplInterface) ApprovePendingOperation(ctx context.Context, operationID string, approver string) (*dao.PendingOperation, error) {
+	ret := _m.Called(ctx, operationID, approver)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApprovePendingOperation")
+	}
+
+	var r0 *dao.PendingOperation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*dao.PendingOperation, error)); ok {
+		return rf(ctx, operationID, approver)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dao.PendingOperation); ok {
+		r0 = rf(ctx, operationID, approver)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.PendingOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, operationID, approver)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ApproveTransactionReview provides a mock function with given fields: ctx, reviewID, reviewer
+func (_m *WalletImplInterface) ApproveTransactionReview(ctx context.Context, reviewID string, reviewer string) (*dao.TransactionReview, error) {
+	ret := _m.Called(ctx, reviewID, reviewer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApproveTransactionReview")
+	}
+
+	var r0 *dao.TransactionReview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*dao.TransactionReview, error)); ok {
+		return rf(ctx, reviewID, reviewer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dao.TransactionReview); ok {
+		r0 = rf(ctx, reviewID, reviewer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.TransactionReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, reviewID, reviewer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuthorizeWallet provides a mock function with given fields: ctx, walletID, userID, access, amount
+func (_m *WalletImplInterface) AuthorizeWallet(ctx context.Context, walletID string, userID string, access logic.WalletAccess, amount float64) error {
+	ret := _m.Called(ctx, walletID, userID, access, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthorizeWallet")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, logic.WalletAccess, float64) error); ok {
+		r0 = rf(ctx, walletID, userID, access, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CancelPaymentRequest provides a mock function with given fields: ctx, requestID, actor
+func (_m *WalletImplInterface) CancelPaymentRequest(ctx context.Context, requestID string, actor string) (*dao.PaymentRequest, error) {
+	ret := _m.Called(ctx, requestID, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelPaymentRequest")
+	}
+
+	var r0 *dao.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*dao.PaymentRequest, error)); ok {
+		return rf(ctx, requestID, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dao.PaymentRequest); ok {
+		r0 = rf(ctx, requestID, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, requestID, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CancelScheduledTransfer provides a mock function with given fields: ctx, scheduleID, actor
+func (_m *WalletImplInterface) CancelScheduledTransfer(ctx context.Context, scheduleID string, actor string) (*dao.Schedule, error) {
+	ret := _m.Called(ctx, scheduleID, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelScheduledTransfer")
+	}
+
+	var r0 *dao.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*dao.Schedule, error)); ok {
+		return rf(ctx, scheduleID, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dao.Schedule); ok {
+		r0 = rf(ctx, scheduleID, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, scheduleID, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckLedgerIntegrity provides a mock function with given fields: ctx, apply, operator
+func (_m *WalletImplInterface) CheckLedgerIntegrity(ctx context.Context, apply bool, operator string) (*logic.LedgerReport, error) {
+	ret := _m.Called(ctx, apply, operator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckLedgerIntegrity")
+	}
+
+	var r0 *logic.LedgerReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, bool, string) (*logic.LedgerReport, error)); ok {
+		return rf(ctx, apply, operator)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, bool, string) *logic.LedgerReport); ok {
+		r0 = rf(ctx, apply, operator)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.LedgerReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, bool, string) error); ok {
+		r1 = rf(ctx, apply, operator)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateEscrow provides a mock function with given fields: ctx, req
+func (_m *WalletImplInterface) CreateEscrow(ctx context.Context, req logic.EscrowRequest) (*dao.Escrow, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateEscrow")
+	}
+
+	var r0 *dao.Escrow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, logic.EscrowRequest) (*dao.Escrow, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, logic.EscrowRequest) *dao.Escrow); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Escrow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, logic.EscrowRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreatePaymentLink provides a mock function with given fields: ctx, in
+func (_m *WalletImplInterface) CreatePaymentLink(ctx context.Context, in logic.PaymentLinkInput) (*logic.PaymentLinkView, error) {
+	ret := _m.Called(ctx, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePaymentLink")
+	}
+
+	var r0 *logic.PaymentLinkView
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, logic.PaymentLinkInput) (*logic.PaymentLinkView, error)); ok {
+		return rf(ctx, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, logic.PaymentLinkInput) *logic.PaymentLinkView); ok {
+		r0 = rf(ctx, in)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.PaymentLinkView)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, logic.PaymentLinkInput) error); ok {
+		r1 = rf(ctx, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreatePaymentRequest provides a mock function with given fields: ctx, in
+func (_m *WalletImplInterface) CreatePaymentRequest(ctx context.Context, in logic.PaymentRequestInput) (*dao.PaymentRequest, error) {
+	ret := _m.Called(ctx, in)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePaymentRequest")
+	}
+
+	var r0 *dao.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, logic.PaymentRequestInput) (*dao.PaymentRequest, error)); ok {
+		return rf(ctx, in)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, logic.PaymentRequestInput) *dao.PaymentRequest); ok {
+		r0 = rf(ctx, in)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, logic.PaymentRequestInput) error); ok {
+		r1 = rf(ctx, in)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreatePocket provides a mock function with given fields: ctx, parentWalletID, name, goalAmount, goalDate
+func (_m *WalletImplInterface) CreatePocket(ctx context.Context, parentWalletID string, name string, goalAmount *float64, goalDate *time.Time) (*dao.Wallet, error) {
+	ret := _m.Called(ctx, parentWalletID, name, goalAmount, goalDate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePocket")
+	}
+
+	var r0 *dao.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *float64, *time.Time) (*dao.Wallet, error)); ok {
+		return rf(ctx, parentWalletID, name, goalAmount, goalDate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *float64, *time.Time) *dao.Wallet); ok {
+		r0 = rf(ctx, parentWalletID, name, goalAmount, goalDate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *float64, *time.Time) error); ok {
+		r1 = rf(ctx, parentWalletID, name, goalAmount, goalDate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateScheduledTransfer provides a mock function with given fields: ctx, req
+func (_m *WalletImplInterface) CreateScheduledTransfer(ctx context.Context, req logic.ScheduleRequest) (*dao.Schedule, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateScheduledTransfer")
+	}
+
+	var r0 *dao.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, logic.ScheduleRequest) (*dao.Schedule, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, logic.ScheduleRequest) *dao.Schedule); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, logic.ScheduleRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTransferBatch provides a mock function with given fields: ctx, req
+func (_m *WalletImplInterface) CreateTransferBatch(ctx context.Context, req logic.TransferBatchRequest) (*logic.TransferBatchResult, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTransferBatch")
+	}
+
+	var r0 *logic.TransferBatchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, logic.TransferBatchRequest) (*logic.TransferBatchResult, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, logic.TransferBatchRequest) *logic.TransferBatchResult); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.TransferBatchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, logic.TransferBatchRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateUser provides a mock function with given fields: ctx, name, email
+func (_m *WalletImplInterface) CreateUser(ctx context.Context, name string, email string) (*dao.User, *dao.Wallet, *dao.UserScreening, error) {
+	ret := _m.Called(ctx, name, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUser")
+	}
+
+	var r0 *dao.User
+	var r1 *dao.Wallet
+	var r2 *dao.UserScreening
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*dao.User, *dao.Wallet, *dao.UserScreening, error)); ok {
+		return rf(ctx, name, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dao.User); ok {
+		r0 = rf(ctx, name, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) *dao.Wallet); ok {
+		r1 = rf(ctx, name, email)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*dao.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string) *dao.UserScreening); ok {
+		r2 = rf(ctx, name, email)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(*dao.UserScreening)
+		}
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, string, string) error); ok {
+		r3 = rf(ctx, name, email)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
+// CreateWallet provides a mock function with given fields: ctx, userID, walletType, interestRate
+func (_m *WalletImplInterface) CreateWallet(ctx context.Context, userID string, walletType string, interestRate *float64) (*dao.Wallet, error) {
+	ret := _m.Called(ctx, userID, walletType, interestRate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWallet")
+	}
+
+	var r0 *dao.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *float64) (*dao.Wallet, error)); ok {
+		return rf(ctx, userID, walletType, interestRate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *float64) *dao.Wallet); ok {
+		r0 = rf(ctx, userID, walletType, interestRate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *float64) error); ok {
+		r1 = rf(ctx, userID, walletType, interestRate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeclinePaymentRequest provides a mock function with given fields: ctx, requestID, actor
+func (_m *WalletImplInterface) DeclinePaymentRequest(ctx context.Context, requestID string, actor string) (*dao.PaymentRequest, error) {
+	ret := _m.Called(ctx, requestID, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeclinePaymentRequest")
+	}
+
+	var r0 *dao.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*dao.PaymentRequest, error)); ok {
+		return rf(ctx, requestID, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dao.PaymentRequest); ok {
+		r0 = rf(ctx, requestID, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, requestID, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Deposit provides a mock function with given fields: ctx, walletID, amount
+func (_m *WalletImplInterface) Deposit(ctx context.Context, walletID string, amount float64) error {
+	ret := _m.Called(ctx, walletID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Deposit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, float64) error); ok {
+		r0 = rf(ctx, walletID, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisputeEscrow provides a mock function with given fields: ctx, escrowID, reason, actor
+func (_m *WalletImplInterface) DisputeEscrow(ctx context.Context, escrowID string, reason string, actor string) (*dao.Escrow, error) {
+	ret := _m.Called(ctx, escrowID, reason, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisputeEscrow")
+	}
+
+	var r0 *dao.Escrow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*dao.Escrow, error)); ok {
+		return rf(ctx, escrowID, reason, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *dao.Escrow); ok {
+		r0 = rf(ctx, escrowID, reason, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Escrow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, escrowID, reason, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpireEscrows provides a mock function with given fields: ctx
+func (_m *WalletImplInterface) ExpireEscrows(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireEscrows")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpirePaymentRequests provides a mock function with given fields: ctx
+func (_m *WalletImplInterface) ExpirePaymentRequests(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpirePaymentRequests")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExpirePendingOperations provides a mock function with given fields: ctx
+func (_m *WalletImplInterface) ExpirePendingOperations(ctx context.Context) ([]dao.PendingOperation, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpirePendingOperations")
+	}
+
+	var r0 []dao.PendingOperation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]dao.PendingOperation, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []dao.PendingOperation); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.PendingOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportTransactions provides a mock function with given fields: ctx, filter, format, out
+func (_m *WalletImplInterface) ExportTransactions(ctx context.Context, filter dao.TransactionFilter, format string, out io.Writer) error {
+	ret := _m.Called(ctx, filter, format, out)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportTransactions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dao.TransactionFilter, string, io.Writer) error); ok {
+		r0 = rf(ctx, filter, format, out)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GenerateMonthlyStatements provides a mock function with given fields: ctx
+func (_m *WalletImplInterface) GenerateMonthlyStatements(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateMonthlyStatements")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GeneratePayoutBatch provides a mock function with given fields: ctx
+func (_m *WalletImplInterface) GeneratePayoutBatch(ctx context.Context) (*dao.PayoutBatch, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GeneratePayoutBatch")
+	}
+
+	var r0 *dao.PayoutBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*dao.PayoutBatch, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *dao.PayoutBatch); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.PayoutBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBalance provides a mock function with given fields: ctx, walletID
+func (_m *WalletImplInterface) GetBalance(ctx context.Context, walletID string) (float64, error) {
+	ret := _m.Called(ctx, walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalance")
+	}
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (float64, error)); ok {
+		return rf(ctx, walletID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) float64); ok {
+		r0 = rf(ctx, walletID)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBalanceAsOf provides a mock function with given fields: ctx, walletID, asOf
+func (_m *WalletImplInterface) GetBalanceAsOf(ctx context.Context, walletID string, asOf time.Time) (float64, error) {
+	ret := _m.Called(ctx, walletID, asOf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBalanceAsOf")
+	}
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (float64, error)); ok {
+		return rf(ctx, walletID, asOf)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) float64); ok {
+		r0 = rf(ctx, walletID, asOf)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, walletID, asOf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBankImport provides a mock function with given fields: ctx, importID
+func (_m *WalletImplInterface) GetBankImport(ctx context.Context, importID string) (*logic.BankImportResult, error) {
+	ret := _m.Called(ctx, importID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBankImport")
+	}
+
+	var r0 *logic.BankImportResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*logic.BankImportResult, error)); ok {
+		return rf(ctx, importID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *logic.BankImportResult); ok {
+		r0 = rf(ctx, importID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.BankImportResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, importID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCreditLimit provides a mock function with given fields: ctx, walletID
+func (_m *WalletImplInterface) GetCreditLimit(ctx context.Context, walletID string) (float64, error) {
+	ret := _m.Called(ctx, walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCreditLimit")
+	}
+
+	var r0 float64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (float64, error)); ok {
+		return rf(ctx, walletID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) float64); ok {
+		r0 = rf(ctx, walletID)
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEscrow provides a mock function with given fields: ctx, escrowID
+func (_m *WalletImplInterface) GetEscrow(ctx context.Context, escrowID string) (*dao.Escrow, error) {
+	ret := _m.Called(ctx, escrowID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEscrow")
+	}
+
+	var r0 *dao.Escrow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*dao.Escrow, error)); ok {
+		return rf(ctx, escrowID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *dao.Escrow); ok {
+		r0 = rf(ctx, escrowID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Escrow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, escrowID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetInterestReport provides a mock function with given fields: ctx, walletID, from, to
+func (_m *WalletImplInterface) GetInterestReport(ctx context.Context, walletID string, from time.Time, to time.Time) (*logic.InterestReport, error) {
+	ret := _m.Called(ctx, walletID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInterestReport")
+	}
+
+	var r0 *logic.InterestReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) (*logic.InterestReport, error)); ok {
+		return rf(ctx, walletID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Time) *logic.InterestReport); ok {
+		r0 = rf(ctx, walletID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.InterestReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, walletID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPaymentRequest provides a mock function with given fields: ctx, requestID
+func (_m *WalletImplInterface) GetPaymentRequest(ctx context.Context, requestID string) (*dao.PaymentRequest, error) {
+	ret := _m.Called(ctx, requestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPaymentRequest")
+	}
+
+	var r0 *dao.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*dao.PaymentRequest, error)); ok {
+		return rf(ctx, requestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *dao.PaymentRequest); ok {
+		r0 = rf(ctx, requestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, requestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPayout provides a mock function with given fields: ctx, payoutID
+func (_m *WalletImplInterface) GetPayout(ctx context.Context, payoutID string) (*dao.Payout, error) {
+	ret := _m.Called(ctx, payoutID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayout")
+	}
+
+	var r0 *dao.Payout
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*dao.Payout, error)); ok {
+		return rf(ctx, payoutID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *dao.Payout); ok {
+		r0 = rf(ctx, payoutID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Payout)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, payoutID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPendingOperation provides a mock function with given fields: ctx, operationID
+func (_m *WalletImplInterface) GetPendingOperation(ctx context.Context, operationID string) (*dao.PendingOperation, error) {
+	ret := _m.Called(ctx, operationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingOperation")
+	}
+
+	var r0 *dao.PendingOperation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*dao.PendingOperation, error)); ok {
+		return rf(ctx, operationID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *dao.PendingOperation); ok {
+		r0 = rf(ctx, operationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.PendingOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, operationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProviderOperation provides a mock function with given fields: ctx, opID
+func (_m *WalletImplInterface) GetProviderOperation(ctx context.Context, opID string) (*dao.ProviderOperation, error) {
+	ret := _m.Called(ctx, opID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProviderOperation")
+	}
+
+	var r0 *dao.ProviderOperation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*dao.ProviderOperation, error)); ok {
+		return rf(ctx, opID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *dao.ProviderOperation); ok {
+		r0 = rf(ctx, opID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.ProviderOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, opID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetScheduledTransfer provides a mock function with given fields: ctx, scheduleID
+func (_m *WalletImplInterface) GetScheduledTransfer(ctx context.Context, scheduleID string) (*logic.ScheduleDetail, error) {
+	ret := _m.Called(ctx, scheduleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetScheduledTransfer")
+	}
+
+	var r0 *logic.ScheduleDetail
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*logic.ScheduleDetail, error)); ok {
+		return rf(ctx, scheduleID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *logic.ScheduleDetail); ok {
+		r0 = rf(ctx, scheduleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.ScheduleDetail)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, scheduleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStatement provides a mock function with given fields: ctx, statementID
+func (_m *WalletImplInterface) GetStatement(ctx context.Context, statementID string) (*statement.Statement, error) {
+	ret := _m.Called(ctx, statementID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatement")
+	}
+
+	var r0 *statement.Statement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*statement.Statement, error)); ok {
+		return rf(ctx, statementID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *statement.Statement); ok {
+		r0 = rf(ctx, statementID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*statement.Statement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, statementID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransactionHistory provides a mock function with given fields: ctx, filter, cursor
+func (_m *WalletImplInterface) GetTransactionHistory(ctx context.Context, filter dao.TransactionFilter, cursor string) (*logic.TransactionPage, error) {
+	ret := _m.Called(ctx, filter, cursor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionHistory")
+	}
+
+	var r0 *logic.TransactionPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dao.TransactionFilter, string) (*logic.TransactionPage, error)); ok {
+		return rf(ctx, filter, cursor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dao.TransactionFilter, string) *logic.TransactionPage); ok {
+		r0 = rf(ctx, filter, cursor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.TransactionPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dao.TransactionFilter, string) error); ok {
+		r1 = rf(ctx, filter, cursor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransferBatch provides a mock function with given fields: ctx, batchID
+func (_m *WalletImplInterface) GetTransferBatch(ctx context.Context, batchID string) (*logic.TransferBatchResult, error) {
+	ret := _m.Called(ctx, batchID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransferBatch")
+	}
+
+	var r0 *logic.TransferBatchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*logic.TransferBatchResult, error)); ok {
+		return rf(ctx, batchID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *logic.TransferBatchResult); ok {
+		r0 = rf(ctx, batchID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.TransferBatchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, batchID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWalletView provides a mock function with given fields: ctx, walletID
+func (_m *WalletImplInterface) GetWalletView(ctx context.Context, walletID string) (*logic.WalletView, error) {
+	ret := _m.Called(ctx, walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWalletView")
+	}
+
+	var r0 *logic.WalletView
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*logic.WalletView, error)); ok {
+		return rf(ctx, walletID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *logic.WalletView); ok {
+		r0 = rf(ctx, walletID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.WalletView)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HandleProviderCallback provides a mock function with given fields: ctx, name, header, body
+func (_m *WalletImplInterface) HandleProviderCallback(ctx context.Context, name string, header http.Header, body []byte) (int, error) {
+	ret := _m.Called(ctx, name, header, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HandleProviderCallback")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, http.Header, []byte) (int, error)); ok {
+		return rf(ctx, name, header, body)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, http.Header, []byte) int); ok {
+		r0 = rf(ctx, name, header, body)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, http.Header, []byte) error); ok {
+		r1 = rf(ctx, name, header, body)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportBankStatement provides a mock function with given fields: ctx, format, data, importedBy
+func (_m *WalletImplInterface) ImportBankStatement(ctx context.Context, format string, data []byte, importedBy string) (*logic.BankImportResult, error) {
+	ret := _m.Called(ctx, format, data, importedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportBankStatement")
+	}
+
+	var r0 *logic.BankImportResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte, string) (*logic.BankImportResult, error)); ok {
+		return rf(ctx, format, data, importedBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte, string) *logic.BankImportResult); ok {
+		r0 = rf(ctx, format, data, importedBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.BankImportResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []byte, string) error); ok {
+		r1 = rf(ctx, format, data, importedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InviteMember provides a mock function with given fields: ctx, walletID, invite
+func (_m *WalletImplInterface) InviteMember(ctx context.Context, walletID string, invite logic.MemberInvite) (*dao.WalletMember, error) {
+	ret := _m.Called(ctx, walletID, invite)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InviteMember")
+	}
+
+	var r0 *dao.WalletMember
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, logic.MemberInvite) (*dao.WalletMember, error)); ok {
+		return rf(ctx, walletID, invite)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, logic.MemberInvite) *dao.WalletMember); ok {
+		r0 = rf(ctx, walletID, invite)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.WalletMember)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, logic.MemberInvite) error); ok {
+		r1 = rf(ctx, walletID, invite)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAdjustments provides a mock function with given fields: ctx, walletID
+func (_m *WalletImplInterface) ListAdjustments(ctx context.Context, walletID string) ([]dao.Adjustment, error) {
+	ret := _m.Called(ctx, walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAdjustments")
+	}
+
+	var r0 []dao.Adjustment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]dao.Adjustment, error)); ok {
+		return rf(ctx, walletID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []dao.Adjustment); ok {
+		r0 = rf(ctx, walletID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.Adjustment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAuditLog provides a mock function with given fields: ctx, afterSeq, limit
+func (_m *WalletImplInterface) ListAuditLog(ctx context.Context, afterSeq int64, limit int) ([]dao.AuditLog, error) {
+	ret := _m.Called(ctx, afterSeq, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAuditLog")
+	}
+
+	var r0 []dao.AuditLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) ([]dao.AuditLog, error)); ok {
+		return rf(ctx, afterSeq, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) []dao.AuditLog); ok {
+		r0 = rf(ctx, afterSeq, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.AuditLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, afterSeq, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListMembers provides a mock function with given fields: ctx, walletID
+func (_m *WalletImplInterface) ListMembers(ctx context.Context, walletID string) ([]logic.MemberView, error) {
+	ret := _m.Called(ctx, walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListMembers")
+	}
+
+	var r0 []logic.MemberView
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]logic.MemberView, error)); ok {
+		return rf(ctx, walletID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []logic.MemberView); ok {
+		r0 = rf(ctx, walletID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]logic.MemberView)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPaymentRequests provides a mock function with given fields: ctx, walletID, incoming, status
+func (_m *WalletImplInterface) ListPaymentRequests(ctx context.Context, walletID string, incoming bool, status string) ([]dao.PaymentRequest, error) {
+	ret := _m.Called(ctx, walletID, incoming, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPaymentRequests")
+	}
+
+	var r0 []dao.PaymentRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, string) ([]dao.PaymentRequest, error)); ok {
+		return rf(ctx, walletID, incoming, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, string) []dao.PaymentRequest); ok {
+		r0 = rf(ctx, walletID, incoming, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.PaymentRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool, string) error); ok {
+		r1 = rf(ctx, walletID, incoming, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPayouts provides a mock function with given fields: ctx, status
+func (_m *WalletImplInterface) ListPayouts(ctx context.Context, status string) ([]dao.Payout, error) {
+	ret := _m.Called(ctx, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPayouts")
+	}
+
+	var r0 []dao.Payout
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]dao.Payout, error)); ok {
+		return rf(ctx, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []dao.Payout); ok {
+		r0 = rf(ctx, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.Payout)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPendingOperations provides a mock function with given fields: ctx, status
+func (_m *WalletImplInterface) ListPendingOperations(ctx context.Context, status string) ([]dao.PendingOperation, error) {
+	ret := _m.Called(ctx, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPendingOperations")
+	}
+
+	var r0 []dao.PendingOperation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]dao.PendingOperation, error)); ok {
+		return rf(ctx, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []dao.PendingOperation); ok {
+		r0 = rf(ctx, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.PendingOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListScheduledTransfers provides a mock function with given fields: ctx, walletID
+func (_m *WalletImplInterface) ListScheduledTransfers(ctx context.Context, walletID string) ([]dao.Schedule, error) {
+	ret := _m.Called(ctx, walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListScheduledTransfers")
+	}
+
+	var r0 []dao.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]dao.Schedule, error)); ok {
+		return rf(ctx, walletID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []dao.Schedule); ok {
+		r0 = rf(ctx, walletID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListStatements provides a mock function with given fields: ctx, walletID
+func (_m *WalletImplInterface) ListStatements(ctx context.Context, walletID string) ([]dao.Statement, error) {
+	ret := _m.Called(ctx, walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListStatements")
+	}
+
+	var r0 []dao.Statement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]dao.Statement, error)); ok {
+		return rf(ctx, walletID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []dao.Statement); ok {
+		r0 = rf(ctx, walletID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.Statement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTransactionReviews provides a mock function with given fields: ctx, status
+func (_m *WalletImplInterface) ListTransactionReviews(ctx context.Context, status string) ([]dao.TransactionReview, error) {
+	ret := _m.Called(ctx, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTransactionReviews")
+	}
+
+	var r0 []dao.TransactionReview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]dao.TransactionReview, error)); ok {
+		return rf(ctx, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []dao.TransactionReview); ok {
+		r0 = rf(ctx, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dao.TransactionReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MovePocketFunds provides a mock function with given fields: ctx, walletID, fromWalletID, toWalletID, amount
+func (_m *WalletImplInterface) MovePocketFunds(ctx context.Context, walletID string, fromWalletID string, toWalletID string, amount float64) (*logic.WalletView, error) {
+	ret := _m.Called(ctx, walletID, fromWalletID, toWalletID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MovePocketFunds")
+	}
+
+	var r0 *logic.WalletView
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, float64) (*logic.WalletView, error)); ok {
+		return rf(ctx, walletID, fromWalletID, toWalletID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, float64) *logic.WalletView); ok {
+		r0 = rf(ctx, walletID, fromWalletID, toWalletID, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.WalletView)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, float64) error); ok {
+		r1 = rf(ctx, walletID, fromWalletID, toWalletID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PauseScheduledTransfer provides a mock function with given fields: ctx, scheduleID, actor
+func (_m *WalletImplInterface) PauseScheduledTransfer(ctx context.Context, scheduleID string, actor string) (*dao.Schedule, error) {
+	ret := _m.Called(ctx, scheduleID, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PauseScheduledTransfer")
+	}
+
+	var r0 *dao.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*dao.Schedule, error)); ok {
+		return rf(ctx, scheduleID, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dao.Schedule); ok {
+		r0 = rf(ctx, scheduleID, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, scheduleID, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PayPaymentLink provides a mock function with given fields: ctx, token, fromWalletID, amount
+func (_m *WalletImplInterface) PayPaymentLink(ctx context.Context, token string, fromWalletID string, amount float64) (*logic.PaymentLinkPayment, error) {
+	ret := _m.Called(ctx, token, fromWalletID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PayPaymentLink")
+	}
+
+	var r0 *logic.PaymentLinkPayment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, float64) (*logic.PaymentLinkPayment, error)); ok {
+		return rf(ctx, token, fromWalletID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, float64) *logic.PaymentLinkPayment); ok {
+		r0 = rf(ctx, token, fromWalletID, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.PaymentLinkPayment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, float64) error); ok {
+		r1 = rf(ctx, token, fromWalletID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PaymentLinkQR provides a mock function with given fields: ctx, token, scale
+func (_m *WalletImplInterface) PaymentLinkQR(ctx context.Context, token string, scale int) ([]byte, error) {
+	ret := _m.Called(ctx, token, scale)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PaymentLinkQR")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]byte, error)); ok {
+		return rf(ctx, token, scale)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []byte); ok {
+		r0 = rf(ctx, token, scale)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, token, scale)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProcessTransferBatches provides a mock function with given fields: ctx
+func (_m *WalletImplInterface) ProcessTransferBatches(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProcessTransferBatches")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QuoteFee provides a mock function with given fields: ctx, txType, walletID, amount
+func (_m *WalletImplInterface) QuoteFee(ctx context.Context, txType string, walletID string, amount float64) (*fees.Quote, error) {
+	ret := _m.Called(ctx, txType, walletID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QuoteFee")
+	}
+
+	var r0 *fees.Quote
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, float64) (*fees.Quote, error)); ok {
+		return rf(ctx, txType, walletID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, float64) *fees.Quote); ok {
+		r0 = rf(ctx, txType, walletID, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fees.Quote)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, float64) error); ok {
+		r1 = rf(ctx, txType, walletID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordConfigChange provides a mock function with given fields: ctx, key, value
+func (_m *WalletImplInterface) RecordConfigChange(ctx context.Context, key string, value interface{}) {
+	_m.Called(ctx, key, value)
+}
+
+// RefundEscrow provides a mock function with given fields: ctx, escrowID, actor
+func (_m *WalletImplInterface) RefundEscrow(ctx context.Context, escrowID string, actor string) (*dao.Escrow, error) {
+	ret := _m.Called(ctx, escrowID, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefundEscrow")
+	}
+
+	var r0 *dao.Escrow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*dao.Escrow, error)); ok {
+		return rf(ctx, escrowID, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dao.Escrow); ok {
+		r0 = rf(ctx, escrowID, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Escrow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, escrowID, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RejectPendingOperation provides a mock function with given fields: ctx, operationID, approver
+func (_m *WalletImplInterface) RejectPendingOperation(ctx context.Context, operationID string, approver string) (*dao.PendingOperation, error) {
+	ret := _m.Called(ctx, operationID, approver)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectPendingOperation")
+	}
+
+	var r0 *dao.PendingOperation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*dao.PendingOperation, error)); ok {
+		return rf(ctx, operationID, approver)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dao.PendingOperation); ok {
+		r0 = rf(ctx, operationID, approver)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.PendingOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, operationID, approver)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RejectTransactionReview provides a mock function with given fields: ctx, reviewID, reviewer
+func (_m *WalletImplInterface) RejectTransactionReview(ctx context.Context, reviewID string, reviewer string) (*dao.TransactionReview, error) {
+	ret := _m.Called(ctx, reviewID, reviewer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RejectTransactionReview")
+	}
+
+	var r0 *dao.TransactionReview
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*dao.TransactionReview, error)); ok {
+		return rf(ctx, reviewID, reviewer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dao.TransactionReview); ok {
+		r0 = rf(ctx, reviewID, reviewer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.TransactionReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, reviewID, reviewer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseEscrow provides a mock function with given fields: ctx, escrowID, actor
+func (_m *WalletImplInterface) ReleaseEscrow(ctx context.Context, escrowID string, actor string) (*dao.Escrow, error) {
+	ret := _m.Called(ctx, escrowID, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseEscrow")
+	}
+
+	var r0 *dao.Escrow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*dao.Escrow, error)); ok {
+		return rf(ctx, escrowID, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dao.Escrow); ok {
+		r0 = rf(ctx, escrowID, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Escrow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, escrowID, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveMember provides a mock function with given fields: ctx, walletID, userID, actorID
+func (_m *WalletImplInterface) RemoveMember(ctx context.Context, walletID string, userID string, actorID string) error {
+	ret := _m.Called(ctx, walletID, userID, actorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, walletID, userID, actorID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequiresApproval provides a mock function with given fields: amount
+func (_m *WalletImplInterface) RequiresApproval(amount float64) bool {
+	ret := _m.Called(amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequiresApproval")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(float64) bool); ok {
+		r0 = rf(amount)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// ResolveEscrow provides a mock function with given fields: ctx, escrowID, outcome, operator
+func (_m *WalletImplInterface) ResolveEscrow(ctx context.Context, escrowID string, outcome string, operator string) (*dao.Escrow, error) {
+	ret := _m.Called(ctx, escrowID, outcome, operator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveEscrow")
+	}
+
+	var r0 *dao.Escrow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*dao.Escrow, error)); ok {
+		return rf(ctx, escrowID, outcome, operator)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *dao.Escrow); ok {
+		r0 = rf(ctx, escrowID, outcome, operator)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Escrow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, escrowID, outcome, operator)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResolvePaymentLink provides a mock function with given fields: ctx, token
+func (_m *WalletImplInterface) ResolvePaymentLink(ctx context.Context, token string) (*logic.PaymentLinkView, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolvePaymentLink")
+	}
+
+	var r0 *logic.PaymentLinkView
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*logic.PaymentLinkView, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *logic.PaymentLinkView); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.PaymentLinkView)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// Deposit provides a mock function with given fields: ctx, walletID, amount
-func (_m *WalletImplInterface) Deposit(ctx context.Context, walletID string, amount float64) error {
-	ret := _m.Called(ctx, walletID, amount)
+// ResumeScheduledTransfer provides a mock function with given fields: ctx, scheduleID, actor
+func (_m *WalletImplInterface) ResumeScheduledTransfer(ctx context.Context, scheduleID string, actor string) (*dao.Schedule, error) {
+	ret := _m.Called(ctx, scheduleID, actor)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Deposit")
+		panic("no return value specified for ResumeScheduledTransfer")
+	}
+
+	var r0 *dao.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*dao.Schedule, error)); ok {
+		return rf(ctx, scheduleID, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *dao.Schedule); ok {
+		r0 = rf(ctx, scheduleID, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, scheduleID, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RunDueSchedules provides a mock function with given fields: ctx
+func (_m *WalletImplInterface) RunDueSchedules(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunDueSchedules")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ScreenTransferParties provides a mock function with given fields: ctx, fromWalletID, toWalletID
+func (_m *WalletImplInterface) ScreenTransferParties(ctx context.Context, fromWalletID string, toWalletID string) error {
+	ret := _m.Called(ctx, fromWalletID, toWalletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ScreenTransferParties")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, float64) error); ok {
-		r0 = rf(ctx, walletID, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, fromWalletID, toWalletID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -33,27 +2059,29 @@ func (_m *WalletImplInterface) Deposit(ctx context.Context, walletID string, amo
 	return r0
 }
 
-// GetBalance provides a mock function with given fields: ctx, walletID
-func (_m *WalletImplInterface) GetBalance(ctx context.Context, walletID string) (float64, error) {
-	ret := _m.Called(ctx, walletID)
+// SetCreditLimit provides a mock function with given fields: ctx, walletID, limit, actorID
+func (_m *WalletImplInterface) SetCreditLimit(ctx context.Context, walletID string, limit float64, actorID string) (*dao.Wallet, error) {
+	ret := _m.Called(ctx, walletID, limit, actorID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetBalance")
+		panic("no return value specified for SetCreditLimit")
 	}
 
-	var r0 float64
+	var r0 *dao.Wallet
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (float64, error)); ok {
-		return rf(ctx, walletID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, float64, string) (*dao.Wallet, error)); ok {
+		return rf(ctx, walletID, limit, actorID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) float64); ok {
-		r0 = rf(ctx, walletID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, float64, string) *dao.Wallet); ok {
+		r0 = rf(ctx, walletID, limit, actorID)
 	} else {
-		r0 = ret.Get(0).(float64)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Wallet)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, walletID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, float64, string) error); ok {
+		r1 = rf(ctx, walletID, limit, actorID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -61,29 +2089,175 @@ func (_m *WalletImplInterface) GetBalance(ctx context.Context, walletID string)
 	return r0, r1
 }
 
-// GetTransactionHistory provides a mock function with given fields: ctx, walletID, txType, start, end, limit, offset
-func (_m *WalletImplInterface) GetTransactionHistory(ctx context.Context, walletID string, txType string, start string, end string, limit int, offset int) ([]dao.Transaction, error) {
-	ret := _m.Called(ctx, walletID, txType, start, end, limit, offset)
+// SetPocketGoal provides a mock function with given fields: ctx, pocketID, goalAmount, goalDate
+func (_m *WalletImplInterface) SetPocketGoal(ctx context.Context, pocketID string, goalAmount *float64, goalDate *time.Time) (*logic.Pocket, error) {
+	ret := _m.Called(ctx, pocketID, goalAmount, goalDate)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetTransactionHistory")
+		panic("no return value specified for SetPocketGoal")
+	}
+
+	var r0 *logic.Pocket
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *float64, *time.Time) (*logic.Pocket, error)); ok {
+		return rf(ctx, pocketID, goalAmount, goalDate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *float64, *time.Time) *logic.Pocket); ok {
+		r0 = rf(ctx, pocketID, goalAmount, goalDate)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.Pocket)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *float64, *time.Time) error); ok {
+		r1 = rf(ctx, pocketID, goalAmount, goalDate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SnapshotBalances provides a mock function with given fields: ctx
+func (_m *WalletImplInterface) SnapshotBalances(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SnapshotBalances")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SplitPayment provides a mock function with given fields: ctx, req
+func (_m *WalletImplInterface) SplitPayment(ctx context.Context, req logic.SplitPaymentRequest) (*logic.SplitPaymentResult, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SplitPayment")
+	}
+
+	var r0 *logic.SplitPaymentResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, logic.SplitPaymentRequest) (*logic.SplitPaymentResult, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, logic.SplitPaymentRequest) *logic.SplitPaymentResult); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.SplitPaymentResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, logic.SplitPaymentRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubmitAdjustment provides a mock function with given fields: ctx, payload, idempotencyKey, method, path
+func (_m *WalletImplInterface) SubmitAdjustment(ctx context.Context, payload logic.AdjustmentPayload, idempotencyKey string, method string, path string) (*dao.PendingOperation, error) {
+	ret := _m.Called(ctx, payload, idempotencyKey, method, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubmitAdjustment")
+	}
+
+	var r0 *dao.PendingOperation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, logic.AdjustmentPayload, string, string, string) (*dao.PendingOperation, error)); ok {
+		return rf(ctx, payload, idempotencyKey, method, path)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, logic.AdjustmentPayload, string, string, string) *dao.PendingOperation); ok {
+		r0 = rf(ctx, payload, idempotencyKey, method, path)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.PendingOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, logic.AdjustmentPayload, string, string, string) error); ok {
+		r1 = rf(ctx, payload, idempotencyKey, method, path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubmitForApproval provides a mock function with given fields: ctx, req
+func (_m *WalletImplInterface) SubmitForApproval(ctx context.Context, req logic.ApprovalRequest) (*dao.PendingOperation, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubmitForApproval")
 	}
 
-	var r0 []dao.Transaction
+	var r0 *dao.PendingOperation
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, int, int) ([]dao.Transaction, error)); ok {
-		return rf(ctx, walletID, txType, start, end, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, logic.ApprovalRequest) (*dao.PendingOperation, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, int, int) []dao.Transaction); ok {
-		r0 = rf(ctx, walletID, txType, start, end, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, logic.ApprovalRequest) *dao.PendingOperation); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]dao.Transaction)
+			r0 = ret.Get(0).(*dao.PendingOperation)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, int, int) error); ok {
-		r1 = rf(ctx, walletID, txType, start, end, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, logic.ApprovalRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SyncProviderOperations provides a mock function with given fields: ctx
+func (_m *WalletImplInterface) SyncProviderOperations(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SyncProviderOperations")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -92,21 +2266,123 @@ func (_m *WalletImplInterface) GetTransactionHistory(ctx context.Context, wallet
 }
 
 // Transfer provides a mock function with given fields: ctx, fromWalletID, toWalletID, amount
-func (_m *WalletImplInterface) Transfer(ctx context.Context, fromWalletID string, toWalletID string, amount float64) error {
+func (_m *WalletImplInterface) Transfer(ctx context.Context, fromWalletID string, toWalletID string, amount float64) (*fees.Quote, error) {
 	ret := _m.Called(ctx, fromWalletID, toWalletID, amount)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Transfer")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, float64) error); ok {
+	var r0 *fees.Quote
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, float64) (*fees.Quote, error)); ok {
+		return rf(ctx, fromWalletID, toWalletID, amount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, float64) *fees.Quote); ok {
 		r0 = rf(ctx, fromWalletID, toWalletID, amount)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fees.Quote)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, float64) error); ok {
+		r1 = rf(ctx, fromWalletID, toWalletID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePayoutStatus provides a mock function with given fields: ctx, payoutID, status, reason, operator
+func (_m *WalletImplInterface) UpdatePayoutStatus(ctx context.Context, payoutID string, status string, reason string, operator string) (*dao.Payout, error) {
+	ret := _m.Called(ctx, payoutID, status, reason, operator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePayoutStatus")
+	}
+
+	var r0 *dao.Payout
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) (*dao.Payout, error)); ok {
+		return rf(ctx, payoutID, status, reason, operator)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) *dao.Payout); ok {
+		r0 = rf(ctx, payoutID, status, reason, operator)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Payout)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, payoutID, status, reason, operator)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpgradeKycLevel provides a mock function with given fields: ctx, userID, level, evidenceRefs, approvedBy
+func (_m *WalletImplInterface) UpgradeKycLevel(ctx context.Context, userID string, level string, evidenceRefs []string, approvedBy string) (*dao.User, error) {
+	ret := _m.Called(ctx, userID, level, evidenceRefs, approvedBy)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpgradeKycLevel")
+	}
+
+	var r0 *dao.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, string) (*dao.User, error)); ok {
+		return rf(ctx, userID, level, evidenceRefs, approvedBy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, string) *dao.User); ok {
+		r0 = rf(ctx, userID, level, evidenceRefs, approvedBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string, string) error); ok {
+		r1 = rf(ctx, userID, level, evidenceRefs, approvedBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyAuditLog provides a mock function with given fields: ctx
+func (_m *WalletImplInterface) VerifyAuditLog(ctx context.Context) (*logic.AuditVerification, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyAuditLog")
+	}
+
+	var r0 *logic.AuditVerification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*logic.AuditVerification, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *logic.AuditVerification); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*logic.AuditVerification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // Withdraw provides a mock function with given fields: ctx, walletID, amount
@@ -127,6 +2403,66 @@ func (_m *WalletImplInterface) Withdraw(ctx context.Context, walletID string, am
 	return r0
 }
 
+// WithdrawToBank provides a mock function with given fields: ctx, walletID, amount, beneficiary
+func (_m *WalletImplInterface) WithdrawToBank(ctx context.Context, walletID string, amount float64, beneficiary payout.Account) (*dao.Payout, error) {
+	ret := _m.Called(ctx, walletID, amount, beneficiary)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithdrawToBank")
+	}
+
+	var r0 *dao.Payout
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, float64, payout.Account) (*dao.Payout, error)); ok {
+		return rf(ctx, walletID, amount, beneficiary)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, float64, payout.Account) *dao.Payout); ok {
+		r0 = rf(ctx, walletID, amount, beneficiary)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.Payout)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, float64, payout.Account) error); ok {
+		r1 = rf(ctx, walletID, amount, beneficiary)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithdrawViaProvider provides a mock function with given fields: ctx, walletID, amount, target
+func (_m *WalletImplInterface) WithdrawViaProvider(ctx context.Context, walletID string, amount float64, target logic.ProviderTarget) (*dao.ProviderOperation, error) {
+	ret := _m.Called(ctx, walletID, amount, target)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithdrawViaProvider")
+	}
+
+	var r0 *dao.ProviderOperation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, float64, logic.ProviderTarget) (*dao.ProviderOperation, error)); ok {
+		return rf(ctx, walletID, amount, target)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, float64, logic.ProviderTarget) *dao.ProviderOperation); ok {
+		r0 = rf(ctx, walletID, amount, target)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dao.ProviderOperation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, float64, logic.ProviderTarget) error); ok {
+		r1 = rf(ctx, walletID, amount, target)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewWalletImplInterface creates a new instance of WalletImplInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewWalletImplInterface(t interface {
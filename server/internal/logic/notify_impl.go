@@ -0,0 +1,34 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/notify"
+)
+
+// SetNotifier plugs the notifier told about events the members of a wallet should hear of.
+func (l *WalletImpl) SetNotifier(notifier notify.Notifier) {
+	l.notifier = notifier
+}
+
+// notify sends an event to the members of walletIDs. Notifications are best effort: a failure
+// is logged and never fails the operation that raised the event.
+func (l *WalletImpl) notify(ctx context.Context, eventType, entityID string, walletIDs []string, data any) {
+	if l.notifier == nil {
+		return
+	}
+	err := l.notifier.Notify(ctx, notify.Event{
+		ID:        uuid.NewString(),
+		Type:      eventType,
+		WalletIDs: walletIDs,
+		EntityID:  entityID,
+		Data:      data,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		l.logger.WithError(err).Warnf("Failed to send %s notification for %s", eventType, entityID)
+	}
+}
@@ -0,0 +1,288 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/fees"
+	"github.com/julkhong/walletapp/server/internal/paylink"
+	"github.com/julkhong/walletapp/server/internal/qrcode"
+)
+
+var (
+	ErrPaymentLinksDisabled        = errors.New("payment links are not configured")
+	ErrPaymentLinkNotFound         = errors.New("payment link not found")
+	ErrPaymentLinkExpired          = errors.New("payment link has expired")
+	ErrPaymentLinkUsed             = errors.New("payment link has already been used")
+	ErrInvalidPaymentLink          = errors.New("invalid payment link")
+	ErrPaymentLinkRequiresApproval = errors.New("amount requires approval and cannot be paid through a link")
+	ErrPaymentLinkNeedsReview      = errors.New("payment was flagged for review")
+)
+
+const (
+	// defaultPaymentLinkExpiry applies when no expiry is configured.
+	defaultPaymentLinkExpiry = 30 * 24 * time.Hour
+	// maxPaymentLinkReference bounds the reference, which must fit the EMV reference label.
+	maxPaymentLinkReference = 25
+	// DefaultQRScale is the pixels per module of a payment link QR code.
+	DefaultQRScale = 8
+	// MaxQRScale bounds the pixels per module a caller may ask for.
+	MaxQRScale = 32
+)
+
+// PaymentLinkConfig holds the secret payment link tokens are signed with and the merchant
+// details EMV payloads carry. Links are disabled while Secret is empty.
+type PaymentLinkConfig struct {
+	Secret   string
+	Expiry   time.Duration
+	GUID     string
+	Currency string
+	Country  string
+	City     string
+}
+
+func (l *WalletImpl) SetPaymentLinkConfig(cfg PaymentLinkConfig) {
+	l.links = cfg
+}
+
+// PaymentLinkInput creates a link paying WalletID. Without an Amount the payer chooses how much
+// to pay. ExpiresAt defaults to the configured expiry from now.
+type PaymentLinkInput struct {
+	WalletID  string
+	Amount    *float64
+	Reference string
+	SingleUse bool
+	ExpiresAt *time.Time
+	CreatedBy string
+}
+
+// PaymentLinkView is a link with what a payer needs to pay it: the token, the name of the payee
+// and the EMV payload its QR code carries.
+type PaymentLinkView struct {
+	dao.PaymentLink
+	Token     string `json:"token"`
+	PayeeName string `json:"payee_name"`
+	EMV       string `json:"emv"`
+}
+
+// PaymentLinkPayment is a settled payment through a link; TransactionID is the payer's row.
+type PaymentLinkPayment struct {
+	LinkID        string      `json:"payment_link_id"`
+	FromWalletID  string      `json:"from_wallet_id"`
+	ToWalletID    string      `json:"to_wallet_id"`
+	Amount        float64     `json:"amount"`
+	TransactionID string      `json:"transaction_id"`
+	Fee           *fees.Quote `json:"-"`
+}
+
+// CreatePaymentLink records a link and signs its token. Nothing moves until someone pays it.
+func (l *WalletImpl) CreatePaymentLink(ctx context.Context, in PaymentLinkInput) (*PaymentLinkView, error) {
+	signer, err := l.linkSigner()
+	if err != nil {
+		return nil, err
+	}
+	var amount *float64
+	if in.Amount != nil {
+		rounded := common.RoundToNDecimals(*in.Amount, 4)
+		if rounded <= 0 {
+			return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidPaymentLink)
+		}
+		amount = &rounded
+	}
+	if len(in.Reference) > maxPaymentLinkReference {
+		return nil, fmt.Errorf("%w: reference is longer than %d characters", ErrInvalidPaymentLink, maxPaymentLinkReference)
+	}
+	now := time.Now()
+	expiry := l.links.Expiry
+	if expiry <= 0 {
+		expiry = defaultPaymentLinkExpiry
+	}
+	expiresAt := now.Add(expiry)
+	if in.ExpiresAt != nil {
+		if !in.ExpiresAt.After(now) {
+			return nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidPaymentLink)
+		}
+		expiresAt = *in.ExpiresAt
+	}
+	if err := l.ensureWalletActive(in.WalletID); err != nil {
+		return nil, err
+	}
+
+	link := &dao.PaymentLink{
+		ID:        uuid.NewString(),
+		WalletID:  in.WalletID,
+		Amount:    amount,
+		Reference: in.Reference,
+		SingleUse: in.SingleUse,
+		ExpiresAt: expiresAt,
+		CreatedBy: in.CreatedBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	token, err := signer.Sign(link.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.dao.CreatePaymentLink(link); err != nil {
+		return nil, fmt.Errorf("payment link failed: %w", err)
+	}
+
+	l.recordAudit(ctx, common.AuditActionLinkCreated, common.AuditEntityLink, link.ID, map[string]any{
+		"wallet_id":  link.WalletID,
+		"amount":     link.Amount,
+		"single_use": link.SingleUse,
+		"expires_at": link.ExpiresAt,
+	})
+	return l.paymentLinkView(link, token)
+}
+
+// ResolvePaymentLink turns a token into the link it names and the transfer it asks for. It fails
+// with ErrPaymentLinkNotFound for a token this service did not sign, and with
+// ErrPaymentLinkExpired or ErrPaymentLinkUsed for a link that can no longer be paid.
+func (l *WalletImpl) ResolvePaymentLink(ctx context.Context, token string) (*PaymentLinkView, error) {
+	link, err := l.payablePaymentLink(token)
+	if err != nil {
+		return nil, err
+	}
+	return l.paymentLinkView(link, token)
+}
+
+// PaymentLinkQR returns a PNG QR code of the EMV payload of a payable link, scale pixels per
+// module.
+func (l *WalletImpl) PaymentLinkQR(ctx context.Context, token string, scale int) ([]byte, error) {
+	view, err := l.ResolvePaymentLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	code, err := qrcode.Encode([]byte(view.EMV))
+	if err != nil {
+		return nil, err
+	}
+	return code.PNG(scale)
+}
+
+// PayPaymentLink settles a link with a transfer from fromWalletID. amount is what the payer
+// chose; it may be zero for a link with a fixed amount and must match that amount otherwise.
+// The use is claimed before the transfer so a single-use link is paid at most once, and handed
+// back when the transfer fails. A payment that needs approval or that risk screening would send
+// to review is rejected, as neither can settle the link afterwards.
+func (l *WalletImpl) PayPaymentLink(ctx context.Context, token, fromWalletID string, amount float64) (*PaymentLinkPayment, error) {
+	link, err := l.payablePaymentLink(token)
+	if err != nil {
+		return nil, err
+	}
+	amount = common.RoundToNDecimals(amount, 4)
+	if link.Amount != nil {
+		if amount != 0 && amount != *link.Amount {
+			return nil, fmt.Errorf("%w: the link asks for %.4f", ErrInvalidPaymentLink, *link.Amount)
+		}
+		amount = *link.Amount
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidPaymentLink)
+	}
+	if fromWalletID == link.WalletID {
+		return nil, fmt.Errorf("%w: a wallet cannot pay its own link", ErrInvalidPaymentLink)
+	}
+	if l.RequiresApproval(amount) {
+		return nil, ErrPaymentLinkRequiresApproval
+	}
+	if err := l.ScreenTransferParties(ctx, fromWalletID, link.WalletID); err != nil {
+		return nil, err
+	}
+	if err := l.screenWithoutReview(ctx, fromWalletID, link.WalletID, amount, ErrPaymentLinkNeedsReview); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := l.dao.ClaimPaymentLink(link.ID, now); err != nil {
+		if errors.Is(err, dao.ErrPaymentLinkNotFound) {
+			return nil, ErrPaymentLinkUsed
+		}
+		return nil, fmt.Errorf("payment link failed: %w", err)
+	}
+	txID, fee, err := l.transfer(ctx, fromWalletID, link.WalletID, amount, false)
+	if err != nil {
+		if rerr := l.dao.ReleasePaymentLink(link.ID, time.Now()); rerr != nil {
+			l.logger.WithError(rerr).Errorf("Failed to release payment link %s after its transfer failed", link.ID)
+		}
+		return nil, err
+	}
+	if err := l.dao.SetPaymentLinkTransaction(link.ID, txID); err != nil {
+		l.logger.WithError(err).Errorf("Failed to record transaction %s on payment link %s", txID, link.ID)
+	}
+
+	payment := &PaymentLinkPayment{
+		LinkID:        link.ID,
+		FromWalletID:  fromWalletID,
+		ToWalletID:    link.WalletID,
+		Amount:        amount,
+		TransactionID: txID,
+		Fee:           fee,
+	}
+	l.recordAudit(ctx, common.AuditActionLinkPaid, common.AuditEntityLink, link.ID, map[string]any{
+		"from_wallet_id": fromWalletID,
+		"amount":         amount,
+		"transaction_id": txID,
+	})
+	l.notify(ctx, common.EventPaymentLinkPaid, link.ID, []string{link.WalletID}, payment)
+	return payment, nil
+}
+
+func (l *WalletImpl) linkSigner() (*paylink.Signer, error) {
+	if l.links.Secret == "" {
+		return nil, ErrPaymentLinksDisabled
+	}
+	return paylink.NewSigner(l.links.Secret), nil
+}
+
+// payablePaymentLink fetches the link a token names, checking that it can still be paid.
+func (l *WalletImpl) payablePaymentLink(token string) (*dao.PaymentLink, error) {
+	signer, err := l.linkSigner()
+	if err != nil {
+		return nil, err
+	}
+	linkID, err := signer.Verify(token)
+	if err != nil {
+		return nil, ErrPaymentLinkNotFound
+	}
+	link, err := l.dao.GetPaymentLink(linkID)
+	if err != nil {
+		if errors.Is(err, dao.ErrPaymentLinkNotFound) {
+			return nil, ErrPaymentLinkNotFound
+		}
+		return nil, err
+	}
+	if !time.Now().Before(link.ExpiresAt) {
+		return nil, ErrPaymentLinkExpired
+	}
+	if link.SingleUse && link.UseCount > 0 {
+		return nil, ErrPaymentLinkUsed
+	}
+	return link, nil
+}
+
+func (l *WalletImpl) paymentLinkView(link *dao.PaymentLink, token string) (*PaymentLinkView, error) {
+	owner, err := l.dao.GetUserByWalletID(link.WalletID)
+	if err != nil {
+		return nil, fmt.Errorf("payment link failed: %w", err)
+	}
+	emv := paylink.EMVPayload{
+		GUID:         l.links.GUID,
+		Token:        token,
+		SingleUse:    link.SingleUse,
+		Currency:     l.links.Currency,
+		Amount:       link.Amount,
+		Country:      l.links.Country,
+		MerchantName: owner.Name,
+		MerchantCity: l.links.City,
+		Reference:    link.Reference,
+	}
+	return &PaymentLinkView{PaymentLink: *link, Token: token, PayeeName: owner.Name, EMV: emv.String()}, nil
+}
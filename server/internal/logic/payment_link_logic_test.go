@@ -0,0 +1,204 @@
+package logic_test
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/dao/mocks"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/notify"
+	"github.com/julkhong/walletapp/server/internal/paylink"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	linkSecret = "link-secret"
+	linkID     = "70000000-0000-0000-0000-000000000001"
+)
+
+var linkConfig = logic.PaymentLinkConfig{Secret: linkSecret, GUID: "com.walletapp", Currency: "840", Country: "US", City: "Online"}
+
+func linkToken() string {
+	token, _ := paylink.NewSigner(linkSecret).Sign(linkID)
+	return token
+}
+
+func payableLink(amount *float64, singleUse bool) *dao.PaymentLink {
+	return &dao.PaymentLink{ID: linkID, WalletID: "wallet-payee", Amount: amount, Reference: "INV-42",
+		SingleUse: singleUse, ExpiresAt: time.Now().Add(time.Hour)}
+}
+
+func TestCreatePaymentLink(t *testing.T) {
+	ctx := context.TODO()
+	amount := 12.5
+
+	t.Run("signs a token and builds the EMV payload", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		impl.SetPaymentLinkConfig(linkConfig)
+		expectActiveWallets(mockDao, "wallet-payee")
+		mockDao.On("CreatePaymentLink", mock.MatchedBy(func(link *dao.PaymentLink) bool {
+			return *link.Amount == 12.5 && link.SingleUse && link.ExpiresAt.Sub(link.CreatedAt) == 30*24*time.Hour
+		})).Return(nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-payee").Return(&dao.User{Name: "Alice"}, nil).Once()
+
+		view, err := impl.CreatePaymentLink(ctx, logic.PaymentLinkInput{WalletID: "wallet-payee", Amount: &amount, Reference: "INV-42", SingleUse: true})
+		assert.NoError(t, err)
+		linkID, err := paylink.NewSigner(linkSecret).Verify(view.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, view.ID, linkID)
+		assert.Equal(t, "Alice", view.PayeeName)
+		assert.True(t, strings.HasPrefix(view.EMV, "000201010212"), view.EMV)
+		assert.Contains(t, view.EMV, view.Token)
+		assert.Contains(t, view.EMV, "540412.5")
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("disabled without a secret", func(t *testing.T) {
+		impl, _ := setupLogicTest()
+		_, err := impl.CreatePaymentLink(ctx, logic.PaymentLinkInput{WalletID: "wallet-payee"})
+		assert.ErrorIs(t, err, logic.ErrPaymentLinksDisabled)
+	})
+
+	t.Run("invalid links", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		impl.SetPaymentLinkConfig(linkConfig)
+		zero, past := 0.0, time.Now().Add(-time.Minute)
+		for name, bad := range map[string]logic.PaymentLinkInput{
+			"zero amount":        {WalletID: "wallet-payee", Amount: &zero},
+			"expiry in past":     {WalletID: "wallet-payee", ExpiresAt: &past},
+			"reference too long": {WalletID: "wallet-payee", Reference: strings.Repeat("x", 26)},
+		} {
+			_, err := impl.CreatePaymentLink(ctx, bad)
+			assert.ErrorIs(t, err, logic.ErrInvalidPaymentLink, name)
+		}
+		mockDao.AssertNotCalled(t, "CreatePaymentLink", mock.Anything)
+	})
+}
+
+func TestResolvePaymentLink(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("returns the transfer and a QR code", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		impl.SetPaymentLinkConfig(linkConfig)
+		mockDao.On("GetPaymentLink", linkID).Return(payableLink(nil, false), nil)
+		mockDao.On("GetUserByWalletID", "wallet-payee").Return(&dao.User{Name: "Alice"}, nil)
+
+		view, err := impl.ResolvePaymentLink(ctx, linkToken())
+		assert.NoError(t, err)
+		assert.Equal(t, "wallet-payee", view.WalletID)
+		assert.NotContains(t, view.EMV, "5404")
+
+		out, err := impl.PaymentLinkQR(ctx, linkToken(), 2)
+		assert.NoError(t, err)
+		_, err = png.Decode(bytes.NewReader(out))
+		assert.NoError(t, err)
+	})
+
+	t.Run("token signed with another secret", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		impl.SetPaymentLinkConfig(logic.PaymentLinkConfig{Secret: "other"})
+		_, err := impl.ResolvePaymentLink(ctx, linkToken())
+		assert.ErrorIs(t, err, logic.ErrPaymentLinkNotFound)
+		mockDao.AssertNotCalled(t, "GetPaymentLink", mock.Anything)
+	})
+
+	t.Run("expired or used", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		impl.SetPaymentLinkConfig(linkConfig)
+		expired := payableLink(nil, false)
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		used := payableLink(nil, true)
+		used.UseCount = 1
+		mockDao.On("GetPaymentLink", linkID).Return(expired, nil).Once()
+		mockDao.On("GetPaymentLink", linkID).Return(used, nil).Once()
+
+		_, err := impl.ResolvePaymentLink(ctx, linkToken())
+		assert.ErrorIs(t, err, logic.ErrPaymentLinkExpired)
+		_, err = impl.ResolvePaymentLink(ctx, linkToken())
+		assert.ErrorIs(t, err, logic.ErrPaymentLinkUsed)
+	})
+}
+
+func TestPayPaymentLink(t *testing.T) {
+	ctx := context.TODO()
+	amount := 25.0
+
+	expectTransfer := func(mockDao *mocks.WalletDaoInterface, payerBalance float64) {
+		expectActiveWallets(mockDao, "wallet-payer", "wallet-payee")
+		mockDao.On("GetBalance", "wallet-payer").Return(payerBalance, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-payer").Return(fullKycUser, nil).Once()
+	}
+
+	t.Run("pays the fixed amount and tells the payee", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		impl.SetPaymentLinkConfig(linkConfig)
+		notifier := setupNotifier(impl)
+		mockDao.On("GetPaymentLink", linkID).Return(payableLink(&amount, true), nil).Once()
+		mockDao.On("ClaimPaymentLink", linkID, mock.Anything).Return(nil).Once()
+		expectTransfer(mockDao, 100)
+		mockDao.On("GetBalance", "wallet-payee").Return(0.0, nil).Once()
+		mockDao.On("PostTransfers", mock.Anything).Return(nil).Once()
+		mockDao.On("SetPaymentLinkTransaction", linkID, mock.Anything).Return(nil).Once()
+		notifier.On("Notify", mock.Anything, mock.MatchedBy(func(ev notify.Event) bool {
+			return ev.Type == common.EventPaymentLinkPaid && ev.EntityID == linkID &&
+				assert.ObjectsAreEqual([]string{"wallet-payee"}, ev.WalletIDs)
+		})).Return(nil).Once()
+
+		payment, err := impl.PayPaymentLink(ctx, linkToken(), "wallet-payer", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 25.0, payment.Amount)
+		assert.NotEmpty(t, payment.TransactionID)
+		mockDao.AssertExpectations(t)
+		notifier.AssertExpectations(t)
+	})
+
+	t.Run("failed transfer releases the link", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		impl.SetPaymentLinkConfig(linkConfig)
+		mockDao.On("GetPaymentLink", linkID).Return(payableLink(nil, true), nil).Once()
+		mockDao.On("ClaimPaymentLink", linkID, mock.Anything).Return(nil).Once()
+		expectTransfer(mockDao, 10)
+		mockDao.On("ReleasePaymentLink", linkID, mock.Anything).Return(nil).Once()
+
+		_, err := impl.PayPaymentLink(ctx, linkToken(), "wallet-payer", 25)
+		assert.ErrorIs(t, err, logic.ErrInsufficientBalance)
+		mockDao.AssertExpectations(t)
+		mockDao.AssertNotCalled(t, "SetPaymentLinkTransaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("claimed by someone else first", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		impl.SetPaymentLinkConfig(linkConfig)
+		expectActiveWallets(mockDao, "wallet-payer", "wallet-payee")
+		mockDao.On("GetPaymentLink", linkID).Return(payableLink(&amount, true), nil).Once()
+		mockDao.On("ClaimPaymentLink", linkID, mock.Anything).Return(dao.ErrPaymentLinkNotFound).Once()
+
+		_, err := impl.PayPaymentLink(ctx, linkToken(), "wallet-payer", 0)
+		assert.ErrorIs(t, err, logic.ErrPaymentLinkUsed)
+		mockDao.AssertNotCalled(t, "PostTransfers", mock.Anything)
+	})
+
+	t.Run("invalid payments", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		impl.SetPaymentLinkConfig(linkConfig)
+		mockDao.On("GetPaymentLink", linkID).Return(payableLink(&amount, false), nil).Once()
+		mockDao.On("GetPaymentLink", linkID).Return(payableLink(nil, false), nil).Once()
+		mockDao.On("GetPaymentLink", linkID).Return(payableLink(&amount, false), nil).Once()
+
+		_, err := impl.PayPaymentLink(ctx, linkToken(), "wallet-payer", 30)
+		assert.ErrorIs(t, err, logic.ErrInvalidPaymentLink, "amount differs from the link's")
+		_, err = impl.PayPaymentLink(ctx, linkToken(), "wallet-payer", 0)
+		assert.ErrorIs(t, err, logic.ErrInvalidPaymentLink, "payer chooses no amount")
+		_, err = impl.PayPaymentLink(ctx, linkToken(), "wallet-payee", 0)
+		assert.ErrorIs(t, err, logic.ErrInvalidPaymentLink, "payee pays its own link")
+		mockDao.AssertNotCalled(t, "ClaimPaymentLink", mock.Anything, mock.Anything)
+	})
+}
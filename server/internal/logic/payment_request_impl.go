@@ -0,0 +1,284 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+)
+
+var (
+	ErrPaymentRequestNotFound         = errors.New("payment request not found")
+	ErrPaymentRequestStatusConflict   = errors.New("payment request is no longer pending")
+	ErrPaymentRequestExpired          = errors.New("payment request has expired")
+	ErrInvalidPaymentRequest          = errors.New("invalid payment request")
+	ErrPaymentRequestRequiresApproval = errors.New("amount requires approval and cannot be paid from a request")
+	ErrPaymentRequestNeedsReview      = errors.New("payment was flagged for review")
+)
+
+const (
+	// defaultPaymentRequestExpiry applies when no expiry is configured.
+	defaultPaymentRequestExpiry = 7 * 24 * time.Hour
+	// maxPaymentRequestNote bounds the note shown to the payer.
+	maxPaymentRequestNote = 280
+	// maxPaymentRequestList bounds the requests one listing returns.
+	maxPaymentRequestList = 100
+	// maxPaymentRequestExpiryBatch bounds the requests one expiry pass expires.
+	maxPaymentRequestExpiryBatch = 500
+	// paymentRequestExpiryActor is recorded as the actor of expiries.
+	paymentRequestExpiryActor = "payment-request-expiry"
+)
+
+var paymentRequestStatuses = []string{
+	common.PaymentRequestStatusPending,
+	common.PaymentRequestStatusAccepted,
+	common.PaymentRequestStatusDeclined,
+	common.PaymentRequestStatusCancelled,
+	common.PaymentRequestStatusExpired,
+}
+
+// PaymentRequestConfig is how long a payment request stays open when it names no expiry.
+type PaymentRequestConfig struct {
+	Expiry time.Duration
+}
+
+func (l *WalletImpl) SetPaymentRequestConfig(cfg PaymentRequestConfig) {
+	l.requests = cfg
+}
+
+// PaymentRequestInput asks the payer wallet to pay Amount to the requester wallet. ExpiresAt
+// defaults to the configured expiry from now.
+type PaymentRequestInput struct {
+	RequesterWalletID string
+	PayerWalletID     string
+	Amount            float64
+	Note              string
+	ExpiresAt         *time.Time
+	CreatedBy         string
+}
+
+// CreatePaymentRequest records a pending request and tells the payer about it. Nothing moves
+// until the payer accepts.
+func (l *WalletImpl) CreatePaymentRequest(ctx context.Context, in PaymentRequestInput) (*dao.PaymentRequest, error) {
+	amount := common.RoundToNDecimals(in.Amount, 4)
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidPaymentRequest)
+	}
+	if in.RequesterWalletID == in.PayerWalletID {
+		return nil, fmt.Errorf("%w: requester and payer wallets must differ", ErrInvalidPaymentRequest)
+	}
+	if len(in.Note) > maxPaymentRequestNote {
+		return nil, fmt.Errorf("%w: note is longer than %d characters", ErrInvalidPaymentRequest, maxPaymentRequestNote)
+	}
+	now := time.Now()
+	expiry := l.requests.Expiry
+	if expiry <= 0 {
+		expiry = defaultPaymentRequestExpiry
+	}
+	expiresAt := now.Add(expiry)
+	if in.ExpiresAt != nil {
+		if !in.ExpiresAt.After(now) {
+			return nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidPaymentRequest)
+		}
+		expiresAt = *in.ExpiresAt
+	}
+	if err := l.ensureWalletActive(in.RequesterWalletID); err != nil {
+		return nil, err
+	}
+	if err := l.ensureWalletActive(in.PayerWalletID); err != nil {
+		return nil, err
+	}
+
+	req := &dao.PaymentRequest{
+		ID:                uuid.NewString(),
+		RequesterWalletID: in.RequesterWalletID,
+		PayerWalletID:     in.PayerWalletID,
+		Amount:            amount,
+		Note:              in.Note,
+		Status:            common.PaymentRequestStatusPending,
+		ExpiresAt:         expiresAt,
+		CreatedBy:         in.CreatedBy,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := l.dao.CreatePaymentRequest(req); err != nil {
+		return nil, fmt.Errorf("payment request failed: %w", err)
+	}
+
+	l.recordAudit(ctx, common.AuditActionRequestCreated, common.AuditEntityRequest, req.ID, map[string]any{
+		"requester_wallet_id": req.RequesterWalletID,
+		"payer_wallet_id":     req.PayerWalletID,
+		"amount":              req.Amount,
+		"expires_at":          req.ExpiresAt,
+	})
+	l.notify(ctx, common.EventPaymentRequestCreated, req.ID, []string{req.PayerWalletID}, req)
+	return req, nil
+}
+
+func (l *WalletImpl) GetPaymentRequest(ctx context.Context, requestID string) (*dao.PaymentRequest, error) {
+	req, err := l.dao.GetPaymentRequest(requestID)
+	if errors.Is(err, dao.ErrPaymentRequestNotFound) {
+		return nil, ErrPaymentRequestNotFound
+	}
+	return req, err
+}
+
+// ListPaymentRequests returns the latest requests a wallet was asked to pay, or with incoming
+// false the ones it sent, optionally in one status.
+func (l *WalletImpl) ListPaymentRequests(ctx context.Context, walletID string, incoming bool, status string) ([]dao.PaymentRequest, error) {
+	if status != "" && !slices.Contains(paymentRequestStatuses, status) {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidPaymentRequest, status)
+	}
+	return l.dao.ListPaymentRequests(walletID, incoming, status, maxPaymentRequestList)
+}
+
+// AcceptPaymentRequest pays a pending request with a transfer from the payer to the requester.
+// The request is claimed before the transfer so it is paid at most once, and handed back to
+// pending when the transfer fails. A payment that needs approval or that risk screening would
+// send to review is rejected, as neither can accept the request afterwards.
+func (l *WalletImpl) AcceptPaymentRequest(ctx context.Context, requestID, actor string) (*dao.PaymentRequest, error) {
+	req, err := l.pendingPaymentRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if l.RequiresApproval(req.Amount) {
+		return nil, ErrPaymentRequestRequiresApproval
+	}
+	if err := l.ScreenTransferParties(ctx, req.PayerWalletID, req.RequesterWalletID); err != nil {
+		return nil, err
+	}
+	if err := l.screenWithoutReview(ctx, req.PayerWalletID, req.RequesterWalletID, req.Amount, ErrPaymentRequestNeedsReview); err != nil {
+		return nil, err
+	}
+
+	if err := l.respondToPaymentRequest(req, common.PaymentRequestStatusAccepted, actor); err != nil {
+		return nil, err
+	}
+	txID, _, err := l.transfer(ctx, req.PayerWalletID, req.RequesterWalletID, req.Amount, false)
+	if err != nil {
+		req.Status, req.RespondedBy, req.RespondedAt = common.PaymentRequestStatusPending, nil, nil
+		if rerr := l.dao.UpdatePaymentRequest(req, common.PaymentRequestStatusAccepted); rerr != nil {
+			l.logger.WithError(rerr).Errorf("Failed to reopen payment request %s after its transfer failed", req.ID)
+		}
+		return nil, err
+	}
+	req.TransactionID = &txID
+	if err := l.dao.UpdatePaymentRequest(req, common.PaymentRequestStatusAccepted); err != nil {
+		l.logger.WithError(err).Errorf("Failed to record transaction %s on payment request %s", txID, req.ID)
+	}
+
+	l.announcePaymentRequest(ctx, req)
+	return req, nil
+}
+
+// DeclinePaymentRequest is the payer turning a pending request down.
+func (l *WalletImpl) DeclinePaymentRequest(ctx context.Context, requestID, actor string) (*dao.PaymentRequest, error) {
+	return l.closePaymentRequest(ctx, requestID, common.PaymentRequestStatusDeclined, actor)
+}
+
+// CancelPaymentRequest is the requester withdrawing a pending request.
+func (l *WalletImpl) CancelPaymentRequest(ctx context.Context, requestID, actor string) (*dao.PaymentRequest, error) {
+	return l.closePaymentRequest(ctx, requestID, common.PaymentRequestStatusCancelled, actor)
+}
+
+// ExpirePaymentRequests expires pending requests past their expiry and tells both wallets.
+func (l *WalletImpl) ExpirePaymentRequests(ctx context.Context) (int, error) {
+	requests, err := l.dao.ListExpiredPaymentRequests(time.Now(), maxPaymentRequestExpiryBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for i := range requests {
+		req := &requests[i]
+		if err := l.respondToPaymentRequest(req, common.PaymentRequestStatusExpired, paymentRequestExpiryActor); err != nil {
+			if !errors.Is(err, ErrPaymentRequestStatusConflict) {
+				l.logger.WithError(err).Errorf("Failed to expire payment request %s", req.ID)
+			}
+			continue
+		}
+		l.announcePaymentRequest(ctx, req)
+		expired++
+	}
+	if expired > 0 {
+		l.logger.Infof("Expired %d payment requests", expired)
+	}
+	return expired, nil
+}
+
+func (l *WalletImpl) closePaymentRequest(ctx context.Context, requestID, to, actor string) (*dao.PaymentRequest, error) {
+	req, err := l.pendingPaymentRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.respondToPaymentRequest(req, to, actor); err != nil {
+		return nil, err
+	}
+	l.announcePaymentRequest(ctx, req)
+	return req, nil
+}
+
+// pendingPaymentRequest fetches a request that can still be answered. A pending request past its
+// expiry that the expiry job has not reached yet is expired on the spot.
+func (l *WalletImpl) pendingPaymentRequest(ctx context.Context, requestID string) (*dao.PaymentRequest, error) {
+	req, err := l.GetPaymentRequest(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status != common.PaymentRequestStatusPending {
+		return nil, ErrPaymentRequestStatusConflict
+	}
+	if !time.Now().Before(req.ExpiresAt) {
+		if err := l.respondToPaymentRequest(req, common.PaymentRequestStatusExpired, paymentRequestExpiryActor); err == nil {
+			l.announcePaymentRequest(ctx, req)
+		}
+		return nil, ErrPaymentRequestExpired
+	}
+	return req, nil
+}
+
+// respondToPaymentRequest moves a pending request to its answer. It fails with
+// ErrPaymentRequestStatusConflict when another caller answered it first.
+func (l *WalletImpl) respondToPaymentRequest(req *dao.PaymentRequest, to, actor string) error {
+	now := time.Now()
+	req.Status, req.RespondedBy, req.RespondedAt, req.UpdatedAt = to, &actor, &now, now
+	if err := l.dao.UpdatePaymentRequest(req, common.PaymentRequestStatusPending); err != nil {
+		if errors.Is(err, dao.ErrPaymentRequestNotFound) {
+			return ErrPaymentRequestStatusConflict
+		}
+		return fmt.Errorf("payment request failed: %w", err)
+	}
+	return nil
+}
+
+// announcePaymentRequest audits an answered request and tells the other side: the requester
+// when the payer answered, the payer when the requester cancelled and both on expiry.
+func (l *WalletImpl) announcePaymentRequest(ctx context.Context, req *dao.PaymentRequest) {
+	l.recordAudit(ctx, common.AuditActionRequestStatus, common.AuditEntityRequest, req.ID, map[string]any{
+		"status":         req.Status,
+		"transaction_id": req.TransactionID,
+		"responded_by":   req.RespondedBy,
+	})
+
+	var eventType string
+	walletIDs := []string{req.RequesterWalletID}
+	switch req.Status {
+	case common.PaymentRequestStatusAccepted:
+		eventType = common.EventPaymentRequestAccepted
+	case common.PaymentRequestStatusDeclined:
+		eventType = common.EventPaymentRequestDeclined
+	case common.PaymentRequestStatusCancelled:
+		eventType, walletIDs = common.EventPaymentRequestCancelled, []string{req.PayerWalletID}
+	case common.PaymentRequestStatusExpired:
+		eventType, walletIDs = common.EventPaymentRequestExpired, []string{req.RequesterWalletID, req.PayerWalletID}
+	default:
+		return
+	}
+	l.notify(ctx, eventType, req.ID, walletIDs, req)
+}
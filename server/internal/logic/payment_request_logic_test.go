@@ -0,0 +1,213 @@
+package logic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/notify"
+	notifyMocks "github.com/julkhong/walletapp/server/internal/notify/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupNotifier(impl *logic.WalletImpl) *notifyMocks.Notifier {
+	notifier := new(notifyMocks.Notifier)
+	impl.SetNotifier(notifier)
+	return notifier
+}
+
+// notified matches an event of eventType about request-1 sent to walletIDs.
+func notified(eventType string, walletIDs ...string) any {
+	return mock.MatchedBy(func(ev notify.Event) bool {
+		return ev.Type == eventType && ev.EntityID == "request-1" && assert.ObjectsAreEqual(walletIDs, ev.WalletIDs)
+	})
+}
+
+func pendingRequest() *dao.PaymentRequest {
+	return &dao.PaymentRequest{ID: "request-1", RequesterWalletID: "wallet-requester", PayerWalletID: "wallet-payer",
+		Amount: 25, Status: common.PaymentRequestStatusPending, ExpiresAt: time.Now().Add(time.Hour)}
+}
+
+func TestCreatePaymentRequest(t *testing.T) {
+	ctx := context.TODO()
+	in := logic.PaymentRequestInput{RequesterWalletID: "wallet-requester", PayerWalletID: "wallet-payer", Amount: 25, Note: "dinner"}
+
+	t.Run("tells the payer", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		impl.SetPaymentRequestConfig(logic.PaymentRequestConfig{Expiry: 48 * time.Hour})
+		notifier := setupNotifier(impl)
+		expectActiveWallets(mockDao, "wallet-requester", "wallet-payer")
+		mockDao.On("CreatePaymentRequest", mock.MatchedBy(func(req *dao.PaymentRequest) bool {
+			return req.Status == common.PaymentRequestStatusPending && req.Amount == 25 && req.Note == "dinner" &&
+				req.ExpiresAt.Sub(req.CreatedAt) == 48*time.Hour
+		})).Return(nil).Once()
+		notifier.On("Notify", mock.Anything, mock.MatchedBy(func(ev notify.Event) bool {
+			return ev.Type == common.EventPaymentRequestCreated && assert.ObjectsAreEqual([]string{"wallet-payer"}, ev.WalletIDs)
+		})).Return(nil).Once()
+
+		req, err := impl.CreatePaymentRequest(ctx, in)
+		assert.NoError(t, err)
+		assert.Equal(t, "wallet-payer", req.PayerWalletID)
+		mockDao.AssertExpectations(t)
+		notifier.AssertExpectations(t)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		past := time.Now().Add(-time.Minute)
+		for name, bad := range map[string]logic.PaymentRequestInput{
+			"same wallet":      {RequesterWalletID: "wallet-payer", PayerWalletID: "wallet-payer", Amount: 25},
+			"no amount":        {RequesterWalletID: "wallet-requester", PayerWalletID: "wallet-payer"},
+			"expiry in past":   {RequesterWalletID: "wallet-requester", PayerWalletID: "wallet-payer", Amount: 25, ExpiresAt: &past},
+			"note is too long": {RequesterWalletID: "wallet-requester", PayerWalletID: "wallet-payer", Amount: 25, Note: string(make([]byte, 281))},
+		} {
+			_, err := impl.CreatePaymentRequest(ctx, bad)
+			assert.ErrorIs(t, err, logic.ErrInvalidPaymentRequest, name)
+		}
+		mockDao.AssertNotCalled(t, "CreatePaymentRequest", mock.Anything)
+	})
+}
+
+func TestAcceptPaymentRequest(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("pays the requester", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		notifier := setupNotifier(impl)
+		expectActiveWallets(mockDao, "wallet-requester", "wallet-payer")
+		mockDao.On("GetPaymentRequest", "request-1").Return(pendingRequest(), nil).Once()
+		mockDao.On("UpdatePaymentRequest", mock.MatchedBy(func(req *dao.PaymentRequest) bool {
+			return req.Status == common.PaymentRequestStatusAccepted && *req.RespondedBy == "user-1" && req.TransactionID == nil
+		}), common.PaymentRequestStatusPending).Return(nil).Once()
+		mockDao.On("GetBalance", "wallet-payer").Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-payer").Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", "wallet-requester").Return(0.0, nil).Once()
+		mockDao.On("PostTransfers", mock.Anything).Return(nil).Once()
+		mockDao.On("UpdatePaymentRequest", mock.MatchedBy(func(req *dao.PaymentRequest) bool {
+			return req.TransactionID != nil
+		}), common.PaymentRequestStatusAccepted).Return(nil).Once()
+		notifier.On("Notify", mock.Anything, notified(common.EventPaymentRequestAccepted, "wallet-requester")).Return(nil).Once()
+
+		req, err := impl.AcceptPaymentRequest(ctx, "request-1", "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, common.PaymentRequestStatusAccepted, req.Status)
+		assert.NotNil(t, req.TransactionID)
+		mockDao.AssertExpectations(t)
+		notifier.AssertExpectations(t)
+	})
+
+	t.Run("failed transfer reopens the request", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		notifier := setupNotifier(impl)
+		expectActiveWallets(mockDao, "wallet-requester", "wallet-payer")
+		mockDao.On("GetPaymentRequest", "request-1").Return(pendingRequest(), nil).Once()
+		mockDao.On("UpdatePaymentRequest", mock.Anything, common.PaymentRequestStatusPending).Return(nil).Once()
+		mockDao.On("GetBalance", "wallet-payer").Return(10.0, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-payer").Return(fullKycUser, nil).Once()
+		mockDao.On("UpdatePaymentRequest", mock.MatchedBy(func(req *dao.PaymentRequest) bool {
+			return req.Status == common.PaymentRequestStatusPending && req.RespondedBy == nil
+		}), common.PaymentRequestStatusAccepted).Return(nil).Once()
+
+		_, err := impl.AcceptPaymentRequest(ctx, "request-1", "user-1")
+		assert.ErrorIs(t, err, logic.ErrInsufficientBalance)
+		mockDao.AssertExpectations(t)
+		notifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+	})
+
+	t.Run("answered by someone else first", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectActiveWallets(mockDao, "wallet-requester", "wallet-payer")
+		mockDao.On("GetPaymentRequest", "request-1").Return(pendingRequest(), nil).Once()
+		mockDao.On("UpdatePaymentRequest", mock.Anything, common.PaymentRequestStatusPending).
+			Return(dao.ErrPaymentRequestNotFound).Once()
+
+		_, err := impl.AcceptPaymentRequest(ctx, "request-1", "user-1")
+		assert.ErrorIs(t, err, logic.ErrPaymentRequestStatusConflict)
+		mockDao.AssertNotCalled(t, "PostTransfers", mock.Anything)
+	})
+
+	t.Run("past its expiry", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		notifier := setupNotifier(impl)
+		req := pendingRequest()
+		req.ExpiresAt = time.Now().Add(-time.Minute)
+		mockDao.On("GetPaymentRequest", "request-1").Return(req, nil).Once()
+		mockDao.On("UpdatePaymentRequest", mock.MatchedBy(func(req *dao.PaymentRequest) bool {
+			return req.Status == common.PaymentRequestStatusExpired
+		}), common.PaymentRequestStatusPending).Return(nil).Once()
+		notifier.On("Notify", mock.Anything, notified(common.EventPaymentRequestExpired, "wallet-requester", "wallet-payer")).
+			Return(nil).Once()
+
+		_, err := impl.AcceptPaymentRequest(ctx, "request-1", "user-1")
+		assert.ErrorIs(t, err, logic.ErrPaymentRequestExpired)
+		mockDao.AssertExpectations(t)
+		notifier.AssertExpectations(t)
+	})
+
+	t.Run("not pending", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		req := pendingRequest()
+		req.Status = common.PaymentRequestStatusDeclined
+		mockDao.On("GetPaymentRequest", "request-1").Return(req, nil).Once()
+
+		_, err := impl.AcceptPaymentRequest(ctx, "request-1", "user-1")
+		assert.ErrorIs(t, err, logic.ErrPaymentRequestStatusConflict)
+	})
+}
+
+func TestDeclineAndCancelPaymentRequest(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("decline tells the requester", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		notifier := setupNotifier(impl)
+		mockDao.On("GetPaymentRequest", "request-1").Return(pendingRequest(), nil).Once()
+		mockDao.On("UpdatePaymentRequest", mock.Anything, common.PaymentRequestStatusPending).Return(nil).Once()
+		notifier.On("Notify", mock.Anything, notified(common.EventPaymentRequestDeclined, "wallet-requester")).Return(nil).Once()
+
+		req, err := impl.DeclinePaymentRequest(ctx, "request-1", "user-2")
+		assert.NoError(t, err)
+		assert.Equal(t, common.PaymentRequestStatusDeclined, req.Status)
+		notifier.AssertExpectations(t)
+	})
+
+	t.Run("cancel tells the payer", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		notifier := setupNotifier(impl)
+		mockDao.On("GetPaymentRequest", "request-1").Return(pendingRequest(), nil).Once()
+		mockDao.On("UpdatePaymentRequest", mock.Anything, common.PaymentRequestStatusPending).Return(nil).Once()
+		notifier.On("Notify", mock.Anything, notified(common.EventPaymentRequestCancelled, "wallet-payer")).
+			Return(assert.AnError).Once()
+
+		// a notification that cannot be sent does not fail the cancellation
+		req, err := impl.CancelPaymentRequest(ctx, "request-1", "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, common.PaymentRequestStatusCancelled, req.Status)
+		notifier.AssertExpectations(t)
+	})
+}
+
+func TestExpirePaymentRequests(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	notifier := setupNotifier(impl)
+	raced := pendingRequest()
+	raced.ID = "request-2"
+	mockDao.On("ListExpiredPaymentRequests", mock.Anything, mock.Anything).
+		Return([]dao.PaymentRequest{*pendingRequest(), *raced}, nil).Once()
+	mockDao.On("UpdatePaymentRequest", mock.MatchedBy(func(req *dao.PaymentRequest) bool { return req.ID == "request-1" }),
+		common.PaymentRequestStatusPending).Return(nil).Once()
+	mockDao.On("UpdatePaymentRequest", mock.MatchedBy(func(req *dao.PaymentRequest) bool { return req.ID == "request-2" }),
+		common.PaymentRequestStatusPending).Return(dao.ErrPaymentRequestNotFound).Once()
+	notifier.On("Notify", mock.Anything, notified(common.EventPaymentRequestExpired, "wallet-requester", "wallet-payer")).
+		Return(nil).Once()
+
+	expired, err := impl.ExpirePaymentRequests(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	mockDao.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
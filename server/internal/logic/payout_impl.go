@@ -0,0 +1,277 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/payout"
+)
+
+var (
+	ErrInvalidBeneficiary      = errors.New("invalid beneficiary")
+	ErrInvalidPayoutAmount     = errors.New("bank payouts must be a positive amount in whole cents")
+	ErrPayoutNotFound          = errors.New("payout not found")
+	ErrInvalidPayoutTransition = errors.New("payout cannot move to the requested status")
+	ErrPayoutsNotConfigured    = errors.New("payout directory and debtor account are not configured")
+)
+
+// maxPayoutBatchSize bounds the payouts written to one pain.001 file.
+const maxPayoutBatchSize = 1000
+
+// payoutTransitions lists the statuses each payout status may move to.
+var payoutTransitions = map[string][]string{
+	common.PayoutStatusPending: {common.PayoutStatusSent},
+	common.PayoutStatusSent:    {common.PayoutStatusSettled, common.PayoutStatusReturned},
+	// banks can still return a payout after reporting it settled
+	common.PayoutStatusSettled: {common.PayoutStatusReturned},
+}
+
+// PayoutConfig describes the account bank payouts are paid from and where batch files go.
+type PayoutConfig struct {
+	Dir      string
+	Currency string
+	Debtor   payout.Account
+}
+
+func (l *WalletImpl) SetPayoutConfig(cfg PayoutConfig) {
+	cfg.Debtor = cfg.Debtor.Normalize()
+	l.payouts = cfg
+}
+
+// WithdrawToBank withdraws from the wallet and queues the amount to be paid out to the
+// beneficiary's bank account in the next payout batch.
+func (l *WalletImpl) WithdrawToBank(ctx context.Context, walletID string, amount float64, beneficiary payout.Account) (*dao.Payout, error) {
+	if amount <= 0 || common.RoundToNDecimals(amount, 2) != amount {
+		return nil, ErrInvalidPayoutAmount
+	}
+	beneficiary = beneficiary.Normalize()
+	if err := beneficiary.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBeneficiary, err)
+	}
+	return l.withdrawToBank(ctx, walletID, amount, beneficiary, true)
+}
+
+func (l *WalletImpl) withdrawToBank(ctx context.Context, walletID string, amount float64, beneficiary payout.Account, screen bool) (*dao.Payout, error) {
+	posted, err := l.withdraw(ctx, walletID, amount, screen, withdrawDestination{beneficiary: &beneficiary})
+	if err != nil {
+		return nil, err
+	}
+	return posted.Payout, nil
+}
+
+// newPayout is the pending payout of withdrawal txID, recorded with its debit.
+func (l *WalletImpl) newPayout(txID, walletID string, amount float64, beneficiary payout.Account) *dao.Payout {
+	now := time.Now()
+	return &dao.Payout{
+		ID:              uuid.NewString(),
+		TransactionID:   txID,
+		WalletID:        walletID,
+		Amount:          amount,
+		Currency:        l.payoutCurrency(),
+		BeneficiaryName: beneficiary.Name,
+		BeneficiaryIBAN: beneficiary.IBAN,
+		BeneficiaryBIC:  beneficiary.BIC,
+		Status:          common.PayoutStatusPending,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+func (l *WalletImpl) payoutCurrency() string {
+	if l.payouts.Currency == "" {
+		return "USD"
+	}
+	return l.payouts.Currency
+}
+
+func (l *WalletImpl) ListPayouts(ctx context.Context, status string) ([]dao.Payout, error) {
+	payouts, err := l.dao.ListPayouts(status, 0)
+	if err != nil {
+		return nil, err
+	}
+	if payouts == nil {
+		payouts = []dao.Payout{}
+	}
+	return payouts, nil
+}
+
+func (l *WalletImpl) GetPayout(ctx context.Context, payoutID string) (*dao.Payout, error) {
+	record, err := l.dao.GetPayout(payoutID)
+	if err != nil {
+		if errors.Is(err, dao.ErrPayoutNotFound) {
+			return nil, ErrPayoutNotFound
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+// UpdatePayoutStatus records what the bank reported about a payout. A return credits the
+// amount back to the wallet with a payout_return transaction.
+func (l *WalletImpl) UpdatePayoutStatus(ctx context.Context, payoutID, status, reason, operator string) (*dao.Payout, error) {
+	if operator == "" {
+		return nil, ErrApproverRequired
+	}
+
+	record, err := l.GetPayout(ctx, payoutID)
+	if err != nil {
+		return nil, err
+	}
+	if !canTransition(record.Status, status) {
+		l.logger.Warnf("Rejected payout %s status change from %s to %s", payoutID, record.Status, status)
+		return nil, ErrInvalidPayoutTransition
+	}
+
+	from := record.Status
+	if status == common.PayoutStatusReturned {
+		reversal := &dao.Transaction{
+			ID:        uuid.NewString(),
+			WalletID:  record.WalletID,
+			Type:      common.TransactionTypePayoutReturn,
+			Amount:    record.Amount,
+			CreatedAt: time.Now(),
+		}
+		err = l.dao.ReturnPayout(payoutID, from, reason, reversal)
+		if err == nil {
+			record.ReturnReason = &reason
+			record.ReversalTransactionID = &reversal.ID
+			record.UpdatedAt = reversal.CreatedAt
+		}
+	} else {
+		err = l.dao.UpdatePayoutStatus(payoutID, from, status)
+		record.UpdatedAt = time.Now()
+	}
+	if err != nil {
+		if errors.Is(err, dao.ErrPayoutNotFound) {
+			// another update won the race
+			return nil, ErrInvalidPayoutTransition
+		}
+		l.logger.WithError(err).Errorf("Failed to update payout %s", payoutID)
+		return nil, err
+	}
+	record.Status = status
+
+	l.recordAudit(ctx, common.AuditActionPayoutStatus, common.AuditEntityPayout, payoutID, map[string]any{
+		"from":     from,
+		"to":       status,
+		"reason":   reason,
+		"operator": operator,
+	})
+	return record, nil
+}
+
+func canTransition(from, to string) bool {
+	for _, next := range payoutTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// GeneratePayoutBatch writes the pending payouts to a pain.001 file in the payout directory and
+// marks them sent. It returns nil when nothing is pending. The file is written under a temporary
+// name and only renamed once the payouts are marked, so a bank upload never picks up a file for
+// payouts that were not recorded as sent.
+func (l *WalletImpl) GeneratePayoutBatch(ctx context.Context) (*dao.PayoutBatch, error) {
+	if l.payouts.Dir == "" || l.payouts.Debtor.IBAN == "" {
+		return nil, ErrPayoutsNotConfigured
+	}
+
+	pending, err := l.dao.ListPayouts(common.PayoutStatusPending, maxPayoutBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("payout batch failed: %w", err)
+	}
+
+	currency := l.payoutCurrency()
+	now := time.Now().UTC()
+	batchID := uuid.NewString()
+	batch := payout.Batch{
+		MessageID:     strings.ReplaceAll(batchID, "-", ""),
+		CreatedAt:     now,
+		ExecutionDate: now,
+		Debtor:        l.payouts.Debtor,
+		Currency:      currency,
+	}
+	var ids []string
+	for _, p := range pending {
+		if p.Currency != currency {
+			l.logger.Warnf("Payout %s is in %s, not the configured %s; left pending", p.ID, p.Currency, currency)
+			continue
+		}
+		batch.Payments = append(batch.Payments, payout.Payment{
+			EndToEndID: strings.ReplaceAll(p.ID, "-", ""),
+			Amount:     p.Amount,
+			Creditor:   payout.Account{Name: p.BeneficiaryName, IBAN: p.BeneficiaryIBAN, BIC: p.BeneficiaryBIC},
+			Remittance: "Wallet withdrawal " + p.TransactionID,
+		})
+		ids = append(ids, p.ID)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	record := &dao.PayoutBatch{
+		ID:          batchID,
+		MessageID:   batch.MessageID,
+		FileName:    fmt.Sprintf("pain001-%s-%s.xml", now.Format("20060102T150405"), batchID[:8]),
+		PayoutCount: len(ids),
+		TotalAmount: common.RoundToNDecimals(batch.ControlSum(), 4),
+		Currency:    currency,
+		CreatedAt:   now,
+	}
+	path := filepath.Join(l.payouts.Dir, record.FileName)
+	tmpPath := path + ".tmp"
+	if err := writePayoutFile(tmpPath, batch); err != nil {
+		l.logger.WithError(err).Error("Failed to write payout file")
+		return nil, fmt.Errorf("payout batch failed: %w", err)
+	}
+
+	if err := l.dao.MarkPayoutsSent(record, ids); err != nil {
+		_ = os.Remove(tmpPath)
+		l.logger.WithError(err).Error("Failed to mark payouts sent")
+		return nil, fmt.Errorf("payout batch failed: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		l.logger.WithError(err).Errorf("Payouts of batch %s are marked sent but the file is still at %s", batchID, tmpPath)
+		return nil, fmt.Errorf("payout batch failed: %w", err)
+	}
+
+	l.logger.Infof("Wrote payout batch %s with %d payouts to %s", batchID, len(ids), path)
+	l.recordAudit(ctx, common.AuditActionPayoutBatched, common.AuditEntityPayoutBatch, batchID, map[string]any{
+		"file_name":    record.FileName,
+		"payout_count": record.PayoutCount,
+		"total_amount": record.TotalAmount,
+		"currency":     currency,
+	})
+	return record, nil
+}
+
+func writePayoutFile(path string, batch payout.Batch) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return err
+	}
+	if err := payout.WritePain001(file, batch); err != nil {
+		_ = file.Close()
+		_ = os.Remove(path)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+	return nil
+}
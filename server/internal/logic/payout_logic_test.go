@@ -0,0 +1,259 @@
+package logic_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/dao/mocks"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/payout"
+	"github.com/julkhong/walletapp/server/internal/risk"
+	riskMocks "github.com/julkhong/walletapp/server/internal/risk/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testBeneficiary = payout.Account{Name: "Jane Doe", IBAN: "GB82 WEST 1234 5698 7654 32"}
+
+func TestWithdrawToBank(t *testing.T) {
+	ctx := context.TODO()
+	walletID := "wallet-1"
+
+	t.Run("debits and queues the payout", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectActiveWallets(mockDao, walletID)
+		mockDao.On("GetBalance", walletID).Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
+		var posted dao.Withdrawal
+		mockDao.On("PostWithdrawal", mock.Anything).Run(func(args mock.Arguments) {
+			posted = args.Get(0).(dao.Withdrawal)
+		}).Return(nil).Once()
+
+		record, err := impl.WithdrawToBank(ctx, walletID, 25.5, testBeneficiary)
+		assert.NoError(t, err)
+		assert.Same(t, posted.Payout, record, "the payout is recorded with the debit")
+		assert.Equal(t, common.PayoutStatusPending, record.Status)
+		assert.Equal(t, posted.Debit.ID, record.TransactionID)
+		assert.Equal(t, "GB82WEST12345698765432", record.BeneficiaryIBAN)
+		assert.Equal(t, "USD", record.Currency)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("failed posting records nothing", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectActiveWallets(mockDao, walletID)
+		mockDao.On("GetBalance", walletID).Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
+		mockDao.On("PostWithdrawal", mock.Anything).Return(errors.New("insert failed")).Once()
+
+		record, err := impl.WithdrawToBank(ctx, walletID, 25, testBeneficiary)
+		assert.Error(t, err)
+		assert.Nil(t, record)
+	})
+
+	t.Run("invalid beneficiary", func(t *testing.T) {
+		impl, _ := setupLogicTest()
+		_, err := impl.WithdrawToBank(ctx, walletID, 25, payout.Account{Name: "Jane Doe", IBAN: "GB00WEST12345698765432"})
+		assert.ErrorIs(t, err, logic.ErrInvalidBeneficiary)
+	})
+
+	t.Run("fractions of a cent", func(t *testing.T) {
+		impl, _ := setupLogicTest()
+		_, err := impl.WithdrawToBank(ctx, walletID, 25.001, testBeneficiary)
+		assert.Equal(t, logic.ErrInvalidPayoutAmount, err)
+	})
+
+	t.Run("insufficient balance creates no payout", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectActiveWallets(mockDao, walletID)
+		mockDao.On("GetBalance", walletID).Return(10.0, nil).Once()
+		mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
+
+		_, err := impl.WithdrawToBank(ctx, walletID, 25, testBeneficiary)
+		assert.Equal(t, logic.ErrInsufficientBalance, err)
+		mockDao.AssertNotCalled(t, "PostWithdrawal", mock.Anything)
+	})
+
+	t.Run("reviewed withdrawal keeps the beneficiary", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		screener := new(riskMocks.Screener)
+		impl.SetScreener(screener)
+		expectActiveWallets(mockDao, walletID)
+		mockDao.On("GetBalance", walletID).Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
+		screener.On("Screen", mock.Anything, mock.Anything).Return(risk.Result{Action: risk.ActionReview, Hits: []string{"large"}}, nil).Once()
+		var parked *dao.TransactionReview
+		mockDao.On("CreateTransactionReview", mock.Anything).Run(func(args mock.Arguments) {
+			parked = args.Get(0).(*dao.TransactionReview)
+		}).Return(nil).Once()
+
+		_, err := impl.WithdrawToBank(ctx, walletID, 25, testBeneficiary)
+		var pending *logic.PendingReviewError
+		assert.ErrorAs(t, err, &pending)
+		assert.Contains(t, *parked.Beneficiary, `"iban":"GB82WEST12345698765432"`)
+		mockDao.AssertNotCalled(t, "PostWithdrawal", mock.Anything)
+	})
+}
+
+func TestApproveReviewedBankWithdrawal(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	walletID := "wallet-1"
+	expectActiveWallets(mockDao, walletID)
+	beneficiary := `{"name":"Jane Doe","iban":"GB82WEST12345698765432"}`
+	mockDao.On("GetTransactionReview", "review-1").Return(&dao.TransactionReview{
+		ID:          "review-1",
+		Type:        common.TransactionTypeWithdraw,
+		WalletID:    walletID,
+		Amount:      20,
+		Status:      common.ReviewStatusPending,
+		Beneficiary: &beneficiary,
+	}, nil).Once()
+	mockDao.On("UpdateTransactionReviewStatus", "review-1", common.ReviewStatusPending, common.ReviewStatusApproved, "operator-1").Return(nil).Once()
+	mockDao.On("GetBalance", walletID).Return(100.0, nil).Once()
+	mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
+	mockDao.On("PostWithdrawal", mock.MatchedBy(func(w dao.Withdrawal) bool {
+		return w.Payout != nil && w.Payout.BeneficiaryName == "Jane Doe" && w.Payout.Amount == 20
+	})).Return(nil).Once()
+
+	_, err := impl.ApproveTransactionReview(context.TODO(), "review-1", "operator-1")
+	assert.NoError(t, err)
+	mockDao.AssertExpectations(t)
+}
+
+func TestUpdatePayoutStatus(t *testing.T) {
+	ctx := context.TODO()
+	sent := func() *dao.Payout {
+		return &dao.Payout{ID: "payout-1", WalletID: "wallet-1", TransactionID: "tx-1", Amount: 25, Status: common.PayoutStatusSent}
+	}
+
+	t.Run("settled", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetPayout", "payout-1").Return(sent(), nil).Once()
+		mockDao.On("UpdatePayoutStatus", "payout-1", common.PayoutStatusSent, common.PayoutStatusSettled).Return(nil).Once()
+
+		record, err := impl.UpdatePayoutStatus(ctx, "payout-1", common.PayoutStatusSettled, "", "operator-1")
+		assert.NoError(t, err)
+		assert.Equal(t, common.PayoutStatusSettled, record.Status)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("returned credits the wallet back", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetPayout", "payout-1").Return(sent(), nil).Once()
+		var reversal *dao.Transaction
+		mockDao.On("ReturnPayout", "payout-1", common.PayoutStatusSent, "AC04", mock.Anything).Run(func(args mock.Arguments) {
+			reversal = args.Get(3).(*dao.Transaction)
+		}).Return(nil).Once()
+
+		record, err := impl.UpdatePayoutStatus(ctx, "payout-1", common.PayoutStatusReturned, "AC04", "operator-1")
+		assert.NoError(t, err)
+		assert.Equal(t, common.PayoutStatusReturned, record.Status)
+		assert.Equal(t, common.TransactionTypePayoutReturn, reversal.Type)
+		assert.Equal(t, 25.0, reversal.Amount)
+		assert.Equal(t, "wallet-1", reversal.WalletID)
+		assert.Equal(t, reversal.ID, *record.ReversalTransactionID)
+	})
+
+	t.Run("pending payout cannot settle", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		pending := sent()
+		pending.Status = common.PayoutStatusPending
+		mockDao.On("GetPayout", "payout-1").Return(pending, nil).Once()
+
+		_, err := impl.UpdatePayoutStatus(ctx, "payout-1", common.PayoutStatusSettled, "", "operator-1")
+		assert.Equal(t, logic.ErrInvalidPayoutTransition, err)
+	})
+
+	t.Run("returned twice", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetPayout", "payout-1").Return(sent(), nil).Once()
+		mockDao.On("ReturnPayout", "payout-1", common.PayoutStatusSent, "", mock.Anything).Return(dao.ErrPayoutNotFound).Once()
+
+		_, err := impl.UpdatePayoutStatus(ctx, "payout-1", common.PayoutStatusReturned, "", "operator-1")
+		assert.Equal(t, logic.ErrInvalidPayoutTransition, err)
+	})
+
+	t.Run("unknown payout", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetPayout", "missing").Return(nil, dao.ErrPayoutNotFound).Once()
+
+		_, err := impl.UpdatePayoutStatus(ctx, "missing", common.PayoutStatusSettled, "", "operator-1")
+		assert.Equal(t, logic.ErrPayoutNotFound, err)
+	})
+
+	t.Run("operator required", func(t *testing.T) {
+		impl, _ := setupLogicTest()
+		_, err := impl.UpdatePayoutStatus(ctx, "payout-1", common.PayoutStatusSettled, "", "")
+		assert.Equal(t, logic.ErrApproverRequired, err)
+	})
+}
+
+func TestGeneratePayoutBatch(t *testing.T) {
+	ctx := context.TODO()
+	pending := []dao.Payout{
+		{ID: "11111111-1111-1111-1111-111111111111", TransactionID: "tx-1", Amount: 25, Currency: "USD", BeneficiaryName: "Jane Doe", BeneficiaryIBAN: "GB82WEST12345698765432", Status: common.PayoutStatusPending},
+		{ID: "22222222-2222-2222-2222-222222222222", TransactionID: "tx-2", Amount: 10.5, Currency: "USD", BeneficiaryName: "John Roe", BeneficiaryIBAN: "DE89370400440532013000", Status: common.PayoutStatusPending},
+		{ID: "33333333-3333-3333-3333-333333333333", TransactionID: "tx-3", Amount: 7, Currency: "EUR", BeneficiaryName: "Old Payout", BeneficiaryIBAN: "DE89370400440532013000", Status: common.PayoutStatusPending},
+	}
+	newImpl := func(dir string) (*logic.WalletImpl, *mocks.WalletDaoInterface) {
+		impl, mockDao := setupLogicTest()
+		impl.SetPayoutConfig(logic.PayoutConfig{
+			Dir:      dir,
+			Currency: "USD",
+			Debtor:   payout.Account{Name: "Wallet App", IBAN: "DE89 3704 0044 0532 0130 00"},
+		})
+		return impl, mockDao
+	}
+
+	t.Run("writes the file and marks payouts sent", func(t *testing.T) {
+		dir := t.TempDir()
+		impl, mockDao := newImpl(dir)
+		mockDao.On("ListPayouts", common.PayoutStatusPending, mock.Anything).Return(pending, nil).Once()
+		mockDao.On("MarkPayoutsSent", mock.Anything, []string{pending[0].ID, pending[1].ID}).Return(nil).Once()
+
+		batch, err := impl.GeneratePayoutBatch(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, batch.PayoutCount)
+		assert.Equal(t, 35.5, batch.TotalAmount)
+		assert.Len(t, batch.MessageID, 32)
+
+		data, err := os.ReadFile(filepath.Join(dir, batch.FileName))
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "<EndToEndId>11111111111111111111111111111111</EndToEndId>")
+		assert.Contains(t, string(data), "<IBAN>DE89370400440532013000</IBAN>")
+		assert.NotContains(t, string(data), "Old Payout")
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("nothing pending", func(t *testing.T) {
+		impl, mockDao := newImpl(t.TempDir())
+		mockDao.On("ListPayouts", common.PayoutStatusPending, mock.Anything).Return([]dao.Payout{}, nil).Once()
+
+		batch, err := impl.GeneratePayoutBatch(ctx)
+		assert.NoError(t, err)
+		assert.Nil(t, batch)
+	})
+
+	t.Run("failed marking leaves no file", func(t *testing.T) {
+		dir := t.TempDir()
+		impl, mockDao := newImpl(dir)
+		mockDao.On("ListPayouts", common.PayoutStatusPending, mock.Anything).Return(pending[:1], nil).Once()
+		mockDao.On("MarkPayoutsSent", mock.Anything, mock.Anything).Return(errors.New("db down")).Once()
+
+		_, err := impl.GeneratePayoutBatch(ctx)
+		assert.Error(t, err)
+		entries, _ := os.ReadDir(dir)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		impl, _ := setupLogicTest()
+		_, err := impl.GeneratePayoutBatch(ctx)
+		assert.Equal(t, logic.ErrPayoutsNotConfigured, err)
+	})
+}
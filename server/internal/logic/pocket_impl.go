@@ -0,0 +1,290 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+)
+
+var (
+	ErrPocketNameRequired  = errors.New("pocket name is required")
+	ErrInvalidPocketGoal   = errors.New("pocket goal needs a positive amount and a date that is not in the past")
+	ErrInvalidPocketParent = errors.New("pockets can only be opened on a wallet that is not a pocket")
+	ErrTooManyPockets      = errors.New("wallet already has the maximum number of pockets")
+	ErrNotPocket           = errors.New("wallet is not a pocket")
+	ErrInvalidPocketMove   = errors.New("money only moves between a wallet and its own pockets")
+	ErrPocketWallet        = errors.New("pockets only move money to and from their parent wallet")
+)
+
+// maxPockets bounds the pockets one wallet can have.
+const maxPockets = 20
+
+// PocketGoal is how far a pocket's balance is towards its target amount.
+type PocketGoal struct {
+	TargetAmount    float64 `json:"target_amount"`
+	TargetDate      *string `json:"target_date,omitempty"`
+	Saved           float64 `json:"saved"`
+	Remaining       float64 `json:"remaining"`
+	ProgressPercent float64 `json:"progress_percent"`
+	Reached         bool    `json:"reached"`
+	// DaysLeft counts the days until the target date, zero once it has passed.
+	DaysLeft *int `json:"days_left,omitempty"`
+}
+
+// Pocket is a pocket wallet with the progress of its goal.
+type Pocket struct {
+	dao.Wallet
+	Goal *PocketGoal `json:"goal,omitempty"`
+}
+
+// WalletView is a wallet with its pockets. TotalBalance adds the pockets to the wallet's own
+// balance. For a pocket, Goal is its progress and Pockets is empty.
+type WalletView struct {
+	dao.Wallet
+	Goal           *PocketGoal `json:"goal,omitempty"`
+	Pockets        []Pocket    `json:"pockets"`
+	PocketsBalance float64     `json:"pockets_balance"`
+	TotalBalance   float64     `json:"total_balance"`
+}
+
+// CreatePocket opens a named pocket under a wallet, optionally with a goal. The pocket belongs to
+// the wallet's owner and starts out empty.
+func (l *WalletImpl) CreatePocket(ctx context.Context, parentWalletID, name string, goalAmount *float64, goalDate *time.Time) (*dao.Wallet, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrPocketNameRequired
+	}
+	goalAmount, goalDate, err := validatePocketGoal(goalAmount, goalDate)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := l.dao.GetWalletByID(parentWalletID)
+	if err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, fmt.Errorf("create pocket failed: %w", err)
+	}
+	if parent.Type == common.WalletTypePocket {
+		return nil, ErrInvalidPocketParent
+	}
+	if parent.Status == common.WalletStatusFrozen {
+		return nil, ErrWalletFrozen
+	}
+
+	pockets, err := l.dao.ListPockets(parentWalletID)
+	if err != nil {
+		return nil, fmt.Errorf("create pocket failed: %w", err)
+	}
+	if len(pockets) >= maxPockets {
+		return nil, ErrTooManyPockets
+	}
+
+	pocket := &dao.Wallet{
+		ID:             uuid.NewString(),
+		UserID:         parent.UserID,
+		Balance:        0,
+		Status:         common.WalletStatusActive,
+		Type:           common.WalletTypePocket,
+		ParentWalletID: &parent.ID,
+		Name:           &name,
+		GoalAmount:     goalAmount,
+		GoalDate:       goalDate,
+		CreatedAt:      time.Now(),
+	}
+	if err := l.dao.CreateWallet(pocket); err != nil {
+		l.logger.WithError(err).Error("Failed to create pocket")
+		return nil, fmt.Errorf("create pocket failed: %w", err)
+	}
+
+	l.recordAudit(ctx, common.AuditActionPocketCreated, common.AuditEntityWallet, pocket.ID, map[string]any{
+		"parent_wallet_id": parent.ID,
+		"name":             name,
+		"goal_amount":      goalAmount,
+		"goal_date":        goalDate,
+	})
+	return pocket, nil
+}
+
+// SetPocketGoal sets the goal of a pocket, or clears it when goalAmount is nil.
+func (l *WalletImpl) SetPocketGoal(ctx context.Context, pocketID string, goalAmount *float64, goalDate *time.Time) (*Pocket, error) {
+	goalAmount, goalDate, err := validatePocketGoal(goalAmount, goalDate)
+	if err != nil {
+		return nil, err
+	}
+
+	pocket, err := l.dao.GetWalletByID(pocketID)
+	if err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, fmt.Errorf("set pocket goal failed: %w", err)
+	}
+	if pocket.Type != common.WalletTypePocket {
+		return nil, ErrNotPocket
+	}
+
+	if err := l.dao.UpdatePocketGoal(pocketID, goalAmount, goalDate); err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, fmt.Errorf("set pocket goal failed: %w", err)
+	}
+
+	l.recordAudit(ctx, common.AuditActionPocketGoalSet, common.AuditEntityWallet, pocketID, map[string]any{
+		"goal_amount": goalAmount,
+		"goal_date":   goalDate,
+	})
+	pocket.GoalAmount, pocket.GoalDate = goalAmount, goalDate
+	return &Pocket{Wallet: *pocket, Goal: goalProgress(pocket, time.Now())}, nil
+}
+
+// MovePocketFunds moves amount between walletID and one of its pockets, or between two of its
+// pockets, in one DB transaction. Moves are free and skip KYC limits and screening since the
+// money stays with the owner; neither side may be frozen and the sender may not go below zero.
+// It returns the wallet with its pockets after the move.
+func (l *WalletImpl) MovePocketFunds(ctx context.Context, walletID, fromWalletID, toWalletID string, amount float64) (*WalletView, error) {
+	amount = common.RoundToNDecimals(amount, 4)
+	l.logger.Infof("Moving %.4f from %s to %s under wallet %s", amount, fromWalletID, toWalletID, walletID)
+	if fromWalletID == toWalletID || amount <= 0 {
+		return nil, ErrInvalidPocketMove
+	}
+
+	var from *dao.Wallet
+	for _, id := range []string{fromWalletID, toWalletID} {
+		wallet, err := l.dao.GetWalletByID(id)
+		if err != nil {
+			if errors.Is(err, dao.ErrWalletNotFound) {
+				return nil, ErrWalletNotFound
+			}
+			return nil, fmt.Errorf("pocket move failed: %w", err)
+		}
+		if !inPocketFamily(wallet, walletID) {
+			return nil, ErrInvalidPocketMove
+		}
+		if wallet.Status == common.WalletStatusFrozen {
+			return nil, ErrWalletFrozen
+		}
+		if id == fromWalletID {
+			from = wallet
+		}
+	}
+	if from.Balance < amount {
+		l.logger.Warnf("Insufficient balance for pocket move: current=%.4f, requested=%.4f", from.Balance, amount)
+		return nil, ErrInsufficientBalance
+	}
+
+	leg := dao.TransferLeg{
+		FromWalletID: fromWalletID,
+		ToWalletID:   toWalletID,
+		Amount:       amount,
+		DebitID:      uuid.NewString(),
+		CreditID:     uuid.NewString(),
+		Type:         common.TransactionTypePocketMove,
+	}
+	if err := l.dao.PostTransfers([]dao.TransferLeg{leg}); err != nil {
+		if errors.Is(err, dao.ErrInsufficientFunds) {
+			return nil, ErrInsufficientBalance
+		}
+		return nil, fmt.Errorf("pocket move failed: %w", err)
+	}
+
+	l.recordAudit(ctx, common.AuditActionPocketMove, common.AuditEntityWallet, walletID, map[string]any{
+		"from_wallet_id": fromWalletID,
+		"to_wallet_id":   toWalletID,
+		"amount":         amount,
+	})
+	return l.GetWalletView(ctx, walletID)
+}
+
+// GetWalletView returns a wallet with its pockets and their goals.
+func (l *WalletImpl) GetWalletView(ctx context.Context, walletID string) (*WalletView, error) {
+	wallet, err := l.dao.GetWalletByID(walletID)
+	if err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	view := &WalletView{Wallet: *wallet, Pockets: []Pocket{}, TotalBalance: wallet.Balance}
+	if wallet.Type == common.WalletTypePocket {
+		view.Goal = goalProgress(wallet, now)
+		return view, nil
+	}
+
+	pockets, err := l.dao.ListPockets(walletID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range pockets {
+		view.Pockets = append(view.Pockets, Pocket{Wallet: pockets[i], Goal: goalProgress(&pockets[i], now)})
+		view.PocketsBalance += pockets[i].Balance
+	}
+	view.PocketsBalance = common.RoundToNDecimals(view.PocketsBalance, 4)
+	view.TotalBalance = common.RoundToNDecimals(wallet.Balance+view.PocketsBalance, 4)
+	return view, nil
+}
+
+// inPocketFamily reports whether wallet is walletID itself or one of its pockets.
+func inPocketFamily(wallet *dao.Wallet, walletID string) bool {
+	if wallet.ID == walletID {
+		return wallet.Type != common.WalletTypePocket
+	}
+	return wallet.Type == common.WalletTypePocket && wallet.ParentWalletID != nil && *wallet.ParentWalletID == walletID
+}
+
+// validatePocketGoal checks a goal and returns it normalised: the amount at four decimals and
+// the date as a UTC day. A date needs an amount.
+func validatePocketGoal(amount *float64, date *time.Time) (*float64, *time.Time, error) {
+	if amount == nil {
+		if date != nil {
+			return nil, nil, ErrInvalidPocketGoal
+		}
+		return nil, nil, nil
+	}
+	rounded := common.RoundToNDecimals(*amount, 4)
+	if rounded <= 0 {
+		return nil, nil, ErrInvalidPocketGoal
+	}
+	if date == nil {
+		return &rounded, nil, nil
+	}
+	day := startOfDay(*date)
+	if day.Before(startOfDay(time.Now())) {
+		return nil, nil, ErrInvalidPocketGoal
+	}
+	return &rounded, &day, nil
+}
+
+// goalProgress returns the progress of a pocket's goal, or nil when it has none.
+func goalProgress(pocket *dao.Wallet, now time.Time) *PocketGoal {
+	if pocket.GoalAmount == nil || *pocket.GoalAmount <= 0 {
+		return nil
+	}
+	target := *pocket.GoalAmount
+	saved := math.Max(pocket.Balance, 0)
+	goal := &PocketGoal{
+		TargetAmount:    target,
+		Saved:           saved,
+		Remaining:       common.RoundToNDecimals(math.Max(target-saved, 0), 4),
+		ProgressPercent: math.Min(common.RoundToNDecimals(saved/target*100, 2), 100),
+		Reached:         saved >= target,
+	}
+	if pocket.GoalDate != nil {
+		date := pocket.GoalDate.UTC().Format(time.DateOnly)
+		days := int(math.Max(0, startOfDay(*pocket.GoalDate).Sub(startOfDay(now)).Hours()/24))
+		goal.TargetDate, goal.DaysLeft = &date, &days
+	}
+	return goal
+}
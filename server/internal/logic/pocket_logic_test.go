@@ -0,0 +1,162 @@
+package logic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func pocketWallet(id string, balance float64, goal *float64) dao.Wallet {
+	parent, name := "wallet-p", "rent"
+	return dao.Wallet{ID: id, UserID: "user-1", Balance: balance, Status: common.WalletStatusActive,
+		Type: common.WalletTypePocket, ParentWalletID: &parent, Name: &name, GoalAmount: goal}
+}
+
+func TestCreatePocket(t *testing.T) {
+	ctx := context.TODO()
+	parent := &dao.Wallet{ID: "wallet-p", UserID: "user-1", Status: common.WalletStatusActive, Type: common.WalletTypeStandard}
+
+	t.Run("pocket with a goal", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		goal, date := 1200.0, time.Now().AddDate(0, 6, 0)
+		mockDao.On("GetWalletByID", "wallet-p").Return(parent, nil).Once()
+		mockDao.On("ListPockets", "wallet-p").Return([]dao.Wallet{}, nil).Once()
+		mockDao.On("CreateWallet", mock.MatchedBy(func(w *dao.Wallet) bool {
+			return w.Type == common.WalletTypePocket && *w.ParentWalletID == "wallet-p" && w.UserID == "user-1" &&
+				*w.Name == "holiday" && *w.GoalAmount == 1200 && w.GoalDate.Hour() == 0
+		})).Return(nil).Once()
+
+		pocket, err := impl.CreatePocket(ctx, "wallet-p", " holiday ", &goal, &date)
+		assert.NoError(t, err)
+		assert.Equal(t, "holiday", *pocket.Name)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("invalid goal", func(t *testing.T) {
+		impl, _ := setupLogicTest()
+		goal, past := 100.0, time.Now().AddDate(0, 0, -2)
+		zero := 0.0
+
+		_, err := impl.CreatePocket(ctx, "wallet-p", "rent", &goal, &past)
+		assert.ErrorIs(t, err, logic.ErrInvalidPocketGoal)
+		_, err = impl.CreatePocket(ctx, "wallet-p", "rent", &zero, nil)
+		assert.ErrorIs(t, err, logic.ErrInvalidPocketGoal)
+		_, err = impl.CreatePocket(ctx, "wallet-p", "rent", nil, &past)
+		assert.ErrorIs(t, err, logic.ErrInvalidPocketGoal)
+		_, err = impl.CreatePocket(ctx, "wallet-p", "  ", nil, nil)
+		assert.ErrorIs(t, err, logic.ErrPocketNameRequired)
+	})
+
+	t.Run("no pockets in pockets", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		pocket := pocketWallet("pocket-1", 0, nil)
+		mockDao.On("GetWalletByID", "pocket-1").Return(&pocket, nil).Once()
+
+		_, err := impl.CreatePocket(ctx, "pocket-1", "rent", nil, nil)
+		assert.ErrorIs(t, err, logic.ErrInvalidPocketParent)
+	})
+
+	t.Run("pocket limit", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetWalletByID", "wallet-p").Return(parent, nil).Once()
+		mockDao.On("ListPockets", "wallet-p").Return(make([]dao.Wallet, 20), nil).Once()
+
+		_, err := impl.CreatePocket(ctx, "wallet-p", "rent", nil, nil)
+		assert.ErrorIs(t, err, logic.ErrTooManyPockets)
+		mockDao.AssertNotCalled(t, "CreateWallet", mock.Anything)
+	})
+}
+
+func TestMovePocketFunds(t *testing.T) {
+	ctx := context.TODO()
+	parent := &dao.Wallet{ID: "wallet-p", Balance: 500, Status: common.WalletStatusActive, Type: common.WalletTypeStandard}
+	pocket := pocketWallet("pocket-1", 100, nil)
+
+	t.Run("parent to pocket", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetWalletByID", "wallet-p").Return(parent, nil)
+		mockDao.On("GetWalletByID", "pocket-1").Return(&pocket, nil).Once()
+		mockDao.On("PostTransfers", mock.MatchedBy(func(legs []dao.TransferLeg) bool {
+			return len(legs) == 1 && legs[0].FromWalletID == "wallet-p" && legs[0].ToWalletID == "pocket-1" &&
+				legs[0].Amount == 200 && legs[0].Type == common.TransactionTypePocketMove
+		})).Return(nil).Once()
+		mockDao.On("ListPockets", "wallet-p").Return([]dao.Wallet{pocket}, nil).Once()
+
+		view, err := impl.MovePocketFunds(ctx, "wallet-p", "wallet-p", "pocket-1", 200)
+		assert.NoError(t, err)
+		assert.Len(t, view.Pockets, 1)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("sender may not go below zero", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetWalletByID", "pocket-1").Return(&pocket, nil).Once()
+		mockDao.On("GetWalletByID", "wallet-p").Return(parent, nil).Once()
+
+		_, err := impl.MovePocketFunds(ctx, "wallet-p", "pocket-1", "wallet-p", 100.01)
+		assert.ErrorIs(t, err, logic.ErrInsufficientBalance)
+		mockDao.AssertNotCalled(t, "PostTransfers", mock.Anything)
+	})
+
+	t.Run("pocket of another wallet", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		other := pocketWallet("pocket-2", 100, nil)
+		otherParent := "wallet-x"
+		other.ParentWalletID = &otherParent
+		mockDao.On("GetWalletByID", "wallet-p").Return(parent, nil).Once()
+		mockDao.On("GetWalletByID", "pocket-2").Return(&other, nil).Once()
+
+		_, err := impl.MovePocketFunds(ctx, "wallet-p", "wallet-p", "pocket-2", 10)
+		assert.ErrorIs(t, err, logic.ErrInvalidPocketMove)
+	})
+}
+
+func TestGetWalletView(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	goal, reached := 1000.0, 50.0
+	rent := pocketWallet("pocket-1", 250, &goal)
+	date := time.Now().UTC().AddDate(0, 0, 10)
+	rent.GoalDate = &date
+	mockDao.On("GetWalletByID", "wallet-p").
+		Return(&dao.Wallet{ID: "wallet-p", Balance: 500, Type: common.WalletTypeStandard}, nil).Once()
+	mockDao.On("ListPockets", "wallet-p").Return([]dao.Wallet{rent, pocketWallet("pocket-2", 80.5, &reached), pocketWallet("pocket-3", 0, nil)}, nil).Once()
+
+	view, err := impl.GetWalletView(context.TODO(), "wallet-p")
+	assert.NoError(t, err)
+	assert.Equal(t, 330.5, view.PocketsBalance)
+	assert.Equal(t, 830.5, view.TotalBalance)
+	assert.Len(t, view.Pockets, 3)
+
+	assert.Equal(t, 25.0, view.Pockets[0].Goal.ProgressPercent)
+	assert.Equal(t, 750.0, view.Pockets[0].Goal.Remaining)
+	assert.Equal(t, 10, *view.Pockets[0].Goal.DaysLeft)
+	assert.False(t, view.Pockets[0].Goal.Reached)
+	assert.Equal(t, 100.0, view.Pockets[1].Goal.ProgressPercent)
+	assert.True(t, view.Pockets[1].Goal.Reached)
+	assert.Nil(t, view.Pockets[2].Goal)
+}
+
+func TestPocketsCannotMoveMoneyOut(t *testing.T) {
+	// handlers match the error by value, so it must not be wrapped
+	ctx := context.TODO()
+	impl, mockDao := setupLogicTest()
+	pocket := pocketWallet("pocket-1", 100, nil)
+	mockDao.On("GetBalance", "pocket-1").Return(100.0, nil)
+	mockDao.On("GetWalletByID", "pocket-1").Return(&pocket, nil)
+	expectActiveWallets(mockDao, "wallet-1")
+	mockDao.On("GetBalance", "wallet-1").Return(100.0, nil)
+	mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil)
+
+	assert.Equal(t, logic.ErrPocketWallet, impl.Withdraw(ctx, "pocket-1", 10))
+	assert.Equal(t, logic.ErrPocketWallet, impl.Deposit(ctx, "pocket-1", 10))
+	_, err := impl.Transfer(ctx, "pocket-1", "wallet-1", 10)
+	assert.Equal(t, logic.ErrPocketWallet, err)
+	_, err = impl.Transfer(ctx, "wallet-1", "pocket-1", 10)
+	assert.Equal(t, logic.ErrPocketWallet, err)
+}
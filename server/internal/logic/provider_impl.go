@@ -0,0 +1,390 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/provider"
+)
+
+var (
+	ErrProviderNotFound          = errors.New("payment provider not found")
+	ErrProviderOperationNotFound = errors.New("provider operation not found")
+	ErrProviderPayoutFailed      = errors.New("payment provider failed the payout, the withdrawal was reversed")
+)
+
+// maxProviderSyncBatch bounds the operations one status sync queries.
+const maxProviderSyncBatch = 500
+
+// providerInitiateGrace is how long a payout may stay unknown to its provider before the status
+// sync treats it as never received. It covers payouts whose initiate call is still in flight.
+const providerInitiateGrace = 10 * time.Minute
+
+// ProviderTarget names the provider a withdrawal is paid out through and the provider-specific
+// destination, such as a card token or a mobile money number.
+type ProviderTarget struct {
+	Name        string `json:"name"`
+	Destination string `json:"destination"`
+}
+
+// SetProviders plugs in the payment providers withdrawals and deposits can go through.
+func (l *WalletImpl) SetProviders(registry *provider.Registry) {
+	l.providers = registry
+}
+
+// WithdrawViaProvider is a two-phase withdrawal through an external provider. The wallet is
+// debited before the payout is initiated; a payout the provider rejects or later fails is
+// credited back with a withdraw_reversal transaction. The returned operation is pending until
+// the provider reports the outcome.
+func (l *WalletImpl) WithdrawViaProvider(ctx context.Context, walletID string, amount float64, target ProviderTarget) (*dao.ProviderOperation, error) {
+	if _, ok := l.providers.Get(target.Name); !ok {
+		return nil, ErrProviderNotFound
+	}
+	return l.withdrawViaProvider(ctx, walletID, amount, target, true)
+}
+
+func (l *WalletImpl) withdrawViaProvider(ctx context.Context, walletID string, amount float64, target ProviderTarget, screen bool) (*dao.ProviderOperation, error) {
+	p, ok := l.providers.Get(target.Name)
+	if !ok {
+		return nil, ErrProviderNotFound
+	}
+
+	posted, err := l.withdraw(ctx, walletID, amount, screen, withdrawDestination{provider: &target})
+	if err != nil {
+		return nil, err
+	}
+	op := posted.Operation
+
+	res, err := p.InitiatePayout(ctx, provider.PayoutRequest{
+		Reference:   op.ID,
+		WalletID:    walletID,
+		Amount:      op.Amount,
+		Currency:    l.payoutCurrency(),
+		Destination: target.Destination,
+	})
+	if err != nil {
+		if !errors.Is(err, provider.ErrRejected) {
+			// the provider may still have accepted the payout; the status sync settles it
+			l.logger.WithError(err).Warnf("Provider %s did not confirm payout %s", p.Name(), op.ID)
+			return op, nil
+		}
+		res = provider.Result{Status: provider.StatusFailed, FailureReason: err.Error()}
+	}
+
+	if err := l.applyProviderResult(ctx, op, res); err != nil {
+		return nil, err
+	}
+	if op.Status == common.ProviderStatusFailed {
+		if op.FailureReason != nil {
+			return nil, fmt.Errorf("%w: %s", ErrProviderPayoutFailed, *op.FailureReason)
+		}
+		return nil, ErrProviderPayoutFailed
+	}
+	return op, nil
+}
+
+// newProviderPayout is the provider operation paying out withdrawal txID, recorded with its debit.
+func newProviderPayout(txID, walletID string, amount float64, target ProviderTarget) *dao.ProviderOperation {
+	now := time.Now()
+	return &dao.ProviderOperation{
+		ID:            uuid.NewString(),
+		Provider:      target.Name,
+		Kind:          common.ProviderOperationPayout,
+		WalletID:      walletID,
+		Amount:        amount,
+		Destination:   target.Destination,
+		Status:        common.ProviderStatusInitiated,
+		TransactionID: &txID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func (l *WalletImpl) GetProviderOperation(ctx context.Context, opID string) (*dao.ProviderOperation, error) {
+	op, err := l.dao.GetProviderOperation(opID)
+	if err != nil {
+		if errors.Is(err, dao.ErrProviderOperationNotFound) {
+			return nil, ErrProviderOperationNotFound
+		}
+		return nil, err
+	}
+	return op, nil
+}
+
+// HandleProviderCallback applies the events of a provider callback. Payout events settle or
+// reverse the payout; payin events credit the wallet once per provider reference. Events that
+// were already applied are ignored, so providers can safely retry callbacks.
+func (l *WalletImpl) HandleProviderCallback(ctx context.Context, name string, header http.Header, body []byte) (int, error) {
+	p, ok := l.providers.Get(name)
+	if !ok {
+		return 0, ErrProviderNotFound
+	}
+
+	events, err := p.HandleCallback(ctx, header, body)
+	if err != nil {
+		l.logger.WithError(err).Warnf("Rejected %s callback", name)
+		return 0, err
+	}
+
+	for _, ev := range events {
+		switch ev.Kind {
+		case provider.EventPayout:
+			err = l.applyPayoutEvent(ctx, name, ev)
+		case provider.EventPayin:
+			err = l.creditPayin(ctx, name, ev)
+		default:
+			l.logger.Warnf("Ignoring %s callback event of kind %q", name, ev.Kind)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(events), nil
+}
+
+func (l *WalletImpl) applyPayoutEvent(ctx context.Context, name string, ev provider.Event) error {
+	op, err := l.dao.GetProviderOperation(ev.Reference)
+	if err != nil {
+		if errors.Is(err, dao.ErrProviderOperationNotFound) {
+			l.logger.Warnf("Ignoring %s callback for unknown payout %s", name, ev.Reference)
+			return nil
+		}
+		return err
+	}
+	if op.Provider != name || op.Kind != common.ProviderOperationPayout {
+		l.logger.Warnf("Ignoring %s callback for operation %s of %s", name, op.ID, op.Provider)
+		return nil
+	}
+	if isFinalProviderStatus(op.Status) {
+		if op.Status != ev.Status {
+			l.logger.Warnf("Provider %s reported %s for payout %s already %s", name, ev.Status, op.ID, op.Status)
+		}
+		return nil
+	}
+
+	return l.applyProviderResult(ctx, op, provider.Result{
+		ProviderReference: ev.ProviderReference,
+		Status:            ev.Status,
+		FailureReason:     ev.FailureReason,
+	})
+}
+
+// creditPayin records money a provider received for a wallet and credits it. A payin for a wallet
+// that is frozen or would exceed its KYC balance limit is recorded failed for operators to resolve
+// with the provider.
+func (l *WalletImpl) creditPayin(ctx context.Context, name string, ev provider.Event) error {
+	if ev.Status != provider.StatusSucceeded {
+		return nil
+	}
+	if _, err := uuid.Parse(ev.WalletID); err != nil {
+		l.logger.Errorf("Provider %s reported payin %s for invalid wallet %q", name, ev.ProviderReference, ev.WalletID)
+		return nil
+	}
+	if err := l.ensureWalletActive(ev.WalletID); errors.Is(err, ErrWalletNotFound) {
+		l.logger.Errorf("Provider %s reported payin %s for unknown wallet %s", name, ev.ProviderReference, ev.WalletID)
+		return nil
+	}
+
+	now := time.Now()
+	reference := ev.ProviderReference
+	op := &dao.ProviderOperation{
+		ID:                uuid.NewString(),
+		Provider:          name,
+		Kind:              common.ProviderOperationPayin,
+		WalletID:          ev.WalletID,
+		Amount:            common.RoundToNDecimals(ev.Amount, 4),
+		ProviderReference: &reference,
+		Status:            common.ProviderStatusInitiated,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := l.dao.CreateProviderOperation(op); err != nil {
+		if errors.Is(err, dao.ErrProviderOperationExists) {
+			l.logger.Infof("Ignoring repeated %s payin %s", name, reference)
+			return nil
+		}
+		return err
+	}
+
+	if reason := l.payinRejection(op); reason != "" {
+		l.logger.Warnf("Payin %s to wallet %s not credited: %s", op.ID, op.WalletID, reason)
+		if err := l.dao.UpdateProviderOperation(op.ID, op.Status, dao.ProviderOperationUpdate{
+			Status:        common.ProviderStatusFailed,
+			FailureReason: reason,
+		}); err != nil {
+			return err
+		}
+		l.recordAudit(ctx, common.AuditActionProviderStatus, common.AuditEntityProviderOp, op.ID, map[string]any{
+			"from":     op.Status,
+			"to":       common.ProviderStatusFailed,
+			"provider": name,
+			"reason":   reason,
+		})
+		return nil
+	}
+
+	deposit := &dao.Transaction{
+		ID:        uuid.NewString(),
+		WalletID:  op.WalletID,
+		Type:      common.TransactionTypeDeposit,
+		Amount:    op.Amount,
+		CreatedAt: now,
+	}
+	if err := l.dao.CompleteProviderPayin(op.ID, op.Status, deposit); err != nil {
+		l.logger.WithError(err).Errorf("Failed to credit payin %s", op.ID)
+		return err
+	}
+
+	l.recordAudit(ctx, common.AuditActionDeposit, common.AuditEntityWallet, op.WalletID, map[string]any{
+		"amount":             op.Amount,
+		"balance":            *deposit.BalanceAfter,
+		"provider":           name,
+		"provider_reference": reference,
+	})
+	return nil
+}
+
+// payinRejection returns why a payin cannot be credited, or "" when it can.
+func (l *WalletImpl) payinRejection(op *dao.ProviderOperation) string {
+	if err := l.ensureWalletActive(op.WalletID); err != nil {
+		return err.Error()
+	}
+	rule, err := l.kycRuleForWallet(op.WalletID)
+	if err != nil {
+		return err.Error()
+	}
+	if rule.MaxBalance > 0 {
+		current, err := l.dao.GetBalance(op.WalletID)
+		if err != nil {
+			return err.Error()
+		}
+		if current+op.Amount > rule.MaxBalance {
+			return ErrKycBalanceLimitExceeded.Error()
+		}
+	}
+	return ""
+}
+
+// SyncProviderOperations asks providers for the status of payouts they have not settled yet and
+// applies the answers. It returns the number of payouts that reached a final status.
+func (l *WalletImpl) SyncProviderOperations(ctx context.Context) (int, error) {
+	ops, err := l.dao.ListProviderOperations(common.ProviderOperationPayout,
+		[]string{common.ProviderStatusInitiated, common.ProviderStatusPending}, maxProviderSyncBatch)
+	if err != nil {
+		return 0, fmt.Errorf("provider sync failed: %w", err)
+	}
+
+	settled := 0
+	for i := range ops {
+		op := &ops[i]
+		p, ok := l.providers.Get(op.Provider)
+		if !ok {
+			l.logger.Warnf("Payout %s uses provider %s which is not configured", op.ID, op.Provider)
+			continue
+		}
+
+		res, err := p.QueryStatus(ctx, op.ID)
+		if err != nil {
+			if !errors.Is(err, provider.ErrUnknownReference) || time.Since(op.CreatedAt) < providerInitiateGrace {
+				l.logger.WithError(err).Warnf("Failed to query %s for payout %s", op.Provider, op.ID)
+				continue
+			}
+			res = provider.Result{Status: provider.StatusFailed, FailureReason: "payout never reached the provider"}
+		}
+
+		if err := l.applyProviderResult(ctx, op, res); err != nil {
+			return settled, err
+		}
+		if isFinalProviderStatus(op.Status) {
+			settled++
+		}
+	}
+	return settled, nil
+}
+
+// applyProviderResult moves a payout to the status the provider reported, reversing the
+// withdrawal when it failed. When another callback or sync settled the payout first, op is
+// refreshed from the stored operation instead.
+func (l *WalletImpl) applyProviderResult(ctx context.Context, op *dao.ProviderOperation, res provider.Result) error {
+	from := op.Status
+	now := time.Now()
+
+	var err error
+	switch res.Status {
+	case provider.StatusSucceeded:
+		err = l.dao.UpdateProviderOperation(op.ID, from, dao.ProviderOperationUpdate{
+			Status:            common.ProviderStatusSucceeded,
+			ProviderReference: res.ProviderReference,
+		})
+	case provider.StatusFailed:
+		reason := res.FailureReason
+		if reason == "" {
+			reason = "failed by provider"
+		}
+		reversal := &dao.Transaction{
+			ID:        uuid.NewString(),
+			WalletID:  op.WalletID,
+			Type:      common.TransactionTypeWithdrawReversal,
+			Amount:    op.Amount,
+			CreatedAt: now,
+		}
+		err = l.dao.ReverseProviderOperation(op.ID, from, reason, reversal)
+		if err == nil {
+			op.FailureReason = &reason
+			op.ReversalTransactionID = &reversal.ID
+		}
+	default:
+		if from == common.ProviderStatusPending {
+			return nil
+		}
+		err = l.dao.UpdateProviderOperation(op.ID, from, dao.ProviderOperationUpdate{
+			Status:            common.ProviderStatusPending,
+			ProviderReference: res.ProviderReference,
+		})
+	}
+	if err != nil {
+		if errors.Is(err, dao.ErrProviderOperationNotFound) {
+			latest, getErr := l.dao.GetProviderOperation(op.ID)
+			if getErr != nil {
+				return getErr
+			}
+			*op = *latest
+			return nil
+		}
+		l.logger.WithError(err).Errorf("Failed to update provider operation %s", op.ID)
+		return err
+	}
+
+	switch res.Status {
+	case provider.StatusSucceeded:
+		op.Status = common.ProviderStatusSucceeded
+	case provider.StatusFailed:
+		op.Status = common.ProviderStatusFailed
+	default:
+		op.Status = common.ProviderStatusPending
+	}
+	if res.ProviderReference != "" {
+		reference := res.ProviderReference
+		op.ProviderReference = &reference
+	}
+	op.UpdatedAt = now
+
+	l.recordAudit(ctx, common.AuditActionProviderStatus, common.AuditEntityProviderOp, op.ID, map[string]any{
+		"from":     from,
+		"to":       op.Status,
+		"provider": op.Provider,
+		"reason":   res.FailureReason,
+	})
+	return nil
+}
+
+func isFinalProviderStatus(status string) bool {
+	return status == common.ProviderStatusSucceeded || status == common.ProviderStatusFailed
+}
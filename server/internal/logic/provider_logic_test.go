@@ -0,0 +1,274 @@
+package logic_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/dao/mocks"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/provider"
+	providerMocks "github.com/julkhong/walletapp/server/internal/provider/mocks"
+	"github.com/julkhong/walletapp/server/internal/risk"
+	riskMocks "github.com/julkhong/walletapp/server/internal/risk/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupProvider(impl *logic.WalletImpl) *providerMocks.PaymentProvider {
+	p := new(providerMocks.PaymentProvider)
+	p.On("Name").Return("acme").Maybe()
+	impl.SetProviders(provider.NewRegistry(p))
+	return p
+}
+
+// expectDebit sets up a successful withdrawal of a full-KYC wallet holding 100, posted with its
+// provider operation.
+func expectDebit(mockDao *mocks.WalletDaoInterface, walletID string) {
+	expectActiveWallets(mockDao, walletID)
+	mockDao.On("GetBalance", walletID).Return(100.0, nil).Once()
+	mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
+	mockDao.On("PostWithdrawal", mock.MatchedBy(func(w dao.Withdrawal) bool {
+		return w.Operation != nil && w.Operation.Kind == common.ProviderOperationPayout &&
+			*w.Operation.TransactionID == w.Debit.ID
+	})).Return(nil).Once()
+}
+
+func TestWithdrawViaProvider(t *testing.T) {
+	ctx := context.TODO()
+	walletID := "wallet-1"
+	target := logic.ProviderTarget{Name: "acme", Destination: "card_123"}
+
+	t.Run("pending once initiated", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		p := setupProvider(impl)
+		expectDebit(mockDao, walletID)
+		p.On("InitiatePayout", mock.Anything, mock.MatchedBy(func(req provider.PayoutRequest) bool {
+			return req.Amount == 40 && req.Destination == "card_123" && req.Currency == "USD"
+		})).Return(provider.Result{ProviderReference: "po_1", Status: provider.StatusPending}, nil).Once()
+		mockDao.On("UpdateProviderOperation", mock.Anything, common.ProviderStatusInitiated,
+			dao.ProviderOperationUpdate{Status: common.ProviderStatusPending, ProviderReference: "po_1"}).Return(nil).Once()
+
+		op, err := impl.WithdrawViaProvider(ctx, walletID, 40, target)
+		assert.NoError(t, err)
+		assert.Equal(t, common.ProviderStatusPending, op.Status)
+		assert.Equal(t, "po_1", *op.ProviderReference)
+		assert.NotNil(t, op.TransactionID)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("rejected payout is reversed", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		p := setupProvider(impl)
+		expectDebit(mockDao, walletID)
+		p.On("InitiatePayout", mock.Anything, mock.Anything).Return(provider.Result{}, provider.ErrRejected).Once()
+		mockDao.On("ReverseProviderOperation", mock.Anything, common.ProviderStatusInitiated, provider.ErrRejected.Error(),
+			mock.MatchedBy(func(tx *dao.Transaction) bool {
+				return tx.Type == common.TransactionTypeWithdrawReversal && tx.Amount == 40 && tx.WalletID == walletID
+			})).Return(nil).Once()
+
+		_, err := impl.WithdrawViaProvider(ctx, walletID, 40, target)
+		assert.ErrorIs(t, err, logic.ErrProviderPayoutFailed)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("unconfirmed payout stays initiated", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		p := setupProvider(impl)
+		expectDebit(mockDao, walletID)
+		p.On("InitiatePayout", mock.Anything, mock.Anything).Return(provider.Result{}, context.DeadlineExceeded).Once()
+
+		op, err := impl.WithdrawViaProvider(ctx, walletID, 40, target)
+		assert.NoError(t, err)
+		assert.Equal(t, common.ProviderStatusInitiated, op.Status)
+		mockDao.AssertNotCalled(t, "ReverseProviderOperation", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("failed posting initiates nothing", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		p := setupProvider(impl)
+		expectActiveWallets(mockDao, walletID)
+		mockDao.On("GetBalance", walletID).Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
+		mockDao.On("PostWithdrawal", mock.Anything).Return(errors.New("insert failed")).Once()
+
+		_, err := impl.WithdrawViaProvider(ctx, walletID, 40, target)
+		assert.Error(t, err)
+		p.AssertNotCalled(t, "InitiatePayout", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown provider debits nothing", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		setupProvider(impl)
+
+		_, err := impl.WithdrawViaProvider(ctx, walletID, 40, logic.ProviderTarget{Name: "other"})
+		assert.Equal(t, logic.ErrProviderNotFound, err)
+		mockDao.AssertNotCalled(t, "GetBalance", mock.Anything)
+	})
+
+	t.Run("reviewed withdrawal keeps the provider", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		p := setupProvider(impl)
+		screener := new(riskMocks.Screener)
+		impl.SetScreener(screener)
+		expectActiveWallets(mockDao, walletID)
+		mockDao.On("GetBalance", walletID).Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
+		screener.On("Screen", mock.Anything, mock.Anything).Return(risk.Result{Action: risk.ActionReview, Hits: []string{"large"}}, nil).Once()
+		var parked *dao.TransactionReview
+		mockDao.On("CreateTransactionReview", mock.Anything).Run(func(args mock.Arguments) {
+			parked = args.Get(0).(*dao.TransactionReview)
+		}).Return(nil).Once()
+
+		_, err := impl.WithdrawViaProvider(ctx, walletID, 40, target)
+		var pending *logic.PendingReviewError
+		assert.ErrorAs(t, err, &pending)
+		assert.JSONEq(t, `{"name":"acme","destination":"card_123"}`, *parked.Provider)
+		p.AssertNotCalled(t, "InitiatePayout", mock.Anything, mock.Anything)
+	})
+
+	t.Run("fake provider end to end", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		impl.SetProviders(provider.NewRegistry(provider.NewFake(provider.FakeConfig{})))
+		expectDebit(mockDao, walletID)
+		mockDao.On("UpdateProviderOperation", mock.Anything, common.ProviderStatusInitiated,
+			mock.MatchedBy(func(u dao.ProviderOperationUpdate) bool { return u.Status == common.ProviderStatusSucceeded })).Return(nil).Once()
+
+		op, err := impl.WithdrawViaProvider(ctx, walletID, 40, logic.ProviderTarget{Name: provider.FakeName})
+		assert.NoError(t, err)
+		assert.Equal(t, common.ProviderStatusSucceeded, op.Status)
+	})
+}
+
+func TestHandleProviderCallback(t *testing.T) {
+	ctx := context.TODO()
+	walletID := "10000000-0000-0000-0000-000000000001"
+
+	t.Run("failed payout is reversed", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		p := setupProvider(impl)
+		p.On("HandleCallback", mock.Anything, mock.Anything, mock.Anything).Return([]provider.Event{{
+			Kind: provider.EventPayout, Reference: "op-1", Status: provider.StatusFailed, FailureReason: "card expired",
+		}}, nil).Once()
+		mockDao.On("GetProviderOperation", "op-1").Return(&dao.ProviderOperation{
+			ID: "op-1", Provider: "acme", Kind: common.ProviderOperationPayout, WalletID: walletID, Amount: 40, Status: common.ProviderStatusPending,
+		}, nil).Once()
+		mockDao.On("ReverseProviderOperation", "op-1", common.ProviderStatusPending, "card expired", mock.Anything).Return(nil).Once()
+
+		processed, err := impl.HandleProviderCallback(ctx, "acme", http.Header{}, []byte(`{}`))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, processed)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("settled payout ignores repeats", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		p := setupProvider(impl)
+		p.On("HandleCallback", mock.Anything, mock.Anything, mock.Anything).Return([]provider.Event{{
+			Kind: provider.EventPayout, Reference: "op-1", Status: provider.StatusFailed,
+		}}, nil).Once()
+		mockDao.On("GetProviderOperation", "op-1").Return(&dao.ProviderOperation{
+			ID: "op-1", Provider: "acme", Kind: common.ProviderOperationPayout, Status: common.ProviderStatusSucceeded,
+		}, nil).Once()
+
+		_, err := impl.HandleProviderCallback(ctx, "acme", http.Header{}, []byte(`{}`))
+		assert.NoError(t, err)
+		mockDao.AssertNotCalled(t, "ReverseProviderOperation", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("payin credits the wallet once", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		p := setupProvider(impl)
+		payin := []provider.Event{{
+			Kind: provider.EventPayin, ProviderReference: "pi_1", Status: provider.StatusSucceeded, WalletID: walletID, Amount: 12.5,
+		}}
+		p.On("HandleCallback", mock.Anything, mock.Anything, mock.Anything).Return(payin, nil).Twice()
+		expectActiveWallets(mockDao, walletID)
+		mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
+		mockDao.On("CreateProviderOperation", mock.MatchedBy(func(op *dao.ProviderOperation) bool {
+			return op.Kind == common.ProviderOperationPayin && *op.ProviderReference == "pi_1"
+		})).Return(nil).Once()
+		mockDao.On("CompleteProviderPayin", mock.Anything, common.ProviderStatusInitiated, mock.MatchedBy(func(tx *dao.Transaction) bool {
+			return tx.Type == common.TransactionTypeDeposit && tx.Amount == 12.5
+		})).Run(func(args mock.Arguments) {
+			balance := 112.5
+			args.Get(2).(*dao.Transaction).BalanceAfter = &balance
+		}).Return(nil).Once()
+
+		_, err := impl.HandleProviderCallback(ctx, "acme", http.Header{}, []byte(`{}`))
+		assert.NoError(t, err)
+
+		mockDao.On("CreateProviderOperation", mock.Anything).Return(dao.ErrProviderOperationExists).Once()
+		_, err = impl.HandleProviderCallback(ctx, "acme", http.Header{}, []byte(`{}`))
+		assert.NoError(t, err)
+		mockDao.AssertNumberOfCalls(t, "CompleteProviderPayin", 1)
+	})
+
+	t.Run("payin over the kyc limit is recorded failed", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		p := setupProvider(impl)
+		p.On("HandleCallback", mock.Anything, mock.Anything, mock.Anything).Return([]provider.Event{{
+			Kind: provider.EventPayin, ProviderReference: "pi_2", Status: provider.StatusSucceeded, WalletID: walletID, Amount: 500,
+		}}, nil).Once()
+		expectActiveWallets(mockDao, walletID)
+		mockDao.On("GetUserByWalletID", walletID).Return(&dao.User{ID: "user-1", KycLevel: common.KycLevelUnverified}, nil).Once()
+		mockDao.On("GetBalance", walletID).Return(900.0, nil).Once()
+		mockDao.On("CreateProviderOperation", mock.Anything).Return(nil).Once()
+		mockDao.On("UpdateProviderOperation", mock.Anything, common.ProviderStatusInitiated, dao.ProviderOperationUpdate{
+			Status: common.ProviderStatusFailed, FailureReason: logic.ErrKycBalanceLimitExceeded.Error(),
+		}).Return(nil).Once()
+
+		_, err := impl.HandleProviderCallback(ctx, "acme", http.Header{}, []byte(`{}`))
+		assert.NoError(t, err)
+		mockDao.AssertExpectations(t)
+		mockDao.AssertNotCalled(t, "CompleteProviderPayin", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("bad signature", func(t *testing.T) {
+		impl, _ := setupLogicTest()
+		p := setupProvider(impl)
+		p.On("HandleCallback", mock.Anything, mock.Anything, mock.Anything).Return(nil, provider.ErrInvalidSignature).Once()
+
+		_, err := impl.HandleProviderCallback(ctx, "acme", http.Header{}, []byte(`{}`))
+		assert.ErrorIs(t, err, provider.ErrInvalidSignature)
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		impl, _ := setupLogicTest()
+		setupProvider(impl)
+
+		_, err := impl.HandleProviderCallback(ctx, "other", http.Header{}, []byte(`{}`))
+		assert.Equal(t, logic.ErrProviderNotFound, err)
+	})
+}
+
+func TestSyncProviderOperations(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	p := setupProvider(impl)
+	now := time.Now()
+
+	mockDao.On("ListProviderOperations", common.ProviderOperationPayout,
+		[]string{common.ProviderStatusInitiated, common.ProviderStatusPending}, mock.Anything).Return([]dao.ProviderOperation{
+		{ID: "op-1", Provider: "acme", WalletID: "wallet-1", Amount: 10, Status: common.ProviderStatusPending, CreatedAt: now},
+		{ID: "op-2", Provider: "acme", WalletID: "wallet-1", Amount: 20, Status: common.ProviderStatusInitiated, CreatedAt: now.Add(-time.Hour)},
+		{ID: "op-3", Provider: "acme", WalletID: "wallet-1", Amount: 30, Status: common.ProviderStatusInitiated, CreatedAt: now},
+		{ID: "op-4", Provider: "gone", WalletID: "wallet-1", Amount: 40, Status: common.ProviderStatusPending, CreatedAt: now},
+	}, nil).Once()
+	p.On("QueryStatus", mock.Anything, "op-1").Return(provider.Result{Status: provider.StatusSucceeded}, nil).Once()
+	p.On("QueryStatus", mock.Anything, "op-2").Return(provider.Result{}, provider.ErrUnknownReference).Once()
+	// op-3 may still be in flight
+	p.On("QueryStatus", mock.Anything, "op-3").Return(provider.Result{}, provider.ErrUnknownReference).Once()
+	mockDao.On("UpdateProviderOperation", "op-1", common.ProviderStatusPending,
+		dao.ProviderOperationUpdate{Status: common.ProviderStatusSucceeded}).Return(nil).Once()
+	mockDao.On("ReverseProviderOperation", "op-2", common.ProviderStatusInitiated, "payout never reached the provider", mock.Anything).Return(nil).Once()
+
+	settled, err := impl.SyncProviderOperations(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, settled)
+	mockDao.AssertExpectations(t)
+	p.AssertExpectations(t)
+}
@@ -0,0 +1,225 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/payout"
+	"github.com/julkhong/walletapp/server/internal/risk"
+)
+
+var (
+	ErrTransactionDenied = errors.New("transaction denied by risk screening")
+	ErrReviewNotFound    = errors.New("pending review not found")
+)
+
+// PendingReviewError is returned when screening parks a movement for operator review
+// instead of committing it.
+type PendingReviewError struct {
+	ReviewID string
+}
+
+func (e *PendingReviewError) Error() string {
+	return "transaction pending review: " + e.ReviewID
+}
+
+// SetScreener plugs a risk screener in front of withdrawals and transfers.
+func (l *WalletImpl) SetScreener(screener risk.Screener) {
+	l.screener = screener
+}
+
+// screen runs the configured screener and turns review/deny decisions into errors. A withdrawal's
+// beneficiary or provider is stored with its review so approval can still pay it out.
+func (l *WalletImpl) screen(ctx context.Context, ev risk.Event, dest withdrawDestination) error {
+	if l.screener == nil {
+		return nil
+	}
+
+	result, err := l.screener.Screen(ctx, ev)
+	if err != nil {
+		l.logger.WithError(err).Error("Risk screening failed")
+		return fmt.Errorf("%s failed: %w", ev.Type, err)
+	}
+
+	switch result.Action {
+	case risk.ActionDeny:
+		l.logger.Warnf("%s from wallet %s denied by rules %v", ev.Type, ev.WalletID, result.Hits)
+		return ErrTransactionDenied
+	case risk.ActionReview:
+		hits, _ := json.Marshal(result.Hits)
+		review := &dao.TransactionReview{
+			ID:        uuid.NewString(),
+			Type:      ev.Type,
+			WalletID:  ev.WalletID,
+			Amount:    ev.Amount,
+			RuleHits:  string(hits),
+			Status:    common.ReviewStatusPending,
+			CreatedAt: time.Now(),
+		}
+		if ev.CounterpartyWalletID != "" {
+			counterparty := ev.CounterpartyWalletID
+			review.CounterpartyWalletID = &counterparty
+		}
+		if dest.beneficiary != nil {
+			raw, _ := json.Marshal(dest.beneficiary)
+			details := string(raw)
+			review.Beneficiary = &details
+		}
+		if dest.provider != nil {
+			raw, _ := json.Marshal(dest.provider)
+			details := string(raw)
+			review.Provider = &details
+		}
+		if err := l.dao.CreateTransactionReview(review); err != nil {
+			l.logger.WithError(err).Error("Failed to park transaction for review")
+			return fmt.Errorf("%s failed: %w", ev.Type, err)
+		}
+		l.recordAudit(ctx, common.AuditActionReviewCreated, common.AuditEntityReview, review.ID, map[string]any{
+			"type":      ev.Type,
+			"wallet_id": ev.WalletID,
+			"amount":    ev.Amount,
+			"rule_hits": result.Hits,
+		})
+		return &PendingReviewError{ReviewID: review.ID}
+	}
+	return nil
+}
+
+// screenWithoutReview screens a transfer that cannot be parked for review, such as one leg of a
+// larger payment, and returns reviewErr when screening would send it there.
+func (l *WalletImpl) screenWithoutReview(ctx context.Context, fromWalletID, toWalletID string, amount float64, reviewErr error) error {
+	if l.screener == nil {
+		return nil
+	}
+
+	result, err := l.screener.Screen(ctx, risk.Event{
+		Type:                 common.TransactionTypeTransfer,
+		WalletID:             fromWalletID,
+		CounterpartyWalletID: toWalletID,
+		Amount:               amount,
+	})
+	if err != nil {
+		return fmt.Errorf("transfer screening failed: %w", err)
+	}
+	switch result.Action {
+	case risk.ActionDeny:
+		return ErrTransactionDenied
+	case risk.ActionReview:
+		return reviewErr
+	}
+	return nil
+}
+
+func (l *WalletImpl) ListTransactionReviews(ctx context.Context, status string) ([]dao.TransactionReview, error) {
+	return l.dao.ListTransactionReviews(status)
+}
+
+// ApproveTransactionReview claims a pending review and executes the parked movement
+// without screening it again. A movement that can no longer be executed marks the review failed;
+// the review is still returned with the execution error.
+func (l *WalletImpl) ApproveTransactionReview(ctx context.Context, reviewID, reviewer string) (*dao.TransactionReview, error) {
+	review, err := l.claimTransactionReview(reviewID, reviewer, common.ReviewStatusApproved)
+	if err != nil {
+		return nil, err
+	}
+	l.recordAudit(ctx, common.AuditActionReviewDecided, common.AuditEntityReview, reviewID, map[string]any{
+		"status":   common.ReviewStatusApproved,
+		"reviewer": reviewer,
+	})
+
+	// the reviewer releases the movement but is not the one spending; reviews do not record who was
+	ctx = withInitiator(ctx, "")
+	switch review.Type {
+	case common.TransactionTypeWithdraw:
+		if review.Provider != nil {
+			var target ProviderTarget
+			if err = json.Unmarshal([]byte(*review.Provider), &target); err != nil {
+				err = fmt.Errorf("review %s has an unreadable provider: %w", reviewID, err)
+				break
+			}
+			_, err = l.withdrawViaProvider(ctx, review.WalletID, review.Amount, target, false)
+			break
+		}
+		if review.Beneficiary == nil {
+			_, err = l.withdraw(ctx, review.WalletID, review.Amount, false, withdrawDestination{})
+			break
+		}
+		var beneficiary payout.Account
+		if err = json.Unmarshal([]byte(*review.Beneficiary), &beneficiary); err != nil {
+			err = fmt.Errorf("review %s has an unreadable beneficiary: %w", reviewID, err)
+			break
+		}
+		_, err = l.withdrawToBank(ctx, review.WalletID, review.Amount, beneficiary, false)
+	case common.TransactionTypeTransfer:
+		if review.CounterpartyWalletID == nil {
+			err = fmt.Errorf("review %s has no counterparty wallet", reviewID)
+			break
+		}
+		// a best-effort batch item parked by the review is settled with the transfer
+		_, _, err = l.transferWith(ctx, review.WalletID, *review.CounterpartyWalletID, review.Amount, false, func(legs []dao.TransferLeg) error {
+			return l.dao.PostReviewedTransfer(reviewID, legs)
+		})
+	default:
+		err = fmt.Errorf("unsupported review type %q", review.Type)
+	}
+
+	if err != nil {
+		l.logger.WithError(err).Errorf("Approved review %s could not be executed", reviewID)
+		if markErr := l.dao.UpdateTransactionReviewStatus(reviewID, common.ReviewStatusApproved, common.ReviewStatusFailed, reviewer); markErr != nil {
+			l.logger.WithError(markErr).Errorf("Failed to mark review %s as failed", reviewID)
+		}
+		review.Status = common.ReviewStatusFailed
+		l.failReviewedBatchItem(reviewID, err)
+		return review, err
+	}
+	return review, nil
+}
+
+func (l *WalletImpl) RejectTransactionReview(ctx context.Context, reviewID, reviewer string) (*dao.TransactionReview, error) {
+	review, err := l.claimTransactionReview(reviewID, reviewer, common.ReviewStatusRejected)
+	if err != nil {
+		return nil, err
+	}
+	l.recordAudit(ctx, common.AuditActionReviewDecided, common.AuditEntityReview, reviewID, map[string]any{
+		"status":   common.ReviewStatusRejected,
+		"reviewer": reviewer,
+	})
+	l.failReviewedBatchItem(reviewID, ErrBatchItemRejected)
+	return review, nil
+}
+
+// claimTransactionReview moves a pending review to toStatus and returns it.
+func (l *WalletImpl) claimTransactionReview(reviewID, reviewer, toStatus string) (*dao.TransactionReview, error) {
+	review, err := l.dao.GetTransactionReview(reviewID)
+	if err != nil {
+		if errors.Is(err, dao.ErrReviewNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+
+	if err := l.dao.UpdateTransactionReviewStatus(reviewID, common.ReviewStatusPending, toStatus, reviewer); err != nil {
+		if errors.Is(err, dao.ErrReviewNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+
+	review.Status = toStatus
+	review.ReviewedBy = &reviewer
+	return review, nil
+}
+
+// failReviewedBatchItem fails the best-effort batch item parked by a review, if any.
+func (l *WalletImpl) failReviewedBatchItem(reviewID string, reason error) {
+	if err := l.dao.FailReviewedTransferBatchItem(reviewID, reason.Error()); err != nil {
+		l.logger.WithError(err).Errorf("Failed to settle the batch item of review %s", reviewID)
+	}
+}
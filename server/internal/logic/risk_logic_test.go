@@ -0,0 +1,173 @@
+package logic_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/risk"
+	riskMocks "github.com/julkhong/walletapp/server/internal/risk/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransferScreening(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	screener := new(riskMocks.Screener)
+	impl.SetScreener(screener)
+	ctx := context.TODO()
+	fromWallet := "wallet-from"
+	toWallet := "wallet-to"
+	expectActiveWallets(mockDao, fromWallet, toWallet)
+
+	expectChecks := func() {
+		mockDao.On("GetBalance", fromWallet).Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", fromWallet).Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", toWallet).Return(50.0, nil).Once()
+	}
+
+	t.Run("allowed transfer is committed", func(t *testing.T) {
+		expectChecks()
+		screener.On("Screen", mock.Anything, mock.Anything).Return(risk.Result{Action: risk.ActionAllow}, nil).Once()
+		mockDao.On("PostTransfers", mock.Anything).Return(nil).Once()
+
+		_, err := impl.Transfer(ctx, fromWallet, toWallet, 25.0)
+		assert.NoError(t, err)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("denied transfer", func(t *testing.T) {
+		expectChecks()
+		screener.On("Screen", mock.Anything, mock.Anything).
+			Return(risk.Result{Action: risk.ActionDeny, Hits: []string{"huge"}}, nil).Once()
+
+		_, err := impl.Transfer(ctx, fromWallet, toWallet, 25.0)
+		assert.ErrorIs(t, err, logic.ErrTransactionDenied)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("reviewed transfer is parked", func(t *testing.T) {
+		expectChecks()
+		screener.On("Screen", mock.Anything, risk.Event{
+			Type:                 common.TransactionTypeTransfer,
+			WalletID:             fromWallet,
+			CounterpartyWalletID: toWallet,
+			Amount:               25.0,
+		}).Return(risk.Result{Action: risk.ActionReview, Hits: []string{"large"}}, nil).Once()
+		mockDao.On("CreateTransactionReview", mock.MatchedBy(func(r *dao.TransactionReview) bool {
+			return r.Status == common.ReviewStatusPending && r.RuleHits == `["large"]` && *r.CounterpartyWalletID == toWallet
+		})).Return(nil).Once()
+
+		_, err := impl.Transfer(ctx, fromWallet, toWallet, 25.0)
+		var pending *logic.PendingReviewError
+		assert.ErrorAs(t, err, &pending)
+		assert.NotEmpty(t, pending.ReviewID)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("screening failure fails closed", func(t *testing.T) {
+		expectChecks()
+		screener.On("Screen", mock.Anything, mock.Anything).Return(risk.Result{}, errors.New("db down")).Once()
+
+		_, err := impl.Transfer(ctx, fromWallet, toWallet, 25.0)
+		assert.Error(t, err)
+		mockDao.AssertExpectations(t)
+	})
+}
+
+func TestApproveTransactionReview(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	ctx := context.TODO()
+	walletID := "wallet-1"
+	expectActiveWallets(mockDao, walletID)
+	review := &dao.TransactionReview{
+		ID:       "review-1",
+		Type:     common.TransactionTypeWithdraw,
+		WalletID: walletID,
+		Amount:   20,
+		Status:   common.ReviewStatusPending,
+	}
+
+	t.Run("approved withdraw is executed", func(t *testing.T) {
+		mockDao.On("GetTransactionReview", "review-1").Return(review, nil).Once()
+		mockDao.On("UpdateTransactionReviewStatus", "review-1", common.ReviewStatusPending, common.ReviewStatusApproved, "operator-1").
+			Return(nil).Once()
+		mockDao.On("GetBalance", walletID).Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
+		mockDao.On("PostWithdrawal", mock.Anything).Return(nil).Once()
+
+		decided, err := impl.ApproveTransactionReview(ctx, "review-1", "operator-1")
+		assert.NoError(t, err)
+		assert.Equal(t, common.ReviewStatusApproved, decided.Status)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("execution failure marks review failed", func(t *testing.T) {
+		mockDao.On("GetTransactionReview", "review-1").Return(review, nil).Once()
+		mockDao.On("UpdateTransactionReviewStatus", "review-1", common.ReviewStatusPending, common.ReviewStatusApproved, "operator-1").
+			Return(nil).Once()
+		mockDao.On("GetBalance", walletID).Return(5.0, nil).Once()
+		mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
+		mockDao.On("UpdateTransactionReviewStatus", "review-1", common.ReviewStatusApproved, common.ReviewStatusFailed, "operator-1").
+			Return(nil).Once()
+		mockDao.On("FailReviewedTransferBatchItem", "review-1", logic.ErrInsufficientBalance.Error()).Return(nil).Once()
+
+		decided, err := impl.ApproveTransactionReview(ctx, "review-1", "operator-1")
+		assert.ErrorIs(t, err, logic.ErrInsufficientBalance)
+		assert.Equal(t, common.ReviewStatusFailed, decided.Status)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("approved transfer settles a parked batch item with it", func(t *testing.T) {
+		counterparty := "wallet-2"
+		expectActiveWallets(mockDao, counterparty)
+		transfer := &dao.TransactionReview{ID: "review-1", Type: common.TransactionTypeTransfer, WalletID: walletID,
+			CounterpartyWalletID: &counterparty, Amount: 20, Status: common.ReviewStatusPending}
+		mockDao.On("GetTransactionReview", "review-1").Return(transfer, nil).Once()
+		mockDao.On("UpdateTransactionReviewStatus", "review-1", common.ReviewStatusPending, common.ReviewStatusApproved, "operator-1").
+			Return(nil).Once()
+		mockDao.On("GetBalance", walletID).Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", counterparty).Return(0.0, nil).Once()
+		mockDao.On("PostReviewedTransfer", "review-1", mock.MatchedBy(func(legs []dao.TransferLeg) bool {
+			return len(legs) == 1 && legs[0].ToWalletID == counterparty && legs[0].Amount == 20
+		})).Return(nil).Once()
+
+		_, err := impl.ApproveTransactionReview(ctx, "review-1", "operator-1")
+		assert.NoError(t, err)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("already resolved", func(t *testing.T) {
+		mockDao.On("GetTransactionReview", "review-1").Return(review, nil).Once()
+		mockDao.On("UpdateTransactionReviewStatus", "review-1", common.ReviewStatusPending, common.ReviewStatusApproved, "operator-1").
+			Return(dao.ErrReviewNotFound).Once()
+
+		_, err := impl.ApproveTransactionReview(ctx, "review-1", "operator-1")
+		assert.ErrorIs(t, err, logic.ErrReviewNotFound)
+		mockDao.AssertExpectations(t)
+	})
+}
+
+func TestRejectTransactionReview(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+
+	mockDao.On("GetTransactionReview", "review-1").Return(&dao.TransactionReview{
+		ID:             "review-1",
+		Type:           common.TransactionTypeTransfer,
+		Status:         common.ReviewStatusPending,
+		IdempotencyKey: "key-review",
+	}, nil).Once()
+	mockDao.On("UpdateTransactionReviewStatus", "review-1", common.ReviewStatusPending, common.ReviewStatusRejected, "operator-1").
+		Return(nil).Once()
+	mockDao.On("FailReviewedTransferBatchItem", "review-1", logic.ErrBatchItemRejected.Error()).Return(nil).Once()
+
+	review, err := impl.RejectTransactionReview(context.TODO(), "review-1", "operator-1")
+	assert.NoError(t, err)
+	assert.Equal(t, common.ReviewStatusRejected, review.Status)
+	assert.Equal(t, "key-review", review.IdempotencyKey)
+	mockDao.AssertExpectations(t)
+}
@@ -0,0 +1,141 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/screening"
+)
+
+var (
+	ErrInvalidUser    = errors.New("name and email are required")
+	ErrSanctionsMatch = errors.New("party matched a sanctions or blocklist entry")
+)
+
+// SetSanctionsChecker plugs the sanctions/blocklist checker used at onboarding and on transfers.
+func (l *WalletImpl) SetSanctionsChecker(checker screening.Checker) {
+	l.sanctions = checker
+}
+
+// CreateUser onboards a user with an empty wallet. A user matching a sanctions entry is still
+// created so operators can review the hit, but the wallet starts out frozen.
+func (l *WalletImpl) CreateUser(ctx context.Context, name, email string) (*dao.User, *dao.Wallet, *dao.UserScreening, error) {
+	name = strings.TrimSpace(name)
+	email = strings.TrimSpace(email)
+	if name == "" || email == "" {
+		return nil, nil, nil, ErrInvalidUser
+	}
+
+	now := time.Now()
+	user := &dao.User{
+		ID:        uuid.NewString(),
+		Name:      name,
+		Email:     email,
+		KycLevel:  common.KycLevelUnverified,
+		CreatedAt: now,
+	}
+	wallet := &dao.Wallet{
+		ID:        uuid.NewString(),
+		UserID:    user.ID,
+		Balance:   0,
+		Status:    common.WalletStatusActive,
+		Type:      common.WalletTypeStandard,
+		CreatedAt: now,
+	}
+
+	result := l.checkSanctions(user)
+	if result != nil && result.Status == common.ScreeningStatusMatch {
+		wallet.Status = common.WalletStatusFrozen
+	}
+
+	if err := l.dao.CreateUserWithWallet(user, wallet); err != nil {
+		l.logger.WithError(err).Error("Failed to create user")
+		return nil, nil, nil, fmt.Errorf("create user failed: %w", err)
+	}
+
+	if result != nil {
+		if err := l.dao.SaveUserScreening(result); err != nil {
+			l.logger.WithError(err).Errorf("Failed to store screening result for user %s", user.ID)
+		}
+	}
+
+	l.recordAudit(ctx, common.AuditActionUserCreated, common.AuditEntityUser, user.ID, map[string]any{
+		"wallet_id":     wallet.ID,
+		"wallet_status": wallet.Status,
+		"kyc_level":     user.KycLevel,
+	})
+
+	return user, wallet, result, nil
+}
+
+// ScreenTransferParties screens the owners of both wallets of a transfer and freezes the
+// wallets of any owner that matches a sanctions entry.
+func (l *WalletImpl) ScreenTransferParties(ctx context.Context, fromWalletID, toWalletID string) error {
+	if l.sanctions == nil {
+		return nil
+	}
+
+	matched := false
+	for _, walletID := range []string{fromWalletID, toWalletID} {
+		user, err := l.dao.GetUserByWalletID(walletID)
+		if err != nil {
+			if errors.Is(err, dao.ErrWalletNotFound) {
+				return ErrWalletNotFound
+			}
+			return fmt.Errorf("sanctions screening failed: %w", err)
+		}
+
+		result := l.checkSanctions(user)
+		if err := l.dao.SaveUserScreening(result); err != nil {
+			l.logger.WithError(err).Errorf("Failed to store screening result for user %s", user.ID)
+		}
+		if result.Status != common.ScreeningStatusMatch {
+			continue
+		}
+
+		matched = true
+		if err := l.dao.FreezeUserWallets(user.ID); err != nil {
+			return fmt.Errorf("sanctions screening failed: %w", err)
+		}
+		l.recordAudit(ctx, common.AuditActionWalletsFrozen, common.AuditEntityUser, user.ID, map[string]any{
+			"reason":    "sanctions_match",
+			"list_name": result.ListName,
+		})
+	}
+
+	if matched {
+		return ErrSanctionsMatch
+	}
+	return nil
+}
+
+// checkSanctions returns nil when no checker is configured.
+func (l *WalletImpl) checkSanctions(user *dao.User) *dao.UserScreening {
+	if l.sanctions == nil {
+		return nil
+	}
+
+	result := &dao.UserScreening{
+		UserID:     user.ID,
+		Status:     common.ScreeningStatusClear,
+		ScreenedAt: time.Now(),
+	}
+
+	match, found := l.sanctions.Check(user.Name, user.Email)
+	if found {
+		l.logger.Warnf("User %s matched list %s", user.ID, match.ListName)
+		result.Status = common.ScreeningStatusMatch
+		result.ListName = &match.ListName
+		result.MatchedName = &match.Name
+		result.MatchedEmail = &match.Email
+		result.Score = match.Score
+	}
+	return result
+}
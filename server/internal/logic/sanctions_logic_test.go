@@ -0,0 +1,100 @@
+package logic_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/screening"
+	screeningMocks "github.com/julkhong/walletapp/server/internal/screening/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateUser(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	checker := new(screeningMocks.Checker)
+	impl.SetSanctionsChecker(checker)
+	ctx := context.TODO()
+
+	t.Run("clear user gets an active wallet", func(t *testing.T) {
+		checker.On("Check", "Alice", "alice@example.com").Return(nil, false).Once()
+		mockDao.On("CreateUserWithWallet", mock.Anything, mock.MatchedBy(func(w *dao.Wallet) bool {
+			return w.Status == common.WalletStatusActive
+		})).Return(nil).Once()
+		mockDao.On("SaveUserScreening", mock.MatchedBy(func(s *dao.UserScreening) bool {
+			return s.Status == common.ScreeningStatusClear
+		})).Return(nil).Once()
+
+		user, wallet, result, err := impl.CreateUser(ctx, "Alice", "alice@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, common.KycLevelUnverified, user.KycLevel)
+		assert.Equal(t, user.ID, wallet.UserID)
+		assert.Equal(t, common.ScreeningStatusClear, result.Status)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("matched user gets a frozen wallet", func(t *testing.T) {
+		checker.On("Check", "Mallory Blackwood", "m@example.com").
+			Return(&screening.Match{ListName: "sample_blocklist", Name: "Mallory Blackwood", Score: 1}, true).Once()
+		mockDao.On("CreateUserWithWallet", mock.Anything, mock.MatchedBy(func(w *dao.Wallet) bool {
+			return w.Status == common.WalletStatusFrozen
+		})).Return(nil).Once()
+		mockDao.On("SaveUserScreening", mock.MatchedBy(func(s *dao.UserScreening) bool {
+			return s.Status == common.ScreeningStatusMatch && *s.ListName == "sample_blocklist"
+		})).Return(nil).Once()
+
+		_, wallet, result, err := impl.CreateUser(ctx, "Mallory Blackwood", "m@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, common.WalletStatusFrozen, wallet.Status)
+		assert.Equal(t, common.ScreeningStatusMatch, result.Status)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("missing email", func(t *testing.T) {
+		_, _, _, err := impl.CreateUser(ctx, "Alice", " ")
+		assert.ErrorIs(t, err, logic.ErrInvalidUser)
+	})
+}
+
+func TestScreenTransferParties(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	ctx := context.TODO()
+	sender := &dao.User{ID: "user-1", Name: "Alice", Email: "alice@example.com"}
+	receiver := &dao.User{ID: "user-2", Name: "Trudy Impostor", Email: "trudy@example.com"}
+
+	t.Run("no checker configured", func(t *testing.T) {
+		err := impl.ScreenTransferParties(ctx, "wallet-1", "wallet-2")
+		assert.NoError(t, err)
+	})
+
+	checker := new(screeningMocks.Checker)
+	impl.SetSanctionsChecker(checker)
+
+	t.Run("clear parties", func(t *testing.T) {
+		mockDao.On("GetUserByWalletID", "wallet-1").Return(sender, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-3").Return(sender, nil).Once()
+		checker.On("Check", "Alice", "alice@example.com").Return(nil, false).Twice()
+		mockDao.On("SaveUserScreening", mock.Anything).Return(nil).Twice()
+
+		err := impl.ScreenTransferParties(ctx, "wallet-1", "wallet-3")
+		assert.NoError(t, err)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("matched receiver is frozen", func(t *testing.T) {
+		mockDao.On("GetUserByWalletID", "wallet-1").Return(sender, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-2").Return(receiver, nil).Once()
+		checker.On("Check", "Alice", "alice@example.com").Return(nil, false).Once()
+		checker.On("Check", "Trudy Impostor", "trudy@example.com").
+			Return(&screening.Match{ListName: "sample_blocklist", Name: "Trudy Impostor", Score: 1}, true).Once()
+		mockDao.On("SaveUserScreening", mock.Anything).Return(nil).Twice()
+		mockDao.On("FreezeUserWallets", "user-2").Return(nil).Once()
+
+		err := impl.ScreenTransferParties(ctx, "wallet-1", "wallet-2")
+		assert.ErrorIs(t, err, logic.ErrSanctionsMatch)
+		mockDao.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,309 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/interest"
+	"github.com/julkhong/walletapp/server/internal/statement"
+)
+
+var (
+	ErrInvalidWalletType     = errors.New("wallet type must be standard or savings")
+	ErrInvalidInterestRate   = errors.New("interest rate must be between 0 and 1 and is for savings wallets only")
+	ErrNoInterest            = errors.New("wallet is neither a savings wallet nor has a credit line")
+	ErrInvalidInterestPeriod = errors.New("interest report period must run forwards and span at most a year")
+)
+
+const (
+	// maxInterestCatchUpDays bounds the days one run accrues for a wallet; a longer gap is
+	// caught up over the following runs.
+	maxInterestCatchUpDays = 400
+	// maxInterestReportDays bounds the period of an accrual report.
+	maxInterestReportDays = 366
+)
+
+// InterestConfig holds the annual rate of savings wallets that have none of their own and the
+// annual rate charged on overdrafts.
+type InterestConfig struct {
+	DefaultRate   float64
+	OverdraftRate float64
+}
+
+func (l *WalletImpl) SetInterestConfig(cfg InterestConfig) {
+	l.interest = cfg
+}
+
+// InterestReport is the interest a wallet accrued over a period. Amounts are exact decimals at
+// ten places, negative for overdraft interest; Posted is the part already booked on the wallet.
+type InterestReport struct {
+	WalletID   string                `json:"wallet_id"`
+	AnnualRate float64               `json:"annual_rate"`
+	From       string                `json:"from"`
+	To         string                `json:"to"`
+	Accrued    string                `json:"accrued"`
+	Posted     string                `json:"posted"`
+	Unposted   string                `json:"unposted"`
+	Days       []dao.InterestAccrual `json:"days"`
+}
+
+// CreateWallet opens another wallet for an existing user. Only savings wallets take an interest
+// rate; without one they earn the configured default. Like at onboarding, the wallet starts
+// out frozen when the user matches a sanctions entry.
+func (l *WalletImpl) CreateWallet(ctx context.Context, userID, walletType string, interestRate *float64) (*dao.Wallet, error) {
+	if walletType == "" {
+		walletType = common.WalletTypeStandard
+	}
+	if walletType != common.WalletTypeStandard && walletType != common.WalletTypeSavings {
+		return nil, ErrInvalidWalletType
+	}
+	if interestRate != nil && (walletType != common.WalletTypeSavings || *interestRate < 0 || *interestRate > 1) {
+		return nil, ErrInvalidInterestRate
+	}
+
+	user, err := l.dao.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, dao.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	wallet := &dao.Wallet{
+		ID:           uuid.NewString(),
+		UserID:       user.ID,
+		Balance:      0,
+		Status:       common.WalletStatusActive,
+		Type:         walletType,
+		InterestRate: interestRate,
+		CreatedAt:    time.Now(),
+	}
+	result := l.checkSanctions(user)
+	if result != nil && result.Status == common.ScreeningStatusMatch {
+		wallet.Status = common.WalletStatusFrozen
+	}
+
+	if err := l.dao.CreateWallet(wallet); err != nil {
+		l.logger.WithError(err).Error("Failed to create wallet")
+		return nil, fmt.Errorf("create wallet failed: %w", err)
+	}
+	if result != nil {
+		if err := l.dao.SaveUserScreening(result); err != nil {
+			l.logger.WithError(err).Errorf("Failed to store screening result for user %s", user.ID)
+		}
+	}
+
+	l.recordAudit(ctx, common.AuditActionWalletCreated, common.AuditEntityWallet, wallet.ID, map[string]any{
+		"user_id":       user.ID,
+		"type":          wallet.Type,
+		"interest_rate": wallet.InterestRate,
+		"status":        wallet.Status,
+	})
+	return wallet, nil
+}
+
+// AccrueInterest accrues a day of interest on the end-of-day balance of every savings wallet,
+// and of overdraft interest on every wallet with a credit line, for each day that has ended. It
+// posts the interest of every month accrued to its last day. Days missed while the job did not
+// run are caught up. It returns how many days were accrued.
+func (l *WalletImpl) AccrueInterest(ctx context.Context) (int, error) {
+	wallets, err := l.dao.ListInterestWallets()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	// a day is accrued once it has ended and its transactions have settled
+	lastDay := startOfDay(now.Add(-snapshotLag)).AddDate(0, 0, -1)
+	accrued := 0
+	for i := range wallets {
+		days, err := l.accrueWalletInterest(ctx, &wallets[i], lastDay, now)
+		accrued += days
+		if err != nil {
+			l.logger.WithError(err).Errorf("Failed to accrue interest for wallet %s", wallets[i].ID)
+		}
+	}
+	if accrued > 0 {
+		l.logger.Infof("Accrued %d days of interest", accrued)
+	}
+	return accrued, nil
+}
+
+func (l *WalletImpl) accrueWalletInterest(ctx context.Context, wallet *dao.Wallet, lastDay, now time.Time) (int, error) {
+	last, err := l.dao.GetLastInterestAccrualDate(wallet.ID)
+	if err != nil {
+		return 0, err
+	}
+	day := startOfDay(wallet.CreatedAt)
+	if last != nil {
+		day = startOfDay(*last).AddDate(0, 0, 1)
+	}
+
+	rate, daily := l.interestRate(wallet), interest.Daily
+	if wallet.Type != common.WalletTypeSavings {
+		daily = interest.Overdraft
+	}
+	var accruals []dao.InterestAccrual
+	for ; !day.After(lastDay) && len(accruals) < maxInterestCatchUpDays; day = day.AddDate(0, 0, 1) {
+		balance, err := l.dao.GetBalanceAsOf(wallet.ID, day.AddDate(0, 0, 1).Add(-time.Microsecond))
+		if err != nil {
+			return 0, err
+		}
+		balance = common.RoundToNDecimals(balance, 4)
+		accruals = append(accruals, dao.InterestAccrual{
+			WalletID:    wallet.ID,
+			AccrualDate: day,
+			Balance:     balance,
+			AnnualRate:  rate,
+			Amount:      daily(balance, rate),
+			CreatedAt:   now,
+		})
+	}
+
+	created, err := l.dao.CreateInterestAccruals(accruals)
+	if err != nil {
+		return 0, err
+	}
+	if len(accruals) > 0 {
+		last = &accruals[len(accruals)-1].AccrualDate
+	}
+	if last == nil {
+		return int(created), nil
+	}
+
+	// months still missing days are posted once they are caught up
+	postBefore, _ := statement.MonthPeriod(last.AddDate(0, 0, 1))
+	return int(created), l.postInterest(ctx, wallet.ID, postBefore, now)
+}
+
+// postInterest books the unposted accruals of each month before postBefore as one transaction,
+// the month's exact total rounded to the ledger's four decimals: an interest credit, or an
+// overdraft interest debit when the total is negative.
+func (l *WalletImpl) postInterest(ctx context.Context, walletID string, postBefore, now time.Time) error {
+	accruals, err := l.dao.ListUnpostedInterestAccruals(walletID, postBefore)
+	if err != nil {
+		return err
+	}
+
+	for len(accruals) > 0 {
+		start, end := statement.MonthPeriod(accruals[0].AccrualDate)
+		var amounts []string
+		for len(accruals) > 0 && accruals[0].AccrualDate.Before(end) {
+			amounts = append(amounts, accruals[0].Amount)
+			accruals = accruals[1:]
+		}
+
+		total, err := interest.Sum(amounts, interest.PostingPlaces)
+		if err != nil {
+			return err
+		}
+		amount, _ := strconv.ParseFloat(total, 64)
+		var credit *dao.Transaction
+		if amount != 0 {
+			credit = &dao.Transaction{
+				ID:        uuid.NewString(),
+				WalletID:  walletID,
+				Type:      common.TransactionTypeInterest,
+				Amount:    amount,
+				CreatedAt: now,
+			}
+			if amount < 0 {
+				credit.Type = common.TransactionTypeOverdraftInterest
+			}
+		}
+
+		if err := l.dao.PostInterest(walletID, start, end, now, credit); err != nil {
+			if errors.Is(err, dao.ErrInterestPosted) {
+				continue
+			}
+			return err
+		}
+		if credit != nil {
+			l.recordAudit(ctx, common.AuditActionInterestPosted, common.AuditEntityWallet, walletID, map[string]any{
+				"month":          start.Format("2006-01"),
+				"type":           credit.Type,
+				"amount":         amount,
+				"days":           len(amounts),
+				"transaction_id": credit.ID,
+			})
+		}
+	}
+	return nil
+}
+
+// GetInterestReport returns the daily accruals of a savings wallet or a wallet with a credit line
+// for the days from from up to and including to.
+func (l *WalletImpl) GetInterestReport(ctx context.Context, walletID string, from, to time.Time) (*InterestReport, error) {
+	from, to = startOfDay(from), startOfDay(to)
+	if to.Before(from) || to.Sub(from) >= maxInterestReportDays*24*time.Hour {
+		return nil, ErrInvalidInterestPeriod
+	}
+
+	wallet, err := l.dao.GetWalletByID(walletID)
+	if err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, err
+	}
+	if wallet.Type != common.WalletTypeSavings && wallet.CreditLimit == 0 {
+		return nil, ErrNoInterest
+	}
+
+	accruals, err := l.dao.ListInterestAccruals(walletID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if accruals == nil {
+		accruals = []dao.InterestAccrual{}
+	}
+
+	var all, posted, unposted []string
+	for _, accrual := range accruals {
+		all = append(all, accrual.Amount)
+		if accrual.PostedAt != nil {
+			posted = append(posted, accrual.Amount)
+		} else {
+			unposted = append(unposted, accrual.Amount)
+		}
+	}
+	report := &InterestReport{
+		WalletID:   walletID,
+		AnnualRate: l.interestRate(wallet),
+		From:       from.Format(time.DateOnly),
+		To:         to.Format(time.DateOnly),
+		Days:       accruals,
+	}
+	for _, total := range []struct {
+		amounts []string
+		out     *string
+	}{{all, &report.Accrued}, {posted, &report.Posted}, {unposted, &report.Unposted}} {
+		if *total.out, err = interest.Sum(total.amounts, interest.AccrualPlaces); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+func (l *WalletImpl) interestRate(wallet *dao.Wallet) float64 {
+	if wallet.Type != common.WalletTypeSavings {
+		return l.interest.OverdraftRate
+	}
+	if wallet.InterestRate != nil {
+		return *wallet.InterestRate
+	}
+	return l.interest.DefaultRate
+}
+
+// startOfDay truncates t to midnight UTC; interest days are UTC days.
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,194 @@
+package logic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateWallet(t *testing.T) {
+	ctx := context.TODO()
+	rate := 0.05
+
+	t.Run("savings wallet with its own rate", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetUserByID", "user-1").Return(&dao.User{ID: "user-1", Name: "Alice"}, nil).Once()
+		mockDao.On("CreateWallet", mock.MatchedBy(func(w *dao.Wallet) bool {
+			return w.UserID == "user-1" && w.Type == common.WalletTypeSavings && *w.InterestRate == rate &&
+				w.Status == common.WalletStatusActive
+		})).Return(nil).Once()
+
+		wallet, err := impl.CreateWallet(ctx, "user-1", common.WalletTypeSavings, &rate)
+		assert.NoError(t, err)
+		assert.Equal(t, common.WalletTypeSavings, wallet.Type)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("type defaults to standard", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetUserByID", "user-1").Return(&dao.User{ID: "user-1"}, nil).Once()
+		mockDao.On("CreateWallet", mock.Anything).Return(nil).Once()
+
+		wallet, err := impl.CreateWallet(ctx, "user-1", "", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, common.WalletTypeStandard, wallet.Type)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		negative := -0.01
+
+		_, err := impl.CreateWallet(ctx, "user-1", "checking", nil)
+		assert.ErrorIs(t, err, logic.ErrInvalidWalletType)
+		_, err = impl.CreateWallet(ctx, "user-1", common.WalletTypeStandard, &rate)
+		assert.ErrorIs(t, err, logic.ErrInvalidInterestRate)
+		_, err = impl.CreateWallet(ctx, "user-1", common.WalletTypeSavings, &negative)
+		assert.ErrorIs(t, err, logic.ErrInvalidInterestRate)
+		mockDao.AssertNotCalled(t, "CreateWallet", mock.Anything)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetUserByID", "user-2").Return(nil, dao.ErrUserNotFound).Once()
+
+		_, err := impl.CreateWallet(ctx, "user-2", common.WalletTypeSavings, nil)
+		assert.ErrorIs(t, err, logic.ErrUserNotFound)
+	})
+}
+
+func TestAccrueInterest(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Now().UTC().Add(-time.Minute)
+	yesterday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	thisMonth := time.Date(yesterday.Year(), yesterday.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if yesterday.AddDate(0, 0, 1).Day() == 1 {
+		// yesterday closed its month, which is then posted as well
+		thisMonth = thisMonth.AddDate(0, 1, 0)
+	}
+
+	t.Run("catches up missed days and posts complete months", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		impl.SetInterestConfig(logic.InterestConfig{DefaultRate: 0.02})
+		wallet := dao.Wallet{ID: "wallet-s", Type: common.WalletTypeSavings, CreatedAt: yesterday.AddDate(0, 0, -2).Add(15 * time.Hour)}
+		mockDao.On("ListInterestWallets").Return([]dao.Wallet{wallet}, nil).Once()
+		mockDao.On("GetLastInterestAccrualDate", "wallet-s").Return(nil, nil).Once()
+		mockDao.On("GetBalanceAsOf", "wallet-s", mock.Anything).Return(1000.0, nil).Times(3)
+		mockDao.On("CreateInterestAccruals", mock.MatchedBy(func(accruals []dao.InterestAccrual) bool {
+			return len(accruals) == 3 && accruals[2].AccrualDate.Equal(yesterday) &&
+				accruals[0].Amount == "0.0547945205" && accruals[0].AnnualRate == 0.02
+		})).Return(int64(3), nil).Once()
+
+		// two earlier months still waiting to be posted
+		jan := time.Date(2026, 1, 30, 0, 0, 0, 0, time.UTC)
+		feb := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+		mockDao.On("ListUnpostedInterestAccruals", "wallet-s", thisMonth).Return([]dao.InterestAccrual{
+			{WalletID: "wallet-s", AccrualDate: jan, Amount: "0.0547945205"},
+			{WalletID: "wallet-s", AccrualDate: jan.AddDate(0, 0, 1), Amount: "0.0547945205"},
+			{WalletID: "wallet-s", AccrualDate: feb, Amount: "0.0000123"},
+		}, nil).Once()
+		mockDao.On("PostInterest", "wallet-s", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), feb, mock.Anything,
+			mock.MatchedBy(func(tx *dao.Transaction) bool {
+				return tx.Type == common.TransactionTypeInterest && tx.Amount == 0.1096
+			})).Return(nil).Once()
+		// February's interest rounds to zero and is only marked as posted
+		mockDao.On("PostInterest", "wallet-s", feb, feb.AddDate(0, 1, 0), mock.Anything, (*dao.Transaction)(nil)).
+			Return(dao.ErrInterestPosted).Once()
+
+		accrued, err := impl.AccrueInterest(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, accrued)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("overdraft interest on a credit line", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		impl.SetInterestConfig(logic.InterestConfig{DefaultRate: 0.02, OverdraftRate: 0.18})
+		last := yesterday.AddDate(0, 0, -1)
+		wallet := dao.Wallet{ID: "wallet-c", Type: common.WalletTypeStandard, CreditLimit: 5000}
+		mockDao.On("ListInterestWallets").Return([]dao.Wallet{wallet}, nil).Once()
+		mockDao.On("GetLastInterestAccrualDate", "wallet-c").Return(&last, nil).Once()
+		mockDao.On("GetBalanceAsOf", "wallet-c", mock.Anything).Return(-1000.0, nil).Once()
+		mockDao.On("CreateInterestAccruals", mock.MatchedBy(func(accruals []dao.InterestAccrual) bool {
+			return len(accruals) == 1 && accruals[0].Amount == "-0.4931506849" && accruals[0].AnnualRate == 0.18
+		})).Return(int64(1), nil).Once()
+
+		jan := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockDao.On("ListUnpostedInterestAccruals", "wallet-c", thisMonth).Return([]dao.InterestAccrual{
+			{WalletID: "wallet-c", AccrualDate: jan, Amount: "-0.4931506849"},
+			{WalletID: "wallet-c", AccrualDate: jan.AddDate(0, 0, 1), Amount: "0.0000000000"},
+		}, nil).Once()
+		mockDao.On("PostInterest", "wallet-c", jan, jan.AddDate(0, 1, 0), mock.Anything,
+			mock.MatchedBy(func(tx *dao.Transaction) bool {
+				return tx.Type == common.TransactionTypeOverdraftInterest && tx.Amount == -0.4932
+			})).Return(nil).Once()
+
+		accrued, err := impl.AccrueInterest(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, accrued)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("up to date wallet accrues nothing", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		rate := 0.05
+		wallet := dao.Wallet{ID: "wallet-s", Type: common.WalletTypeSavings, InterestRate: &rate}
+		mockDao.On("ListInterestWallets").Return([]dao.Wallet{wallet}, nil).Once()
+		mockDao.On("GetLastInterestAccrualDate", "wallet-s").Return(&yesterday, nil).Once()
+		mockDao.On("CreateInterestAccruals", []dao.InterestAccrual(nil)).Return(int64(0), nil).Once()
+		mockDao.On("ListUnpostedInterestAccruals", "wallet-s", thisMonth).Return(nil, nil).Once()
+
+		accrued, err := impl.AccrueInterest(ctx)
+		assert.NoError(t, err)
+		assert.Zero(t, accrued)
+		mockDao.AssertNotCalled(t, "GetBalanceAsOf", mock.Anything, mock.Anything)
+		mockDao.AssertNotCalled(t, "PostInterest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetInterestReport(t *testing.T) {
+	ctx := context.TODO()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	t.Run("totals split by posting", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		impl.SetInterestConfig(logic.InterestConfig{DefaultRate: 0.02})
+		postedAt := time.Now()
+		mockDao.On("GetWalletByID", "wallet-s").Return(&dao.Wallet{ID: "wallet-s", Type: common.WalletTypeSavings}, nil).Once()
+		mockDao.On("ListInterestAccruals", "wallet-s", from, from.AddDate(0, 0, 1)).Return([]dao.InterestAccrual{
+			{AccrualDate: from, Amount: "0.0547945205", PostedAt: &postedAt},
+			{AccrualDate: from.AddDate(0, 0, 1), Amount: "0.0547945205"},
+		}, nil).Once()
+
+		report, err := impl.GetInterestReport(ctx, "wallet-s", from, to)
+		assert.NoError(t, err)
+		assert.Equal(t, 0.02, report.AnnualRate)
+		assert.Equal(t, "2026-03-02", report.To)
+		assert.Equal(t, "0.1095890410", report.Accrued)
+		assert.Equal(t, "0.0547945205", report.Posted)
+		assert.Equal(t, "0.0547945205", report.Unposted)
+		assert.Len(t, report.Days, 2)
+	})
+
+	t.Run("standard wallet without a credit line", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetWalletByID", "wallet-1").Return(&dao.Wallet{ID: "wallet-1", Type: common.WalletTypeStandard}, nil).Once()
+
+		_, err := impl.GetInterestReport(ctx, "wallet-1", from, to)
+		assert.ErrorIs(t, err, logic.ErrNoInterest)
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		impl, _ := setupLogicTest()
+		_, err := impl.GetInterestReport(ctx, "wallet-s", to, from)
+		assert.ErrorIs(t, err, logic.ErrInvalidInterestPeriod)
+		_, err = impl.GetInterestReport(ctx, "wallet-s", from, from.AddDate(1, 1, 0))
+		assert.ErrorIs(t, err, logic.ErrInvalidInterestPeriod)
+	})
+}
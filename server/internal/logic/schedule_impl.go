@@ -0,0 +1,378 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/recurrence"
+)
+
+var (
+	ErrScheduleNotFound         = errors.New("scheduled transfer not found")
+	ErrScheduleStatusConflict   = errors.New("scheduled transfer cannot change to that status")
+	ErrInvalidRecurrence        = errors.New("invalid recurrence")
+	ErrInvalidSchedule          = errors.New("invalid scheduled transfer")
+	ErrScheduleRequiresApproval = errors.New("amount requires approval and cannot be scheduled")
+)
+
+const (
+	// maxScheduleBatch bounds the due schedules one scheduler pass picks up.
+	maxScheduleBatch = 200
+	// maxScheduleCatchUp bounds the missed occurrences of one schedule a pass executes, so a
+	// long outage is caught up over several passes instead of blocking other schedules.
+	maxScheduleCatchUp = 10
+	// maxScheduleFailures is how many occurrences in a row may fail before the schedule is paused.
+	maxScheduleFailures = 3
+	// scheduleRunHistory is how many recent runs are returned with a schedule.
+	scheduleRunHistory = 50
+)
+
+// ScheduleRequest describes a recurring transfer. Recurrence is a five-field cron expression or
+// an RRULE, evaluated in UTC from StartAt.
+type ScheduleRequest struct {
+	FromWalletID string
+	ToWalletID   string
+	Amount       float64
+	Recurrence   string
+	StartAt      time.Time
+	EndAt        *time.Time
+	CreatedBy    string
+}
+
+// ScheduleDetail is a schedule with its most recent runs.
+type ScheduleDetail struct {
+	dao.Schedule
+	Runs []dao.ScheduleRun `json:"runs"`
+}
+
+func (l *WalletImpl) CreateScheduledTransfer(ctx context.Context, req ScheduleRequest) (*dao.Schedule, error) {
+	amount := common.RoundToNDecimals(req.Amount, 4)
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidSchedule)
+	}
+	if req.FromWalletID == req.ToWalletID {
+		return nil, fmt.Errorf("%w: source and destination wallets must differ", ErrInvalidSchedule)
+	}
+	if l.RequiresApproval(amount) {
+		return nil, ErrScheduleRequiresApproval
+	}
+
+	now := time.Now().UTC()
+	start := req.StartAt.UTC()
+	if req.StartAt.IsZero() {
+		start = now.Truncate(time.Minute)
+	}
+	var end *time.Time
+	if req.EndAt != nil {
+		e := req.EndAt.UTC()
+		if !e.After(start) {
+			return nil, fmt.Errorf("%w: end_at must be after start_at", ErrInvalidSchedule)
+		}
+		end = &e
+	}
+
+	rule, err := recurrence.Parse(req.Recurrence, start)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	next, ok := nextOccurrence(rule, now, end)
+	if !ok {
+		return nil, fmt.Errorf("%w: the recurrence has no occurrence before end_at", ErrInvalidSchedule)
+	}
+
+	for _, walletID := range []string{req.FromWalletID, req.ToWalletID} {
+		if err := l.ensureWalletActive(walletID); err != nil {
+			return nil, err
+		}
+	}
+
+	schedule := &dao.Schedule{
+		ID:           uuid.NewString(),
+		FromWalletID: req.FromWalletID,
+		ToWalletID:   req.ToWalletID,
+		Amount:       amount,
+		Recurrence:   req.Recurrence,
+		StartAt:      start,
+		EndAt:        end,
+		NextRunAt:    &next,
+		Status:       common.ScheduleStatusActive,
+		CreatedBy:    req.CreatedBy,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := l.dao.CreateSchedule(schedule); err != nil {
+		return nil, fmt.Errorf("failed to create scheduled transfer: %w", err)
+	}
+
+	l.recordAudit(ctx, common.AuditActionScheduleCreated, common.AuditEntitySchedule, schedule.ID, map[string]any{
+		"from_wallet_id": schedule.FromWalletID,
+		"to_wallet_id":   schedule.ToWalletID,
+		"amount":         schedule.Amount,
+		"recurrence":     schedule.Recurrence,
+		"next_run_at":    next,
+	})
+	return schedule, nil
+}
+
+func (l *WalletImpl) ListScheduledTransfers(ctx context.Context, walletID string) ([]dao.Schedule, error) {
+	return l.dao.ListSchedules(walletID)
+}
+
+func (l *WalletImpl) GetScheduledTransfer(ctx context.Context, scheduleID string) (*ScheduleDetail, error) {
+	schedule, err := l.getSchedule(scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := l.dao.ListScheduleRuns(scheduleID, scheduleRunHistory)
+	if err != nil {
+		return nil, err
+	}
+	return &ScheduleDetail{Schedule: *schedule, Runs: runs}, nil
+}
+
+// PauseScheduledTransfer stops an active schedule from running. Its next occurrence is kept
+// for reference; resuming recomputes it.
+func (l *WalletImpl) PauseScheduledTransfer(ctx context.Context, scheduleID, actor string) (*dao.Schedule, error) {
+	schedule, err := l.getSchedule(scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	return l.changeScheduleStatus(ctx, schedule, []string{common.ScheduleStatusActive}, common.ScheduleStatusPaused, schedule.NextRunAt, actor)
+}
+
+// ResumeScheduledTransfer reactivates a paused schedule from its next occurrence after now;
+// occurrences missed while paused are skipped. A schedule with no occurrences left completes.
+func (l *WalletImpl) ResumeScheduledTransfer(ctx context.Context, scheduleID, actor string) (*dao.Schedule, error) {
+	schedule, err := l.getSchedule(scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := recurrence.Parse(schedule.Recurrence, schedule.StartAt.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+
+	to := common.ScheduleStatusActive
+	var nextRunAt *time.Time
+	if next, ok := nextOccurrence(rule, time.Now().UTC(), schedule.EndAt); ok {
+		nextRunAt = &next
+	} else {
+		to = common.ScheduleStatusCompleted
+	}
+	return l.changeScheduleStatus(ctx, schedule, []string{common.ScheduleStatusPaused}, to, nextRunAt, actor)
+}
+
+func (l *WalletImpl) CancelScheduledTransfer(ctx context.Context, scheduleID, actor string) (*dao.Schedule, error) {
+	schedule, err := l.getSchedule(scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	from := []string{common.ScheduleStatusActive, common.ScheduleStatusPaused}
+	return l.changeScheduleStatus(ctx, schedule, from, common.ScheduleStatusCancelled, nil, actor)
+}
+
+func (l *WalletImpl) getSchedule(scheduleID string) (*dao.Schedule, error) {
+	schedule, err := l.dao.GetSchedule(scheduleID)
+	if err != nil {
+		if errors.Is(err, dao.ErrScheduleNotFound) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (l *WalletImpl) changeScheduleStatus(ctx context.Context, schedule *dao.Schedule, from []string, to string, nextRunAt *time.Time, actor string) (*dao.Schedule, error) {
+	if !slices.Contains(from, schedule.Status) {
+		return nil, ErrScheduleStatusConflict
+	}
+	if err := l.dao.UpdateScheduleStatus(schedule.ID, from, to, nextRunAt); err != nil {
+		if errors.Is(err, dao.ErrScheduleNotFound) {
+			// changed by someone else between the read and the update
+			return nil, ErrScheduleStatusConflict
+		}
+		return nil, err
+	}
+
+	l.recordAudit(ctx, common.AuditActionScheduleStatus, common.AuditEntitySchedule, schedule.ID, map[string]any{
+		"from":  schedule.Status,
+		"to":    to,
+		"actor": actor,
+	})
+	schedule.Status = to
+	schedule.NextRunAt = nextRunAt
+	schedule.FailureCount = 0
+	return schedule, nil
+}
+
+// RunDueSchedules executes the occurrences of active schedules that are due. Each occurrence is
+// claimed under the key schedule:<id>:<unix time>, so a retried or concurrent pass never pays
+// it twice. Business failures such as an insufficient balance are recorded and the schedule
+// moves on, pausing after maxScheduleFailures in a row; unexpected errors leave the occurrence
+// due for the next pass. It returns the number of occurrences executed.
+func (l *WalletImpl) RunDueSchedules(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	schedules, err := l.dao.ListDueSchedules(now, maxScheduleBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	executed := 0
+	for i := range schedules {
+		n, err := l.runSchedule(ctx, &schedules[i], now)
+		executed += n
+		if err != nil {
+			l.logger.WithError(err).Errorf("Scheduled transfer %s failed", schedules[i].ID)
+		}
+	}
+	if executed > 0 {
+		l.logger.Infof("Executed %d scheduled transfer occurrences", executed)
+	}
+	return executed, nil
+}
+
+func (l *WalletImpl) runSchedule(ctx context.Context, schedule *dao.Schedule, now time.Time) (int, error) {
+	rule, err := recurrence.Parse(schedule.Recurrence, schedule.StartAt.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+
+	executed := 0
+	for i := 0; i < maxScheduleCatchUp && schedule.NextRunAt != nil && !schedule.NextRunAt.After(now); i++ {
+		occurrence := *schedule.NextRunAt
+		advance := dao.ScheduleAdvance{LastRunAt: occurrence, Status: common.ScheduleStatusActive}
+
+		status, runErr := l.runOccurrence(ctx, schedule, occurrence)
+		switch {
+		case errors.Is(runErr, dao.ErrScheduleRunExists):
+			// executed by an earlier pass that did not get to advance the schedule
+			advance.FailureCount = schedule.FailureCount
+		case status == "" || status == common.ScheduleRunError:
+			return executed, runErr
+		case status == common.ScheduleRunFailed:
+			executed++
+			advance.FailureCount = schedule.FailureCount + 1
+		default:
+			executed++
+		}
+
+		if advance.FailureCount >= maxScheduleFailures {
+			advance.Status = common.ScheduleStatusPaused
+			advance.NextRunAt = schedule.NextRunAt
+		} else if next, ok := nextOccurrence(rule, occurrence, schedule.EndAt); ok {
+			advance.NextRunAt = &next
+		} else {
+			advance.Status = common.ScheduleStatusCompleted
+		}
+
+		if err := l.dao.AdvanceSchedule(schedule.ID, occurrence, advance); err != nil {
+			if errors.Is(err, dao.ErrScheduleNotFound) {
+				// paused or cancelled while the occurrence ran
+				return executed, nil
+			}
+			return executed, err
+		}
+		if advance.Status != schedule.Status {
+			l.recordAudit(ctx, common.AuditActionScheduleStatus, common.AuditEntitySchedule, schedule.ID, map[string]any{
+				"from":   schedule.Status,
+				"to":     advance.Status,
+				"reason": scheduleStopReason(advance, runErr),
+			})
+		}
+
+		schedule.Status = advance.Status
+		schedule.NextRunAt = advance.NextRunAt
+		schedule.LastRunAt = &advance.LastRunAt
+		schedule.FailureCount = advance.FailureCount
+		if schedule.Status != common.ScheduleStatusActive {
+			break
+		}
+	}
+	return executed, nil
+}
+
+// runOccurrence claims and executes one occurrence. It returns the run status with the error
+// behind it; an empty status means the occurrence could not be claimed.
+func (l *WalletImpl) runOccurrence(ctx context.Context, schedule *dao.Schedule, occurrence time.Time) (string, error) {
+	now := time.Now()
+	run := &dao.ScheduleRun{
+		ID:             uuid.NewString(),
+		ScheduleID:     schedule.ID,
+		OccurrenceAt:   occurrence,
+		IdempotencyKey: fmt.Sprintf("schedule:%s:%d", schedule.ID, occurrence.Unix()),
+		Status:         common.ScheduleRunPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := l.dao.ClaimScheduleRun(run); err != nil {
+		return "", err
+	}
+
+	err := l.authorizeCreator(ctx, schedule.FromWalletID, schedule.CreatedBy, schedule.Amount)
+	if err == nil {
+		err = l.ScreenTransferParties(ctx, schedule.FromWalletID, schedule.ToWalletID)
+	}
+	if err == nil {
+		_, err = l.Transfer(withInitiator(ctx, schedule.CreatedBy), schedule.FromWalletID, schedule.ToWalletID, schedule.Amount)
+	}
+
+	status := scheduleRunStatus(err)
+	var message *string
+	if err != nil {
+		m := err.Error()
+		message = &m
+	}
+	if finishErr := l.dao.FinishScheduleRun(run.IdempotencyKey, status, message); finishErr != nil {
+		l.logger.WithError(finishErr).Errorf("Failed to record the outcome of %s", run.IdempotencyKey)
+	}
+	if status == common.ScheduleRunError {
+		err = fmt.Errorf("occurrence %s: %w", run.IdempotencyKey, err)
+	}
+	return status, err
+}
+
+// scheduleRunStatus classifies the outcome of an occurrence. Failures the wallet owner has to
+// fix count towards pausing the schedule; anything else is retried.
+func scheduleRunStatus(err error) string {
+	var pending *PendingReviewError
+	switch {
+	case err == nil:
+		return common.ScheduleRunSucceeded
+	case errors.As(err, &pending):
+		return common.ScheduleRunReview
+	case errors.Is(err, ErrInsufficientBalance),
+		errors.Is(err, ErrWalletFrozen),
+		errors.Is(err, ErrWalletNotFound),
+		errors.Is(err, dao.ErrWalletNotFound),
+		errors.Is(err, ErrKycTransferLimitExceeded),
+		errors.Is(err, ErrTransactionDenied),
+		errors.Is(err, ErrSanctionsMatch),
+		errors.Is(err, ErrWalletAccessDenied),
+		errors.Is(err, ErrSpendingCapExceeded):
+		return common.ScheduleRunFailed
+	}
+	return common.ScheduleRunError
+}
+
+func scheduleStopReason(advance dao.ScheduleAdvance, runErr error) string {
+	if advance.Status == common.ScheduleStatusPaused && runErr != nil {
+		return fmt.Sprintf("%d consecutive failures, last: %v", advance.FailureCount, runErr)
+	}
+	return "no occurrences left"
+}
+
+// nextOccurrence returns the first occurrence of rule after t that is not past end.
+func nextOccurrence(rule recurrence.Rule, t time.Time, end *time.Time) (time.Time, bool) {
+	next, ok := rule.Next(t)
+	if !ok || (end != nil && next.After(*end)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
@@ -0,0 +1,247 @@
+package logic_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/dao/mocks"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateScheduledTransfer(t *testing.T) {
+	ctx := context.TODO()
+	start := time.Now().UTC().AddDate(0, 0, 1).Truncate(time.Hour)
+	req := logic.ScheduleRequest{
+		FromWalletID: "wallet-1",
+		ToWalletID:   "wallet-2",
+		Amount:       25,
+		Recurrence:   "FREQ=WEEKLY",
+		StartAt:      start,
+		CreatedBy:    "user-1",
+	}
+
+	t.Run("first run at start", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectActiveWallets(mockDao, "wallet-1", "wallet-2")
+		mockDao.On("CreateSchedule", mock.MatchedBy(func(s *dao.Schedule) bool {
+			return s.Status == common.ScheduleStatusActive && s.NextRunAt.Equal(start) && s.Amount == 25
+		})).Return(nil).Once()
+
+		schedule, err := impl.CreateScheduledTransfer(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, start, *schedule.NextRunAt)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		impl.SetApprovalPolicy(logic.ApprovalPolicy{TransferThreshold: 1000})
+
+		bad := req
+		bad.Recurrence = "every monday"
+		_, err := impl.CreateScheduledTransfer(ctx, bad)
+		assert.ErrorIs(t, err, logic.ErrInvalidRecurrence)
+
+		bad = req
+		end := start.Add(-time.Hour)
+		bad.EndAt = &end
+		_, err = impl.CreateScheduledTransfer(ctx, bad)
+		assert.ErrorIs(t, err, logic.ErrInvalidSchedule)
+
+		bad = req
+		bad.ToWalletID = bad.FromWalletID
+		_, err = impl.CreateScheduledTransfer(ctx, bad)
+		assert.ErrorIs(t, err, logic.ErrInvalidSchedule)
+
+		bad = req
+		bad.Amount = 5000
+		_, err = impl.CreateScheduledTransfer(ctx, bad)
+		assert.ErrorIs(t, err, logic.ErrScheduleRequiresApproval)
+
+		mockDao.AssertNotCalled(t, "CreateSchedule", mock.Anything)
+	})
+}
+
+// dueSchedule is a daily schedule whose first occurrence, an hour ago, is due.
+func dueSchedule(failures int) (dao.Schedule, time.Time) {
+	occurrence := time.Now().UTC().Add(-time.Hour).Truncate(time.Minute)
+	return dao.Schedule{
+		ID:           "sched-1",
+		FromWalletID: "wallet-1",
+		ToWalletID:   "wallet-2",
+		Amount:       30,
+		Recurrence:   "FREQ=DAILY",
+		StartAt:      occurrence,
+		NextRunAt:    &occurrence,
+		FailureCount: failures,
+		Status:       common.ScheduleStatusActive,
+	}, occurrence
+}
+
+func expectClaim(mockDao *mocks.WalletDaoInterface, occurrence time.Time, err error) string {
+	key := fmt.Sprintf("schedule:sched-1:%d", occurrence.Unix())
+	mockDao.On("ClaimScheduleRun", mock.MatchedBy(func(run *dao.ScheduleRun) bool {
+		return run.IdempotencyKey == key && run.OccurrenceAt.Equal(occurrence)
+	})).Return(err).Once()
+	return key
+}
+
+func TestRunDueSchedules(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("executes and advances", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		schedule, occurrence := dueSchedule(1)
+		next := occurrence.AddDate(0, 0, 1)
+		mockDao.On("ListDueSchedules", mock.Anything, 200).Return([]dao.Schedule{schedule}, nil).Once()
+		key := expectClaim(mockDao, occurrence, nil)
+		expectActiveWallets(mockDao, "wallet-1", "wallet-2")
+		mockDao.On("GetBalance", "wallet-1").Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", "wallet-2").Return(0.0, nil).Once()
+		mockDao.On("PostTransfers", mock.Anything).Return(nil).Once()
+		mockDao.On("FinishScheduleRun", key, common.ScheduleRunSucceeded, (*string)(nil)).Return(nil).Once()
+		mockDao.On("AdvanceSchedule", "sched-1", occurrence, dao.ScheduleAdvance{
+			NextRunAt: &next, LastRunAt: occurrence, Status: common.ScheduleStatusActive,
+		}).Return(nil).Once()
+
+		executed, err := impl.RunDueSchedules(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, executed)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("pauses after repeated insufficient balance", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		schedule, occurrence := dueSchedule(2)
+		mockDao.On("ListDueSchedules", mock.Anything, 200).Return([]dao.Schedule{schedule}, nil).Once()
+		key := expectClaim(mockDao, occurrence, nil)
+		expectActiveWallets(mockDao, "wallet-1", "wallet-2")
+		mockDao.On("GetBalance", "wallet-1").Return(10.0, nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil).Once()
+		mockDao.On("FinishScheduleRun", key, common.ScheduleRunFailed, mock.MatchedBy(func(msg *string) bool {
+			return msg != nil && *msg == logic.ErrInsufficientBalance.Error()
+		})).Return(nil).Once()
+		mockDao.On("AdvanceSchedule", "sched-1", occurrence, dao.ScheduleAdvance{
+			NextRunAt: &occurrence, LastRunAt: occurrence, FailureCount: 3, Status: common.ScheduleStatusPaused,
+		}).Return(nil).Once()
+
+		executed, err := impl.RunDueSchedules(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, executed)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("occurrence already executed", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		schedule, occurrence := dueSchedule(0)
+		next := occurrence.AddDate(0, 0, 1)
+		mockDao.On("ListDueSchedules", mock.Anything, 200).Return([]dao.Schedule{schedule}, nil).Once()
+		expectClaim(mockDao, occurrence, dao.ErrScheduleRunExists)
+		mockDao.On("AdvanceSchedule", "sched-1", occurrence, dao.ScheduleAdvance{
+			NextRunAt: &next, LastRunAt: occurrence, Status: common.ScheduleStatusActive,
+		}).Return(nil).Once()
+
+		executed, err := impl.RunDueSchedules(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, executed)
+		mockDao.AssertNotCalled(t, "GetBalance", mock.Anything)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("creator is authorized for every occurrence", func(t *testing.T) {
+		spendingCap := 100.0
+		for name, tc := range map[string]struct {
+			member    *dao.WalletMember
+			memberErr error
+			err       error
+		}{
+			"spending cap used up": {member: member("spender", common.MemberRoleSpender, common.MemberStatusActive, &spendingCap), err: logic.ErrSpendingCapExceeded},
+			"member removed":       {memberErr: dao.ErrMemberNotFound, err: logic.ErrWalletAccessDenied},
+		} {
+			t.Run(name, func(t *testing.T) {
+				impl, mockDao := setupLogicTest()
+				schedule, occurrence := dueSchedule(0)
+				schedule.CreatedBy = "spender"
+				next := occurrence.AddDate(0, 0, 1)
+				mockDao.On("ListDueSchedules", mock.Anything, 200).Return([]dao.Schedule{schedule}, nil).Once()
+				key := expectClaim(mockDao, occurrence, nil)
+				expectActiveWallets(mockDao, "wallet-1")
+				mockDao.On("GetWalletMember", "wallet-1", "spender").Return(tc.member, tc.memberErr).Once()
+				mockDao.On("SumMemberSpending", "wallet-1", "spender", mock.Anything).Return(90.0, nil).Maybe()
+				mockDao.On("FinishScheduleRun", key, common.ScheduleRunFailed, mock.MatchedBy(func(msg *string) bool {
+					return msg != nil && *msg == tc.err.Error()
+				})).Return(nil).Once()
+				mockDao.On("AdvanceSchedule", "sched-1", occurrence, dao.ScheduleAdvance{
+					NextRunAt: &next, LastRunAt: occurrence, FailureCount: 1, Status: common.ScheduleStatusActive,
+				}).Return(nil).Once()
+
+				executed, err := impl.RunDueSchedules(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, executed)
+				mockDao.AssertNotCalled(t, "PostTransfers", mock.Anything)
+				mockDao.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("unexpected error is retried", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		schedule, occurrence := dueSchedule(0)
+		mockDao.On("ListDueSchedules", mock.Anything, 200).Return([]dao.Schedule{schedule}, nil).Once()
+		key := expectClaim(mockDao, occurrence, nil)
+		mockDao.On("GetBalance", "wallet-1").Return(0.0, errors.New("connection reset")).Once()
+		mockDao.On("FinishScheduleRun", key, common.ScheduleRunError, mock.Anything).Return(nil).Once()
+
+		executed, err := impl.RunDueSchedules(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, executed)
+		mockDao.AssertNotCalled(t, "AdvanceSchedule", mock.Anything, mock.Anything, mock.Anything)
+		mockDao.AssertExpectations(t)
+	})
+}
+
+func TestScheduledTransferStatus(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("resume from now", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		schedule, _ := dueSchedule(3)
+		schedule.Status = common.ScheduleStatusPaused
+		mockDao.On("GetSchedule", "sched-1").Return(&schedule, nil).Once()
+		mockDao.On("UpdateScheduleStatus", "sched-1", []string{common.ScheduleStatusPaused}, common.ScheduleStatusActive,
+			mock.MatchedBy(func(next *time.Time) bool { return next != nil && next.After(time.Now()) })).Return(nil).Once()
+
+		resumed, err := impl.ResumeScheduledTransfer(ctx, "sched-1", "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, common.ScheduleStatusActive, resumed.Status)
+		assert.Equal(t, 0, resumed.FailureCount)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("cancelled schedule cannot be paused", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		schedule, _ := dueSchedule(0)
+		schedule.Status = common.ScheduleStatusCancelled
+		mockDao.On("GetSchedule", "sched-1").Return(&schedule, nil).Once()
+
+		_, err := impl.PauseScheduledTransfer(ctx, "sched-1", "user-1")
+		assert.ErrorIs(t, err, logic.ErrScheduleStatusConflict)
+		mockDao.AssertNotCalled(t, "UpdateScheduleStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown schedule", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetSchedule", "sched-2").Return(nil, dao.ErrScheduleNotFound).Once()
+
+		_, err := impl.CancelScheduledTransfer(ctx, "sched-2", "user-1")
+		assert.ErrorIs(t, err, logic.ErrScheduleNotFound)
+	})
+}
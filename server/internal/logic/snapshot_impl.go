@@ -0,0 +1,51 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+)
+
+// snapshotLag keeps snapshots clear of transactions that are still being written.
+const snapshotLag = time.Minute
+
+var (
+	ErrAsOfInFuture = errors.New("as_of must not be in the future")
+)
+
+// GetBalanceAsOf returns the wallet balance at a past point in time, computed from the
+// transaction history. Before the wallet existed its balance is zero.
+func (l *WalletImpl) GetBalanceAsOf(ctx context.Context, walletID string, asOf time.Time) (float64, error) {
+	if asOf.After(time.Now()) {
+		return 0, ErrAsOfInFuture
+	}
+
+	if _, err := l.dao.GetWalletByID(walletID); err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return 0, ErrWalletNotFound
+		}
+		return 0, fmt.Errorf("balance as of failed: %w", err)
+	}
+
+	balance, err := l.dao.GetBalanceAsOf(walletID, asOf)
+	if err != nil {
+		l.logger.WithError(err).Errorf("Failed to get balance of wallet %s as of %s", walletID, asOf)
+		return 0, fmt.Errorf("balance as of failed: %w", err)
+	}
+	return common.RoundToNDecimals(balance, 4), nil
+}
+
+// SnapshotBalances records the ledger balance of every wallet so as-of queries only need to
+// scan the transactions since the latest snapshot.
+func (l *WalletImpl) SnapshotBalances(ctx context.Context) (int64, error) {
+	count, err := l.dao.CreateBalanceSnapshots(time.Now().Add(-snapshotLag).Truncate(time.Second))
+	if err != nil {
+		return 0, err
+	}
+	l.logger.Infof("Created %d balance snapshots", count)
+	return count, nil
+}
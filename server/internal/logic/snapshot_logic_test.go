@@ -0,0 +1,68 @@
+package logic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetBalanceAsOf(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	ctx := context.TODO()
+	asOf := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	t.Run("balance from history", func(t *testing.T) {
+		mockDao.On("GetWalletByID", "wallet-1").Return(&dao.Wallet{ID: "wallet-1"}, nil).Once()
+		mockDao.On("GetBalanceAsOf", "wallet-1", asOf).Return(80.123456, nil).Once()
+
+		balance, err := impl.GetBalanceAsOf(ctx, "wallet-1", asOf)
+		assert.NoError(t, err)
+		assert.Equal(t, 80.1235, balance)
+	})
+
+	t.Run("unknown wallet", func(t *testing.T) {
+		mockDao.On("GetWalletByID", "wallet-missing").Return(nil, dao.ErrWalletNotFound).Once()
+
+		_, err := impl.GetBalanceAsOf(ctx, "wallet-missing", asOf)
+		assert.Equal(t, logic.ErrWalletNotFound, err)
+	})
+
+	t.Run("future as_of", func(t *testing.T) {
+		_, err := impl.GetBalanceAsOf(ctx, "wallet-1", time.Now().Add(time.Hour))
+		assert.Equal(t, logic.ErrAsOfInFuture, err)
+	})
+}
+
+func TestSnapshotBalances(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+
+	mockDao.On("CreateBalanceSnapshots", mock.MatchedBy(func(asOf time.Time) bool {
+		return asOf.Before(time.Now().Add(-59 * time.Second))
+	})).Return(int64(5), nil).Once()
+
+	count, err := impl.SnapshotBalances(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+}
+
+func TestTransferAuditCarriesBalanceAfter(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	expectActiveWallets(mockDao, "wallet-from", "wallet-to")
+
+	mockDao.On("GetBalance", "wallet-from").Return(100.0, nil).Once()
+	mockDao.On("GetUserByWalletID", "wallet-from").Return(fullKycUser, nil).Once()
+	mockDao.On("GetBalance", "wallet-to").Return(5.0, nil).Once()
+	mockDao.On("PostTransfers", mock.Anything).Return(nil).Once()
+
+	_, err := impl.Transfer(context.TODO(), "wallet-from", "wallet-to", 25)
+	assert.NoError(t, err)
+	mockDao.AssertExpectations(t)
+	mockDao.AssertCalled(t, "AppendAuditLog", mock.MatchedBy(func(entry *dao.AuditLog) bool {
+		return entry.Details == `{"amount":25,"from_balance":75,"to_balance":30,"to_wallet_id":"wallet-to"}`
+	}))
+}
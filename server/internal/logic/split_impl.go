@@ -0,0 +1,262 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+)
+
+var (
+	ErrInvalidSplit          = errors.New("invalid split payment")
+	ErrSplitRequiresApproval = errors.New("amount requires approval and cannot be split")
+	ErrSplitNeedsReview      = errors.New("split payment needs risk review, which it cannot wait for")
+)
+
+const (
+	// MaxSplitRecipients bounds the recipients of one split payment.
+	MaxSplitRecipients = 100
+	// splitUnitsPerAmount is how many of the smallest amounts, 0.0001, make up 1.
+	splitUnitsPerAmount = 10000
+)
+
+// SplitShare is one recipient of a split payment, paid either a fixed Amount or a Percent of the
+// payment's amount.
+type SplitShare struct {
+	ToWalletID string
+	Amount     *float64
+	Percent    *float64
+}
+
+// SplitPaymentRequest pays Amount out of one wallet to many. With fixed shares Amount may be
+// zero, in which case it is their sum.
+type SplitPaymentRequest struct {
+	FromWalletID string
+	Amount       float64
+	Shares       []SplitShare
+}
+
+// SplitLeg is what one recipient of a split payment was paid; TransactionID is the sender's row.
+type SplitLeg struct {
+	ToWalletID    string   `json:"to_wallet_id"`
+	Amount        float64  `json:"amount"`
+	Percent       *float64 `json:"percent,omitempty"`
+	TransactionID string   `json:"transaction_id"`
+}
+
+// SplitPaymentResult is a posted split payment. Every row it wrote, fees included, carries GroupID.
+type SplitPaymentResult struct {
+	GroupID      string     `json:"group_id"`
+	FromWalletID string     `json:"from_wallet_id"`
+	Amount       float64    `json:"amount"`
+	Fee          float64    `json:"fee"`
+	Legs         []SplitLeg `json:"legs"`
+}
+
+// SplitPayment debits one wallet and credits each share in one DB transaction: either every
+// recipient is paid or none is. The KYC transfer limit applies to the whole amount; each leg is
+// screened and charged the transfer fee like a transfer of its own.
+func (l *WalletImpl) SplitPayment(ctx context.Context, req SplitPaymentRequest) (*SplitPaymentResult, error) {
+	if len(req.Shares) == 0 || len(req.Shares) > MaxSplitRecipients {
+		return nil, fmt.Errorf("%w: a split has 1 to %d recipients", ErrInvalidSplit, MaxSplitRecipients)
+	}
+	seen := make(map[string]bool, len(req.Shares))
+	for i, share := range req.Shares {
+		if share.ToWalletID == req.FromWalletID {
+			return nil, fmt.Errorf("%w: share %d pays the sending wallet", ErrInvalidSplit, i+1)
+		}
+		if seen[share.ToWalletID] {
+			return nil, fmt.Errorf("%w: share %d repeats wallet %s", ErrInvalidSplit, i+1, share.ToWalletID)
+		}
+		seen[share.ToWalletID] = true
+	}
+	amounts, total, err := allocateSplit(req.Amount, req.Shares)
+	if err != nil {
+		return nil, err
+	}
+	if l.RequiresApproval(total) {
+		return nil, ErrSplitRequiresApproval
+	}
+	l.logger.Infof("Splitting %.4f from wallet %s between %d wallets", total, req.FromWalletID, len(amounts))
+
+	from, err := l.activeWallet(req.FromWalletID)
+	if err != nil {
+		return nil, err
+	}
+	kycLevel, rule, err := l.kycForWallet(req.FromWalletID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.MaxTransferAmount > 0 && total > rule.MaxTransferAmount {
+		l.logger.Warnf("KYC transfer limit exceeded: requested=%.4f, max=%.4f", total, rule.MaxTransferAmount)
+		return nil, ErrKycTransferLimitExceeded
+	}
+	for i, share := range req.Shares {
+		if err := l.ensureWalletActive(share.ToWalletID); err != nil {
+			return nil, err
+		}
+		if err := l.ScreenTransferParties(ctx, req.FromWalletID, share.ToWalletID); err != nil {
+			return nil, err
+		}
+		if err := l.screenWithoutReview(ctx, req.FromWalletID, share.ToWalletID, amounts[i], ErrSplitNeedsReview); err != nil {
+			return nil, err
+		}
+	}
+
+	groupID := uuid.NewString()
+	result := &SplitPaymentResult{GroupID: groupID, FromWalletID: req.FromWalletID, Amount: total, Legs: make([]SplitLeg, len(amounts))}
+	legs := make([]dao.TransferLeg, len(amounts))
+	var feeLegs []dao.TransferLeg
+	for i, share := range req.Shares {
+		legs[i] = dao.TransferLeg{
+			FromWalletID: req.FromWalletID,
+			ToWalletID:   share.ToWalletID,
+			Amount:       amounts[i],
+			DebitID:      uuid.NewString(),
+			CreditID:     uuid.NewString(),
+			InitiatedBy:  initiator(ctx),
+			GroupID:      &groupID,
+		}
+		result.Legs[i] = SplitLeg{ToWalletID: share.ToWalletID, Amount: amounts[i], Percent: share.Percent, TransactionID: legs[i].DebitID}
+		fee := l.quoteFee(common.TransactionTypeTransfer, kycLevel, req.FromWalletID, amounts[i])
+		if leg := l.feeLeg(req.FromWalletID, legs[i].DebitID, fee); leg != nil {
+			leg.GroupID = &groupID
+			feeLegs = append(feeLegs, *leg)
+			result.Fee += fee.Fee
+		}
+	}
+	result.Fee = common.RoundToNDecimals(result.Fee, 4)
+
+	balance, err := l.dao.GetBalance(req.FromWalletID)
+	if err != nil {
+		return nil, fmt.Errorf("split payment failed: %w", err)
+	}
+	// the overdraft fee is charged once, on what the whole payment overdraws, with the first leg
+	if leg, overdraft := l.overdraftFeeLeg(kycLevel, req.FromWalletID, legs[0].DebitID, balance, total, result.Fee); leg != nil {
+		leg.GroupID = &groupID
+		feeLegs = append(feeLegs, *leg)
+		result.Fee = common.RoundToNDecimals(result.Fee+overdraft, 4)
+	}
+	if balance+from.CreditLimit < total+result.Fee {
+		l.logger.Warnf("Insufficient funds for split payment: current=%.4f, credit_limit=%.4f, requested=%.4f, fee=%.4f",
+			balance, from.CreditLimit, total, result.Fee)
+		return nil, ErrInsufficientBalance
+	}
+
+	if err := l.dao.PostTransfers(append(legs, feeLegs...)); err != nil {
+		if errors.Is(err, dao.ErrInsufficientFunds) {
+			return nil, ErrInsufficientBalance
+		}
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, fmt.Errorf("split payment failed: %w", err)
+	}
+
+	l.recordAudit(ctx, common.AuditActionSplitPayment, common.AuditEntityWallet, req.FromWalletID, map[string]any{
+		"group_id": groupID,
+		"amount":   total,
+		"fee":      result.Fee,
+		"legs":     result.Legs,
+	})
+	return result, nil
+}
+
+// allocateSplit works out what each share is paid and the total. Shares are all fixed amounts,
+// which must add up to amount when it is given, or all percentages of amount, which must add up
+// to 100. Percentage shares are worked out in units of 0.0001 and rounded down; the units left
+// over go one each to the shares with the largest rounded-off fractions, the earlier share first
+// on a tie, so the shares always add up to amount exactly.
+func allocateSplit(amount float64, shares []SplitShare) ([]float64, float64, error) {
+	fixed, percent := 0, 0
+	for i, share := range shares {
+		switch {
+		case share.Amount != nil && share.Percent == nil:
+			if *share.Amount <= 0 {
+				return nil, 0, fmt.Errorf("%w: share %d: amount must be positive", ErrInvalidSplit, i+1)
+			}
+			fixed++
+		case share.Percent != nil && share.Amount == nil:
+			if *share.Percent <= 0 || *share.Percent > 100 {
+				return nil, 0, fmt.Errorf("%w: share %d: percent must be above 0 and at most 100", ErrInvalidSplit, i+1)
+			}
+			percent++
+		default:
+			return nil, 0, fmt.Errorf("%w: share %d needs either an amount or a percent", ErrInvalidSplit, i+1)
+		}
+	}
+	if fixed > 0 && percent > 0 {
+		return nil, 0, fmt.Errorf("%w: shares are all amounts or all percents", ErrInvalidSplit)
+	}
+	if amount < 0 {
+		return nil, 0, fmt.Errorf("%w: amount must be positive", ErrInvalidSplit)
+	}
+	totalUnits := int64(math.Round(amount * splitUnitsPerAmount))
+
+	units := make([]int64, len(shares))
+	if fixed > 0 {
+		var sum int64
+		for i, share := range shares {
+			units[i] = int64(math.Round(*share.Amount * splitUnitsPerAmount))
+			if units[i] == 0 {
+				return nil, 0, fmt.Errorf("%w: share %d: amount must be positive", ErrInvalidSplit, i+1)
+			}
+			sum += units[i]
+		}
+		if totalUnits != 0 && sum != totalUnits {
+			return nil, 0, fmt.Errorf("%w: share amounts add up to %.4f, not %.4f", ErrInvalidSplit, toAmount(sum), toAmount(totalUnits))
+		}
+		totalUnits = sum
+	} else {
+		if totalUnits <= 0 {
+			return nil, 0, fmt.Errorf("%w: amount is required with percent shares", ErrInvalidSplit)
+		}
+		var percents float64
+		for _, share := range shares {
+			percents += *share.Percent
+		}
+		if math.Abs(percents-100) > 1e-9 {
+			return nil, 0, fmt.Errorf("%w: percents add up to %g, not 100", ErrInvalidSplit, percents)
+		}
+
+		fractions := make([]float64, len(shares))
+		var allocated int64
+		for i, share := range shares {
+			exact := float64(totalUnits) * *share.Percent / 100
+			// absorb float error so an exact share is not rounded down a unit
+			units[i] = int64(math.Floor(exact + 1e-9))
+			fractions[i] = exact - float64(units[i])
+			allocated += units[i]
+		}
+		order := make([]int, len(shares))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return fractions[order[a]] > fractions[order[b]] })
+		for i := int64(0); i < totalUnits-allocated; i++ {
+			units[order[i]]++
+		}
+		for i := range units {
+			if units[i] == 0 {
+				return nil, 0, fmt.Errorf("%w: share %d rounds to nothing", ErrInvalidSplit, i+1)
+			}
+		}
+	}
+
+	amounts := make([]float64, len(units))
+	for i, u := range units {
+		amounts[i] = toAmount(u)
+	}
+	return amounts, toAmount(totalUnits), nil
+}
+
+// toAmount turns units of 0.0001 back into an amount.
+func toAmount(units int64) float64 {
+	return common.RoundToNDecimals(float64(units)/splitUnitsPerAmount, 4)
+}
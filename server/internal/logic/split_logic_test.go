@@ -0,0 +1,121 @@
+package logic_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func amountShare(walletID string, amount float64) logic.SplitShare {
+	return logic.SplitShare{ToWalletID: walletID, Amount: &amount}
+}
+
+func percentShare(walletID string, percent float64) logic.SplitShare {
+	return logic.SplitShare{ToWalletID: walletID, Percent: &percent}
+}
+
+func TestSplitPayment(t *testing.T) {
+	ctx := context.TODO()
+
+	t.Run("fixed amounts with a fee per leg", func(t *testing.T) {
+		impl, mockDao := setupFeeTest()
+		expectActiveWallets(mockDao, "wallet-a", "wallet-b", "wallet-c")
+		mockDao.On("GetUserByWalletID", "wallet-a").Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", "wallet-a").Return(100.0, nil).Once()
+		mockDao.On("PostTransfers", mock.MatchedBy(func(legs []dao.TransferLeg) bool {
+			if len(legs) != 4 || legs[0].GroupID == nil {
+				return false
+			}
+			for _, leg := range legs {
+				if leg.GroupID == nil || *leg.GroupID != *legs[0].GroupID {
+					return false
+				}
+			}
+			return legs[0].ToWalletID == "wallet-b" && legs[0].Amount == 30 &&
+				legs[1].ToWalletID == "wallet-c" && legs[1].Amount == 20 &&
+				legs[2].ToWalletID == feeWallet && legs[2].Amount == 0.3 && *legs[2].ParentID == legs[0].DebitID &&
+				legs[3].ToWalletID == feeWallet && legs[3].Amount == 0.2 && *legs[3].ParentID == legs[1].DebitID
+		})).Return(nil).Once()
+
+		result, err := impl.SplitPayment(ctx, logic.SplitPaymentRequest{
+			FromWalletID: "wallet-a",
+			Shares:       []logic.SplitShare{amountShare("wallet-b", 30), amountShare("wallet-c", 20)},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 50.0, result.Amount)
+		assert.Equal(t, 0.5, result.Fee)
+		assert.NotEmpty(t, result.GroupID)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("percent remainders go to the largest fractions", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectActiveWallets(mockDao, "wallet-a", "wallet-b", "wallet-c", "wallet-d")
+		mockDao.On("GetUserByWalletID", "wallet-a").Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", "wallet-a").Return(100.0, nil).Once()
+		mockDao.On("PostTransfers", mock.Anything).Return(nil).Once()
+
+		// 10 split three ways is 3.3333 each, with 0.0001 left over for the first share
+		result, err := impl.SplitPayment(ctx, logic.SplitPaymentRequest{
+			FromWalletID: "wallet-a",
+			Amount:       10,
+			Shares: []logic.SplitShare{
+				percentShare("wallet-b", 100.0/3), percentShare("wallet-c", 100.0/3), percentShare("wallet-d", 100.0/3),
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3.3334, result.Legs[0].Amount)
+		assert.Equal(t, 3.3333, result.Legs[1].Amount)
+		assert.Equal(t, 3.3333, result.Legs[2].Amount)
+
+		// 7 units at 25% and 75% is 1.75 and 5.25, so the leftover unit goes to the 0.75 fraction
+		mockDao.On("GetUserByWalletID", "wallet-a").Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", "wallet-a").Return(100.0, nil).Once()
+		mockDao.On("PostTransfers", mock.Anything).Return(nil).Once()
+		result, err = impl.SplitPayment(ctx, logic.SplitPaymentRequest{
+			FromWalletID: "wallet-a",
+			Amount:       0.0007,
+			Shares:       []logic.SplitShare{percentShare("wallet-b", 25), percentShare("wallet-c", 75)},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 0.0002, result.Legs[0].Amount)
+		assert.Equal(t, 0.0005, result.Legs[1].Amount)
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectActiveWallets(mockDao, "wallet-a", "wallet-b", "wallet-c")
+		mockDao.On("GetUserByWalletID", "wallet-a").Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", "wallet-a").Return(40.0, nil).Once()
+
+		_, err := impl.SplitPayment(ctx, logic.SplitPaymentRequest{
+			FromWalletID: "wallet-a",
+			Shares:       []logic.SplitShare{amountShare("wallet-b", 30), amountShare("wallet-c", 20)},
+		})
+		assert.ErrorIs(t, err, logic.ErrInsufficientBalance)
+		mockDao.AssertNotCalled(t, "PostTransfers", mock.Anything)
+	})
+
+	t.Run("invalid splits", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		for name, req := range map[string]logic.SplitPaymentRequest{
+			"no shares":        {FromWalletID: "wallet-a", Amount: 10},
+			"pays the sender":  {FromWalletID: "wallet-a", Shares: []logic.SplitShare{amountShare("wallet-a", 10)}},
+			"repeated wallet":  {FromWalletID: "wallet-a", Shares: []logic.SplitShare{amountShare("wallet-b", 5), amountShare("wallet-b", 5)}},
+			"mixed shares":     {FromWalletID: "wallet-a", Amount: 10, Shares: []logic.SplitShare{amountShare("wallet-b", 5), percentShare("wallet-c", 50)}},
+			"amount mismatch":  {FromWalletID: "wallet-a", Amount: 10, Shares: []logic.SplitShare{amountShare("wallet-b", 5), amountShare("wallet-c", 4)}},
+			"percents short":   {FromWalletID: "wallet-a", Amount: 10, Shares: []logic.SplitShare{percentShare("wallet-b", 50), percentShare("wallet-c", 40)}},
+			"percent no total": {FromWalletID: "wallet-a", Shares: []logic.SplitShare{percentShare("wallet-b", 100)}},
+			"share rounds to nothing": {FromWalletID: "wallet-a", Amount: 0.0001,
+				Shares: []logic.SplitShare{percentShare("wallet-b", 50), percentShare("wallet-c", 50)}},
+		} {
+			_, err := impl.SplitPayment(ctx, req)
+			assert.ErrorIs(t, err, logic.ErrInvalidSplit, name)
+		}
+		mockDao.AssertNotCalled(t, "PostTransfers", mock.Anything)
+	})
+}
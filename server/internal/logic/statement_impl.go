@@ -0,0 +1,162 @@
+package logic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/statement"
+)
+
+var (
+	ErrStatementNotFound   = errors.New("statement not found")
+	ErrStatementPeriodOpen = errors.New("statement period has not ended yet")
+)
+
+// GenerateStatement returns the wallet's statement for the calendar month (UTC) containing month,
+// generating and storing it the first time it is asked for.
+func (l *WalletImpl) GenerateStatement(ctx context.Context, walletID string, month time.Time) (*dao.Statement, error) {
+	start, end := statement.MonthPeriod(month)
+	if end.Add(snapshotLag).After(time.Now()) {
+		return nil, ErrStatementPeriodOpen
+	}
+
+	if _, err := l.dao.GetWalletByID(walletID); err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, fmt.Errorf("statement failed: %w", err)
+	}
+
+	existing, err := l.dao.GetStatementForPeriod(walletID, start)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, dao.ErrStatementNotFound) {
+		return nil, fmt.Errorf("statement failed: %w", err)
+	}
+
+	// timestamps are stored with microsecond precision, so these are the last instants
+	// before and inside the period
+	beforeStart, lastInPeriod := start.Add(-time.Microsecond), end.Add(-time.Microsecond)
+
+	opening, err := l.dao.GetBalanceAsOf(walletID, beforeStart)
+	if err != nil {
+		l.logger.WithError(err).Errorf("Failed to get opening balance of wallet %s", walletID)
+		return nil, fmt.Errorf("statement failed: %w", err)
+	}
+
+	var txs []dao.Transaction
+	err = l.dao.StreamTransactionHistory(dao.TransactionFilter{
+		WalletID:  walletID,
+		Start:     &start,
+		End:       &lastInPeriod,
+		Ascending: true,
+	}, func(tx *dao.Transaction) error {
+		txs = append(txs, *tx)
+		return nil
+	})
+	if err != nil {
+		l.logger.WithError(err).Errorf("Failed to read transactions of wallet %s", walletID)
+		return nil, fmt.Errorf("statement failed: %w", err)
+	}
+
+	doc := statement.Build(uuid.NewString(), walletID, start, end, opening, txs)
+	doc.GeneratedAt = time.Now().UTC()
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("statement failed: %w", err)
+	}
+
+	record := &dao.Statement{
+		ID:               doc.ID,
+		WalletID:         walletID,
+		PeriodStart:      start,
+		PeriodEnd:        end,
+		OpeningBalance:   doc.OpeningBalance,
+		ClosingBalance:   doc.ClosingBalance,
+		TransactionCount: len(doc.Lines),
+		Document:         string(raw),
+		CreatedAt:        doc.GeneratedAt,
+	}
+	if err := l.dao.CreateStatement(record); err != nil {
+		if errors.Is(err, dao.ErrStatementExists) {
+			// generated concurrently, keep the stored one
+			return l.dao.GetStatementForPeriod(walletID, start)
+		}
+		return nil, fmt.Errorf("statement failed: %w", err)
+	}
+
+	l.logger.Infof("Generated statement %s for wallet %s, %s", record.ID, walletID, start.Format("2006-01"))
+	return record, nil
+}
+
+// GenerateMonthlyStatements generates last month's statement for every wallet that does not have
+// one yet, so a run missed by downtime is caught up by the next one. It returns how many were generated.
+func (l *WalletImpl) GenerateMonthlyStatements(ctx context.Context) (int, error) {
+	current, _ := statement.MonthPeriod(time.Now())
+	start, end := statement.MonthPeriod(current.Add(-time.Nanosecond))
+	if end.Add(snapshotLag).After(time.Now()) {
+		return 0, nil
+	}
+
+	walletIDs, err := l.dao.ListWalletsWithoutStatement(start, end)
+	if err != nil {
+		return 0, err
+	}
+
+	generated := 0
+	for _, walletID := range walletIDs {
+		if _, err := l.GenerateStatement(ctx, walletID, start); err != nil {
+			l.logger.WithError(err).Errorf("Failed to generate statement for wallet %s", walletID)
+			continue
+		}
+		generated++
+	}
+	if generated > 0 {
+		l.logger.Infof("Generated %d statements for %s", generated, start.Format("2006-01"))
+	}
+	return generated, nil
+}
+
+// ListStatements returns the wallet's stored statements, newest first.
+func (l *WalletImpl) ListStatements(ctx context.Context, walletID string) ([]dao.Statement, error) {
+	if _, err := l.dao.GetWalletByID(walletID); err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, err
+	}
+
+	statements, err := l.dao.ListStatements(walletID)
+	if err != nil {
+		return nil, err
+	}
+	if statements == nil {
+		statements = []dao.Statement{}
+	}
+	return statements, nil
+}
+
+// GetStatement returns a stored statement exactly as it was generated.
+func (l *WalletImpl) GetStatement(ctx context.Context, statementID string) (*statement.Statement, error) {
+	record, err := l.dao.GetStatement(statementID)
+	if err != nil {
+		if errors.Is(err, dao.ErrStatementNotFound) {
+			return nil, ErrStatementNotFound
+		}
+		return nil, err
+	}
+
+	var doc statement.Statement
+	if err := json.Unmarshal([]byte(record.Document), &doc); err != nil {
+		l.logger.WithError(err).Errorf("Stored statement %s is unreadable", statementID)
+		return nil, fmt.Errorf("statement %s is corrupt: %w", statementID, err)
+	}
+	return &doc, nil
+}
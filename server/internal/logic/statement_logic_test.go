@@ -0,0 +1,137 @@
+package logic_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/statement"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGenerateStatement(t *testing.T) {
+	ctx := context.TODO()
+	march := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	start, end := statement.MonthPeriod(march)
+
+	t.Run("computes and stores the period", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectActiveWallets(mockDao, "wallet-1")
+		mockDao.On("GetStatementForPeriod", "wallet-1", start).Return(nil, dao.ErrStatementNotFound).Once()
+		mockDao.On("GetBalanceAsOf", "wallet-1", start.Add(-time.Microsecond)).Return(40.0, nil).Once()
+		mockDao.On("StreamTransactionHistory", mock.MatchedBy(func(f dao.TransactionFilter) bool {
+			return f.WalletID == "wallet-1" && f.Start.Equal(start) && f.End.Equal(end.Add(-time.Microsecond)) && f.Ascending
+		}), mock.Anything).Run(func(args mock.Arguments) {
+			fn := args.Get(1).(func(*dao.Transaction) error)
+			_ = fn(&dao.Transaction{ID: "tx-1", Type: "deposit", Amount: 100, CreatedAt: start.Add(time.Hour)})
+			_ = fn(&dao.Transaction{ID: "tx-2", Type: "withdraw", Amount: 15, CreatedAt: start.Add(2 * time.Hour)})
+		}).Return(nil).Once()
+
+		var stored *dao.Statement
+		mockDao.On("CreateStatement", mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(0).(*dao.Statement)
+		}).Return(nil).Once()
+
+		record, err := impl.GenerateStatement(ctx, "wallet-1", march)
+		assert.NoError(t, err)
+		assert.Same(t, stored, record)
+		assert.Equal(t, 40.0, record.OpeningBalance)
+		assert.Equal(t, 125.0, record.ClosingBalance)
+		assert.Equal(t, 2, record.TransactionCount)
+
+		var doc statement.Statement
+		assert.NoError(t, json.Unmarshal([]byte(record.Document), &doc))
+		assert.Equal(t, record.ID, doc.ID)
+		assert.Equal(t, 15.0, doc.Totals["withdraw"].Debits)
+	})
+
+	t.Run("existing statement returned as is", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectActiveWallets(mockDao, "wallet-1")
+		existing := &dao.Statement{ID: "stmt-1"}
+		mockDao.On("GetStatementForPeriod", "wallet-1", start).Return(existing, nil).Once()
+
+		record, err := impl.GenerateStatement(ctx, "wallet-1", march)
+		assert.NoError(t, err)
+		assert.Same(t, existing, record)
+		mockDao.AssertNotCalled(t, "CreateStatement", mock.Anything)
+	})
+
+	t.Run("concurrent generation keeps the stored one", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectActiveWallets(mockDao, "wallet-1")
+		existing := &dao.Statement{ID: "stmt-1"}
+		mockDao.On("GetStatementForPeriod", "wallet-1", start).Return(nil, dao.ErrStatementNotFound).Once()
+		mockDao.On("GetBalanceAsOf", "wallet-1", mock.Anything).Return(0.0, nil).Once()
+		mockDao.On("StreamTransactionHistory", mock.Anything, mock.Anything).Return(nil).Once()
+		mockDao.On("CreateStatement", mock.Anything).Return(dao.ErrStatementExists).Once()
+		mockDao.On("GetStatementForPeriod", "wallet-1", start).Return(existing, nil).Once()
+
+		record, err := impl.GenerateStatement(ctx, "wallet-1", march)
+		assert.NoError(t, err)
+		assert.Same(t, existing, record)
+	})
+
+	t.Run("current month is not ready", func(t *testing.T) {
+		impl, _ := setupLogicTest()
+		_, err := impl.GenerateStatement(ctx, "wallet-1", time.Now())
+		assert.ErrorIs(t, err, logic.ErrStatementPeriodOpen)
+	})
+
+	t.Run("unknown wallet", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("GetWalletByID", "missing").Return(nil, dao.ErrWalletNotFound).Once()
+		_, err := impl.GenerateStatement(ctx, "missing", march)
+		assert.ErrorIs(t, err, logic.ErrWalletNotFound)
+	})
+}
+
+func TestGenerateMonthlyStatements(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	current, _ := statement.MonthPeriod(time.Now())
+	start, end := statement.MonthPeriod(current.Add(-time.Nanosecond))
+	if time.Since(current) < time.Minute {
+		t.Skip("the previous month only closes once the snapshot lag has passed")
+	}
+
+	mockDao.On("ListWalletsWithoutStatement", start, end).Return([]string{"wallet-1", "missing"}, nil).Once()
+	expectActiveWallets(mockDao, "wallet-1")
+	mockDao.On("GetWalletByID", "missing").Return(nil, dao.ErrWalletNotFound).Once()
+	mockDao.On("GetStatementForPeriod", "wallet-1", start).Return(&dao.Statement{ID: "stmt-1"}, nil).Once()
+
+	generated, err := impl.GenerateMonthlyStatements(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, generated)
+}
+
+func TestGetStatement(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	ctx := context.TODO()
+
+	mockDao.On("GetStatement", "stmt-1").Return(&dao.Statement{ID: "stmt-1", Document: `{"id":"stmt-1","wallet_id":"wallet-1","closing_balance":12.5}`}, nil).Once()
+	doc, err := impl.GetStatement(ctx, "stmt-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 12.5, doc.ClosingBalance)
+
+	mockDao.On("GetStatement", "missing").Return(nil, dao.ErrStatementNotFound).Once()
+	_, err = impl.GetStatement(ctx, "missing")
+	assert.ErrorIs(t, err, logic.ErrStatementNotFound)
+
+	mockDao.On("GetStatement", "broken").Return(&dao.Statement{Document: "{"}, nil).Once()
+	_, err = impl.GetStatement(ctx, "broken")
+	assert.Error(t, err)
+}
+
+func TestListStatements(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	expectActiveWallets(mockDao, "wallet-1")
+	mockDao.On("ListStatements", "wallet-1").Return(nil, nil).Once()
+
+	statements, err := impl.ListStatements(context.TODO(), "wallet-1")
+	assert.NoError(t, err)
+	assert.NotNil(t, statements)
+}
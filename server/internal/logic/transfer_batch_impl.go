@@ -0,0 +1,380 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dao "github.com/julkhong/walletapp/server/internal/dao"
+)
+
+var (
+	ErrTransferBatchNotFound  = errors.New("transfer batch not found")
+	ErrInvalidTransferBatch   = errors.New("invalid transfer batch")
+	ErrBatchItemNeedsReview   = errors.New("transfer needs risk review, which an all-or-nothing batch cannot wait for")
+	ErrBatchItemNeedsApproval = errors.New("amount requires approval and cannot be paid in a batch")
+	ErrBatchItemRejected      = errors.New("transfer rejected by risk review")
+)
+
+const (
+	// MaxTransferBatchItems bounds the recipients of one batch.
+	MaxTransferBatchItems = 1000
+	// maxPendingBatches bounds the batches one processing pass executes.
+	maxPendingBatches = 20
+	// batchStallTimeout is how long a batch stays processing before another pass resumes it.
+	batchStallTimeout = 15 * time.Minute
+)
+
+type TransferBatchItemRequest struct {
+	ToWalletID string
+	Amount     float64
+	Reference  string
+}
+
+type TransferBatchRequest struct {
+	FromWalletID   string
+	Mode           string
+	Items          []TransferBatchItemRequest
+	IdempotencyKey string
+	CreatedBy      string
+}
+
+// TransferBatchResult is a batch with its items.
+type TransferBatchResult struct {
+	dao.TransferBatch
+	Items []dao.TransferBatchItem `json:"items"`
+}
+
+// CreateTransferBatch stores a batch for the batch job to execute. A repeated idempotency key
+// returns the batch created by the first request.
+func (l *WalletImpl) CreateTransferBatch(ctx context.Context, req TransferBatchRequest) (*TransferBatchResult, error) {
+	if req.Mode != common.BatchModeAllOrNothing && req.Mode != common.BatchModeBestEffort {
+		return nil, fmt.Errorf("%w: mode must be %s or %s", ErrInvalidTransferBatch, common.BatchModeAllOrNothing, common.BatchModeBestEffort)
+	}
+	if len(req.Items) == 0 || len(req.Items) > MaxTransferBatchItems {
+		return nil, fmt.Errorf("%w: a batch has 1 to %d items", ErrInvalidTransferBatch, MaxTransferBatchItems)
+	}
+	if err := l.ensureWalletActive(req.FromWalletID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	batch := &dao.TransferBatch{
+		ID:             uuid.NewString(),
+		FromWalletID:   req.FromWalletID,
+		Mode:           req.Mode,
+		Status:         common.BatchStatusPending,
+		IdempotencyKey: req.IdempotencyKey,
+		ItemCount:      len(req.Items),
+		CreatedBy:      req.CreatedBy,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	items := make([]dao.TransferBatchItem, len(req.Items))
+	for i, item := range req.Items {
+		amount := common.RoundToNDecimals(item.Amount, 4)
+		if amount <= 0 {
+			return nil, fmt.Errorf("%w: item %d: amount must be positive", ErrInvalidTransferBatch, i+1)
+		}
+		if item.ToWalletID == req.FromWalletID {
+			return nil, fmt.Errorf("%w: item %d: recipient is the source wallet", ErrInvalidTransferBatch, i+1)
+		}
+		items[i] = dao.TransferBatchItem{
+			ID:         uuid.NewString(),
+			BatchID:    batch.ID,
+			Seq:        i + 1,
+			ToWalletID: item.ToWalletID,
+			Amount:     amount,
+			Reference:  item.Reference,
+			Status:     common.BatchItemPending,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		batch.TotalAmount = common.RoundToNDecimals(batch.TotalAmount+amount, 4)
+	}
+
+	if err := l.dao.CreateTransferBatch(batch, items); err != nil {
+		if errors.Is(err, dao.ErrTransferBatchExists) {
+			existing, err := l.dao.GetTransferBatchByKey(req.IdempotencyKey)
+			if err != nil {
+				return nil, err
+			}
+			return l.GetTransferBatch(ctx, existing.ID)
+		}
+		return nil, fmt.Errorf("failed to create transfer batch: %w", err)
+	}
+
+	l.recordAudit(ctx, common.AuditActionBatchCreated, common.AuditEntityBatch, batch.ID, map[string]any{
+		"from_wallet_id": batch.FromWalletID,
+		"mode":           batch.Mode,
+		"item_count":     batch.ItemCount,
+		"total_amount":   batch.TotalAmount,
+	})
+	return &TransferBatchResult{TransferBatch: *batch, Items: items}, nil
+}
+
+func (l *WalletImpl) GetTransferBatch(ctx context.Context, batchID string) (*TransferBatchResult, error) {
+	batch, err := l.dao.GetTransferBatch(batchID)
+	if err != nil {
+		if errors.Is(err, dao.ErrTransferBatchNotFound) {
+			return nil, ErrTransferBatchNotFound
+		}
+		return nil, err
+	}
+	items, err := l.dao.ListTransferBatchItems(batchID)
+	if err != nil {
+		return nil, err
+	}
+	return &TransferBatchResult{TransferBatch: *batch, Items: items}, nil
+}
+
+// ProcessTransferBatches executes pending batches, and resumes batches left processing by an
+// interrupted pass, and returns how many it finished. A best-effort item is paid and marked
+// succeeded in one DB transaction, so a resumed batch pays only the items still pending.
+func (l *WalletImpl) ProcessTransferBatches(ctx context.Context) (int, error) {
+	before := time.Now().Add(-batchStallTimeout)
+	stalled, err := l.dao.ListStalledTransferBatches(before, maxPendingBatches)
+	if err != nil {
+		return 0, err
+	}
+	finished, err := l.runTransferBatches(ctx, stalled, func(batchID string) error {
+		return l.dao.ReclaimTransferBatch(batchID, before)
+	})
+	if err != nil {
+		return finished, err
+	}
+
+	batches, err := l.dao.ListPendingTransferBatches(maxPendingBatches)
+	if err != nil {
+		return finished, err
+	}
+	pending, err := l.runTransferBatches(ctx, batches, l.dao.ClaimTransferBatch)
+	return finished + pending, err
+}
+
+// runTransferBatches executes the batches claim hands to this pass.
+func (l *WalletImpl) runTransferBatches(ctx context.Context, batches []dao.TransferBatch, claim func(batchID string) error) (int, error) {
+	finished := 0
+	for i := range batches {
+		batch := &batches[i]
+		if err := claim(batch.ID); err != nil {
+			if errors.Is(err, dao.ErrTransferBatchNotFound) {
+				continue
+			}
+			return finished, err
+		}
+		batch.Status = common.BatchStatusProcessing
+
+		if err := l.executeTransferBatch(ctx, batch); err != nil {
+			l.logger.WithError(err).Errorf("Transfer batch %s failed", batch.ID)
+			continue
+		}
+		finished++
+	}
+	return finished, nil
+}
+
+func (l *WalletImpl) executeTransferBatch(ctx context.Context, batch *dao.TransferBatch) error {
+	items, err := l.dao.ListTransferBatchItems(batch.ID)
+	if err != nil {
+		return err
+	}
+	// the batch's payments are its creator's spending
+	spendCtx := withInitiator(ctx, batch.CreatedBy)
+	if batch.Mode == common.BatchModeAllOrNothing {
+		err = l.executeAllOrNothing(spendCtx, batch, items)
+	} else {
+		err = l.executeBestEffort(spendCtx, batch, items)
+	}
+	if err != nil {
+		return err
+	}
+
+	l.recordAudit(ctx, common.AuditActionBatchCompleted, common.AuditEntityBatch, batch.ID, map[string]any{
+		"status":          batch.Status,
+		"succeeded_count": batch.SucceededCount,
+		"failed_count":    batch.FailedCount,
+	})
+	return nil
+}
+
+// executeBestEffort pays each pending item with an ordinary transfer posted together with the
+// item's outcome. Items settled before the batch was interrupted are only counted.
+func (l *WalletImpl) executeBestEffort(ctx context.Context, batch *dao.TransferBatch, items []dao.TransferBatchItem) error {
+	for _, item := range items {
+		switch item.Status {
+		case common.BatchItemSucceeded:
+			batch.SucceededCount++
+			continue
+		case common.BatchItemFailed:
+			batch.FailedCount++
+			continue
+		case common.BatchItemReview:
+			continue
+		}
+
+		var itemErr error
+		if l.RequiresApproval(item.Amount) {
+			itemErr = ErrBatchItemNeedsApproval
+		} else {
+			itemErr = l.authorizeCreator(ctx, batch.FromWalletID, batch.CreatedBy, item.Amount)
+			if itemErr == nil {
+				itemErr = l.ScreenTransferParties(ctx, batch.FromWalletID, item.ToWalletID)
+			}
+			if itemErr == nil {
+				_, _, itemErr = l.transferWith(ctx, batch.FromWalletID, item.ToWalletID, item.Amount, true, func(legs []dao.TransferLeg) error {
+					return l.dao.PostTransferBatchItem(item.ID, legs)
+				})
+			}
+		}
+
+		var pending *PendingReviewError
+		switch {
+		case itemErr == nil:
+			batch.SucceededCount++
+		case errors.Is(itemErr, dao.ErrBatchItemNotPending):
+			// settled by another pass that resumed the batch meanwhile, which counts it
+			l.logger.Warnf("Batch item %s was settled by another pass", item.ID)
+		case errors.As(itemErr, &pending):
+			if err := l.dao.ParkTransferBatchItem(item.ID, pending.ReviewID); err != nil {
+				l.logger.WithError(err).Errorf("Failed to record the review of batch item %s", item.ID)
+			}
+		default:
+			batch.FailedCount++
+			if err := l.dao.UpdateTransferBatchItem(item.ID, common.BatchItemFailed, errorMessage(itemErr), nil); err != nil {
+				l.logger.WithError(err).Errorf("Failed to record the outcome of batch item %s", item.ID)
+			}
+		}
+	}
+
+	switch {
+	case batch.FailedCount == 0:
+		batch.Status = common.BatchStatusCompleted
+	case batch.FailedCount == batch.ItemCount:
+		batch.Status = common.BatchStatusFailed
+	default:
+		batch.Status = common.BatchStatusPartial
+	}
+	return l.finishBatch(batch, nil)
+}
+
+// executeAllOrNothing checks every item against the transfer rules first and then posts all of
+// them, with their fees, in one DB transaction. A single failing item fails the whole batch.
+func (l *WalletImpl) executeAllOrNothing(ctx context.Context, batch *dao.TransferBatch, items []dao.TransferBatchItem) error {
+	fail := func(reason error) error {
+		batch.Status = common.BatchStatusFailed
+		return l.finishBatch(batch, reason)
+	}
+
+	kycLevel, rule, err := l.kycForWallet(batch.FromWalletID)
+	if err == nil {
+		err = l.ensureWalletActive(batch.FromWalletID)
+	}
+	if err == nil {
+		err = l.authorizeCreator(ctx, batch.FromWalletID, batch.CreatedBy, batch.TotalAmount)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	for _, item := range items {
+		itemErr := l.checkBatchItem(ctx, batch, item, rule)
+		if itemErr == nil {
+			continue
+		}
+		batch.FailedCount++
+		if err := l.dao.UpdateTransferBatchItem(item.ID, common.BatchItemFailed, errorMessage(itemErr), nil); err != nil {
+			l.logger.WithError(err).Errorf("Failed to record the outcome of batch item %s", item.ID)
+		}
+	}
+	if batch.FailedCount > 0 {
+		return fail(fmt.Errorf("%d of %d items failed", batch.FailedCount, batch.ItemCount))
+	}
+
+	itemIDs := make([]string, len(items))
+	legs := make([]dao.TransferLeg, len(items))
+	var feeLegs []dao.TransferLeg
+	var feeTotal float64
+	for i, item := range items {
+		itemIDs[i] = item.ID
+		legs[i] = dao.TransferLeg{
+			FromWalletID: batch.FromWalletID,
+			ToWalletID:   item.ToWalletID,
+			Amount:       item.Amount,
+			DebitID:      uuid.NewString(),
+			CreditID:     uuid.NewString(),
+			InitiatedBy:  initiator(ctx),
+		}
+		fee := l.quoteFee(common.TransactionTypeTransfer, kycLevel, batch.FromWalletID, item.Amount)
+		if leg := l.feeLeg(batch.FromWalletID, legs[i].DebitID, fee); leg != nil {
+			feeLegs = append(feeLegs, *leg)
+			feeTotal += fee.Fee
+		}
+	}
+	if l.feesEnabled() {
+		// the overdraft fee is charged once, on what the whole batch overdraws, with the first item
+		balance, err := l.dao.GetBalance(batch.FromWalletID)
+		if err != nil {
+			return fail(fmt.Errorf("transfer failed: %w", err))
+		}
+		if leg, _ := l.overdraftFeeLeg(kycLevel, batch.FromWalletID, legs[0].DebitID, balance, batch.TotalAmount, feeTotal); leg != nil {
+			feeLegs = append(feeLegs, *leg)
+		}
+	}
+	legs = append(legs, feeLegs...)
+
+	now := time.Now()
+	batch.Status = common.BatchStatusCompleted
+	batch.SucceededCount = len(items)
+	batch.UpdatedAt = now
+	batch.CompletedAt = &now
+	if err := l.dao.ExecuteTransferBatch(batch, itemIDs, legs); err != nil {
+		batch.SucceededCount = 0
+		if errors.Is(err, dao.ErrInsufficientFunds) {
+			return fail(ErrInsufficientBalance)
+		}
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return fail(ErrWalletNotFound)
+		}
+		return fail(fmt.Errorf("transfer failed: %w", err))
+	}
+	return nil
+}
+
+// checkBatchItem applies the rules of a transfer to one item of an all-or-nothing batch without
+// moving money. The sender balance is checked when the batch is posted.
+func (l *WalletImpl) checkBatchItem(ctx context.Context, batch *dao.TransferBatch, item dao.TransferBatchItem, rule KycTierRule) error {
+	if l.RequiresApproval(item.Amount) {
+		return ErrBatchItemNeedsApproval
+	}
+	if rule.MaxTransferAmount > 0 && item.Amount > rule.MaxTransferAmount {
+		return ErrKycTransferLimitExceeded
+	}
+	if err := l.ensureWalletActive(item.ToWalletID); err != nil {
+		return err
+	}
+	if err := l.ScreenTransferParties(ctx, batch.FromWalletID, item.ToWalletID); err != nil {
+		return err
+	}
+	return l.screenWithoutReview(ctx, batch.FromWalletID, item.ToWalletID, item.Amount, ErrBatchItemNeedsReview)
+}
+
+func (l *WalletImpl) finishBatch(batch *dao.TransferBatch, reason error) error {
+	now := time.Now()
+	batch.UpdatedAt = now
+	batch.CompletedAt = &now
+	batch.Error = errorMessage(reason)
+	return l.dao.FinishTransferBatch(batch)
+}
+
+func errorMessage(err error) *string {
+	if err == nil {
+		return nil
+	}
+	message := err.Error()
+	return &message
+}
@@ -0,0 +1,285 @@
+package logic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/dao/mocks"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/risk"
+	riskMocks "github.com/julkhong/walletapp/server/internal/risk/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateTransferBatch(t *testing.T) {
+	ctx := context.TODO()
+	req := logic.TransferBatchRequest{
+		FromWalletID: "wallet-1",
+		Mode:         common.BatchModeBestEffort,
+		Items: []logic.TransferBatchItemRequest{
+			{ToWalletID: "wallet-2", Amount: 10.5, Reference: "march"},
+			{ToWalletID: "wallet-3", Amount: 20},
+		},
+		IdempotencyKey: "key-1",
+	}
+
+	t.Run("created pending", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectActiveWallets(mockDao, "wallet-1")
+		mockDao.On("CreateTransferBatch", mock.MatchedBy(func(b *dao.TransferBatch) bool {
+			return b.Status == common.BatchStatusPending && b.ItemCount == 2 && b.TotalAmount == 30.5
+		}), mock.MatchedBy(func(items []dao.TransferBatchItem) bool {
+			return len(items) == 2 && items[0].Seq == 1 && items[0].Reference == "march" && items[1].Seq == 2
+		})).Return(nil).Once()
+
+		batch, err := impl.CreateTransferBatch(ctx, req)
+		assert.NoError(t, err)
+		assert.Len(t, batch.Items, 2)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("repeated idempotency key returns the first batch", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectActiveWallets(mockDao, "wallet-1")
+		mockDao.On("CreateTransferBatch", mock.Anything, mock.Anything).Return(dao.ErrTransferBatchExists).Once()
+		mockDao.On("GetTransferBatchByKey", "key-1").Return(&dao.TransferBatch{ID: "batch-0"}, nil).Once()
+		mockDao.On("GetTransferBatch", "batch-0").Return(&dao.TransferBatch{ID: "batch-0", Status: common.BatchStatusCompleted}, nil).Once()
+		mockDao.On("ListTransferBatchItems", "batch-0").Return([]dao.TransferBatchItem{{ID: "item-1"}}, nil).Once()
+
+		batch, err := impl.CreateTransferBatch(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, "batch-0", batch.ID)
+		assert.Equal(t, common.BatchStatusCompleted, batch.Status)
+	})
+
+	t.Run("invalid batches", func(t *testing.T) {
+		impl, mockDao := setupLogicTest()
+		expectActiveWallets(mockDao, "wallet-1")
+
+		bad := req
+		bad.Mode = "sometimes"
+		_, err := impl.CreateTransferBatch(ctx, bad)
+		assert.ErrorIs(t, err, logic.ErrInvalidTransferBatch)
+
+		bad = req
+		bad.Items = []logic.TransferBatchItemRequest{{ToWalletID: "wallet-1", Amount: 5}}
+		_, err = impl.CreateTransferBatch(ctx, bad)
+		assert.ErrorIs(t, err, logic.ErrInvalidTransferBatch)
+
+		bad = req
+		bad.Items = nil
+		_, err = impl.CreateTransferBatch(ctx, bad)
+		assert.ErrorIs(t, err, logic.ErrInvalidTransferBatch)
+
+		mockDao.AssertNotCalled(t, "CreateTransferBatch", mock.Anything, mock.Anything)
+	})
+}
+
+func batchItems() []dao.TransferBatchItem {
+	return []dao.TransferBatchItem{
+		{ID: "item-1", Seq: 1, ToWalletID: "wallet-2", Amount: 30},
+		{ID: "item-2", Seq: 2, ToWalletID: "wallet-3", Amount: 50},
+	}
+}
+
+func TestProcessTransferBatchesBestEffort(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	batch := dao.TransferBatch{ID: "batch-1", FromWalletID: "wallet-1", Mode: common.BatchModeBestEffort, ItemCount: 2, Status: common.BatchStatusPending}
+	mockDao.On("ListStalledTransferBatches", mock.Anything, 20).Return([]dao.TransferBatch{}, nil).Once()
+	mockDao.On("ListPendingTransferBatches", 20).Return([]dao.TransferBatch{batch}, nil).Once()
+	mockDao.On("ClaimTransferBatch", "batch-1").Return(nil).Once()
+	mockDao.On("ListTransferBatchItems", "batch-1").Return(batchItems(), nil).Once()
+	expectActiveWallets(mockDao, "wallet-1", "wallet-2", "wallet-3")
+	mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil)
+
+	// the first item is paid together with its outcome, leaving too little for the second
+	mockDao.On("GetBalance", "wallet-1").Return(60.0, nil).Once()
+	mockDao.On("GetBalance", "wallet-2").Return(0.0, nil).Once()
+	mockDao.On("PostTransferBatchItem", "item-1", mock.MatchedBy(func(legs []dao.TransferLeg) bool {
+		return len(legs) == 1 && legs[0].ToWalletID == "wallet-2" && legs[0].Amount == 30
+	})).Return(nil).Once()
+	mockDao.On("GetBalance", "wallet-1").Return(30.0, nil).Once()
+
+	mockDao.On("UpdateTransferBatchItem", "item-2", common.BatchItemFailed,
+		mock.MatchedBy(func(msg *string) bool { return *msg == logic.ErrInsufficientBalance.Error() }), (*string)(nil)).Return(nil).Once()
+	mockDao.On("FinishTransferBatch", mock.MatchedBy(func(b *dao.TransferBatch) bool {
+		return b.Status == common.BatchStatusPartial && b.SucceededCount == 1 && b.FailedCount == 1 && b.CompletedAt != nil
+	})).Return(nil).Once()
+
+	finished, err := impl.ProcessTransferBatches(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, finished)
+	mockDao.AssertExpectations(t)
+}
+
+func TestProcessTransferBatchesAllOrNothing(t *testing.T) {
+	batch := dao.TransferBatch{ID: "batch-1", FromWalletID: "wallet-1", Mode: common.BatchModeAllOrNothing, ItemCount: 2, Status: common.BatchStatusPending}
+	setup := func() (*logic.WalletImpl, *mocks.WalletDaoInterface) {
+		impl, mockDao := setupLogicTest()
+		mockDao.On("ListStalledTransferBatches", mock.Anything, 20).Return([]dao.TransferBatch{}, nil).Once()
+		mockDao.On("ListPendingTransferBatches", 20).Return([]dao.TransferBatch{batch}, nil).Once()
+		mockDao.On("ClaimTransferBatch", "batch-1").Return(nil).Once()
+		mockDao.On("ListTransferBatchItems", "batch-1").Return(batchItems(), nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil).Once()
+		return impl, mockDao
+	}
+
+	t.Run("posts all items at once", func(t *testing.T) {
+		impl, mockDao := setup()
+		expectActiveWallets(mockDao, "wallet-1", "wallet-2", "wallet-3")
+		mockDao.On("ExecuteTransferBatch", mock.MatchedBy(func(b *dao.TransferBatch) bool {
+			return b.Status == common.BatchStatusCompleted && b.SucceededCount == 2
+		}), []string{"item-1", "item-2"}, mock.MatchedBy(func(legs []dao.TransferLeg) bool {
+			return len(legs) == 2 && legs[0].ToWalletID == "wallet-2" && legs[1].Amount == 50 && legs[1].FromWalletID == "wallet-1"
+		})).Return(nil).Once()
+
+		finished, err := impl.ProcessTransferBatches(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, finished)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("one frozen recipient fails the batch", func(t *testing.T) {
+		impl, mockDao := setup()
+		expectActiveWallets(mockDao, "wallet-1", "wallet-2")
+		mockDao.On("GetWalletByID", "wallet-3").Return(&dao.Wallet{ID: "wallet-3", Status: common.WalletStatusFrozen}, nil)
+		mockDao.On("UpdateTransferBatchItem", "item-2", common.BatchItemFailed,
+			mock.MatchedBy(func(msg *string) bool { return *msg == logic.ErrWalletFrozen.Error() }), (*string)(nil)).Return(nil).Once()
+		mockDao.On("FinishTransferBatch", mock.MatchedBy(func(b *dao.TransferBatch) bool {
+			return b.Status == common.BatchStatusFailed && b.FailedCount == 1 && *b.Error == "1 of 2 items failed"
+		})).Return(nil).Once()
+
+		_, err := impl.ProcessTransferBatches(context.TODO())
+		assert.NoError(t, err)
+		mockDao.AssertNotCalled(t, "ExecuteTransferBatch", mock.Anything, mock.Anything, mock.Anything)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("insufficient balance fails the batch", func(t *testing.T) {
+		impl, mockDao := setup()
+		expectActiveWallets(mockDao, "wallet-1", "wallet-2", "wallet-3")
+		mockDao.On("ExecuteTransferBatch", mock.Anything, mock.Anything, mock.Anything).Return(dao.ErrInsufficientFunds).Once()
+		mockDao.On("FinishTransferBatch", mock.MatchedBy(func(b *dao.TransferBatch) bool {
+			return b.Status == common.BatchStatusFailed && b.SucceededCount == 0 && *b.Error == logic.ErrInsufficientBalance.Error()
+		})).Return(nil).Once()
+
+		_, err := impl.ProcessTransferBatches(context.TODO())
+		assert.NoError(t, err)
+		mockDao.AssertExpectations(t)
+	})
+}
+
+func TestProcessTransferBatchesAuthorizesCreator(t *testing.T) {
+	spendingCap := 100.0
+	setup := func(mode string) (*logic.WalletImpl, *mocks.WalletDaoInterface) {
+		impl, mockDao := setupLogicTest()
+		batch := dao.TransferBatch{ID: "batch-1", FromWalletID: "wallet-1", Mode: mode, ItemCount: 2, TotalAmount: 80,
+			Status: common.BatchStatusPending, CreatedBy: "spender"}
+		mockDao.On("ListStalledTransferBatches", mock.Anything, 20).Return([]dao.TransferBatch{}, nil).Once()
+		mockDao.On("ListPendingTransferBatches", 20).Return([]dao.TransferBatch{batch}, nil).Once()
+		mockDao.On("ClaimTransferBatch", "batch-1").Return(nil).Once()
+		mockDao.On("ListTransferBatchItems", "batch-1").Return(batchItems(), nil).Once()
+		mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil)
+		mockDao.On("GetWalletMember", "wallet-1", "spender").
+			Return(member("spender", common.MemberRoleSpender, common.MemberStatusActive, &spendingCap), nil)
+		mockDao.On("SumMemberSpending", "wallet-1", "spender", mock.Anything).Return(60.0, nil)
+		expectActiveWallets(mockDao, "wallet-1", "wallet-2", "wallet-3")
+		return impl, mockDao
+	}
+
+	t.Run("all or nothing batch over the cap fails", func(t *testing.T) {
+		impl, mockDao := setup(common.BatchModeAllOrNothing)
+		mockDao.On("FinishTransferBatch", mock.MatchedBy(func(b *dao.TransferBatch) bool {
+			return b.Status == common.BatchStatusFailed && *b.Error == logic.ErrSpendingCapExceeded.Error()
+		})).Return(nil).Once()
+
+		_, err := impl.ProcessTransferBatches(context.TODO())
+		assert.NoError(t, err)
+		mockDao.AssertNotCalled(t, "ExecuteTransferBatch", mock.Anything, mock.Anything, mock.Anything)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("best effort item over the cap fails", func(t *testing.T) {
+		impl, mockDao := setup(common.BatchModeBestEffort)
+		mockDao.On("GetBalance", "wallet-1").Return(100.0, nil).Once()
+		mockDao.On("GetBalance", "wallet-2").Return(0.0, nil).Once()
+		mockDao.On("PostTransferBatchItem", "item-1", mock.Anything).Return(nil).Once()
+		mockDao.On("UpdateTransferBatchItem", "item-2", common.BatchItemFailed,
+			mock.MatchedBy(func(msg *string) bool { return *msg == logic.ErrSpendingCapExceeded.Error() }), (*string)(nil)).Return(nil).Once()
+		mockDao.On("FinishTransferBatch", mock.MatchedBy(func(b *dao.TransferBatch) bool {
+			return b.Status == common.BatchStatusPartial && b.SucceededCount == 1 && b.FailedCount == 1
+		})).Return(nil).Once()
+
+		_, err := impl.ProcessTransferBatches(context.TODO())
+		assert.NoError(t, err)
+		mockDao.AssertExpectations(t)
+	})
+}
+
+func TestProcessTransferBatchesResumesStalledBatch(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	batch := dao.TransferBatch{ID: "batch-1", FromWalletID: "wallet-1", Mode: common.BatchModeBestEffort, ItemCount: 2, Status: common.BatchStatusProcessing}
+	items := batchItems()
+	items[0].Status = common.BatchItemSucceeded
+	items[1].Status = common.BatchItemPending
+	mockDao.On("ListStalledTransferBatches", mock.MatchedBy(func(before time.Time) bool {
+		return before.Before(time.Now().Add(-10 * time.Minute))
+	}), 20).Return([]dao.TransferBatch{batch}, nil).Once()
+	mockDao.On("ReclaimTransferBatch", "batch-1", mock.Anything).Return(nil).Once()
+	mockDao.On("ListTransferBatchItems", "batch-1").Return(items, nil).Once()
+	expectActiveWallets(mockDao, "wallet-1", "wallet-3")
+	mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil)
+
+	// the item paid before the interruption is only counted
+	mockDao.On("GetBalance", "wallet-1").Return(100.0, nil).Once()
+	mockDao.On("GetBalance", "wallet-3").Return(0.0, nil).Once()
+	mockDao.On("PostTransferBatchItem", "item-2", mock.Anything).Return(nil).Once()
+	mockDao.On("FinishTransferBatch", mock.MatchedBy(func(b *dao.TransferBatch) bool {
+		return b.Status == common.BatchStatusCompleted && b.SucceededCount == 2 && b.FailedCount == 0
+	})).Return(nil).Once()
+	mockDao.On("ListPendingTransferBatches", 20).Return([]dao.TransferBatch{}, nil).Once()
+
+	finished, err := impl.ProcessTransferBatches(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, finished)
+	mockDao.AssertNotCalled(t, "GetBalance", "wallet-2")
+	mockDao.AssertExpectations(t)
+}
+
+func TestBestEffortItemParkedForReview(t *testing.T) {
+	impl, mockDao := setupLogicTest()
+	screener := new(riskMocks.Screener)
+	impl.SetScreener(screener)
+	screener.On("Screen", mock.Anything, mock.MatchedBy(func(ev risk.Event) bool { return ev.CounterpartyWalletID == "wallet-3" })).
+		Return(risk.Result{Action: risk.ActionReview, Hits: []string{"large_amount_review"}}, nil)
+	screener.On("Screen", mock.Anything, mock.Anything).Return(risk.Result{Action: risk.ActionAllow}, nil)
+
+	batch := dao.TransferBatch{ID: "batch-1", FromWalletID: "wallet-1", Mode: common.BatchModeBestEffort, ItemCount: 2, Status: common.BatchStatusPending}
+	mockDao.On("ListStalledTransferBatches", mock.Anything, 20).Return([]dao.TransferBatch{}, nil).Once()
+	mockDao.On("ListPendingTransferBatches", 20).Return([]dao.TransferBatch{batch}, nil).Once()
+	mockDao.On("ClaimTransferBatch", "batch-1").Return(nil).Once()
+	mockDao.On("ListTransferBatchItems", "batch-1").Return(batchItems(), nil).Once()
+	expectActiveWallets(mockDao, "wallet-1", "wallet-2", "wallet-3")
+	mockDao.On("GetUserByWalletID", "wallet-1").Return(fullKycUser, nil)
+	mockDao.On("GetBalance", "wallet-1").Return(100.0, nil)
+	mockDao.On("GetBalance", "wallet-2").Return(0.0, nil).Once()
+	mockDao.On("GetBalance", "wallet-3").Return(0.0, nil).Once()
+	mockDao.On("PostTransferBatchItem", "item-1", mock.Anything).Return(nil).Once()
+	var reviewID string
+	mockDao.On("CreateTransactionReview", mock.MatchedBy(func(review *dao.TransactionReview) bool {
+		reviewID = review.ID
+		return review.WalletID == "wallet-1" && review.Amount == 50
+	})).Return(nil).Once()
+	mockDao.On("ParkTransferBatchItem", "item-2", mock.MatchedBy(func(id string) bool { return id == reviewID })).Return(nil).Once()
+	mockDao.On("FinishTransferBatch", mock.MatchedBy(func(b *dao.TransferBatch) bool {
+		return b.Status == common.BatchStatusCompleted && b.SucceededCount == 1 && b.FailedCount == 0
+	})).Return(nil).Once()
+
+	_, err := impl.ProcessTransferBatches(context.TODO())
+	assert.NoError(t, err)
+	mockDao.AssertExpectations(t)
+}
@@ -11,16 +11,34 @@ import (
 
 	"github.com/julkhong/walletapp/server/internal/common"
 	dao "github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/fees"
+	"github.com/julkhong/walletapp/server/internal/notify"
+	"github.com/julkhong/walletapp/server/internal/payout"
+	"github.com/julkhong/walletapp/server/internal/provider"
+	"github.com/julkhong/walletapp/server/internal/risk"
+	"github.com/julkhong/walletapp/server/internal/screening"
 )
 
 var (
 	ErrInsufficientBalance = errors.New("insufficient balance")
 	ErrWalletNotFound      = errors.New("wallet not found")
+	ErrWalletFrozen        = errors.New("wallet is frozen")
 )
 
 type WalletImpl struct {
-	dao    dao.WalletDaoInterface
-	logger *logrus.Entry
+	dao       dao.WalletDaoInterface
+	logger    *logrus.Entry
+	screener  risk.Screener
+	sanctions screening.Checker
+	approvals ApprovalPolicy
+	payouts   PayoutConfig
+	providers *provider.Registry
+	fees      FeeConfig
+	interest  InterestConfig
+	escrow    EscrowConfig
+	requests  PaymentRequestConfig
+	links     PaymentLinkConfig
+	notifier  notify.Notifier
 }
 
 func NewWalletImpl(dao dao.WalletDaoInterface, baseLogger *logrus.Logger) *WalletImpl {
@@ -38,29 +56,77 @@ func (l *WalletImpl) Deposit(ctx context.Context, walletID string, amount float6
 		return fmt.Errorf("deposit failed: %w", err)
 	}
 
-	err = l.dao.UpdateBalance(&dao.UpdateBalance{
+	if err := l.ensureWalletActive(walletID); err != nil {
+		if errors.Is(err, ErrWalletFrozen) || errors.Is(err, ErrPocketWallet) {
+			return err
+		}
+		return fmt.Errorf("deposit failed: %w", err)
+	}
+
+	rule, err := l.kycRuleForWallet(walletID)
+	if err != nil {
+		l.logger.WithError(err).Errorf("Failed to resolve KYC rule")
+		return fmt.Errorf("deposit failed: %w", err)
+	}
+	if rule.MaxBalance > 0 && current+amount > rule.MaxBalance {
+		l.logger.Warnf("KYC balance limit exceeded: current=%.4f, requested=%.4f, max=%.4f", current, amount, rule.MaxBalance)
+		return ErrKycBalanceLimitExceeded
+	}
+
+	if _, err := l.postLedgerEntry(walletID, current, amount, common.TransactionTypeDeposit); err != nil {
+		l.logger.WithError(err).Errorf("Failed to update balance")
+		return fmt.Errorf("deposit failed: %w", err)
+	}
+
+	l.recordAudit(ctx, common.AuditActionDeposit, common.AuditEntityWallet, walletID, map[string]any{
+		"amount":  amount,
+		"balance": common.RoundToNDecimals(current+amount, 4),
+	})
+	return nil
+}
+
+// postLedgerEntry moves a wallet's balance by a signed amount from its known current balance
+// and records the movement as a transaction of txType. It returns the transaction ID.
+func (l *WalletImpl) postLedgerEntry(walletID string, current, amount float64, txType string) (string, error) {
+	balanceAfter := common.RoundToNDecimals(current+amount, 4)
+	err := l.dao.UpdateBalance(&dao.UpdateBalance{
 		WalletID: walletID,
-		Amount:   common.RoundToNDecimals(current+amount, 4),
+		Amount:   balanceAfter,
 	})
 	var related *string
+	txID := uuid.NewString()
 	_ = l.dao.CreateTransaction(&dao.Transaction{
-		ID:            uuid.NewString(),
+		ID:            txID,
 		WalletID:      walletID,
-		Type:          common.TransactionTypeDeposit,
+		Type:          txType,
 		Amount:        amount,
 		RelatedUserID: related,
+		BalanceAfter:  &balanceAfter,
 		CreatedAt:     time.Now(),
 	})
 
 	if err != nil {
-		l.logger.WithError(err).Errorf("Failed to update balance")
-		return fmt.Errorf("deposit failed: %w", err)
+		return "", err
 	}
-
-	return nil
+	return txID, nil
 }
 
 func (l *WalletImpl) Withdraw(ctx context.Context, walletID string, amount float64) error {
+	_, err := l.withdraw(ctx, walletID, amount, true, withdrawDestination{})
+	return err
+}
+
+// withdrawDestination is where a withdrawal's money goes once it leaves the wallet: a bank
+// account paid in a payout batch, an external payment provider, or neither for cash.
+type withdrawDestination struct {
+	beneficiary *payout.Account
+	provider    *ProviderTarget
+}
+
+// withdraw debits the wallet and returns what was posted: the withdrawal transaction, its fee and
+// the record that pays the money out to the destination, all written in one DB transaction. The
+// destination is kept with the review when screening parks the withdrawal.
+func (l *WalletImpl) withdraw(ctx context.Context, walletID string, amount float64, screen bool, dest withdrawDestination) (*dao.Withdrawal, error) {
 	amount = common.RoundToNDecimals(amount, 4)
 	l.logger.Infof("Withdrawing %.4f from wallet %s", amount, walletID)
 
@@ -68,39 +134,102 @@ func (l *WalletImpl) Withdraw(ctx context.Context, walletID string, amount float
 	if err != nil {
 		l.logger.WithError(err).Errorf("Failed to fetch wallet balance")
 		if errors.Is(err, dao.ErrWalletNotFound) {
-			return ErrWalletNotFound
+			return nil, ErrWalletNotFound
 		}
-		return fmt.Errorf("withdraw failed: %w", err)
+		return nil, fmt.Errorf("withdraw failed: %w", err)
 	}
 
-	if current < amount {
-		l.logger.Warnf("Insufficient balance: current=%.4f, requested=%.4f", current, amount)
-		return ErrInsufficientBalance
+	wallet, err := l.activeWallet(walletID)
+	if err != nil {
+		if errors.Is(err, ErrWalletFrozen) || errors.Is(err, ErrWalletNotFound) || errors.Is(err, ErrPocketWallet) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("withdraw failed: %w", err)
 	}
 
-	err = l.dao.UpdateBalance(&dao.UpdateBalance{
-		WalletID: walletID,
-		Amount:   common.RoundToNDecimals(current-amount, 4),
-	})
-	var related *string
-	_ = l.dao.CreateTransaction(&dao.Transaction{
-		ID:            uuid.NewString(),
-		WalletID:      walletID,
-		Type:          common.TransactionTypeWithdraw,
-		Amount:        amount,
-		RelatedUserID: related,
-		CreatedAt:     time.Now(),
-	})
-
+	kycLevel, rule, err := l.kycForWallet(walletID)
 	if err != nil {
-		l.logger.WithError(err).Errorf("Failed to update balance after withdraw")
-		return fmt.Errorf("withdraw failed: %w", err)
+		l.logger.WithError(err).Errorf("Failed to resolve KYC rule")
+		if errors.Is(err, ErrWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, fmt.Errorf("withdraw failed: %w", err)
+	}
+	if !rule.WithdrawAllowed {
+		l.logger.Warnf("Withdraw rejected by KYC tier for wallet %s", walletID)
+		return nil, ErrKycWithdrawNotAllowed
 	}
 
-	return nil
+	fee := l.quoteFee(common.TransactionTypeWithdraw, kycLevel, walletID, amount)
+	l.addOverdraftFee(&fee, kycLevel, walletID, current)
+	if current+wallet.CreditLimit < fee.Total {
+		l.logger.Warnf("Insufficient balance: current=%.4f, credit_limit=%.4f, requested=%.4f, fee=%.4f",
+			current, wallet.CreditLimit, amount, fee.Fee)
+		return nil, ErrInsufficientBalance
+	}
+
+	if screen {
+		if err := l.screen(ctx, risk.Event{
+			Type:     common.TransactionTypeWithdraw,
+			WalletID: walletID,
+			Amount:   amount,
+		}, dest); err != nil {
+			return nil, err
+		}
+	}
+
+	txID := uuid.NewString()
+	posted := &dao.Withdrawal{
+		Debit: &dao.Transaction{
+			ID:          txID,
+			WalletID:    walletID,
+			Type:        common.TransactionTypeWithdraw,
+			Amount:      amount,
+			InitiatedBy: initiator(ctx),
+			CreatedAt:   time.Now(),
+		},
+		Fee: l.feeLeg(walletID, txID, fee),
+	}
+	switch {
+	case dest.beneficiary != nil:
+		posted.Payout = l.newPayout(txID, walletID, amount, *dest.beneficiary)
+	case dest.provider != nil:
+		posted.Operation = newProviderPayout(txID, walletID, amount, *dest.provider)
+	}
+	if err := l.dao.PostWithdrawal(*posted); err != nil {
+		if errors.Is(err, dao.ErrInsufficientFunds) {
+			return nil, ErrInsufficientBalance
+		}
+		l.logger.WithError(err).Errorf("Failed to post withdraw")
+		return nil, fmt.Errorf("withdraw failed: %w", err)
+	}
+
+	details := map[string]any{
+		"amount":  amount,
+		"balance": common.RoundToNDecimals(current-fee.Total, 4),
+	}
+	if fee.Fee > 0 {
+		details["fee"] = fee.Fee
+	}
+	l.recordAudit(ctx, common.AuditActionWithdraw, common.AuditEntityWallet, walletID, details)
+	return posted, nil
+}
+
+// Transfer moves amount between wallets and returns the fee charged on top of it, or nil when
+// fees are not enabled. The transfer and its fee are posted together.
+func (l *WalletImpl) Transfer(ctx context.Context, fromWalletID, toWalletID string, amount float64) (*fees.Quote, error) {
+	_, fee, err := l.transfer(ctx, fromWalletID, toWalletID, amount, true)
+	return fee, err
+}
+
+// transfer returns the ID of the sender's transfer transaction and the fee charged.
+func (l *WalletImpl) transfer(ctx context.Context, fromWalletID, toWalletID string, amount float64, screen bool) (string, *fees.Quote, error) {
+	return l.transferWith(ctx, fromWalletID, toWalletID, amount, screen, l.dao.PostTransfers)
 }
 
-func (l *WalletImpl) Transfer(ctx context.Context, fromWalletID, toWalletID string, amount float64) error {
+// transferWith is transfer with the legs posted by post, for callers that record the outcome of
+// the transfer in the same DB transaction.
+func (l *WalletImpl) transferWith(ctx context.Context, fromWalletID, toWalletID string, amount float64, screen bool, post func([]dao.TransferLeg) error) (string, *fees.Quote, error) {
 	amount = common.RoundToNDecimals(amount, 4)
 	l.logger.Infof("Transferring %.4f from wallet %s to %s", amount, fromWalletID, toWalletID)
 
@@ -108,62 +237,100 @@ func (l *WalletImpl) Transfer(ctx context.Context, fromWalletID, toWalletID stri
 	if err != nil {
 		l.logger.WithError(err).Error("Failed to get sender balance")
 		if errors.Is(err, dao.ErrWalletNotFound) {
-			return fmt.Errorf("sender wallet not found: %w", err)
+			return "", nil, fmt.Errorf("sender wallet not found: %w", err)
 		}
-		return fmt.Errorf("transfer failed: %w", err)
+		return "", nil, fmt.Errorf("transfer failed: %w", err)
 	}
 
-	if fromBalance < amount {
-		l.logger.Warnf("Insufficient funds for transfer: current=%.4f, requested=%.4f", fromBalance, amount)
-		return ErrInsufficientBalance
+	fromWallet, err := l.activeWallet(fromWalletID)
+	if err != nil {
+		if errors.Is(err, ErrWalletFrozen) || errors.Is(err, ErrPocketWallet) {
+			return "", nil, err
+		}
+		return "", nil, fmt.Errorf("transfer failed: %w", err)
+	}
+
+	kycLevel, rule, err := l.kycForWallet(fromWalletID)
+	if err != nil {
+		l.logger.WithError(err).Error("Failed to resolve sender KYC rule")
+		return "", nil, fmt.Errorf("transfer failed: %w", err)
+	}
+	if rule.MaxTransferAmount > 0 && amount > rule.MaxTransferAmount {
+		l.logger.Warnf("KYC transfer limit exceeded: requested=%.4f, max=%.4f", amount, rule.MaxTransferAmount)
+		return "", nil, ErrKycTransferLimitExceeded
+	}
+
+	fee := l.quoteFee(common.TransactionTypeTransfer, kycLevel, fromWalletID, amount)
+	l.addOverdraftFee(&fee, kycLevel, fromWalletID, fromBalance)
+	if fromBalance+fromWallet.CreditLimit < fee.Total {
+		l.logger.Warnf("Insufficient funds for transfer: current=%.4f, credit_limit=%.4f, requested=%.4f, fee=%.4f",
+			fromBalance, fromWallet.CreditLimit, amount, fee.Fee)
+		return "", nil, ErrInsufficientBalance
 	}
 
 	toBalance, err := l.dao.GetBalance(toWalletID)
 	if err != nil {
 		l.logger.WithError(err).Error("Failed to get receiver balance")
 		if errors.Is(err, dao.ErrWalletNotFound) {
-			return fmt.Errorf("receiver wallet not found: %w", err)
+			return "", nil, fmt.Errorf("receiver wallet not found: %w", err)
 		}
-		return fmt.Errorf("transfer failed: %w", err)
+		return "", nil, fmt.Errorf("transfer failed: %w", err)
 	}
 
-	// Update balances
-	if err := l.dao.UpdateBalance(&dao.UpdateBalance{
-		WalletID: fromWalletID,
-		Amount:   common.RoundToNDecimals(fromBalance-amount, 4),
-	}); err != nil {
-		l.logger.WithError(err).Error("Failed to update sender balance")
-		return fmt.Errorf("transfer failed: %w", err)
+	if err := l.ensureWalletActive(toWalletID); err != nil {
+		if errors.Is(err, ErrWalletFrozen) || errors.Is(err, ErrPocketWallet) {
+			return "", nil, err
+		}
+		return "", nil, fmt.Errorf("transfer failed: %w", err)
 	}
 
-	if err := l.dao.UpdateBalance(&dao.UpdateBalance{
-		WalletID: toWalletID,
-		Amount:   common.RoundToNDecimals(toBalance+amount, 4),
-	}); err != nil {
-		l.logger.WithError(err).Error("Failed to update receiver balance")
-		return fmt.Errorf("transfer failed: %w", err)
+	if screen {
+		if err := l.screen(ctx, risk.Event{
+			Type:                 common.TransactionTypeTransfer,
+			WalletID:             fromWalletID,
+			CounterpartyWalletID: toWalletID,
+			Amount:               amount,
+		}, withdrawDestination{}); err != nil {
+			return "", nil, err
+		}
 	}
 
-	// Create transactions
-	_ = l.dao.CreateTransaction(&dao.Transaction{
-		ID:            uuid.NewString(),
-		WalletID:      fromWalletID,
-		Type:          common.TransactionTypeTransfer,
-		Amount:        -amount,
-		RelatedUserID: &toWalletID,
-		CreatedAt:     time.Now(),
-	})
-
-	_ = l.dao.CreateTransaction(&dao.Transaction{
-		ID:            uuid.NewString(),
-		WalletID:      toWalletID,
-		Type:          common.TransactionTypeTransfer,
-		Amount:        amount,
-		RelatedUserID: &fromWalletID,
-		CreatedAt:     time.Now(),
-	})
+	debitID := uuid.NewString()
+	legs := []dao.TransferLeg{{
+		FromWalletID: fromWalletID,
+		ToWalletID:   toWalletID,
+		Amount:       amount,
+		DebitID:      debitID,
+		CreditID:     uuid.NewString(),
+		Type:         common.TransactionTypeTransfer,
+		InitiatedBy:  initiator(ctx),
+	}}
+	if leg := l.feeLeg(fromWalletID, debitID, fee); leg != nil {
+		legs = append(legs, *leg)
+	}
+	if err := post(legs); err != nil {
+		if errors.Is(err, dao.ErrInsufficientFunds) {
+			return "", nil, ErrInsufficientBalance
+		}
+		l.logger.WithError(err).Error("Failed to post transfer")
+		return "", nil, fmt.Errorf("transfer failed: %w", err)
+	}
 
-	return nil
+	details := map[string]any{
+		"to_wallet_id": toWalletID,
+		"amount":       amount,
+		"from_balance": common.RoundToNDecimals(fromBalance-fee.Total, 4),
+		"to_balance":   common.RoundToNDecimals(toBalance+amount, 4),
+	}
+	var charged *fees.Quote
+	if l.feesEnabled() {
+		charged = &fee
+		if fee.Fee > 0 {
+			details["fee"] = fee.Fee
+		}
+	}
+	l.recordAudit(ctx, common.AuditActionTransfer, common.AuditEntityWallet, fromWalletID, details)
+	return debitID, charged, nil
 }
 
 func (l *WalletImpl) GetBalance(ctx context.Context, walletID string) (float64, error) {
@@ -178,6 +345,28 @@ func (l *WalletImpl) GetBalance(ctx context.Context, walletID string) (float64,
 	return common.RoundToNDecimals(balance, 4), nil
 }
 
-func (l *WalletImpl) GetTransactionHistory(ctx context.Context, walletID, txType, start, end string, limit, offset int) ([]dao.Transaction, error) {
-	return l.dao.GetTransactionHistory(walletID, txType, start, end, limit, offset)
+// ensureWalletActive rejects movements on frozen wallets and pockets.
+func (l *WalletImpl) ensureWalletActive(walletID string) error {
+	_, err := l.activeWallet(walletID)
+	return err
+}
+
+// activeWallet returns a wallet that is not frozen. Pockets are rejected too: their money only
+// moves to and from their parent wallet.
+func (l *WalletImpl) activeWallet(walletID string) (*dao.Wallet, error) {
+	wallet, err := l.dao.GetWalletByID(walletID)
+	if err != nil {
+		if errors.Is(err, dao.ErrWalletNotFound) {
+			return nil, ErrWalletNotFound
+		}
+		return nil, err
+	}
+	if wallet.Type == common.WalletTypePocket {
+		return nil, ErrPocketWallet
+	}
+	if wallet.Status == common.WalletStatusFrozen {
+		l.logger.Warnf("Rejected movement on frozen wallet %s", walletID)
+		return nil, ErrWalletFrozen
+	}
+	return wallet, nil
 }
@@ -4,8 +4,8 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/julkhong/walletapp/server/internal/common"
 	"github.com/julkhong/walletapp/server/internal/dao"
 	"github.com/julkhong/walletapp/server/internal/dao/mocks"
@@ -15,8 +15,18 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+var fullKycUser = &dao.User{ID: "user-1", KycLevel: common.KycLevelFull}
+
+func expectActiveWallets(mockDao *mocks.WalletDaoInterface, walletIDs ...string) {
+	for _, id := range walletIDs {
+		mockDao.On("GetWalletByID", id).
+			Return(&dao.Wallet{ID: id, Status: common.WalletStatusActive}, nil).Maybe()
+	}
+}
+
 func setupLogicTest() (*logic.WalletImpl, *mocks.WalletDaoInterface) {
 	mockDao := new(mocks.WalletDaoInterface)
+	mockDao.On("AppendAuditLog", mock.Anything).Return(nil).Maybe()
 	logger := logrus.New()
 	impl := logic.NewWalletImpl(mockDao, logger)
 	return impl, mockDao
@@ -27,9 +37,11 @@ func TestDeposit(t *testing.T) {
 	ctx := context.TODO()
 	walletID := "wallet-1"
 	amount := 10.12345
+	expectActiveWallets(mockDao, walletID)
 
 	t.Run("successful deposit", func(t *testing.T) {
 		mockDao.On("GetBalance", walletID).Return(90.0, nil).Once()
+		mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
 		mockDao.On("UpdateBalance", mock.Anything).Return(nil).Once()
 		mockDao.On("CreateTransaction", mock.Anything).Return(nil).Once()
 
@@ -46,8 +58,29 @@ func TestDeposit(t *testing.T) {
 		mockDao.AssertExpectations(t)
 	})
 
+	t.Run("kyc balance limit exceeded", func(t *testing.T) {
+		mockDao.On("GetBalance", walletID).Return(995.0, nil).Once()
+		mockDao.On("GetUserByWalletID", walletID).
+			Return(&dao.User{ID: "user-1", KycLevel: common.KycLevelUnverified}, nil).Once()
+
+		err := impl.Deposit(ctx, walletID, amount)
+		assert.ErrorIs(t, err, logic.ErrKycBalanceLimitExceeded)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("frozen wallet", func(t *testing.T) {
+		mockDao.On("GetBalance", "wallet-frozen").Return(100.0, nil).Once()
+		mockDao.On("GetWalletByID", "wallet-frozen").
+			Return(&dao.Wallet{ID: "wallet-frozen", Status: common.WalletStatusFrozen}, nil).Once()
+
+		err := impl.Deposit(ctx, "wallet-frozen", amount)
+		assert.ErrorIs(t, err, logic.ErrWalletFrozen)
+		mockDao.AssertExpectations(t)
+	})
+
 	t.Run("update failed", func(t *testing.T) {
 		mockDao.On("GetBalance", walletID).Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
 		mockDao.On("UpdateBalance", mock.Anything).Return(errors.New("update failed")).Once()
 		mockDao.On("CreateTransaction", mock.Anything).Return(nil).Once()
 
@@ -62,11 +95,12 @@ func TestWithdraw(t *testing.T) {
 	ctx := context.TODO()
 	walletID := "wallet-2"
 	amount := 20.00
+	expectActiveWallets(mockDao, walletID)
 
 	t.Run("successful withdraw", func(t *testing.T) {
 		mockDao.On("GetBalance", walletID).Return(100.0, nil).Once()
-		mockDao.On("UpdateBalance", mock.Anything).Return(nil).Once()
-		mockDao.On("CreateTransaction", mock.Anything).Return(nil).Once()
+		mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
+		mockDao.On("PostWithdrawal", mock.Anything).Return(nil).Once()
 
 		err := impl.Withdraw(ctx, walletID, amount)
 		assert.NoError(t, err)
@@ -75,12 +109,23 @@ func TestWithdraw(t *testing.T) {
 
 	t.Run("insufficient balance", func(t *testing.T) {
 		mockDao.On("GetBalance", walletID).Return(10.0, nil).Once()
+		mockDao.On("GetUserByWalletID", walletID).Return(fullKycUser, nil).Once()
 
 		err := impl.Withdraw(ctx, walletID, amount)
 		assert.ErrorIs(t, err, logic.ErrInsufficientBalance)
 		mockDao.AssertExpectations(t)
 	})
 
+	t.Run("withdraw not allowed for kyc tier", func(t *testing.T) {
+		mockDao.On("GetBalance", walletID).Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", walletID).
+			Return(&dao.User{ID: "user-1", KycLevel: common.KycLevelUnverified}, nil).Once()
+
+		err := impl.Withdraw(ctx, walletID, amount)
+		assert.ErrorIs(t, err, logic.ErrKycWithdrawNotAllowed)
+		mockDao.AssertExpectations(t)
+	})
+
 	t.Run("wallet not found", func(t *testing.T) {
 		mockDao.On("GetBalance", walletID).Return(0.0, dao.ErrWalletNotFound).Once()
 
@@ -96,31 +141,56 @@ func TestTransfer(t *testing.T) {
 	fromWallet := "wallet-from"
 	toWallet := "wallet-to"
 	amount := 25.0
+	expectActiveWallets(mockDao, fromWallet, toWallet)
 
 	t.Run("successful transfer", func(t *testing.T) {
 		mockDao.On("GetBalance", fromWallet).Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", fromWallet).Return(fullKycUser, nil).Once()
 		mockDao.On("GetBalance", toWallet).Return(50.0, nil).Once()
-		mockDao.On("UpdateBalance", mock.Anything).Return(nil).Times(2)
-		mockDao.On("CreateTransaction", mock.Anything).Return(nil).Times(2)
+		mockDao.On("PostTransfers", mock.Anything).Return(nil).Once()
 
-		err := impl.Transfer(ctx, fromWallet, toWallet, amount)
+		_, err := impl.Transfer(ctx, fromWallet, toWallet, amount)
 		assert.NoError(t, err)
 		mockDao.AssertExpectations(t)
 	})
 
 	t.Run("insufficient funds", func(t *testing.T) {
 		mockDao.On("GetBalance", fromWallet).Return(10.0, nil).Once()
+		mockDao.On("GetUserByWalletID", fromWallet).Return(fullKycUser, nil).Once()
 
-		err := impl.Transfer(ctx, fromWallet, toWallet, amount)
+		_, err := impl.Transfer(ctx, fromWallet, toWallet, amount)
 		assert.ErrorIs(t, err, logic.ErrInsufficientBalance)
 		mockDao.AssertExpectations(t)
 	})
 
+	t.Run("kyc transfer limit exceeded", func(t *testing.T) {
+		mockDao.On("GetBalance", fromWallet).Return(1000.0, nil).Once()
+		mockDao.On("GetUserByWalletID", fromWallet).
+			Return(&dao.User{ID: "user-1", KycLevel: common.KycLevelUnverified}, nil).Once()
+
+		_, err := impl.Transfer(ctx, fromWallet, toWallet, 300.0)
+		assert.ErrorIs(t, err, logic.ErrKycTransferLimitExceeded)
+		mockDao.AssertExpectations(t)
+	})
+
+	t.Run("frozen receiver", func(t *testing.T) {
+		mockDao.On("GetBalance", fromWallet).Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", fromWallet).Return(fullKycUser, nil).Once()
+		mockDao.On("GetBalance", "wallet-frozen").Return(0.0, nil).Once()
+		mockDao.On("GetWalletByID", "wallet-frozen").
+			Return(&dao.Wallet{ID: "wallet-frozen", Status: common.WalletStatusFrozen}, nil).Once()
+
+		_, err := impl.Transfer(ctx, fromWallet, "wallet-frozen", amount)
+		assert.ErrorIs(t, err, logic.ErrWalletFrozen)
+		mockDao.AssertExpectations(t)
+	})
+
 	t.Run("receiver wallet not found", func(t *testing.T) {
 		mockDao.On("GetBalance", fromWallet).Return(100.0, nil).Once()
+		mockDao.On("GetUserByWalletID", fromWallet).Return(fullKycUser, nil).Once()
 		mockDao.On("GetBalance", toWallet).Return(0.0, dao.ErrWalletNotFound).Once()
 
-		err := impl.Transfer(ctx, fromWallet, toWallet, amount)
+		_, err := impl.Transfer(ctx, fromWallet, toWallet, amount)
 		assert.Error(t, err)
 		mockDao.AssertExpectations(t)
 	})
@@ -153,12 +223,80 @@ func TestGetBalance(t *testing.T) {
 func TestGetTransactionHistory(t *testing.T) {
 	impl, mockDao := setupLogicTest()
 	ctx := context.TODO()
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	rows := []dao.Transaction{
+		{ID: "tx-3", CreatedAt: base.Add(3 * time.Minute)},
+		{ID: "tx-2", CreatedAt: base.Add(2 * time.Minute)},
+		{ID: "tx-1", CreatedAt: base.Add(time.Minute)},
+	}
+
+	t.Run("first page with next cursor", func(t *testing.T) {
+		mockDao.On("GetTransactionHistory", dao.TransactionFilter{WalletID: "wallet-4", Type: "deposit", Limit: 3}).
+			Return(rows, nil).Once()
+
+		page, err := impl.GetTransactionHistory(ctx, dao.TransactionFilter{WalletID: "wallet-4", Type: "deposit", Limit: 2}, "")
+		assert.NoError(t, err)
+		assert.Len(t, page.Transactions, 2)
+		assert.NotNil(t, page.NextCursor)
+
+		cursor, err := logic.DecodeCursor(*page.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, "tx-2", cursor.ID)
+		assert.True(t, cursor.CreatedAt.Equal(base.Add(2*time.Minute)))
+		mockDao.AssertExpectations(t)
+	})
 
-	mockDao.On("GetTransactionHistory", "wallet-4", "deposit", "2024-01-01", "2024-01-02", 10, 0).
-		Return([]dao.Transaction{{ID: uuid.NewString()}}, nil).Once()
+	t.Run("next page continues after cursor", func(t *testing.T) {
+		cursor := logic.EncodeCursor(dao.TransactionCursor{CreatedAt: rows[1].CreatedAt, ID: "tx-2"})
+		mockDao.On("GetTransactionHistory", mock.MatchedBy(func(f dao.TransactionFilter) bool {
+			return f.After != nil && f.After.ID == "tx-2" && f.Limit == 3
+		})).Return(rows[2:], nil).Once()
 
-	txns, err := impl.GetTransactionHistory(ctx, "wallet-4", "deposit", "2024-01-01", "2024-01-02", 10, 0)
-	assert.NoError(t, err)
-	assert.Len(t, txns, 1)
-	mockDao.AssertExpectations(t)
+		page, err := impl.GetTransactionHistory(ctx, dao.TransactionFilter{WalletID: "wallet-4", Limit: 2}, cursor)
+		assert.NoError(t, err)
+		assert.Len(t, page.Transactions, 1)
+		assert.Nil(t, page.NextCursor)
+	})
+
+	t.Run("default page size", func(t *testing.T) {
+		mockDao.On("GetTransactionHistory", dao.TransactionFilter{WalletID: "wallet-5", Limit: logic.DefaultHistoryPageSize + 1}).
+			Return(nil, nil).Once()
+
+		page, err := impl.GetTransactionHistory(ctx, dao.TransactionFilter{WalletID: "wallet-5"}, "")
+		assert.NoError(t, err)
+		assert.Empty(t, page.Transactions)
+		assert.NotNil(t, page.Transactions, "empty pages encode as []")
+	})
+
+	t.Run("money coming back can be filtered", func(t *testing.T) {
+		for _, txType := range []string{common.TransactionTypePayoutReturn, common.TransactionTypeWithdrawReversal} {
+			mockDao.On("GetTransactionHistory", dao.TransactionFilter{WalletID: "wallet-6", Type: txType,
+				Limit: logic.DefaultHistoryPageSize + 1}).Return(nil, nil).Once()
+
+			_, err := impl.GetTransactionHistory(ctx, dao.TransactionFilter{WalletID: "wallet-6", Type: txType}, "")
+			assert.NoError(t, err, txType)
+		}
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		later := base.Add(time.Hour)
+		neg, low, high := -1.0, 5.0, 10.0
+		cases := []struct {
+			name   string
+			filter dao.TransactionFilter
+			cursor string
+			err    error
+		}{
+			{"page too large", dao.TransactionFilter{Limit: logic.MaxHistoryPageSize + 1}, "", logic.ErrInvalidPageSize},
+			{"unknown type", dao.TransactionFilter{Type: "bonus"}, "", logic.ErrInvalidTxType},
+			{"start after end", dao.TransactionFilter{Start: &later, End: &base}, "", logic.ErrInvalidTimeRange},
+			{"negative amount", dao.TransactionFilter{MinAmount: &neg}, "", logic.ErrInvalidAmountRange},
+			{"min above max", dao.TransactionFilter{MinAmount: &high, MaxAmount: &low}, "", logic.ErrInvalidAmountRange},
+			{"garbage cursor", dao.TransactionFilter{}, "not-a-cursor!", logic.ErrInvalidCursor},
+		}
+		for _, tc := range cases {
+			_, err := impl.GetTransactionHistory(ctx, tc.filter, tc.cursor)
+			assert.Equal(t, tc.err, err, tc.name)
+		}
+	})
 }
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of a webhook body, keyed with the webhook secret.
+const SignatureHeader = "X-Wallet-Signature"
+
+// EventHeader carries the type of the event in a webhook body.
+const EventHeader = "X-Wallet-Event"
+
+// Event tells the members of WalletIDs that something happened to the entity EntityID.
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	WalletIDs []string  `json:"wallet_ids"`
+	EntityID  string    `json:"entity_id"`
+	Data      any       `json:"data,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+//go:generate mockery --name=Notifier --output=./mocks --outpkg=mocks
+type Notifier interface {
+	Notify(ctx context.Context, ev Event) error
+}
+
+// LogNotifier writes events to the log. It is used when no webhook is configured.
+type LogNotifier struct {
+	logger *logrus.Entry
+}
+
+func NewLogNotifier(baseLogger *logrus.Logger) *LogNotifier {
+	return &LogNotifier{logger: baseLogger.WithField("tag", "NOTIFY")}
+}
+
+func (n *LogNotifier) Notify(_ context.Context, ev Event) error {
+	n.logger.Infof("Event %s %s for %s on %s", ev.ID, ev.Type, ev.EntityID, ev.WalletIDs)
+	return nil
+}
+
+// Webhook posts every event as JSON to one URL. When Secret is set the body is signed in
+// SignatureHeader so the receiver can tell the event came from the wallet service.
+type Webhook struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhook(url, secret string, timeout time.Duration) *Webhook {
+	return &Webhook{url: url, secret: secret, client: &http.Client{Timeout: timeout}}
+}
+
+// Notify delivers one event. Any response but a 2xx is an error; events are not retried.
+func (n *Webhook) Notify(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, ev.Type)
+	if n.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of body keyed with secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
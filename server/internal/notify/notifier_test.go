@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSignsEvents(t *testing.T) {
+	var got Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, Sign("s3cret", body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, "payment_request.created", r.Header.Get(EventHeader))
+		assert.NoError(t, json.Unmarshal(body, &got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL, "s3cret", time.Second)
+	err := webhook.Notify(context.Background(), Event{ID: "ev-1", Type: "payment_request.created", WalletIDs: []string{"wallet-1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "ev-1", got.ID)
+	assert.Equal(t, []string{"wallet-1"}, got.WalletIDs)
+}
+
+func TestWebhookRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get(SignatureHeader))
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := NewWebhook(server.URL, "", time.Second).Notify(context.Background(), Event{ID: "ev-1"})
+	assert.ErrorContains(t, err, "502")
+}
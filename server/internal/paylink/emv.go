@@ -0,0 +1,118 @@
+package paylink
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Field IDs of the EMV merchant-presented QR code payload used by payment links.
+const (
+	idPayloadFormat   = "00"
+	idInitiation      = "01"
+	idMerchantAccount = "26"
+	idCategoryCode    = "52"
+	idCurrency        = "53"
+	idAmount          = "54"
+	idCountry         = "58"
+	idMerchantName    = "59"
+	idMerchantCity    = "60"
+	idAdditionalData  = "62"
+	idCRC             = "63"
+
+	// sub-fields of the merchant account and additional data templates
+	idAccountGUID    = "00"
+	idAccountToken   = "01"
+	idReferenceLabel = "05"
+)
+
+const (
+	// initiationStatic marks a code that may be paid many times, initiationDynamic one that is
+	// paid once.
+	initiationStatic  = "11"
+	initiationDynamic = "12"
+
+	maxMerchantName = 25
+	maxMerchantCity = 15
+	maxReference    = 25
+)
+
+// EMVPayload is what a payment link QR code carries, laid out as an EMV merchant-presented
+// payload. The token goes in a merchant account template under GUID, so a banking app that
+// does not know the wallet service can still show the payee, amount and reference.
+type EMVPayload struct {
+	GUID         string
+	Token        string
+	SingleUse    bool
+	Currency     string // ISO 4217 numeric code
+	Amount       *float64
+	Country      string
+	MerchantName string
+	MerchantCity string
+	Reference    string
+}
+
+// String returns the payload with its trailing CRC. Names, city and reference are cut to the
+// lengths the format allows.
+func (p EMVPayload) String() string {
+	var b strings.Builder
+	writeField(&b, idPayloadFormat, "01")
+	if p.SingleUse {
+		writeField(&b, idInitiation, initiationDynamic)
+	} else {
+		writeField(&b, idInitiation, initiationStatic)
+	}
+
+	var account strings.Builder
+	writeField(&account, idAccountGUID, p.GUID)
+	writeField(&account, idAccountToken, p.Token)
+	writeField(&b, idMerchantAccount, account.String())
+
+	writeField(&b, idCategoryCode, "0000")
+	writeField(&b, idCurrency, p.Currency)
+	if p.Amount != nil {
+		writeField(&b, idAmount, strconv.FormatFloat(*p.Amount, 'f', -1, 64))
+	}
+	writeField(&b, idCountry, p.Country)
+	writeField(&b, idMerchantName, truncate(p.MerchantName, maxMerchantName))
+	writeField(&b, idMerchantCity, truncate(p.MerchantCity, maxMerchantCity))
+	if p.Reference != "" {
+		var additional strings.Builder
+		writeField(&additional, idReferenceLabel, truncate(p.Reference, maxReference))
+		writeField(&b, idAdditionalData, additional.String())
+	}
+
+	b.WriteString(idCRC + "04")
+	return fmt.Sprintf("%s%04X", b.String(), CRC16([]byte(b.String())))
+}
+
+// writeField appends one ID, length, value field. Empty values are left out.
+func writeField(b *strings.Builder, id, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Fprintf(b, "%s%02d%s", id, len(value), value)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
+
+// CRC16 is the CRC-16/CCITT-FALSE checksum EMV payloads end with.
+func CRC16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, c := range data {
+		crc ^= uint16(c) << 8
+		for range 8 {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
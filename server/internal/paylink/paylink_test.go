@@ -0,0 +1,78 @@
+package paylink
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const linkID = "70000000-0000-0000-0000-000000000001"
+
+func TestSigner(t *testing.T) {
+	signer := NewSigner("secret")
+	token, err := signer.Sign(linkID)
+	assert.NoError(t, err)
+	assert.Len(t, token, 45)
+
+	got, err := signer.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, linkID, got)
+
+	t.Run("other secret", func(t *testing.T) {
+		_, err := NewSigner("other").Verify(token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		other, _ := signer.Sign("70000000-0000-0000-0000-000000000002")
+		idPart, _, _ := strings.Cut(other, ".")
+		_, macPart, _ := strings.Cut(token, ".")
+		_, err := signer.Verify(idPart + "." + macPart)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, bad := range []string{"", "abc", token[:30], token + "x", strings.Replace(token, ".", "", 1)} {
+			_, err := signer.Verify(bad)
+			assert.ErrorIs(t, err, ErrInvalidToken, bad)
+		}
+	})
+
+	t.Run("not a uuid", func(t *testing.T) {
+		_, err := signer.Sign("link-1")
+		assert.Error(t, err)
+	})
+}
+
+func TestCRC16(t *testing.T) {
+	assert.Equal(t, uint16(0x29B1), CRC16([]byte("123456789")))
+}
+
+func TestEMVPayload(t *testing.T) {
+	amount := 12.5
+	payload := EMVPayload{
+		GUID:         "com.walletapp",
+		Token:        "tok",
+		SingleUse:    true,
+		Currency:     "840",
+		Amount:       &amount,
+		Country:      "US",
+		MerchantName: "Alice Merchant With A Very Long Name",
+		MerchantCity: "San Francisco",
+		Reference:    "INV-42",
+	}.String()
+
+	want := "000201" + "010212" + "2624" + "0013com.walletapp" + "0103tok" + "52040000" + "5303840" +
+		"540412.5" + "5802US" + "5925Alice Merchant With A Ver" + "6013San Francisco" + "62100506INV-42" + "6304"
+	assert.True(t, strings.HasPrefix(payload, want), payload)
+	assert.Len(t, payload, len(want)+4)
+	assert.Equal(t, fmt.Sprintf("%04X", CRC16([]byte(want))), payload[len(want):])
+
+	t.Run("reusable without amount or reference", func(t *testing.T) {
+		payload := EMVPayload{GUID: "g", Token: "t", Currency: "840", Country: "US", MerchantName: "Bob", MerchantCity: "NYC"}.String()
+		assert.Equal(t, "00020101021126100001g0101t5204000053038405802US5903Bob6003NYC6304", payload[:len(payload)-4])
+		assert.NotContains(t, payload, "5404")
+	})
+}
@@ -0,0 +1,76 @@
+package payout
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidName = errors.New("beneficiary name is required and at most 70 characters")
+	ErrInvalidIBAN = errors.New("invalid IBAN")
+	ErrInvalidBIC  = errors.New("invalid BIC")
+)
+
+// MaxNameLength is the longest party name every pain.001 version accepts.
+const MaxNameLength = 70
+
+var (
+	ibanPattern = regexp.MustCompile(`^[A-Z]{2}[0-9]{2}[A-Z0-9]{11,30}$`)
+	bicPattern  = regexp.MustCompile(`^[A-Z]{4}[A-Z]{2}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+)
+
+// Account is a bank account money is paid from or to.
+type Account struct {
+	Name string `json:"name"`
+	IBAN string `json:"iban"`
+	BIC  string `json:"bic,omitempty"`
+}
+
+// Normalize upper-cases the IBAN and BIC and removes the spaces they are usually printed with.
+func (a Account) Normalize() Account {
+	return Account{
+		Name: strings.TrimSpace(a.Name),
+		IBAN: compact(a.IBAN),
+		BIC:  compact(a.BIC),
+	}
+}
+
+// Validate checks a normalized account. The BIC is optional.
+func (a Account) Validate() error {
+	if a.Name == "" || utf8.RuneCountInString(a.Name) > MaxNameLength {
+		return ErrInvalidName
+	}
+	if !ValidIBAN(a.IBAN) {
+		return ErrInvalidIBAN
+	}
+	if a.BIC != "" && !bicPattern.MatchString(a.BIC) {
+		return ErrInvalidBIC
+	}
+	return nil
+}
+
+// ValidIBAN checks the format and the ISO 7064 mod-97 check digits of a compact IBAN.
+func ValidIBAN(iban string) bool {
+	if !ibanPattern.MatchString(iban) {
+		return false
+	}
+	rearranged := iban[4:] + iban[:4]
+	var digits strings.Builder
+	for _, r := range rearranged {
+		if r >= 'A' && r <= 'Z' {
+			digits.WriteString(strconv.Itoa(int(r-'A') + 10))
+		} else {
+			digits.WriteRune(r)
+		}
+	}
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+func compact(s string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(s), ""))
+}
@@ -0,0 +1,192 @@
+package payout
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// Pain001Namespace is the ISO 20022 customer credit transfer initiation version we write.
+const Pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.09"
+
+// notProvided stands in for an unknown agent BIC, as the schema requires an identification.
+const notProvided = "NOTPROVIDED"
+
+var ErrEmptyBatch = errors.New("payout batch has no payments")
+
+// Batch is one credit transfer initiation: every payment is sent from the same debtor account
+// on the same execution date.
+type Batch struct {
+	MessageID     string
+	CreatedAt     time.Time
+	ExecutionDate time.Time
+	Debtor        Account
+	Currency      string
+	Payments      []Payment
+}
+
+// Payment is a single credit transfer. EndToEndID travels with the money to the beneficiary
+// and back on returns, so it must identify the payout; it is at most 35 characters.
+type Payment struct {
+	EndToEndID string
+	Amount     float64
+	Creditor   Account
+	Remittance string
+}
+
+// ControlSum is the total of all payment amounts, as written in the file.
+func (b Batch) ControlSum() float64 {
+	var sum float64
+	for _, p := range b.Payments {
+		sum += p.Amount
+	}
+	return sum
+}
+
+type pain001Document struct {
+	XMLName  xml.Name        `xml:"Document"`
+	Xmlns    string          `xml:"xmlns,attr"`
+	Initiate pain001Initiate `xml:"CstmrCdtTrfInitn"`
+}
+
+type pain001Initiate struct {
+	Header      pain001Header      `xml:"GrpHdr"`
+	PaymentInfo pain001PaymentInfo `xml:"PmtInf"`
+}
+
+type pain001Header struct {
+	MessageID      string       `xml:"MsgId"`
+	CreatedAt      string       `xml:"CreDtTm"`
+	NumberOfTxs    int          `xml:"NbOfTxs"`
+	ControlSum     string       `xml:"CtrlSum"`
+	InitiatingName pain001Party `xml:"InitgPty"`
+}
+
+type pain001PaymentInfo struct {
+	ID            string              `xml:"PmtInfId"`
+	Method        string              `xml:"PmtMtd"`
+	BatchBooking  bool                `xml:"BtchBookg"`
+	NumberOfTxs   int                 `xml:"NbOfTxs"`
+	ControlSum    string              `xml:"CtrlSum"`
+	ExecutionDate string              `xml:"ReqdExctnDt>Dt"`
+	Debtor        pain001Party        `xml:"Dbtr"`
+	DebtorAccount pain001AccountID    `xml:"DbtrAcct"`
+	DebtorAgent   pain001Agent        `xml:"DbtrAgt"`
+	Transfers     []pain001CreditInfo `xml:"CdtTrfTxInf"`
+}
+
+type pain001Party struct {
+	Name string `xml:"Nm"`
+}
+
+type pain001AccountID struct {
+	IBAN string `xml:"Id>IBAN"`
+}
+
+type pain001Agent struct {
+	BIC   string `xml:"FinInstnId>BICFI,omitempty"`
+	Other string `xml:"FinInstnId>Othr>Id,omitempty"`
+}
+
+type pain001CreditInfo struct {
+	InstructionID   string             `xml:"PmtId>InstrId"`
+	EndToEndID      string             `xml:"PmtId>EndToEndId"`
+	Amount          pain001Amount      `xml:"Amt>InstdAmt"`
+	CreditorAgent   *pain001Agent      `xml:"CdtrAgt,omitempty"`
+	Creditor        pain001Party       `xml:"Cdtr"`
+	CreditorAccount pain001AccountID   `xml:"CdtrAcct"`
+	Remittance      *pain001Remittance `xml:"RmtInf,omitempty"`
+}
+
+type pain001Remittance struct {
+	Unstructured string `xml:"Ustrd"`
+}
+
+type pain001Amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// WritePain001 writes the batch as a pain.001.001.09 XML document.
+func WritePain001(w io.Writer, b Batch) error {
+	if len(b.Payments) == 0 {
+		return ErrEmptyBatch
+	}
+
+	controlSum := formatAmount(b.ControlSum())
+	info := pain001PaymentInfo{
+		ID:            b.MessageID,
+		Method:        "TRF",
+		BatchBooking:  true,
+		NumberOfTxs:   len(b.Payments),
+		ControlSum:    controlSum,
+		ExecutionDate: b.ExecutionDate.UTC().Format("2006-01-02"),
+		Debtor:        pain001Party{Name: b.Debtor.Name},
+		DebtorAccount: pain001AccountID{IBAN: b.Debtor.IBAN},
+		DebtorAgent:   agent(b.Debtor.BIC),
+	}
+	for _, p := range b.Payments {
+		transfer := pain001CreditInfo{
+			InstructionID:   p.EndToEndID,
+			EndToEndID:      p.EndToEndID,
+			Amount:          pain001Amount{Currency: b.Currency, Value: formatAmount(p.Amount)},
+			Creditor:        pain001Party{Name: p.Creditor.Name},
+			CreditorAccount: pain001AccountID{IBAN: p.Creditor.IBAN},
+		}
+		if p.Remittance != "" {
+			transfer.Remittance = &pain001Remittance{Unstructured: truncateRunes(p.Remittance, 140)}
+		}
+		if p.Creditor.BIC != "" {
+			creditorAgent := agent(p.Creditor.BIC)
+			transfer.CreditorAgent = &creditorAgent
+		}
+		info.Transfers = append(info.Transfers, transfer)
+	}
+
+	doc := pain001Document{
+		Xmlns: Pain001Namespace,
+		Initiate: pain001Initiate{
+			Header: pain001Header{
+				MessageID:      b.MessageID,
+				CreatedAt:      b.CreatedAt.UTC().Format("2006-01-02T15:04:05Z"),
+				NumberOfTxs:    len(b.Payments),
+				ControlSum:     controlSum,
+				InitiatingName: pain001Party{Name: b.Debtor.Name},
+			},
+			PaymentInfo: info,
+		},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func agent(bic string) pain001Agent {
+	if bic == "" {
+		return pain001Agent{Other: notProvided}
+	}
+	return pain001Agent{BIC: bic}
+}
+
+// formatAmount writes amounts with two decimals; payouts are validated to whole cents.
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
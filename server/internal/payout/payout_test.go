@@ -0,0 +1,70 @@
+package payout
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountValidate(t *testing.T) {
+	valid := Account{Name: " Jane Doe ", IBAN: "de89 3704 0044 0532 0130 00", BIC: "cobadeffxxx"}.Normalize()
+	assert.Equal(t, Account{Name: "Jane Doe", IBAN: "DE89370400440532013000", BIC: "COBADEFFXXX"}, valid)
+	assert.NoError(t, valid.Validate())
+
+	for name, tc := range map[string]struct {
+		account Account
+		err     error
+	}{
+		"missing name":     {Account{IBAN: "DE89370400440532013000"}, ErrInvalidName},
+		"long name":        {Account{Name: strings.Repeat("x", 71), IBAN: "DE89370400440532013000"}, ErrInvalidName},
+		"bad check digits": {Account{Name: "Jane", IBAN: "DE88370400440532013000"}, ErrInvalidIBAN},
+		"too short":        {Account{Name: "Jane", IBAN: "DE8937040044"}, ErrInvalidIBAN},
+		"bad bic":          {Account{Name: "Jane", IBAN: "GB82WEST12345698765432", BIC: "WEST"}, ErrInvalidBIC},
+	} {
+		assert.ErrorIs(t, tc.account.Normalize().Validate(), tc.err, name)
+	}
+	assert.True(t, ValidIBAN("GB82WEST12345698765432"))
+}
+
+func TestWritePain001(t *testing.T) {
+	created := time.Date(2026, 3, 3, 15, 4, 5, 0, time.UTC)
+	batch := Batch{
+		MessageID:     "a1b2c3",
+		CreatedAt:     created,
+		ExecutionDate: created,
+		Debtor:        Account{Name: "Wallet App Ltd", IBAN: "DE89370400440532013000"},
+		Currency:      "USD",
+		Payments: []Payment{
+			{EndToEndID: "p1", Amount: 150, Creditor: Account{Name: "Jane Doe", IBAN: "GB82WEST12345698765432", BIC: "WESTGB2L"}, Remittance: "Wallet withdrawal"},
+			{EndToEndID: "p2", Amount: 20.5, Creditor: Account{Name: "John Roe", IBAN: "GB82WEST12345698765432"}},
+		},
+	}
+
+	var out strings.Builder
+	assert.NoError(t, WritePain001(&out, batch))
+	doc := out.String()
+
+	assert.True(t, strings.HasPrefix(doc, `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, doc, `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.09">`)
+	assert.Contains(t, doc, `<CreDtTm>2026-03-03T15:04:05Z</CreDtTm>`)
+	assert.Equal(t, 2, strings.Count(doc, `<CtrlSum>170.50</CtrlSum>`))
+	assert.Contains(t, doc, `<ReqdExctnDt>`+"\n"+`        <Dt>2026-03-03</Dt>`)
+	assert.Contains(t, doc, `<InstdAmt Ccy="USD">20.50</InstdAmt>`)
+	assert.Contains(t, doc, `<BICFI>WESTGB2L</BICFI>`)
+	// the debtor has no BIC
+	assert.Contains(t, doc, `<Othr>`+"\n"+`            <Id>NOTPROVIDED</Id>`)
+	assert.Equal(t, 1, strings.Count(doc, `<CdtrAgt>`))
+	assert.Equal(t, 1, strings.Count(doc, `<RmtInf>`))
+
+	// the output parses back as XML
+	var parsed struct {
+		EndToEndIDs []string `xml:"CstmrCdtTrfInitn>PmtInf>CdtTrfTxInf>PmtId>EndToEndId"`
+	}
+	assert.NoError(t, xml.Unmarshal([]byte(doc), &parsed))
+	assert.Equal(t, []string{"p1", "p2"}, parsed.EndToEndIDs)
+
+	assert.ErrorIs(t, WritePain001(&out, Batch{}), ErrEmptyBatch)
+}
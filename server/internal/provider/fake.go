@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	mathrand "math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// FakeName is the name the built-in fake provider registers under.
+const FakeName = "fake"
+
+// FakeSignatureHeader carries the hex HMAC-SHA256 of a fake callback body.
+const FakeSignatureHeader = "X-Fake-Signature"
+
+// FakeConfig tunes the fake provider. Latency delays every call, SettleAfter is how long a payout
+// stays pending and FailureRate is the share of payouts, between 0 and 1, that end up failed.
+// When Secret is set callbacks must be signed with it.
+type FakeConfig struct {
+	Latency     time.Duration
+	SettleAfter time.Duration
+	FailureRate float64
+	Secret      string
+}
+
+// Fake is an in-memory provider for local testing. It settles payouts on its own after
+// SettleAfter and accepts hand-written callbacks, so both the polling and the callback path can
+// be exercised without a real rail.
+type Fake struct {
+	cfg     FakeConfig
+	now     func() time.Time
+	mu      sync.Mutex
+	payouts map[string]*fakePayout
+}
+
+type fakePayout struct {
+	providerReference string
+	settlesAt         time.Time
+	fails             bool
+}
+
+type fakeCallback struct {
+	Type              string  `json:"type"`
+	Reference         string  `json:"reference"`
+	ProviderReference string  `json:"provider_reference"`
+	Status            string  `json:"status"`
+	FailureReason     string  `json:"failure_reason"`
+	WalletID          string  `json:"wallet_id"`
+	Amount            float64 `json:"amount"`
+}
+
+func NewFake(cfg FakeConfig) *Fake {
+	return &Fake{cfg: cfg, now: time.Now, payouts: map[string]*fakePayout{}}
+}
+
+func (f *Fake) Name() string {
+	return FakeName
+}
+
+// InitiatePayout accepts the payout and decides up front whether it will fail. Initiating the
+// same reference again returns the payout already accepted.
+func (f *Fake) InitiatePayout(ctx context.Context, req PayoutRequest) (Result, error) {
+	if err := f.wait(ctx); err != nil {
+		return Result{}, err
+	}
+	if req.Amount <= 0 {
+		return Result{}, fmt.Errorf("%w: amount must be positive", ErrRejected)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, ok := f.payouts[req.Reference]
+	if !ok {
+		p = &fakePayout{
+			providerReference: "fake_" + randomHex(8),
+			settlesAt:         f.now().Add(f.cfg.SettleAfter),
+			fails:             mathrand.Float64() < f.cfg.FailureRate,
+		}
+		f.payouts[req.Reference] = p
+	}
+	return f.result(p), nil
+}
+
+func (f *Fake) QueryStatus(ctx context.Context, reference string) (Result, error) {
+	if err := f.wait(ctx); err != nil {
+		return Result{}, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, ok := f.payouts[reference]
+	if !ok {
+		return Result{}, ErrUnknownReference
+	}
+	return f.result(p), nil
+}
+
+// HandleCallback decodes a single JSON event, checking its signature when a secret is set.
+func (f *Fake) HandleCallback(ctx context.Context, header http.Header, body []byte) ([]Event, error) {
+	if f.cfg.Secret != "" && !hmac.Equal([]byte(header.Get(FakeSignatureHeader)), []byte(f.Sign(body))) {
+		return nil, ErrInvalidSignature
+	}
+
+	var cb fakeCallback
+	if err := json.Unmarshal(body, &cb); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedCallback, err)
+	}
+	switch cb.Type {
+	case EventPayout:
+		if cb.Reference == "" || (cb.Status != StatusSucceeded && cb.Status != StatusFailed) {
+			return nil, fmt.Errorf("%w: payout events need a reference and a final status", ErrMalformedCallback)
+		}
+	case EventPayin:
+		if cb.ProviderReference == "" || cb.WalletID == "" || cb.Amount <= 0 {
+			return nil, fmt.Errorf("%w: payin events need a provider_reference, wallet_id and positive amount", ErrMalformedCallback)
+		}
+		if cb.Status == "" {
+			cb.Status = StatusSucceeded
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown event type %q", ErrMalformedCallback, cb.Type)
+	}
+
+	return []Event{{
+		Kind:              cb.Type,
+		Reference:         cb.Reference,
+		ProviderReference: cb.ProviderReference,
+		Status:            cb.Status,
+		FailureReason:     cb.FailureReason,
+		WalletID:          cb.WalletID,
+		Amount:            cb.Amount,
+	}}, nil
+}
+
+// Sign returns the signature HandleCallback expects for body.
+func (f *Fake) Sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(f.cfg.Secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (f *Fake) result(p *fakePayout) Result {
+	res := Result{ProviderReference: p.providerReference, Status: StatusPending}
+	if f.now().Before(p.settlesAt) {
+		return res
+	}
+	if p.fails {
+		res.Status = StatusFailed
+		res.FailureReason = "fake provider declined the payout"
+		return res
+	}
+	res.Status = StatusSucceeded
+	return res
+}
+
+func (f *Fake) wait(ctx context.Context) error {
+	if f.cfg.Latency <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(f.cfg.Latency)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
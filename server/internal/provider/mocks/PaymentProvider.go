@@ -0,0 +1,135 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	http "net/http"
+
+	mock "github.com/stretchr/testify/mock"
+
+	provider "github.com/julkhong/walletapp/server/internal/provider"
+)
+
+// PaymentProvider is an autogenerated mock type for the PaymentProvider type
+type PaymentProvider struct {
+	mock.Mock
+}
+
+// HandleCallback provides a mock function with given fields: ctx, header, body
+func (_m *PaymentProvider) HandleCallback(ctx context.Context, header http.Header, body []byte) ([]provider.Event, error) {
+	ret := _m.Called(ctx, header, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HandleCallback")
+	}
+
+	var r0 []provider.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, http.Header, []byte) ([]provider.Event, error)); ok {
+		return rf(ctx, header, body)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, http.Header, []byte) []provider.Event); ok {
+		r0 = rf(ctx, header, body)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]provider.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, http.Header, []byte) error); ok {
+		r1 = rf(ctx, header, body)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InitiatePayout provides a mock function with given fields: ctx, req
+func (_m *PaymentProvider) InitiatePayout(ctx context.Context, req provider.PayoutRequest) (provider.Result, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InitiatePayout")
+	}
+
+	var r0 provider.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, provider.PayoutRequest) (provider.Result, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, provider.PayoutRequest) provider.Result); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(provider.Result)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, provider.PayoutRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Name provides a mock function with no fields
+func (_m *PaymentProvider) Name() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Name")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// QueryStatus provides a mock function with given fields: ctx, reference
+func (_m *PaymentProvider) QueryStatus(ctx context.Context, reference string) (provider.Result, error) {
+	ret := _m.Called(ctx, reference)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryStatus")
+	}
+
+	var r0 provider.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (provider.Result, error)); ok {
+		return rf(ctx, reference)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) provider.Result); ok {
+		r0 = rf(ctx, reference)
+	} else {
+		r0 = ret.Get(0).(provider.Result)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPaymentProvider creates a new instance of PaymentProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPaymentProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PaymentProvider {
+	mock := &PaymentProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
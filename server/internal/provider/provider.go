@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+)
+
+var (
+	ErrUnknownReference  = errors.New("provider does not know the reference")
+	ErrRejected          = errors.New("provider rejected the payout")
+	ErrInvalidSignature  = errors.New("callback signature is invalid")
+	ErrMalformedCallback = errors.New("callback body is malformed")
+)
+
+// Operation statuses reported by providers.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Event kinds carried by provider callbacks.
+const (
+	EventPayout = "payout"
+	EventPayin  = "payin"
+)
+
+// PayoutRequest asks a provider to send money out of a wallet. Reference is our operation ID;
+// providers treat it as an idempotency key and accept it when querying the payout's status.
+type PayoutRequest struct {
+	Reference   string
+	WalletID    string
+	Amount      float64
+	Currency    string
+	Destination string
+}
+
+// Result is what a provider reports about a payout.
+type Result struct {
+	ProviderReference string
+	Status            string
+	FailureReason     string
+}
+
+// Event is one notification decoded from a provider callback. Payout events identify the payout
+// by Reference; payin events report money received for WalletID and are deduplicated on
+// ProviderReference.
+type Event struct {
+	Kind              string
+	Reference         string
+	ProviderReference string
+	Status            string
+	FailureReason     string
+	WalletID          string
+	Amount            float64
+}
+
+// PaymentProvider is an external payment rail money can leave or enter wallets through.
+// InitiatePayout returns ErrRejected, possibly wrapped, when the provider refuses the payout
+// outright; any other error leaves the outcome unknown until QueryStatus or a callback reports it.
+//
+//go:generate mockery --name=PaymentProvider --output=./mocks --outpkg=mocks
+type PaymentProvider interface {
+	Name() string
+	InitiatePayout(ctx context.Context, req PayoutRequest) (Result, error)
+	QueryStatus(ctx context.Context, reference string) (Result, error)
+	HandleCallback(ctx context.Context, header http.Header, body []byte) ([]Event, error)
+}
+
+// Registry holds the configured providers by name.
+type Registry struct {
+	providers map[string]PaymentProvider
+}
+
+func NewRegistry(providers ...PaymentProvider) *Registry {
+	r := &Registry{providers: map[string]PaymentProvider{}}
+	for _, p := range providers {
+		r.Register(p)
+	}
+	return r
+}
+
+// Register adds a provider, replacing any provider of the same name.
+func (r *Registry) Register(p PaymentProvider) {
+	r.providers[p.Name()] = p
+}
+
+func (r *Registry) Get(name string) (PaymentProvider, bool) {
+	if r == nil {
+		return nil, false
+	}
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+// Names returns the registered provider names in sorted order.
+func (r *Registry) Names() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeSettlesAfterDelay(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	fake := NewFake(FakeConfig{SettleAfter: time.Minute})
+	fake.now = func() time.Time { return now }
+
+	res, err := fake.InitiatePayout(context.Background(), PayoutRequest{Reference: "op-1", Amount: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPending, res.Status)
+	assert.NotEmpty(t, res.ProviderReference)
+
+	again, err := fake.InitiatePayout(context.Background(), PayoutRequest{Reference: "op-1", Amount: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, res.ProviderReference, again.ProviderReference)
+
+	now = now.Add(time.Minute)
+	res, err = fake.QueryStatus(context.Background(), "op-1")
+	assert.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, res.Status)
+
+	_, err = fake.QueryStatus(context.Background(), "op-2")
+	assert.ErrorIs(t, err, ErrUnknownReference)
+}
+
+func TestFakeFailureRate(t *testing.T) {
+	fake := NewFake(FakeConfig{FailureRate: 1})
+
+	res, err := fake.InitiatePayout(context.Background(), PayoutRequest{Reference: "op-1", Amount: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, StatusFailed, res.Status)
+	assert.NotEmpty(t, res.FailureReason)
+
+	_, err = fake.InitiatePayout(context.Background(), PayoutRequest{Reference: "op-2"})
+	assert.ErrorIs(t, err, ErrRejected)
+}
+
+func TestFakeLatencyHonoursContext(t *testing.T) {
+	fake := NewFake(FakeConfig{Latency: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := fake.InitiatePayout(ctx, PayoutRequest{Reference: "op-1", Amount: 10})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestFakeHandleCallback(t *testing.T) {
+	fake := NewFake(FakeConfig{Secret: "s3cret"})
+	payin := []byte(`{"type": "payin", "provider_reference": "pi_1", "wallet_id": "wallet-1", "amount": 12.5}`)
+
+	header := http.Header{}
+	header.Set(FakeSignatureHeader, fake.Sign(payin))
+	events, err := fake.HandleCallback(context.Background(), header, payin)
+	assert.NoError(t, err)
+	assert.Equal(t, []Event{{Kind: EventPayin, ProviderReference: "pi_1", Status: StatusSucceeded, WalletID: "wallet-1", Amount: 12.5}}, events)
+
+	_, err = fake.HandleCallback(context.Background(), http.Header{}, payin)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	unsigned := NewFake(FakeConfig{})
+	_, err = unsigned.HandleCallback(context.Background(), http.Header{}, []byte(`{"type": "payout", "reference": "op-1", "status": "pending"}`))
+	assert.ErrorIs(t, err, ErrMalformedCallback)
+
+	_, err = unsigned.HandleCallback(context.Background(), http.Header{}, []byte(`not json`))
+	assert.ErrorIs(t, err, ErrMalformedCallback)
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(NewFake(FakeConfig{}))
+
+	p, ok := registry.Get(FakeName)
+	assert.True(t, ok)
+	assert.Equal(t, FakeName, p.Name())
+
+	_, ok = registry.Get("acme")
+	assert.False(t, ok)
+	assert.Equal(t, []string{FakeName}, registry.Names())
+
+	var empty *Registry
+	_, ok = empty.Get(FakeName)
+	assert.False(t, ok)
+}
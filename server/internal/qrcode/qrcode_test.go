@@ -0,0 +1,119 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// the data and error correction codewords of HELLO WORLD at 1-M, from the standard's worked example
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	assert.Equal(t, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}, rsRemainder(data, rsDivisor(10)))
+}
+
+func TestCapacity(t *testing.T) {
+	for v := minVersion + 1; v <= maxVersion; v++ {
+		assert.Greater(t, dataCodewords(v), dataCodewords(v-1), v)
+	}
+	assert.Equal(t, 16, dataCodewords(1))
+	assert.Equal(t, 216, dataCodewords(10))
+	assert.Equal(t, 2334, dataCodewords(40))
+
+	assert.Empty(t, alignmentPositions(1))
+	assert.Equal(t, []int{6, 22, 38}, alignmentPositions(7))
+	assert.Equal(t, []int{6, 34, 60, 86, 112, 138}, alignmentPositions(32))
+	assert.Equal(t, []int{6, 30, 58, 86, 114, 142, 170}, alignmentPositions(40))
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	want := []int{
+		0b101010000010010, 0b101000100100101, 0b101111001111100, 0b101101101001011,
+		0b100010111111001, 0b100000011001110, 0b100111110010111, 0b100101010100000,
+	}
+	for mask, bits := range want {
+		assert.Equal(t, bits, formatBits(mask), mask)
+	}
+	assert.Equal(t, 0b000111110010010100, versionBits(7))
+	assert.Equal(t, 0b101000110001101001, versionBits(40))
+}
+
+func TestEncode(t *testing.T) {
+	t.Run("smallest version that fits", func(t *testing.T) {
+		code, err := Encode([]byte(strings.Repeat("a", 14)))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, code.Version)
+		assert.Equal(t, 21, code.Size)
+
+		code, err = Encode([]byte(strings.Repeat("a", 15)))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, code.Version)
+
+		code, err = Encode([]byte(strings.Repeat("a", 2331)))
+		assert.NoError(t, err)
+		assert.Equal(t, 40, code.Version)
+
+		_, err = Encode([]byte(strings.Repeat("a", 2332)))
+		assert.ErrorIs(t, err, ErrTooLong)
+	})
+
+	t.Run("function patterns", func(t *testing.T) {
+		code, err := Encode([]byte("00020101021126"))
+		assert.NoError(t, err)
+		last := code.Size - 1
+		for _, corner := range [][2]int{{0, 0}, {last - 6, 0}, {0, last - 6}} {
+			x, y := corner[0], corner[1]
+			assert.True(t, code.Dark(x, y))
+			assert.False(t, code.Dark(x+1, y+1))
+			assert.True(t, code.Dark(x+3, y+3))
+		}
+		for i := 8; i < code.Size-8; i++ {
+			assert.Equal(t, i%2 == 0, code.Dark(i, 6))
+			assert.Equal(t, i%2 == 0, code.Dark(6, i))
+		}
+		assert.True(t, code.Dark(8, code.Size-8))
+
+		// both copies of the format carry the same valid bits
+		first, second := 0, 0
+		firstAt := [][2]int{{8, 0}, {8, 1}, {8, 2}, {8, 3}, {8, 4}, {8, 5}, {8, 7}, {8, 8}, {7, 8}, {5, 8}, {4, 8}, {3, 8}, {2, 8}, {1, 8}, {0, 8}}
+		for i, at := range firstAt {
+			if code.Dark(at[0], at[1]) {
+				first |= 1 << i
+			}
+			x, y := last-i, 8
+			if i >= 8 {
+				x, y = 8, code.Size-15+i
+			}
+			if code.Dark(x, y) {
+				second |= 1 << i
+			}
+		}
+		assert.Equal(t, first, second)
+		assert.Contains(t, []int{formatBits(0), formatBits(1), formatBits(2), formatBits(3),
+			formatBits(4), formatBits(5), formatBits(6), formatBits(7)}, first)
+	})
+}
+
+func TestPNG(t *testing.T) {
+	code, err := Encode([]byte("https://example.com/pay"))
+	assert.NoError(t, err)
+
+	out, err := code.PNG(4)
+	assert.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(out))
+	assert.NoError(t, err)
+
+	dim := (code.Size + 2*quietZone) * 4
+	assert.Equal(t, dim, img.Bounds().Dx())
+	isDark := func(x, y int) bool {
+		r, _, _, _ := img.At(x, y).RGBA()
+		return r == 0
+	}
+	assert.False(t, isDark(0, 0))
+	assert.True(t, isDark(quietZone*4, quietZone*4))
+	assert.True(t, isDark(quietZone*4+3, quietZone*4+3))
+	assert.False(t, isDark(quietZone*4+4, quietZone*4+4))
+}
@@ -0,0 +1,51 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalid = errors.New("invalid recurrence")
+
+// searchDays bounds how far ahead Next looks for an occurrence. Ten years covers every rule that
+// matches at all, including February 29 and long monthly intervals.
+const searchDays = 3660
+
+// Rule yields the occurrences of a recurring schedule.
+type Rule interface {
+	// Next returns the first occurrence strictly after t. It returns false when there is none.
+	Next(t time.Time) (time.Time, bool)
+}
+
+// Parse reads a five-field cron expression such as "0 9 1 * *" or an RFC 5545 RRULE such as
+// "RRULE:FREQ=MONTHLY;BYMONTHDAY=1". start is the first moment the schedule may fire; it anchors
+// RRULE intervals and supplies the default day and time of day. Occurrences are computed in
+// start's location.
+func Parse(expr string, start time.Time) (Rule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("%w: empty expression", ErrInvalid)
+	}
+	upper := strings.ToUpper(expr)
+	if strings.HasPrefix(upper, "RRULE:") || strings.Contains(upper, "FREQ=") {
+		return parseRRule(strings.TrimPrefix(upper, "RRULE:"), start)
+	}
+	return parseCron(expr, start)
+}
+
+// civilDay is midnight UTC of t's calendar date in its own location, so whole days can be
+// counted without daylight saving time getting in the way.
+func civilDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(a, b time.Time) int {
+	return int(civilDay(b).Sub(civilDay(a)).Hours() / 24)
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,97 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// occurrences returns the first n occurrences of expr from start.
+func occurrences(t *testing.T, expr string, start time.Time, n int) []string {
+	rule, err := Parse(expr, start)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	var out []string
+	at := start.Add(-time.Nanosecond)
+	for len(out) < n {
+		next, ok := rule.Next(at)
+		if !ok {
+			break
+		}
+		out = append(out, next.Format(time.RFC3339))
+		at = next
+	}
+	return out
+}
+
+func TestCron(t *testing.T) {
+	start := date("2026-01-15T10:30:00Z")
+
+	assert.Equal(t, []string{"2026-02-01T09:00:00Z", "2026-03-01T09:00:00Z", "2026-04-01T09:00:00Z"},
+		occurrences(t, "0 9 1 * *", start, 3))
+	assert.Equal(t, []string{"2026-01-15T12:00:00Z", "2026-01-15T18:00:00Z", "2026-01-16T00:00:00Z"},
+		occurrences(t, "0 */6 * * *", start, 3))
+	// weekdays only
+	assert.Equal(t, []string{"2026-01-16T08:15:00Z", "2026-01-19T08:15:00Z"},
+		occurrences(t, "15 8 * * 1-5", start, 2))
+	// restricted day of month and day of week fire on either
+	assert.Equal(t, []string{"2026-01-18T00:00:00Z", "2026-01-20T00:00:00Z", "2026-01-25T00:00:00Z"},
+		occurrences(t, "0 0 20 * 7", start, 3))
+	assert.Equal(t, []string{"2028-02-29T00:00:00Z"}, occurrences(t, "0 0 29 2 *", start, 1))
+}
+
+func TestCronRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{"0 9 1 *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		_, err := Parse(expr, time.Now())
+		assert.ErrorIs(t, err, ErrInvalid, expr)
+	}
+}
+
+func TestRRule(t *testing.T) {
+	start := date("2026-01-15T10:30:00Z")
+
+	assert.Equal(t, []string{"2026-02-01T10:30:00Z", "2026-03-01T10:30:00Z"},
+		occurrences(t, "RRULE:FREQ=MONTHLY;BYMONTHDAY=1", start, 2))
+	// the start's day of month by default, skipping months without it
+	assert.Equal(t, []string{"2026-01-31T09:00:00Z", "2026-03-31T09:00:00Z"},
+		occurrences(t, "FREQ=MONTHLY;BYHOUR=9;BYMINUTE=0", date("2026-01-31T09:00:00Z"), 2))
+	assert.Equal(t, []string{"2026-01-31T10:30:00Z", "2026-02-28T10:30:00Z"},
+		occurrences(t, "FREQ=MONTHLY;BYMONTHDAY=-1", start, 2))
+	// every other week on Monday and Friday, counted from the start's week
+	assert.Equal(t, []string{"2026-01-16T10:30:00Z", "2026-01-26T10:30:00Z", "2026-01-30T10:30:00Z"},
+		occurrences(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", start, 3))
+	assert.Equal(t, []string{"2026-01-15T10:30:00Z", "2026-01-18T10:30:00Z", "2026-01-21T10:30:00Z"},
+		occurrences(t, "FREQ=DAILY;INTERVAL=3", start, 3))
+	assert.Equal(t, []string{"2026-01-15T10:30:00Z", "2026-01-16T10:30:00Z"},
+		occurrences(t, "FREQ=DAILY;UNTIL=20260116", start, 5))
+}
+
+func TestRRuleRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"RRULE:FREQ=YEARLY", "RRULE:INTERVAL=2", "FREQ=DAILY;COUNT=3", "FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYMONTHDAY=0", "FREQ=DAILY;INTERVAL=0", "FREQ=DAILY;UNTIL=tomorrow",
+	} {
+		_, err := Parse(expr, time.Now())
+		assert.ErrorIs(t, err, ErrInvalid, expr)
+	}
+}
+
+func TestNextBeforeStart(t *testing.T) {
+	start := date("2026-03-01T00:00:00Z")
+	rule, err := Parse("0 0 * * *", start)
+	assert.NoError(t, err)
+
+	next, ok := rule.Next(date("2026-01-01T00:00:00Z"))
+	assert.True(t, ok)
+	assert.Equal(t, start, next)
+}
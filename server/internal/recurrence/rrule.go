@@ -0,0 +1,204 @@
+package recurrence
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rrule is the part of RFC 5545 recurrence rules schedules need: FREQ of DAILY, WEEKLY or
+// MONTHLY with INTERVAL, BYDAY (without ordinals), BYMONTHDAY, BYHOUR, BYMINUTE and UNTIL.
+// Weeks start on Monday.
+type rrule struct {
+	freq      string
+	interval  int
+	weekdays  map[time.Weekday]bool
+	monthDays []int
+	hours     []int
+	minutes   []int
+	until     time.Time
+	start     time.Time
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+func parseRRule(expr string, start time.Time) (*rrule, error) {
+	r := &rrule{interval: 1, start: start}
+	for _, part := range strings.Split(expr, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed RRULE part %q", ErrInvalid, part)
+		}
+
+		var err error
+		switch key {
+		case "FREQ":
+			if value != "DAILY" && value != "WEEKLY" && value != "MONTHLY" {
+				return nil, fmt.Errorf("%w: FREQ must be DAILY, WEEKLY or MONTHLY", ErrInvalid)
+			}
+			r.freq = value
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err == nil && r.interval < 1 {
+				err = fmt.Errorf("must be at least 1")
+			}
+		case "BYDAY":
+			r.weekdays = map[time.Weekday]bool{}
+			for _, name := range strings.Split(value, ",") {
+				day, ok := rruleWeekdays[name]
+				if !ok {
+					return nil, fmt.Errorf("%w: unsupported BYDAY value %q", ErrInvalid, name)
+				}
+				r.weekdays[day] = true
+			}
+		case "BYMONTHDAY":
+			r.monthDays, err = parseInts(value, -31, 31)
+			for _, d := range r.monthDays {
+				if d == 0 {
+					err = fmt.Errorf("0 is not a day")
+				}
+			}
+		case "BYHOUR":
+			r.hours, err = parseInts(value, 0, 23)
+		case "BYMINUTE":
+			r.minutes, err = parseInts(value, 0, 59)
+		case "UNTIL":
+			r.until, err = parseUntil(value)
+		case "WKST":
+			if value != "MO" {
+				err = fmt.Errorf("only MO is supported")
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported RRULE part %s", ErrInvalid, key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s=%s: %v", ErrInvalid, key, value, err)
+		}
+	}
+	if r.freq == "" {
+		return nil, fmt.Errorf("%w: RRULE needs FREQ", ErrInvalid)
+	}
+
+	if r.freq == "WEEKLY" && r.weekdays == nil {
+		r.weekdays = map[time.Weekday]bool{start.Weekday(): true}
+	}
+	if r.freq == "MONTHLY" && r.weekdays == nil && r.monthDays == nil {
+		r.monthDays = []int{start.Day()}
+	}
+	if r.hours == nil {
+		r.hours = []int{start.Hour()}
+	}
+	if r.minutes == nil {
+		r.minutes = []int{start.Minute()}
+	}
+	sort.Ints(r.hours)
+	sort.Ints(r.minutes)
+	return r, nil
+}
+
+func parseInts(value string, min, max int) ([]int, error) {
+	var out []int
+	for _, raw := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", raw)
+		}
+		if n < min || n > max {
+			return nil, fmt.Errorf("%d is outside %d..%d", n, min, max)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// a date-only UNTIL includes the whole day
+				t = t.Add(24*time.Hour - time.Nanosecond)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("expected YYYYMMDD or YYYYMMDDTHHMMSSZ")
+}
+
+func (r *rrule) Next(t time.Time) (time.Time, bool) {
+	loc := r.start.Location()
+	if t.Before(r.start) {
+		t = r.start.Add(-time.Nanosecond)
+	}
+	t = t.In(loc)
+
+	y, m, d := t.Date()
+	for i := 0; i <= searchDays; i++ {
+		day := time.Date(y, m, d+i, 0, 0, 0, 0, loc)
+		if !r.until.IsZero() && day.After(r.until) {
+			return time.Time{}, false
+		}
+		if !r.inInterval(day) || !r.matchesDay(day) {
+			continue
+		}
+		for _, h := range r.hours {
+			for _, min := range r.minutes {
+				candidate := time.Date(day.Year(), day.Month(), day.Day(), h, min, r.start.Second(), 0, loc)
+				if !candidate.After(t) {
+					continue
+				}
+				if !r.until.IsZero() && candidate.After(r.until) {
+					return time.Time{}, false
+				}
+				return candidate, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// inInterval reports whether day falls in a period INTERVAL periods apart from the start's.
+func (r *rrule) inInterval(day time.Time) bool {
+	var periods int
+	switch r.freq {
+	case "DAILY":
+		periods = daysBetween(r.start, day)
+	case "WEEKLY":
+		periods = daysBetween(mondayOf(r.start), mondayOf(day)) / 7
+	case "MONTHLY":
+		periods = (day.Year()-r.start.Year())*12 + int(day.Month()) - int(r.start.Month())
+	}
+	return periods%r.interval == 0
+}
+
+func (r *rrule) matchesDay(day time.Time) bool {
+	if r.weekdays != nil && !r.weekdays[day.Weekday()] {
+		return false
+	}
+	if r.monthDays == nil {
+		return true
+	}
+	last := daysIn(day.Year(), day.Month())
+	for _, d := range r.monthDays {
+		if d < 0 {
+			d = last + d + 1
+		}
+		if d == day.Day() {
+			return true
+		}
+	}
+	return false
+}
+
+func mondayOf(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	y, m, d := t.Date()
+	return time.Date(y, m, d-offset, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,122 @@
+package risk
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+)
+
+// Event describes a money movement about to be committed.
+type Event struct {
+	Type                 string
+	WalletID             string
+	CounterpartyWalletID string
+	Amount               float64
+	At                   time.Time
+}
+
+// Result is the outcome of screening an event, with the names of the rules that matched.
+type Result struct {
+	Action string   `json:"action"`
+	Hits   []string `json:"hits"`
+}
+
+//go:generate mockery --name=Screener --output=./mocks --outpkg=mocks
+type Screener interface {
+	Screen(ctx context.Context, ev Event) (Result, error)
+}
+
+// History is the read access the engine needs to evaluate history-based rules.
+type History interface {
+	GetWalletByID(walletID string) (*dao.Wallet, error)
+	CountOutgoingTransactions(walletID string, since time.Time) (int64, error)
+	HasTransferBetween(fromWalletID, toWalletID string, since time.Time) (bool, error)
+}
+
+type Engine struct {
+	rules   []Rule
+	history History
+	logger  *logrus.Entry
+}
+
+func NewEngine(rules []Rule, history History, baseLogger *logrus.Logger) *Engine {
+	logger := baseLogger.WithField("tag", "RISK-ENGINE")
+	return &Engine{rules: rules, history: history, logger: logger}
+}
+
+// Screen evaluates every applicable rule and returns the most severe action.
+func (e *Engine) Screen(ctx context.Context, ev Event) (Result, error) {
+	if ev.At.IsZero() {
+		ev.At = time.Now()
+	}
+
+	result := Result{Action: ActionAllow}
+	for i := range e.rules {
+		rule := &e.rules[i]
+		if !rule.appliesTo(ev.Type) {
+			continue
+		}
+
+		hit, err := e.evaluate(rule, ev)
+		if err != nil {
+			e.logger.WithError(err).Errorf("Failed to evaluate rule %s", rule.Name)
+			return Result{}, err
+		}
+		if !hit {
+			continue
+		}
+
+		result.Hits = append(result.Hits, rule.Name)
+		if severity(rule.Action) > severity(result.Action) {
+			result.Action = rule.Action
+		}
+	}
+
+	if result.Action != ActionAllow {
+		e.logger.Warnf("Screening %s of %.4f from wallet %s: %s (%v)", ev.Type, ev.Amount, ev.WalletID, result.Action, result.Hits)
+	}
+	return result, nil
+}
+
+func (e *Engine) evaluate(rule *Rule, ev Event) (bool, error) {
+	switch rule.Type {
+	case RuleTypeAmountThreshold:
+		return ev.Amount >= rule.MinAmount, nil
+	case RuleTypeVelocity:
+		count, err := e.history.CountOutgoingTransactions(ev.WalletID, ev.At.Add(-rule.window))
+		if err != nil {
+			return false, err
+		}
+		// the current movement counts towards the limit
+		return count+1 > rule.MaxCount, nil
+	case RuleTypeRoundTrip:
+		if ev.Type != common.TransactionTypeTransfer || ev.CounterpartyWalletID == "" {
+			return false, nil
+		}
+		return e.history.HasTransferBetween(ev.CounterpartyWalletID, ev.WalletID, ev.At.Add(-rule.window))
+	case RuleTypeNewWalletOutflow:
+		if ev.Amount < rule.MinAmount {
+			return false, nil
+		}
+		wallet, err := e.history.GetWalletByID(ev.WalletID)
+		if err != nil {
+			return false, err
+		}
+		return ev.At.Sub(wallet.CreatedAt) < rule.maxWalletAge, nil
+	}
+	return false, nil
+}
+
+func severity(action string) int {
+	switch action {
+	case ActionDeny:
+		return 2
+	case ActionReview:
+		return 1
+	}
+	return 0
+}
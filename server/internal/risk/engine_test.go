@@ -0,0 +1,146 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeHistory struct {
+	walletCreatedAt time.Time
+	outgoing        int64
+	reverseTransfer bool
+	err             error
+}
+
+func (f *fakeHistory) GetWalletByID(walletID string) (*dao.Wallet, error) {
+	return &dao.Wallet{ID: walletID, CreatedAt: f.walletCreatedAt}, f.err
+}
+
+func (f *fakeHistory) CountOutgoingTransactions(walletID string, since time.Time) (int64, error) {
+	return f.outgoing, f.err
+}
+
+func (f *fakeHistory) HasTransferBetween(fromWalletID, toWalletID string, since time.Time) (bool, error) {
+	return f.reverseTransfer, f.err
+}
+
+const testRules = `{
+  "rules": [
+    {"name": "large", "type": "amount_threshold", "action": "review", "applies_to": ["transfer"], "min_amount": 1000},
+    {"name": "huge", "type": "amount_threshold", "action": "deny", "min_amount": 5000},
+    {"name": "rapid", "type": "velocity", "action": "review", "max_count": 3, "window": "1m"},
+    {"name": "round_trip", "type": "round_trip", "action": "review", "window": "1h"},
+    {"name": "new_wallet", "type": "new_wallet_outflow", "action": "deny", "min_amount": 500, "max_wallet_age": "24h"}
+  ]
+}`
+
+func setupEngine(t *testing.T, history *fakeHistory) *Engine {
+	rules, err := ParseRules([]byte(testRules))
+	assert.NoError(t, err)
+	return NewEngine(rules, history, logrus.New())
+}
+
+func TestScreen(t *testing.T) {
+	ctx := context.TODO()
+	oldWallet := time.Now().Add(-30 * 24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		history *fakeHistory
+		event   Event
+		action  string
+		hits    []string
+	}{
+		{
+			name:    "small transfer allowed",
+			history: &fakeHistory{walletCreatedAt: oldWallet},
+			event:   Event{Type: "transfer", WalletID: "a", CounterpartyWalletID: "b", Amount: 10},
+			action:  ActionAllow,
+		},
+		{
+			name:    "large transfer reviewed",
+			history: &fakeHistory{walletCreatedAt: oldWallet},
+			event:   Event{Type: "transfer", WalletID: "a", CounterpartyWalletID: "b", Amount: 2000},
+			action:  ActionReview,
+			hits:    []string{"large"},
+		},
+		{
+			name:    "large withdraw not covered by transfer rule",
+			history: &fakeHistory{walletCreatedAt: oldWallet},
+			event:   Event{Type: "withdraw", WalletID: "a", Amount: 2000},
+			action:  ActionAllow,
+		},
+		{
+			name:    "deny outranks review",
+			history: &fakeHistory{walletCreatedAt: oldWallet},
+			event:   Event{Type: "transfer", WalletID: "a", CounterpartyWalletID: "b", Amount: 6000},
+			action:  ActionDeny,
+			hits:    []string{"large", "huge"},
+		},
+		{
+			name:    "rapid fire outflows",
+			history: &fakeHistory{walletCreatedAt: oldWallet, outgoing: 3},
+			event:   Event{Type: "withdraw", WalletID: "a", Amount: 10},
+			action:  ActionReview,
+			hits:    []string{"rapid"},
+		},
+		{
+			name:    "round trip",
+			history: &fakeHistory{walletCreatedAt: oldWallet, reverseTransfer: true},
+			event:   Event{Type: "transfer", WalletID: "a", CounterpartyWalletID: "b", Amount: 10},
+			action:  ActionReview,
+			hits:    []string{"round_trip"},
+		},
+		{
+			name:    "new wallet large outflow",
+			history: &fakeHistory{walletCreatedAt: time.Now().Add(-time.Hour)},
+			event:   Event{Type: "withdraw", WalletID: "a", Amount: 600},
+			action:  ActionDeny,
+			hits:    []string{"new_wallet"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := setupEngine(t, tt.history).Screen(ctx, tt.event)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.action, result.Action)
+			assert.Equal(t, tt.hits, result.Hits)
+		})
+	}
+
+	t.Run("history failure", func(t *testing.T) {
+		engine := setupEngine(t, &fakeHistory{err: errors.New("db down")})
+		_, err := engine.Screen(ctx, Event{Type: "withdraw", WalletID: "a", Amount: 10})
+		assert.Error(t, err)
+	})
+}
+
+func TestParseRules(t *testing.T) {
+	t.Run("invalid action", func(t *testing.T) {
+		_, err := ParseRules([]byte(`{"rules":[{"name":"x","type":"amount_threshold","action":"block","min_amount":1}]}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		_, err := ParseRules([]byte(`{"rules":[{"name":"x","type":"velocity","action":"review","max_count":1,"window":"soon"}]}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := ParseRules([]byte(`{"rules":[{"name":"x","type":"geo","action":"deny"}]}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("shipped rules file is valid", func(t *testing.T) {
+		rules, err := LoadRules("../../rules/risk_rules.json")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, rules)
+	})
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	risk "github.com/julkhong/walletapp/server/internal/risk"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Screener is an autogenerated mock type for the Screener type
+type Screener struct {
+	mock.Mock
+}
+
+// Screen provides a mock function with given fields: ctx, ev
+func (_m *Screener) Screen(ctx context.Context, ev risk.Event) (risk.Result, error) {
+	ret := _m.Called(ctx, ev)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Screen")
+	}
+
+	var r0 risk.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, risk.Event) (risk.Result, error)); ok {
+		return rf(ctx, ev)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, risk.Event) risk.Result); ok {
+		r0 = rf(ctx, ev)
+	} else {
+		r0 = ret.Get(0).(risk.Result)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, risk.Event) error); ok {
+		r1 = rf(ctx, ev)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewScreener creates a new instance of Screener. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewScreener(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Screener {
+	mock := &Screener{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,114 @@
+package risk
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	ActionAllow  = "allow"
+	ActionReview = "review"
+	ActionDeny   = "deny"
+)
+
+const (
+	RuleTypeAmountThreshold  = "amount_threshold"
+	RuleTypeVelocity         = "velocity"
+	RuleTypeRoundTrip        = "round_trip"
+	RuleTypeNewWalletOutflow = "new_wallet_outflow"
+)
+
+// Rule is a single screening rule as defined in the rules file.
+// Only the fields relevant to the rule type need to be set.
+type Rule struct {
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	Action       string   `json:"action"`
+	AppliesTo    []string `json:"applies_to"`
+	MinAmount    float64  `json:"min_amount"`
+	MaxCount     int64    `json:"max_count"`
+	Window       string   `json:"window"`
+	MaxWalletAge string   `json:"max_wallet_age"`
+
+	window       time.Duration
+	maxWalletAge time.Duration
+}
+
+type ruleFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// LoadRules reads and validates a JSON rules file.
+func LoadRules(path string) ([]Rule, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rules file: %w", err)
+	}
+	return ParseRules(raw)
+}
+
+func ParseRules(raw []byte) ([]Rule, error) {
+	var file ruleFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("parse rules file: %w", err)
+	}
+
+	for i := range file.Rules {
+		if err := file.Rules[i].validate(); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", file.Rules[i].Name, err)
+		}
+	}
+	return file.Rules, nil
+}
+
+func (r *Rule) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("missing name")
+	}
+	if r.Action != ActionReview && r.Action != ActionDeny {
+		return fmt.Errorf("invalid action %q", r.Action)
+	}
+
+	var err error
+	switch r.Type {
+	case RuleTypeAmountThreshold:
+		if r.MinAmount <= 0 {
+			return fmt.Errorf("min_amount must be positive")
+		}
+	case RuleTypeVelocity:
+		if r.MaxCount <= 0 {
+			return fmt.Errorf("max_count must be positive")
+		}
+		if r.window, err = time.ParseDuration(r.Window); err != nil {
+			return fmt.Errorf("invalid window: %w", err)
+		}
+	case RuleTypeRoundTrip:
+		if r.window, err = time.ParseDuration(r.Window); err != nil {
+			return fmt.Errorf("invalid window: %w", err)
+		}
+	case RuleTypeNewWalletOutflow:
+		if r.MinAmount <= 0 {
+			return fmt.Errorf("min_amount must be positive")
+		}
+		if r.maxWalletAge, err = time.ParseDuration(r.MaxWalletAge); err != nil {
+			return fmt.Errorf("invalid max_wallet_age: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown rule type %q", r.Type)
+	}
+	return nil
+}
+
+func (r *Rule) appliesTo(txType string) bool {
+	if len(r.AppliesTo) == 0 {
+		return true
+	}
+	for _, t := range r.AppliesTo {
+		if t == txType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	screening "github.com/julkhong/walletapp/server/internal/screening"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Checker is an autogenerated mock type for the Checker type
+type Checker struct {
+	mock.Mock
+}
+
+// Check provides a mock function with given fields: name, email
+func (_m *Checker) Check(name string, email string) (*screening.Match, bool) {
+	ret := _m.Called(name, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Check")
+	}
+
+	var r0 *screening.Match
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(string, string) (*screening.Match, bool)); ok {
+		return rf(name, email)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *screening.Match); ok {
+		r0 = rf(name, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*screening.Match)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) bool); ok {
+		r1 = rf(name, email)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// NewChecker creates a new instance of Checker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewChecker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Checker {
+	mock := &Checker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
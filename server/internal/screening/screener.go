@@ -0,0 +1,151 @@
+package screening
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Entry is a single listed party loaded from a sanctions or blocklist CSV file.
+type Entry struct {
+	ListName string
+	Name     string
+	Email    string
+
+	normalizedName string
+}
+
+type Match struct {
+	ListName string
+	Name     string
+	Email    string
+	Score    float64
+}
+
+//go:generate mockery --name=Checker --output=./mocks --outpkg=mocks
+type Checker interface {
+	Check(name, email string) (*Match, bool)
+}
+
+type Screener struct {
+	entries   []Entry
+	threshold float64
+	logger    *logrus.Entry
+}
+
+func NewScreener(entries []Entry, threshold float64, baseLogger *logrus.Logger) *Screener {
+	logger := baseLogger.WithField("tag", "SANCTIONS-SCREENING")
+	for i := range entries {
+		entries[i].normalizedName = NormalizeName(entries[i].Name)
+		entries[i].Email = NormalizeEmail(entries[i].Email)
+	}
+	logger.Infof("Loaded %d sanctions/blocklist entries", len(entries))
+	return &Screener{entries: entries, threshold: threshold, logger: logger}
+}
+
+// LoadLists reads every *.csv file in dir. Files must have a header row with a "name"
+// column and an optional "email" column; the file name is used as the list name.
+func LoadLists(dir string) ([]Entry, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.csv"))
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, path := range paths {
+		listName := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		listEntries, err := loadList(path, listName)
+		if err != nil {
+			return nil, fmt.Errorf("load list %s: %w", path, err)
+		}
+		entries = append(entries, listEntries...)
+	}
+	return entries, nil
+}
+
+func loadList(path, listName string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	nameCol, emailCol := -1, -1
+	for i, col := range header {
+		switch strings.ToLower(strings.TrimSpace(col)) {
+		case "name":
+			nameCol = i
+		case "email":
+			emailCol = i
+		}
+	}
+	if nameCol < 0 {
+		return nil, errors.New("missing name column")
+	}
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		entry := Entry{ListName: listName}
+		if nameCol < len(record) {
+			entry.Name = strings.TrimSpace(record[nameCol])
+		}
+		if emailCol >= 0 && emailCol < len(record) {
+			entry.Email = strings.TrimSpace(record[emailCol])
+		}
+		if entry.Name == "" && entry.Email == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Check returns the best match for the given name and email. An exact email match
+// scores 1; names match when their similarity reaches the screener threshold.
+func (s *Screener) Check(name, email string) (*Match, bool) {
+	normalizedName := NormalizeName(name)
+	normalizedEmail := NormalizeEmail(email)
+
+	var best *Match
+	for i := range s.entries {
+		entry := &s.entries[i]
+
+		score := 0.0
+		if normalizedEmail != "" && entry.Email == normalizedEmail {
+			score = 1
+		} else if normalizedName != "" && entry.normalizedName != "" {
+			score = Similarity(normalizedName, entry.normalizedName)
+		}
+
+		if score >= s.threshold && (best == nil || score > best.Score) {
+			best = &Match{ListName: entry.ListName, Name: entry.Name, Email: entry.Email, Score: score}
+		}
+	}
+
+	if best != nil {
+		s.logger.Warnf("Screening match for %q on list %s (score %.2f)", name, best.ListName, best.Score)
+		return best, true
+	}
+	return nil, false
+}
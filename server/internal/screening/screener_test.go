@@ -0,0 +1,81 @@
+package screening
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"John Doe", "doe john"},
+		{"Doe, John", "doe john"},
+		{"  JOSÉ   Müller ", "jose muller"},
+		{"O'Brien-Smith", "brien o smith"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, NormalizeName(tt.input))
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, Levenshtein("abc", "abc"))
+	assert.Equal(t, 3, Levenshtein("", "abc"))
+	assert.Equal(t, 1, Levenshtein("mallory", "malory"))
+	assert.Equal(t, 3, Levenshtein("kitten", "sitting"))
+}
+
+func TestScreenerCheck(t *testing.T) {
+	entries := []Entry{
+		{ListName: "ofac", Name: "Mallory Blackwood", Email: "Mallory@Blocked.example"},
+		{ListName: "internal", Name: "Ivan Q. Fraudster"},
+	}
+	screener := NewScreener(entries, 0.85, logrus.New())
+
+	t.Run("fuzzy name match", func(t *testing.T) {
+		match, found := screener.Check("Blackwood, Malory", "someone@example.com")
+		assert.True(t, found)
+		assert.Equal(t, "ofac", match.ListName)
+		assert.Less(t, match.Score, 1.0)
+	})
+
+	t.Run("exact email match", func(t *testing.T) {
+		match, found := screener.Check("Completely Different", "mallory@blocked.example")
+		assert.True(t, found)
+		assert.Equal(t, 1.0, match.Score)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		_, found := screener.Check("Alice Smith", "alice@example.com")
+		assert.False(t, found)
+	})
+}
+
+func TestLoadLists(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "blocklist.csv"), []byte("email,name\nx@example.com,Eve Evil\n,Bad Actor\n"), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "ignored.txt"), []byte("name\nNobody\n"), 0o600)
+	assert.NoError(t, err)
+
+	entries, err := LoadLists(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "blocklist", entries[0].ListName)
+	assert.Equal(t, "Eve Evil", entries[0].Name)
+	assert.Equal(t, "x@example.com", entries[0].Email)
+
+	t.Run("missing name column", func(t *testing.T) {
+		bad := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(bad, "bad.csv"), []byte("email\nx@example.com\n"), 0o600))
+		_, err := LoadLists(bad)
+		assert.Error(t, err)
+	})
+}
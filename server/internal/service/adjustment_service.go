@@ -0,0 +1,94 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+func (s *WalletService) CreateAdjustmentHandler(w http.ResponseWriter, r *http.Request) {
+	actorID := common.GetActorID(r)
+	if actorID == "" {
+		common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+		return
+	}
+
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	if idempotencyKey == "" {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Missing Idempotency-Key")
+		return
+	}
+
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+
+	if record, found := s.Dao.CheckIdempotencyKey(idempotencyKey, r.Method, r.URL.Path); found {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(record.StatusCode)
+		_, _ = w.Write([]byte(record.Response))
+		return
+	}
+
+	var req dto.AdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+
+	op, err := s.Impl.SubmitAdjustment(r.Context(), logic.AdjustmentPayload{
+		WalletID:    walletID,
+		Amount:      req.Amount,
+		ReasonCode:  req.ReasonCode,
+		Note:        req.Note,
+		RequestedBy: actorID,
+	}, idempotencyKey, r.Method, r.URL.Path)
+	if err != nil {
+		s.logger.WithError(err).Error("Adjustment submission failed")
+		switch err {
+		case logic.ErrWalletNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
+		case logic.ErrInvalidReasonCode:
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidReasonCode, err.Error())
+		case logic.ErrInvalidAdjustmentAmount, logic.ErrAdjustmentNoteRequired:
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, "Adjustment failed")
+		}
+		return
+	}
+
+	s.writeIdempotent(w, r, idempotencyKey, http.StatusAccepted, dto.GenericResponse[dto.PendingApprovalResponse]{
+		Status: "pending",
+		Data: dto.PendingApprovalResponse{
+			Message:    "adjustment pending approval",
+			ApprovalID: op.ID,
+		},
+	})
+}
+
+func (s *WalletService) ListAdjustmentsHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+
+	adjustments, err := s.Impl.ListAdjustments(r.Context(), walletID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list adjustments")
+		common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to list adjustments")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[[]dao.Adjustment]{
+		Status: "success",
+		Data:   adjustments,
+	})
+}
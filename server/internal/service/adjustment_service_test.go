@@ -0,0 +1,99 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAdjustmentHandler(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	walletID := "10000000-0000-0000-0000-000000000001"
+	path := "/admin/wallets/" + walletID + "/adjustments"
+	body := `{"amount":25,"reason_code":"goodwill","note":"outage credit"}`
+
+	t.Run("adjustment submitted for approval", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "key-adj")
+		req.Header.Set("X-Actor-ID", "operator-1")
+		req = withRouteParam(req, "id", walletID)
+
+		daoMock.On("CheckIdempotencyKey", "key-adj", "POST", path).Return(nil, false).Once()
+		logicMock.On("SubmitAdjustment", mock.Anything, logic.AdjustmentPayload{
+			WalletID: walletID, Amount: 25, ReasonCode: "goodwill", Note: "outage credit", RequestedBy: "operator-1",
+		}, "key-adj", "POST", path).Return(&dao.PendingOperation{ID: "op-adj"}, nil).Once()
+		daoMock.On("SaveIdempotencyKey", mock.MatchedBy(func(r *dao.IdempotencyRecord) bool {
+			return r.Key == "key-adj" && r.StatusCode == http.StatusAccepted
+		})).Return(nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.CreateAdjustmentHandler(w, req)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Contains(t, w.Body.String(), "op-adj")
+		logicMock.AssertExpectations(t)
+	})
+
+	t.Run("operator identity required", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "key-adj")
+		req = withRouteParam(req, "id", walletID)
+
+		w := httptest.NewRecorder()
+		svc.CreateAdjustmentHandler(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid reason code", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"amount":25,"reason_code":"x","note":"n"}`))
+		req.Header.Set("Idempotency-Key", "key-bad")
+		req.Header.Set("X-Actor-ID", "operator-1")
+		req = withRouteParam(req, "id", walletID)
+
+		daoMock.On("CheckIdempotencyKey", "key-bad", "POST", path).Return(nil, false).Once()
+		logicMock.On("SubmitAdjustment", mock.Anything, mock.Anything, "key-bad", "POST", path).
+			Return(nil, logic.ErrInvalidReasonCode).Once()
+
+		w := httptest.NewRecorder()
+		svc.CreateAdjustmentHandler(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "1017")
+	})
+}
+
+func TestApproveAdjustmentStoresOutcome(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	operationID := "30000000-0000-0000-0000-000000000002"
+	op := &dao.PendingOperation{
+		ID:             operationID,
+		Type:           common.TransactionTypeAdjustment,
+		Payload:        `{"wallet_id":"10000000-0000-0000-0000-000000000001","amount":25,"reason_code":"goodwill","note":"n","requested_by":"maker"}`,
+		Status:         common.ApprovalStatusApproved,
+		IdempotencyKey: "key-adj",
+		RequestMethod:  "POST",
+		RequestPath:    "/admin/wallets/10000000-0000-0000-0000-000000000001/adjustments",
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/approvals/"+operationID+"/approve", nil)
+	req.Header.Set("X-Actor-ID", "checker")
+	req = withRouteParam(req, "id", operationID)
+
+	logicMock.On("ApprovePendingOperation", mock.Anything, operationID, "checker").Return(op, nil).Once()
+	logicMock.On("GetBalance", mock.Anything, "10000000-0000-0000-0000-000000000001").Return(125.0, nil).Once()
+	daoMock.On("UpdateIdempotencyKey", mock.MatchedBy(func(r *dao.IdempotencyRecord) bool {
+		return r.Key == "key-adj" && r.StatusCode == http.StatusOK && strings.Contains(r.Response, "adjustment success")
+	})).Return(nil).Once()
+
+	w := httptest.NewRecorder()
+	svc.ApproveHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	daoMock.AssertExpectations(t)
+}
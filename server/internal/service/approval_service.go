@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+func (s *WalletService) ListApprovalsHandler(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = common.ApprovalStatusPending
+	}
+
+	ops, err := s.Impl.ListPendingOperations(r.Context(), status)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list pending operations")
+		common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to list approvals")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[[]dao.PendingOperation]{
+		Status: "success",
+		Data:   ops,
+	})
+}
+
+func (s *WalletService) GetApprovalHandler(w http.ResponseWriter, r *http.Request) {
+	operationID := chi.URLParam(r, "id")
+	if operationID == "" || !isUUID(w, operationID, "approval_id") {
+		return
+	}
+
+	op, err := s.Impl.GetPendingOperation(r.Context(), operationID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to fetch pending operation")
+		if err == logic.ErrApprovalNotFound {
+			common.WriteError(w, http.StatusNotFound, common.ErrApprovalNotFound, err.Error())
+		} else {
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to fetch approval")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*dao.PendingOperation]{
+		Status: "success",
+		Data:   op,
+	})
+}
+
+func (s *WalletService) ApproveHandler(w http.ResponseWriter, r *http.Request) {
+	s.decideApproval(w, r, true)
+}
+
+func (s *WalletService) RejectHandler(w http.ResponseWriter, r *http.Request) {
+	s.decideApproval(w, r, false)
+}
+
+func (s *WalletService) decideApproval(w http.ResponseWriter, r *http.Request, approve bool) {
+	actorID := common.GetActorID(r)
+	if actorID == "" {
+		common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+		return
+	}
+
+	operationID := chi.URLParam(r, "id")
+	if operationID == "" || !isUUID(w, operationID, "approval_id") {
+		return
+	}
+
+	var (
+		op      *dao.PendingOperation
+		execErr error
+	)
+	if approve {
+		op, execErr = s.Impl.ApprovePendingOperation(r.Context(), operationID, actorID)
+	} else {
+		op, execErr = s.Impl.RejectPendingOperation(r.Context(), operationID, actorID)
+	}
+
+	if op == nil {
+		s.logger.WithError(execErr).Error("Approval decision failed")
+		if execErr == logic.ErrApprovalExpired {
+			s.storeExpiredOutcome(r.Context(), operationID)
+		}
+		switch execErr {
+		case logic.ErrApprovalNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrApprovalNotFound, execErr.Error())
+		case logic.ErrApprovalNotPending, logic.ErrApprovalExpired:
+			common.WriteError(w, http.StatusConflict, common.ErrApprovalConflict, execErr.Error())
+		case logic.ErrSelfApproval:
+			common.WriteError(w, http.StatusForbidden, common.ErrSelfApproval, execErr.Error())
+		case logic.ErrInitiatorRequired:
+			common.WriteError(w, http.StatusForbidden, common.ErrUnauthorized, execErr.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, "Approval decision failed")
+		}
+		return
+	}
+
+	s.storeApprovalOutcome(r.Context(), op, execErr)
+
+	if execErr != nil {
+		s.logger.WithError(execErr).Errorf("Approved operation %s did not complete", op.ID)
+		statusCode, _ := s.approvalOutcome(r.Context(), op, execErr)
+		status := "failed"
+		if statusCode == http.StatusAccepted {
+			status = "pending"
+		}
+		common.WriteJSON(w, statusCode, dto.GenericResponse[*dao.PendingOperation]{
+			Status: status,
+			Data:   op,
+		})
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*dao.PendingOperation]{
+		Status: "success",
+		Data:   op,
+	})
+}
+
+// ExpirePendingOperations expires overdue operations and finalizes their idempotency records.
+func (s *WalletService) ExpirePendingOperations(ctx context.Context) error {
+	ops, err := s.Impl.ExpirePendingOperations(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range ops {
+		s.storeApprovalOutcome(ctx, &ops[i], nil)
+	}
+	return nil
+}
+
+// storeExpiredOutcome finalizes the idempotency record of an operation that expired when it was decided.
+func (s *WalletService) storeExpiredOutcome(ctx context.Context, operationID string) {
+	op, err := s.Impl.GetPendingOperation(ctx, operationID)
+	if err != nil {
+		s.logger.WithError(err).Errorf("Failed to fetch expired operation %s", operationID)
+		return
+	}
+	if op.Status == common.ApprovalStatusExpired {
+		s.storeApprovalOutcome(ctx, op, nil)
+	}
+}
+
+// storeApprovalOutcome replaces the pending response saved for the original idempotency key
+// with the final outcome of the operation.
+func (s *WalletService) storeApprovalOutcome(ctx context.Context, op *dao.PendingOperation, execErr error) {
+	if op.IdempotencyKey == "" {
+		return
+	}
+
+	statusCode, resp := s.approvalOutcome(ctx, op, execErr)
+	respJSON, _ := json.Marshal(resp)
+
+	// the review an approved operation was parked in answers the same request once decided
+	var pending *logic.PendingReviewError
+	if errors.As(execErr, &pending) {
+		s.setReviewRequest(pending.ReviewID, op.IdempotencyKey, op.RequestMethod, op.RequestPath)
+	}
+
+	err := s.Dao.UpdateIdempotencyKey(&dao.IdempotencyRecord{
+		Key:        op.IdempotencyKey,
+		Method:     op.RequestMethod,
+		Path:       op.RequestPath,
+		Response:   string(respJSON),
+		StatusCode: statusCode,
+	})
+	if err != nil {
+		s.logger.WithError(err).Errorf("Failed to store outcome of operation %s", op.ID)
+	}
+}
+
+func (s *WalletService) approvalOutcome(ctx context.Context, op *dao.PendingOperation, execErr error) (int, any) {
+	switch op.Status {
+	case common.ApprovalStatusRejected:
+		return http.StatusForbidden, common.ErrorResponse{
+			Error: common.ErrorDetail{Code: common.ErrApprovalRejected, Message: "operation rejected"},
+		}
+	case common.ApprovalStatusExpired:
+		return http.StatusConflict, common.ErrorResponse{
+			Error: common.ErrorDetail{Code: common.ErrApprovalConflict, Message: logic.ErrApprovalExpired.Error()},
+		}
+	}
+
+	var pending *logic.PendingReviewError
+	if errors.As(execErr, &pending) {
+		return http.StatusAccepted, dto.GenericResponse[dto.PendingReviewResponse]{
+			Status: "pending",
+			Data:   dto.PendingReviewResponse{Message: op.Type + " pending review", ReviewID: pending.ReviewID},
+		}
+	}
+
+	switch op.Type {
+	case common.TransactionTypeTransfer:
+		if execErr != nil {
+			httpStatus, code, message := transferError(execErr)
+			return httpStatus, common.ErrorResponse{Error: common.ErrorDetail{Code: code, Message: message}}
+		}
+
+		var payload logic.TransferPayload
+		_ = json.Unmarshal([]byte(op.Payload), &payload)
+		balance, err := s.Impl.GetBalance(ctx, payload.FromWalletID)
+		if err != nil {
+			s.logger.WithError(err).Warn("Failed to fetch updated sender balance")
+		}
+		return http.StatusOK, dto.GenericResponse[dto.TransferResponse]{
+			Status: "success",
+			Data: dto.TransferResponse{
+				Message:  "transfer success",
+				WalletID: payload.FromWalletID,
+				Balance:  balance,
+				Fee:      feeBreakdown(op.Fee),
+			},
+		}
+	case common.TransactionTypeAdjustment:
+		var payload logic.AdjustmentPayload
+		_ = json.Unmarshal([]byte(op.Payload), &payload)
+		switch execErr {
+		case nil:
+		case logic.ErrWalletNotFound:
+			return http.StatusNotFound, common.ErrorResponse{
+				Error: common.ErrorDetail{Code: common.ErrWalletNotFound, Message: execErr.Error()},
+			}
+		case logic.ErrInsufficientBalance:
+			return http.StatusBadRequest, common.ErrorResponse{
+				Error: common.ErrorDetail{Code: common.ErrInsufficientBalance, Message: execErr.Error()},
+			}
+		default:
+			return http.StatusInternalServerError, common.ErrorResponse{
+				Error: common.ErrorDetail{Code: common.ErrUnknown, Message: "adjustment failed"},
+			}
+		}
+
+		balance, err := s.Impl.GetBalance(ctx, payload.WalletID)
+		if err != nil {
+			s.logger.WithError(err).Warn("Failed to fetch adjusted balance")
+		}
+		return http.StatusOK, dto.GenericResponse[dto.AdjustmentResponse]{
+			Status: "success",
+			Data: dto.AdjustmentResponse{
+				Message:  "adjustment success",
+				WalletID: payload.WalletID,
+				Balance:  balance,
+			},
+		}
+	}
+
+	if execErr != nil {
+		return http.StatusInternalServerError, common.ErrorResponse{
+			Error: common.ErrorDetail{Code: common.ErrUnknown, Message: op.Type + " failed"},
+		}
+	}
+	return http.StatusOK, dto.GenericResponse[dto.SuccessResponse]{
+		Status: "success",
+		Data:   dto.SuccessResponse{Message: op.Type + " success"},
+	}
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/fees"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransferHandlerRequiresApproval(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	body := `{"from_wallet_id":"10000000-0000-0000-0000-000000000001","to_wallet_id":"10000000-0000-0000-0000-000000000002","amount":9000}`
+
+	req := httptest.NewRequest(http.MethodPost, "/wallets/transfer", strings.NewReader(body))
+	req.Header.Set("Idempotency-Key", "key-large")
+	req.Header.Set("X-Actor-ID", "maker")
+
+	daoMock.On("CheckIdempotencyKey", "key-large", "POST", "/wallets/transfer").Return(nil, false).Once()
+	logicMock.On("AuthorizeWallet", mock.Anything, "10000000-0000-0000-0000-000000000001", "maker", logic.AccessSpend, 9000.0).Return(nil).Once()
+	logicMock.On("ScreenTransferParties", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	logicMock.On("RequiresApproval", 9000.0).Return(true).Once()
+	logicMock.On("SubmitForApproval", mock.Anything, mock.MatchedBy(func(req logic.ApprovalRequest) bool {
+		return req.InitiatedBy == "maker" && req.IdempotencyKey == "key-large"
+	})).Return(&dao.PendingOperation{ID: "op-1"}, nil).Once()
+	daoMock.On("SaveIdempotencyKey", mock.MatchedBy(func(r *dao.IdempotencyRecord) bool {
+		return r.StatusCode == http.StatusAccepted
+	})).Return(nil).Once()
+
+	w := httptest.NewRecorder()
+	svc.TransferHandler(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), "op-1")
+	logicMock.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransferHandlerApprovalDenied(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	body := `{"from_wallet_id":"10000000-0000-0000-0000-000000000001","to_wallet_id":"10000000-0000-0000-0000-000000000002","amount":150000}`
+
+	req := httptest.NewRequest(http.MethodPost, "/wallets/transfer", strings.NewReader(body))
+	req.Header.Set("Idempotency-Key", "key-huge")
+	req.Header.Set("X-Actor-ID", "maker")
+
+	daoMock.On("CheckIdempotencyKey", "key-huge", "POST", "/wallets/transfer").Return(nil, false).Once()
+	logicMock.On("AuthorizeWallet", mock.Anything, "10000000-0000-0000-0000-000000000001", "maker", logic.AccessSpend, 150000.0).Return(nil).Once()
+	logicMock.On("ScreenTransferParties", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	logicMock.On("RequiresApproval", 150000.0).Return(true).Once()
+	logicMock.On("SubmitForApproval", mock.Anything, mock.Anything).Return(nil, logic.ErrTransactionDenied).Once()
+
+	w := httptest.NewRecorder()
+	svc.TransferHandler(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), logic.ErrTransactionDenied.Error())
+	daoMock.AssertNotCalled(t, "SaveIdempotencyKey", mock.Anything)
+}
+
+func TestTransferHandlerApprovalNeedsActor(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	body := `{"from_wallet_id":"10000000-0000-0000-0000-000000000001","to_wallet_id":"10000000-0000-0000-0000-000000000002","amount":9000}`
+
+	req := httptest.NewRequest(http.MethodPost, "/wallets/transfer", strings.NewReader(body))
+	req.Header.Set("Idempotency-Key", "key-large")
+
+	daoMock.On("CheckIdempotencyKey", "key-large", "POST", "/wallets/transfer").Return(nil, false).Once()
+	logicMock.On("ScreenTransferParties", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	logicMock.On("RequiresApproval", 9000.0).Return(true).Once()
+
+	w := httptest.NewRecorder()
+	svc.TransferHandler(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	logicMock.AssertNotCalled(t, "SubmitForApproval", mock.Anything, mock.Anything)
+	logicMock.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApproveHandler(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	operationID := "30000000-0000-0000-0000-000000000001"
+	op := &dao.PendingOperation{
+		ID:             operationID,
+		Type:           common.TransactionTypeTransfer,
+		Payload:        `{"from_wallet_id":"10000000-0000-0000-0000-000000000001","to_wallet_id":"10000000-0000-0000-0000-000000000002","amount":9000}`,
+		Status:         common.ApprovalStatusApproved,
+		IdempotencyKey: "key-large",
+		RequestMethod:  "POST",
+		RequestPath:    "/wallets/transfer",
+		Fee:            &fees.Quote{Amount: 9000, Fee: 5, Total: 9005},
+	}
+
+	t.Run("approval executes and stores outcome", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/approvals/"+operationID+"/approve", nil)
+		req.Header.Set("X-Actor-ID", "checker")
+		req = withRouteParam(req, "id", operationID)
+
+		logicMock.On("ApprovePendingOperation", mock.Anything, operationID, "checker").Return(op, nil).Once()
+		logicMock.On("GetBalance", mock.Anything, "10000000-0000-0000-0000-000000000001").Return(1000.0, nil).Once()
+		daoMock.On("UpdateIdempotencyKey", mock.MatchedBy(func(r *dao.IdempotencyRecord) bool {
+			return r.Key == "key-large" && r.StatusCode == http.StatusOK && strings.Contains(r.Response, "transfer success") &&
+				strings.Contains(r.Response, `"fee":5`)
+		})).Return(nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.ApproveHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		daoMock.AssertExpectations(t)
+	})
+
+	t.Run("failed execution answers with the transfer error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/approvals/"+operationID+"/approve", nil)
+		req.Header.Set("X-Actor-ID", "checker")
+		req = withRouteParam(req, "id", operationID)
+
+		logicMock.On("ApprovePendingOperation", mock.Anything, operationID, "checker").Return(op, logic.ErrInsufficientBalance).Once()
+		daoMock.On("UpdateIdempotencyKey", mock.MatchedBy(func(r *dao.IdempotencyRecord) bool {
+			return r.Key == "key-large" && r.StatusCode == http.StatusBadRequest
+		})).Return(nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.ApproveHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"status": "failed"`)
+		daoMock.AssertExpectations(t)
+	})
+
+	t.Run("approved operation parked for review keeps the key on the review", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/approvals/"+operationID+"/approve", nil)
+		req.Header.Set("X-Actor-ID", "checker")
+		req = withRouteParam(req, "id", operationID)
+
+		logicMock.On("ApprovePendingOperation", mock.Anything, operationID, "checker").
+			Return(op, &logic.PendingReviewError{ReviewID: "review-9"}).Once()
+		daoMock.On("UpdateIdempotencyKey", mock.MatchedBy(func(r *dao.IdempotencyRecord) bool {
+			return r.Key == "key-large" && r.StatusCode == http.StatusAccepted
+		})).Return(nil).Once()
+		daoMock.On("SetTransactionReviewRequest", "review-9", "key-large", "POST", "/wallets/transfer").Return(nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.ApproveHandler(w, req)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Contains(t, w.Body.String(), `"status": "pending"`)
+		daoMock.AssertExpectations(t)
+	})
+
+	t.Run("expired at decision finalizes the stored response", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/approvals/"+operationID+"/approve", nil)
+		req.Header.Set("X-Actor-ID", "checker")
+		req = withRouteParam(req, "id", operationID)
+
+		expired := *op
+		expired.Status = common.ApprovalStatusExpired
+		logicMock.On("ApprovePendingOperation", mock.Anything, operationID, "checker").Return(nil, logic.ErrApprovalExpired).Once()
+		logicMock.On("GetPendingOperation", mock.Anything, operationID).Return(&expired, nil).Once()
+		daoMock.On("UpdateIdempotencyKey", mock.MatchedBy(func(r *dao.IdempotencyRecord) bool {
+			return r.Key == "key-large" && r.StatusCode == http.StatusConflict && strings.Contains(r.Response, "expired")
+		})).Return(nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.ApproveHandler(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		daoMock.AssertExpectations(t)
+	})
+
+	t.Run("self approval forbidden", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/approvals/"+operationID+"/approve", nil)
+		req.Header.Set("X-Actor-ID", "maker")
+		req = withRouteParam(req, "id", operationID)
+
+		logicMock.On("ApprovePendingOperation", mock.Anything, operationID, "maker").Return(nil, logic.ErrSelfApproval).Once()
+
+		w := httptest.NewRecorder()
+		svc.ApproveHandler(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("missing actor", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/approvals/"+operationID+"/approve", nil)
+		req = withRouteParam(req, "id", operationID)
+
+		w := httptest.NewRecorder()
+		svc.ApproveHandler(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestRejectHandlerStoresOutcome(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	operationID := "30000000-0000-0000-0000-000000000002"
+
+	req := httptest.NewRequest(http.MethodPost, "/approvals/"+operationID+"/reject", nil)
+	req.Header.Set("X-Actor-ID", "checker")
+	req = withRouteParam(req, "id", operationID)
+
+	logicMock.On("RejectPendingOperation", mock.Anything, operationID, "checker").Return(&dao.PendingOperation{
+		ID:             operationID,
+		Type:           common.TransactionTypeTransfer,
+		Status:         common.ApprovalStatusRejected,
+		IdempotencyKey: "key-rejected",
+	}, nil).Once()
+	daoMock.On("UpdateIdempotencyKey", mock.MatchedBy(func(r *dao.IdempotencyRecord) bool {
+		return r.Key == "key-rejected" && r.StatusCode == http.StatusForbidden
+	})).Return(nil).Once()
+
+	w := httptest.NewRecorder()
+	svc.RejectHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	daoMock.AssertExpectations(t)
+}
+
+func TestExpirePendingOperationsStoresOutcome(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+
+	logicMock.On("ExpirePendingOperations", mock.Anything).Return([]dao.PendingOperation{
+		{ID: "op-1", Type: common.TransactionTypeTransfer, Status: common.ApprovalStatusExpired, IdempotencyKey: "key-expired", RequestMethod: "POST", RequestPath: "/wallets/transfer"},
+		{ID: "op-2", Type: common.TransactionTypeTransfer, Status: common.ApprovalStatusExpired},
+	}, nil).Once()
+	daoMock.On("UpdateIdempotencyKey", mock.MatchedBy(func(r *dao.IdempotencyRecord) bool {
+		return r.Key == "key-expired" && r.StatusCode == http.StatusConflict && strings.Contains(r.Response, "expired")
+	})).Return(nil).Once()
+
+	err := svc.ExpirePendingOperations(context.Background())
+
+	assert.NoError(t, err)
+	daoMock.AssertNumberOfCalls(t, "UpdateIdempotencyKey", 1)
+}
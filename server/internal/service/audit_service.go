@@ -0,0 +1,66 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+const (
+	defaultAuditPageSize = 100
+	maxAuditPageSize     = 1000
+)
+
+func (s *WalletService) ListAuditLogHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var afterSeq int64
+	if raw := query.Get("after_seq"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || parsed < 0 {
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid after_seq")
+			return
+		}
+		afterSeq = parsed
+	}
+
+	limit := defaultAuditPageSize
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > maxAuditPageSize {
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	entries, err := s.Impl.ListAuditLog(r.Context(), afterSeq, limit)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list audit log")
+		common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to list audit log")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[[]dao.AuditLog]{
+		Status: "success",
+		Data:   entries,
+	})
+}
+
+func (s *WalletService) VerifyAuditLogHandler(w http.ResponseWriter, r *http.Request) {
+	result, err := s.Impl.VerifyAuditLog(r.Context())
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to verify audit log")
+		common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to verify audit log")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*logic.AuditVerification]{
+		Status: "success",
+		Data:   result,
+	})
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/audit"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListAuditLogHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+
+	t.Run("page after sequence", func(t *testing.T) {
+		logicMock.On("ListAuditLog", mock.Anything, int64(40), 20).Return([]dao.AuditLog{{Seq: 41}}, nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.ListAuditLogHandler(w, httptest.NewRequest(http.MethodGet, "/admin/audit?after_seq=40&limit=20", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"seq": 41`)
+	})
+
+	t.Run("limit above maximum", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.ListAuditLogHandler(w, httptest.NewRequest(http.MethodGet, "/admin/audit?limit=5000", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid after_seq", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.ListAuditLogHandler(w, httptest.NewRequest(http.MethodGet, "/admin/audit?after_seq=abc", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestVerifyAuditLogHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+
+	logicMock.On("VerifyAuditLog", mock.Anything).Return(&logic.AuditVerification{
+		Valid:   false,
+		Checked: 6,
+		Break:   &audit.Break{Seq: 7, Reason: "row content does not match its hash"},
+	}, nil).Once()
+
+	w := httptest.NewRecorder()
+	svc.VerifyAuditLogHandler(w, httptest.NewRequest(http.MethodGet, "/admin/audit/verify", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"valid": false`)
+	assert.Contains(t, w.Body.String(), `"seq": 7`)
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/bankrec"
+	"github.com/julkhong/walletapp/server/internal/common"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+// maxStatementSize bounds uploaded bank statement files.
+const maxStatementSize = 10 << 20
+
+// ImportBankStatementHandler takes a raw camt.053 or MT940 file as the request body and returns
+// the reconciliation report. The format is detected when ?format is omitted.
+func (s *WalletService) ImportBankStatementHandler(w http.ResponseWriter, r *http.Request) {
+	actorID := common.GetActorID(r)
+	if actorID == "" {
+		common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != bankrec.FormatCamt053 && format != bankrec.FormatMT940 {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid format, expected camt053 or mt940")
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxStatementSize))
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid statement file")
+		return
+	}
+	if len(data) == 0 {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Empty statement file")
+		return
+	}
+
+	result, err := s.Impl.ImportBankStatement(r.Context(), format, data, actorID)
+	if err != nil {
+		s.logger.WithError(err).Error("Bank statement import failed")
+		switch {
+		case errors.Is(err, bankrec.ErrMalformed), errors.Is(err, bankrec.ErrUnsupportedFormat):
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Bank statement import failed")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*logic.BankImportResult]{
+		Status: "success",
+		Data:   result,
+	})
+}
+
+func (s *WalletService) GetBankImportHandler(w http.ResponseWriter, r *http.Request) {
+	importID := chi.URLParam(r, "id")
+	if importID == "" || !isUUID(w, importID, "import_id") {
+		return
+	}
+
+	result, err := s.Impl.GetBankImport(r.Context(), importID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to fetch bank import")
+		switch err {
+		case logic.ErrBankImportNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrBankImportNotFound, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to fetch bank import")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*logic.BankImportResult]{
+		Status: "success",
+		Data:   result,
+	})
+}
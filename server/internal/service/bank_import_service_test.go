@@ -0,0 +1,102 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/bankrec"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestImportBankStatementHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	body := ":20:STMT-1\n:61:2603030303C150,00NTRFNONREF\n"
+
+	newRequest := func(target string, actor string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		if actor != "" {
+			req.Header.Set("X-Actor-ID", actor)
+		}
+		return req
+	}
+
+	t.Run("imported", func(t *testing.T) {
+		logicMock.On("ImportBankStatement", mock.Anything, "mt940", []byte(body), "operator-1").Return(&logic.BankImportResult{
+			BankImport: dao.BankImport{ID: "import-1", MatchedCount: 1},
+			Report:     &bankrec.Report{},
+		}, nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.ImportBankStatementHandler(w, newRequest("/admin/bank-statements/import?format=mt940", "operator-1"))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"matched_count": 1`)
+	})
+
+	t.Run("needs an operator", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.ImportBankStatementHandler(w, newRequest("/admin/bank-statements/import", ""))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.ImportBankStatementHandler(w, newRequest("/admin/bank-statements/import?format=bai2", "operator-1"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/bank-statements/import", nil)
+		req.Header.Set("X-Actor-ID", "operator-1")
+		w := httptest.NewRecorder()
+		svc.ImportBankStatementHandler(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("malformed file", func(t *testing.T) {
+		logicMock.On("ImportBankStatement", mock.Anything, "", mock.Anything, "operator-2").
+			Return(nil, fmt.Errorf("%w: line 2: invalid :61: statement line", bankrec.ErrMalformed)).Once()
+
+		w := httptest.NewRecorder()
+		svc.ImportBankStatementHandler(w, newRequest("/admin/bank-statements/import", "operator-2"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "line 2")
+	})
+}
+
+func TestGetBankImportHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	importID := "5b0c1e9a-3a3f-4d6a-9f6e-1c2d3e4f5a6b"
+
+	t.Run("found", func(t *testing.T) {
+		logicMock.On("GetBankImport", mock.Anything, importID).Return(&logic.BankImportResult{
+			BankImport: dao.BankImport{ID: importID},
+			Report:     &bankrec.Report{UnmatchedDeposits: []string{"tx-9"}},
+		}, nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.GetBankImportHandler(w, withRouteParam(httptest.NewRequest(http.MethodGet, "/admin/bank-statements/"+importID, nil), "id", importID))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"tx-9"`)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		missing := "00000000-0000-0000-0000-000000000000"
+		logicMock.On("GetBankImport", mock.Anything, missing).Return(nil, logic.ErrBankImportNotFound).Once()
+
+		w := httptest.NewRecorder()
+		svc.GetBankImportHandler(w, withRouteParam(httptest.NewRequest(http.MethodGet, "/admin/bank-statements/"+missing, nil), "id", missing))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.GetBankImportHandler(w, withRouteParam(httptest.NewRequest(http.MethodGet, "/admin/bank-statements/abc", nil), "id", "abc"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
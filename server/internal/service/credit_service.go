@@ -0,0 +1,52 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+// SetCreditLimitHandler sets or closes the credit line of a wallet.
+func (s *WalletService) SetCreditLimitHandler(w http.ResponseWriter, r *http.Request) {
+	actorID := common.GetActorID(r)
+	if actorID == "" {
+		common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+		return
+	}
+
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+
+	var req dto.CreditLimitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+
+	wallet, err := s.Impl.SetCreditLimit(r.Context(), walletID, req.CreditLimit, actorID)
+	if err != nil {
+		s.logger.WithError(err).Error("Set credit limit failed")
+		switch err {
+		case logic.ErrInvalidCreditLimit:
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		case logic.ErrWalletNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, "Set credit limit failed")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*dao.Wallet]{
+		Status: "success",
+		Data:   wallet,
+	})
+}
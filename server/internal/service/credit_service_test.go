@@ -0,0 +1,39 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetCreditLimitHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	setLimit := func(actor, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/wallets/"+payerWalletID+"/credit-limit", strings.NewReader(body))
+		if actor != "" {
+			req.Header.Set("X-Actor-ID", actor)
+		}
+		w := httptest.NewRecorder()
+		svc.SetCreditLimitHandler(w, withRouteParam(req, "id", payerWalletID))
+		return w
+	}
+
+	logicMock.On("SetCreditLimit", mock.Anything, payerWalletID, 500.0, "operator-1").
+		Return(&dao.Wallet{ID: payerWalletID, Type: "standard", Status: "active", CreditLimit: 500}, nil).Once()
+	w := setLimit("operator-1", `{"credit_limit": 500}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"credit_limit": 500`)
+
+	logicMock.On("SetCreditLimit", mock.Anything, payerWalletID, 500.0, "operator-1").
+		Return(nil, logic.ErrInvalidCreditLimit).Once()
+	assert.Equal(t, http.StatusBadRequest, setLimit("operator-1", `{"credit_limit": 500}`).Code)
+
+	assert.Equal(t, http.StatusUnauthorized, setLimit("", `{"credit_limit": 500}`).Code)
+	logicMock.AssertExpectations(t)
+}
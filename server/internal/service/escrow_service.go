@@ -0,0 +1,225 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+// CreateEscrowHandler moves a buyer's payment into escrow for a seller.
+func (s *WalletService) CreateEscrowHandler(w http.ResponseWriter, r *http.Request) {
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	if idempotencyKey == "" {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Missing Idempotency-Key")
+		return
+	}
+
+	if record, found := s.Dao.CheckIdempotencyKey(idempotencyKey, r.Method, r.URL.Path); found {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(record.StatusCode)
+		_, _ = w.Write([]byte(record.Response))
+		return
+	}
+
+	var req dto.EscrowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+	if !isUUID(w, req.BuyerWalletID, "buyer_wallet_id") || !isUUID(w, req.SellerWalletID, "seller_wallet_id") {
+		return
+	}
+	if req.Amount <= 0.1 {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Amount must be more than 0.1")
+		return
+	}
+	if !s.authorizeWallet(w, r, req.BuyerWalletID, logic.AccessSpend, req.Amount) {
+		return
+	}
+
+	escrow, err := s.Impl.CreateEscrow(r.Context(), logic.EscrowRequest{
+		BuyerWalletID:  req.BuyerWalletID,
+		SellerWalletID: req.SellerWalletID,
+		Amount:         req.Amount,
+		Reference:      req.Reference,
+		ExpiresAt:      req.ExpiresAt,
+		CreatedBy:      common.GetActorID(r),
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to create escrow")
+		s.writeEscrowError(w, err, "Failed to create escrow")
+		return
+	}
+
+	s.writeIdempotent(w, r, idempotencyKey, http.StatusCreated, dto.GenericResponse[*dao.Escrow]{
+		Status: "success",
+		Data:   escrow,
+	})
+}
+
+// GetEscrowHandler returns an escrow to a member of the buyer or the seller wallet.
+func (s *WalletService) GetEscrowHandler(w http.ResponseWriter, r *http.Request) {
+	escrow, ok := s.loadEscrow(w, r)
+	if !ok || !s.authorizeEscrowParty(w, r, escrow) {
+		return
+	}
+	s.writeEscrow(w, escrow)
+}
+
+// ReleaseEscrowHandler pays a held escrow to the seller. The buyer calls it to confirm delivery.
+func (s *WalletService) ReleaseEscrowHandler(w http.ResponseWriter, r *http.Request) {
+	escrow, ok := s.loadEscrow(w, r)
+	if !ok || !s.authorizeWallet(w, r, escrow.BuyerWalletID, logic.AccessSpend, 0) {
+		return
+	}
+
+	escrow, err := s.Impl.ReleaseEscrow(r.Context(), escrow.ID, common.GetActorID(r))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to release escrow")
+		s.writeEscrowError(w, err, "Failed to release escrow")
+		return
+	}
+	s.writeEscrow(w, escrow)
+}
+
+// CancelEscrowHandler refunds a held or disputed escrow to the buyer. The seller calls it to
+// cancel the sale.
+func (s *WalletService) CancelEscrowHandler(w http.ResponseWriter, r *http.Request) {
+	escrow, ok := s.loadEscrow(w, r)
+	if !ok || !s.authorizeWallet(w, r, escrow.SellerWalletID, logic.AccessSpend, 0) {
+		return
+	}
+
+	escrow, err := s.Impl.RefundEscrow(r.Context(), escrow.ID, common.GetActorID(r))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to cancel escrow")
+		s.writeEscrowError(w, err, "Failed to cancel escrow")
+		return
+	}
+	s.writeEscrow(w, escrow)
+}
+
+// DisputeEscrowHandler lets the buyer or the seller dispute a held escrow, which then waits for
+// an operator to resolve it.
+func (s *WalletService) DisputeEscrowHandler(w http.ResponseWriter, r *http.Request) {
+	escrow, ok := s.loadEscrow(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.EscrowDisputeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Missing reason")
+		return
+	}
+	if !s.authorizeEscrowParty(w, r, escrow) {
+		return
+	}
+
+	escrow, err := s.Impl.DisputeEscrow(r.Context(), escrow.ID, req.Reason, common.GetActorID(r))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to dispute escrow")
+		s.writeEscrowError(w, err, "Failed to dispute escrow")
+		return
+	}
+	s.writeEscrow(w, escrow)
+}
+
+// ResolveEscrowHandler settles a disputed escrow as the operator in X-Actor-ID decided.
+func (s *WalletService) ResolveEscrowHandler(w http.ResponseWriter, r *http.Request) {
+	escrowID := chi.URLParam(r, "id")
+	if escrowID == "" || !isUUID(w, escrowID, "escrow_id") {
+		return
+	}
+	operator := common.GetActorID(r)
+	if operator == "" {
+		common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+		return
+	}
+
+	var req dto.EscrowResolveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+
+	escrow, err := s.Impl.ResolveEscrow(r.Context(), escrowID, req.Outcome, operator)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to resolve escrow")
+		s.writeEscrowError(w, err, "Failed to resolve escrow")
+		return
+	}
+	s.writeEscrow(w, escrow)
+}
+
+// loadEscrow fetches the escrow in the path, writing the error response when it cannot.
+func (s *WalletService) loadEscrow(w http.ResponseWriter, r *http.Request) (*dao.Escrow, bool) {
+	escrowID := chi.URLParam(r, "id")
+	if escrowID == "" || !isUUID(w, escrowID, "escrow_id") {
+		return nil, false
+	}
+
+	escrow, err := s.Impl.GetEscrow(r.Context(), escrowID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to fetch escrow")
+		s.writeEscrowError(w, err, "Failed to fetch escrow")
+		return nil, false
+	}
+	return escrow, true
+}
+
+// authorizeEscrowParty checks that the caller can see the buyer or the seller wallet of an escrow.
+func (s *WalletService) authorizeEscrowParty(w http.ResponseWriter, r *http.Request, escrow *dao.Escrow) bool {
+	if actorID := common.GetActorID(r); actorID != "" &&
+		s.Impl.AuthorizeWallet(r.Context(), escrow.SellerWalletID, actorID, logic.AccessView, 0) == nil {
+		return true
+	}
+	return s.authorizeWallet(w, r, escrow.BuyerWalletID, logic.AccessView, 0)
+}
+
+func (s *WalletService) writeEscrow(w http.ResponseWriter, escrow *dao.Escrow) {
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*dao.Escrow]{
+		Status: "success",
+		Data:   escrow,
+	})
+}
+
+// writeEscrowError writes the response for a failed escrow operation.
+func (s *WalletService) writeEscrowError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case logic.ErrEscrowNotFound:
+		common.WriteError(w, http.StatusNotFound, common.ErrEscrowNotFound, err.Error())
+	case logic.ErrEscrowStatusConflict:
+		common.WriteError(w, http.StatusConflict, common.ErrEscrowConflict, err.Error())
+	case logic.ErrInvalidEscrowOutcome, logic.ErrEscrowRequiresApproval:
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+	case logic.ErrEscrowNeedsReview:
+		common.WriteError(w, http.StatusForbidden, common.ErrTransactionDenied, err.Error())
+	case logic.ErrSanctionsMatch:
+		common.WriteError(w, http.StatusForbidden, common.ErrSanctionsMatch, err.Error())
+	case logic.ErrEscrowDisabled:
+		common.WriteError(w, http.StatusServiceUnavailable, common.ErrUnknown, err.Error())
+	default:
+		if errors.Is(err, logic.ErrInvalidEscrow) {
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+			return
+		}
+		status, code, message := transferError(err)
+		if code == common.ErrUnknown {
+			message = fallback
+		}
+		common.WriteError(w, status, code, message)
+	}
+}
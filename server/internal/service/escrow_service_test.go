@@ -0,0 +1,171 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	escrowID       = "50000000-0000-0000-0000-000000000001"
+	sellerWalletID = "10000000-0000-0000-0000-000000000002"
+)
+
+func testEscrow(status string) *dao.Escrow {
+	return &dao.Escrow{ID: escrowID, BuyerWalletID: payerWalletID, SellerWalletID: sellerWalletID, Amount: 40, Status: status}
+}
+
+func TestCreateEscrowHandler(t *testing.T) {
+	create := func(svc *WalletService, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/escrows", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "key-escrow")
+		req.Header.Set("X-Actor-ID", memberUserID)
+		w := httptest.NewRecorder()
+		svc.CreateEscrowHandler(w, req)
+		return w
+	}
+	body := `{"buyer_wallet_id": "` + payerWalletID + `", "seller_wallet_id": "` + sellerWalletID + `", "amount": 40, "reference": "order-7"}`
+
+	t.Run("held", func(t *testing.T) {
+		svc, logicMock, daoMock := setupTestService()
+		daoMock.On("CheckIdempotencyKey", "key-escrow", "POST", "/escrows").Return(nil, false).Once()
+		daoMock.On("SaveIdempotencyKey", mock.Anything).Return(nil).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessSpend, 40.0).Return(nil).Once()
+		logicMock.On("CreateEscrow", mock.Anything, mock.MatchedBy(func(req logic.EscrowRequest) bool {
+			return req.BuyerWalletID == payerWalletID && req.SellerWalletID == sellerWalletID && req.Amount == 40 &&
+				req.Reference == "order-7" && req.CreatedBy == memberUserID
+		})).Return(testEscrow("held"), nil).Once()
+
+		w := create(svc, body)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"held"`)
+		logicMock.AssertExpectations(t)
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		svc, logicMock, daoMock := setupTestService()
+		daoMock.On("CheckIdempotencyKey", "key-escrow", "POST", "/escrows").Return(nil, false).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessSpend, 40.0).Return(nil).Once()
+		logicMock.On("CreateEscrow", mock.Anything, mock.Anything).Return(nil, logic.ErrInsufficientBalance).Once()
+
+		w := create(svc, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":1002`)
+	})
+}
+
+func TestReleaseEscrowHandler(t *testing.T) {
+	release := func(svc *WalletService) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/escrows/"+escrowID+"/release", nil)
+		req.Header.Set("X-Actor-ID", memberUserID)
+		w := httptest.NewRecorder()
+		svc.ReleaseEscrowHandler(w, withRouteParam(req, "id", escrowID))
+		return w
+	}
+
+	t.Run("buyer confirms delivery", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("GetEscrow", mock.Anything, escrowID).Return(testEscrow("held"), nil).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessSpend, 0.0).Return(nil).Once()
+		logicMock.On("ReleaseEscrow", mock.Anything, escrowID, memberUserID).Return(testEscrow("released"), nil).Once()
+
+		w := release(svc)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status": "released"`)
+	})
+
+	t.Run("disputed", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("GetEscrow", mock.Anything, escrowID).Return(testEscrow("disputed"), nil).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessSpend, 0.0).Return(nil).Once()
+		logicMock.On("ReleaseEscrow", mock.Anything, escrowID, memberUserID).Return(nil, logic.ErrEscrowStatusConflict).Once()
+
+		w := release(svc)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":1034`)
+	})
+
+	t.Run("seller cannot release", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("GetEscrow", mock.Anything, escrowID).Return(testEscrow("held"), nil).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessSpend, 0.0).
+			Return(logic.ErrWalletAccessDenied).Once()
+
+		w := release(svc)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		logicMock.AssertNotCalled(t, "ReleaseEscrow", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown escrow", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("GetEscrow", mock.Anything, escrowID).Return(nil, logic.ErrEscrowNotFound).Once()
+
+		w := release(svc)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":1033`)
+	})
+}
+
+func TestDisputeEscrowHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	logicMock.On("GetEscrow", mock.Anything, escrowID).Return(testEscrow("held"), nil)
+	logicMock.On("AuthorizeWallet", mock.Anything, sellerWalletID, memberUserID, logic.AccessView, 0.0).Return(nil).Once()
+	logicMock.On("DisputeEscrow", mock.Anything, escrowID, "wrong item", memberUserID).Return(testEscrow("disputed"), nil).Once()
+
+	dispute := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/escrows/"+escrowID+"/dispute", strings.NewReader(body))
+		req.Header.Set("X-Actor-ID", memberUserID)
+		w := httptest.NewRecorder()
+		svc.DisputeEscrowHandler(w, withRouteParam(req, "id", escrowID))
+		return w
+	}
+
+	w := dispute(`{"reason": "wrong item"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status": "disputed"`)
+
+	w = dispute(`{"reason": " "}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	logicMock.AssertExpectations(t)
+}
+
+func TestResolveEscrowHandler(t *testing.T) {
+	resolve := func(svc *WalletService, actor, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/escrows/"+escrowID+"/resolve", strings.NewReader(body))
+		if actor != "" {
+			req.Header.Set("X-Actor-ID", actor)
+		}
+		w := httptest.NewRecorder()
+		svc.ResolveEscrowHandler(w, withRouteParam(req, "id", escrowID))
+		return w
+	}
+
+	t.Run("operator refunds", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("ResolveEscrow", mock.Anything, escrowID, "refund", "ops-1").Return(testEscrow("refunded"), nil).Once()
+
+		w := resolve(svc, "ops-1", `{"outcome": "refund"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status": "refunded"`)
+	})
+
+	t.Run("invalid outcome", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("ResolveEscrow", mock.Anything, escrowID, "split", "ops-1").Return(nil, logic.ErrInvalidEscrowOutcome).Once()
+
+		w := resolve(svc, "ops-1", `{"outcome": "split"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("operator required", func(t *testing.T) {
+		svc, _, _ := setupTestService()
+		w := resolve(svc, "", `{"outcome": "refund"}`)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/export"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+func (s *WalletService) ExportTransactionsHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+	if !s.authorizeWallet(w, r, walletID, logic.AccessView, 0) {
+		return
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+	filter, err := parseHistoryFilter(walletID, query)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		return
+	}
+
+	out := &attachmentWriter{
+		w:           w,
+		contentType: export.ContentType(format),
+		filename:    fmt.Sprintf("transactions-%s-%s.%s", walletID, time.Now().UTC().Format("20060102"), format),
+	}
+	err = s.Impl.ExportTransactions(r.Context(), filter, format, out)
+	if err != nil {
+		s.logger.WithError(err).Errorf("Failed to export transactions of wallet %s", walletID)
+		if out.started {
+			// the status line is already sent, the client sees a truncated download
+			return
+		}
+		switch err {
+		case export.ErrUnsupportedFormat, logic.ErrInvalidTimeRange, logic.ErrInvalidAmountRange, logic.ErrInvalidTxType:
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		case logic.ErrWalletNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to export transactions")
+		}
+		return
+	}
+
+	// an empty JSON Lines export writes no bytes but is still a download
+	out.start()
+}
+
+// attachmentWriter sends the download headers together with the first bytes of the body, so
+// errors found before anything is written can still be answered with a JSON error.
+type attachmentWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (a *attachmentWriter) start() {
+	if a.started {
+		return
+	}
+	a.started = true
+	a.w.Header().Set("Content-Type", a.contentType)
+	a.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", a.filename))
+	a.w.WriteHeader(http.StatusOK)
+}
+
+func (a *attachmentWriter) Write(p []byte) (int, error) {
+	a.start()
+	n, err := a.w.Write(p)
+	if flusher, ok := a.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
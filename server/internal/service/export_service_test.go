@@ -0,0 +1,78 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/export"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExportTransactionsHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	walletID := "10000000-0000-0000-0000-000000000001"
+	path := "/wallets/" + walletID + "/transactions/export"
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		svc.ExportTransactionsHandler(w, withRouteParam(httptest.NewRequest(http.MethodGet, path+query, nil), "id", walletID))
+		return w
+	}
+
+	t.Run("streams attachment with filters", func(t *testing.T) {
+		logicMock.On("ExportTransactions", mock.Anything, mock.MatchedBy(func(f dao.TransactionFilter) bool {
+			return f.WalletID == walletID && f.Type == "withdraw" && f.Ascending
+		}), export.FormatJSONL, mock.Anything).Run(func(args mock.Arguments) {
+			_, _ = io.WriteString(args.Get(3).(io.Writer), "{\"id\":\"tx-1\"}\n")
+		}).Return(nil).Once()
+
+		w := get("?format=jsonl&type=withdraw&sort=asc")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		assert.True(t, strings.HasPrefix(w.Header().Get("Content-Disposition"), `attachment; filename="transactions-`+walletID+"-"))
+		assert.True(t, strings.HasSuffix(w.Header().Get("Content-Disposition"), `.jsonl"`))
+		assert.Equal(t, "{\"id\":\"tx-1\"}\n", w.Body.String())
+	})
+
+	t.Run("csv by default and headers on empty export", func(t *testing.T) {
+		logicMock.On("ExportTransactions", mock.Anything, mock.Anything, export.FormatCSV, mock.Anything).Return(nil).Once()
+
+		w := get("")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), `.csv"`)
+	})
+
+	t.Run("bad filters", func(t *testing.T) {
+		for _, query := range []string{"?start=yesterday", "?offset=10", "?counterparty=bob"} {
+			assert.Equal(t, http.StatusBadRequest, get(query).Code, query)
+		}
+	})
+
+	t.Run("rejected by logic", func(t *testing.T) {
+		logicMock.On("ExportTransactions", mock.Anything, mock.Anything, "xlsx", mock.Anything).Return(export.ErrUnsupportedFormat).Once()
+		w := get("?format=xlsx")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Disposition"))
+
+		logicMock.On("ExportTransactions", mock.Anything, mock.Anything, export.FormatOFX, mock.Anything).Return(logic.ErrWalletNotFound).Once()
+		assert.Equal(t, http.StatusNotFound, get("?format=ofx").Code)
+	})
+
+	t.Run("failure mid-stream keeps partial download", func(t *testing.T) {
+		logicMock.On("ExportTransactions", mock.Anything, mock.Anything, export.FormatCSV, mock.Anything).Run(func(args mock.Arguments) {
+			_, _ = io.WriteString(args.Get(3).(io.Writer), "id,created_at\n")
+		}).Return(errors.New("connection reset")).Once()
+
+		w := get("?format=csv")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "id,created_at\n", w.Body.String())
+	})
+}
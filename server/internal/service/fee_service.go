@@ -0,0 +1,74 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/fees"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+// QuoteFeeHandler previews the fee a transfer or withdrawal would be charged. Nothing is moved,
+// so no Idempotency-Key is needed.
+func (s *WalletService) QuoteFeeHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.FeeQuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+
+	if !isUUID(w, req.WalletID, "wallet_id") {
+		return
+	}
+	if req.Amount <= 0.1 {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Amount must be more than 0.1")
+		return
+	}
+
+	quote, err := s.Impl.QuoteFee(r.Context(), req.TransactionType, req.WalletID, req.Amount)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to quote fee")
+		switch err {
+		case logic.ErrInvalidFeeQuote:
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		case logic.ErrWalletNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
+		case logic.ErrWalletFrozen:
+			common.WriteError(w, http.StatusForbidden, common.ErrWalletFrozen, err.Error())
+		case logic.ErrPocketWallet:
+			common.WriteError(w, http.StatusBadRequest, common.ErrPocketMovement, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, "Failed to quote fee")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[dto.FeeQuoteResponse]{
+		Status: "success",
+		Data: dto.FeeQuoteResponse{
+			WalletID:        req.WalletID,
+			TransactionType: quote.TransactionType,
+			KycLevel:        quote.KycLevel,
+			FeeBreakdown:    *feeBreakdown(quote),
+		},
+	})
+}
+
+// feeBreakdown converts a fee quote for a response; nil stays nil.
+func feeBreakdown(quote *fees.Quote) *dto.FeeBreakdown {
+	if quote == nil {
+		return nil
+	}
+	breakdown := &dto.FeeBreakdown{
+		Amount:     quote.Amount,
+		Fee:        quote.Fee,
+		Total:      quote.Total,
+		Components: make([]dto.FeeComponent, len(quote.Components)),
+	}
+	for i, c := range quote.Components {
+		breakdown.Components[i] = dto.FeeComponent{Rule: c.Rule, Type: c.Type, Amount: c.Amount, Capped: c.Capped}
+	}
+	return breakdown
+}
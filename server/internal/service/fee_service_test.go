@@ -0,0 +1,71 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/fees"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const payerWalletID = "10000000-0000-0000-0000-000000000001"
+
+func transferFee() *fees.Quote {
+	return &fees.Quote{
+		TransactionType: "transfer",
+		KycLevel:        "full",
+		Amount:          50,
+		Fee:             0.5,
+		Total:           50.5,
+		Components:      []fees.Component{{Rule: "transfer_percentage", Type: fees.RuleTypePercentage, Amount: 0.5}},
+	}
+}
+
+func TestQuoteFeeHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	quote := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		svc.QuoteFeeHandler(w, httptest.NewRequest(http.MethodPost, "/fees/quote", strings.NewReader(body)))
+		return w
+	}
+
+	logicMock.On("QuoteFee", mock.Anything, "transfer", payerWalletID, 50.0).Return(transferFee(), nil).Once()
+	w := quote(`{"wallet_id": "` + payerWalletID + `", "transaction_type": "transfer", "amount": 50}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"fee": 0.5`)
+	assert.Contains(t, w.Body.String(), `"total": 50.5`)
+	assert.Contains(t, w.Body.String(), `"rule": "transfer_percentage"`)
+
+	logicMock.On("QuoteFee", mock.Anything, "deposit", payerWalletID, 50.0).Return(nil, logic.ErrInvalidFeeQuote).Once()
+	w = quote(`{"wallet_id": "` + payerWalletID + `", "transaction_type": "deposit", "amount": 50}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = quote(`{"wallet_id": "alice", "transaction_type": "transfer", "amount": 50}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	logicMock.AssertExpectations(t)
+}
+
+func TestTransferHandlerReturnsFee(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	toWalletID := "10000000-0000-0000-0000-000000000002"
+	daoMock.On("CheckIdempotencyKey", "key-fee", "POST", "/wallets/transfer").Return(nil, false).Once()
+	daoMock.On("SaveIdempotencyKey", mock.Anything).Return(nil).Once()
+	logicMock.On("ScreenTransferParties", mock.Anything, payerWalletID, toWalletID).Return(nil).Once()
+	logicMock.On("RequiresApproval", 50.0).Return(false).Once()
+	logicMock.On("Transfer", mock.Anything, payerWalletID, toWalletID, 50.0).Return(transferFee(), nil).Once()
+	logicMock.On("GetBalance", mock.Anything, payerWalletID).Return(49.5, nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/wallets/transfer",
+		strings.NewReader(`{"from_wallet_id": "`+payerWalletID+`", "to_wallet_id": "`+toWalletID+`", "amount": 50}`))
+	req.Header.Set("Idempotency-Key", "key-fee")
+	w := httptest.NewRecorder()
+	svc.TransferHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"fee":{"amount":50,"fee":0.5,"total":50.5`)
+	logicMock.AssertExpectations(t)
+}
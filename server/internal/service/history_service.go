@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+func (s *WalletService) TransactionHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+	if !s.authorizeWallet(w, r, walletID, logic.AccessView, 0) {
+		return
+	}
+
+	query := r.URL.Query()
+	filter, err := parseHistoryFilter(walletID, query)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		return
+	}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid limit")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	page, err := s.Impl.GetTransactionHistory(r.Context(), filter, query.Get("cursor"))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to fetch transaction history")
+		switch err {
+		case logic.ErrInvalidCursor, logic.ErrInvalidPageSize, logic.ErrInvalidTimeRange,
+			logic.ErrInvalidAmountRange, logic.ErrInvalidTxType:
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to fetch transaction history")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*logic.TransactionPage]{
+		Status: "success",
+		Data:   page,
+	})
+}
+
+// parseHistoryFilter reads the history filters shared by the history and export endpoints.
+// It only checks formats; ranges are validated by the logic layer.
+func parseHistoryFilter(walletID string, query url.Values) (dao.TransactionFilter, error) {
+	filter := dao.TransactionFilter{
+		WalletID: walletID,
+		Type:     query.Get("type"),
+	}
+
+	if query.Has("offset") {
+		return filter, fmt.Errorf("offset is not supported, use cursor")
+	}
+
+	for _, bound := range []struct {
+		name   string
+		target **time.Time
+	}{{"start", &filter.Start}, {"end", &filter.End}} {
+		if raw := query.Get(bound.name); raw != "" {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s, expected RFC3339", bound.name)
+			}
+			*bound.target = &parsed
+		}
+	}
+
+	for _, bound := range []struct {
+		name   string
+		target **float64
+	}{{"min_amount", &filter.MinAmount}, {"max_amount", &filter.MaxAmount}} {
+		if raw := query.Get(bound.name); raw != "" {
+			parsed, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s", bound.name)
+			}
+			*bound.target = &parsed
+		}
+	}
+
+	if counterparty := query.Get("counterparty"); counterparty != "" {
+		if !common.IsValidUUID(counterparty) {
+			return filter, fmt.Errorf("invalid counterparty")
+		}
+		filter.Counterparty = counterparty
+	}
+
+	switch query.Get("sort") {
+	case "", "desc":
+	case "asc":
+		filter.Ascending = true
+	default:
+		return filter, fmt.Errorf("invalid sort, expected asc or desc")
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransactionHistoryHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	walletID := "10000000-0000-0000-0000-000000000001"
+	path := "/wallets/" + walletID + "/transactions"
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		svc.TransactionHistoryHandler(w, withRouteParam(httptest.NewRequest(http.MethodGet, path+query, nil), "id", walletID))
+		return w
+	}
+
+	t.Run("filters and cursor passed through", func(t *testing.T) {
+		start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+		next := "next-token"
+		logicMock.On("GetTransactionHistory", mock.Anything, mock.MatchedBy(func(f dao.TransactionFilter) bool {
+			return f.WalletID == walletID && f.Start.Equal(start) && f.End == nil && *f.MinAmount == 5 &&
+				f.Counterparty == "10000000-0000-0000-0000-000000000002" && f.Ascending && f.Limit == 10
+		}), "abc").Return(&logic.TransactionPage{WalletID: walletID, Transactions: []dao.Transaction{}, NextCursor: &next}, nil).Once()
+
+		w := get("?start=2026-03-01T00:00:00Z&min_amount=5&counterparty=10000000-0000-0000-0000-000000000002&sort=asc&limit=10&cursor=abc")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"next_cursor": "next-token"`)
+	})
+
+	t.Run("bad parameters", func(t *testing.T) {
+		for _, query := range []string{
+			"?start=yesterday",
+			"?limit=ten",
+			"?min_amount=lots",
+			"?counterparty=bob",
+			"?sort=sideways",
+			"?offset=20",
+		} {
+			assert.Equal(t, http.StatusBadRequest, get(query).Code, query)
+		}
+	})
+
+	t.Run("logic validation errors", func(t *testing.T) {
+		logicMock.On("GetTransactionHistory", mock.Anything, mock.Anything, "stale").Return(nil, logic.ErrInvalidCursor).Once()
+		assert.Equal(t, http.StatusBadRequest, get("?cursor=stale").Code)
+	})
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+func (s *WalletService) KycUpgradeHandler(w http.ResponseWriter, r *http.Request) {
+	actorID := common.GetActorID(r)
+	if actorID == "" {
+		common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+		return
+	}
+
+	userID := chi.URLParam(r, "id")
+	if userID == "" || !isUUID(w, userID, "user_id") {
+		return
+	}
+
+	var req dto.KycUpgradeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+
+	user, err := s.Impl.UpgradeKycLevel(r.Context(), userID, req.Level, req.EvidenceRefs, actorID)
+	if err != nil {
+		s.logger.WithError(err).Error("KYC upgrade failed")
+		switch err {
+		case logic.ErrUserNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrUserNotFound, err.Error())
+		case logic.ErrInvalidKycLevel, logic.ErrKycDowngradeNotAllowed, logic.ErrKycEvidenceRequired:
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, "KYC upgrade failed")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[dto.KycUpgradeResponse]{
+		Status: "success",
+		Data: dto.KycUpgradeResponse{
+			UserID:   user.ID,
+			KycLevel: user.KycLevel,
+		},
+	})
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestKycUpgradeHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	userID := "00000000-0000-0000-0000-000000000001"
+	path := "/admin/users/" + userID + "/kyc"
+
+	t.Run("valid upgrade request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"level":"basic","evidence_refs":["passport-123"]}`))
+		req.Header.Set("X-Actor-ID", "operator-1")
+		req = withRouteParam(req, "id", userID)
+
+		logicMock.On("UpgradeKycLevel", mock.Anything, userID, common.KycLevelBasic, []string{"passport-123"}, "operator-1").
+			Return(&dao.User{ID: userID, KycLevel: common.KycLevelBasic}, nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.KycUpgradeHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"kyc_level": "basic"`)
+	})
+
+	t.Run("missing actor", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"level":"basic"}`))
+		req = withRouteParam(req, "id", userID)
+
+		w := httptest.NewRecorder()
+		svc.KycUpgradeHandler(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("downgrade rejected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"level":"unverified","evidence_refs":["x"]}`))
+		req.Header.Set("X-Actor-ID", "operator-1")
+		req = withRouteParam(req, "id", userID)
+
+		logicMock.On("UpgradeKycLevel", mock.Anything, userID, common.KycLevelUnverified, []string{"x"}, "operator-1").
+			Return(nil, logic.ErrKycDowngradeNotAllowed).Once()
+
+		w := httptest.NewRecorder()
+		svc.KycUpgradeHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+// ReconcileLedgerHandler runs the ledger integrity check. It only reports unless apply=true,
+// which writes correcting entries and needs an operator identity.
+func (s *WalletService) ReconcileLedgerHandler(w http.ResponseWriter, r *http.Request) {
+	apply := false
+	if raw := r.URL.Query().Get("apply"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid apply")
+			return
+		}
+		apply = parsed
+	}
+
+	actorID := common.GetActorID(r)
+	if apply && actorID == "" {
+		common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+		return
+	}
+
+	report, err := s.Impl.CheckLedgerIntegrity(r.Context(), apply, actorID)
+	if err != nil {
+		s.logger.WithError(err).Error("Ledger integrity check failed")
+		common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Ledger integrity check failed")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*logic.LedgerReport]{
+		Status: "success",
+		Data:   report,
+	})
+}
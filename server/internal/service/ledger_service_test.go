@@ -0,0 +1,49 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReconcileLedgerHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+
+	t.Run("dry run reports discrepancies", func(t *testing.T) {
+		logicMock.On("CheckLedgerIntegrity", mock.Anything, false, "").Return(&logic.LedgerReport{
+			Discrepancies: []logic.LedgerDiscrepancy{{WalletID: "wallet-1", Balance: 100, LedgerBalance: 90, Difference: 10}},
+		}, nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.ReconcileLedgerHandler(w, httptest.NewRequest(http.MethodPost, "/admin/ledger/reconcile", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"difference": 10`)
+	})
+
+	t.Run("apply needs an operator", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.ReconcileLedgerHandler(w, httptest.NewRequest(http.MethodPost, "/admin/ledger/reconcile?apply=true", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("apply with operator", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/ledger/reconcile?apply=true", nil)
+		req.Header.Set("X-Actor-ID", "operator-1")
+		logicMock.On("CheckLedgerIntegrity", mock.Anything, true, "operator-1").Return(&logic.LedgerReport{Applied: true}, nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.ReconcileLedgerHandler(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		logicMock.AssertExpectations(t)
+	})
+
+	t.Run("invalid apply flag", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.ReconcileLedgerHandler(w, httptest.NewRequest(http.MethodPost, "/admin/ledger/reconcile?apply=maybe", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
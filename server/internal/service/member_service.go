@@ -0,0 +1,181 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+// authorizeWallet checks that the caller in X-Actor-ID is a member of the wallet with the access
+// asked for; for spending, amount must fit in a spender's monthly cap. Calls without an actor
+// pass unless membership is required. It writes the error response and returns false when the
+// caller may not go ahead.
+func (s *WalletService) authorizeWallet(w http.ResponseWriter, r *http.Request, walletID string, access logic.WalletAccess, amount float64) bool {
+	actorID := common.GetActorID(r)
+	if actorID == "" {
+		if s.membershipRequired {
+			common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+			return false
+		}
+		return true
+	}
+
+	err := s.Impl.AuthorizeWallet(r.Context(), walletID, actorID, access, amount)
+	if err == nil {
+		return true
+	}
+	s.logger.WithError(err).Warnf("Wallet %s access denied to %s", walletID, actorID)
+	switch err {
+	case logic.ErrWalletAccessDenied:
+		common.WriteError(w, http.StatusForbidden, common.ErrWalletAccessDenied, err.Error())
+	case logic.ErrSpendingCapExceeded:
+		common.WriteError(w, http.StatusForbidden, common.ErrSpendingCapExceeded, err.Error())
+	case logic.ErrWalletNotFound:
+		common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
+	default:
+		common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, "Failed to check wallet access")
+	}
+	return false
+}
+
+// InviteMemberHandler invites a user to share the wallet in the path. Only owners can invite.
+func (s *WalletService) InviteMemberHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+	actorID := common.GetActorID(r)
+	if actorID == "" {
+		common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+		return
+	}
+
+	var req dto.InviteMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+	if !isUUID(w, req.UserID, "user_id") {
+		return
+	}
+	if !s.authorizeWallet(w, r, walletID, logic.AccessManage, 0) {
+		return
+	}
+
+	member, err := s.Impl.InviteMember(r.Context(), walletID, logic.MemberInvite{
+		UserID:      req.UserID,
+		Role:        req.Role,
+		SpendingCap: req.SpendingCap,
+		InvitedBy:   actorID,
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("Invite member failed")
+		s.writeMemberError(w, err, "Invite member failed")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusCreated, dto.GenericResponse[*dao.WalletMember]{
+		Status: "success",
+		Data:   member,
+	})
+}
+
+// AcceptInvitationHandler makes the caller a member of the wallet they were invited to.
+func (s *WalletService) AcceptInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+	actorID := common.GetActorID(r)
+	if actorID == "" {
+		common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+		return
+	}
+
+	member, err := s.Impl.AcceptInvitation(r.Context(), walletID, actorID)
+	if err != nil {
+		s.logger.WithError(err).Error("Accept invitation failed")
+		s.writeMemberError(w, err, "Accept invitation failed")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*dao.WalletMember]{
+		Status: "success",
+		Data:   member,
+	})
+}
+
+// ListMembersHandler lists the members and open invitations of the wallet in the path.
+func (s *WalletService) ListMembersHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+	if !s.authorizeWallet(w, r, walletID, logic.AccessView, 0) {
+		return
+	}
+
+	members, err := s.Impl.ListMembers(r.Context(), walletID)
+	if err != nil {
+		s.logger.WithError(err).Error("List members failed")
+		s.writeMemberError(w, err, "Failed to list members")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[[]logic.MemberView]{
+		Status: "success",
+		Data:   members,
+	})
+}
+
+// RemoveMemberHandler takes a user off the wallet in the path. Owners can remove anyone; other
+// members can only leave, or decline their invitation.
+func (s *WalletService) RemoveMemberHandler(w http.ResponseWriter, r *http.Request) {
+	walletID, userID := chi.URLParam(r, "id"), chi.URLParam(r, "userId")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") || !isUUID(w, userID, "user_id") {
+		return
+	}
+	actorID := common.GetActorID(r)
+	if actorID == "" {
+		common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+		return
+	}
+	if actorID != userID && !s.authorizeWallet(w, r, walletID, logic.AccessManage, 0) {
+		return
+	}
+
+	if err := s.Impl.RemoveMember(r.Context(), walletID, userID, actorID); err != nil {
+		s.logger.WithError(err).Error("Remove member failed")
+		s.writeMemberError(w, err, "Remove member failed")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[dto.SuccessResponse]{
+		Status: "success",
+		Data:   dto.SuccessResponse{Message: "member removed"},
+	})
+}
+
+// writeMemberError writes the response for a failed membership operation.
+func (s *WalletService) writeMemberError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case logic.ErrInvalidMemberRole, logic.ErrInvalidSpendingCap, logic.ErrPocketMembers:
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+	case logic.ErrWalletNotFound:
+		common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
+	case logic.ErrUserNotFound:
+		common.WriteError(w, http.StatusNotFound, common.ErrUserNotFound, err.Error())
+	case logic.ErrInvitationNotFound, logic.ErrMemberNotFound:
+		common.WriteError(w, http.StatusNotFound, common.ErrMemberNotFound, err.Error())
+	case logic.ErrAlreadyMember, logic.ErrLastOwner:
+		common.WriteError(w, http.StatusConflict, common.ErrMemberConflict, err.Error())
+	default:
+		common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, fallback)
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	ownerUserID  = "40000000-0000-0000-0000-000000000001"
+	memberUserID = "40000000-0000-0000-0000-000000000002"
+)
+
+func TestInviteMemberHandler(t *testing.T) {
+	invite := func(svc *WalletService, actor, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/wallets/"+payerWalletID+"/members", strings.NewReader(body))
+		if actor != "" {
+			req.Header.Set("X-Actor-ID", actor)
+		}
+		w := httptest.NewRecorder()
+		svc.InviteMemberHandler(w, withRouteParam(req, "id", payerWalletID))
+		return w
+	}
+	body := `{"user_id": "` + memberUserID + `", "role": "spender", "spending_cap": 300}`
+
+	t.Run("owner invites", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, ownerUserID, logic.AccessManage, 0.0).Return(nil).Once()
+		logicMock.On("InviteMember", mock.Anything, payerWalletID, mock.MatchedBy(func(inv logic.MemberInvite) bool {
+			return inv.UserID == memberUserID && inv.Role == common.MemberRoleSpender && *inv.SpendingCap == 300 &&
+				inv.InvitedBy == ownerUserID
+		})).Return(&dao.WalletMember{WalletID: payerWalletID, UserID: memberUserID, Role: "spender", Status: "invited"}, nil).Once()
+
+		w := invite(svc, ownerUserID, body)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"status": "invited"`)
+		logicMock.AssertExpectations(t)
+	})
+
+	t.Run("spender cannot invite", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessManage, 0.0).
+			Return(logic.ErrWalletAccessDenied).Once()
+
+		w := invite(svc, memberUserID, body)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":1029`)
+		logicMock.AssertNotCalled(t, "InviteMember", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("actor required", func(t *testing.T) {
+		svc, _, _ := setupTestService()
+		w := invite(svc, "", body)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("already a member", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, ownerUserID, logic.AccessManage, 0.0).Return(nil).Once()
+		logicMock.On("InviteMember", mock.Anything, payerWalletID, mock.Anything).Return(nil, logic.ErrAlreadyMember).Once()
+
+		w := invite(svc, ownerUserID, body)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestAcceptInvitationHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	logicMock.On("AcceptInvitation", mock.Anything, payerWalletID, memberUserID).
+		Return(&dao.WalletMember{WalletID: payerWalletID, UserID: memberUserID, Role: "viewer", Status: "active"}, nil).Once()
+	logicMock.On("AcceptInvitation", mock.Anything, payerWalletID, ownerUserID).Return(nil, logic.ErrInvitationNotFound).Once()
+
+	accept := func(actor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/wallets/"+payerWalletID+"/members/accept", nil)
+		req.Header.Set("X-Actor-ID", actor)
+		w := httptest.NewRecorder()
+		svc.AcceptInvitationHandler(w, withRouteParam(req, "id", payerWalletID))
+		return w
+	}
+
+	w := accept(memberUserID)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status": "active"`)
+
+	w = accept(ownerUserID)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":1031`)
+	logicMock.AssertExpectations(t)
+}
+
+func TestRemoveMemberHandler(t *testing.T) {
+	remove := func(svc *WalletService, actor, userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/wallets/"+payerWalletID+"/members/"+userID+"/remove", nil)
+		req.Header.Set("X-Actor-ID", actor)
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("id", payerWalletID)
+		routeCtx.URLParams.Add("userId", userID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
+		w := httptest.NewRecorder()
+		svc.RemoveMemberHandler(w, req)
+		return w
+	}
+
+	t.Run("member leaves", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("RemoveMember", mock.Anything, payerWalletID, memberUserID, memberUserID).Return(nil).Once()
+
+		w := remove(svc, memberUserID, memberUserID)
+		assert.Equal(t, http.StatusOK, w.Code)
+		logicMock.AssertNotCalled(t, "AuthorizeWallet", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("last owner", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("RemoveMember", mock.Anything, payerWalletID, ownerUserID, ownerUserID).Return(logic.ErrLastOwner).Once()
+
+		w := remove(svc, ownerUserID, ownerUserID)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("others need an owner", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessManage, 0.0).
+			Return(logic.ErrWalletAccessDenied).Once()
+
+		w := remove(svc, memberUserID, ownerUserID)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		logicMock.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestWithdrawHandlerSpendingCap(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	daoMock.On("CheckIdempotencyKey", "key-cap", "POST", "/wallets/"+payerWalletID+"/withdraw").Return(nil, false).Once()
+	logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessSpend, 80.0).
+		Return(logic.ErrSpendingCapExceeded).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/wallets/"+payerWalletID+"/withdraw", strings.NewReader(`{"amount": 80}`))
+	req.Header.Set("Idempotency-Key", "key-cap")
+	req.Header.Set("X-Actor-ID", memberUserID)
+	w := httptest.NewRecorder()
+	svc.WithdrawHandler(w, withRouteParam(req, "id", payerWalletID))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":1030`)
+	logicMock.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBalanceHandlerRequiresMember(t *testing.T) {
+	svc, _, _ := setupTestService()
+	svc.membershipRequired = true
+
+	req := httptest.NewRequest(http.MethodGet, "/wallets/"+payerWalletID+"/balance", nil)
+	w := httptest.NewRecorder()
+	svc.BalanceHandler(w, withRouteParam(req, "id", payerWalletID))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
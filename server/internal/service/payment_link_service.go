@@ -0,0 +1,190 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+// CreatePaymentLinkHandler creates a signed link anyone holding it can pay the wallet through.
+func (s *WalletService) CreatePaymentLinkHandler(w http.ResponseWriter, r *http.Request) {
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	if idempotencyKey == "" {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Missing Idempotency-Key")
+		return
+	}
+
+	if record, found := s.Dao.CheckIdempotencyKey(idempotencyKey, r.Method, r.URL.Path); found {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(record.StatusCode)
+		_, _ = w.Write([]byte(record.Response))
+		return
+	}
+
+	var req dto.PaymentLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+	if !isUUID(w, req.WalletID, "wallet_id") {
+		return
+	}
+	if req.Amount != nil && *req.Amount <= 0.1 {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Amount must be more than 0.1")
+		return
+	}
+	if !s.authorizeWallet(w, r, req.WalletID, logic.AccessSpend, 0) {
+		return
+	}
+
+	link, err := s.Impl.CreatePaymentLink(r.Context(), logic.PaymentLinkInput{
+		WalletID:  req.WalletID,
+		Amount:    req.Amount,
+		Reference: req.Reference,
+		SingleUse: req.SingleUse,
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: common.GetActorID(r),
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to create payment link")
+		s.writePaymentLinkError(w, err, "Failed to create payment link")
+		return
+	}
+
+	s.writeIdempotent(w, r, idempotencyKey, http.StatusCreated, dto.GenericResponse[*logic.PaymentLinkView]{
+		Status: "success",
+		Data:   link,
+	})
+}
+
+// ResolvePaymentLinkHandler returns the transfer the token in the path asks for, with the EMV
+// payload its QR code carries.
+func (s *WalletService) ResolvePaymentLinkHandler(w http.ResponseWriter, r *http.Request) {
+	link, err := s.Impl.ResolvePaymentLink(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to resolve payment link")
+		s.writePaymentLinkError(w, err, "Failed to resolve payment link")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*logic.PaymentLinkView]{
+		Status: "success",
+		Data:   link,
+	})
+}
+
+// PaymentLinkQRHandler serves the QR code of a payment link as a PNG. scale sets the pixels per
+// module.
+func (s *WalletService) PaymentLinkQRHandler(w http.ResponseWriter, r *http.Request) {
+	scale := logic.DefaultQRScale
+	if raw := r.URL.Query().Get("scale"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > logic.MaxQRScale {
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "scale must be between 1 and "+strconv.Itoa(logic.MaxQRScale))
+			return
+		}
+		scale = n
+	}
+
+	image, err := s.Impl.PaymentLinkQR(r.Context(), chi.URLParam(r, "token"), scale)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to render payment link QR code")
+		s.writePaymentLinkError(w, err, "Failed to render payment link QR code")
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(image)
+}
+
+// payPaymentLink is TransferHandler settling a payment link. The payee and, when the link fixes
+// it, the amount come from the link; a to_wallet_id or amount in the request must agree with it.
+func (s *WalletService) payPaymentLink(w http.ResponseWriter, r *http.Request, idempotencyKey string, req dto.TransferRequest) {
+	if !isUUID(w, req.FromWalletID, "from_wallet_id") {
+		return
+	}
+	if req.ToWalletID != "" && !isUUID(w, req.ToWalletID, "to_wallet_id") {
+		return
+	}
+
+	link, err := s.Impl.ResolvePaymentLink(r.Context(), req.LinkToken)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to resolve payment link")
+		s.writePaymentLinkError(w, err, "Transfer failed")
+		return
+	}
+	if req.ToWalletID != "" && req.ToWalletID != link.WalletID {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "to_wallet_id does not match the payment link")
+		return
+	}
+	amount := req.Amount
+	if link.Amount != nil && amount == 0 {
+		amount = *link.Amount
+	}
+	if amount <= 0.1 {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Amount must be more than 0.1")
+		return
+	}
+	if !s.authorizeWallet(w, r, req.FromWalletID, logic.AccessSpend, amount) {
+		return
+	}
+
+	payment, err := s.Impl.PayPaymentLink(r.Context(), req.LinkToken, req.FromWalletID, amount)
+	if err != nil {
+		s.logger.WithError(err).Error("Payment link transfer failed")
+		s.writePaymentLinkError(w, err, "Transfer failed")
+		return
+	}
+
+	balance, err := s.Impl.GetBalance(r.Context(), req.FromWalletID)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to fetch updated sender balance")
+	}
+
+	s.writeIdempotent(w, r, idempotencyKey, http.StatusOK, dto.GenericResponse[dto.TransferResponse]{
+		Status: "success",
+		Data: dto.TransferResponse{
+			Message:       "transfer success",
+			WalletID:      req.FromWalletID,
+			Balance:       balance,
+			Fee:           feeBreakdown(payment.Fee),
+			PaymentLinkID: payment.LinkID,
+		},
+	})
+}
+
+// writePaymentLinkError writes the response for a failed payment link operation.
+func (s *WalletService) writePaymentLinkError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case logic.ErrPaymentLinksDisabled:
+		common.WriteError(w, http.StatusServiceUnavailable, common.ErrUnknown, err.Error())
+	case logic.ErrPaymentLinkNotFound:
+		common.WriteError(w, http.StatusNotFound, common.ErrLinkNotFound, err.Error())
+	case logic.ErrPaymentLinkExpired, logic.ErrPaymentLinkUsed:
+		common.WriteError(w, http.StatusConflict, common.ErrLinkConflict, err.Error())
+	case logic.ErrPaymentLinkRequiresApproval:
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+	case logic.ErrPaymentLinkNeedsReview:
+		common.WriteError(w, http.StatusForbidden, common.ErrTransactionDenied, err.Error())
+	case logic.ErrSanctionsMatch:
+		common.WriteError(w, http.StatusForbidden, common.ErrSanctionsMatch, err.Error())
+	default:
+		if errors.Is(err, logic.ErrInvalidPaymentLink) {
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+			return
+		}
+		status, code, message := transferError(err)
+		if code == common.ErrUnknown {
+			message = fallback
+		}
+		common.WriteError(w, status, code, message)
+	}
+}
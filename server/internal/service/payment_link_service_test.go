@@ -0,0 +1,168 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	linkID    = "70000000-0000-0000-0000-000000000001"
+	linkToken = "cAAAAAAAAAAAAAAAAAAAAQ.signature"
+)
+
+// testPaymentLink is a link paying the seller wallet.
+func testPaymentLink(amount *float64) *logic.PaymentLinkView {
+	return &logic.PaymentLinkView{
+		PaymentLink: dao.PaymentLink{ID: linkID, WalletID: sellerWalletID, Amount: amount},
+		Token:       linkToken,
+		PayeeName:   "Seller",
+		EMV:         "000201010211",
+	}
+}
+
+func TestCreatePaymentLinkHandler(t *testing.T) {
+	create := func(svc *WalletService, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/payment-links", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "key-link")
+		req.Header.Set("X-Actor-ID", memberUserID)
+		w := httptest.NewRecorder()
+		svc.CreatePaymentLinkHandler(w, req)
+		return w
+	}
+
+	t.Run("created", func(t *testing.T) {
+		svc, logicMock, daoMock := setupTestService()
+		daoMock.On("CheckIdempotencyKey", "key-link", "POST", "/payment-links").Return(nil, false).Once()
+		daoMock.On("SaveIdempotencyKey", mock.Anything).Return(nil).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, sellerWalletID, memberUserID, logic.AccessSpend, 0.0).Return(nil).Once()
+		logicMock.On("CreatePaymentLink", mock.Anything, mock.MatchedBy(func(in logic.PaymentLinkInput) bool {
+			return in.WalletID == sellerWalletID && *in.Amount == 25 && in.SingleUse && in.Reference == "INV-42" &&
+				in.CreatedBy == memberUserID
+		})).Return(testPaymentLink(nil), nil).Once()
+
+		w := create(svc, `{"wallet_id": "`+sellerWalletID+`", "amount": 25, "reference": "INV-42", "single_use": true}`)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"token":"`+linkToken+`"`)
+		logicMock.AssertExpectations(t)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		svc, logicMock, daoMock := setupTestService()
+		daoMock.On("CheckIdempotencyKey", "key-link", "POST", "/payment-links").Return(nil, false).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, sellerWalletID, memberUserID, logic.AccessSpend, 0.0).Return(nil).Once()
+		logicMock.On("CreatePaymentLink", mock.Anything, mock.Anything).Return(nil, logic.ErrPaymentLinksDisabled).Once()
+
+		w := create(svc, `{"wallet_id": "`+sellerWalletID+`"}`)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
+
+func TestResolvePaymentLinkHandler(t *testing.T) {
+	get := func(handler http.HandlerFunc, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		handler(w, withRouteParam(req, "token", linkToken))
+		return w
+	}
+
+	t.Run("resolved", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("ResolvePaymentLink", mock.Anything, linkToken).Return(testPaymentLink(nil), nil).Once()
+
+		w := get(svc.ResolvePaymentLinkHandler, "/payment-links/"+linkToken)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"emv": "000201010211"`)
+	})
+
+	t.Run("used", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("ResolvePaymentLink", mock.Anything, linkToken).Return(nil, logic.ErrPaymentLinkUsed).Once()
+
+		w := get(svc.ResolvePaymentLinkHandler, "/payment-links/"+linkToken)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":1038`)
+	})
+
+	t.Run("QR code", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("PaymentLinkQR", mock.Anything, linkToken, 4).Return([]byte("\x89PNG"), nil).Once()
+
+		w := get(svc.PaymentLinkQRHandler, "/payment-links/"+linkToken+"/qr?scale=4")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+		assert.Equal(t, "\x89PNG", w.Body.String())
+	})
+
+	t.Run("QR scale out of range", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+
+		w := get(svc.PaymentLinkQRHandler, "/payment-links/"+linkToken+"/qr?scale=100")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		logicMock.AssertNotCalled(t, "PaymentLinkQR", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTransferHandlerPaymentLink(t *testing.T) {
+	transfer := func(svc *WalletService, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/wallets/transfer", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "key-link-transfer")
+		req.Header.Set("X-Actor-ID", memberUserID)
+		w := httptest.NewRecorder()
+		svc.TransferHandler(w, req)
+		return w
+	}
+	amount := 25.0
+
+	t.Run("pays the link amount", func(t *testing.T) {
+		svc, logicMock, daoMock := setupTestService()
+		daoMock.On("CheckIdempotencyKey", "key-link-transfer", "POST", "/wallets/transfer").Return(nil, false).Once()
+		daoMock.On("SaveIdempotencyKey", mock.Anything).Return(nil).Once()
+		logicMock.On("ResolvePaymentLink", mock.Anything, linkToken).Return(testPaymentLink(&amount), nil).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessSpend, 25.0).Return(nil).Once()
+		logicMock.On("PayPaymentLink", mock.Anything, linkToken, payerWalletID, 25.0).
+			Return(&logic.PaymentLinkPayment{LinkID: linkID, Amount: 25, TransactionID: "tx-1"}, nil).Once()
+		logicMock.On("GetBalance", mock.Anything, payerWalletID).Return(75.0, nil).Once()
+
+		w := transfer(svc, `{"from_wallet_id": "`+payerWalletID+`", "link_token": "`+linkToken+`"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"payment_link_id":"`+linkID+`"`)
+		logicMock.AssertExpectations(t)
+		logicMock.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("payee differs from the link", func(t *testing.T) {
+		svc, logicMock, daoMock := setupTestService()
+		daoMock.On("CheckIdempotencyKey", "key-link-transfer", "POST", "/wallets/transfer").Return(nil, false).Once()
+		logicMock.On("ResolvePaymentLink", mock.Anything, linkToken).Return(testPaymentLink(&amount), nil).Once()
+
+		w := transfer(svc, `{"from_wallet_id": "`+payerWalletID+`", "to_wallet_id": "`+thirdWalletID+`", "link_token": "`+linkToken+`"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		logicMock.AssertNotCalled(t, "PayPaymentLink", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("open amount left out", func(t *testing.T) {
+		svc, logicMock, daoMock := setupTestService()
+		daoMock.On("CheckIdempotencyKey", "key-link-transfer", "POST", "/wallets/transfer").Return(nil, false).Once()
+		logicMock.On("ResolvePaymentLink", mock.Anything, linkToken).Return(testPaymentLink(nil), nil).Once()
+
+		w := transfer(svc, `{"from_wallet_id": "`+payerWalletID+`", "link_token": "`+linkToken+`"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		svc, logicMock, daoMock := setupTestService()
+		daoMock.On("CheckIdempotencyKey", "key-link-transfer", "POST", "/wallets/transfer").Return(nil, false).Once()
+		logicMock.On("ResolvePaymentLink", mock.Anything, "forged").Return(nil, logic.ErrPaymentLinkNotFound).Once()
+
+		w := transfer(svc, `{"from_wallet_id": "`+payerWalletID+`", "amount": 10, "link_token": "forged"}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":1037`)
+	})
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+// CreatePaymentRequestHandler asks another wallet to pay the requester wallet.
+func (s *WalletService) CreatePaymentRequestHandler(w http.ResponseWriter, r *http.Request) {
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	if idempotencyKey == "" {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Missing Idempotency-Key")
+		return
+	}
+
+	if record, found := s.Dao.CheckIdempotencyKey(idempotencyKey, r.Method, r.URL.Path); found {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(record.StatusCode)
+		_, _ = w.Write([]byte(record.Response))
+		return
+	}
+
+	var req dto.PaymentRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+	if !isUUID(w, req.RequesterWalletID, "requester_wallet_id") || !isUUID(w, req.PayerWalletID, "payer_wallet_id") {
+		return
+	}
+	if req.Amount <= 0.1 {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Amount must be more than 0.1")
+		return
+	}
+	if !s.authorizeWallet(w, r, req.RequesterWalletID, logic.AccessSpend, 0) {
+		return
+	}
+
+	request, err := s.Impl.CreatePaymentRequest(r.Context(), logic.PaymentRequestInput{
+		RequesterWalletID: req.RequesterWalletID,
+		PayerWalletID:     req.PayerWalletID,
+		Amount:            req.Amount,
+		Note:              req.Note,
+		ExpiresAt:         req.ExpiresAt,
+		CreatedBy:         common.GetActorID(r),
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to create payment request")
+		s.writePaymentRequestError(w, err, "Failed to create payment request")
+		return
+	}
+
+	s.writeIdempotent(w, r, idempotencyKey, http.StatusCreated, dto.GenericResponse[*dao.PaymentRequest]{
+		Status: "success",
+		Data:   request,
+	})
+}
+
+// ListPaymentRequestsHandler lists the requests the wallet in the path was asked to pay, or with
+// direction=outgoing the ones it sent. status narrows the list to one status.
+func (s *WalletService) ListPaymentRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+	direction := r.URL.Query().Get("direction")
+	if direction != "" && direction != "incoming" && direction != "outgoing" {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "direction must be incoming or outgoing")
+		return
+	}
+	if !s.authorizeWallet(w, r, walletID, logic.AccessView, 0) {
+		return
+	}
+
+	requests, err := s.Impl.ListPaymentRequests(r.Context(), walletID, direction != "outgoing", r.URL.Query().Get("status"))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list payment requests")
+		s.writePaymentRequestError(w, err, "Failed to list payment requests")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[[]dao.PaymentRequest]{
+		Status: "success",
+		Data:   requests,
+	})
+}
+
+// GetPaymentRequestHandler returns a request to a member of the requester or the payer wallet.
+func (s *WalletService) GetPaymentRequestHandler(w http.ResponseWriter, r *http.Request) {
+	request, ok := s.loadPaymentRequest(w, r)
+	if !ok {
+		return
+	}
+	if actorID := common.GetActorID(r); actorID == "" ||
+		s.Impl.AuthorizeWallet(r.Context(), request.RequesterWalletID, actorID, logic.AccessView, 0) != nil {
+		if !s.authorizeWallet(w, r, request.PayerWalletID, logic.AccessView, 0) {
+			return
+		}
+	}
+	s.writePaymentRequest(w, request)
+}
+
+// AcceptPaymentRequestHandler pays a pending request out of the payer wallet.
+func (s *WalletService) AcceptPaymentRequestHandler(w http.ResponseWriter, r *http.Request) {
+	request, ok := s.loadPaymentRequest(w, r)
+	if !ok || !s.authorizeWallet(w, r, request.PayerWalletID, logic.AccessSpend, request.Amount) {
+		return
+	}
+
+	request, err := s.Impl.AcceptPaymentRequest(r.Context(), request.ID, common.GetActorID(r))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to accept payment request")
+		s.writePaymentRequestError(w, err, "Failed to accept payment request")
+		return
+	}
+	s.writePaymentRequest(w, request)
+}
+
+// DeclinePaymentRequestHandler is the payer turning a pending request down.
+func (s *WalletService) DeclinePaymentRequestHandler(w http.ResponseWriter, r *http.Request) {
+	request, ok := s.loadPaymentRequest(w, r)
+	if !ok || !s.authorizeWallet(w, r, request.PayerWalletID, logic.AccessSpend, 0) {
+		return
+	}
+
+	request, err := s.Impl.DeclinePaymentRequest(r.Context(), request.ID, common.GetActorID(r))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to decline payment request")
+		s.writePaymentRequestError(w, err, "Failed to decline payment request")
+		return
+	}
+	s.writePaymentRequest(w, request)
+}
+
+// CancelPaymentRequestHandler is the requester withdrawing a pending request.
+func (s *WalletService) CancelPaymentRequestHandler(w http.ResponseWriter, r *http.Request) {
+	request, ok := s.loadPaymentRequest(w, r)
+	if !ok || !s.authorizeWallet(w, r, request.RequesterWalletID, logic.AccessSpend, 0) {
+		return
+	}
+
+	request, err := s.Impl.CancelPaymentRequest(r.Context(), request.ID, common.GetActorID(r))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to cancel payment request")
+		s.writePaymentRequestError(w, err, "Failed to cancel payment request")
+		return
+	}
+	s.writePaymentRequest(w, request)
+}
+
+// loadPaymentRequest fetches the request in the path, writing the error response when it cannot.
+func (s *WalletService) loadPaymentRequest(w http.ResponseWriter, r *http.Request) (*dao.PaymentRequest, bool) {
+	requestID := chi.URLParam(r, "id")
+	if requestID == "" || !isUUID(w, requestID, "request_id") {
+		return nil, false
+	}
+
+	request, err := s.Impl.GetPaymentRequest(r.Context(), requestID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to fetch payment request")
+		s.writePaymentRequestError(w, err, "Failed to fetch payment request")
+		return nil, false
+	}
+	return request, true
+}
+
+func (s *WalletService) writePaymentRequest(w http.ResponseWriter, request *dao.PaymentRequest) {
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*dao.PaymentRequest]{
+		Status: "success",
+		Data:   request,
+	})
+}
+
+// writePaymentRequestError writes the response for a failed payment request operation.
+func (s *WalletService) writePaymentRequestError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case logic.ErrPaymentRequestNotFound:
+		common.WriteError(w, http.StatusNotFound, common.ErrRequestNotFound, err.Error())
+	case logic.ErrPaymentRequestStatusConflict, logic.ErrPaymentRequestExpired:
+		common.WriteError(w, http.StatusConflict, common.ErrRequestConflict, err.Error())
+	case logic.ErrPaymentRequestRequiresApproval:
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+	case logic.ErrPaymentRequestNeedsReview:
+		common.WriteError(w, http.StatusForbidden, common.ErrTransactionDenied, err.Error())
+	case logic.ErrSanctionsMatch:
+		common.WriteError(w, http.StatusForbidden, common.ErrSanctionsMatch, err.Error())
+	default:
+		if errors.Is(err, logic.ErrInvalidPaymentRequest) {
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+			return
+		}
+		status, code, message := transferError(err)
+		if code == common.ErrUnknown {
+			message = fallback
+		}
+		common.WriteError(w, status, code, message)
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const requestID = "60000000-0000-0000-0000-000000000001"
+
+// testPaymentRequest asks the payer wallet to pay the seller wallet.
+func testPaymentRequest(status string) *dao.PaymentRequest {
+	return &dao.PaymentRequest{ID: requestID, RequesterWalletID: sellerWalletID, PayerWalletID: payerWalletID, Amount: 25, Status: status}
+}
+
+func TestCreatePaymentRequestHandler(t *testing.T) {
+	create := func(svc *WalletService, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/payment-requests", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "key-request")
+		req.Header.Set("X-Actor-ID", memberUserID)
+		w := httptest.NewRecorder()
+		svc.CreatePaymentRequestHandler(w, req)
+		return w
+	}
+	body := `{"requester_wallet_id": "` + sellerWalletID + `", "payer_wallet_id": "` + payerWalletID + `", "amount": 25, "note": "dinner"}`
+
+	t.Run("created", func(t *testing.T) {
+		svc, logicMock, daoMock := setupTestService()
+		daoMock.On("CheckIdempotencyKey", "key-request", "POST", "/payment-requests").Return(nil, false).Once()
+		daoMock.On("SaveIdempotencyKey", mock.Anything).Return(nil).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, sellerWalletID, memberUserID, logic.AccessSpend, 0.0).Return(nil).Once()
+		logicMock.On("CreatePaymentRequest", mock.Anything, mock.MatchedBy(func(in logic.PaymentRequestInput) bool {
+			return in.RequesterWalletID == sellerWalletID && in.PayerWalletID == payerWalletID && in.Amount == 25 &&
+				in.Note == "dinner" && in.CreatedBy == memberUserID
+		})).Return(testPaymentRequest("pending"), nil).Once()
+
+		w := create(svc, body)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"status":"pending"`)
+		logicMock.AssertExpectations(t)
+	})
+
+	t.Run("invalid payer", func(t *testing.T) {
+		svc, logicMock, daoMock := setupTestService()
+		daoMock.On("CheckIdempotencyKey", "key-request", "POST", "/payment-requests").Return(nil, false).Once()
+
+		w := create(svc, `{"requester_wallet_id": "`+sellerWalletID+`", "payer_wallet_id": "nope", "amount": 25}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		logicMock.AssertNotCalled(t, "CreatePaymentRequest", mock.Anything, mock.Anything)
+	})
+}
+
+func TestListPaymentRequestsHandler(t *testing.T) {
+	list := func(svc *WalletService, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/wallets/"+payerWalletID+"/payment-requests"+query, nil)
+		req.Header.Set("X-Actor-ID", memberUserID)
+		w := httptest.NewRecorder()
+		svc.ListPaymentRequestsHandler(w, withRouteParam(req, "id", payerWalletID))
+		return w
+	}
+
+	t.Run("incoming pending", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessView, 0.0).Return(nil).Once()
+		logicMock.On("ListPaymentRequests", mock.Anything, payerWalletID, true, "pending").
+			Return([]dao.PaymentRequest{*testPaymentRequest("pending")}, nil).Once()
+
+		w := list(svc, "?status=pending")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), requestID)
+	})
+
+	t.Run("outgoing", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessView, 0.0).Return(nil).Once()
+		logicMock.On("ListPaymentRequests", mock.Anything, payerWalletID, false, "").Return([]dao.PaymentRequest{}, nil).Once()
+
+		w := list(svc, "?direction=outgoing")
+		assert.Equal(t, http.StatusOK, w.Code)
+		logicMock.AssertExpectations(t)
+	})
+
+	t.Run("unknown direction", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+
+		w := list(svc, "?direction=sideways")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		logicMock.AssertNotCalled(t, "ListPaymentRequests", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAcceptPaymentRequestHandler(t *testing.T) {
+	accept := func(svc *WalletService) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/payment-requests/"+requestID+"/accept", nil)
+		req.Header.Set("X-Actor-ID", memberUserID)
+		w := httptest.NewRecorder()
+		svc.AcceptPaymentRequestHandler(w, withRouteParam(req, "id", requestID))
+		return w
+	}
+
+	t.Run("paid", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("GetPaymentRequest", mock.Anything, requestID).Return(testPaymentRequest("pending"), nil).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessSpend, 25.0).Return(nil).Once()
+		logicMock.On("AcceptPaymentRequest", mock.Anything, requestID, memberUserID).Return(testPaymentRequest("accepted"), nil).Once()
+
+		w := accept(svc)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status": "accepted"`)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("GetPaymentRequest", mock.Anything, requestID).Return(testPaymentRequest("pending"), nil).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessSpend, 25.0).Return(nil).Once()
+		logicMock.On("AcceptPaymentRequest", mock.Anything, requestID, memberUserID).Return(nil, logic.ErrPaymentRequestExpired).Once()
+
+		w := accept(svc)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":1036`)
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("GetPaymentRequest", mock.Anything, requestID).Return(testPaymentRequest("pending"), nil).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessSpend, 25.0).Return(nil).Once()
+		logicMock.On("AcceptPaymentRequest", mock.Anything, requestID, memberUserID).Return(nil, logic.ErrInsufficientBalance).Once()
+
+		w := accept(svc)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":1002`)
+	})
+
+	t.Run("requester cannot accept", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("GetPaymentRequest", mock.Anything, requestID).Return(testPaymentRequest("pending"), nil).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessSpend, 25.0).
+			Return(logic.ErrWalletAccessDenied).Once()
+
+		w := accept(svc)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		logicMock.AssertNotCalled(t, "AcceptPaymentRequest", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown request", func(t *testing.T) {
+		svc, logicMock, _ := setupTestService()
+		logicMock.On("GetPaymentRequest", mock.Anything, requestID).Return(nil, logic.ErrPaymentRequestNotFound).Once()
+
+		w := accept(svc)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":1035`)
+	})
+}
+
+func TestDeclineAndCancelPaymentRequestHandlers(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	logicMock.On("GetPaymentRequest", mock.Anything, requestID).Return(testPaymentRequest("pending"), nil)
+	logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessSpend, 0.0).Return(nil).Once()
+	logicMock.On("DeclinePaymentRequest", mock.Anything, requestID, memberUserID).Return(testPaymentRequest("declined"), nil).Once()
+	logicMock.On("AuthorizeWallet", mock.Anything, sellerWalletID, memberUserID, logic.AccessSpend, 0.0).Return(nil).Once()
+	logicMock.On("CancelPaymentRequest", mock.Anything, requestID, memberUserID).
+		Return(nil, logic.ErrPaymentRequestStatusConflict).Once()
+
+	answer := func(action string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/payment-requests/"+requestID+"/"+action, nil)
+		req.Header.Set("X-Actor-ID", memberUserID)
+		w := httptest.NewRecorder()
+		handler(w, withRouteParam(req, "id", requestID))
+		return w
+	}
+
+	w := answer("decline", svc.DeclinePaymentRequestHandler)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status": "declined"`)
+
+	w = answer("cancel", svc.CancelPaymentRequestHandler)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	logicMock.AssertExpectations(t)
+}
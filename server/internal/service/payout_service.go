@@ -0,0 +1,134 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/payout"
+)
+
+var payoutStatuses = map[string]bool{
+	common.PayoutStatusPending:  true,
+	common.PayoutStatusSent:     true,
+	common.PayoutStatusSettled:  true,
+	common.PayoutStatusReturned: true,
+}
+
+// withdrawToBank handles a withdrawal that carries beneficiary bank details.
+func (s *WalletService) withdrawToBank(w http.ResponseWriter, r *http.Request, idempotencyKey, walletID string, req dto.WithdrawRequest) {
+	beneficiary := payout.Account{Name: req.Beneficiary.Name, IBAN: req.Beneficiary.IBAN, BIC: req.Beneficiary.BIC}
+	record, err := s.Impl.WithdrawToBank(r.Context(), walletID, req.Amount, beneficiary)
+	if err != nil {
+		s.logger.WithError(err).Error("Bank withdraw failed")
+		if errors.Is(err, logic.ErrInvalidBeneficiary) || errors.Is(err, logic.ErrInvalidPayoutAmount) {
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+			return
+		}
+		s.writeWithdrawError(w, r, idempotencyKey, err)
+		return
+	}
+
+	s.writeIdempotent(w, r, idempotencyKey, http.StatusOK, dto.GenericResponse[dto.PayoutResponse]{
+		Status: "success",
+		Data: dto.PayoutResponse{
+			Message:  "withdraw pending payout",
+			PayoutID: record.ID,
+			Status:   record.Status,
+		},
+	})
+}
+
+func (s *WalletService) ListPayoutsHandler(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status != "" && !payoutStatuses[status] {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid status")
+		return
+	}
+
+	payouts, err := s.Impl.ListPayouts(r.Context(), status)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list payouts")
+		common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to list payouts")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[[]dao.Payout]{
+		Status: "success",
+		Data:   payouts,
+	})
+}
+
+func (s *WalletService) GetPayoutHandler(w http.ResponseWriter, r *http.Request) {
+	payoutID := chi.URLParam(r, "id")
+	if payoutID == "" || !isUUID(w, payoutID, "payout_id") {
+		return
+	}
+
+	record, err := s.Impl.GetPayout(r.Context(), payoutID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to fetch payout")
+		switch err {
+		case logic.ErrPayoutNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrPayoutNotFound, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to fetch payout")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*dao.Payout]{
+		Status: "success",
+		Data:   record,
+	})
+}
+
+// UpdatePayoutStatusHandler records a bank status update for a payout. Returning a payout
+// credits the withdrawal back to the wallet.
+func (s *WalletService) UpdatePayoutStatusHandler(w http.ResponseWriter, r *http.Request) {
+	payoutID := chi.URLParam(r, "id")
+	if payoutID == "" || !isUUID(w, payoutID, "payout_id") {
+		return
+	}
+
+	actorID := common.GetActorID(r)
+	if actorID == "" {
+		common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+		return
+	}
+
+	var req dto.PayoutStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+	if req.Status != common.PayoutStatusSent && req.Status != common.PayoutStatusSettled && req.Status != common.PayoutStatusReturned {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid status, expected sent, settled or returned")
+		return
+	}
+
+	record, err := s.Impl.UpdatePayoutStatus(r.Context(), payoutID, req.Status, req.Reason, actorID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to update payout status")
+		switch err {
+		case logic.ErrPayoutNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrPayoutNotFound, err.Error())
+		case logic.ErrInvalidPayoutTransition:
+			common.WriteError(w, http.StatusConflict, common.ErrPayoutConflict, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to update payout status")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*dao.Payout]{
+		Status: "success",
+		Data:   record,
+	})
+}
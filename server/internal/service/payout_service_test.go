@@ -0,0 +1,149 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/payout"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWithdrawToBankHandler(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	walletID := "10000000-0000-0000-0000-000000000000"
+	path := "/wallets/" + walletID + "/withdraw"
+	daoMock.On("CheckIdempotencyKey", mock.Anything, "POST", path).Return(nil, false)
+	daoMock.On("SaveIdempotencyKey", mock.Anything).Return(nil)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "key-payout")
+		return withRouteParam(req, "id", walletID)
+	}
+	beneficiary := payout.Account{Name: "Jane Doe", IBAN: "GB82WEST12345698765432"}
+
+	t.Run("queued for payout", func(t *testing.T) {
+		logicMock.On("WithdrawToBank", mock.Anything, walletID, 25.0, beneficiary).
+			Return(&dao.Payout{ID: "payout-1", Status: "pending_payout"}, nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.WithdrawHandler(w, newRequest(`{"amount": 25, "beneficiary": {"name": "Jane Doe", "iban": "GB82WEST12345698765432"}}`))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"payout_id":"payout-1"`)
+		assert.Contains(t, w.Body.String(), `"status":"pending_payout"`)
+	})
+
+	t.Run("invalid beneficiary", func(t *testing.T) {
+		logicMock.On("WithdrawToBank", mock.Anything, walletID, 30.0, mock.Anything).
+			Return(nil, logic.ErrInvalidBeneficiary).Once()
+
+		w := httptest.NewRecorder()
+		svc.WithdrawHandler(w, newRequest(`{"amount": 30, "beneficiary": {"name": "Jane Doe", "iban": "GB00"}}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		logicMock.On("WithdrawToBank", mock.Anything, walletID, 40.0, mock.Anything).
+			Return(nil, logic.ErrInsufficientBalance).Once()
+
+		w := httptest.NewRecorder()
+		svc.WithdrawHandler(w, newRequest(`{"amount": 40, "beneficiary": {"name": "Jane Doe", "iban": "GB82WEST12345698765432"}}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "insufficient balance")
+	})
+
+	t.Run("plain withdrawal is unchanged", func(t *testing.T) {
+		logicMock.On("Withdraw", mock.Anything, walletID, 5.0).Return(nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.WithdrawHandler(w, newRequest(`{"amount": 5}`))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "withdraw success")
+	})
+}
+
+func TestListPayoutsHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+
+	logicMock.On("ListPayouts", mock.Anything, "sent").Return([]dao.Payout{{ID: "payout-1", Status: "sent"}}, nil).Once()
+	w := httptest.NewRecorder()
+	svc.ListPayoutsHandler(w, httptest.NewRequest(http.MethodGet, "/admin/payouts?status=sent", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id": "payout-1"`)
+
+	w = httptest.NewRecorder()
+	svc.ListPayoutsHandler(w, httptest.NewRequest(http.MethodGet, "/admin/payouts?status=lost", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetPayoutHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	payoutID := "20000000-0000-0000-0000-000000000001"
+	newRequest := func(id string) *http.Request {
+		return withRouteParam(httptest.NewRequest(http.MethodGet, "/admin/payouts/"+id, nil), "id", id)
+	}
+
+	logicMock.On("GetPayout", mock.Anything, payoutID).Return(&dao.Payout{ID: payoutID, Status: "sent"}, nil).Once()
+	w := httptest.NewRecorder()
+	svc.GetPayoutHandler(w, newRequest(payoutID))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	missing := "20000000-0000-0000-0000-000000000002"
+	logicMock.On("GetPayout", mock.Anything, missing).Return(nil, logic.ErrPayoutNotFound).Once()
+	w = httptest.NewRecorder()
+	svc.GetPayoutHandler(w, newRequest(missing))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	svc.GetPayoutHandler(w, newRequest("abc"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestUpdatePayoutStatusHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	payoutID := "20000000-0000-0000-0000-000000000001"
+	newRequest := func(body, actor string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/admin/payouts/"+payoutID+"/status", strings.NewReader(body))
+		if actor != "" {
+			req.Header.Set("X-Actor-ID", actor)
+		}
+		return withRouteParam(req, "id", payoutID)
+	}
+
+	t.Run("returned", func(t *testing.T) {
+		reversal := "tx-r"
+		logicMock.On("UpdatePayoutStatus", mock.Anything, payoutID, "returned", "AC04", "operator-1").
+			Return(&dao.Payout{ID: payoutID, Status: "returned", ReversalTransactionID: &reversal}, nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.UpdatePayoutStatusHandler(w, newRequest(`{"status": "returned", "reason": "AC04"}`, "operator-1"))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"reversal_transaction_id": "tx-r"`)
+	})
+
+	t.Run("invalid transition", func(t *testing.T) {
+		logicMock.On("UpdatePayoutStatus", mock.Anything, payoutID, "settled", "", "operator-1").
+			Return(nil, logic.ErrInvalidPayoutTransition).Once()
+
+		w := httptest.NewRecorder()
+		svc.UpdatePayoutStatusHandler(w, newRequest(`{"status": "settled"}`, "operator-1"))
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("unknown status", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.UpdatePayoutStatusHandler(w, newRequest(`{"status": "pending_payout"}`, "operator-1"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("needs an operator", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.UpdatePayoutStatusHandler(w, newRequest(`{"status": "settled"}`, ""))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+// GetWalletHandler returns a wallet with its pockets, their goals and the combined balance.
+func (s *WalletService) GetWalletHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+	if !s.authorizeWallet(w, r, walletID, logic.AccessView, 0) {
+		return
+	}
+
+	view, err := s.Impl.GetWalletView(r.Context(), walletID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get wallet")
+		if err == logic.ErrWalletNotFound {
+			common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
+			return
+		}
+		common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to get wallet")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*logic.WalletView]{
+		Status: "success",
+		Data:   view,
+	})
+}
+
+// CreatePocketHandler opens a pocket under the wallet in the path.
+func (s *WalletService) CreatePocketHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+
+	var req dto.CreatePocketRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+	goalDate, ok := parseGoalDate(w, req.GoalDate)
+	if !ok || !s.authorizeWallet(w, r, walletID, logic.AccessManage, 0) {
+		return
+	}
+
+	pocket, err := s.Impl.CreatePocket(r.Context(), walletID, req.Name, req.GoalAmount, goalDate)
+	if err != nil {
+		s.logger.WithError(err).Error("Create pocket failed")
+		s.writePocketError(w, err, "Create pocket failed")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusCreated, dto.GenericResponse[*dao.Wallet]{
+		Status: "success",
+		Data:   pocket,
+	})
+}
+
+// SetPocketGoalHandler sets or clears the goal of a pocket.
+func (s *WalletService) SetPocketGoalHandler(w http.ResponseWriter, r *http.Request) {
+	pocketID := chi.URLParam(r, "id")
+	if pocketID == "" || !isUUID(w, pocketID, "pocket_id") {
+		return
+	}
+
+	var req dto.PocketGoalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+	goalDate, ok := parseGoalDate(w, req.GoalDate)
+	if !ok || !s.authorizeWallet(w, r, pocketID, logic.AccessManage, 0) {
+		return
+	}
+
+	pocket, err := s.Impl.SetPocketGoal(r.Context(), pocketID, req.GoalAmount, goalDate)
+	if err != nil {
+		s.logger.WithError(err).Error("Set pocket goal failed")
+		s.writePocketError(w, err, "Set pocket goal failed")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*logic.Pocket]{
+		Status: "success",
+		Data:   pocket,
+	})
+}
+
+// MovePocketFundsHandler moves money between the wallet in the path and its pockets.
+func (s *WalletService) MovePocketFundsHandler(w http.ResponseWriter, r *http.Request) {
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	if idempotencyKey == "" {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Missing Idempotency-Key")
+		return
+	}
+
+	if record, found := s.Dao.CheckIdempotencyKey(idempotencyKey, r.Method, r.URL.Path); found {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(record.StatusCode)
+		_, _ = w.Write([]byte(record.Response))
+		return
+	}
+
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+
+	var req dto.PocketMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+	if !isUUID(w, req.FromWalletID, "from_wallet_id") || !isUUID(w, req.ToWalletID, "to_wallet_id") {
+		return
+	}
+	if req.Amount <= 0 {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Amount must be positive")
+		return
+	}
+	// the money stays in the wallet, so pocket moves do not count against a spending cap
+	if !s.authorizeWallet(w, r, walletID, logic.AccessSpend, 0) {
+		return
+	}
+
+	view, err := s.Impl.MovePocketFunds(r.Context(), walletID, req.FromWalletID, req.ToWalletID, req.Amount)
+	if err != nil {
+		s.logger.WithError(err).Error("Pocket move failed")
+		s.writePocketError(w, err, "Pocket move failed")
+		return
+	}
+
+	s.writeIdempotent(w, r, idempotencyKey, http.StatusOK, dto.GenericResponse[*logic.WalletView]{
+		Status: "success",
+		Data:   view,
+	})
+}
+
+// writePocketError writes the response for a failed pocket operation.
+func (s *WalletService) writePocketError(w http.ResponseWriter, err error, fallback string) {
+	switch err {
+	case logic.ErrPocketNameRequired, logic.ErrInvalidPocketGoal, logic.ErrInvalidPocketParent,
+		logic.ErrTooManyPockets, logic.ErrNotPocket, logic.ErrInvalidPocketMove:
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+	case logic.ErrInsufficientBalance:
+		common.WriteError(w, http.StatusBadRequest, common.ErrInsufficientBalance, err.Error())
+	case logic.ErrWalletNotFound:
+		common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
+	case logic.ErrWalletFrozen:
+		common.WriteError(w, http.StatusForbidden, common.ErrWalletFrozen, err.Error())
+	default:
+		common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, fallback)
+	}
+}
+
+// parseGoalDate reads an optional YYYY-MM-DD goal date. It writes the error response and returns
+// false when the date is malformed.
+func parseGoalDate(w http.ResponseWriter, raw *string) (*time.Time, bool) {
+	if raw == nil || *raw == "" {
+		return nil, true
+	}
+	date, err := time.Parse(time.DateOnly, *raw)
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid goal_date, expected YYYY-MM-DD")
+		return nil, false
+	}
+	return &date, true
+}
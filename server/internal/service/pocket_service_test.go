@@ -0,0 +1,126 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const pocketID = "20000000-0000-0000-0000-000000000001"
+
+func TestGetWalletHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	name := "rent"
+	logicMock.On("GetWalletView", mock.Anything, payerWalletID).Return(&logic.WalletView{
+		Wallet:         dao.Wallet{ID: payerWalletID, Balance: 500, Type: "standard"},
+		Pockets:        []logic.Pocket{{Wallet: dao.Wallet{ID: pocketID, Balance: 250, Type: "pocket", Name: &name}, Goal: &logic.PocketGoal{TargetAmount: 1000, Saved: 250, ProgressPercent: 25}}},
+		PocketsBalance: 250,
+		TotalBalance:   750,
+	}, nil).Once()
+
+	req := withRouteParam(httptest.NewRequest(http.MethodGet, "/wallets/"+payerWalletID, nil), "id", payerWalletID)
+	w := httptest.NewRecorder()
+	svc.GetWalletHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total_balance": 750`)
+	assert.Contains(t, w.Body.String(), `"name": "rent"`)
+	assert.Contains(t, w.Body.String(), `"progress_percent": 25`)
+	logicMock.AssertExpectations(t)
+}
+
+func TestCreatePocketHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	create := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/wallets/"+payerWalletID+"/pockets", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		svc.CreatePocketHandler(w, withRouteParam(req, "id", payerWalletID))
+		return w
+	}
+
+	goal, date := 1200.0, time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC)
+	logicMock.On("CreatePocket", mock.Anything, payerWalletID, "holiday", &goal, &date).
+		Return(&dao.Wallet{ID: pocketID, Type: "pocket"}, nil).Once()
+	assert.Equal(t, http.StatusCreated, create(`{"name": "holiday", "goal_amount": 1200, "goal_date": "2027-06-01"}`).Code)
+
+	logicMock.On("CreatePocket", mock.Anything, payerWalletID, "", (*float64)(nil), (*time.Time)(nil)).
+		Return(nil, logic.ErrPocketNameRequired).Once()
+	assert.Equal(t, http.StatusBadRequest, create(`{}`).Code)
+
+	assert.Equal(t, http.StatusBadRequest, create(`{"name": "holiday", "goal_amount": 1200, "goal_date": "June"}`).Code)
+	logicMock.AssertExpectations(t)
+}
+
+func TestMovePocketFundsHandler(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	move := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/wallets/"+payerWalletID+"/pockets/move", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", key)
+		w := httptest.NewRecorder()
+		svc.MovePocketFundsHandler(w, withRouteParam(req, "id", payerWalletID))
+		return w
+	}
+	body := `{"from_wallet_id": "` + payerWalletID + `", "to_wallet_id": "` + pocketID + `", "amount": 200}`
+
+	daoMock.On("CheckIdempotencyKey", "key-move", "POST", "/wallets/"+payerWalletID+"/pockets/move").Return(nil, false).Once()
+	daoMock.On("SaveIdempotencyKey", mock.Anything).Return(nil).Once()
+	logicMock.On("MovePocketFunds", mock.Anything, payerWalletID, payerWalletID, pocketID, 200.0).
+		Return(&logic.WalletView{Wallet: dao.Wallet{ID: payerWalletID, Balance: 300}, Pockets: []logic.Pocket{}, TotalBalance: 500}, nil).Once()
+	w := move("key-move", body)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total_balance":500`)
+
+	daoMock.On("CheckIdempotencyKey", "key-short", "POST", "/wallets/"+payerWalletID+"/pockets/move").Return(nil, false).Once()
+	logicMock.On("MovePocketFunds", mock.Anything, payerWalletID, payerWalletID, pocketID, 200.0).
+		Return(nil, logic.ErrInsufficientBalance).Once()
+	assert.Equal(t, http.StatusBadRequest, move("key-short", body).Code)
+
+	assert.Equal(t, http.StatusBadRequest, move("", body).Code)
+	logicMock.AssertExpectations(t)
+}
+
+func TestPocketMovementHandlers(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	daoMock.On("CheckIdempotencyKey", mock.Anything, "POST", mock.Anything).Return(nil, false)
+	send := func(handler http.HandlerFunc, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "key-pocket")
+		w := httptest.NewRecorder()
+		handler(w, withRouteParam(req, "id", pocketID))
+		return w
+	}
+
+	t.Run("deposit", func(t *testing.T) {
+		logicMock.On("Deposit", mock.Anything, pocketID, 10.0).Return(logic.ErrPocketWallet).Once()
+		w := send(svc.DepositHandler, "/wallets/"+pocketID+"/deposit", `{"amount": 10}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":1028`)
+	})
+
+	t.Run("withdraw", func(t *testing.T) {
+		logicMock.On("Withdraw", mock.Anything, pocketID, 10.0).Return(logic.ErrPocketWallet).Once()
+		w := send(svc.WithdrawHandler, "/wallets/"+pocketID+"/withdraw", `{"amount": 10}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":1028`)
+	})
+
+	t.Run("transfer", func(t *testing.T) {
+		logicMock.On("ScreenTransferParties", mock.Anything, pocketID, payerWalletID).Return(nil).Once()
+		logicMock.On("RequiresApproval", 10.0).Return(false).Once()
+		logicMock.On("Transfer", mock.Anything, pocketID, payerWalletID, 10.0).Return(nil, logic.ErrPocketWallet).Once()
+		w := send(svc.TransferHandler, "/wallets/transfer",
+			`{"from_wallet_id": "`+pocketID+`", "to_wallet_id": "`+payerWalletID+`", "amount": 10}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":1028`)
+	})
+
+	logicMock.AssertExpectations(t)
+	daoMock.AssertNotCalled(t, "SaveIdempotencyKey", mock.Anything)
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/provider"
+)
+
+const maxCallbackSize = 1 << 20
+
+// withdrawViaProvider handles a withdrawal paid out through an external payment provider.
+func (s *WalletService) withdrawViaProvider(w http.ResponseWriter, r *http.Request, idempotencyKey, walletID string, req dto.WithdrawRequest) {
+	target := logic.ProviderTarget{Name: req.Provider.Name, Destination: req.Provider.Destination}
+	op, err := s.Impl.WithdrawViaProvider(r.Context(), walletID, req.Amount, target)
+	if err != nil {
+		s.logger.WithError(err).Error("Provider withdraw failed")
+		switch {
+		case errors.Is(err, logic.ErrProviderNotFound):
+			common.WriteError(w, http.StatusBadRequest, common.ErrProviderNotFound, err.Error())
+		case errors.Is(err, logic.ErrProviderPayoutFailed):
+			common.WriteError(w, http.StatusBadGateway, common.ErrProviderFailed, err.Error())
+		default:
+			s.writeWithdrawError(w, r, idempotencyKey, err)
+		}
+		return
+	}
+
+	s.writeIdempotent(w, r, idempotencyKey, http.StatusOK, dto.GenericResponse[dto.ProviderWithdrawResponse]{
+		Status: "success",
+		Data: dto.ProviderWithdrawResponse{
+			Message:     "withdraw initiated with " + op.Provider,
+			OperationID: op.ID,
+			Status:      op.Status,
+		},
+	})
+}
+
+// ProviderCallbackHandler receives status notifications from a payment provider. Anything but a
+// 2xx response makes the provider retry, so only bad signatures and unreadable bodies are
+// rejected outright.
+func (s *WalletService) ProviderCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxCallbackSize))
+	if err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+
+	processed, err := s.Impl.HandleProviderCallback(r.Context(), name, r.Header, body)
+	if err != nil {
+		s.logger.WithError(err).Errorf("Failed to handle %s callback", name)
+		switch {
+		case errors.Is(err, logic.ErrProviderNotFound):
+			common.WriteError(w, http.StatusNotFound, common.ErrProviderNotFound, err.Error())
+		case errors.Is(err, provider.ErrInvalidSignature):
+			common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, err.Error())
+		case errors.Is(err, provider.ErrMalformedCallback):
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to handle callback")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[dto.ProviderCallbackResponse]{
+		Status: "success",
+		Data:   dto.ProviderCallbackResponse{Processed: processed},
+	})
+}
+
+func (s *WalletService) GetProviderOperationHandler(w http.ResponseWriter, r *http.Request) {
+	opID := chi.URLParam(r, "id")
+	if opID == "" || !isUUID(w, opID, "operation_id") {
+		return
+	}
+
+	op, err := s.Impl.GetProviderOperation(r.Context(), opID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to fetch provider operation")
+		switch err {
+		case logic.ErrProviderOperationNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrProviderOpNotFound, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to fetch provider operation")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*dao.ProviderOperation]{
+		Status: "success",
+		Data:   op,
+	})
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWithdrawViaProviderHandler(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	walletID := "10000000-0000-0000-0000-000000000000"
+	path := "/wallets/" + walletID + "/withdraw"
+	daoMock.On("CheckIdempotencyKey", mock.Anything, "POST", path).Return(nil, false)
+	daoMock.On("SaveIdempotencyKey", mock.Anything).Return(nil)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "key-provider")
+		return withRouteParam(req, "id", walletID)
+	}
+	target := logic.ProviderTarget{Name: "fake", Destination: "card_123"}
+
+	t.Run("initiated", func(t *testing.T) {
+		logicMock.On("WithdrawViaProvider", mock.Anything, walletID, 25.0, target).
+			Return(&dao.ProviderOperation{ID: "op-1", Provider: "fake", Status: "pending"}, nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.WithdrawHandler(w, newRequest(`{"amount": 25, "provider": {"name": "fake", "destination": "card_123"}}`))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"operation_id":"op-1"`)
+		assert.Contains(t, w.Body.String(), `"status":"pending"`)
+	})
+
+	t.Run("provider failed the payout", func(t *testing.T) {
+		logicMock.On("WithdrawViaProvider", mock.Anything, walletID, 30.0, target).
+			Return(nil, fmt.Errorf("%w: card expired", logic.ErrProviderPayoutFailed)).Once()
+
+		w := httptest.NewRecorder()
+		svc.WithdrawHandler(w, newRequest(`{"amount": 30, "provider": {"name": "fake", "destination": "card_123"}}`))
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		assert.Contains(t, w.Body.String(), "card expired")
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		logicMock.On("WithdrawViaProvider", mock.Anything, walletID, 35.0, mock.Anything).Return(nil, logic.ErrProviderNotFound).Once()
+
+		w := httptest.NewRecorder()
+		svc.WithdrawHandler(w, newRequest(`{"amount": 35, "provider": {"name": "acme"}}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		logicMock.On("WithdrawViaProvider", mock.Anything, walletID, 40.0, mock.Anything).Return(nil, logic.ErrInsufficientBalance).Once()
+
+		w := httptest.NewRecorder()
+		svc.WithdrawHandler(w, newRequest(`{"amount": 40, "provider": {"name": "fake"}}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "insufficient balance")
+	})
+
+	t.Run("beneficiary and provider together", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.WithdrawHandler(w, newRequest(`{"amount": 5, "provider": {"name": "fake"}, "beneficiary": {"name": "Jane Doe", "iban": "GB82WEST12345698765432"}}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestProviderCallbackHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	newRequest := func(name, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/providers/"+name+"/callbacks", strings.NewReader(body))
+		return withRouteParam(req, "name", name)
+	}
+	body := `{"type": "payout", "reference": "op-1", "status": "succeeded"}`
+
+	logicMock.On("HandleProviderCallback", mock.Anything, "fake", mock.Anything, []byte(body)).Return(1, nil).Once()
+	w := httptest.NewRecorder()
+	svc.ProviderCallbackHandler(w, newRequest("fake", body))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"processed": 1`)
+
+	logicMock.On("HandleProviderCallback", mock.Anything, "fake", mock.Anything, []byte("{}")).Return(0, provider.ErrInvalidSignature).Once()
+	w = httptest.NewRecorder()
+	svc.ProviderCallbackHandler(w, newRequest("fake", "{}"))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	logicMock.On("HandleProviderCallback", mock.Anything, "fake", mock.Anything, []byte("nope")).
+		Return(0, fmt.Errorf("%w: bad json", provider.ErrMalformedCallback)).Once()
+	w = httptest.NewRecorder()
+	svc.ProviderCallbackHandler(w, newRequest("fake", "nope"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	logicMock.On("HandleProviderCallback", mock.Anything, "acme", mock.Anything, mock.Anything).Return(0, logic.ErrProviderNotFound).Once()
+	w = httptest.NewRecorder()
+	svc.ProviderCallbackHandler(w, newRequest("acme", body))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetProviderOperationHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	opID := "30000000-0000-0000-0000-000000000001"
+	newRequest := func(id string) *http.Request {
+		return withRouteParam(httptest.NewRequest(http.MethodGet, "/admin/provider-operations/"+id, nil), "id", id)
+	}
+
+	logicMock.On("GetProviderOperation", mock.Anything, opID).Return(&dao.ProviderOperation{ID: opID, Status: "succeeded"}, nil).Once()
+	w := httptest.NewRecorder()
+	svc.GetProviderOperationHandler(w, newRequest(opID))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status": "succeeded"`)
+
+	missing := "30000000-0000-0000-0000-000000000002"
+	logicMock.On("GetProviderOperation", mock.Anything, missing).Return(nil, logic.ErrProviderOperationNotFound).Once()
+	w = httptest.NewRecorder()
+	svc.GetProviderOperationHandler(w, newRequest(missing))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	svc.GetProviderOperationHandler(w, newRequest("abc"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
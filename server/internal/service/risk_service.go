@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+func (s *WalletService) ListTransactionReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	if common.GetActorID(r) == "" {
+		common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = common.ReviewStatusPending
+	}
+
+	reviews, err := s.Impl.ListTransactionReviews(r.Context(), status)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list transaction reviews")
+		common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to list transaction reviews")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[[]dao.TransactionReview]{
+		Status: "success",
+		Data:   reviews,
+	})
+}
+
+func (s *WalletService) ApproveTransactionReviewHandler(w http.ResponseWriter, r *http.Request) {
+	actorID := common.GetActorID(r)
+	if actorID == "" {
+		common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+		return
+	}
+
+	reviewID := chi.URLParam(r, "id")
+	if reviewID == "" || !isUUID(w, reviewID, "review_id") {
+		return
+	}
+
+	review, err := s.Impl.ApproveTransactionReview(r.Context(), reviewID, actorID)
+	if review != nil {
+		s.storeReviewOutcome(r.Context(), review, err)
+	}
+	if err != nil {
+		s.logger.WithError(err).Error("Review approval failed")
+		httpStatus, code, message := reviewError(err)
+		common.WriteError(w, httpStatus, code, message)
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[dto.SuccessResponse]{
+		Status: "success",
+		Data:   dto.SuccessResponse{Message: "review approved"},
+	})
+}
+
+func (s *WalletService) RejectTransactionReviewHandler(w http.ResponseWriter, r *http.Request) {
+	actorID := common.GetActorID(r)
+	if actorID == "" {
+		common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+		return
+	}
+
+	reviewID := chi.URLParam(r, "id")
+	if reviewID == "" || !isUUID(w, reviewID, "review_id") {
+		return
+	}
+
+	review, err := s.Impl.RejectTransactionReview(r.Context(), reviewID, actorID)
+	if err != nil {
+		s.logger.WithError(err).Error("Review rejection failed")
+		if err == logic.ErrReviewNotFound {
+			common.WriteError(w, http.StatusNotFound, common.ErrReviewNotFound, err.Error())
+		} else {
+			common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, "Review rejection failed")
+		}
+		return
+	}
+	s.storeReviewOutcome(r.Context(), review, nil)
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[dto.SuccessResponse]{
+		Status: "success",
+		Data:   dto.SuccessResponse{Message: "review rejected"},
+	})
+}
+
+func reviewError(err error) (int, int, string) {
+	switch err {
+	case logic.ErrReviewNotFound:
+		return http.StatusNotFound, common.ErrReviewNotFound, err.Error()
+	case logic.ErrWalletNotFound:
+		return http.StatusNotFound, common.ErrWalletNotFound, err.Error()
+	case logic.ErrInsufficientBalance:
+		return http.StatusBadRequest, common.ErrInsufficientBalance, err.Error()
+	default:
+		return http.StatusInternalServerError, common.ErrUnknown, "Review approval failed"
+	}
+}
+
+// storeReviewOutcome replaces the pending response saved for the idempotency key of the request a
+// review parked with the outcome of the decision.
+func (s *WalletService) storeReviewOutcome(ctx context.Context, review *dao.TransactionReview, execErr error) {
+	if review.IdempotencyKey == "" {
+		return
+	}
+
+	statusCode, resp := s.reviewOutcome(ctx, review, execErr)
+	respJSON, _ := json.Marshal(resp)
+
+	err := s.Dao.UpdateIdempotencyKey(&dao.IdempotencyRecord{
+		Key:        review.IdempotencyKey,
+		Method:     review.RequestMethod,
+		Path:       review.RequestPath,
+		Response:   string(respJSON),
+		StatusCode: statusCode,
+	})
+	if err != nil {
+		s.logger.WithError(err).Errorf("Failed to store outcome of review %s", review.ID)
+	}
+}
+
+func (s *WalletService) reviewOutcome(ctx context.Context, review *dao.TransactionReview, execErr error) (int, any) {
+	if review.Status == common.ReviewStatusRejected {
+		return http.StatusForbidden, common.ErrorResponse{
+			Error: common.ErrorDetail{Code: common.ErrTransactionDenied, Message: review.Type + " rejected by review"},
+		}
+	}
+	if execErr != nil {
+		httpStatus, code, message := reviewError(execErr)
+		if review.Type == common.TransactionTypeTransfer {
+			httpStatus, code, message = transferError(execErr)
+		}
+		return httpStatus, common.ErrorResponse{Error: common.ErrorDetail{Code: code, Message: message}}
+	}
+
+	if review.Type == common.TransactionTypeTransfer {
+		balance, err := s.Impl.GetBalance(ctx, review.WalletID)
+		if err != nil {
+			s.logger.WithError(err).Warn("Failed to fetch updated sender balance")
+		}
+		return http.StatusOK, dto.GenericResponse[dto.TransferResponse]{
+			Status: "success",
+			Data: dto.TransferResponse{
+				Message:  "transfer success",
+				WalletID: review.WalletID,
+				Balance:  balance,
+			},
+		}
+	}
+	return http.StatusOK, dto.GenericResponse[dto.SuccessResponse]{
+		Status: "success",
+		Data:   dto.SuccessResponse{Message: review.Type + " success"},
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransferHandlerScreening(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	body := `{"from_wallet_id":"10000000-0000-0000-0000-000000000001","to_wallet_id":"10000000-0000-0000-0000-000000000002","amount":50}`
+	logicMock.On("ScreenTransferParties", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	logicMock.On("RequiresApproval", mock.Anything).Return(false)
+
+	t.Run("transfer parked for review", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/wallets/transfer", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "key-review")
+
+		daoMock.On("CheckIdempotencyKey", "key-review", "POST", "/wallets/transfer").Return(nil, false).Once()
+		logicMock.On("Transfer", mock.Anything, "10000000-0000-0000-0000-000000000001", "10000000-0000-0000-0000-000000000002", 50.0).
+			Return(nil, &logic.PendingReviewError{ReviewID: "review-1"}).Once()
+		daoMock.On("SaveIdempotencyKey", mock.Anything).Return(nil).Once()
+		daoMock.On("SetTransactionReviewRequest", "review-1", "key-review", "POST", "/wallets/transfer").Return(nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.TransferHandler(w, req)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Contains(t, w.Body.String(), "review-1")
+		daoMock.AssertExpectations(t)
+	})
+
+	t.Run("transfer denied", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/wallets/transfer", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "key-deny")
+
+		daoMock.On("CheckIdempotencyKey", "key-deny", "POST", "/wallets/transfer").Return(nil, false).Once()
+		logicMock.On("Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, logic.ErrTransactionDenied).Once()
+
+		w := httptest.NewRecorder()
+		svc.TransferHandler(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestApproveTransactionReviewHandler(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	reviewID := "20000000-0000-0000-0000-000000000001"
+	review := &dao.TransactionReview{
+		ID:             reviewID,
+		Type:           common.TransactionTypeTransfer,
+		WalletID:       "10000000-0000-0000-0000-000000000001",
+		Amount:         50,
+		Status:         common.ReviewStatusApproved,
+		IdempotencyKey: "key-review",
+		RequestMethod:  "POST",
+		RequestPath:    "/wallets/transfer",
+	}
+
+	t.Run("approve pending review", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/reviews/"+reviewID+"/approve", nil)
+		req.Header.Set("X-Actor-ID", "operator-1")
+		req = withRouteParam(req, "id", reviewID)
+
+		logicMock.On("ApproveTransactionReview", mock.Anything, reviewID, "operator-1").Return(review, nil).Once()
+		logicMock.On("GetBalance", mock.Anything, review.WalletID).Return(950.0, nil).Once()
+		daoMock.On("UpdateIdempotencyKey", mock.MatchedBy(func(r *dao.IdempotencyRecord) bool {
+			return r.Key == "key-review" && r.Path == "/wallets/transfer" && r.StatusCode == http.StatusOK &&
+				strings.Contains(r.Response, "transfer success")
+		})).Return(nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.ApproveTransactionReviewHandler(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "review approved")
+		daoMock.AssertExpectations(t)
+	})
+
+	t.Run("failed execution stores the error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/reviews/"+reviewID+"/approve", nil)
+		req.Header.Set("X-Actor-ID", "operator-1")
+		req = withRouteParam(req, "id", reviewID)
+
+		failed := *review
+		failed.Status = common.ReviewStatusFailed
+		logicMock.On("ApproveTransactionReview", mock.Anything, reviewID, "operator-1").Return(&failed, logic.ErrInsufficientBalance).Once()
+		daoMock.On("UpdateIdempotencyKey", mock.MatchedBy(func(r *dao.IdempotencyRecord) bool {
+			return r.Key == "key-review" && r.StatusCode == http.StatusBadRequest
+		})).Return(nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.ApproveTransactionReviewHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		daoMock.AssertExpectations(t)
+	})
+
+	t.Run("review not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/reviews/"+reviewID+"/approve", nil)
+		req.Header.Set("X-Actor-ID", "operator-1")
+		req = withRouteParam(req, "id", reviewID)
+
+		logicMock.On("ApproveTransactionReview", mock.Anything, reviewID, "operator-1").Return(nil, logic.ErrReviewNotFound).Once()
+
+		w := httptest.NewRecorder()
+		svc.ApproveTransactionReviewHandler(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestRejectTransactionReviewHandlerStoresOutcome(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	reviewID := "20000000-0000-0000-0000-000000000002"
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/reviews/"+reviewID+"/reject", nil)
+	req.Header.Set("X-Actor-ID", "operator-1")
+	req = withRouteParam(req, "id", reviewID)
+
+	logicMock.On("RejectTransactionReview", mock.Anything, reviewID, "operator-1").Return(&dao.TransactionReview{
+		ID:             reviewID,
+		Type:           common.TransactionTypeWithdraw,
+		Status:         common.ReviewStatusRejected,
+		IdempotencyKey: "key-rejected",
+	}, nil).Once()
+	daoMock.On("UpdateIdempotencyKey", mock.MatchedBy(func(r *dao.IdempotencyRecord) bool {
+		return r.Key == "key-rejected" && r.StatusCode == http.StatusForbidden && strings.Contains(r.Response, "rejected by review")
+	})).Return(nil).Once()
+
+	w := httptest.NewRecorder()
+	svc.RejectTransactionReviewHandler(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	daoMock.AssertExpectations(t)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+// CreateWalletHandler opens a standard or savings wallet for an existing user.
+func (s *WalletService) CreateWalletHandler(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "id")
+	if userID == "" || !isUUID(w, userID, "user_id") {
+		return
+	}
+
+	var req dto.CreateWalletRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+
+	wallet, err := s.Impl.CreateWallet(r.Context(), userID, req.Type, req.InterestRate)
+	if err != nil {
+		s.logger.WithError(err).Error("Create wallet failed")
+		switch err {
+		case logic.ErrInvalidWalletType, logic.ErrInvalidInterestRate:
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		case logic.ErrUserNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrUserNotFound, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, "Create wallet failed")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusCreated, dto.GenericResponse[*dao.Wallet]{
+		Status: "success",
+		Data:   wallet,
+	})
+}
+
+// InterestReportHandler returns the daily interest accruals of a savings wallet between the from
+// and to dates (YYYY-MM-DD, inclusive), by default the current month to date.
+func (s *WalletService) InterestReportHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+	if !s.authorizeWallet(w, r, walletID, logic.AccessView, 0) {
+		return
+	}
+
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := now
+	for _, param := range []struct {
+		name string
+		out  *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		raw := r.URL.Query().Get(param.name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid "+param.name+", expected YYYY-MM-DD")
+			return
+		}
+		*param.out = parsed
+	}
+
+	report, err := s.Impl.GetInterestReport(r.Context(), walletID, from, to)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to build interest report")
+		switch err {
+		case logic.ErrInvalidInterestPeriod, logic.ErrNoInterest:
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		case logic.ErrWalletNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, "Failed to build interest report")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*logic.InterestReport]{
+		Status: "success",
+		Data:   report,
+	})
+}
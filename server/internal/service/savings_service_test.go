@@ -0,0 +1,71 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const savingsUserID = "00000000-0000-0000-0000-000000000001"
+
+func TestCreateWalletHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	create := func(userID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/users/"+userID+"/wallets", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		svc.CreateWalletHandler(w, withRouteParam(req, "id", userID))
+		return w
+	}
+
+	rate := 0.03
+	logicMock.On("CreateWallet", mock.Anything, savingsUserID, "savings", &rate).
+		Return(&dao.Wallet{ID: payerWalletID, UserID: savingsUserID, Type: "savings", InterestRate: &rate, Status: "active"}, nil).Once()
+	w := create(savingsUserID, `{"type": "savings", "interest_rate": 0.03}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"type": "savings"`)
+	assert.Contains(t, w.Body.String(), `"interest_rate": 0.03`)
+
+	logicMock.On("CreateWallet", mock.Anything, savingsUserID, "checking", (*float64)(nil)).
+		Return(nil, logic.ErrInvalidWalletType).Once()
+	assert.Equal(t, http.StatusBadRequest, create(savingsUserID, `{"type": "checking"}`).Code)
+
+	logicMock.On("CreateWallet", mock.Anything, savingsUserID, "savings", (*float64)(nil)).
+		Return(nil, logic.ErrUserNotFound).Once()
+	assert.Equal(t, http.StatusNotFound, create(savingsUserID, `{"type": "savings"}`).Code)
+
+	assert.Equal(t, http.StatusBadRequest, create("alice", `{"type": "savings"}`).Code)
+	logicMock.AssertExpectations(t)
+}
+
+func TestInterestReportHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	report := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/wallets/"+payerWalletID+"/interest"+query, nil)
+		w := httptest.NewRecorder()
+		svc.InterestReportHandler(w, withRouteParam(req, "id", payerWalletID))
+		return w
+	}
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	logicMock.On("GetInterestReport", mock.Anything, payerWalletID, from, to).Return(&logic.InterestReport{
+		WalletID: payerWalletID, AnnualRate: 0.02, From: "2026-03-01", To: "2026-03-31",
+		Accrued: "1.6986301355", Posted: "1.6986301355", Unposted: "0.0000000000", Days: []dao.InterestAccrual{},
+	}, nil).Once()
+	w := report("?from=2026-03-01&to=2026-03-31")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"accrued": "1.6986301355"`)
+
+	logicMock.On("GetInterestReport", mock.Anything, payerWalletID, from, to).Return(nil, logic.ErrNoInterest).Once()
+	assert.Equal(t, http.StatusBadRequest, report("?from=2026-03-01&to=2026-03-31").Code)
+
+	assert.Equal(t, http.StatusBadRequest, report("?from=03/01/2026").Code)
+	logicMock.AssertExpectations(t)
+}
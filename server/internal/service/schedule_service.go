@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+// CreateScheduledTransferHandler schedules a recurring transfer out of the wallet in the path.
+func (s *WalletService) CreateScheduledTransferHandler(w http.ResponseWriter, r *http.Request) {
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	if idempotencyKey == "" {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Missing Idempotency-Key")
+		return
+	}
+
+	if record, found := s.Dao.CheckIdempotencyKey(idempotencyKey, r.Method, r.URL.Path); found {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(record.StatusCode)
+		_, _ = w.Write([]byte(record.Response))
+		return
+	}
+
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+
+	var req dto.ScheduledTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+
+	if !isUUID(w, req.ToWalletID, "to_wallet_id") {
+		return
+	}
+
+	if req.Amount <= 0.1 {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Amount must be more than 0.1")
+		return
+	}
+	if !s.authorizeWallet(w, r, walletID, logic.AccessSpend, req.Amount) {
+		return
+	}
+
+	scheduleReq := logic.ScheduleRequest{
+		FromWalletID: walletID,
+		ToWalletID:   req.ToWalletID,
+		Amount:       req.Amount,
+		Recurrence:   req.Recurrence,
+		EndAt:        req.EndAt,
+		CreatedBy:    common.GetActorID(r),
+	}
+	if req.StartAt != nil {
+		scheduleReq.StartAt = *req.StartAt
+	}
+
+	schedule, err := s.Impl.CreateScheduledTransfer(r.Context(), scheduleReq)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to create scheduled transfer")
+		switch {
+		case errors.Is(err, logic.ErrInvalidRecurrence), errors.Is(err, logic.ErrInvalidSchedule):
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		case errors.Is(err, logic.ErrScheduleRequiresApproval):
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		case errors.Is(err, logic.ErrWalletNotFound):
+			common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
+		case errors.Is(err, logic.ErrWalletFrozen):
+			common.WriteError(w, http.StatusForbidden, common.ErrWalletFrozen, err.Error())
+		case errors.Is(err, logic.ErrPocketWallet):
+			common.WriteError(w, http.StatusBadRequest, common.ErrPocketMovement, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to create scheduled transfer")
+		}
+		return
+	}
+
+	s.writeIdempotent(w, r, idempotencyKey, http.StatusCreated, dto.GenericResponse[*dao.Schedule]{
+		Status: "success",
+		Data:   schedule,
+	})
+}
+
+func (s *WalletService) ListScheduledTransfersHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+	if !s.authorizeWallet(w, r, walletID, logic.AccessView, 0) {
+		return
+	}
+
+	schedules, err := s.Impl.ListScheduledTransfers(r.Context(), walletID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list scheduled transfers")
+		common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to list scheduled transfers")
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[[]dao.Schedule]{
+		Status: "success",
+		Data:   schedules,
+	})
+}
+
+// GetScheduledTransferHandler returns a schedule with its most recent runs.
+func (s *WalletService) GetScheduledTransferHandler(w http.ResponseWriter, r *http.Request) {
+	scheduleID := chi.URLParam(r, "id")
+	if scheduleID == "" || !isUUID(w, scheduleID, "schedule_id") {
+		return
+	}
+
+	schedule, err := s.Impl.GetScheduledTransfer(r.Context(), scheduleID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to fetch scheduled transfer")
+		switch err {
+		case logic.ErrScheduleNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrScheduleNotFound, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to fetch scheduled transfer")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*logic.ScheduleDetail]{
+		Status: "success",
+		Data:   schedule,
+	})
+}
+
+func (s *WalletService) PauseScheduledTransferHandler(w http.ResponseWriter, r *http.Request) {
+	s.changeScheduledTransfer(w, r, s.Impl.PauseScheduledTransfer)
+}
+
+func (s *WalletService) ResumeScheduledTransferHandler(w http.ResponseWriter, r *http.Request) {
+	s.changeScheduledTransfer(w, r, s.Impl.ResumeScheduledTransfer)
+}
+
+func (s *WalletService) CancelScheduledTransferHandler(w http.ResponseWriter, r *http.Request) {
+	s.changeScheduledTransfer(w, r, s.Impl.CancelScheduledTransfer)
+}
+
+func (s *WalletService) changeScheduledTransfer(w http.ResponseWriter, r *http.Request, change func(ctx context.Context, scheduleID, actor string) (*dao.Schedule, error)) {
+	scheduleID := chi.URLParam(r, "id")
+	if scheduleID == "" || !isUUID(w, scheduleID, "schedule_id") {
+		return
+	}
+
+	schedule, err := change(r.Context(), scheduleID, common.GetActorID(r))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to change scheduled transfer")
+		switch err {
+		case logic.ErrScheduleNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrScheduleNotFound, err.Error())
+		case logic.ErrScheduleStatusConflict:
+			common.WriteError(w, http.StatusConflict, common.ErrScheduleConflict, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to change scheduled transfer")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*dao.Schedule]{
+		Status: "success",
+		Data:   schedule,
+	})
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateScheduledTransferHandler(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	walletID := "10000000-0000-0000-0000-000000000000"
+	toWalletID := "20000000-0000-0000-0000-000000000000"
+	path := "/wallets/" + walletID + "/scheduled-transfers"
+	daoMock.On("CheckIdempotencyKey", mock.Anything, "POST", path).Return(nil, false)
+	daoMock.On("SaveIdempotencyKey", mock.Anything).Return(nil)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "key-schedule")
+		return withRouteParam(req, "id", walletID)
+	}
+	start := time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("created", func(t *testing.T) {
+		logicMock.On("CreateScheduledTransfer", mock.Anything, mock.MatchedBy(func(req logic.ScheduleRequest) bool {
+			return req.FromWalletID == walletID && req.ToWalletID == toWalletID && req.Amount == 50 &&
+				req.Recurrence == "0 9 1 * *" && req.StartAt.Equal(start)
+		})).Return(&dao.Schedule{ID: "sched-1", Status: "active", NextRunAt: &start}, nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.CreateScheduledTransferHandler(w, newRequest(fmt.Sprintf(
+			`{"to_wallet_id": %q, "amount": 50, "recurrence": "0 9 1 * *", "start_at": "2026-11-01T09:00:00Z"}`, toWalletID)))
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":"sched-1"`)
+		assert.Contains(t, w.Body.String(), `"next_run_at":"2026-11-01T09:00:00Z"`)
+	})
+
+	t.Run("invalid recurrence", func(t *testing.T) {
+		logicMock.On("CreateScheduledTransfer", mock.Anything, mock.MatchedBy(func(req logic.ScheduleRequest) bool {
+			return req.Recurrence == "sometimes"
+		})).Return(nil, fmt.Errorf("%w: cron expressions have 5 fields, got 1", logic.ErrInvalidRecurrence)).Once()
+
+		w := httptest.NewRecorder()
+		svc.CreateScheduledTransferHandler(w, newRequest(fmt.Sprintf(
+			`{"to_wallet_id": %q, "amount": 50, "recurrence": "sometimes"}`, toWalletID)))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "5 fields")
+	})
+
+	t.Run("missing idempotency key", func(t *testing.T) {
+		req := newRequest(`{}`)
+		req.Header.Del("Idempotency-Key")
+
+		w := httptest.NewRecorder()
+		svc.CreateScheduledTransferHandler(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestChangeScheduledTransferHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	scheduleID := "30000000-0000-0000-0000-000000000000"
+
+	newRequest := func(action string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/scheduled-transfers/"+scheduleID+"/"+action, nil)
+		req.Header.Set("X-Actor-ID", "user-1")
+		return withRouteParam(req, "id", scheduleID)
+	}
+
+	t.Run("paused", func(t *testing.T) {
+		logicMock.On("PauseScheduledTransfer", mock.Anything, scheduleID, "user-1").
+			Return(&dao.Schedule{ID: scheduleID, Status: "paused"}, nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.PauseScheduledTransferHandler(w, newRequest("pause"))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"status": "paused"`)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		logicMock.On("ResumeScheduledTransfer", mock.Anything, scheduleID, "user-1").
+			Return(nil, logic.ErrScheduleStatusConflict).Once()
+
+		w := httptest.NewRecorder()
+		svc.ResumeScheduledTransferHandler(w, newRequest("resume"))
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		logicMock.On("CancelScheduledTransfer", mock.Anything, scheduleID, "user-1").
+			Return(nil, logic.ErrScheduleNotFound).Once()
+
+		w := httptest.NewRecorder()
+		svc.CancelScheduledTransferHandler(w, newRequest("cancel"))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
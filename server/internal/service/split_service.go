@@ -0,0 +1,79 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+// SplitPaymentHandler pays several wallets out of one in a single atomic posting.
+func (s *WalletService) SplitPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	if idempotencyKey == "" {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Missing Idempotency-Key")
+		return
+	}
+
+	if record, found := s.Dao.CheckIdempotencyKey(idempotencyKey, r.Method, r.URL.Path); found {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(record.StatusCode)
+		_, _ = w.Write([]byte(record.Response))
+		return
+	}
+
+	var req dto.SplitPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+	if !isUUID(w, req.FromWalletID, "from_wallet_id") {
+		return
+	}
+	shares := make([]logic.SplitShare, len(req.Splits))
+	total := req.Amount
+	for i, split := range req.Splits {
+		if !isUUID(w, split.ToWalletID, "to_wallet_id") {
+			return
+		}
+		shares[i] = logic.SplitShare{ToWalletID: split.ToWalletID, Amount: split.Amount, Percent: split.Percent}
+		if req.Amount == 0 && split.Amount != nil {
+			total += *split.Amount
+		}
+	}
+	if !s.authorizeWallet(w, r, req.FromWalletID, logic.AccessSpend, total) {
+		return
+	}
+
+	result, err := s.Impl.SplitPayment(r.Context(), logic.SplitPaymentRequest{
+		FromWalletID: req.FromWalletID,
+		Amount:       req.Amount,
+		Shares:       shares,
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("Split payment failed")
+		switch {
+		case errors.Is(err, logic.ErrInvalidSplit), err == logic.ErrSplitRequiresApproval:
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		case err == logic.ErrSplitNeedsReview:
+			common.WriteError(w, http.StatusForbidden, common.ErrTransactionDenied, err.Error())
+		case err == logic.ErrSanctionsMatch:
+			common.WriteError(w, http.StatusForbidden, common.ErrSanctionsMatch, err.Error())
+		default:
+			status, code, message := transferError(err)
+			if code == common.ErrUnknown {
+				message = "Split payment failed"
+			}
+			common.WriteError(w, status, code, message)
+		}
+		return
+	}
+
+	s.writeIdempotent(w, r, idempotencyKey, http.StatusOK, dto.GenericResponse[*logic.SplitPaymentResult]{
+		Status: "success",
+		Data:   result,
+	})
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const thirdWalletID = "10000000-0000-0000-0000-000000000003"
+
+func TestSplitPaymentHandler(t *testing.T) {
+	split := func(svc *WalletService, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/payments/split", strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "key-split")
+		req.Header.Set("X-Actor-ID", memberUserID)
+		w := httptest.NewRecorder()
+		svc.SplitPaymentHandler(w, req)
+		return w
+	}
+	body := `{"from_wallet_id": "` + payerWalletID + `", "splits": [{"to_wallet_id": "` + sellerWalletID + `", "amount": 30},
+		{"to_wallet_id": "` + thirdWalletID + `", "amount": 20}]}`
+
+	t.Run("paid", func(t *testing.T) {
+		svc, logicMock, daoMock := setupTestService()
+		daoMock.On("CheckIdempotencyKey", "key-split", "POST", "/payments/split").Return(nil, false).Once()
+		daoMock.On("SaveIdempotencyKey", mock.Anything).Return(nil).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessSpend, 50.0).Return(nil).Once()
+		logicMock.On("SplitPayment", mock.Anything, mock.MatchedBy(func(req logic.SplitPaymentRequest) bool {
+			return req.FromWalletID == payerWalletID && len(req.Shares) == 2 &&
+				req.Shares[0].ToWalletID == sellerWalletID && *req.Shares[0].Amount == 30 && req.Shares[1].Percent == nil
+		})).Return(&logic.SplitPaymentResult{GroupID: "group-1", FromWalletID: payerWalletID, Amount: 50}, nil).Once()
+
+		w := split(svc, body)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"group_id":"group-1"`)
+		logicMock.AssertExpectations(t)
+	})
+
+	t.Run("invalid split", func(t *testing.T) {
+		svc, logicMock, daoMock := setupTestService()
+		daoMock.On("CheckIdempotencyKey", "key-split", "POST", "/payments/split").Return(nil, false).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessSpend, 50.0).Return(nil).Once()
+		logicMock.On("SplitPayment", mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("%w: percents add up to 90, not 100", logic.ErrInvalidSplit)).Once()
+
+		w := split(svc, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "percents add up to 90")
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		svc, logicMock, daoMock := setupTestService()
+		daoMock.On("CheckIdempotencyKey", "key-split", "POST", "/payments/split").Return(nil, false).Once()
+		logicMock.On("AuthorizeWallet", mock.Anything, payerWalletID, memberUserID, logic.AccessSpend, 50.0).Return(nil).Once()
+		logicMock.On("SplitPayment", mock.Anything, mock.Anything).Return(nil, logic.ErrInsufficientBalance).Once()
+
+		w := split(svc, body)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":1002`)
+	})
+
+	t.Run("invalid recipient", func(t *testing.T) {
+		svc, logicMock, daoMock := setupTestService()
+		daoMock.On("CheckIdempotencyKey", "key-split", "POST", "/payments/split").Return(nil, false).Once()
+
+		w := split(svc, `{"from_wallet_id": "`+payerWalletID+`", "splits": [{"to_wallet_id": "nope", "amount": 5}]}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		logicMock.AssertNotCalled(t, "SplitPayment", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/statement"
+)
+
+func (s *WalletService) ListStatementsHandler(w http.ResponseWriter, r *http.Request) {
+	walletID := chi.URLParam(r, "id")
+	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
+		return
+	}
+	if !s.authorizeWallet(w, r, walletID, logic.AccessView, 0) {
+		return
+	}
+
+	statements, err := s.Impl.ListStatements(r.Context(), walletID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to list statements")
+		switch err {
+		case logic.ErrWalletNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to list statements")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[[]dao.Statement]{
+		Status: "success",
+		Data:   statements,
+	})
+}
+
+// GetStatementHandler returns a stored statement as JSON, or as a printable HTML document with ?format=html.
+func (s *WalletService) GetStatementHandler(w http.ResponseWriter, r *http.Request) {
+	statementID := chi.URLParam(r, "id")
+	if statementID == "" || !isUUID(w, statementID, "statement_id") {
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "html" {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid format, expected json or html")
+		return
+	}
+
+	doc, err := s.Impl.GetStatement(r.Context(), statementID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to fetch statement")
+		switch err {
+		case logic.ErrStatementNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrStatementNotFound, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to fetch statement")
+		}
+		return
+	}
+
+	if format != "html" {
+		common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*statement.Statement]{
+			Status: "success",
+			Data:   doc,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q",
+		fmt.Sprintf("statement-%s-%s.html", doc.WalletID, doc.PeriodStart.Format("2006-01"))))
+	w.WriteHeader(http.StatusOK)
+	if err := statement.RenderHTML(w, doc); err != nil {
+		s.logger.WithError(err).Errorf("Failed to render statement %s", statementID)
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/statement"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListStatementsHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	walletID := "10000000-0000-0000-0000-000000000001"
+
+	get := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/wallets/"+walletID+"/statements", nil)
+		svc.ListStatementsHandler(w, withRouteParam(req, "id", walletID))
+		return w
+	}
+
+	logicMock.On("ListStatements", mock.Anything, walletID).Return([]dao.Statement{{ID: "stmt-1", WalletID: walletID, ClosingBalance: 12.5}}, nil).Once()
+	w := get()
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"closing_balance": 12.5`)
+	assert.NotContains(t, w.Body.String(), "document")
+
+	logicMock.On("ListStatements", mock.Anything, walletID).Return(nil, logic.ErrWalletNotFound).Once()
+	assert.Equal(t, http.StatusNotFound, get().Code)
+}
+
+func TestGetStatementHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	statementID := "20000000-0000-0000-0000-000000000001"
+	start, end := statement.MonthPeriod(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	doc := statement.Build(statementID, "wallet-1", start, end, 10, nil)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/statements/"+statementID+query, nil)
+		svc.GetStatementHandler(w, withRouteParam(req, "id", statementID))
+		return w
+	}
+
+	t.Run("json", func(t *testing.T) {
+		logicMock.On("GetStatement", mock.Anything, statementID).Return(doc, nil).Once()
+		w := get("")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `"opening_balance": 10`)
+	})
+
+	t.Run("html", func(t *testing.T) {
+		logicMock.On("GetStatement", mock.Anything, statementID).Return(doc, nil).Once()
+		w := get("?format=html")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `inline; filename="statement-wallet-1-2026-03.html"`, w.Header().Get("Content-Disposition"))
+		assert.True(t, strings.HasPrefix(w.Body.String(), "<!DOCTYPE html>"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get("?format=pdf").Code)
+
+		logicMock.On("GetStatement", mock.Anything, statementID).Return(nil, logic.ErrStatementNotFound).Once()
+		assert.Equal(t, http.StatusNotFound, get("").Code)
+
+		logicMock.On("GetStatement", mock.Anything, statementID).Return(nil, errors.New("db down")).Once()
+		assert.Equal(t, http.StatusInternalServerError, get("").Code)
+	})
+}
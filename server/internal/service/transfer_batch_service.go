@@ -0,0 +1,177 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+// maxBatchSize bounds uploaded batch bodies.
+const maxBatchSize = 1 << 20
+
+// CreateTransferBatchHandler accepts a batch of transfers out of one wallet as JSON or, with a
+// text/csv body, as CSV rows of to_wallet_id, amount and an optional reference. The batch is
+// executed in the background; its status is polled with GetTransferBatchHandler.
+func (s *WalletService) CreateTransferBatchHandler(w http.ResponseWriter, r *http.Request) {
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	if idempotencyKey == "" {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Missing Idempotency-Key")
+		return
+	}
+
+	if record, found := s.Dao.CheckIdempotencyKey(idempotencyKey, r.Method, r.URL.Path); found {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(record.StatusCode)
+		_, _ = w.Write([]byte(record.Response))
+		return
+	}
+
+	var req dto.TransferBatchRequest
+	body := http.MaxBytesReader(w, r.Body, maxBatchSize)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+		items, err := parseBatchCSV(body)
+		if err != nil {
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid CSV: "+err.Error())
+			return
+		}
+		req.FromWalletID = r.URL.Query().Get("from_wallet_id")
+		req.Mode = r.URL.Query().Get("mode")
+		req.Items = items
+	} else if err := json.NewDecoder(body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+
+	if !isUUID(w, req.FromWalletID, "from_wallet_id") {
+		return
+	}
+
+	batchReq := logic.TransferBatchRequest{
+		FromWalletID:   req.FromWalletID,
+		Mode:           req.Mode,
+		Items:          make([]logic.TransferBatchItemRequest, len(req.Items)),
+		IdempotencyKey: idempotencyKey,
+		CreatedBy:      common.GetActorID(r),
+	}
+	var total float64
+	for i, item := range req.Items {
+		if !isUUID(w, item.ToWalletID, fmt.Sprintf("to_wallet_id in item %d", i+1)) {
+			return
+		}
+		if item.Amount <= 0.1 {
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, fmt.Sprintf("Amount in item %d must be more than 0.1", i+1))
+			return
+		}
+		batchReq.Items[i] = logic.TransferBatchItemRequest{
+			ToWalletID: item.ToWalletID,
+			Amount:     item.Amount,
+			Reference:  item.Reference,
+		}
+		total += item.Amount
+	}
+	if !s.authorizeWallet(w, r, req.FromWalletID, logic.AccessSpend, total) {
+		return
+	}
+
+	batch, err := s.Impl.CreateTransferBatch(r.Context(), batchReq)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to create transfer batch")
+		switch {
+		case errors.Is(err, logic.ErrInvalidTransferBatch):
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		case errors.Is(err, logic.ErrWalletNotFound):
+			common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
+		case errors.Is(err, logic.ErrWalletFrozen):
+			common.WriteError(w, http.StatusForbidden, common.ErrWalletFrozen, err.Error())
+		case errors.Is(err, logic.ErrPocketWallet):
+			common.WriteError(w, http.StatusBadRequest, common.ErrPocketMovement, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to create transfer batch")
+		}
+		return
+	}
+
+	s.writeIdempotent(w, r, idempotencyKey, http.StatusAccepted, dto.GenericResponse[*logic.TransferBatchResult]{
+		Status: "success",
+		Data:   batch,
+	})
+}
+
+func (s *WalletService) GetTransferBatchHandler(w http.ResponseWriter, r *http.Request) {
+	batchID := chi.URLParam(r, "id")
+	if batchID == "" || !isUUID(w, batchID, "batch_id") {
+		return
+	}
+
+	batch, err := s.Impl.GetTransferBatch(r.Context(), batchID)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to fetch transfer batch")
+		switch err {
+		case logic.ErrTransferBatchNotFound:
+			common.WriteError(w, http.StatusNotFound, common.ErrBatchNotFound, err.Error())
+		default:
+			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to fetch transfer batch")
+		}
+		return
+	}
+
+	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[*logic.TransferBatchResult]{
+		Status: "success",
+		Data:   batch,
+	})
+}
+
+// parseBatchCSV reads batch items from CSV with a header row naming the to_wallet_id, amount
+// and optional reference columns.
+func parseBatchCSV(r io.Reader) ([]dto.TransferBatchItem, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("missing header row")
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	toCol, okTo := columns["to_wallet_id"]
+	amountCol, okAmount := columns["amount"]
+	if !okTo || !okAmount {
+		return nil, fmt.Errorf("header must name to_wallet_id and amount columns")
+	}
+	refCol, hasRef := columns["reference"]
+
+	var items []dto.TransferBatchItem
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		amount, err := strconv.ParseFloat(strings.TrimSpace(record[amountCol]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid amount %q", line, record[amountCol])
+		}
+		item := dto.TransferBatchItem{ToWalletID: strings.TrimSpace(record[toCol]), Amount: amount}
+		if hasRef {
+			item.Reference = strings.TrimSpace(record[refCol])
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateTransferBatchHandler(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	fromWalletID := "10000000-0000-0000-0000-000000000000"
+	toWalletID := "20000000-0000-0000-0000-000000000000"
+	daoMock.On("CheckIdempotencyKey", mock.Anything, "POST", "/wallets/transfer/batch").Return(nil, false)
+	daoMock.On("SaveIdempotencyKey", mock.Anything).Return(nil)
+
+	newRequest := func(url, contentType, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		req.Header.Set("Idempotency-Key", "key-batch")
+		req.Header.Set("Content-Type", contentType)
+		return req
+	}
+	accepted := &logic.TransferBatchResult{TransferBatch: dao.TransferBatch{ID: "batch-1", Status: common.BatchStatusPending}}
+
+	t.Run("json", func(t *testing.T) {
+		logicMock.On("CreateTransferBatch", mock.Anything, mock.MatchedBy(func(req logic.TransferBatchRequest) bool {
+			return req.FromWalletID == fromWalletID && req.Mode == "all_or_nothing" && req.IdempotencyKey == "key-batch" &&
+				len(req.Items) == 1 && req.Items[0].ToWalletID == toWalletID && req.Items[0].Amount == 12.5
+		})).Return(accepted, nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.CreateTransferBatchHandler(w, newRequest("/wallets/transfer/batch", "application/json",
+			`{"from_wallet_id": "`+fromWalletID+`", "mode": "all_or_nothing", "items": [{"to_wallet_id": "`+toWalletID+`", "amount": 12.5}]}`))
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Contains(t, w.Body.String(), `"id":"batch-1"`)
+		assert.Contains(t, w.Body.String(), `"status":"pending"`)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		logicMock.On("CreateTransferBatch", mock.Anything, mock.MatchedBy(func(req logic.TransferBatchRequest) bool {
+			return req.Mode == "best_effort" && len(req.Items) == 2 &&
+				req.Items[0].Reference == "march salary" && req.Items[1].Amount == 99.99 && req.Items[1].Reference == ""
+		})).Return(accepted, nil).Once()
+
+		csv := "to_wallet_id,amount,reference\n" +
+			toWalletID + ",1500,march salary\n" +
+			toWalletID + ", 99.99,\n"
+		w := httptest.NewRecorder()
+		svc.CreateTransferBatchHandler(w, newRequest("/wallets/transfer/batch?from_wallet_id="+fromWalletID+"&mode=best_effort", "text/csv", csv))
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	})
+
+	t.Run("csv without amount column", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.CreateTransferBatchHandler(w, newRequest("/wallets/transfer/batch?from_wallet_id="+fromWalletID, "text/csv",
+			"to_wallet_id\n"+toWalletID+"\n"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "amount")
+	})
+
+	t.Run("invalid recipient", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc.CreateTransferBatchHandler(w, newRequest("/wallets/transfer/batch", "application/json",
+			`{"from_wallet_id": "`+fromWalletID+`", "mode": "best_effort", "items": [{"to_wallet_id": "bob", "amount": 5}]}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "item 1")
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		logicMock.On("CreateTransferBatch", mock.Anything, mock.MatchedBy(func(req logic.TransferBatchRequest) bool {
+			return req.Mode == ""
+		})).Return(nil, logic.ErrInvalidTransferBatch).Once()
+
+		w := httptest.NewRecorder()
+		svc.CreateTransferBatchHandler(w, newRequest("/wallets/transfer/batch", "application/json",
+			`{"from_wallet_id": "`+fromWalletID+`", "items": [{"to_wallet_id": "`+toWalletID+`", "amount": 5}]}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetTransferBatchHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	batchID := "30000000-0000-0000-0000-000000000000"
+
+	logicMock.On("GetTransferBatch", mock.Anything, batchID).Return(&logic.TransferBatchResult{
+		TransferBatch: dao.TransferBatch{ID: batchID, Status: common.BatchStatusPartial, SucceededCount: 1, FailedCount: 1},
+	}, nil).Once()
+
+	w := httptest.NewRecorder()
+	svc.GetTransferBatchHandler(w, withRouteParam(httptest.NewRequest(http.MethodGet, "/wallets/transfer/batch/"+batchID, nil), "id", batchID))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status": "partially_completed"`)
+
+	logicMock.On("GetTransferBatch", mock.Anything, batchID).Return(nil, logic.ErrTransferBatchNotFound).Once()
+	w = httptest.NewRecorder()
+	svc.GetTransferBatchHandler(w, withRouteParam(httptest.NewRequest(http.MethodGet, "/wallets/transfer/batch/"+batchID, nil), "id", batchID))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
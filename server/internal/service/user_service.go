@@ -0,0 +1,44 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/logic"
+)
+
+func (s *WalletService) CreateUserHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
+		return
+	}
+
+	user, wallet, screening, err := s.Impl.CreateUser(r.Context(), req.Name, req.Email)
+	if err != nil {
+		s.logger.WithError(err).Error("Create user failed")
+		if err == logic.ErrInvalidUser {
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		} else {
+			common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, "Create user failed")
+		}
+		return
+	}
+
+	resp := dto.CreateUserResponse{
+		UserID:       user.ID,
+		WalletID:     wallet.ID,
+		WalletStatus: wallet.Status,
+		KycLevel:     user.KycLevel,
+	}
+	if screening != nil {
+		resp.ScreeningStatus = screening.Status
+	}
+
+	common.WriteJSON(w, http.StatusCreated, dto.GenericResponse[dto.CreateUserResponse]{
+		Status: "success",
+		Data:   resp,
+	})
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateUserHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+
+	t.Run("screened user created", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"Mallory Blackwood","email":"m@example.com"}`))
+
+		logicMock.On("CreateUser", mock.Anything, "Mallory Blackwood", "m@example.com").Return(
+			&dao.User{ID: "user-1", KycLevel: common.KycLevelUnverified},
+			&dao.Wallet{ID: "wallet-1", Status: common.WalletStatusFrozen},
+			&dao.UserScreening{Status: common.ScreeningStatusMatch},
+			nil,
+		).Once()
+
+		w := httptest.NewRecorder()
+		svc.CreateUserHandler(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"wallet_status": "frozen"`)
+		assert.Contains(t, w.Body.String(), `"screening_status": "match"`)
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"Alice"}`))
+
+		logicMock.On("CreateUser", mock.Anything, "Alice", "").Return(nil, nil, nil, logic.ErrInvalidUser).Once()
+
+		w := httptest.NewRecorder()
+		svc.CreateUserHandler(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestTransferHandlerSanctions(t *testing.T) {
+	svc, logicMock, daoMock := setupTestService()
+	body := `{"from_wallet_id":"10000000-0000-0000-0000-000000000001","to_wallet_id":"10000000-0000-0000-0000-000000000002","amount":50}`
+
+	req := httptest.NewRequest(http.MethodPost, "/wallets/transfer", strings.NewReader(body))
+	req.Header.Set("Idempotency-Key", "key-sanctions")
+
+	daoMock.On("CheckIdempotencyKey", "key-sanctions", "POST", "/wallets/transfer").Return(nil, false).Once()
+	logicMock.On("ScreenTransferParties", mock.Anything, "10000000-0000-0000-0000-000000000001", "10000000-0000-0000-0000-000000000002").
+		Return(logic.ErrSanctionsMatch).Once()
+
+	w := httptest.NewRecorder()
+	svc.TransferHandler(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	logicMock.AssertNotCalled(t, "Transfer", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -1,9 +1,11 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/sirupsen/logrus"
@@ -12,13 +14,32 @@ import (
 	"github.com/julkhong/walletapp/server/internal/config"
 	"github.com/julkhong/walletapp/server/internal/dao"
 	dto "github.com/julkhong/walletapp/server/internal/dto"
+	"github.com/julkhong/walletapp/server/internal/fees"
 	"github.com/julkhong/walletapp/server/internal/logic"
+	"github.com/julkhong/walletapp/server/internal/notify"
+	"github.com/julkhong/walletapp/server/internal/payout"
+	"github.com/julkhong/walletapp/server/internal/provider"
+	"github.com/julkhong/walletapp/server/internal/risk"
+	"github.com/julkhong/walletapp/server/internal/screening"
 )
 
+// notificationTimeout bounds one delivery to the notification webhook.
+const notificationTimeout = 5 * time.Second
+
 type WalletService struct {
 	logger *logrus.Logger
 	Dao    dao.WalletDaoInterface
 	Impl   logic.WalletImplInterface
+	// membershipRequired makes every wallet call name the member making it in X-Actor-ID.
+	membershipRequired bool
+	// startupConfig is the configuration the service was built with, recorded by RecordStartupConfig.
+	startupConfig []configSection
+}
+
+// configSection is one named part of the configuration recorded in the audit trail.
+type configSection struct {
+	key   string
+	value any
 }
 
 func NewWalletService(cfg *config.Config, logger *logrus.Logger) *WalletService {
@@ -27,8 +48,148 @@ func NewWalletService(cfg *config.Config, logger *logrus.Logger) *WalletService
 		logger.Error("failed to init wallet service", err)
 	}
 	impl := logic.NewWalletImpl(dao, logger)
+	// configuration is recorded once the service runs, see RecordStartupConfig
+	var sections []configSection
+	record := func(key string, value any) {
+		sections = append(sections, configSection{key: key, value: value})
+	}
+
+	if cfg.RiskRulesFile != "" {
+		rules, err := risk.LoadRules(cfg.RiskRulesFile)
+		if err != nil {
+			logger.WithError(err).Error("failed to load risk rules, screening disabled")
+		} else {
+			impl.SetScreener(risk.NewEngine(rules, dao, logger))
+			record("risk_rules", map[string]any{"file": cfg.RiskRulesFile, "rules": rules})
+		}
+	}
+
+	if cfg.FeeScheduleFile != "" {
+		rules, err := fees.LoadRules(cfg.FeeScheduleFile)
+		if err != nil {
+			logger.WithError(err).Error("failed to load fee schedule, fees disabled")
+		} else {
+			impl.SetFeeConfig(logic.FeeConfig{Schedule: fees.NewSchedule(rules), WalletID: cfg.FeeWalletID})
+			record("fee_schedule", map[string]any{
+				"file":      cfg.FeeScheduleFile,
+				"wallet_id": cfg.FeeWalletID,
+				"rules":     rules,
+			})
+		}
+	}
+
+	if cfg.EscrowWalletID != "" {
+		impl.SetEscrowConfig(logic.EscrowConfig{WalletID: cfg.EscrowWalletID, Timeout: cfg.EscrowTimeout})
+		record("escrow", map[string]any{
+			"wallet_id": cfg.EscrowWalletID,
+			"timeout":   cfg.EscrowTimeout.String(),
+		})
+	}
+
+	impl.SetPaymentRequestConfig(logic.PaymentRequestConfig{Expiry: cfg.PaymentRequestExpiry})
+	record("payment_requests", map[string]any{"expiry": cfg.PaymentRequestExpiry.String()})
+
+	if cfg.PaymentLinkSecret != "" {
+		impl.SetPaymentLinkConfig(logic.PaymentLinkConfig{
+			Secret:   cfg.PaymentLinkSecret,
+			Expiry:   cfg.PaymentLinkExpiry,
+			GUID:     cfg.PaymentLinkGUID,
+			Currency: cfg.PaymentLinkCurrency,
+			Country:  cfg.PaymentLinkCountry,
+			City:     cfg.PaymentLinkCity,
+		})
+		record("payment_links", map[string]any{
+			"expiry":   cfg.PaymentLinkExpiry.String(),
+			"guid":     cfg.PaymentLinkGUID,
+			"currency": cfg.PaymentLinkCurrency,
+			"country":  cfg.PaymentLinkCountry,
+			"city":     cfg.PaymentLinkCity,
+		})
+	}
+
+	if cfg.NotificationWebhookURL != "" {
+		impl.SetNotifier(notify.NewWebhook(cfg.NotificationWebhookURL, cfg.NotificationWebhookSecret, notificationTimeout))
+		record("notifications", map[string]any{
+			"webhook_url": cfg.NotificationWebhookURL,
+			"signed":      cfg.NotificationWebhookSecret != "",
+		})
+	} else {
+		impl.SetNotifier(notify.NewLogNotifier(logger))
+	}
+
+	impl.SetInterestConfig(logic.InterestConfig{DefaultRate: cfg.SavingsInterestRate, OverdraftRate: cfg.OverdraftInterestRate})
+	record("savings_interest", map[string]any{
+		"default_rate":   cfg.SavingsInterestRate,
+		"overdraft_rate": cfg.OverdraftInterestRate,
+	})
+
+	policy := logic.ApprovalPolicy{
+		TransferThreshold: cfg.ApprovalTransferThreshold,
+		TTL:               cfg.ApprovalTTL,
+	}
+	impl.SetApprovalPolicy(policy)
+	record("approval_policy", map[string]any{
+		"transfer_threshold": policy.TransferThreshold,
+		"ttl":                policy.TTL.String(),
+	})
+
+	payouts := logic.PayoutConfig{
+		Dir:      cfg.PayoutDir,
+		Currency: cfg.PayoutCurrency,
+		Debtor:   payout.Account{Name: cfg.PayoutDebtorName, IBAN: cfg.PayoutDebtorIBAN, BIC: cfg.PayoutDebtorBIC},
+	}
+	impl.SetPayoutConfig(payouts)
+	record("payouts", map[string]any{
+		"dir":         payouts.Dir,
+		"currency":    payouts.Currency,
+		"debtor_name": payouts.Debtor.Name,
+		"debtor_iban": payouts.Debtor.IBAN,
+		"debtor_bic":  payouts.Debtor.BIC,
+	})
+
+	providers := provider.NewRegistry()
+	if cfg.FakeProviderEnabled {
+		providers.Register(provider.NewFake(provider.FakeConfig{
+			Latency:     cfg.FakeProviderLatency,
+			SettleAfter: cfg.FakeProviderSettleAfter,
+			FailureRate: cfg.FakeProviderFailureRate,
+			Secret:      cfg.FakeProviderSecret,
+		}))
+		record("fake_provider", map[string]any{
+			"latency":      cfg.FakeProviderLatency.String(),
+			"settle_after": cfg.FakeProviderSettleAfter.String(),
+			"failure_rate": cfg.FakeProviderFailureRate,
+			"signed":       cfg.FakeProviderSecret != "",
+		})
+	}
+	impl.SetProviders(providers)
+
+	if cfg.SanctionsListDir != "" {
+		entries, err := screening.LoadLists(cfg.SanctionsListDir)
+		if err != nil {
+			logger.WithError(err).Error("failed to load sanctions lists, screening disabled")
+		} else {
+			impl.SetSanctionsChecker(screening.NewScreener(entries, cfg.SanctionsMatchThreshold, logger))
+			record("sanctions_lists", map[string]any{
+				"dir":             cfg.SanctionsListDir,
+				"entries":         len(entries),
+				"match_threshold": cfg.SanctionsMatchThreshold,
+			})
+		}
+	}
 
-	return &WalletService{Dao: dao, logger: logger, Impl: impl}
+	record("wallet_membership", map[string]any{"required": cfg.WalletMembershipRequired})
+
+	return &WalletService{Dao: dao, logger: logger, Impl: impl, membershipRequired: cfg.WalletMembershipRequired, startupConfig: sections}
+}
+
+// RecordStartupConfig records the configuration the service was built with in the audit trail so
+// changes between deploys are visible. Sections unchanged since they were last recorded are
+// skipped.
+func (s *WalletService) RecordStartupConfig(ctx context.Context) {
+	for _, section := range s.startupConfig {
+		s.Impl.RecordConfigChange(ctx, section.key, section.value)
+	}
 }
 
 func isUUID(w http.ResponseWriter, id, label string) bool {
@@ -38,6 +199,53 @@ func isUUID(w http.ResponseWriter, id, label string) bool {
 	}
 	return true
 }
+
+// writeIdempotent stores the response for the idempotency key and writes it out.
+func (s *WalletService) writeIdempotent(w http.ResponseWriter, r *http.Request, idempotencyKey string, statusCode int, resp any) {
+	respJSON, _ := json.Marshal(resp)
+
+	_ = s.Dao.SaveIdempotencyKey(&dao.IdempotencyRecord{
+		Key:        idempotencyKey,
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Response:   string(respJSON),
+		StatusCode: statusCode,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(respJSON)
+}
+
+// writeScreeningResult handles review and deny outcomes of risk screening. It returns false
+// when err is not a screening outcome.
+func (s *WalletService) writeScreeningResult(w http.ResponseWriter, r *http.Request, idempotencyKey, txType string, err error) bool {
+	var pending *logic.PendingReviewError
+	if errors.As(err, &pending) {
+		s.writeIdempotent(w, r, idempotencyKey, http.StatusAccepted, dto.GenericResponse[dto.PendingReviewResponse]{
+			Status: "pending",
+			Data: dto.PendingReviewResponse{
+				Message:  txType + " pending review",
+				ReviewID: pending.ReviewID,
+			},
+		})
+		s.setReviewRequest(pending.ReviewID, idempotencyKey, r.Method, r.URL.Path)
+		return true
+	}
+	if errors.Is(err, logic.ErrTransactionDenied) {
+		common.WriteError(w, http.StatusForbidden, common.ErrTransactionDenied, err.Error())
+		return true
+	}
+	return false
+}
+
+// setReviewRequest keeps the idempotency key on the review so its decision can replace the pending response.
+func (s *WalletService) setReviewRequest(reviewID, idempotencyKey, method, path string) {
+	if err := s.Dao.SetTransactionReviewRequest(reviewID, idempotencyKey, method, path); err != nil {
+		s.logger.WithError(err).Errorf("Failed to record the request of review %s", reviewID)
+	}
+}
+
 func (s *WalletService) DepositHandler(w http.ResponseWriter, r *http.Request) {
 	idempotencyKey := r.Header.Get("Idempotency-Key")
 	if idempotencyKey == "" {
@@ -62,12 +270,21 @@ func (s *WalletService) DepositHandler(w http.ResponseWriter, r *http.Request) {
 		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
 		return
 	}
+	if !s.authorizeWallet(w, r, walletID, logic.AccessSpend, 0) {
+		return
+	}
 
 	if err := s.Impl.Deposit(r.Context(), walletID, req.Amount); err != nil {
 		s.logger.WithError(err).Error("Deposit failed")
 		switch err {
 		case logic.ErrWalletNotFound:
 			common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
+		case logic.ErrWalletFrozen:
+			common.WriteError(w, http.StatusForbidden, common.ErrWalletFrozen, err.Error())
+		case logic.ErrKycBalanceLimitExceeded:
+			common.WriteError(w, http.StatusForbidden, common.ErrKycBalanceLimit, err.Error())
+		case logic.ErrPocketWallet:
+			common.WriteError(w, http.StatusBadRequest, common.ErrPocketMovement, err.Error())
 		default:
 			common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, "Deposit failed")
 		}
@@ -118,16 +335,25 @@ func (s *WalletService) WithdrawHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if req.Beneficiary != nil && req.Provider != nil {
+		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Use either beneficiary or provider, not both")
+		return
+	}
+	if !s.authorizeWallet(w, r, walletID, logic.AccessSpend, req.Amount) {
+		return
+	}
+	if req.Beneficiary != nil {
+		s.withdrawToBank(w, r, idempotencyKey, walletID, req)
+		return
+	}
+	if req.Provider != nil {
+		s.withdrawViaProvider(w, r, idempotencyKey, walletID, req)
+		return
+	}
+
 	if err := s.Impl.Withdraw(r.Context(), walletID, req.Amount); err != nil {
 		s.logger.WithError(err).Error("Withdraw failed")
-		switch err {
-		case logic.ErrWalletNotFound:
-			common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
-		case logic.ErrInsufficientBalance:
-			common.WriteError(w, http.StatusBadRequest, common.ErrInsufficientBalance, err.Error())
-		default:
-			common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, "Withdraw failed")
-		}
+		s.writeWithdrawError(w, r, idempotencyKey, err)
 		return
 	}
 
@@ -150,6 +376,27 @@ func (s *WalletService) WithdrawHandler(w http.ResponseWriter, r *http.Request)
 	_, _ = w.Write(respJSON)
 }
 
+// writeWithdrawError writes the response for a failed withdrawal.
+func (s *WalletService) writeWithdrawError(w http.ResponseWriter, r *http.Request, idempotencyKey string, err error) {
+	if s.writeScreeningResult(w, r, idempotencyKey, common.TransactionTypeWithdraw, err) {
+		return
+	}
+	switch err {
+	case logic.ErrWalletNotFound:
+		common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
+	case logic.ErrInsufficientBalance:
+		common.WriteError(w, http.StatusBadRequest, common.ErrInsufficientBalance, err.Error())
+	case logic.ErrWalletFrozen:
+		common.WriteError(w, http.StatusForbidden, common.ErrWalletFrozen, err.Error())
+	case logic.ErrKycWithdrawNotAllowed:
+		common.WriteError(w, http.StatusForbidden, common.ErrKycWithdrawDisabled, err.Error())
+	case logic.ErrPocketWallet:
+		common.WriteError(w, http.StatusBadRequest, common.ErrPocketMovement, err.Error())
+	default:
+		common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, "Withdraw failed")
+	}
+}
+
 func (s *WalletService) TransferHandler(w http.ResponseWriter, r *http.Request) {
 	idempotencyKey := r.Header.Get("Idempotency-Key")
 	if idempotencyKey == "" {
@@ -169,6 +416,10 @@ func (s *WalletService) TransferHandler(w http.ResponseWriter, r *http.Request)
 		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid request")
 		return
 	}
+	if req.LinkToken != "" {
+		s.payPaymentLink(w, r, idempotencyKey, req)
+		return
+	}
 
 	if !isUUID(w, req.FromWalletID, "from_wallet_id") || !isUUID(w, req.ToWalletID, "to_wallet_id") {
 		return
@@ -178,20 +429,69 @@ func (s *WalletService) TransferHandler(w http.ResponseWriter, r *http.Request)
 		common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Amount must be more than 0.1")
 		return
 	}
+	if !s.authorizeWallet(w, r, req.FromWalletID, logic.AccessSpend, req.Amount) {
+		return
+	}
 
-	if err := s.Impl.Transfer(r.Context(), req.FromWalletID, req.ToWalletID, req.Amount); err != nil {
-		s.logger.WithError(err).Error("Transfer failed")
+	if err := s.Impl.ScreenTransferParties(r.Context(), req.FromWalletID, req.ToWalletID); err != nil {
+		s.logger.WithError(err).Error("Transfer sanctions screening failed")
 		switch err {
+		case logic.ErrSanctionsMatch:
+			common.WriteError(w, http.StatusForbidden, common.ErrSanctionsMatch, err.Error())
 		case logic.ErrWalletNotFound:
 			common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
-		case logic.ErrInsufficientBalance:
-			common.WriteError(w, http.StatusBadRequest, common.ErrInsufficientBalance, err.Error())
 		default:
 			common.WriteError(w, http.StatusInternalServerError, common.ErrUnknown, "Transfer failed")
 		}
 		return
 	}
 
+	if s.Impl.RequiresApproval(req.Amount) {
+		actorID := common.GetActorID(r)
+		if actorID == "" {
+			common.WriteError(w, http.StatusUnauthorized, common.ErrUnauthorized, "Missing X-Actor-ID")
+			return
+		}
+		op, err := s.Impl.SubmitForApproval(r.Context(), logic.ApprovalRequest{
+			Type: common.TransactionTypeTransfer,
+			Payload: logic.TransferPayload{
+				FromWalletID: req.FromWalletID,
+				ToWalletID:   req.ToWalletID,
+				Amount:       req.Amount,
+			},
+			InitiatedBy:    actorID,
+			IdempotencyKey: idempotencyKey,
+			Method:         r.Method,
+			Path:           r.URL.Path,
+		})
+		if err != nil {
+			s.logger.WithError(err).Error("Failed to submit transfer for approval")
+			httpStatus, code, message := transferError(err)
+			common.WriteError(w, httpStatus, code, message)
+			return
+		}
+
+		s.writeIdempotent(w, r, idempotencyKey, http.StatusAccepted, dto.GenericResponse[dto.PendingApprovalResponse]{
+			Status: "pending",
+			Data: dto.PendingApprovalResponse{
+				Message:    "transfer pending approval",
+				ApprovalID: op.ID,
+			},
+		})
+		return
+	}
+
+	fee, err := s.Impl.Transfer(r.Context(), req.FromWalletID, req.ToWalletID, req.Amount)
+	if err != nil {
+		s.logger.WithError(err).Error("Transfer failed")
+		if s.writeScreeningResult(w, r, idempotencyKey, common.TransactionTypeTransfer, err) {
+			return
+		}
+		httpStatus, code, message := transferError(err)
+		common.WriteError(w, httpStatus, code, message)
+		return
+	}
+
 	balance, err := s.Impl.GetBalance(r.Context(), req.FromWalletID)
 	if err != nil {
 		s.logger.WithError(err).Warn("Failed to fetch updated sender balance")
@@ -203,6 +503,7 @@ func (s *WalletService) TransferHandler(w http.ResponseWriter, r *http.Request)
 			Message:  "transfer success",
 			WalletID: req.FromWalletID,
 			Balance:  balance,
+			Fee:      feeBreakdown(fee),
 		},
 	}
 	respJSON, _ := json.Marshal(resp)
@@ -220,18 +521,75 @@ func (s *WalletService) TransferHandler(w http.ResponseWriter, r *http.Request)
 	_, _ = w.Write(respJSON)
 }
 
+// transferError maps a Transfer error to its HTTP status, error code and message.
+func transferError(err error) (int, int, string) {
+	switch err {
+	case logic.ErrWalletNotFound:
+		return http.StatusNotFound, common.ErrWalletNotFound, err.Error()
+	case logic.ErrInsufficientBalance:
+		return http.StatusBadRequest, common.ErrInsufficientBalance, err.Error()
+	case logic.ErrWalletFrozen:
+		return http.StatusForbidden, common.ErrWalletFrozen, err.Error()
+	case logic.ErrKycTransferLimitExceeded:
+		return http.StatusForbidden, common.ErrKycTransferLimit, err.Error()
+	case logic.ErrTransactionDenied:
+		return http.StatusForbidden, common.ErrTransactionDenied, err.Error()
+	case logic.ErrPocketWallet:
+		return http.StatusBadRequest, common.ErrPocketMovement, err.Error()
+	default:
+		return http.StatusInternalServerError, common.ErrUnknown, "Transfer failed"
+	}
+}
+
 func (s *WalletService) BalanceHandler(w http.ResponseWriter, r *http.Request) {
 	walletID := chi.URLParam(r, "id")
 	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
 		return
 	}
+	if !s.authorizeWallet(w, r, walletID, logic.AccessView, 0) {
+		return
+	}
 
-	balance, err := s.Impl.GetBalance(r.Context(), walletID)
+	var asOf *time.Time
+	if raw := r.URL.Query().Get("as_of"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, "Invalid as_of, expected RFC3339")
+			return
+		}
+		asOf = &parsed
+	}
+
+	var (
+		balance float64
+		err     error
+	)
+	if asOf != nil {
+		balance, err = s.Impl.GetBalanceAsOf(r.Context(), walletID, *asOf)
+	} else {
+		balance, err = s.Impl.GetBalance(r.Context(), walletID)
+	}
+	resp := dto.BalanceResponse{
+		WalletID: walletID,
+		Balance:  balance,
+		AsOf:     asOf,
+	}
+	if err == nil && asOf == nil {
+		var creditLimit float64
+		if creditLimit, err = s.Impl.GetCreditLimit(r.Context(), walletID); err == nil {
+			availableCredit := logic.AvailableCredit(balance, creditLimit)
+			resp.CreditLimit = &creditLimit
+			resp.AvailableCredit = &availableCredit
+		}
+	}
 	if err != nil {
 		s.logger.WithError(err).Error("Balance fetch failed")
-		if err == logic.ErrWalletNotFound {
+		switch err {
+		case logic.ErrWalletNotFound:
 			common.WriteError(w, http.StatusNotFound, common.ErrWalletNotFound, err.Error())
-		} else {
+		case logic.ErrAsOfInFuture:
+			common.WriteError(w, http.StatusBadRequest, common.ErrInvalidRequest, err.Error())
+		default:
 			common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to get balance")
 		}
 		return
@@ -239,35 +597,6 @@ func (s *WalletService) BalanceHandler(w http.ResponseWriter, r *http.Request) {
 
 	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[dto.BalanceResponse]{
 		Status: "success",
-		Data: dto.BalanceResponse{
-			WalletID: walletID,
-			Balance:  balance,
-		},
-	})
-}
-
-func (s *WalletService) TransactionHistoryHandler(w http.ResponseWriter, r *http.Request) {
-	walletID := chi.URLParam(r, "id")
-	if walletID == "" || !isUUID(w, walletID, "wallet_id") {
-		return
-	}
-
-	query := r.URL.Query()
-	txType := query.Get("type")
-	start := query.Get("start")
-	end := query.Get("end")
-	limit, _ := strconv.Atoi(query.Get("limit"))
-	offset, _ := strconv.Atoi(query.Get("offset"))
-
-	txs, err := s.Impl.GetTransactionHistory(r.Context(), walletID, txType, start, end, limit, offset)
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to fetch transaction history")
-		common.WriteError(w, http.StatusInternalServerError, common.ErrDatabase, "Failed to fetch transaction history")
-		return
-	}
-
-	common.WriteJSON(w, http.StatusOK, dto.GenericResponse[[]dao.Transaction]{
-		Status: "success",
-		Data:   txs,
+		Data:   resp,
 	})
 }
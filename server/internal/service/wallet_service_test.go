@@ -6,10 +6,13 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	daoMocks "github.com/julkhong/walletapp/server/internal/dao/mocks"
+	"github.com/julkhong/walletapp/server/internal/logic"
 	logicMocks "github.com/julkhong/walletapp/server/internal/logic/mocks"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -19,8 +22,9 @@ func setupTestService() (*WalletService, *logicMocks.WalletImplInterface, *daoMo
 	daoMock := new(daoMocks.WalletDaoInterface)
 
 	return &WalletService{
-		Impl: logicMock,
-		Dao:  daoMock,
+		logger: logrus.New(),
+		Impl:   logicMock,
+		Dao:    daoMock,
 	}, logicMock, daoMock
 }
 
@@ -79,3 +83,63 @@ func TestDepositHandler(t *testing.T) {
 		})
 	})
 }
+
+func TestBalanceHandler(t *testing.T) {
+	svc, logicMock, _ := setupTestService()
+	walletID := "10000000-0000-0000-0000-000000000001"
+
+	t.Run("current balance", func(t *testing.T) {
+		req := withRouteParam(httptest.NewRequest(http.MethodGet, "/wallets/"+walletID+"/balance", nil), "id", walletID)
+		logicMock.On("GetBalance", mock.Anything, walletID).Return(150.0, nil).Once()
+		logicMock.On("GetCreditLimit", mock.Anything, walletID).Return(0.0, nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.BalanceHandler(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "as_of")
+		assert.Contains(t, w.Body.String(), `"credit_limit": 0`)
+	})
+
+	t.Run("overdrawn balance", func(t *testing.T) {
+		req := withRouteParam(httptest.NewRequest(http.MethodGet, "/wallets/"+walletID+"/balance", nil), "id", walletID)
+		logicMock.On("GetBalance", mock.Anything, walletID).Return(-120.0, nil).Once()
+		logicMock.On("GetCreditLimit", mock.Anything, walletID).Return(500.0, nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.BalanceHandler(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"balance": -120`)
+		assert.Contains(t, w.Body.String(), `"credit_limit": 500`)
+		assert.Contains(t, w.Body.String(), `"available_credit": 380`)
+	})
+
+	t.Run("balance as of", func(t *testing.T) {
+		req := withRouteParam(httptest.NewRequest(http.MethodGet, "/wallets/"+walletID+"/balance?as_of=2026-03-03T00:00:00Z", nil), "id", walletID)
+		asOf := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+		logicMock.On("GetBalanceAsOf", mock.Anything, walletID, asOf).Return(80.0, nil).Once()
+
+		w := httptest.NewRecorder()
+		svc.BalanceHandler(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"as_of": "2026-03-03T00:00:00Z"`)
+		assert.Contains(t, w.Body.String(), `"balance": 80`)
+		assert.NotContains(t, w.Body.String(), "credit_limit")
+	})
+
+	t.Run("invalid as_of", func(t *testing.T) {
+		req := withRouteParam(httptest.NewRequest(http.MethodGet, "/wallets/"+walletID+"/balance?as_of=march", nil), "id", walletID)
+
+		w := httptest.NewRecorder()
+		svc.BalanceHandler(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("as_of in the future", func(t *testing.T) {
+		req := withRouteParam(httptest.NewRequest(http.MethodGet, "/wallets/"+walletID+"/balance?as_of=2999-01-01T00:00:00Z", nil), "id", walletID)
+		logicMock.On("GetBalanceAsOf", mock.Anything, walletID, mock.Anything).Return(0.0, logic.ErrAsOfInFuture).Once()
+
+		w := httptest.NewRecorder()
+		svc.BalanceHandler(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,33 @@
+package statement
+
+import (
+	"embed"
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+)
+
+//go:embed templates/statement.html
+var templates embed.FS
+
+var htmlTemplate = template.Must(template.New("statement.html").Funcs(template.FuncMap{
+	"money": func(amount float64) string {
+		return strconv.FormatFloat(amount, 'f', 4, 64)
+	},
+	"date": func(t time.Time) string {
+		return t.UTC().Format("02 Jan 2006")
+	},
+	"datetime": func(t time.Time) string {
+		return t.UTC().Format("02 Jan 2006 15:04 MST")
+	},
+	// periods end exclusively at midnight, the printed period ends the day before
+	"lastDay": func(t time.Time) time.Time {
+		return t.Add(-time.Nanosecond)
+	},
+}).ParseFS(templates, "templates/statement.html"))
+
+// RenderHTML writes the statement as a printable HTML document.
+func RenderHTML(w io.Writer, s *Statement) error {
+	return htmlTemplate.Execute(w, s)
+}
@@ -0,0 +1,92 @@
+package statement
+
+import (
+	"time"
+
+	"github.com/julkhong/walletapp/server/internal/common"
+	"github.com/julkhong/walletapp/server/internal/dao"
+)
+
+// Statement is a wallet's activity over a period. Lines are in posting order and carry the
+// running balance from the opening balance, so closing equals opening plus every line.
+type Statement struct {
+	ID             string               `json:"id"`
+	WalletID       string               `json:"wallet_id"`
+	PeriodStart    time.Time            `json:"period_start"`
+	PeriodEnd      time.Time            `json:"period_end"`
+	OpeningBalance float64              `json:"opening_balance"`
+	ClosingBalance float64              `json:"closing_balance"`
+	TotalCredits   float64              `json:"total_credits"`
+	TotalDebits    float64              `json:"total_debits"`
+	Totals         map[string]TypeTotal `json:"totals"`
+	Lines          []Line               `json:"lines"`
+	GeneratedAt    time.Time            `json:"generated_at"`
+}
+
+// TypeTotal sums the lines of one transaction type. Debits are reported as a positive amount.
+type TypeTotal struct {
+	Count   int     `json:"count"`
+	Credits float64 `json:"credits"`
+	Debits  float64 `json:"debits"`
+	Net     float64 `json:"net"`
+}
+
+type Line struct {
+	TransactionID string    `json:"transaction_id"`
+	Date          time.Time `json:"date"`
+	Type          string    `json:"type"`
+	Counterparty  *string   `json:"counterparty_wallet_id"`
+	Amount        float64   `json:"amount"`
+	Balance       float64   `json:"balance"`
+}
+
+// MonthPeriod returns the calendar month in UTC containing t, as [start, end).
+func MonthPeriod(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// Build assembles a statement from the opening balance and the period's transactions in
+// ascending order.
+func Build(id, walletID string, start, end time.Time, opening float64, txs []dao.Transaction) *Statement {
+	s := &Statement{
+		ID:             id,
+		WalletID:       walletID,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		OpeningBalance: common.RoundToNDecimals(opening, 4),
+		Totals:         map[string]TypeTotal{},
+		Lines:          make([]Line, 0, len(txs)),
+	}
+
+	balance := s.OpeningBalance
+	for i := range txs {
+		tx := &txs[i]
+		amount := common.RoundToNDecimals(tx.SignedAmount(), 4)
+		balance = common.RoundToNDecimals(balance+amount, 4)
+
+		total := s.Totals[tx.Type]
+		total.Count++
+		if amount >= 0 {
+			total.Credits = common.RoundToNDecimals(total.Credits+amount, 4)
+			s.TotalCredits = common.RoundToNDecimals(s.TotalCredits+amount, 4)
+		} else {
+			total.Debits = common.RoundToNDecimals(total.Debits-amount, 4)
+			s.TotalDebits = common.RoundToNDecimals(s.TotalDebits-amount, 4)
+		}
+		total.Net = common.RoundToNDecimals(total.Credits-total.Debits, 4)
+		s.Totals[tx.Type] = total
+
+		s.Lines = append(s.Lines, Line{
+			TransactionID: tx.ID,
+			Date:          tx.CreatedAt.UTC(),
+			Type:          tx.Type,
+			Counterparty:  tx.RelatedUserID,
+			Amount:        amount,
+			Balance:       balance,
+		})
+	}
+	s.ClosingBalance = balance
+	return s
+}
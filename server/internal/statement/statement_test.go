@@ -0,0 +1,64 @@
+package statement
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/julkhong/walletapp/server/internal/dao"
+)
+
+func TestMonthPeriod(t *testing.T) {
+	start, end := MonthPeriod(time.Date(2026, 12, 17, 23, 30, 0, 0, time.FixedZone("UTC+8", 8*3600)))
+	assert.Equal(t, time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestBuild(t *testing.T) {
+	start, end := MonthPeriod(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	counterparty := "wallet-2"
+	txs := []dao.Transaction{
+		{ID: "tx-1", Type: "deposit", Amount: 100, CreatedAt: start.Add(time.Hour)},
+		{ID: "tx-2", Type: "transfer", Amount: -30.25, RelatedUserID: &counterparty, CreatedAt: start.Add(2 * time.Hour)},
+		{ID: "tx-3", Type: "transfer", Amount: 5, RelatedUserID: &counterparty, CreatedAt: start.Add(3 * time.Hour)},
+		{ID: "tx-4", Type: "withdraw", Amount: 20, CreatedAt: start.Add(4 * time.Hour)},
+	}
+
+	s := Build("stmt-1", "wallet-1", start, end, 50, txs)
+
+	assert.Equal(t, 50.0, s.OpeningBalance)
+	assert.Equal(t, 104.75, s.ClosingBalance)
+	assert.Equal(t, 105.0, s.TotalCredits)
+	assert.Equal(t, 50.25, s.TotalDebits)
+	assert.Equal(t, TypeTotal{Count: 2, Credits: 5, Debits: 30.25, Net: -25.25}, s.Totals["transfer"])
+	assert.Equal(t, TypeTotal{Count: 1, Debits: 20, Net: -20}, s.Totals["withdraw"])
+	assert.Len(t, s.Lines, 4)
+	assert.Equal(t, []float64{150, 119.75, 124.75, 104.75}, []float64{s.Lines[0].Balance, s.Lines[1].Balance, s.Lines[2].Balance, s.Lines[3].Balance})
+	assert.Equal(t, -20.0, s.Lines[3].Amount)
+}
+
+func TestBuildEmptyPeriod(t *testing.T) {
+	start, end := MonthPeriod(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	s := Build("stmt-1", "wallet-1", start, end, 12.5, nil)
+
+	assert.Equal(t, 12.5, s.ClosingBalance)
+	assert.NotNil(t, s.Lines)
+	assert.Empty(t, s.Totals)
+}
+
+func TestRenderHTML(t *testing.T) {
+	start, end := MonthPeriod(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	s := Build("stmt-1", "wallet-<1>", start, end, 50, []dao.Transaction{
+		{ID: "tx-1", Type: "deposit", Amount: 100, CreatedAt: start.Add(time.Hour)},
+	})
+
+	var buf bytes.Buffer
+	assert.NoError(t, RenderHTML(&buf, s))
+	out := buf.String()
+	assert.Contains(t, out, "Period 01 Mar 2026 – 31 Mar 2026")
+	assert.Contains(t, out, "Wallet wallet-&lt;1&gt;")
+	assert.Contains(t, out, `<td class="num"><strong>150.0000</strong></td>`)
+	assert.Contains(t, out, "<td>deposit</td><td class=\"num\">1</td>")
+}
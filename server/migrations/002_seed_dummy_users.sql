@@ -1,10 +1,10 @@
 -- Insert dummy users
-INSERT INTO users (id, name, email, created_at) VALUES
-  ('00000000-0000-0000-0000-000000000001', 'Alice', 'alice@example.com', NOW()),
-  ('00000000-0000-0000-0000-000000000002', 'Bob', 'bob@example.com', NOW()),
-  ('00000000-0000-0000-0000-000000000003', 'Charlie', 'charlie@example.com', NOW()),
-  ('00000000-0000-0000-0000-000000000004', 'Diana', 'diana@example.com', NOW()),
-  ('00000000-0000-0000-0000-000000000005', 'Eve', 'eve@example.com', NOW());
+INSERT INTO users (id, name, email, kyc_level, created_at) VALUES
+  ('00000000-0000-0000-0000-000000000001', 'Alice', 'alice@example.com', 'full', NOW()),
+  ('00000000-0000-0000-0000-000000000002', 'Bob', 'bob@example.com', 'full', NOW()),
+  ('00000000-0000-0000-0000-000000000003', 'Charlie', 'charlie@example.com', 'basic', NOW()),
+  ('00000000-0000-0000-0000-000000000004', 'Diana', 'diana@example.com', 'basic', NOW()),
+  ('00000000-0000-0000-0000-000000000005', 'Eve', 'eve@example.com', 'basic', NOW());
 
 -- Insert wallets with balances (in cents)
 INSERT INTO wallets (id, user_id, balance, created_at) VALUES
@@ -13,3 +13,11 @@ INSERT INTO wallets (id, user_id, balance, created_at) VALUES
   ('10000000-0000-0000-0000-000000000003', '00000000-0000-0000-0000-000000000003', 25000, NOW()),  -- $250.00
   ('10000000-0000-0000-0000-000000000004', '00000000-0000-0000-0000-000000000004', 10000, NOW()),  -- $100.00
   ('10000000-0000-0000-0000-000000000005', '00000000-0000-0000-0000-000000000005', 5000, NOW());   -- $50.00
+
+-- Opening deposits so the ledger agrees with the seeded balances
+INSERT INTO transactions (id, wallet_id, type, amount, related_user_id, balance_after, created_at) VALUES
+  ('20000000-0000-0000-0000-000000000001', '10000000-0000-0000-0000-000000000001', 'deposit', 100000, NULL, 100000, NOW()),
+  ('20000000-0000-0000-0000-000000000002', '10000000-0000-0000-0000-000000000002', 'deposit', 50000, NULL, 50000, NOW()),
+  ('20000000-0000-0000-0000-000000000003', '10000000-0000-0000-0000-000000000003', 'deposit', 25000, NULL, 25000, NOW()),
+  ('20000000-0000-0000-0000-000000000004', '10000000-0000-0000-0000-000000000004', 'deposit', 10000, NULL, 10000, NOW()),
+  ('20000000-0000-0000-0000-000000000005', '10000000-0000-0000-0000-000000000005', 'deposit', 5000, NULL, 5000, NOW());
@@ -0,0 +1,16 @@
+-- KYC level on users
+ALTER TABLE users ADD COLUMN IF NOT EXISTS kyc_level TEXT NOT NULL DEFAULT 'unverified';
+
+-- KYC_UPGRADES table
+CREATE TABLE IF NOT EXISTS kyc_upgrades (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id),
+    from_level TEXT NOT NULL,
+    to_level TEXT NOT NULL,
+    evidence_refs TEXT NOT NULL,
+    approved_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_kyc_upgrades_user_id ON kyc_upgrades(user_id);
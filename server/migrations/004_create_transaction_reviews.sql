@@ -0,0 +1,17 @@
+-- TRANSACTION_REVIEWS table (movements parked by risk screening)
+CREATE TABLE IF NOT EXISTS transaction_reviews (
+    id UUID PRIMARY KEY,
+    type TEXT NOT NULL,
+    wallet_id UUID NOT NULL REFERENCES wallets(id),
+    counterparty_wallet_id UUID NULL REFERENCES wallets(id),
+    amount DECIMAL(18, 4) NOT NULL,
+    rule_hits TEXT NOT NULL,
+    status TEXT NOT NULL,
+    reviewed_by TEXT NULL,
+    reviewed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_transaction_reviews_status ON transaction_reviews(status);
+CREATE INDEX IF NOT EXISTS idx_tx_wallet_created_at ON transactions(wallet_id, created_at);
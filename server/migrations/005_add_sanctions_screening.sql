@@ -0,0 +1,16 @@
+-- Wallet status (frozen wallets reject all movements)
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active';
+
+-- USER_SCREENINGS table (latest sanctions/blocklist result per user)
+CREATE TABLE IF NOT EXISTS user_screenings (
+    user_id UUID PRIMARY KEY REFERENCES users(id),
+    status TEXT NOT NULL,
+    list_name TEXT NULL,
+    matched_name TEXT NULL,
+    matched_email TEXT NULL,
+    score DECIMAL(5, 4) NOT NULL DEFAULT 0,
+    screened_at TIMESTAMP NOT NULL
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_user_screenings_status ON user_screenings(status);
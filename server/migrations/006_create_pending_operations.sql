@@ -0,0 +1,19 @@
+-- PENDING_OPERATIONS table (maker-checker approvals)
+CREATE TABLE IF NOT EXISTS pending_operations (
+    id UUID PRIMARY KEY,
+    type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL,
+    initiated_by TEXT NOT NULL,
+    decided_by TEXT NULL,
+    decided_at TIMESTAMP NULL,
+    result TEXT NULL,
+    idempotency_key TEXT NOT NULL DEFAULT '',
+    request_method TEXT NOT NULL DEFAULT '',
+    request_path TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_pending_operations_status_expires_at ON pending_operations(status, expires_at);
@@ -0,0 +1,15 @@
+-- ADJUSTMENTS table (operator balance corrections)
+CREATE TABLE IF NOT EXISTS adjustments (
+    id UUID PRIMARY KEY,
+    transaction_id UUID NOT NULL REFERENCES transactions(id),
+    wallet_id UUID NOT NULL REFERENCES wallets(id),
+    amount DECIMAL(18, 4) NOT NULL,
+    reason_code TEXT NOT NULL,
+    note TEXT NOT NULL,
+    requested_by TEXT NOT NULL,
+    approved_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_adjustments_wallet_id ON adjustments(wallet_id);
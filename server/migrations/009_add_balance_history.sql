@@ -0,0 +1,14 @@
+-- Running balance on each transaction; NULL for rows written before it was tracked
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS balance_after DECIMAL(18, 4) NULL;
+
+-- BALANCE_SNAPSHOTS table (bounds balance-as-of scans)
+CREATE TABLE IF NOT EXISTS balance_snapshots (
+    wallet_id UUID NOT NULL REFERENCES wallets(id),
+    as_of TIMESTAMP NOT NULL,
+    balance DECIMAL(18, 4) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (wallet_id, as_of)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_tx_wallet_id_created_at ON transactions(wallet_id, created_at);
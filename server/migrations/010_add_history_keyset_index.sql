@@ -0,0 +1,5 @@
+-- Keyset pagination of transaction history on (created_at, id)
+CREATE INDEX IF NOT EXISTS idx_tx_wallet_id_created_at_id ON transactions(wallet_id, created_at, id);
+
+-- Superseded by the index above
+DROP INDEX IF EXISTS idx_tx_wallet_id_created_at;
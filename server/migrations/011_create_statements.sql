@@ -0,0 +1,13 @@
+-- STATEMENTS table (generated account statements, kept for re-download)
+CREATE TABLE IF NOT EXISTS statements (
+    id UUID PRIMARY KEY,
+    wallet_id UUID NOT NULL REFERENCES wallets(id),
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    opening_balance DECIMAL(18, 4) NOT NULL,
+    closing_balance DECIMAL(18, 4) NOT NULL,
+    transaction_count INT NOT NULL,
+    document TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE (wallet_id, period_start)
+);
@@ -0,0 +1,30 @@
+-- BANK_IMPORTS table (imported camt.053 / MT940 statements and their reconciliation reports)
+CREATE TABLE IF NOT EXISTS bank_imports (
+    id UUID PRIMARY KEY,
+    format TEXT NOT NULL,
+    statement_id TEXT NOT NULL,
+    account TEXT NOT NULL,
+    file_hash TEXT NOT NULL UNIQUE,
+    line_count INT NOT NULL,
+    matched_count INT NOT NULL,
+    unmatched_count INT NOT NULL,
+    ambiguous_count INT NOT NULL,
+    report TEXT NOT NULL,
+    imported_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- BANK_MATCHES table (deposit transactions backed by a bank line; one line per deposit)
+CREATE TABLE IF NOT EXISTS bank_matches (
+    transaction_id UUID PRIMARY KEY REFERENCES transactions(id),
+    import_id UUID NOT NULL REFERENCES bank_imports(id),
+    bank_reference TEXT NOT NULL,
+    amount DECIMAL(18, 4) NOT NULL,
+    booking_date TIMESTAMP NOT NULL,
+    method TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_bank_matches_import_id ON bank_matches(import_id);
+CREATE INDEX IF NOT EXISTS idx_tx_type_created_at ON transactions(type, created_at);
@@ -0,0 +1,35 @@
+-- PAYOUT_BATCHES table (pain.001 credit transfer files written by the payout job)
+CREATE TABLE IF NOT EXISTS payout_batches (
+    id UUID PRIMARY KEY,
+    message_id TEXT NOT NULL UNIQUE,
+    file_name TEXT NOT NULL,
+    payout_count INT NOT NULL,
+    total_amount DECIMAL(18, 4) NOT NULL,
+    currency TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- PAYOUTS table (withdrawals paid out to a bank account)
+CREATE TABLE IF NOT EXISTS payouts (
+    id UUID PRIMARY KEY,
+    transaction_id UUID NOT NULL UNIQUE REFERENCES transactions(id),
+    wallet_id UUID NOT NULL REFERENCES wallets(id),
+    amount DECIMAL(18, 4) NOT NULL,
+    currency TEXT NOT NULL,
+    beneficiary_name TEXT NOT NULL,
+    beneficiary_iban TEXT NOT NULL,
+    beneficiary_bic TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    batch_id UUID NULL REFERENCES payout_batches(id),
+    return_reason TEXT NULL,
+    reversal_transaction_id UUID NULL REFERENCES transactions(id),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- Bank withdrawals parked by risk screening keep their beneficiary until approved
+ALTER TABLE transaction_reviews ADD COLUMN IF NOT EXISTS beneficiary TEXT NULL;
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_payouts_status_created_at ON payouts(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_payouts_wallet_id ON payouts(wallet_id);
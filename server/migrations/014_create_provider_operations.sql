@@ -0,0 +1,25 @@
+-- PROVIDER_OPERATIONS table (payouts and payins through external payment providers)
+CREATE TABLE IF NOT EXISTS provider_operations (
+    id UUID PRIMARY KEY,
+    provider TEXT NOT NULL,
+    kind TEXT NOT NULL,
+    wallet_id UUID NOT NULL REFERENCES wallets(id),
+    amount DECIMAL(18, 4) NOT NULL,
+    destination TEXT NOT NULL DEFAULT '',
+    provider_reference TEXT NULL,
+    status TEXT NOT NULL,
+    transaction_id UUID NULL REFERENCES transactions(id),
+    reversal_transaction_id UUID NULL REFERENCES transactions(id),
+    failure_reason TEXT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    -- a provider reports each payin once per reference; callbacks are retried
+    UNIQUE (provider, provider_reference)
+);
+
+-- Provider withdrawals parked by risk screening keep their provider until approved
+ALTER TABLE transaction_reviews ADD COLUMN IF NOT EXISTS provider TEXT NULL;
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_provider_operations_status_created_at ON provider_operations(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_provider_operations_wallet_id ON provider_operations(wallet_id);
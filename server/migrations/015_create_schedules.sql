@@ -0,0 +1,34 @@
+-- SCHEDULES table (recurring transfers between wallets)
+CREATE TABLE IF NOT EXISTS schedules (
+    id UUID PRIMARY KEY,
+    from_wallet_id UUID NOT NULL REFERENCES wallets(id),
+    to_wallet_id UUID NOT NULL REFERENCES wallets(id),
+    amount DECIMAL(18, 4) NOT NULL,
+    recurrence TEXT NOT NULL,
+    start_at TIMESTAMP NOT NULL,
+    end_at TIMESTAMP NULL,
+    next_run_at TIMESTAMP NULL,
+    last_run_at TIMESTAMP NULL,
+    failure_count INT NOT NULL DEFAULT 0,
+    status TEXT NOT NULL,
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- SCHEDULE_RUNS table (one row per executed occurrence; the key makes each occurrence run once)
+CREATE TABLE IF NOT EXISTS schedule_runs (
+    id UUID PRIMARY KEY,
+    schedule_id UUID NOT NULL REFERENCES schedules(id),
+    occurrence_at TIMESTAMP NOT NULL,
+    idempotency_key TEXT NOT NULL UNIQUE,
+    status TEXT NOT NULL,
+    error TEXT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_schedules_status_next_run_at ON schedules(status, next_run_at);
+CREATE INDEX IF NOT EXISTS idx_schedules_from_wallet_id ON schedules(from_wallet_id);
+CREATE INDEX IF NOT EXISTS idx_schedule_runs_schedule_id ON schedule_runs(schedule_id, occurrence_at);
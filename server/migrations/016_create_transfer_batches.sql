@@ -0,0 +1,36 @@
+-- TRANSFER_BATCHES table (many transfers out of one wallet, e.g. payroll)
+CREATE TABLE IF NOT EXISTS transfer_batches (
+    id UUID PRIMARY KEY,
+    from_wallet_id UUID NOT NULL REFERENCES wallets(id),
+    mode TEXT NOT NULL,
+    status TEXT NOT NULL,
+    idempotency_key TEXT NOT NULL UNIQUE,
+    item_count INT NOT NULL,
+    total_amount DECIMAL(18, 4) NOT NULL,
+    succeeded_count INT NOT NULL DEFAULT 0,
+    failed_count INT NOT NULL DEFAULT 0,
+    error TEXT NULL,
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    completed_at TIMESTAMP NULL
+);
+
+-- TRANSFER_BATCH_ITEMS table (one recipient of a batch and the outcome of paying it)
+CREATE TABLE IF NOT EXISTS transfer_batch_items (
+    id UUID PRIMARY KEY,
+    batch_id UUID NOT NULL REFERENCES transfer_batches(id),
+    seq INT NOT NULL,
+    to_wallet_id UUID NOT NULL,
+    amount DECIMAL(18, 4) NOT NULL,
+    reference TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    error TEXT NULL,
+    transaction_id UUID NULL REFERENCES transactions(id),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE (batch_id, seq)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_transfer_batches_status_created_at ON transfer_batches(status, created_at);
@@ -0,0 +1,13 @@
+-- Fee rows point at the transfer or withdrawal they were charged for
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS parent_transaction_id UUID NULL REFERENCES transactions(id);
+
+CREATE INDEX IF NOT EXISTS idx_tx_parent_transaction_id ON transactions(parent_transaction_id);
+
+-- System fee wallet collecting every fee (FEE_WALLET_ID)
+INSERT INTO users (id, name, email, kyc_level, created_at) VALUES
+  ('00000000-0000-0000-0000-0000000000fe', 'Fees', 'fees@system.local', 'full', NOW())
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO wallets (id, user_id, balance, created_at) VALUES
+  ('10000000-0000-0000-0000-0000000000fe', '00000000-0000-0000-0000-0000000000fe', 0, NOW())
+ON CONFLICT (id) DO NOTHING;
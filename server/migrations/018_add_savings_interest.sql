@@ -0,0 +1,20 @@
+-- Wallet types; savings wallets earn interest at their own annual rate or SAVINGS_INTEREST_RATE
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS type TEXT NOT NULL DEFAULT 'standard';
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS interest_rate DECIMAL(9, 6) NULL;
+
+CREATE INDEX IF NOT EXISTS idx_wallets_type ON wallets(type);
+
+-- INTEREST_ACCRUALS table (one row per savings wallet and day, posted monthly)
+CREATE TABLE IF NOT EXISTS interest_accruals (
+    wallet_id UUID NOT NULL REFERENCES wallets(id),
+    accrual_date DATE NOT NULL,
+    balance DECIMAL(18, 4) NOT NULL,
+    annual_rate DECIMAL(9, 6) NOT NULL,
+    amount DECIMAL(20, 10) NOT NULL,
+    transaction_id UUID NULL REFERENCES transactions(id),
+    posted_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (wallet_id, accrual_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_interest_accruals_unposted ON interest_accruals(wallet_id, accrual_date) WHERE posted_at IS NULL;
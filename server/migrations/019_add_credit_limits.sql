@@ -0,0 +1,5 @@
+-- Credit lines: a wallet's balance may go negative down to -credit_limit
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS credit_limit DECIMAL(18, 4) NOT NULL DEFAULT 0;
+ALTER TABLE wallets ADD CONSTRAINT wallets_credit_limit_check CHECK (credit_limit >= 0);
+
+CREATE INDEX IF NOT EXISTS idx_wallets_credit_limit ON wallets(id) WHERE credit_limit > 0;
@@ -0,0 +1,7 @@
+-- Pockets: child wallets that ring-fence part of a user's money, with an optional savings goal
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS parent_wallet_id UUID NULL REFERENCES wallets(id);
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS name TEXT NULL;
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS goal_amount DECIMAL(18, 4) NULL;
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS goal_date DATE NULL;
+
+CREATE INDEX IF NOT EXISTS idx_wallets_parent ON wallets(parent_wallet_id) WHERE parent_wallet_id IS NOT NULL;
@@ -0,0 +1,25 @@
+-- WALLET_MEMBERS table (users sharing a wallet: owners, spenders with an optional monthly cap, viewers)
+CREATE TABLE IF NOT EXISTS wallet_members (
+    wallet_id UUID NOT NULL REFERENCES wallets(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    role TEXT NOT NULL,
+    spending_cap DECIMAL(18, 4) NULL,
+    status TEXT NOT NULL,
+    invited_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP NULL,
+    PRIMARY KEY (wallet_id, user_id),
+    CHECK (spending_cap IS NULL OR spending_cap >= 0)
+);
+
+-- Every existing wallet is owned by its user; pockets share the members of their parent
+INSERT INTO wallet_members (wallet_id, user_id, role, status, created_at, accepted_at)
+SELECT id, user_id, 'owner', 'active', created_at, created_at FROM wallets WHERE parent_wallet_id IS NULL
+ON CONFLICT DO NOTHING;
+
+-- Who moved the money, for per-member spending
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS initiated_by TEXT NULL;
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_wallet_members_user_id ON wallet_members(user_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_initiated_by ON transactions(wallet_id, initiated_by, created_at) WHERE initiated_by IS NOT NULL;
@@ -0,0 +1,34 @@
+-- ESCROWS table (a buyer's payment held until the seller's delivery is confirmed)
+CREATE TABLE IF NOT EXISTS escrows (
+    id UUID PRIMARY KEY,
+    buyer_wallet_id UUID NOT NULL REFERENCES wallets(id),
+    seller_wallet_id UUID NOT NULL REFERENCES wallets(id),
+    amount DECIMAL(18, 4) NOT NULL,
+    reference TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    hold_transaction_id UUID NOT NULL REFERENCES transactions(id),
+    settlement_transaction_id UUID NULL REFERENCES transactions(id),
+    dispute_reason TEXT NULL,
+    disputed_by TEXT NULL,
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    settled_at TIMESTAMP NULL,
+    CHECK (amount > 0),
+    CHECK (buyer_wallet_id <> seller_wallet_id)
+);
+
+-- System escrow wallet holding the money of every open escrow (ESCROW_WALLET_ID)
+INSERT INTO users (id, name, email, kyc_level, created_at) VALUES
+  ('00000000-0000-0000-0000-0000000000e5', 'Escrow', 'escrow@system.local', 'full', NOW())
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO wallets (id, user_id, balance, created_at) VALUES
+  ('10000000-0000-0000-0000-0000000000e5', '00000000-0000-0000-0000-0000000000e5', 0, NOW())
+ON CONFLICT (id) DO NOTHING;
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_escrows_buyer_wallet_id ON escrows(buyer_wallet_id);
+CREATE INDEX IF NOT EXISTS idx_escrows_seller_wallet_id ON escrows(seller_wallet_id);
+CREATE INDEX IF NOT EXISTS idx_escrows_status_expires_at ON escrows(status, expires_at);
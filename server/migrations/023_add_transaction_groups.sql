@@ -0,0 +1,4 @@
+-- Rows written for one payment with many legs, such as a split payment, share a group ID
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS group_id UUID NULL;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_group_id ON transactions(group_id) WHERE group_id IS NOT NULL;
@@ -0,0 +1,23 @@
+-- PAYMENT_REQUESTS table (a wallet asking another wallet to pay it)
+CREATE TABLE IF NOT EXISTS payment_requests (
+    id UUID PRIMARY KEY,
+    requester_wallet_id UUID NOT NULL REFERENCES wallets(id),
+    payer_wallet_id UUID NOT NULL REFERENCES wallets(id),
+    amount DECIMAL(18, 4) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    transaction_id UUID NULL REFERENCES transactions(id),
+    created_by TEXT NOT NULL DEFAULT '',
+    responded_by TEXT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    responded_at TIMESTAMP NULL,
+    CHECK (amount > 0),
+    CHECK (requester_wallet_id <> payer_wallet_id)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_payment_requests_payer_wallet_id ON payment_requests(payer_wallet_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_payment_requests_requester_wallet_id ON payment_requests(requester_wallet_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_payment_requests_status_expires_at ON payment_requests(status, expires_at);
@@ -0,0 +1,20 @@
+-- PAYMENT_LINKS table (signed links and QR codes anyone can pay a wallet through)
+CREATE TABLE IF NOT EXISTS payment_links (
+    id UUID PRIMARY KEY,
+    wallet_id UUID NOT NULL REFERENCES wallets(id),
+    amount DECIMAL(18, 4) NULL,
+    reference TEXT NOT NULL DEFAULT '',
+    single_use BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMP NOT NULL,
+    use_count INT NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMP NULL,
+    last_transaction_id UUID NULL REFERENCES transactions(id),
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    CHECK (amount IS NULL OR amount > 0),
+    CHECK (use_count >= 0)
+);
+
+-- Indexes
+CREATE INDEX IF NOT EXISTS idx_payment_links_wallet_id ON payment_links(wallet_id, created_at);
@@ -0,0 +1,2 @@
+-- Rules a transfer awaiting approval hit when it was screened, shown to the checker
+ALTER TABLE pending_operations ADD COLUMN IF NOT EXISTS risk_hits TEXT;
@@ -0,0 +1,6 @@
+-- Links a best_effort batch item parked for risk review to the review that decides it
+ALTER TABLE transfer_batch_items ADD COLUMN IF NOT EXISTS review_id UUID NULL REFERENCES transaction_reviews(id);
+
+CREATE INDEX IF NOT EXISTS idx_transfer_batch_items_review_id ON transfer_batch_items(review_id) WHERE review_id IS NOT NULL;
+-- Finds batches left processing by an interrupted run
+CREATE INDEX IF NOT EXISTS idx_transfer_batches_status_updated_at ON transfer_batches(status, updated_at);
@@ -0,0 +1,4 @@
+-- Idempotency key of the request a review parked, so the decision can replace its pending response
+ALTER TABLE transaction_reviews ADD COLUMN IF NOT EXISTS idempotency_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE transaction_reviews ADD COLUMN IF NOT EXISTS request_method TEXT NOT NULL DEFAULT '';
+ALTER TABLE transaction_reviews ADD COLUMN IF NOT EXISTS request_path TEXT NOT NULL DEFAULT '';